@@ -201,6 +201,18 @@ func (d *DeliveryAdapter) Nack(ctx context.Context, opts core.JobNackOptions) er
 	return d.NackForAttempt(ctx, opts, 0)
 }
 
+// Attempts reports the delivery attempt when the go-job delivery tracks it,
+// and zero otherwise.
+func (d *DeliveryAdapter) Attempts() int {
+	if d == nil || d.delivery == nil {
+		return 0
+	}
+	if counter, ok := d.delivery.(interface{ Attempts() int }); ok {
+		return counter.Attempts()
+	}
+	return 0
+}
+
 func (d *DeliveryAdapter) NackForAttempt(ctx context.Context, opts core.JobNackOptions, attempt int) error {
 	if d == nil || d.delivery == nil {
 		return fmt.Errorf("gojob: delivery is not configured")
@@ -323,6 +335,7 @@ var (
 	_ core.JobDispatchStatusReader = (*EnqueuerAdapter)(nil)
 	_ core.JobDelivery             = (*DeliveryAdapter)(nil)
 	_ core.JobDequeuer             = (*DequeuerAdapter)(nil)
+	_ interface{ Attempts() int }  = (*DeliveryAdapter)(nil)
 	_ worker.Hook                  = (*WorkerHookAdapter)(nil)
 	_ core.JobWorkerHook           = (*capturingCoreHook)(nil)
 )
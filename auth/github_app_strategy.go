@@ -0,0 +1,732 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	defaultGitHubAppAPIBaseURL          = "https://api.github.com"
+	defaultGitHubAppInstallBaseURL      = "https://github.com/apps"
+	defaultGitHubOAuthBaseURL           = "https://github.com"
+	defaultGitHubAppJWTTTL              = 9 * time.Minute
+	maxGitHubAppJWTTTL                  = 10 * time.Minute
+	defaultGitHubAppRenewBefore         = 5 * time.Minute
+	defaultGitHubAppTokenRequestTimeout = 30 * time.Second
+	gitHubAppJWTClockSkew               = 60 * time.Second
+	gitHubAPIVersion                    = "2022-11-28"
+	maxGitHubAppTokenResponseBodyBytes  = 1 << 20 // 1 MiB
+	gitHubUserInstallationsPageSize     = 100
+	maxGitHubUserInstallationPages      = 50
+
+	// gitHubInstallationIDMetadataKey matches the key the installations
+	// package records provider installation identifiers under.
+	gitHubInstallationIDMetadataKey = "external_installation_id"
+)
+
+type GitHubAppStrategyConfig struct {
+	AppID string
+	// AppSlug builds the installation URL returned by Begin.
+	AppSlug    string
+	PrivateKey string
+	APIBaseURL string
+	// Permissions and Repositories narrow every installation token unless a
+	// request supplies its own. Empty values request everything the
+	// installation was granted.
+	Permissions         map[string]string
+	Repositories        []string
+	RepositoryIDs       []int64
+	JWTTTL              time.Duration
+	RenewBefore         time.Duration
+	TokenRequestTimeout time.Duration
+	Now                 func() time.Time
+	HTTPClient          OAuth2HTTPDoer
+	// ClientID and ClientSecret are the app's OAuth credentials. With them,
+	// Complete exchanges the user authorization code GitHub sends to the
+	// setup URL and only accepts installations that user can access.
+	ClientID     string
+	ClientSecret string
+	// OAuthBaseURL defaults to https://github.com.
+	OAuthBaseURL string
+	// InstallationVerifier checks installation ownership when Complete has
+	// no user authorization code, typically against the installations
+	// recorded from installation webhooks.
+	InstallationVerifier GitHubInstallationVerifier
+}
+
+// GitHubInstallationVerifier reports whether a GitHub installation belongs to
+// the scope completing authorization.
+type GitHubInstallationVerifier interface {
+	VerifyGitHubInstallation(ctx context.Context, scope core.ScopeRef, installationID string) (bool, error)
+}
+
+type GitHubInstallationVerifierFunc func(ctx context.Context, scope core.ScopeRef, installationID string) (bool, error)
+
+func (f GitHubInstallationVerifierFunc) VerifyGitHubInstallation(
+	ctx context.Context,
+	scope core.ScopeRef,
+	installationID string,
+) (bool, error) {
+	return f(ctx, scope, installationID)
+}
+
+// GitHubInstallationStoreVerifier accepts an installation when an active
+// installation recorded for the scope under ProviderID carries its GitHub
+// installation id.
+type GitHubInstallationStoreVerifier struct {
+	Store      core.InstallationStore
+	ProviderID string
+}
+
+func (v GitHubInstallationStoreVerifier) VerifyGitHubInstallation(
+	ctx context.Context,
+	scope core.ScopeRef,
+	installationID string,
+) (bool, error) {
+	if v.Store == nil || strings.TrimSpace(v.ProviderID) == "" {
+		return false, fmt.Errorf("auth: github installation store verifier is not configured")
+	}
+	installations, err := v.Store.ListByScope(ctx, strings.TrimSpace(v.ProviderID), scope)
+	if err != nil {
+		return false, err
+	}
+	for _, installation := range installations {
+		if installation.Status == core.InstallationStatusActive && GitHubInstallationID(installation) == installationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GitHubInstallationTokenRequest scopes an installation access token. Zero
+// values fall back to the strategy configuration.
+type GitHubInstallationTokenRequest struct {
+	InstallationID string
+	Permissions    map[string]string
+	Repositories   []string
+	RepositoryIDs  []int64
+}
+
+// GitHubAppStrategy authenticates as a GitHub App. It signs short-lived RS256
+// app JWTs and exchanges them for installation access tokens, which are
+// cached per installation and scoping until shortly before they expire.
+type GitHubAppStrategy struct {
+	config     GitHubAppStrategyConfig
+	httpClient OAuth2HTTPDoer
+	mu         sync.Mutex
+	cache      map[string]cachedClientCredential
+}
+
+type gitHubInstallationTokenPayload struct {
+	Token               string            `json:"token"`
+	ExpiresAt           time.Time         `json:"expires_at"`
+	Permissions         map[string]string `json:"permissions"`
+	RepositorySelection string            `json:"repository_selection"`
+	Repositories        []struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repositories"`
+	Message string `json:"message"`
+}
+
+func NewGitHubAppStrategy(cfg GitHubAppStrategyConfig) *GitHubAppStrategy {
+	jwtTTL := cfg.JWTTTL
+	if jwtTTL <= 0 || jwtTTL > maxGitHubAppJWTTTL {
+		jwtTTL = defaultGitHubAppJWTTTL
+	}
+	renewBefore := cfg.RenewBefore
+	if renewBefore <= 0 {
+		renewBefore = defaultGitHubAppRenewBefore
+	}
+	requestTimeout := cfg.TokenRequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = defaultGitHubAppTokenRequestTimeout
+	}
+	now := cfg.Now
+	if now == nil {
+		now = func() time.Time { return time.Now().UTC() }
+	}
+	apiBaseURL := strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/")
+	if apiBaseURL == "" {
+		apiBaseURL = defaultGitHubAppAPIBaseURL
+	}
+	oauthBaseURL := strings.TrimRight(strings.TrimSpace(cfg.OAuthBaseURL), "/")
+	if oauthBaseURL == "" {
+		oauthBaseURL = defaultGitHubOAuthBaseURL
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &GitHubAppStrategy{
+		config: GitHubAppStrategyConfig{
+			AppID:                strings.TrimSpace(cfg.AppID),
+			AppSlug:              strings.TrimSpace(cfg.AppSlug),
+			PrivateKey:           strings.TrimSpace(cfg.PrivateKey),
+			APIBaseURL:           apiBaseURL,
+			Permissions:          normalizeGitHubPermissions(cfg.Permissions),
+			Repositories:         normalizeValues(cfg.Repositories),
+			RepositoryIDs:        normalizeGitHubRepositoryIDs(cfg.RepositoryIDs),
+			JWTTTL:               jwtTTL,
+			RenewBefore:          renewBefore,
+			TokenRequestTimeout:  requestTimeout,
+			Now:                  now,
+			ClientID:             strings.TrimSpace(cfg.ClientID),
+			ClientSecret:         strings.TrimSpace(cfg.ClientSecret),
+			OAuthBaseURL:         oauthBaseURL,
+			InstallationVerifier: cfg.InstallationVerifier,
+		},
+		httpClient: httpClient,
+		cache:      map[string]cachedClientCredential{},
+	}
+}
+
+func (*GitHubAppStrategy) Type() core.AuthKind {
+	return core.AuthKindGitHubApp
+}
+
+// Begin returns the app installation URL when an app slug is configured.
+// GitHub redirects to the app setup URL with installation_id, and with a user
+// authorization code when the app requests user authorization during
+// installation; callers pass both to Complete.
+func (s *GitHubAppStrategy) Begin(_ context.Context, req core.AuthBeginRequest) (core.AuthBeginResponse, error) {
+	state := strings.TrimSpace(req.State)
+	installURL := ""
+	if s.config.AppSlug != "" {
+		installURL = defaultGitHubAppInstallBaseURL + "/" + url.PathEscape(s.config.AppSlug) + "/installations/new"
+		if state != "" {
+			installURL += "?" + url.Values{"state": []string{state}}.Encode()
+		}
+	}
+	return core.AuthBeginResponse{
+		URL:             installURL,
+		State:           state,
+		RequestedGrants: normalizeValues(req.RequestedRaw),
+		Metadata: map[string]any{
+			"auth_kind": core.AuthKindGitHubApp,
+			"app_id":    s.config.AppID,
+		},
+	}, nil
+}
+
+// Complete connects the scope to the installation named by installation_id.
+// The id arrives through the setup redirect, so it is only accepted once the
+// installation is shown to belong to the caller: through the installations
+// the authorizing user can access when a code and client credentials are
+// available, otherwise through the configured InstallationVerifier. The
+// external account id is always the verified installation id.
+func (s *GitHubAppStrategy) Complete(ctx context.Context, req core.AuthCompleteRequest) (core.AuthCompleteResponse, error) {
+	metadata := cloneMetadata(req.Metadata)
+	installationID := readString(metadata, "installation_id", gitHubInstallationIDMetadataKey)
+	if installationID == "" {
+		return core.AuthCompleteResponse{}, fmt.Errorf("auth: github app installation_id is required")
+	}
+	if err := s.verifyInstallation(ctx, req, installationID); err != nil {
+		return core.AuthCompleteResponse{}, err
+	}
+	credential, err := s.InstallationToken(ctx, gitHubTokenRequestFromMetadata(installationID, metadata))
+	if err != nil {
+		return core.AuthCompleteResponse{}, err
+	}
+	return core.AuthCompleteResponse{
+		ExternalAccountID: installationID,
+		Credential:        credential,
+		RequestedGrants:   append([]string(nil), credential.RequestedScopes...),
+		GrantedGrants:     append([]string(nil), credential.GrantedScopes...),
+		Metadata: map[string]any{
+			"auth_kind":                     core.AuthKindGitHubApp,
+			"app_id":                        s.config.AppID,
+			"installation_id":               installationID,
+			gitHubInstallationIDMetadataKey: installationID,
+			"setup_action":                  readString(metadata, "setup_action"),
+		},
+	}, nil
+}
+
+func (s *GitHubAppStrategy) verifyInstallation(ctx context.Context, req core.AuthCompleteRequest, installationID string) error {
+	code := firstNonEmpty(strings.TrimSpace(req.Code), readString(req.Metadata, "code"))
+	var (
+		owned bool
+		err   error
+	)
+	switch {
+	case code != "" && s.config.ClientID != "" && s.config.ClientSecret != "":
+		owned, err = s.userCanAccessInstallation(ctx, code, req.RedirectURI, installationID)
+	case s.config.InstallationVerifier != nil:
+		owned, err = s.config.InstallationVerifier.VerifyGitHubInstallation(ctx, req.Scope, installationID)
+	default:
+		return fmt.Errorf("auth: github app cannot verify installation %q: a user authorization code with client credentials or an installation verifier is required", installationID)
+	}
+	if err != nil {
+		return fmt.Errorf("auth: github app verify installation %q: %w", installationID, err)
+	}
+	if !owned {
+		return fmt.Errorf("auth: github app installation %q does not belong to the authorizing scope", installationID)
+	}
+	return nil
+}
+
+type gitHubUserTokenPayload struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type gitHubUserInstallationsPayload struct {
+	TotalCount    int `json:"total_count"`
+	Installations []struct {
+		ID int64 `json:"id"`
+	} `json:"installations"`
+	Message string `json:"message"`
+}
+
+// userCanAccessInstallation exchanges the user authorization code for a
+// user-to-server token and looks for installationID among the installations
+// that user can access.
+func (s *GitHubAppStrategy) userCanAccessInstallation(
+	ctx context.Context,
+	code string,
+	redirectURI string,
+	installationID string,
+) (bool, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	form := url.Values{
+		"client_id":     []string{s.config.ClientID},
+		"client_secret": []string{s.config.ClientSecret},
+		"code":          []string{code},
+	}
+	if redirectURI = strings.TrimSpace(redirectURI); redirectURI != "" {
+		form.Set("redirect_uri", redirectURI)
+	}
+	tokenReq, err := http.NewRequest(http.MethodPost, s.config.OAuthBaseURL+"/login/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	tokenReq.Header.Set("Accept", "application/json")
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var token gitHubUserTokenPayload
+	if err := s.doGitHubRequest(ctx, tokenReq, &token, func() string { return token.ErrorDescription }); err != nil {
+		return false, err
+	}
+	if token.Error != "" || strings.TrimSpace(token.AccessToken) == "" {
+		return false, fmt.Errorf("auth: github user token exchange failed: %s", firstNonEmpty(token.ErrorDescription, token.Error, "missing access token"))
+	}
+
+	for page := 1; page <= maxGitHubUserInstallationPages; page++ {
+		endpoint := fmt.Sprintf("%s/user/installations?per_page=%d&page=%d", s.config.APIBaseURL, gitHubUserInstallationsPageSize, page)
+		listReq, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		if err != nil {
+			return false, err
+		}
+		listReq.Header.Set("Accept", "application/vnd.github+json")
+		listReq.Header.Set("Authorization", "Bearer "+strings.TrimSpace(token.AccessToken))
+		listReq.Header.Set("X-GitHub-Api-Version", gitHubAPIVersion)
+		var listed gitHubUserInstallationsPayload
+		if err := s.doGitHubRequest(ctx, listReq, &listed, func() string { return listed.Message }); err != nil {
+			return false, err
+		}
+		for _, installation := range listed.Installations {
+			if strconv.FormatInt(installation.ID, 10) == installationID {
+				return true, nil
+			}
+		}
+		if len(listed.Installations) < gitHubUserInstallationsPageSize {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// doGitHubRequest sends req with the token request timeout and decodes a
+// bounded JSON response into out. message reports the error text of a
+// decoded non-2xx response.
+func (s *GitHubAppStrategy) doGitHubRequest(ctx context.Context, req *http.Request, out any, message func() string) error {
+	if s == nil || s.httpClient == nil {
+		return fmt.Errorf("auth: github app http client is not configured")
+	}
+	requestCtx, cancel := context.WithTimeout(ctx, s.config.TokenRequestTimeout)
+	defer cancel()
+	response, err := s.httpClient.Do(req.WithContext(requestCtx))
+	if err != nil {
+		return fmt.Errorf("auth: github request %s failed: %w", req.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxGitHubAppTokenResponseBodyBytes+1))
+	if err != nil {
+		return fmt.Errorf("auth: github read %s response: %w", req.URL.Path, err)
+	}
+	if int64(len(body)) > maxGitHubAppTokenResponseBodyBytes {
+		return fmt.Errorf("auth: github %s response exceeds %d bytes", req.URL.Path, maxGitHubAppTokenResponseBodyBytes)
+	}
+	decodeErr := json.Unmarshal(body, out)
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"auth: github %s error (%d): %s",
+			req.URL.Path,
+			response.StatusCode,
+			firstNonEmpty(message(), "unknown error"),
+		)
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("auth: github decode %s response: %w", req.URL.Path, decodeErr)
+	}
+	return nil
+}
+
+func (s *GitHubAppStrategy) Refresh(ctx context.Context, cred core.ActiveCredential) (core.RefreshResult, error) {
+	metadata := cloneMetadata(cred.Metadata)
+	installationID := readString(metadata, "installation_id")
+	if installationID == "" {
+		return core.RefreshResult{}, fmt.Errorf("auth: github app refresh requires installation_id metadata")
+	}
+	refreshed, err := s.InstallationToken(ctx, gitHubTokenRequestFromMetadata(installationID, metadata))
+	if err != nil {
+		return core.RefreshResult{}, err
+	}
+	refreshed.ConnectionID = cred.ConnectionID
+	return core.RefreshResult{
+		Credential:    refreshed,
+		GrantedGrants: append([]string(nil), refreshed.GrantedScopes...),
+		Metadata: map[string]any{
+			"auth_kind":       core.AuthKindGitHubApp,
+			"installation_id": installationID,
+		},
+	}, nil
+}
+
+// AppJWT signs an app JWT for calls made as the app itself, such as listing
+// installations. GitHub rejects app JWTs that live longer than ten minutes.
+func (s *GitHubAppStrategy) AppJWT() (string, error) {
+	if s.config.AppID == "" {
+		return "", fmt.Errorf("auth: github app id is required")
+	}
+	if s.config.PrivateKey == "" {
+		return "", fmt.Errorf("auth: github app private key is required")
+	}
+	now := s.config.Now().UTC()
+	claims := map[string]any{
+		"iss": s.config.AppID,
+		// Backdated to absorb clock drift between us and GitHub.
+		"iat": now.Add(-gitHubAppJWTClockSkew).Unix(),
+		"exp": now.Add(s.config.JWTTTL).Unix(),
+	}
+	return buildJWT("", jwtAlgRS256, s.config.PrivateKey, claims)
+}
+
+// InstallationToken returns an installation access token for req, minting a
+// new one when no cached token outlives the renewal window.
+func (s *GitHubAppStrategy) InstallationToken(ctx context.Context, req GitHubInstallationTokenRequest) (core.ActiveCredential, error) {
+	req = s.normalizeTokenRequest(req)
+	if req.InstallationID == "" {
+		return core.ActiveCredential{}, fmt.Errorf("auth: github app installation_id is required")
+	}
+	cacheKey := buildGitHubInstallationCacheKey(req)
+	if cached, ok := s.lookupCachedCredential(cacheKey); ok {
+		return cached, nil
+	}
+	payload, err := s.fetchInstallationToken(ctx, req)
+	if err != nil {
+		return core.ActiveCredential{}, err
+	}
+
+	expiresAt := payload.ExpiresAt.UTC()
+	if payload.ExpiresAt.IsZero() {
+		expiresAt = s.config.Now().UTC().Add(time.Hour)
+	}
+	granted := normalizeGitHubPermissions(payload.Permissions)
+	if len(granted) == 0 {
+		granted = req.Permissions
+	}
+	repositories := append([]string(nil), req.Repositories...)
+	for _, repository := range payload.Repositories {
+		repositories = append(repositories, firstNonEmpty(repository.Name, repository.FullName))
+	}
+	credentialMetadata := map[string]any{
+		"auth_kind":            core.AuthKindGitHubApp,
+		"app_id":               s.config.AppID,
+		"installation_id":      req.InstallationID,
+		"cache_key":            cacheKey,
+		"repository_selection": strings.TrimSpace(payload.RepositorySelection),
+	}
+	if len(req.Permissions) > 0 {
+		credentialMetadata["permissions"] = gitHubPermissionGrants(req.Permissions)
+	}
+	if len(req.Repositories) > 0 {
+		credentialMetadata["repositories"] = append([]string(nil), req.Repositories...)
+	}
+	if len(req.RepositoryIDs) > 0 {
+		ids := make([]string, 0, len(req.RepositoryIDs))
+		for _, id := range req.RepositoryIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		credentialMetadata["repository_ids"] = ids
+	}
+	if repositories = normalizeValues(repositories); len(repositories) > 0 {
+		credentialMetadata["granted_repositories"] = repositories
+	}
+
+	credential := core.ActiveCredential{
+		TokenType:       "bearer",
+		AccessToken:     strings.TrimSpace(payload.Token),
+		RequestedScopes: gitHubPermissionGrants(req.Permissions),
+		GrantedScopes:   gitHubPermissionGrants(granted),
+		ExpiresAt:       &expiresAt,
+		Refreshable:     true,
+		Metadata:        credentialMetadata,
+	}
+	s.storeCachedCredential(cacheKey, credential)
+	return credential, nil
+}
+
+// InstallationCredential mints a token for an active GitHub installation
+// record, using the provider installation id stored in its metadata.
+func (s *GitHubAppStrategy) InstallationCredential(ctx context.Context, installation core.Installation) (core.ActiveCredential, error) {
+	if installation.Status != core.InstallationStatusActive {
+		return core.ActiveCredential{}, fmt.Errorf("auth: github app installation %q is %s", installation.ID, installation.Status)
+	}
+	installationID := GitHubInstallationID(installation)
+	if installationID == "" {
+		return core.ActiveCredential{}, fmt.Errorf("auth: github app installation %q has no provider installation id", installation.ID)
+	}
+	credential, err := s.InstallationToken(ctx, GitHubInstallationTokenRequest{InstallationID: installationID})
+	if err != nil {
+		return core.ActiveCredential{}, err
+	}
+	credential.Metadata = cloneMetadata(credential.Metadata)
+	credential.Metadata["service_installation_id"] = installation.ID
+	return credential, nil
+}
+
+// GitHubInstallationID returns the GitHub installation id recorded on an
+// installation, or "" when it has none.
+func GitHubInstallationID(installation core.Installation) string {
+	return readString(installation.Metadata, gitHubInstallationIDMetadataKey, "installation_id")
+}
+
+func (s *GitHubAppStrategy) fetchInstallationToken(
+	ctx context.Context,
+	req GitHubInstallationTokenRequest,
+) (gitHubInstallationTokenPayload, error) {
+	if s == nil || s.httpClient == nil {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app http client is not configured")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	appJWT, err := s.AppJWT()
+	if err != nil {
+		return gitHubInstallationTokenPayload{}, err
+	}
+
+	body := map[string]any{}
+	if len(req.Permissions) > 0 {
+		body["permissions"] = req.Permissions
+	}
+	if len(req.Repositories) > 0 {
+		body["repositories"] = req.Repositories
+	}
+	if len(req.RepositoryIDs) > 0 {
+		body["repository_ids"] = req.RepositoryIDs
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app encode token request: %w", err)
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, s.config.TokenRequestTimeout)
+	defer cancel()
+	endpoint := s.config.APIBaseURL + "/app/installations/" + url.PathEscape(req.InstallationID) + "/access_tokens"
+	httpReq, err := http.NewRequestWithContext(requestCtx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return gitHubInstallationTokenPayload{}, err
+	}
+	httpReq.Header.Set("Accept", "application/vnd.github+json")
+	httpReq.Header.Set("Authorization", "Bearer "+appJWT)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-GitHub-Api-Version", gitHubAPIVersion)
+
+	response, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app token request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, maxGitHubAppTokenResponseBodyBytes+1))
+	if err != nil {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app read token response: %w", err)
+	}
+	if int64(len(responseBody)) > maxGitHubAppTokenResponseBodyBytes {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf(
+			"auth: github app token response exceeds %d bytes",
+			maxGitHubAppTokenResponseBodyBytes,
+		)
+	}
+	payload := gitHubInstallationTokenPayload{}
+	decodeErr := json.Unmarshal(responseBody, &payload)
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf(
+			"auth: github app token endpoint error (%d): %s",
+			response.StatusCode,
+			firstNonEmpty(payload.Message, "unknown error"),
+		)
+	}
+	if decodeErr != nil {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app decode token response: %w", decodeErr)
+	}
+	if strings.TrimSpace(payload.Token) == "" {
+		return gitHubInstallationTokenPayload{}, fmt.Errorf("auth: github app token response missing token")
+	}
+	return payload, nil
+}
+
+func (s *GitHubAppStrategy) normalizeTokenRequest(req GitHubInstallationTokenRequest) GitHubInstallationTokenRequest {
+	out := GitHubInstallationTokenRequest{
+		InstallationID: strings.TrimSpace(req.InstallationID),
+		Permissions:    normalizeGitHubPermissions(req.Permissions),
+		Repositories:   normalizeValues(req.Repositories),
+		RepositoryIDs:  normalizeGitHubRepositoryIDs(req.RepositoryIDs),
+	}
+	if len(out.Permissions) == 0 {
+		out.Permissions = normalizeGitHubPermissions(s.config.Permissions)
+	}
+	if len(out.Repositories) == 0 && len(out.RepositoryIDs) == 0 {
+		out.Repositories = append([]string(nil), s.config.Repositories...)
+		out.RepositoryIDs = append([]int64(nil), s.config.RepositoryIDs...)
+	}
+	return out
+}
+
+func (s *GitHubAppStrategy) lookupCachedCredential(cacheKey string) (core.ActiveCredential, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cache[cacheKey]
+	if !ok {
+		return core.ActiveCredential{}, false
+	}
+	now := s.config.Now().UTC()
+	if cached.expiresAt.IsZero() || !cached.expiresAt.After(now.Add(s.config.RenewBefore)) {
+		delete(s.cache, cacheKey)
+		return core.ActiveCredential{}, false
+	}
+	return cached.credential, true
+}
+
+func (s *GitHubAppStrategy) storeCachedCredential(cacheKey string, cred core.ActiveCredential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt := time.Time{}
+	if cred.ExpiresAt != nil {
+		expiresAt = cred.ExpiresAt.UTC()
+	}
+	s.cache[cacheKey] = cachedClientCredential{
+		credential: cred,
+		expiresAt:  expiresAt,
+	}
+}
+
+func gitHubTokenRequestFromMetadata(installationID string, metadata map[string]any) GitHubInstallationTokenRequest {
+	req := GitHubInstallationTokenRequest{
+		InstallationID: installationID,
+		Permissions:    readGitHubPermissions(metadata, "permissions"),
+		Repositories:   readStringSlice(metadata, "repositories"),
+	}
+	for _, raw := range readStringSlice(metadata, "repository_ids") {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			req.RepositoryIDs = append(req.RepositoryIDs, id)
+		}
+	}
+	return req
+}
+
+// readGitHubPermissions accepts a permission map or "name:level" grants.
+func readGitHubPermissions(metadata map[string]any, key string) map[string]string {
+	switch typed := metadata[key].(type) {
+	case map[string]string:
+		return normalizeGitHubPermissions(typed)
+	case map[string]any:
+		permissions := make(map[string]string, len(typed))
+		for name, level := range typed {
+			permissions[name] = readAnyString(level)
+		}
+		return normalizeGitHubPermissions(permissions)
+	}
+	permissions := map[string]string{}
+	for _, grant := range readStringSlice(metadata, key) {
+		name, level, ok := strings.Cut(grant, ":")
+		if ok {
+			permissions[name] = level
+		}
+	}
+	return normalizeGitHubPermissions(permissions)
+}
+
+func normalizeGitHubPermissions(permissions map[string]string) map[string]string {
+	out := map[string]string{}
+	for name, level := range permissions {
+		name = strings.ToLower(strings.TrimSpace(name))
+		level = strings.ToLower(strings.TrimSpace(level))
+		if name == "" || level == "" {
+			continue
+		}
+		out[name] = level
+	}
+	return out
+}
+
+func normalizeGitHubRepositoryIDs(ids []int64) []int64 {
+	seen := map[int64]struct{}{}
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// gitHubPermissionGrants renders permissions as sorted "name:level" grants.
+func gitHubPermissionGrants(permissions map[string]string) []string {
+	grants := make([]string, 0, len(permissions))
+	for name, level := range permissions {
+		grants = append(grants, name+":"+level)
+	}
+	sort.Strings(grants)
+	return grants
+}
+
+func buildGitHubInstallationCacheKey(req GitHubInstallationTokenRequest) string {
+	ids := make([]string, 0, len(req.RepositoryIDs))
+	for _, id := range req.RepositoryIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	return fmt.Sprintf(
+		"%s:%s:%s:%s",
+		req.InstallationID,
+		strings.Join(gitHubPermissionGrants(req.Permissions), "|"),
+		strings.Join(req.Repositories, "|"),
+		strings.Join(ids, "|"),
+	)
+}
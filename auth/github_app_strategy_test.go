@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestGitHubAppStrategy_MintsScopedInstallationTokensAndRenewsBeforeExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	privateKeyPEM := generateTestRSAPrivateKeyPEM(t)
+	privateKey, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		t.Fatalf("parse key: %v", err)
+	}
+
+	requests := 0
+	var lastBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveGitHubUserInstallationsForTest(w, r, "42") {
+			return
+		}
+		requests++
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/42/access_tokens" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		appJWT := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := verifyRS256ForTest(&privateKey.PublicKey, appJWT); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		claims := decodeJWTClaimsForTest(t, appJWT)
+		if claims["iss"] != "1234" || int64(claims["iat"].(float64)) != now.Add(-time.Minute).Unix() ||
+			int64(claims["exp"].(float64)) > now.Add(10*time.Minute).Unix() {
+			http.Error(w, fmt.Sprintf("unexpected claims %v", claims), http.StatusUnauthorized)
+			return
+		}
+		lastBody = map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&lastBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":                fmt.Sprintf("ghs_%d", requests),
+			"expires_at":           now.Add(time.Hour).Format(time.RFC3339),
+			"permissions":          map[string]string{"contents": "read", "issues": "write"},
+			"repository_selection": "selected",
+			"repositories":         []map[string]any{{"id": 7, "name": "api", "full_name": "acme/api"}},
+		})
+	}))
+	defer server.Close()
+
+	strategy := NewGitHubAppStrategy(GitHubAppStrategyConfig{
+		AppID:        "1234",
+		AppSlug:      "acme-sync",
+		PrivateKey:   privateKeyPEM,
+		APIBaseURL:   server.URL,
+		OAuthBaseURL: server.URL,
+		ClientID:     "Iv1.client",
+		ClientSecret: "client-secret",
+		Permissions:  map[string]string{"Contents": "read", "issues": "write"},
+		Now:          func() time.Time { return now },
+	})
+
+	begin, err := strategy.Begin(context.Background(), core.AuthBeginRequest{State: "state_1"})
+	if err != nil || begin.URL != "https://github.com/apps/acme-sync/installations/new?state=state_1" {
+		t.Fatalf("unexpected begin %+v %v", begin, err)
+	}
+
+	req := core.AuthCompleteRequest{
+		Scope:    core.ScopeRef{Type: "org", ID: "org_1"},
+		Code:     "user-code",
+		Metadata: map[string]any{"installation_id": "42", "repositories": []string{"api"}, "external_account_id": "spoofed"},
+	}
+	first, err := strategy.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if first.ExternalAccountID != "42" || first.Credential.AccessToken != "ghs_1" ||
+		strings.Join(first.GrantedGrants, ",") != "contents:read,issues:write" {
+		t.Fatalf("unexpected complete response %+v", first)
+	}
+	if fmt.Sprint(lastBody["repositories"]) != "[api]" || fmt.Sprint(lastBody["permissions"]) != "map[contents:read issues:write]" {
+		t.Fatalf("expected token request to be scoped, got %v", lastBody)
+	}
+
+	second, err := strategy.Complete(context.Background(), req)
+	if err != nil || second.Credential.AccessToken != "ghs_1" || requests != 1 {
+		t.Fatalf("expected cached installation token, got %q after %d requests (%v)", second.Credential.AccessToken, requests, err)
+	}
+
+	// Inside the renewal window the cached token is replaced.
+	now = now.Add(56 * time.Minute)
+	refreshed, err := strategy.Refresh(context.Background(), first.Credential)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.Credential.AccessToken != "ghs_2" || requests != 2 || fmt.Sprint(lastBody["repositories"]) != "[api]" {
+		t.Fatalf("expected refresh to mint a new scoped token, got %q after %d requests", refreshed.Credential.AccessToken, requests)
+	}
+}
+
+func TestGitHubAppStrategy_InstallationCredentialUsesInstallationRecord(t *testing.T) {
+	privateKeyPEM := generateTestRSAPrivateKeyPEM(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app/installations/77/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"ghs_install","expires_at":"2099-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+	strategy := NewGitHubAppStrategy(GitHubAppStrategyConfig{AppID: "1234", PrivateKey: privateKeyPEM, APIBaseURL: server.URL})
+
+	installation := core.Installation{
+		ID:         "inst_1",
+		ProviderID: "github",
+		Status:     core.InstallationStatusActive,
+		Metadata:   map[string]any{"external_installation_id": "77"},
+	}
+	credential, err := strategy.InstallationCredential(context.Background(), installation)
+	if err != nil {
+		t.Fatalf("installation credential: %v", err)
+	}
+	if credential.AccessToken != "ghs_install" || credential.Metadata["service_installation_id"] != "inst_1" ||
+		credential.Metadata["installation_id"] != "77" {
+		t.Fatalf("unexpected credential %+v", credential)
+	}
+
+	installation.Status = core.InstallationStatusSuspended
+	if _, err := strategy.InstallationCredential(context.Background(), installation); err == nil {
+		t.Fatalf("expected suspended installation to be rejected")
+	}
+	if _, err := strategy.InstallationToken(context.Background(), GitHubInstallationTokenRequest{InstallationID: "78"}); err == nil ||
+		!strings.Contains(err.Error(), "Not Found") {
+		t.Fatalf("expected token endpoint error, got %v", err)
+	}
+	if _, err := strategy.Complete(context.Background(), core.AuthCompleteRequest{}); err == nil {
+		t.Fatalf("expected installation_id to be required")
+	}
+}
+
+func TestGitHubAppStrategy_CompleteRejectsUnverifiedInstallations(t *testing.T) {
+	privateKeyPEM := generateTestRSAPrivateKeyPEM(t)
+	minted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveGitHubUserInstallationsForTest(w, r, "42") {
+			return
+		}
+		minted++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token":"ghs_install","expires_at":"2099-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+	scope := core.ScopeRef{Type: "org", ID: "org_1"}
+	config := GitHubAppStrategyConfig{AppID: "1234", PrivateKey: privateKeyPEM, APIBaseURL: server.URL, OAuthBaseURL: server.URL}
+
+	unverifiable := NewGitHubAppStrategy(config)
+	if _, err := unverifiable.Complete(context.Background(), core.AuthCompleteRequest{
+		Scope:    scope,
+		Metadata: map[string]any{"installation_id": "42"},
+	}); err == nil {
+		t.Fatalf("expected an installation without a way to verify it to be rejected")
+	}
+
+	withClient := config
+	withClient.ClientID, withClient.ClientSecret = "Iv1.client", "client-secret"
+	if _, err := NewGitHubAppStrategy(withClient).Complete(context.Background(), core.AuthCompleteRequest{
+		Scope:    scope,
+		Code:     "user-code",
+		Metadata: map[string]any{"installation_id": "99"},
+	}); err == nil || !strings.Contains(err.Error(), "does not belong") {
+		t.Fatalf("expected an installation the user cannot access to be rejected, got %v", err)
+	}
+	if _, err := NewGitHubAppStrategy(withClient).Complete(context.Background(), core.AuthCompleteRequest{
+		Scope:    scope,
+		Code:     "bad-code",
+		Metadata: map[string]any{"installation_id": "42"},
+	}); err == nil || !strings.Contains(err.Error(), "bad_verification_code") {
+		t.Fatalf("expected a failed code exchange to be rejected, got %v", err)
+	}
+
+	withStore := config
+	withStore.InstallationVerifier = GitHubInstallationStoreVerifier{
+		Store: stubInstallationLister{installations: []core.Installation{
+			{ID: "inst_1", ProviderID: "github_app", ScopeType: "org", ScopeID: "org_1", Status: core.InstallationStatusActive, Metadata: map[string]any{"external_installation_id": "42"}},
+			{ID: "inst_2", ProviderID: "github_app", ScopeType: "org", ScopeID: "org_1", Status: core.InstallationStatusUninstalled, Metadata: map[string]any{"external_installation_id": "43"}},
+		}},
+		ProviderID: "github_app",
+	}
+	strategy := NewGitHubAppStrategy(withStore)
+	for _, installationID := range []string{"43", "44"} {
+		if _, err := strategy.Complete(context.Background(), core.AuthCompleteRequest{
+			Scope:    scope,
+			Metadata: map[string]any{"installation_id": installationID},
+		}); err == nil {
+			t.Fatalf("expected installation %s to be rejected", installationID)
+		}
+	}
+	if minted != 0 {
+		t.Fatalf("expected no token to be minted for rejected installations, got %d", minted)
+	}
+	complete, err := strategy.Complete(context.Background(), core.AuthCompleteRequest{
+		Scope:    scope,
+		Metadata: map[string]any{"installation_id": "42", "external_account_id": "spoofed"},
+	})
+	if err != nil || complete.ExternalAccountID != "42" || minted != 1 {
+		t.Fatalf("expected the recorded installation to complete, got %+v %v", complete, err)
+	}
+}
+
+type stubInstallationLister struct {
+	core.InstallationStore
+	installations []core.Installation
+}
+
+func (s stubInstallationLister) ListByScope(_ context.Context, providerID string, scope core.ScopeRef) ([]core.Installation, error) {
+	out := []core.Installation{}
+	for _, installation := range s.installations {
+		if installation.ProviderID == providerID && installation.ScopeType == scope.Type && installation.ScopeID == scope.ID {
+			out = append(out, installation)
+		}
+	}
+	return out, nil
+}
+
+// serveGitHubUserInstallationsForTest answers the user code exchange and the
+// user installations listing, which holds installationID on its second page.
+func serveGitHubUserInstallationsForTest(w http.ResponseWriter, r *http.Request, installationID string) bool {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/login/oauth/access_token":
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "user-code" || r.PostForm.Get("client_secret") != "client-secret" {
+			_, _ = w.Write([]byte(`{"error":"bad_verification_code","error_description":"bad_verification_code"}`))
+			return true
+		}
+		_, _ = w.Write([]byte(`{"access_token":"ghu_user","token_type":"bearer"}`))
+		return true
+	case "/user/installations":
+		if r.Header.Get("Authorization") != "Bearer ghu_user" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+			return true
+		}
+		installations := []map[string]any{}
+		if r.URL.Query().Get("page") == "1" {
+			for id := 1000; id < 1100; id++ {
+				installations = append(installations, map[string]any{"id": id})
+			}
+		} else {
+			installations = append(installations, map[string]any{"id": json.Number(installationID)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"total_count": 101, "installations": installations})
+		return true
+	}
+	return false
+}
+
+func verifyRS256ForTest(publicKey *rsa.PublicKey, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature)
+}
@@ -17,4 +17,9 @@ var (
 	_ gocmd.Commander[UpsertInstallationMessage]       = (*UpsertInstallationCommand)(nil)
 	_ gocmd.Commander[UpdateInstallationStatusMessage] = (*UpdateInstallationStatusCommand)(nil)
 	_ gocmd.Commander[CreateSyncJobMessage]            = (*CreateSyncJobCommand)(nil)
+	_ gocmd.Commander[CancelSyncJobMessage]            = (*CancelSyncJobCommand)(nil)
+	_ gocmd.Commander[PauseSyncJobMessage]             = (*PauseSyncJobCommand)(nil)
+	_ gocmd.Commander[ResumeSyncJobMessage]            = (*ResumeSyncJobCommand)(nil)
+	_ gocmd.Commander[UpsertSyncScheduleMessage]       = (*UpsertSyncScheduleCommand)(nil)
+	_ gocmd.Commander[DeleteSyncScheduleMessage]       = (*DeleteSyncScheduleCommand)(nil)
 )
@@ -27,6 +27,17 @@ type SyncJobMutatingService interface {
 	CreateSyncJob(ctx context.Context, req core.CreateSyncJobRequest) (core.CreateSyncJobResult, error)
 }
 
+type SyncJobControlService interface {
+	CancelSyncJob(ctx context.Context, req core.ControlSyncJobRequest) (core.SyncJob, error)
+	PauseSyncJob(ctx context.Context, req core.ControlSyncJobRequest) (core.SyncJob, error)
+	ResumeSyncJob(ctx context.Context, req core.ControlSyncJobRequest) (core.SyncJob, error)
+}
+
+type SyncScheduleMutatingService interface {
+	UpsertSyncSchedule(ctx context.Context, req core.UpsertSyncScheduleRequest) (core.SyncSchedule, error)
+	DeleteSyncSchedule(ctx context.Context, id string) error
+}
+
 type ConnectCommand struct {
 	service MutatingService
 }
@@ -292,6 +303,101 @@ func (c *CreateSyncJobCommand) Execute(ctx context.Context, msg CreateSyncJobMes
 	return nil
 }
 
+type CancelSyncJobCommand struct {
+	service SyncJobControlService
+}
+
+func NewCancelSyncJobCommand(service SyncJobControlService) *CancelSyncJobCommand {
+	return &CancelSyncJobCommand{service: service}
+}
+
+func (c *CancelSyncJobCommand) Execute(ctx context.Context, msg CancelSyncJobMessage) error {
+	if c == nil || c.service == nil {
+		return commandDependencyError("command: sync job control service is required")
+	}
+	out, err := c.service.CancelSyncJob(ctx, msg.Request)
+	if err != nil {
+		return err
+	}
+	storeResult(ctx, out)
+	return nil
+}
+
+type PauseSyncJobCommand struct {
+	service SyncJobControlService
+}
+
+func NewPauseSyncJobCommand(service SyncJobControlService) *PauseSyncJobCommand {
+	return &PauseSyncJobCommand{service: service}
+}
+
+func (c *PauseSyncJobCommand) Execute(ctx context.Context, msg PauseSyncJobMessage) error {
+	if c == nil || c.service == nil {
+		return commandDependencyError("command: sync job control service is required")
+	}
+	out, err := c.service.PauseSyncJob(ctx, msg.Request)
+	if err != nil {
+		return err
+	}
+	storeResult(ctx, out)
+	return nil
+}
+
+type ResumeSyncJobCommand struct {
+	service SyncJobControlService
+}
+
+func NewResumeSyncJobCommand(service SyncJobControlService) *ResumeSyncJobCommand {
+	return &ResumeSyncJobCommand{service: service}
+}
+
+func (c *ResumeSyncJobCommand) Execute(ctx context.Context, msg ResumeSyncJobMessage) error {
+	if c == nil || c.service == nil {
+		return commandDependencyError("command: sync job control service is required")
+	}
+	out, err := c.service.ResumeSyncJob(ctx, msg.Request)
+	if err != nil {
+		return err
+	}
+	storeResult(ctx, out)
+	return nil
+}
+
+type UpsertSyncScheduleCommand struct {
+	service SyncScheduleMutatingService
+}
+
+func NewUpsertSyncScheduleCommand(service SyncScheduleMutatingService) *UpsertSyncScheduleCommand {
+	return &UpsertSyncScheduleCommand{service: service}
+}
+
+func (c *UpsertSyncScheduleCommand) Execute(ctx context.Context, msg UpsertSyncScheduleMessage) error {
+	if c == nil || c.service == nil {
+		return commandDependencyError("command: sync schedule service is required")
+	}
+	out, err := c.service.UpsertSyncSchedule(ctx, msg.Request)
+	if err != nil {
+		return err
+	}
+	storeResult(ctx, out)
+	return nil
+}
+
+type DeleteSyncScheduleCommand struct {
+	service SyncScheduleMutatingService
+}
+
+func NewDeleteSyncScheduleCommand(service SyncScheduleMutatingService) *DeleteSyncScheduleCommand {
+	return &DeleteSyncScheduleCommand{service: service}
+}
+
+func (c *DeleteSyncScheduleCommand) Execute(ctx context.Context, msg DeleteSyncScheduleMessage) error {
+	if c == nil || c.service == nil {
+		return commandDependencyError("command: sync schedule service is required")
+	}
+	return c.service.DeleteSyncSchedule(ctx, msg.SyncScheduleID)
+}
+
 func storeResult[T any](ctx context.Context, value T) {
 	collector := gocmd.ResultFromContext[T](ctx)
 	if collector == nil {
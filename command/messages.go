@@ -21,6 +21,11 @@ const (
 	TypeUpsertInstallation = "services.command.installation.upsert"
 	TypeUpdateInstallation = "services.command.installation.update_status"
 	TypeCreateSyncJob      = "services.command.sync_job.create"
+	TypeCancelSyncJob      = "services.command.sync_job.cancel"
+	TypePauseSyncJob       = "services.command.sync_job.pause"
+	TypeResumeSyncJob      = "services.command.sync_job.resume"
+	TypeUpsertSyncSchedule = "services.command.sync_schedule.upsert"
+	TypeDeleteSyncSchedule = "services.command.sync_schedule.delete"
 )
 
 type ConnectMessage struct {
@@ -269,6 +274,94 @@ func (m CreateSyncJobMessage) Validate() error {
 	return nil
 }
 
+type CancelSyncJobMessage struct {
+	Request core.ControlSyncJobRequest
+}
+
+func (CancelSyncJobMessage) Type() string { return TypeCancelSyncJob }
+
+func (m CancelSyncJobMessage) Validate() error {
+	return validateControlSyncJobRequest(m.Request)
+}
+
+type PauseSyncJobMessage struct {
+	Request core.ControlSyncJobRequest
+}
+
+func (PauseSyncJobMessage) Type() string { return TypePauseSyncJob }
+
+func (m PauseSyncJobMessage) Validate() error {
+	return validateControlSyncJobRequest(m.Request)
+}
+
+type ResumeSyncJobMessage struct {
+	Request core.ControlSyncJobRequest
+}
+
+func (ResumeSyncJobMessage) Type() string { return TypeResumeSyncJob }
+
+func (m ResumeSyncJobMessage) Validate() error {
+	return validateControlSyncJobRequest(m.Request)
+}
+
+type UpsertSyncScheduleMessage struct {
+	Request core.UpsertSyncScheduleRequest
+}
+
+func (UpsertSyncScheduleMessage) Type() string { return TypeUpsertSyncSchedule }
+
+func (m UpsertSyncScheduleMessage) Validate() error {
+	if strings.TrimSpace(m.Request.ProviderID) == "" {
+		return commandValidationError("provider_id", "provider id is required")
+	}
+	scope := core.ScopeRef{
+		Type: strings.TrimSpace(strings.ToLower(m.Request.ScopeType)),
+		ID:   strings.TrimSpace(m.Request.ScopeID),
+	}
+	if err := validateScope(scope); err != nil {
+		return err
+	}
+	hasCron := strings.TrimSpace(m.Request.CronExpression) != ""
+	if hasCron == (m.Request.Interval > 0) {
+		return commandInvalidInputError("command: exactly one of cron expression and interval is required")
+	}
+	if m.Request.Interval < 0 {
+		return commandValidationError("interval", "interval must not be negative")
+	}
+	if m.Request.Jitter < 0 {
+		return commandValidationError("jitter", "jitter must not be negative")
+	}
+	return nil
+}
+
+type DeleteSyncScheduleMessage struct {
+	SyncScheduleID string
+}
+
+func (DeleteSyncScheduleMessage) Type() string { return TypeDeleteSyncSchedule }
+
+func (m DeleteSyncScheduleMessage) Validate() error {
+	if strings.TrimSpace(m.SyncScheduleID) == "" {
+		return commandValidationError("sync_schedule_id", "sync schedule id is required")
+	}
+	return nil
+}
+
+func validateControlSyncJobRequest(req core.ControlSyncJobRequest) error {
+	if strings.TrimSpace(req.SyncJobID) == "" {
+		return commandValidationError("sync_job_id", "sync job id is required")
+	}
+	scopeType := strings.TrimSpace(strings.ToLower(req.ScopeType))
+	scopeID := strings.TrimSpace(req.ScopeID)
+	if (scopeType == "") != (scopeID == "") {
+		return commandInvalidInputError("command: scope type and scope id must both be provided")
+	}
+	if scopeType != "" {
+		return validateScope(core.ScopeRef{Type: scopeType, ID: scopeID})
+	}
+	return nil
+}
+
 func validateScope(scope core.ScopeRef) error {
 	if err := scope.Validate(); err != nil {
 		return commandWrapValidation(err, "command: invalid scope")
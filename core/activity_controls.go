@@ -16,11 +16,39 @@ type ActivityRetentionPruner interface {
 	Prune(ctx context.Context, policy ActivityRetentionPolicy) (deleted int, err error)
 }
 
+const activityRetentionLeaderLease = "services.activity_retention"
+
+type OperationalActivitySinkOption func(*OperationalActivitySink)
+
+// WithActivitySinkLeaderElector makes RunRetention prune only while this
+// replica holds the activity retention lease.
+func WithActivitySinkLeaderElector(elector *LeaderElector) OperationalActivitySinkOption {
+	return func(s *OperationalActivitySink) {
+		if s == nil || elector == nil {
+			return
+		}
+		s.elector = elector
+	}
+}
+
+// WithActivitySinkMetrics counts the rows RunRetention prunes and the runs
+// that fail.
+func WithActivitySinkMetrics(recorder MetricsRecorder) OperationalActivitySinkOption {
+	return func(s *OperationalActivitySink) {
+		if s == nil {
+			return
+		}
+		s.metrics = recorder
+	}
+}
+
 type OperationalActivitySink struct {
 	primary  ServicesActivitySink
 	fallback ServicesActivitySink
 	policy   ActivityRetentionPolicy
 	pruner   ActivityRetentionPruner
+	elector  *LeaderElector
+	metrics  MetricsRecorder
 
 	queue chan ServiceActivityEntry
 	now   func() time.Time
@@ -35,6 +63,7 @@ func NewOperationalActivitySink(
 	fallback ServicesActivitySink,
 	policy ActivityRetentionPolicy,
 	bufferSize int,
+	opts ...OperationalActivitySinkOption,
 ) (*OperationalActivitySink, error) {
 	if primary == nil {
 		return nil, fmt.Errorf("core: primary activity sink is required")
@@ -57,6 +86,11 @@ func NewOperationalActivitySink(
 	if pruner, ok := primary.(ActivityRetentionPruner); ok {
 		sink.pruner = pruner
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(sink)
+		}
+	}
 
 	go sink.run()
 	return sink, nil
@@ -106,6 +140,32 @@ func (s *OperationalActivitySink) EnforceRetention(ctx context.Context) (int, er
 	return pruner.Prune(ctx, s.policy)
 }
 
+// RunRetention enforces the retention policy every interval until ctx is
+// canceled. A failed prune is counted and retried on the next interval.
+func (s *OperationalActivitySink) RunRetention(ctx context.Context, interval time.Duration) error {
+	if s == nil {
+		return fmt.Errorf("core: operational activity sink is not configured")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("core: activity retention interval must be positive")
+	}
+	return runLeaderTicker(ctx, s.elector, activityRetentionLeaderLease, interval, s.retentionTick)
+}
+
+func (s *OperationalActivitySink) retentionTick(ctx context.Context) error {
+	deleted, err := s.EnforceRetention(ctx)
+	if s.metrics == nil || ctx.Err() != nil {
+		return err
+	}
+	if err != nil {
+		s.metrics.IncCounter(ctx, "services.activity.retention_failed.total", 1, nil)
+	}
+	if deleted > 0 {
+		s.metrics.IncCounter(ctx, "services.activity.pruned.total", int64(deleted), nil)
+	}
+	return err
+}
+
 func (s *OperationalActivitySink) Close() {
 	if s == nil {
 		return
@@ -104,6 +104,55 @@ func TestOperationalActivitySink_EnforceRetention(t *testing.T) {
 	}
 }
 
+func TestOperationalActivitySink_RunRetentionAsLeader(t *testing.T) {
+	pruner := &stubPruner{deleted: 3, pruned: make(chan struct{}, 4)}
+	elector, err := NewLeaderElector(newMemoryLeaderLeaseStore(), WithLeaderHolderID("replica_a"))
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+	metrics := &captureMetricsRecorder{}
+	sink, err := NewOperationalActivitySink(
+		pruner,
+		nil,
+		ActivityRetentionPolicy{RowCap: 10},
+		4,
+		WithActivitySinkLeaderElector(elector),
+		WithActivitySinkMetrics(metrics),
+	)
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	defer sink.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.RunRetention(ctx, 10*time.Millisecond)
+	}()
+	for range 2 {
+		select {
+		case <-pruner.pruned:
+		case <-time.After(time.Second):
+			t.Fatalf("expected retention to run repeatedly")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected canceled retention loop to return nil, got %v", err)
+	}
+	if pruner.lease.Name != activityRetentionLeaderLease || pruner.lease.HolderID != "replica_a" {
+		t.Fatalf("expected pruning to run under the retention lease, got %+v", pruner.lease)
+	}
+	metrics.mu.Lock()
+	if len(metrics.counters) < 2 || metrics.counters[0].name != "services.activity.pruned.total" || metrics.counters[0].value != 3 {
+		t.Fatalf("expected pruned rows to be counted, got %+v", metrics.counters)
+	}
+	metrics.mu.Unlock()
+	if err := sink.RunRetention(context.Background(), 0); err == nil {
+		t.Fatalf("expected non-positive interval to be rejected")
+	}
+}
+
 type blockingActivitySink struct {
 	block       chan struct{}
 	started     chan struct{}
@@ -157,7 +206,9 @@ func (s *bufferCapturingActivitySink) List(context.Context, ServicesActivityFilt
 
 type stubPruner struct {
 	lastPolicy ActivityRetentionPolicy
+	lease      LeaderLease
 	deleted    int
+	pruned     chan struct{}
 }
 
 func (s *stubPruner) Record(context.Context, ServiceActivityEntry) error {
@@ -168,8 +219,12 @@ func (s *stubPruner) List(context.Context, ServicesActivityFilter) (ServicesActi
 	return ServicesActivityPage{}, nil
 }
 
-func (s *stubPruner) Prune(_ context.Context, policy ActivityRetentionPolicy) (int, error) {
+func (s *stubPruner) Prune(ctx context.Context, policy ActivityRetentionPolicy) (int, error) {
 	s.lastPolicy = policy
+	s.lease, _ = LeaderLeaseFromContext(ctx)
+	if s.pruned != nil {
+		s.pruned <- struct{}{}
+	}
 	return s.deleted, nil
 }
 
@@ -115,7 +115,11 @@ type SaveCredentialInput struct {
 	EncryptionVersion int
 }
 
+// UpsertSubscriptionInput matches an existing subscription by provider and
+// channel, or by ID when set so a renewal that opened a new channel replaces
+// the record of the one it stopped.
 type UpsertSubscriptionInput struct {
+	ID                   string
 	ConnectionID         string
 	ProviderID           string
 	ResourceType         string
@@ -176,6 +180,82 @@ type GetSyncJobRequest struct {
 	ConnectionID string
 }
 
+// ControlSyncJobRequest names the sync job to cancel, pause or resume. The
+// provider, connection and scope fields guard the lookup like GetSyncJob.
+type ControlSyncJobRequest struct {
+	SyncJobID    string
+	ProviderID   string
+	ScopeType    string
+	ScopeID      string
+	ConnectionID string
+	Reason       string
+	RequestedBy  string
+}
+
+type ListSyncJobsRequest struct {
+	ProviderID   string
+	ConnectionID string
+	Statuses     []SyncJobStatus
+	Limit        int
+}
+
+type SyncJobFilter struct {
+	ProviderID   string
+	ConnectionID string
+	Statuses     []SyncJobStatus
+	// LeaseExpiredBefore matches jobs whose lease expired before the time.
+	LeaseExpiredBefore *time.Time
+	Limit              int
+}
+
+// UpsertSyncScheduleRequest defines a recurring sync for a connection or one
+// of its sync bindings. Exactly one of CronExpression and Interval is set;
+// Timezone applies to cron expressions and defaults to UTC.
+type UpsertSyncScheduleRequest struct {
+	SyncScheduleID string
+	ProviderID     string
+	ScopeType      string
+	ScopeID        string
+	ConnectionID   string
+	SyncBindingID  string
+	Mode           SyncJobMode
+	CronExpression string
+	Interval       time.Duration
+	Jitter         time.Duration
+	Timezone       string
+	Paused         bool
+	Metadata       map[string]any
+}
+
+type ListSyncSchedulesRequest struct {
+	ProviderID    string
+	ConnectionID  string
+	SyncBindingID string
+	Statuses      []SyncScheduleStatus
+	Limit         int
+}
+
+// ListSyncScheduleRunsRequest previews the next Count runs of a schedule
+// after After, which defaults to now.
+type ListSyncScheduleRunsRequest struct {
+	SyncScheduleID string
+	After          *time.Time
+	Count          int
+}
+
+type SyncScheduleFilter struct {
+	ProviderID    string
+	ConnectionID  string
+	SyncBindingID string
+	Statuses      []SyncScheduleStatus
+	// DueBefore matches schedules whose next run is at or before the time.
+	DueBefore *time.Time
+	// AfterID resumes the listing after the schedule with this id, in the
+	// listing's order, to page through more than Limit schedules.
+	AfterID string
+	Limit   int
+}
+
 type CreateSyncJobStoreInput struct {
 	ProviderID     string
 	Scope          ScopeRef
@@ -192,16 +272,33 @@ type SubscribeRequest struct {
 	ResourceID   string
 	CallbackURL  string
 	Metadata     map[string]any
+	// Credential is resolved by the service for the connection when nil.
+	Credential *ActiveCredential
 }
 
 type RenewSubscriptionRequest struct {
 	SubscriptionID string
 	Metadata       map[string]any
+	// RotateSecret asks providers that sign notifications with a generated
+	// secret to issue a new one; renewals keep the current secret otherwise.
+	RotateSecret bool
+	// SecretGracePeriod is how long the previous secret keeps verifying
+	// notifications after a rotation. Defaults to
+	// DefaultVerificationTokenGracePeriod.
+	SecretGracePeriod time.Duration
+	// Subscription and Credential are populated by the service before the
+	// provider is called.
+	Subscription *Subscription
+	Credential   *ActiveCredential
 }
 
 type CancelSubscriptionRequest struct {
 	SubscriptionID string
 	Reason         string
+	// Subscription and Credential are populated by the service before the
+	// provider is called.
+	Subscription *Subscription
+	Credential   *ActiveCredential
 }
 
 type SubscriptionResult struct {
@@ -209,6 +306,10 @@ type SubscriptionResult struct {
 	RemoteSubscriptionID string
 	ExpiresAt            *time.Time
 	Metadata             map[string]any
+	// VerificationToken is a provider-issued or generated secret used to
+	// verify notifications. The service stores it encrypted as the
+	// subscription VerificationTokenRef.
+	VerificationToken string
 }
 
 type ListChangesRequest struct {
@@ -217,6 +318,24 @@ type ListChangesRequest struct {
 	ResourceID   string
 	Cursor       string
 	Limit        int
+	Metadata     map[string]any
+	// Credential authenticates the provider call; callers resolve it for the
+	// connection before listing.
+	Credential *ActiveCredential
+}
+
+type DiscoverSchemaRequest struct {
+	ConnectionID string
+	Scope        ScopeRef
+	// ResourceType and ResourceID name a container when the provider scopes
+	// schemas to one, such as a spreadsheet or form.
+	ResourceType string
+	ResourceID   string
+	// Objects limits discovery to the named objects; empty discovers the
+	// provider defaults.
+	Objects    []string
+	Metadata   map[string]any
+	Credential *ActiveCredential
 }
 
 type ListChangesResult struct {
@@ -687,6 +806,7 @@ const (
 	AuthKindMTLS                   AuthKind = "mtls"
 	AuthKindBasic                  AuthKind = "basic"
 	AuthKindAWSSigV4               AuthKind = "aws_sigv4"
+	AuthKindGitHubApp              AuthKind = "github_app"
 )
 
 type AuthKind string
@@ -885,6 +1005,12 @@ type IncrementalSyncProvider interface {
 	ListChanges(ctx context.Context, req ListChangesRequest) (ListChangesResult, error)
 }
 
+// SchemaDiscoveryProvider describes provider objects as an ExternalSchema so
+// mapping specs can be validated without hand-written schemas.
+type SchemaDiscoveryProvider interface {
+	DiscoverSchema(ctx context.Context, req DiscoverSchemaRequest) (ExternalSchema, error)
+}
+
 type EmbeddedAuthProvider interface {
 	AuthenticateEmbedded(ctx context.Context, req EmbeddedAuthRequest) (EmbeddedAuthResult, error)
 }
@@ -921,6 +1047,42 @@ type SyncJobStore interface {
 	GetSyncJob(ctx context.Context, id string) (SyncJob, error)
 }
 
+type SyncScheduleStore interface {
+	UpsertSyncSchedule(ctx context.Context, schedule SyncSchedule) (SyncSchedule, error)
+	GetSyncSchedule(ctx context.Context, id string) (SyncSchedule, error)
+	ListSyncSchedules(ctx context.Context, filter SyncScheduleFilter) ([]SyncSchedule, error)
+	DeleteSyncSchedule(ctx context.Context, id string) error
+}
+
+// SyncJobExecutionStore persists the execution state of sync jobs.
+// UpdateSyncJobIf saves the job only while the stored job is still in the
+// expected status and reports false, with the stored job, when another writer
+// moved it first.
+type SyncJobExecutionStore interface {
+	GetSyncJob(ctx context.Context, id string) (SyncJob, error)
+	UpdateSyncJobIf(ctx context.Context, job SyncJob, expected SyncJobStatus) (SyncJob, bool, error)
+	ListSyncJobs(ctx context.Context, filter SyncJobFilter) ([]SyncJob, error)
+}
+
+// SyncJobRunner executes sync jobs for one provider. Runners report progress
+// as they go and stop when the reporter returns ErrSyncJobInterrupted.
+type SyncJobRunner interface {
+	RunSyncJob(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error
+}
+
+type SyncJobProgressReporter interface {
+	Report(ctx context.Context, update SyncJobProgressUpdate) error
+}
+
+// SyncJobProgressUpdate carries absolute counts; zero values keep the
+// previously reported value.
+type SyncJobProgressUpdate struct {
+	ItemsProcessed int
+	ItemsTotal     int
+	PagesProcessed int
+	Checkpoint     string
+}
+
 type InboundHandler interface {
 	Surface() string
 	Handle(ctx context.Context, req InboundRequest) (InboundResult, error)
@@ -991,6 +1153,105 @@ type OutboxStore interface {
 	Retry(ctx context.Context, eventID string, cause error, nextAttemptAt time.Time) error
 }
 
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending    OutboxEventStatus = "pending"
+	OutboxEventStatusProcessing OutboxEventStatus = "processing"
+	OutboxEventStatusDelivered  OutboxEventStatus = "delivered"
+	OutboxEventStatusFailed     OutboxEventStatus = "failed"
+	OutboxEventStatusDiscarded  OutboxEventStatus = "discarded"
+)
+
+type OutboxProjectorStatus string
+
+const (
+	OutboxProjectorStatusSucceeded OutboxProjectorStatus = "succeeded"
+	OutboxProjectorStatusFailed    OutboxProjectorStatus = "failed"
+)
+
+// OutboxProjectorDelivery is the delivery state of one event for one
+// projector.
+type OutboxProjectorDelivery struct {
+	EventID     string
+	Projector   string
+	Status      OutboxProjectorStatus
+	Attempts    int
+	LastError   string
+	DeliveredAt *time.Time
+	UpdatedAt   time.Time
+}
+
+type OutboxEntry struct {
+	Event         LifecycleEvent
+	PartitionKey  string
+	Status        OutboxEventStatus
+	Attempts      int
+	NextAttemptAt *time.Time
+	LastError     string
+	Projectors    []OutboxProjectorDelivery
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type OutboxFilter struct {
+	Status       OutboxEventStatus
+	EventName    string
+	ProviderID   string
+	ScopeType    string
+	ScopeID      string
+	ConnectionID string
+	Page         int
+	PerPage      int
+}
+
+type OutboxPage struct {
+	Items   []OutboxEntry
+	Page    int
+	PerPage int
+	Total   int
+	HasNext bool
+}
+
+// OutboxStats summarizes the outbox backlog. Failed events are the dead
+// letters: they exhausted their attempts and wait for an operator.
+type OutboxStats struct {
+	Pending         int
+	Processing      int
+	Failed          int
+	Discarded       int
+	OldestPendingAt *time.Time
+}
+
+// OutboxAdminStore exposes outbox events to operators. Requeue gives a failed
+// or discarded event a fresh attempt budget; Discard parks an undelivered
+// event so it no longer blocks its partition.
+type OutboxAdminStore interface {
+	List(ctx context.Context, filter OutboxFilter) (OutboxPage, error)
+	Get(ctx context.Context, eventID string) (OutboxEntry, error)
+	Requeue(ctx context.Context, eventID string) error
+	Discard(ctx context.Context, eventID string) error
+	Stats(ctx context.Context) (OutboxStats, error)
+}
+
+// OutboxProjectorStore tracks delivery per projector so a retried event only
+// re-runs the projectors that have not succeeded yet.
+type OutboxProjectorStore interface {
+	ListProjectorDeliveries(ctx context.Context, eventID string) ([]OutboxProjectorDelivery, error)
+	RecordProjectorDelivery(ctx context.Context, eventID string, projector string, cause error) error
+}
+
+type NamedLifecycleEventHandler struct {
+	Name    string
+	Handler LifecycleEventHandler
+}
+
+// NamedProjectorRegistry is implemented by registries that can report the
+// name each handler was registered under.
+type NamedProjectorRegistry interface {
+	NamedHandlers() []NamedLifecycleEventHandler
+}
+
 type NotificationProjector interface {
 	Handle(ctx context.Context, event LifecycleEvent) error
 }
@@ -16,7 +16,13 @@ var (
 	ErrInvalidSyncJobStatusTransition      = errors.New("core: invalid sync job status transition")
 	ErrInvalidSyncJobMode                  = errors.New("core: invalid sync job mode")
 	ErrInvalidSyncJobScope                 = errors.New("core: invalid sync job scope")
+	ErrCredentialNotFound                  = errors.New("core: active credential not found")
 	ErrSyncJobNotFound                     = errors.New("core: sync job not found")
+	ErrSyncJobRunnerNotFound               = errors.New("core: sync job runner not found")
+	ErrSyncJobInterrupted                  = errors.New("core: sync job was canceled or paused")
+	ErrSyncScheduleNotFound                = errors.New("core: sync schedule not found")
+	ErrInvalidSyncSchedule                 = errors.New("core: invalid sync schedule")
+	ErrLeadershipLost                      = errors.New("core: leadership lost")
 )
 
 type ScopeType string
@@ -322,6 +328,8 @@ const (
 	SyncJobStatusRunning   SyncJobStatus = "running"
 	SyncJobStatusSucceeded SyncJobStatus = "succeeded"
 	SyncJobStatusFailed    SyncJobStatus = "failed"
+	SyncJobStatusPaused    SyncJobStatus = "paused"
+	SyncJobStatusCanceled  SyncJobStatus = "canceled"
 )
 
 type SyncJobMode string
@@ -343,9 +351,25 @@ type SyncJob struct {
 	Status        SyncJobStatus
 	Attempts      int
 	NextAttemptAt *time.Time
-	Metadata      map[string]any
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// LeaseExpiresAt is when a running job is considered abandoned by its
+	// executor and may be claimed again.
+	LeaseExpiresAt *time.Time
+	Progress       SyncJobProgress
+	Metadata       map[string]any
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SyncJobProgress is the last progress reported by a job's runner. Percent and
+// EstimatedCompletionAt are derived from the item counts when the total is
+// known.
+type SyncJobProgress struct {
+	ItemsProcessed        int
+	ItemsTotal            int
+	PagesProcessed        int
+	Percent               float64
+	StartedAt             *time.Time
+	EstimatedCompletionAt *time.Time
 }
 
 func (j *SyncJob) TransitionTo(status SyncJobStatus, now time.Time) error {
@@ -367,18 +391,29 @@ func (j *SyncJob) TransitionTo(status SyncJobStatus, now time.Time) error {
 func syncJobTransitionAllowed(current, next SyncJobStatus) bool {
 	allowed := map[SyncJobStatus]map[SyncJobStatus]struct{}{
 		SyncJobStatusQueued: {
-			SyncJobStatusRunning: {},
-			SyncJobStatusFailed:  {},
+			SyncJobStatusRunning:  {},
+			SyncJobStatusFailed:   {},
+			SyncJobStatusPaused:   {},
+			SyncJobStatusCanceled: {},
 		},
 		SyncJobStatusRunning: {
 			SyncJobStatusSucceeded: {},
 			SyncJobStatusFailed:    {},
+			SyncJobStatusQueued:    {},
+			SyncJobStatusPaused:    {},
+			SyncJobStatusCanceled:  {},
 		},
 		SyncJobStatusFailed: {
-			SyncJobStatusQueued:  {},
-			SyncJobStatusRunning: {},
+			SyncJobStatusQueued:   {},
+			SyncJobStatusRunning:  {},
+			SyncJobStatusCanceled: {},
+		},
+		SyncJobStatusPaused: {
+			SyncJobStatusQueued:   {},
+			SyncJobStatusCanceled: {},
 		},
 		SyncJobStatusSucceeded: {},
+		SyncJobStatusCanceled:  {},
 	}
 	_, ok := allowed[current][next]
 	return ok
@@ -63,7 +63,9 @@ func serviceErrorMapper(err error) *goerrors.Error {
 		return newServiceErrorFromSource(err, goerrors.CategoryNotFound, ServiceErrorSyncJobNotFound)
 	case errors.Is(err, ErrSyncCursorConflict):
 		return newServiceErrorFromSource(err, goerrors.CategoryConflict, ServiceErrorSyncCursorConflict)
-	case errors.Is(err, ErrInvalidSyncJobMode), errors.Is(err, ErrInvalidSyncJobScope):
+	case errors.Is(err, ErrSyncScheduleNotFound):
+		return newServiceErrorFromSource(err, goerrors.CategoryNotFound, ServiceErrorNotFound)
+	case errors.Is(err, ErrInvalidSyncJobMode), errors.Is(err, ErrInvalidSyncJobScope), errors.Is(err, ErrInvalidSyncSchedule):
 		return newServiceErrorFromSource(err, goerrors.CategoryBadInput, ServiceErrorBadInput)
 	case errors.Is(err, ErrEmbeddedAuthUnsupported):
 		return newServiceErrorFromSource(err, goerrors.CategoryOperation, ServiceErrorEmbeddedAuthUnsupported)
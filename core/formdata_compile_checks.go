@@ -200,6 +200,15 @@ func (compileCheckSyncChangeLogStore) ListSince(
 	return nil, "", nil
 }
 
+func (compileCheckSyncChangeLogStore) GetLatest(
+	ctx context.Context,
+	syncBindingID string,
+	direction SyncDirection,
+	externalID string,
+) (SyncChangeLogEntry, bool, error) {
+	return SyncChangeLogEntry{}, false, nil
+}
+
 type compileCheckExternalFormDataService struct{}
 
 func (compileCheckExternalFormDataService) ValidateMappingSpec(
@@ -277,6 +286,7 @@ var (
 	_ SyncConflictStore           = (*compileCheckSyncConflictStore)(nil)
 	_ SyncCheckpointStore         = (*compileCheckSyncCheckpointStore)(nil)
 	_ SyncChangeLogStore          = (*compileCheckSyncChangeLogStore)(nil)
+	_ SyncChangeLogReader         = (*compileCheckSyncChangeLogStore)(nil)
 	_ MappingSpecValidator        = (*compileCheckExternalFormDataService)(nil)
 	_ MappingSpecPreviewer        = (*compileCheckExternalFormDataService)(nil)
 	_ SyncPlanner                 = (*compileCheckExternalFormDataService)(nil)
@@ -286,6 +296,7 @@ var (
 	_ MappingSpecCompiler         = (*compileCheckMappingSpecCompiler)(nil)
 	_ MappingSpecValidator        = (*MappingCompiler)(nil)
 	_ MappingSpecCompiler         = (*MappingCompiler)(nil)
+	_ MappingSpecInverter         = (*MappingCompiler)(nil)
 	_ MappingSpecPreviewer        = (*MappingPreviewer)(nil)
 	_ IdentityReconciler          = (*IdentityBindingReconciler)(nil)
 	_ SyncPlanner                 = (*SyncPlannerService)(nil)
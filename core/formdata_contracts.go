@@ -29,6 +29,10 @@ type SyncDirection string
 const (
 	SyncDirectionImport SyncDirection = "import"
 	SyncDirectionExport SyncDirection = "export"
+	// SyncDirectionBidirectional is only valid on bindings. Runs, plans and
+	// checkpoints always carry import or export; export runs use the inverse
+	// of the binding's import mapping.
+	SyncDirectionBidirectional SyncDirection = "bidirectional"
 )
 
 func (d SyncDirection) IsValid() bool {
@@ -40,6 +44,15 @@ func (d SyncDirection) IsValid() bool {
 	}
 }
 
+// Allows reports whether a binding with direction d can run in the given
+// run direction.
+func (d SyncDirection) Allows(run SyncDirection) bool {
+	if !run.IsValid() {
+		return false
+	}
+	return d == run || d == SyncDirectionBidirectional
+}
+
 type SyncRunMode string
 
 const (
@@ -62,12 +75,13 @@ const (
 	SyncRunStatusPlanned   SyncRunStatus = "planned"
 	SyncRunStatusRunning   SyncRunStatus = "running"
 	SyncRunStatusSucceeded SyncRunStatus = "succeeded"
+	SyncRunStatusPartial   SyncRunStatus = "partial"
 	SyncRunStatusFailed    SyncRunStatus = "failed"
 )
 
 func (s SyncRunStatus) IsValid() bool {
 	switch s {
-	case SyncRunStatusPlanned, SyncRunStatusRunning, SyncRunStatusSucceeded, SyncRunStatusFailed:
+	case SyncRunStatusPlanned, SyncRunStatusRunning, SyncRunStatusSucceeded, SyncRunStatusPartial, SyncRunStatusFailed:
 		return true
 	default:
 		return false
@@ -77,13 +91,14 @@ func (s SyncRunStatus) IsValid() bool {
 type SyncBindingStatus string
 
 const (
-	SyncBindingStatusActive SyncBindingStatus = "active"
-	SyncBindingStatusPaused SyncBindingStatus = "paused"
+	SyncBindingStatusActive   SyncBindingStatus = "active"
+	SyncBindingStatusPaused   SyncBindingStatus = "paused"
+	SyncBindingStatusDisabled SyncBindingStatus = "disabled"
 )
 
 func (s SyncBindingStatus) IsValid() bool {
 	switch s {
-	case SyncBindingStatusActive, SyncBindingStatusPaused:
+	case SyncBindingStatusActive, SyncBindingStatusPaused, SyncBindingStatusDisabled:
 		return true
 	default:
 		return false
@@ -145,6 +160,75 @@ func (a SyncConflictResolutionAction) IsValid() bool {
 	}
 }
 
+// SyncConflictStrategy names how a detected sync conflict is settled. The
+// source is the external provider and the target the host domain model.
+type SyncConflictStrategy string
+
+const (
+	SyncConflictStrategyLastWriterWins SyncConflictStrategy = "last_writer_wins"
+	SyncConflictStrategySourceWins     SyncConflictStrategy = "source_wins"
+	SyncConflictStrategyTargetWins     SyncConflictStrategy = "target_wins"
+	SyncConflictStrategyThreeWayMerge  SyncConflictStrategy = "three_way_merge"
+	SyncConflictStrategyManual         SyncConflictStrategy = "manual"
+)
+
+// SyncBindingMetadataConflictStrategy overrides the default conflict strategy
+// for a binding through its metadata.
+const SyncBindingMetadataConflictStrategy = "conflict_strategy"
+
+func (s SyncConflictStrategy) IsValid() bool {
+	switch s {
+	case SyncConflictStrategyLastWriterWins,
+		SyncConflictStrategySourceWins,
+		SyncConflictStrategyTargetWins,
+		SyncConflictStrategyThreeWayMerge,
+		SyncConflictStrategyManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// SyncConflictOutcome is the decision a conflict strategy reaches.
+type SyncConflictOutcome string
+
+const (
+	// SyncConflictOutcomeApply delivers the decision payload, or the incoming
+	// change when the payload is nil.
+	SyncConflictOutcomeApply SyncConflictOutcome = "apply"
+	// SyncConflictOutcomeKeep keeps the current version and skips the change.
+	SyncConflictOutcomeKeep SyncConflictOutcome = "keep"
+	// SyncConflictOutcomeManual leaves the conflict pending for an operator.
+	SyncConflictOutcomeManual SyncConflictOutcome = "manual"
+)
+
+// SyncChangeOperation names what happened to a record at its origin.
+type SyncChangeOperation string
+
+const (
+	SyncChangeOperationCreate  SyncChangeOperation = "create"
+	SyncChangeOperationUpdate  SyncChangeOperation = "update"
+	SyncChangeOperationDelete  SyncChangeOperation = "delete"
+	SyncChangeOperationRestore SyncChangeOperation = "restore"
+)
+
+func (o SyncChangeOperation) IsValid() bool {
+	switch o {
+	case SyncChangeOperationCreate,
+		SyncChangeOperationUpdate,
+		SyncChangeOperationDelete,
+		SyncChangeOperationRestore:
+		return true
+	default:
+		return false
+	}
+}
+
+// SyncBindingMetadataTombstoneRetention overrides how long deleted records
+// are retained before purge, as a duration string such as "720h" or a number
+// of seconds.
+const SyncBindingMetadataTombstoneRetention = "tombstone_retention"
+
 type ExternalField struct {
 	Path        string
 	Type        string
@@ -276,8 +360,11 @@ type IdentityBinding struct {
 	MatchKind     IdentityBindingMatchKind
 	Confidence    float64
 	Metadata      map[string]any
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// OrphanedAt is set when the external record was deleted and cleared
+	// again when it is restored.
+	OrphanedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 func (b IdentityBinding) Validate() error {
@@ -365,7 +452,7 @@ func (b SyncBinding) Validate() error {
 	if strings.TrimSpace(b.TargetModel) == "" {
 		return fmt.Errorf("core: target model is required")
 	}
-	if !b.Direction.IsValid() {
+	if !b.Direction.IsValid() && b.Direction != SyncDirectionBidirectional {
 		return fmt.Errorf("core: invalid sync direction %q", b.Direction)
 	}
 	if !b.Status.IsValid() {
@@ -491,12 +578,18 @@ type CompiledMappingRule struct {
 	SourceType string
 	TargetType string
 	Transform  string
+
+	expression *mappingExpression
+	paths      *mappingRulePaths
 }
 
 type CompiledMappingSpec struct {
-	SpecID            string
-	Version           int
-	SourceObject      string
+	SpecID       string
+	Version      int
+	SourceObject string
+	// Inverse marks a spec derived to map target model records back to
+	// SourceObject.
+	Inverse           bool
 	Rules             []CompiledMappingRule
 	DeterministicHash string
 }
@@ -514,13 +607,18 @@ type PreviewMappingSpecRequest struct {
 	Samples []map[string]any
 }
 
+// PreviewFieldDiff reports one applied rule. Collection rules report one
+// diff per element; the element paths then carry the concrete indexes, e.g.
+// `items[2].sku` and `line_items[2].sku`.
 type PreviewFieldDiff struct {
-	RuleID      string
-	SourcePath  string
-	TargetPath  string
-	InputValue  any
-	OutputValue any
-	Changed     bool
+	RuleID            string
+	SourcePath        string
+	TargetPath        string
+	SourceElementPath string
+	TargetElementPath string
+	InputValue        any
+	OutputValue       any
+	Changed           bool
 }
 
 type PreviewRecord struct {
@@ -545,7 +643,10 @@ type PreviewMappingSpecResult struct {
 }
 
 type PlanSyncRunRequest struct {
-	Binding          SyncBinding
+	Binding SyncBinding
+	// Direction defaults to the binding direction and is required for
+	// bidirectional bindings.
+	Direction        SyncDirection
 	Mode             SyncRunMode
 	FromCheckpointID string
 	Limit            int
@@ -565,11 +666,17 @@ type SyncRunPlan struct {
 }
 
 type SyncChange struct {
+	// Operation defaults to update. Deletes are not mapped and may carry an
+	// empty payload, as may restores.
+	Operation     SyncChangeOperation
 	SourceObject  string
 	ExternalID    string
 	SourceVersion string
-	Payload       map[string]any
-	Metadata      map[string]any
+	// ModifiedAt is when the record last changed on its side, used by the
+	// last-writer-wins conflict strategy. Zero means the time of the run.
+	ModifiedAt time.Time
+	Payload    map[string]any
+	Metadata   map[string]any
 }
 
 type RunSyncImportRequest struct {
@@ -590,9 +697,12 @@ type SyncRunResult struct {
 	ProcessedCount int
 	SkippedCount   int
 	ConflictCount  int
-	FailedCount    int
-	NextCheckpoint *SyncCheckpoint
-	Metadata       map[string]any
+	// ResolvedConflictCount counts detected conflicts that the binding's
+	// conflict strategy settled during the run.
+	ResolvedConflictCount int
+	FailedCount           int
+	NextCheckpoint        *SyncCheckpoint
+	Metadata              map[string]any
 }
 
 type SyncConflictResolution struct {
@@ -749,6 +859,7 @@ type SyncChangeLogEntry struct {
 	ConnectionID   string
 	SyncBindingID  string
 	Direction      SyncDirection
+	Operation      SyncChangeOperation
 	SourceObject   string
 	ExternalID     string
 	SourceVersion  string
@@ -769,6 +880,81 @@ type SyncChangeLogStore interface {
 	) ([]SyncChangeLogEntry, string, error)
 }
 
+// SyncChangeLogReader returns the last entry logged for a record, which
+// conflict detection compares incoming changes against.
+type SyncChangeLogReader interface {
+	GetLatest(
+		ctx context.Context,
+		syncBindingID string,
+		direction SyncDirection,
+		externalID string,
+	) (SyncChangeLogEntry, bool, error)
+}
+
+// SyncMappedRecord is a sync change after the binding's published mapping was
+// applied. Input holds the change payload and Output the mapped record.
+// Deletes and restores carry the identity-bound InternalID when one is known,
+// and deletes carry RetainUntil when the binding retains tombstones.
+type SyncMappedRecord struct {
+	RunID          string
+	Binding        SyncBinding
+	Direction      SyncDirection
+	Operation      SyncChangeOperation
+	InternalID     string
+	RetainUntil    *time.Time
+	MappingSpecID  string
+	MappingVersion int
+	SourceObject   string
+	ExternalID     string
+	SourceVersion  string
+	IdempotencyKey string
+	Input          map[string]any
+	Output         map[string]any
+	Issues         []MappingValidationIssue
+	Metadata       map[string]any
+}
+
+// SyncSinkConflict reports that the destination holds a version of the record
+// that the sink refused to overwrite.
+type SyncSinkConflict struct {
+	Reason        string
+	Policy        string
+	TargetPayload map[string]any
+	Metadata      map[string]any
+}
+
+type SyncSinkResult struct {
+	InternalID string
+	Conflict   *SyncSinkConflict
+	Metadata   map[string]any
+}
+
+// SyncTargetSink writes mapped import records into the host domain model.
+// Records may be redelivered after a partial run, so implementations should
+// treat IdempotencyKey as the deduplication key.
+type SyncTargetSink interface {
+	ApplySyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+}
+
+// SyncSourceWriter writes mapped export records to the external provider.
+// Records may be redelivered after a partial run, so implementations should
+// treat IdempotencyKey as the deduplication key.
+type SyncSourceWriter interface {
+	WriteSyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+}
+
+// SyncTargetDeleter is implemented by target sinks that handle deleted
+// records. Restores are delivered through ApplySyncRecord.
+type SyncTargetDeleter interface {
+	DeleteSyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+}
+
+// SyncSourceDeleter is implemented by source writers that handle records
+// deleted in the host domain model.
+type SyncSourceDeleter interface {
+	DeleteSourceRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+}
+
 type MappingSpecValidator interface {
 	ValidateMappingSpec(
 		ctx context.Context,
@@ -783,6 +969,14 @@ type MappingSpecCompiler interface {
 	) (CompiledMappingSpec, []MappingValidationIssue, error)
 }
 
+// MappingSpecInverter derives the export direction of an import spec.
+type MappingSpecInverter interface {
+	CompileInverseMappingSpec(
+		ctx context.Context,
+		req ValidateMappingSpecRequest,
+	) (CompiledMappingSpec, []MappingValidationIssue, error)
+}
+
 type MappingSpecPreviewer interface {
 	PreviewMappingSpec(
 		ctx context.Context,
@@ -811,6 +1005,45 @@ type SyncConflictService interface {
 	SyncConflictResolver
 }
 
+// DetectedSyncConflict describes an incoming change that disagrees with the
+// last synced state of the record. Base and Current are in the shape of the
+// incoming change payload.
+type DetectedSyncConflict struct {
+	Binding   SyncBinding
+	Direction SyncDirection
+	Strategy  SyncConflictStrategy
+	Reason    string
+	Change    SyncChange
+	// Base is the payload last synced in the run direction, nil if none.
+	Base map[string]any
+	// Current is the latest version known from the change log.
+	Current           map[string]any
+	CurrentVersion    string
+	CurrentModifiedAt time.Time
+	// Fields lists the leaf paths on which Change and Current disagree.
+	Fields []string
+}
+
+type SyncConflictDecision struct {
+	Outcome SyncConflictOutcome
+	Payload map[string]any
+	Reason  string
+}
+
+// SyncConflictMerger implements a conflict strategy.
+type SyncConflictMerger interface {
+	MergeSyncConflict(ctx context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error)
+}
+
+type SyncConflictMergerFunc func(ctx context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error)
+
+func (fn SyncConflictMergerFunc) MergeSyncConflict(
+	ctx context.Context,
+	conflict DetectedSyncConflict,
+) (SyncConflictDecision, error) {
+	return fn(ctx, conflict)
+}
+
 type ExternalFormDataService interface {
 	MappingSpecValidator
 	MappingSpecPreviewer
@@ -0,0 +1,340 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLeaderLeaseTTL      = 30 * time.Second
+	defaultLeaderRetryInterval = 5 * time.Second
+)
+
+// LeaderLease is a named, time-bounded claim of leadership. FencingToken
+// increases every time the lease changes hands, so writers can reject work
+// from a holder that has since been replaced.
+type LeaderLease struct {
+	Name         string
+	HolderID     string
+	FencingToken int64
+	AcquiredAt   time.Time
+	ExpiresAt    time.Time
+}
+
+// LeaderLeaseStore persists leader leases. AcquireLeaderLease takes a lease
+// that is free or expired, issuing a new fencing token, and extends a lease the
+// holder already owns; it reports false when another holder owns a live lease.
+// RenewLeaderLease and ReleaseLeaderLease return ErrLeadershipLost once the
+// lease belongs to another holder or fencing token.
+type LeaderLeaseStore interface {
+	AcquireLeaderLease(ctx context.Context, name string, holderID string, ttl time.Duration) (LeaderLease, bool, error)
+	RenewLeaderLease(ctx context.Context, lease LeaderLease, ttl time.Duration) (LeaderLease, error)
+	ReleaseLeaderLease(ctx context.Context, lease LeaderLease) error
+}
+
+type LeaderElectorOption func(*LeaderElector)
+
+// WithLeaderHolderID sets the identity recorded on acquired leases. It
+// defaults to the host name plus a random suffix.
+func WithLeaderHolderID(holderID string) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil || strings.TrimSpace(holderID) == "" {
+			return
+		}
+		e.holderID = strings.TrimSpace(holderID)
+	}
+}
+
+// WithLeaderLeaseTTL sets how long a lease outlives its last renewal, which is
+// how long a crashed leader blocks handover.
+func WithLeaderLeaseTTL(ttl time.Duration) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil || ttl <= 0 {
+			return
+		}
+		e.ttl = ttl
+	}
+}
+
+// WithLeaderRenewInterval sets how often the leader renews its lease. It
+// defaults to a third of the TTL.
+func WithLeaderRenewInterval(interval time.Duration) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil || interval <= 0 {
+			return
+		}
+		e.renewInterval = interval
+	}
+}
+
+// WithLeaderRetryInterval sets how often followers try to take the lease.
+func WithLeaderRetryInterval(interval time.Duration) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil || interval <= 0 {
+			return
+		}
+		e.retryInterval = interval
+	}
+}
+
+// WithLeaderElectorMetrics counts lease store errors by lease and operation,
+// so a replica that keeps failing to campaign or renew is visible.
+func WithLeaderElectorMetrics(recorder MetricsRecorder) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil {
+			return
+		}
+		e.metrics = recorder
+	}
+}
+
+// WithLeaderElectorErrorHandler receives every lease store error. Campaigns
+// and renewals keep retrying after an error; the handler only observes them.
+func WithLeaderElectorErrorHandler(handler func(ctx context.Context, name string, err error)) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil {
+			return
+		}
+		e.onError = handler
+	}
+}
+
+func WithLeaderElectorClock(now func() time.Time) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		if e == nil || now == nil {
+			return
+		}
+		e.now = now
+	}
+}
+
+// LeaderElector runs periodic work on a single replica at a time. A nil
+// elector runs the work directly, so runners can accept one optionally.
+type LeaderElector struct {
+	store         LeaderLeaseStore
+	holderID      string
+	ttl           time.Duration
+	renewInterval time.Duration
+	retryInterval time.Duration
+	now           func() time.Time
+	metrics       MetricsRecorder
+	onError       func(ctx context.Context, name string, err error)
+}
+
+func NewLeaderElector(store LeaderLeaseStore, opts ...LeaderElectorOption) (*LeaderElector, error) {
+	if store == nil {
+		return nil, fmt.Errorf("core: leader lease store is required")
+	}
+	elector := &LeaderElector{
+		store:         store,
+		ttl:           defaultLeaderLeaseTTL,
+		retryInterval: defaultLeaderRetryInterval,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(elector)
+	}
+	if elector.holderID == "" {
+		holderID, err := defaultLeaderHolderID()
+		if err != nil {
+			return nil, err
+		}
+		elector.holderID = holderID
+	}
+	if elector.renewInterval <= 0 {
+		elector.renewInterval = elector.ttl / 3
+	}
+	if elector.renewInterval >= elector.ttl {
+		return nil, fmt.Errorf("core: leader renew interval must be shorter than the lease ttl")
+	}
+	return elector, nil
+}
+
+func (e *LeaderElector) HolderID() string {
+	if e == nil {
+		return ""
+	}
+	return e.holderID
+}
+
+// RunAsLeader blocks until this replica holds the named lease and then runs fn
+// with a context carrying the lease. If the lease cannot be renewed before it
+// expires, fn's context is canceled and the replica campaigns again, so a
+// runner whose leader crashed resumes elsewhere once the TTL has passed.
+// RunAsLeader returns fn's result once fn returns while still leading, and nil
+// when ctx is canceled.
+func (e *LeaderElector) RunAsLeader(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return fmt.Errorf("core: leader function is required")
+	}
+	if e == nil || e.store == nil {
+		return fn(ctx)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("core: leader lease name is required")
+	}
+	for {
+		lease, err := e.campaign(ctx, name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		err = e.lead(ctx, lease, fn)
+		if errors.Is(err, ErrLeadershipLost) {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+		return err
+	}
+}
+
+// campaign retries acquisition until it succeeds or ctx is canceled. Store
+// errors are reported and retried, since a follower has nothing else to do.
+func (e *LeaderElector) campaign(ctx context.Context, name string) (LeaderLease, error) {
+	for {
+		lease, acquired, err := e.store.AcquireLeaderLease(ctx, name, e.holderID, e.ttl)
+		if err == nil && acquired {
+			return lease, nil
+		}
+		if err != nil && ctx.Err() == nil {
+			e.reportLeaseError(ctx, name, "acquire", err)
+		}
+		if waitErr := waitWithContext(ctx, e.retryInterval); waitErr != nil {
+			return LeaderLease{}, waitErr
+		}
+	}
+}
+
+func (e *LeaderElector) lead(ctx context.Context, lease LeaderLease, fn func(ctx context.Context) error) error {
+	leaderCtx, cancel := context.WithCancelCause(context.WithValue(ctx, leaderLeaseContextKey{}, lease))
+	renewed := make(chan LeaderLease, 1)
+	go func() {
+		renewed <- e.keepAlive(leaderCtx, lease, cancel)
+	}()
+
+	err := fn(leaderCtx)
+	// fn reports ErrLeadershipLost when a fenced write found the lease taken
+	// over before the renewal loop noticed.
+	lost := errors.Is(context.Cause(leaderCtx), ErrLeadershipLost) || errors.Is(err, ErrLeadershipLost)
+	cancel(nil)
+	lease = <-renewed
+	if lost {
+		return ErrLeadershipLost
+	}
+	// Release even when ctx is canceled so a clean shutdown hands over
+	// immediately instead of after the TTL.
+	releaseCtx := context.WithoutCancel(ctx)
+	if releaseErr := e.store.ReleaseLeaderLease(releaseCtx, lease); releaseErr != nil {
+		e.reportLeaseError(releaseCtx, lease.Name, "release", releaseErr)
+	}
+	return err
+}
+
+// keepAlive renews the lease until leaderCtx ends. Transient renewal errors
+// are retried until the next attempt would land after expiry; leadership is
+// then given up early so two holders never overlap.
+func (e *LeaderElector) keepAlive(ctx context.Context, lease LeaderLease, cancel context.CancelCauseFunc) LeaderLease {
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return lease
+		case <-ticker.C:
+		}
+		next, err := e.store.RenewLeaderLease(ctx, lease, e.ttl)
+		if err == nil {
+			lease = next
+			continue
+		}
+		if ctx.Err() != nil {
+			return lease
+		}
+		e.reportLeaseError(ctx, lease.Name, "renew", err)
+		if errors.Is(err, ErrLeadershipLost) || !e.now().Add(e.renewInterval).Before(lease.ExpiresAt) {
+			cancel(fmt.Errorf("%w: lease %q: %v", ErrLeadershipLost, lease.Name, err))
+			return lease
+		}
+	}
+}
+
+func (e *LeaderElector) reportLeaseError(ctx context.Context, name string, operation string, err error) {
+	if e.metrics != nil {
+		e.metrics.IncCounter(ctx, "services.leader.lease_errors.total", 1, map[string]string{
+			"lease":     name,
+			"operation": operation,
+		})
+	}
+	if e.onError != nil {
+		e.onError(ctx, name, fmt.Errorf("core: %s leader lease %q: %w", operation, name, err))
+	}
+}
+
+// runLeaderTicker calls tick right away and then every interval until ctx is
+// canceled, on the lease holder alone when elector is set. tick reports its
+// own errors; one that wraps ErrLeadershipLost ends the term so the replica
+// campaigns again.
+func runLeaderTicker(
+	ctx context.Context,
+	elector *LeaderElector,
+	name string,
+	interval time.Duration,
+	tick func(ctx context.Context) error,
+) error {
+	return elector.RunAsLeader(ctx, name, func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := tick(ctx); errors.Is(err, ErrLeadershipLost) && ctx.Err() == nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+type leaderLeaseContextKey struct{}
+
+// LeaderLeaseFromContext returns the lease held by the RunAsLeader call that
+// owns ctx. Stores that support fencing read it to reject writes once the
+// lease's fencing token is no longer current.
+func LeaderLeaseFromContext(ctx context.Context) (LeaderLease, bool) {
+	if ctx == nil {
+		return LeaderLease{}, false
+	}
+	lease, ok := ctx.Value(leaderLeaseContextKey{}).(LeaderLease)
+	return lease, ok
+}
+
+func defaultLeaderHolderID() (string, error) {
+	host, _ := os.Hostname()
+	host = strings.TrimSpace(host)
+	if host == "" {
+		host = "replica"
+	}
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("core: generate leader holder id: %w", err)
+	}
+	return host + "-" + hex.EncodeToString(suffix), nil
+}
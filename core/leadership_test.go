@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLeaderElector_HandsOverAfterTTLWithoutOverlap(t *testing.T) {
+	store := newMemoryLeaderLeaseStore()
+	newElector := func(holderID string) *LeaderElector {
+		elector, err := NewLeaderElector(store,
+			WithLeaderHolderID(holderID),
+			WithLeaderLeaseTTL(150*time.Millisecond),
+			WithLeaderRenewInterval(30*time.Millisecond),
+			WithLeaderRetryInterval(10*time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("new elector: %v", err)
+		}
+		return elector
+	}
+
+	var (
+		active  atomic.Int32
+		overlap atomic.Bool
+		tokens  = make(chan string, 8)
+	)
+	lead := func(ctx context.Context) error {
+		if active.Add(1) > 1 {
+			overlap.Store(true)
+		}
+		defer active.Add(-1)
+		lease, ok := LeaderLeaseFromContext(ctx)
+		if !ok {
+			return fmt.Errorf("expected lease in leader context")
+		}
+		tokens <- fmt.Sprintf("%s:%d", lease.HolderID, lease.FencingToken)
+		<-ctx.Done()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results <- newElector("replica_a").RunAsLeader(ctx, "sync_scheduler", lead)
+	}()
+	if got := receiveLeader(t, tokens); got != "replica_a:1" {
+		t.Fatalf("expected replica_a to lead first, got %s", got)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results <- newElector("replica_b").RunAsLeader(ctx, "sync_scheduler", lead)
+	}()
+	select {
+	case got := <-tokens:
+		t.Fatalf("expected replica_b to wait while the lease is renewed, got %s", got)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// replica_a can no longer reach the store: it must step down before its
+	// lease expires, and replica_b takes over with a new fencing token.
+	store.failRenewals("replica_a")
+	if got := receiveLeader(t, tokens); got != "replica_b:2" {
+		t.Fatalf("expected replica_b to take over with token 2, got %s", got)
+	}
+	if overlap.Load() {
+		t.Fatalf("expected leaders never to overlap")
+	}
+
+	cancel()
+	wg.Wait()
+	close(results)
+	for err := range results {
+		if err != nil {
+			t.Fatalf("expected canceled elections to return nil, got %v", err)
+		}
+	}
+}
+
+func TestLeaderElector_ReleasesOnReturnAndRunsWithoutStore(t *testing.T) {
+	store := newMemoryLeaderLeaseStore()
+	elector, err := NewLeaderElector(store, WithLeaderHolderID("replica_a"), WithLeaderLeaseTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+	failure := errors.New("runner failed")
+	if err := elector.RunAsLeader(context.Background(), "retention", func(context.Context) error {
+		return failure
+	}); !errors.Is(err, failure) {
+		t.Fatalf("expected runner error to be returned, got %v", err)
+	}
+	next, acquired, err := store.AcquireLeaderLease(context.Background(), "retention", "replica_b", time.Hour)
+	if err != nil || !acquired || next.FencingToken != 2 {
+		t.Fatalf("expected released lease to hand over immediately, got %+v %v %v", next, acquired, err)
+	}
+
+	var unelected *LeaderElector
+	ran := false
+	if err := unelected.RunAsLeader(context.Background(), "retention", func(ctx context.Context) error {
+		_, held := LeaderLeaseFromContext(ctx)
+		ran = !held
+		return nil
+	}); err != nil || !ran {
+		t.Fatalf("expected nil elector to run without a lease, got %v", err)
+	}
+	if _, err := NewLeaderElector(store, WithLeaderLeaseTTL(time.Second), WithLeaderRenewInterval(time.Second)); err == nil {
+		t.Fatalf("expected renew interval not shorter than ttl to be rejected")
+	}
+}
+
+func TestLeaderElector_ReportsLeaseStoreErrors(t *testing.T) {
+	store := newMemoryLeaderLeaseStore()
+	store.failRenewals("replica_a")
+	metrics := &captureMetricsRecorder{}
+	reported := make(chan error, 8)
+	elector, err := NewLeaderElector(store,
+		WithLeaderHolderID("replica_a"),
+		WithLeaderRetryInterval(5*time.Millisecond),
+		WithLeaderElectorMetrics(metrics),
+		WithLeaderElectorErrorHandler(func(_ context.Context, name string, err error) {
+			if name == "retention" {
+				select {
+				case reported <- err:
+				default:
+				}
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- elector.RunAsLeader(ctx, "retention", func(context.Context) error {
+			return errors.New("expected the campaign to keep failing")
+		})
+	}()
+	for range 2 {
+		select {
+		case err := <-reported:
+			if !strings.Contains(err.Error(), "acquire leader lease") {
+				t.Fatalf("expected an acquire error, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected campaign errors to be reported")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected canceled campaign to return nil, got %v", err)
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.counters) == 0 || metrics.counters[0].tags["operation"] != "acquire" || metrics.counters[0].tags["lease"] != "retention" {
+		t.Fatalf("expected lease errors to be counted, got %+v", metrics.counters)
+	}
+}
+
+func receiveLeader(t *testing.T, tokens <-chan string) string {
+	t.Helper()
+	select {
+	case got := <-tokens:
+		return got
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for a leader")
+		return ""
+	}
+}
+
+type memoryLeaderLeaseStore struct {
+	mu      sync.Mutex
+	leases  map[string]LeaderLease
+	failing map[string]bool
+}
+
+func newMemoryLeaderLeaseStore() *memoryLeaderLeaseStore {
+	return &memoryLeaderLeaseStore{leases: map[string]LeaderLease{}, failing: map[string]bool{}}
+}
+
+func (s *memoryLeaderLeaseStore) failRenewals(holderID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing[holderID] = true
+}
+
+func (s *memoryLeaderLeaseStore) AcquireLeaderLease(_ context.Context, name string, holderID string, ttl time.Duration) (LeaderLease, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing[holderID] {
+		return LeaderLease{}, false, errors.New("store unreachable")
+	}
+	now := time.Now().UTC()
+	current, ok := s.leases[name]
+	if ok && current.ExpiresAt.After(now) {
+		if current.HolderID != holderID {
+			return current, false, nil
+		}
+		current.ExpiresAt = now.Add(ttl)
+		s.leases[name] = current
+		return current, true, nil
+	}
+	next := LeaderLease{
+		Name:         name,
+		HolderID:     holderID,
+		FencingToken: current.FencingToken + 1,
+		AcquiredAt:   now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	s.leases[name] = next
+	return next, true, nil
+}
+
+func (s *memoryLeaderLeaseStore) RenewLeaderLease(_ context.Context, lease LeaderLease, ttl time.Duration) (LeaderLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing[lease.HolderID] {
+		return LeaderLease{}, errors.New("store unreachable")
+	}
+	current := s.leases[lease.Name]
+	now := time.Now().UTC()
+	if current.HolderID != lease.HolderID || current.FencingToken != lease.FencingToken || !current.ExpiresAt.After(now) {
+		return LeaderLease{}, ErrLeadershipLost
+	}
+	current.ExpiresAt = now.Add(ttl)
+	s.leases[lease.Name] = current
+	return current, nil
+}
+
+func (s *memoryLeaderLeaseStore) ReleaseLeaderLease(_ context.Context, lease LeaderLease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.leases[lease.Name]
+	if current.HolderID != lease.HolderID || current.FencingToken != lease.FencingToken {
+		return ErrLeadershipLost
+	}
+	current.ExpiresAt = time.Now().UTC()
+	s.leases[lease.Name] = current
+	return nil
+}
+
+var _ LeaderLeaseStore = (*memoryLeaderLeaseStore)(nil)
@@ -68,6 +68,23 @@ func (r *LifecycleProjectorRegistry) Handlers() []LifecycleEventHandler {
 	return out
 }
 
+func (r *LifecycleProjectorRegistry) NamedHandlers() []NamedLifecycleEventHandler {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]NamedLifecycleEventHandler, 0, len(r.order))
+	for _, key := range r.order {
+		handler := r.handlers[key]
+		if handler != nil {
+			out = append(out, NamedLifecycleEventHandler{Name: key, Handler: handler})
+		}
+	}
+	return out
+}
+
 type LifecycleActivityProjector struct {
 	sink     ServicesActivitySink
 	enricher ServicesActivityEnricher
@@ -97,8 +97,40 @@ func (c *MappingCompiler) CompileMappingSpec(
 		sourcePath := normalizePath(rule.SourcePath)
 		targetPath := normalizePath(rule.TargetPath)
 
+		parsedSource, sourcePathErr := parseMappingPath(rule.SourcePath)
+		parsedTarget, targetPathErr := parseMappingPath(rule.TargetPath)
+		var rulePaths *mappingRulePaths
+		if sourcePathErr == nil && targetPathErr == nil {
+			rulePaths = &mappingRulePaths{source: parsedSource, target: parsedTarget}
+		}
+		if sourcePathErr != nil {
+			issues = append(issues, mappingIssue(
+				"source_path_invalid",
+				sourcePathErr.Error(),
+				rule.ID,
+				rule.SourcePath,
+				rule.TargetPath,
+				MappingValidationIssueError,
+			))
+		} else {
+			sourcePath = parsedSource.schemaPath()
+		}
+		if targetPathErr != nil {
+			issues = append(issues, mappingIssue(
+				"target_path_invalid",
+				targetPathErr.Error(),
+				rule.ID,
+				rule.SourcePath,
+				rule.TargetPath,
+				MappingValidationIssueError,
+			))
+		}
+
 		sourceField, sourceFound := fieldByPath[sourcePath]
-		if !sourceFound {
+		switch {
+		case sourcePathErr != nil:
+			// Reported as source_path_invalid above.
+		case !sourceFound:
 			issues = append(issues, mappingIssue(
 				"source_field_not_found",
 				fmt.Sprintf("core: source field %q not found in object %q", rule.SourcePath, sourceObject.Name),
@@ -107,11 +139,63 @@ func (c *MappingCompiler) CompileMappingSpec(
 				rule.TargetPath,
 				MappingValidationIssueError,
 			))
-		} else if sourceField.Required {
+		case sourceField.Required:
 			mappedRequiredFields[sourcePath] = struct{}{}
 		}
 
 		transform := normalizeTransform(rule.Transform)
+		targetType := resolveTargetType(rule)
+		sourceType := canonicalFieldType(sourceField.Type)
+		if rulePaths != nil {
+			_, isExpression := mappingExpressionSource(transform)
+			issues = append(issues, validateMappingCardinality(rule, *rulePaths, fieldByPath, isExpression)...)
+		}
+		if expressionSource, isExpression := mappingExpressionSource(transform); isExpression {
+			var fieldRef *ExternalField
+			if sourceFound {
+				fieldRef = &sourceField
+			}
+			expression, expressionErr := compileMappingExpression(expressionSource, &sourceObject, fieldRef)
+			switch {
+			case expressionErr != nil:
+				issues = append(issues, mappingIssue(
+					"transform_expression_invalid",
+					expressionErr.Error(),
+					rule.ID,
+					rule.SourcePath,
+					rule.TargetPath,
+					MappingValidationIssueError,
+				))
+			case !expression.outputCompatible(targetType):
+				issues = append(issues, mappingIssue(
+					"type_incompatible",
+					fmt.Sprintf(
+						"core: expression result type %q is not compatible with target type %q",
+						expression.outputType.String(),
+						targetType,
+					),
+					rule.ID,
+					rule.SourcePath,
+					rule.TargetPath,
+					MappingValidationIssueError,
+				))
+			}
+			if existingRuleID, duplicate := targetPathToRuleID[targetPath]; duplicate {
+				issues = append(issues, duplicateTargetPathIssue(rule, existingRuleID))
+			} else if targetPath != "" {
+				targetPathToRuleID[targetPath] = rule.ID
+			}
+			compiledRules = append(compiledRules, CompiledMappingRule{
+				Rule:       rule,
+				SourceType: sourceType,
+				TargetType: targetType,
+				Transform:  transform,
+				expression: expression,
+				paths:      rulePaths,
+			})
+			continue
+		}
+
 		transformSupported := isSupportedMappingTransform(transform)
 		if !transformSupported {
 			issues = append(issues, mappingIssue(
@@ -124,8 +208,6 @@ func (c *MappingCompiler) CompileMappingSpec(
 			))
 		}
 
-		targetType := resolveTargetType(rule)
-		sourceType := canonicalFieldType(sourceField.Type)
 		if transformSupported &&
 			sourceFound &&
 			targetType != "" &&
@@ -146,14 +228,7 @@ func (c *MappingCompiler) CompileMappingSpec(
 		}
 
 		if existingRuleID, duplicate := targetPathToRuleID[targetPath]; duplicate {
-			issues = append(issues, mappingIssue(
-				"target_path_duplicate",
-				fmt.Sprintf("core: duplicate target path %q for rules %q and %q", rule.TargetPath, existingRuleID, rule.ID),
-				rule.ID,
-				rule.SourcePath,
-				rule.TargetPath,
-				MappingValidationIssueError,
-			))
+			issues = append(issues, duplicateTargetPathIssue(rule, existingRuleID))
 		} else if targetPath != "" {
 			targetPathToRuleID[targetPath] = rule.ID
 		}
@@ -163,6 +238,7 @@ func (c *MappingCompiler) CompileMappingSpec(
 			SourceType: sourceType,
 			TargetType: targetType,
 			Transform:  transform,
+			paths:      rulePaths,
 		})
 	}
 
@@ -185,17 +261,7 @@ func (c *MappingCompiler) CompileMappingSpec(
 		))
 	}
 
-	sort.SliceStable(compiledRules, func(i, j int) bool {
-		left := compiledRules[i]
-		right := compiledRules[j]
-		if left.Rule.TargetPath != right.Rule.TargetPath {
-			return left.Rule.TargetPath < right.Rule.TargetPath
-		}
-		if left.Rule.SourcePath != right.Rule.SourcePath {
-			return left.Rule.SourcePath < right.Rule.SourcePath
-		}
-		return left.Rule.ID < right.Rule.ID
-	})
+	sortCompiledMappingRules(compiledRules)
 
 	compiled := CompiledMappingSpec{
 		SpecID:       spec.SpecID,
@@ -214,6 +280,91 @@ func (c *MappingCompiler) CompileMappingSpec(
 	return compiled, issues, nil
 }
 
+func duplicateTargetPathIssue(rule MappingRule, existingRuleID string) MappingValidationIssue {
+	return mappingIssue(
+		"target_path_duplicate",
+		fmt.Sprintf("core: duplicate target path %q for rules %q and %q", rule.TargetPath, existingRuleID, rule.ID),
+		rule.ID,
+		rule.SourcePath,
+		rule.TargetPath,
+		MappingValidationIssueError,
+	)
+}
+
+// validateMappingCardinality checks collection selectors against the
+// repeatable fields of the schema. Selectors must step into repeatable
+// fields, repeatable fields may only be crossed with a selector, and the
+// target may only select one more collection than the source when the
+// source value is a list to explode.
+func validateMappingCardinality(
+	rule MappingRule,
+	paths mappingRulePaths,
+	fieldByPath map[string]ExternalField,
+	isExpression bool,
+) []MappingValidationIssue {
+	var issues []MappingValidationIssue
+	cardinalityIssue := func(message string) {
+		issues = append(issues, mappingIssue(
+			"cardinality_mismatch",
+			message,
+			rule.ID,
+			rule.SourcePath,
+			rule.TargetPath,
+			MappingValidationIssueError,
+		))
+	}
+
+	source := paths.source
+	prefix := ""
+	leafRepeatable := false
+	for index, segment := range source.segments {
+		prefix = joinMappingPath(prefix, segment.key)
+		field, declared := fieldByPath[prefix]
+		if !declared {
+			continue
+		}
+		last := index == len(source.segments)-1
+		switch {
+		case segment.selector != mappingPathSelectNone && !field.Repeatable:
+			cardinalityIssue(fmt.Sprintf("core: source path %q selects elements of %q which is not repeatable", rule.SourcePath, prefix))
+		case segment.selector == mappingPathSelectNone && field.Repeatable && !last:
+			cardinalityIssue(fmt.Sprintf("core: source path %q crosses repeatable field %q without a selector", rule.SourcePath, prefix))
+		case segment.selector == mappingPathSelectNone && field.Repeatable:
+			leafRepeatable = true
+		}
+	}
+
+	for _, segment := range paths.target.segments {
+		if segment.selector == mappingPathSelectIndex || segment.selector == mappingPathSelectFilter {
+			issues = append(issues, mappingIssue(
+				"target_path_invalid",
+				fmt.Sprintf("core: target path %q may only select elements with []", rule.TargetPath),
+				rule.ID,
+				rule.SourcePath,
+				rule.TargetPath,
+				MappingValidationIssueError,
+			))
+			return issues
+		}
+	}
+
+	sourceDepth := source.depth()
+	targetDepth := paths.target.depth()
+	switch {
+	case targetDepth > sourceDepth+1:
+		cardinalityIssue(fmt.Sprintf(
+			"core: target path %q selects %d collections but source path %q selects %d",
+			rule.TargetPath, targetDepth, rule.SourcePath, sourceDepth,
+		))
+	case targetDepth == sourceDepth+1 && !leafRepeatable && !isExpression:
+		cardinalityIssue(fmt.Sprintf(
+			"core: target path %q explodes a list but source field %q is not repeatable",
+			rule.TargetPath, source.schemaPath(),
+		))
+	}
+	return issues
+}
+
 func containsMappingErrors(issues []MappingValidationIssue) bool {
 	for _, issue := range issues {
 		if issue.Severity == MappingValidationIssueError {
@@ -279,7 +430,7 @@ func buildSourceFieldIndexes(
 	fields := make(map[string]ExternalField, len(object.Fields))
 	required := make(map[string]ExternalField)
 	for _, field := range object.Fields {
-		path := normalizePath(field.Path)
+		path := mappingSchemaPath(field.Path)
 		fields[path] = field
 		if field.Required {
 			required[path] = field
@@ -325,7 +476,20 @@ func normalizePath(path string) string {
 	return strings.TrimSpace(path)
 }
 
+// mappingSchemaPath normalizes a declared field path, accepting both
+// `items.sku` and `items[].sku` for fields inside repeatable objects.
+func mappingSchemaPath(path string) string {
+	parsed, err := parseMappingPath(path)
+	if err != nil {
+		return normalizePath(path)
+	}
+	return parsed.schemaPath()
+}
+
 func normalizeTransform(transform string) string {
+	if expression, ok := mappingExpressionSource(transform); ok {
+		return MappingExpressionPrefix + " " + expression
+	}
 	candidate := strings.TrimSpace(strings.ToLower(transform))
 	if candidate == "" {
 		return "identity"
@@ -379,7 +543,8 @@ func isSupportedMappingTransform(transform string) bool {
 		"trim",
 		"lowercase",
 		"uppercase",
-		"unix_time_to_rfc3339":
+		"unix_time_to_rfc3339",
+		"rfc3339_to_unix_time":
 		return true
 	default:
 		return false
@@ -412,6 +577,9 @@ func isMappingTypeCompatible(sourceType, targetType, transform string) bool {
 	case "unix_time_to_rfc3339":
 		return targetType == "string" &&
 			(sourceType == "integer" || sourceType == "number" || sourceType == "string")
+	case "rfc3339_to_unix_time":
+		return (targetType == "integer" || targetType == "number") &&
+			(sourceType == "string" || sourceType == "datetime")
 	default:
 		return false
 	}
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+)
+
+const (
+	// MappingExpressionPrefix marks a MappingRule.Transform as a CEL
+	// expression, e.g. `cel: record.first_name + " " + record.last_name`.
+	MappingExpressionPrefix = "cel:"
+
+	// MappingExpressionCostLimit bounds the evaluation cost of one expression.
+	MappingExpressionCostLimit uint64 = 10_000
+
+	mappingRecordTypeName = "services.mapping.Record"
+)
+
+// mappingExpression is a checked CEL program for one mapping rule. Programs
+// are safe for concurrent use.
+type mappingExpression struct {
+	source     string
+	program    cel.Program
+	outputType *cel.Type
+}
+
+// mappingExpressionSource returns the expression text when the transform is
+// a CEL expression.
+func mappingExpressionSource(transform string) (string, bool) {
+	trimmed := strings.TrimSpace(transform)
+	if len(trimmed) < len(MappingExpressionPrefix) ||
+		!strings.EqualFold(trimmed[:len(MappingExpressionPrefix)], MappingExpressionPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(trimmed[len(MappingExpressionPrefix):]), true
+}
+
+// compileMappingExpression checks the expression against the source object
+// when one is given. Without a schema `record` and `value` are dynamic, which
+// is how published specs are compiled for execution.
+//
+// Expressions see `record`, the whole source record, and `value`, the value
+// at the rule's source path or null when it is missing. Schema numbers are
+// doubles in expressions because decoded JSON payloads carry float64.
+func compileMappingExpression(
+	source string,
+	object *ExternalObjectSchema,
+	sourceField *ExternalField,
+) (*mappingExpression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("core: mapping expression is empty")
+	}
+	recordType := cel.MapType(cel.StringType, cel.DynType)
+	valueType := cel.DynType
+	opts := []cel.EnvOption{}
+	if object != nil {
+		provider, err := newMappingRecordTypes(*object)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, cel.CustomTypeProvider(provider), cel.CustomTypeAdapter(provider))
+		recordType = cel.ObjectType(mappingRecordTypeName)
+		if sourceField != nil {
+			valueType = provider.fieldType(*sourceField)
+		}
+	}
+	opts = append(opts,
+		cel.Variable("record", recordType),
+		cel.Variable("value", valueType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+		ext.Regex(),
+		ext.Lists(),
+		ext.Math(),
+		mappingTimeFunctions(),
+	)
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("core: build mapping expression environment: %w", err)
+	}
+	checked, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("core: invalid mapping expression: %w", issues.Err())
+	}
+	program, err := env.Program(checked, cel.CostLimit(MappingExpressionCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("core: invalid mapping expression: %w", err)
+	}
+	return &mappingExpression{
+		source:     source,
+		program:    program,
+		outputType: checked.OutputType(),
+	}, nil
+}
+
+func (e *mappingExpression) evaluate(record map[string]any, value any) (any, error) {
+	if e == nil || e.program == nil {
+		return nil, fmt.Errorf("core: mapping expression is not compiled")
+	}
+	out, _, err := e.program.Eval(map[string]any{
+		"record": normalizeExpressionInput(record),
+		"value":  normalizeExpressionInput(value),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("core: evaluate mapping expression: %w", err)
+	}
+	return celValueToNative(out)
+}
+
+// outputCompatible reports whether the checked result type can populate the
+// target type. Dynamic results are checked at evaluation time instead.
+func (e *mappingExpression) outputCompatible(targetType string) bool {
+	targetType = canonicalFieldType(targetType)
+	if e == nil || e.outputType == nil || targetType == "" {
+		return true
+	}
+	switch e.outputType.Kind() {
+	case types.DynKind, types.AnyKind, types.NullTypeKind:
+		return true
+	case types.StringKind:
+		return targetType == "string" || targetType == "datetime"
+	case types.TimestampKind:
+		return targetType == "datetime" || targetType == "string"
+	case types.IntKind, types.UintKind:
+		return targetType == "integer" || targetType == "number"
+	case types.DoubleKind:
+		return targetType == "number"
+	case types.BoolKind:
+		return targetType == "boolean"
+	case types.ListKind:
+		return targetType == "array" || targetType == "list"
+	case types.MapKind, types.StructKind:
+		return targetType == "object"
+	default:
+		return false
+	}
+}
+
+// mappingRecordTypes exposes the fields of an external object schema as CEL
+// object types so expressions are type-checked against the schema. Nested
+// paths become nested object types, and lists of them below repeatable
+// fields.
+type mappingRecordTypes struct {
+	*types.Registry
+	fields map[string]map[string]*types.Type
+}
+
+func newMappingRecordTypes(object ExternalObjectSchema) (*mappingRecordTypes, error) {
+	registry, err := types.NewRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("core: build mapping expression types: %w", err)
+	}
+	provider := &mappingRecordTypes{
+		Registry: registry,
+		fields:   map[string]map[string]*types.Type{mappingRecordTypeName: {}},
+	}
+	fields := append([]ExternalField(nil), object.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return mappingSchemaPath(fields[i].Path) < mappingSchemaPath(fields[j].Path)
+	})
+	repeatable := make(map[string]bool)
+	for _, field := range fields {
+		if field.Repeatable {
+			repeatable[mappingSchemaPath(field.Path)] = true
+		}
+	}
+	for _, field := range fields {
+		segments := strings.Split(mappingSchemaPath(field.Path), ".")
+		typeName := mappingRecordTypeName
+		for index, segment := range segments {
+			if segment == "" {
+				break
+			}
+			if index == len(segments)-1 {
+				if _, nested := provider.fields[typeName+"."+segment]; !nested {
+					provider.fields[typeName][segment] = provider.fieldType(field)
+				}
+				break
+			}
+			nestedName := typeName + "." + segment
+			if _, ok := provider.fields[nestedName]; !ok {
+				provider.fields[nestedName] = map[string]*types.Type{}
+			}
+			nestedType := types.NewObjectType(nestedName)
+			if repeatable[strings.Join(segments[:index+1], ".")] {
+				provider.fields[typeName][segment] = types.NewListType(nestedType)
+			} else {
+				provider.fields[typeName][segment] = nestedType
+			}
+			typeName = nestedName
+		}
+	}
+	return provider, nil
+}
+
+func (p *mappingRecordTypes) fieldType(field ExternalField) *types.Type {
+	var fieldType *types.Type
+	switch canonicalFieldType(field.Type) {
+	case "string", "datetime":
+		fieldType = types.StringType
+	case "integer", "number":
+		fieldType = types.DoubleType
+	case "boolean":
+		fieldType = types.BoolType
+	default:
+		fieldType = types.DynType
+	}
+	if field.Repeatable {
+		return types.NewListType(fieldType)
+	}
+	return fieldType
+}
+
+func (p *mappingRecordTypes) FindStructType(structType string) (*types.Type, bool) {
+	if _, ok := p.fields[structType]; ok {
+		return types.NewTypeTypeWithParam(types.NewObjectType(structType)), true
+	}
+	return p.Registry.FindStructType(structType)
+}
+
+func (p *mappingRecordTypes) FindStructFieldNames(structType string) ([]string, bool) {
+	fields, ok := p.fields[structType]
+	if !ok {
+		return p.Registry.FindStructFieldNames(structType)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+// FindStructFieldType returns only the field type; without field accessors
+// the runtime selects fields from the record map as usual.
+func (p *mappingRecordTypes) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	fields, ok := p.fields[structType]
+	if !ok {
+		return p.Registry.FindStructFieldType(structType, fieldName)
+	}
+	fieldType, ok := fields[fieldName]
+	if !ok {
+		return nil, false
+	}
+	return &types.FieldType{Type: fieldType}, true
+}
+
+// mappingTimeFunctions adds Go layout based time parsing and formatting.
+func mappingTimeFunctions() cel.EnvOption {
+	return cel.Lib(mappingTimeLibrary{})
+}
+
+type mappingTimeLibrary struct{}
+
+func (mappingTimeLibrary) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("parse_time",
+			cel.Overload("parse_time_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.TimestampType,
+				cel.BinaryBinding(func(value, layout ref.Val) ref.Val {
+					parsed, err := time.Parse(string(layout.(types.String)), string(value.(types.String)))
+					if err != nil {
+						return types.NewErr("parse_time: %v", err)
+					}
+					return types.Timestamp{Time: parsed.UTC()}
+				}),
+			),
+		),
+		cel.Function("format_time",
+			cel.Overload("format_time_timestamp_string",
+				[]*cel.Type{cel.TimestampType, cel.StringType},
+				cel.StringType,
+				cel.BinaryBinding(func(value, layout ref.Val) ref.Val {
+					return types.String(value.(types.Timestamp).Time.UTC().Format(string(layout.(types.String))))
+				}),
+			),
+		),
+	}
+}
+
+func (mappingTimeLibrary) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// normalizeExpressionInput converts integers to float64 so values decoded
+// from JSON and values built in Go behave the same in expressions.
+func normalizeExpressionInput(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, item := range typed {
+			out[key] = normalizeExpressionInput(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for index, item := range typed {
+			out[index] = normalizeExpressionInput(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(typed))
+		for index, item := range typed {
+			out[index] = normalizeExpressionInput(item)
+		}
+		return out
+	case json.Number:
+		if number, err := typed.Float64(); err == nil {
+			return number
+		}
+		return typed.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		number, _ := toFloatValue(typed)
+		return number
+	default:
+		return value
+	}
+}
+
+func celValueToNative(value ref.Val) (any, error) {
+	switch typed := value.(type) {
+	case types.Null:
+		return nil, nil
+	case types.String:
+		return string(typed), nil
+	case types.Bool:
+		return bool(typed), nil
+	case types.Int:
+		return int64(typed), nil
+	case types.Uint:
+		return uint64(typed), nil
+	case types.Double:
+		return float64(typed), nil
+	case types.Timestamp:
+		return typed.Time.UTC().Format(time.RFC3339Nano), nil
+	case types.Duration:
+		return typed.Duration.String(), nil
+	case *types.Err:
+		return nil, typed
+	case traits.Mapper:
+		out := make(map[string]any)
+		iterator := typed.Iterator()
+		for iterator.HasNext() == types.True {
+			key := iterator.Next()
+			item, err := celValueToNative(typed.Get(key))
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(key.Value())] = item
+		}
+		return out, nil
+	case traits.Lister:
+		out := make([]any, 0)
+		iterator := typed.Iterator()
+		for iterator.HasNext() == types.True {
+			item, err := celValueToNative(iterator.Next())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	default:
+		return typed.Value(), nil
+	}
+}
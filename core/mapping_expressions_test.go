@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func expressionTestSchema() ExternalSchema {
+	return ExternalSchema{
+		ProviderID: "hubspot",
+		Scope:      ScopeRef{Type: "org", ID: "org_123"},
+		Name:       "contacts_schema",
+		Objects: []ExternalObjectSchema{
+			{
+				Name: "contacts",
+				Fields: []ExternalField{
+					{Path: "first_name", Type: "string"},
+					{Path: "last_name", Type: "string"},
+					{Path: "properties.age", Type: "integer"},
+					{Path: "properties.signup", Type: "string"},
+					{Path: "properties.phone", Type: "string"},
+				},
+			},
+		},
+	}
+}
+
+func expressionTestSpec(rules ...MappingRule) MappingSpec {
+	return MappingSpec{
+		SpecID:       "spec_contacts",
+		ProviderID:   "hubspot",
+		Scope:        ScopeRef{Type: "org", ID: "org_123"},
+		Name:         "contacts",
+		SourceObject: "contacts",
+		TargetModel:  "crm_contacts",
+		Version:      1,
+		Status:       MappingSpecStatusDraft,
+		Rules:        rules,
+	}
+}
+
+func TestMappingCompilerTypeChecksExpressionsAgainstSchema(t *testing.T) {
+	compiler := NewMappingCompiler()
+	result, err := compiler.ValidateMappingSpec(context.Background(), ValidateMappingSpecRequest{
+		Spec: expressionTestSpec(
+			MappingRule{
+				ID:         "rule_name",
+				SourcePath: "first_name",
+				TargetPath: "name",
+				Transform:  `CEL: record.first_name + " " + record.last_name`,
+			},
+			MappingRule{
+				ID:         "rule_unknown_field",
+				SourcePath: "first_name",
+				TargetPath: "nickname",
+				Transform:  "cel: record.nickname",
+			},
+			MappingRule{
+				ID:          "rule_wrong_type",
+				SourcePath:  "properties.age",
+				TargetPath:  "is_adult",
+				Transform:   "cel: value + 1.0",
+				Constraints: map[string]any{"target_type": "boolean"},
+			},
+			MappingRule{
+				ID:         "rule_syntax",
+				SourcePath: "last_name",
+				TargetPath: "broken",
+				Transform:  "cel: record.last_name +",
+			},
+		),
+		Schema: expressionTestSchema(),
+	})
+	if err != nil {
+		t.Fatalf("validate mapping spec: %v", err)
+	}
+	if result.Valid {
+		t.Fatalf("expected invalid result")
+	}
+
+	byRule := map[string]string{}
+	for _, issue := range result.Issues {
+		byRule[issue.RuleID] = issue.Code
+	}
+	if _, ok := byRule["rule_name"]; ok {
+		t.Fatalf("expected concatenation expression to type-check, got %#v", result.Issues)
+	}
+	if byRule["rule_unknown_field"] != "transform_expression_invalid" ||
+		byRule["rule_syntax"] != "transform_expression_invalid" ||
+		byRule["rule_wrong_type"] != "type_incompatible" {
+		t.Fatalf("unexpected expression issues %#v", result.Issues)
+	}
+	for _, rule := range result.NormalizedSpec.Rules {
+		if rule.ID == "rule_name" && !strings.Contains(rule.Transform, "record.first_name") {
+			t.Fatalf("expected expression case to be preserved, got %q", rule.Transform)
+		}
+	}
+}
+
+func TestMappingPreviewerEvaluatesExpressions(t *testing.T) {
+	previewer := NewMappingPreviewer(NewMappingCompiler())
+	result, err := previewer.PreviewMappingSpec(context.Background(), PreviewMappingSpecRequest{
+		Spec: expressionTestSpec(
+			MappingRule{
+				ID:         "rule_name",
+				SourcePath: "first_name",
+				TargetPath: "name",
+				Transform:  `cel: record.first_name + " " + record.last_name`,
+			},
+			MappingRule{
+				ID:         "rule_band",
+				SourcePath: "properties.age",
+				TargetPath: "age_band",
+				Transform:  `cel: value >= 18.0 ? "adult" : "minor"`,
+			},
+			MappingRule{
+				ID:         "rule_signup",
+				SourcePath: "properties.signup",
+				TargetPath: "signed_up_at",
+				Transform:  `cel: parse_time(value, "02/01/2006")`,
+			},
+			MappingRule{
+				ID:         "rule_area_code",
+				SourcePath: "properties.phone",
+				TargetPath: "area_code",
+				Transform:  `cel: has(record.properties.phone) ? regex.extract(value, "^\\((\\d{3})\\)").orValue("") : "000"`,
+			},
+		),
+		Schema: expressionTestSchema(),
+		Samples: []map[string]any{
+			{
+				"first_name": "Ada",
+				"last_name":  "Lovelace",
+				"properties": map[string]any{"age": 36, "signup": "10/12/2025", "phone": "(555) 010-2000"},
+			},
+			{
+				"first_name": "Tim",
+				"last_name":  "Berners",
+				"properties": map[string]any{"age": 12.0, "signup": "not a date"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("preview mapping spec: %v", err)
+	}
+	first := result.Records[0].Output
+	if first["name"] != "Ada Lovelace" || first["age_band"] != "adult" || first["area_code"] != "555" {
+		t.Fatalf("unexpected expression output %#v", first)
+	}
+	if first["signed_up_at"] != "2025-12-10T00:00:00Z" {
+		t.Fatalf("expected parsed signup time, got %#v", first["signed_up_at"])
+	}
+
+	second := result.Records[1]
+	if second.Output["age_band"] != "minor" || second.Output["area_code"] != "000" {
+		t.Fatalf("expected defaults computed from other fields, got %#v", second.Output)
+	}
+	failed := false
+	for _, issue := range second.Issues {
+		if issue.RuleID == "rule_signup" && issue.Code == "preview_transform_failed" {
+			failed = true
+		}
+	}
+	if !failed {
+		t.Fatalf("expected signup parse failure issue, got %#v", second.Issues)
+	}
+}
+
+func TestMappingExpressionEvaluationIsCostLimited(t *testing.T) {
+	expression, err := compileMappingExpression(
+		"[1,2,3,4,5,6,7,8,9,10].all(a, [1,2,3,4,5,6,7,8,9,10].all(b, [1,2,3,4,5,6,7,8,9,10].all(c, "+
+			"[1,2,3,4,5,6,7,8,9,10].all(d, a + b + c + d > 0))))",
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("compile expression: %v", err)
+	}
+	if _, err := expression.evaluate(map[string]any{}, nil); err == nil ||
+		!strings.Contains(err.Error(), "cost limit") {
+		t.Fatalf("expected cost limit error, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// MappingConstraintSourceType states the source field type for published
+	// specs, which compile for execution without the external schema.
+	MappingConstraintSourceType = "source_type"
+	// MappingConstraintSourceCase states the canonical casing of the source
+	// field ("lower", "upper" or "preserve") so casing transforms can be
+	// inverted.
+	MappingConstraintSourceCase = "source_case"
+	// MappingConstraintOneWay leaves a rule out of the inverse spec without
+	// reporting it, e.g. for fields computed on import.
+	MappingConstraintOneWay = "one_way"
+)
+
+// CompileInverseMappingSpec compiles the spec and derives its inverse, which
+// maps target model records back to the source object. Rules that cannot be
+// inverted are reported as rule_not_invertible issues and left out.
+func (c *MappingCompiler) CompileInverseMappingSpec(
+	ctx context.Context,
+	req ValidateMappingSpecRequest,
+) (CompiledMappingSpec, []MappingValidationIssue, error) {
+	compiled, issues, err := c.CompileMappingSpec(ctx, req)
+	if err != nil {
+		return CompiledMappingSpec{}, nil, err
+	}
+	inverse, inverseIssues, err := invertCompiledMappingSpec(compiled)
+	if err != nil {
+		return CompiledMappingSpec{}, nil, err
+	}
+	issues = append(issues, inverseIssues...)
+	sortMappingValidationIssues(issues)
+	return inverse, issues, nil
+}
+
+// invertCompiledMappingSpec swaps the source and target of every rule and
+// replaces each transform with its inverse.
+func invertCompiledMappingSpec(compiled CompiledMappingSpec) (CompiledMappingSpec, []MappingValidationIssue, error) {
+	var issues []MappingValidationIssue
+	notInvertible := func(rule MappingRule, reason string) {
+		issues = append(issues, mappingIssue(
+			"rule_not_invertible",
+			fmt.Sprintf("core: rule %q cannot be inverted: %s", rule.ID, reason),
+			rule.ID,
+			rule.SourcePath,
+			rule.TargetPath,
+			MappingValidationIssueError,
+		))
+	}
+
+	inverseTargets := make(map[string]string)
+	rules := make([]CompiledMappingRule, 0, len(compiled.Rules))
+	for _, compiledRule := range compiled.Rules {
+		rule := compiledRule.Rule
+		if oneWay, _ := rule.Constraints[MappingConstraintOneWay].(bool); oneWay {
+			continue
+		}
+		source, target, err := compiledRulePaths(compiledRule)
+		if err != nil {
+			notInvertible(rule, err.Error())
+			continue
+		}
+		if reason := mappingPathsInvertible(source, target); reason != "" {
+			notInvertible(rule, reason)
+			continue
+		}
+		transform, reason := inverseMappingTransform(compiledRule)
+		if reason != "" {
+			notInvertible(rule, reason)
+			continue
+		}
+		if existingRuleID, duplicate := inverseTargets[rule.SourcePath]; duplicate {
+			notInvertible(rule, fmt.Sprintf("source path %q is also mapped by rule %q", rule.SourcePath, existingRuleID))
+			continue
+		}
+		inverseTargets[rule.SourcePath] = rule.ID
+
+		sourceType := compiledRule.SourceType
+		if sourceType == "" {
+			sourceType = resolveSourceType(rule)
+		}
+		constraints := copyMetadata(rule.Constraints)
+		for _, key := range []string{
+			"target_type", "targetType", "type",
+			MappingConstraintSourceType, "sourceType",
+			MappingConstraintSourceCase,
+		} {
+			delete(constraints, key)
+		}
+		if sourceType != "" {
+			constraints["target_type"] = sourceType
+		}
+		if compiledRule.TargetType != "" {
+			constraints[MappingConstraintSourceType] = compiledRule.TargetType
+		}
+		rules = append(rules, CompiledMappingRule{
+			Rule: MappingRule{
+				ID:          rule.ID,
+				SourcePath:  rule.TargetPath,
+				TargetPath:  rule.SourcePath,
+				Transform:   transform,
+				Required:    rule.Required,
+				Default:     rule.Default,
+				Constraints: constraints,
+				Metadata:    copyMetadata(rule.Metadata),
+			},
+			SourceType: compiledRule.TargetType,
+			TargetType: sourceType,
+			Transform:  transform,
+			paths:      &mappingRulePaths{source: target, target: source},
+		})
+	}
+	sortCompiledMappingRules(rules)
+
+	inverse := CompiledMappingSpec{
+		SpecID:       compiled.SpecID,
+		Version:      compiled.Version,
+		SourceObject: compiled.SourceObject,
+		Inverse:      !compiled.Inverse,
+		Rules:        rules,
+	}
+	hash, err := mappingCompiledHash(inverse)
+	if err != nil {
+		return CompiledMappingSpec{}, nil, err
+	}
+	inverse.DeterministicHash = hash
+	sortMappingValidationIssues(issues)
+	return inverse, issues, nil
+}
+
+// mappingPathsInvertible rejects paths that lose information: selecting by
+// index or filter, and flattening collections.
+func mappingPathsInvertible(source, target mappingPath) string {
+	for _, segment := range source.segments {
+		if segment.selector == mappingPathSelectIndex || segment.selector == mappingPathSelectFilter {
+			return fmt.Sprintf("source path %q selects elements by index or filter", source.raw)
+		}
+	}
+	if target.depth() < source.depth() {
+		return fmt.Sprintf("flattened collections in %q cannot be split back", target.raw)
+	}
+	return ""
+}
+
+// inverseMappingTransform returns the transform that undoes the rule's
+// transform, or the reason it cannot be undone. Conversions take their
+// inverse from the source type, so they need one; conversions and trims that
+// drop information (a fraction, surrounding whitespace) have no inverse.
+func inverseMappingTransform(rule CompiledMappingRule) (string, string) {
+	sourceType := canonicalFieldType(rule.SourceType)
+	if sourceType == "" {
+		sourceType = resolveSourceType(rule.Rule)
+	}
+	unsupportedSource := func() (string, string) {
+		if sourceType == "" {
+			return "", fmt.Sprintf("conversion %q needs a %s constraint", rule.Transform, MappingConstraintSourceType)
+		}
+		return "", fmt.Sprintf("transform %q from source type %q has no inverse", rule.Transform, sourceType)
+	}
+	switch rule.Transform {
+	case "identity":
+		return "identity", ""
+	case "trim":
+		return "", "trim drops surrounding whitespace"
+	case "to_string":
+		switch sourceType {
+		case "integer":
+			return "to_int", ""
+		case "number":
+			return "to_float", ""
+		case "boolean":
+			return "to_bool", ""
+		case "string", "datetime":
+			return "identity", ""
+		}
+		return unsupportedSource()
+	case "to_int":
+		switch sourceType {
+		case "string":
+			return "to_string", ""
+		case "integer":
+			return "identity", ""
+		case "number":
+			return "", "to_int truncates the fraction of a number"
+		case "boolean":
+			return "to_bool", ""
+		}
+		return unsupportedSource()
+	case "to_float":
+		switch sourceType {
+		case "string":
+			return "to_string", ""
+		case "number":
+			return "identity", ""
+		case "integer":
+			return "to_int", ""
+		}
+		return unsupportedSource()
+	case "to_bool":
+		switch sourceType {
+		case "string":
+			return "to_string", ""
+		case "boolean":
+			return "identity", ""
+		}
+		return unsupportedSource()
+	case "lowercase", "uppercase":
+		sourceCase, _ := rule.Rule.Constraints[MappingConstraintSourceCase].(string)
+		switch strings.TrimSpace(strings.ToLower(sourceCase)) {
+		case "lower":
+			return "lowercase", ""
+		case "upper":
+			return "uppercase", ""
+		case "preserve":
+			return "identity", ""
+		case "":
+			return "", fmt.Sprintf("casing transform %q needs a %s constraint", rule.Transform, MappingConstraintSourceCase)
+		}
+		return "", fmt.Sprintf("unknown %s %q", MappingConstraintSourceCase, sourceCase)
+	case "unix_time_to_rfc3339":
+		return "rfc3339_to_unix_time", ""
+	case "rfc3339_to_unix_time":
+		return "unix_time_to_rfc3339", ""
+	}
+	if _, isExpression := mappingExpressionSource(rule.Transform); isExpression {
+		return "", "expressions have no inverse"
+	}
+	return "", fmt.Sprintf("transform %q has no inverse", rule.Transform)
+}
+
+func resolveSourceType(rule MappingRule) string {
+	for _, key := range []string{MappingConstraintSourceType, "sourceType"} {
+		if text, ok := rule.Constraints[key].(string); ok {
+			return canonicalFieldType(text)
+		}
+	}
+	return ""
+}
+
+func sortCompiledMappingRules(rules []CompiledMappingRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		left := rules[i]
+		right := rules[j]
+		if left.Rule.TargetPath != right.Rule.TargetPath {
+			return left.Rule.TargetPath < right.Rule.TargetPath
+		}
+		if left.Rule.SourcePath != right.Rule.SourcePath {
+			return left.Rule.SourcePath < right.Rule.SourcePath
+		}
+		return left.Rule.ID < right.Rule.ID
+	})
+}
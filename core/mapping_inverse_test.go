@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type recordingSyncSourceWriter struct {
+	records []SyncMappedRecord
+}
+
+func (w *recordingSyncSourceWriter) WriteSyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error) {
+	w.records = append(w.records, record)
+	return SyncSinkResult{InternalID: record.ExternalID}, nil
+}
+
+func TestMappingCompilerDerivesInverseSpec(t *testing.T) {
+	compiler := NewMappingCompiler()
+	schema := ExternalSchema{
+		ProviderID: "hubspot",
+		Scope:      ScopeRef{Type: "org", ID: "org_123"},
+		Name:       "contacts_schema",
+		Objects: []ExternalObjectSchema{{
+			Name: "contacts",
+			Fields: []ExternalField{
+				{Path: "email", Type: "string"},
+				{Path: "age", Type: "string"},
+				{Path: "score", Type: "integer"},
+				{Path: "created", Type: "integer"},
+				{Path: "first_name", Type: "string"},
+				{Path: "last_name", Type: "string"},
+				{Path: "tags", Type: "string", Repeatable: true},
+				{Path: "rating", Type: "number"},
+				{Path: "sku", Type: "string"},
+				{Path: "legacy_id"},
+			},
+		}},
+	}
+	spec := expressionTestSpec(
+		MappingRule{ID: "email", SourcePath: "email", TargetPath: "contact.email", Transform: "lowercase",
+			Constraints: map[string]any{MappingConstraintSourceCase: "lower"}},
+		MappingRule{ID: "age", SourcePath: "age", TargetPath: "contact.age", Transform: "to_int"},
+		MappingRule{ID: "score", SourcePath: "score", TargetPath: "contact.score", Transform: "to_string"},
+		MappingRule{ID: "created", SourcePath: "created", TargetPath: "contact.created_at", Transform: "unix_time_to_rfc3339"},
+		MappingRule{ID: "tags", SourcePath: "tags", TargetPath: "contact.labels[].name"},
+		MappingRule{ID: "name", SourcePath: "first_name", TargetPath: "contact.name", Transform: "uppercase"},
+		MappingRule{ID: "full_name", SourcePath: "last_name", TargetPath: "contact.full_name",
+			Transform: `cel: record.first_name + " " + record.last_name`},
+		MappingRule{ID: "display_name", SourcePath: "last_name", TargetPath: "contact.display_name",
+			Transform: `cel: record.last_name`, Constraints: map[string]any{MappingConstraintOneWay: true}},
+		MappingRule{ID: "rating", SourcePath: "rating", TargetPath: "contact.rating", Transform: "to_int"},
+		MappingRule{ID: "sku", SourcePath: "sku", TargetPath: "contact.sku", Transform: "trim"},
+		MappingRule{ID: "legacy_id", SourcePath: "legacy_id", TargetPath: "contact.legacy_id", Transform: "to_string"},
+	)
+
+	inverse, issues, err := compiler.CompileInverseMappingSpec(context.Background(), ValidateMappingSpecRequest{
+		Spec:   spec,
+		Schema: schema,
+	})
+	if err != nil {
+		t.Fatalf("compile inverse: %v", err)
+	}
+	if !inverse.Inverse || inverse.DeterministicHash == "" {
+		t.Fatalf("expected hashed inverse spec, got %+v", inverse)
+	}
+	notInvertible := map[string]bool{}
+	for _, issue := range issues {
+		if issue.Code == "rule_not_invertible" {
+			notInvertible[issue.RuleID] = true
+		}
+	}
+	if !reflect.DeepEqual(notInvertible, map[string]bool{
+		"name": true, "full_name": true, "rating": true, "sku": true, "legacy_id": true,
+	}) {
+		t.Fatalf("unexpected non-invertible rules: %+v", issues)
+	}
+
+	transforms := map[string]string{}
+	for _, rule := range inverse.Rules {
+		transforms[rule.Rule.ID] = rule.Transform
+	}
+	expected := map[string]string{
+		"email":   "lowercase",
+		"age":     "to_string",
+		"score":   "to_int",
+		"created": "rfc3339_to_unix_time",
+		"tags":    "identity",
+	}
+	if !reflect.DeepEqual(transforms, expected) {
+		t.Fatalf("unexpected inverse transforms %+v", transforms)
+	}
+
+	record := map[string]any{"email": "A@Example.com", "age": "42", "score": 7, "created": 1700000000, "tags": []any{"vip", "eu"}}
+	forward, _, forwardIssues := applyCompiledMappingRules(compiledRulesByID(t, compiler, spec, schema, expected), record, "test")
+	if len(forwardIssues) != 0 {
+		t.Fatalf("unexpected forward issues %+v", forwardIssues)
+	}
+	back, _, backIssues := applyCompiledMappingRules(inverse.Rules, forward, "test")
+	if len(backIssues) != 0 {
+		t.Fatalf("unexpected inverse issues %+v", backIssues)
+	}
+	want := map[string]any{"email": "a@example.com", "age": "42", "score": int64(7), "created": int64(1700000000), "tags": []any{"vip", "eu"}}
+	if !reflect.DeepEqual(back, want) {
+		t.Fatalf("expected round trip, got %#v", back)
+	}
+}
+
+func compiledRulesByID(
+	t *testing.T,
+	compiler *MappingCompiler,
+	spec MappingSpec,
+	schema ExternalSchema,
+	ids map[string]string,
+) []CompiledMappingRule {
+	t.Helper()
+	compiled, _, err := compiler.CompileMappingSpec(context.Background(), ValidateMappingSpecRequest{Spec: spec, Schema: schema})
+	if err != nil {
+		t.Fatalf("compile spec: %v", err)
+	}
+	rules := make([]CompiledMappingRule, 0, len(ids))
+	for _, rule := range compiled.Rules {
+		if _, ok := ids[rule.Rule.ID]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func TestSyncExecutionServiceExportsBidirectionalBindingWithInverseMapping(t *testing.T) {
+	ctx := context.Background()
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	specs := newInMemoryMappingSpecStore()
+	if _, err := specs.CreateDraft(ctx, MappingSpec{
+		ID: "spec_row_1", SpecID: "spec_contacts", ProviderID: "hubspot", Scope: scope, Version: 1,
+		Status: MappingSpecStatusPublished, Name: "contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+		Rules: []MappingRule{
+			{ID: "email", SourcePath: "properties.email", TargetPath: "contact.email"},
+			{ID: "age", SourcePath: "properties.age", TargetPath: "contact.age", Transform: "to_int",
+				Constraints: map[string]any{MappingConstraintSourceType: "string"}},
+		},
+	}); err != nil {
+		t.Fatalf("seed spec: %v", err)
+	}
+	bindings := &staticSyncBindingStore{bindings: map[string]SyncBinding{
+		"binding_1": {
+			ID: "binding_1", ProviderID: "hubspot", Scope: scope, ConnectionID: "conn_1",
+			MappingSpecID: "spec_contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+			Direction: SyncDirectionBidirectional, Status: SyncBindingStatusActive,
+		},
+	}}
+	writer := &recordingSyncSourceWriter{}
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		newInMemorySyncChangeLogStore(),
+		WithSyncExecutionMappings(bindings, specs),
+		WithSyncSourceWriter(writer),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+
+	result, err := service.RunSyncExport(ctx, RunSyncExportRequest{
+		Plan: SyncRunPlan{ID: "run_export", BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{{
+			SourceObject: "crm_contacts", ExternalID: "ext_1", SourceVersion: "1",
+			Payload: map[string]any{"contact": map[string]any{"email": "a@example.com", "age": 42}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("run sync export: %v", err)
+	}
+	if result.ProcessedCount != 1 || len(writer.records) != 1 {
+		t.Fatalf("expected one exported record, got %+v", result)
+	}
+	want := map[string]any{"properties": map[string]any{"email": "a@example.com", "age": "42"}}
+	if !reflect.DeepEqual(writer.records[0].Output, want) {
+		t.Fatalf("expected inverse mapping output, got %#v", writer.records[0].Output)
+	}
+
+	planner, err := NewSyncPlannerService(newInMemorySyncCheckpointStore())
+	if err != nil {
+		t.Fatalf("new sync planner: %v", err)
+	}
+	if _, err := planner.PlanSyncRun(ctx, PlanSyncRunRequest{Binding: bindings.bindings["binding_1"], Mode: SyncRunModeApply}); err == nil {
+		t.Fatalf("expected bidirectional plan without direction to fail")
+	}
+	plan, err := planner.PlanSyncRun(ctx, PlanSyncRunRequest{
+		Binding:   bindings.bindings["binding_1"],
+		Direction: SyncDirectionExport,
+		Mode:      SyncRunModeApply,
+	})
+	if err != nil {
+		t.Fatalf("plan export: %v", err)
+	}
+	if plan.Checkpoint.Direction != SyncDirectionExport {
+		t.Fatalf("expected export checkpoint, got %q", plan.Checkpoint.Direction)
+	}
+}
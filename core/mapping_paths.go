@@ -0,0 +1,380 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type mappingPathSelector int
+
+const (
+	mappingPathSelectNone mappingPathSelector = iota
+	// mappingPathSelectAll selects every element: `items[]`.
+	mappingPathSelectAll
+	// mappingPathSelectIndex selects one element: `items[0]`.
+	mappingPathSelectIndex
+	// mappingPathSelectFilter selects matching elements: `answers[?id=='q1']`.
+	mappingPathSelectFilter
+)
+
+type mappingPathSegment struct {
+	key         string
+	selector    mappingPathSelector
+	index       int
+	filterField string
+	filterValue any
+}
+
+// mappingPath is a parsed rule path. Segments are separated by dots and may
+// carry one selector that steps into a list.
+//
+// `[]` and filters are collection selectors: a source path with collection
+// selectors yields one value per element, and a target path writes one
+// element per source element, matched by position. Filtered elements are
+// renumbered so target collections stay dense.
+type mappingPath struct {
+	raw      string
+	segments []mappingPathSegment
+}
+
+type mappingRulePaths struct {
+	source mappingPath
+	target mappingPath
+}
+
+type mappingPathMatch struct {
+	value any
+	// elements holds the element position for each collection selector.
+	elements []int
+	// path is the concrete path with element indexes, e.g. `items[2].sku`.
+	path string
+}
+
+func parseMappingPath(raw string) (mappingPath, error) {
+	path := normalizePath(raw)
+	if path == "" {
+		return mappingPath{}, fmt.Errorf("core: mapping path is empty")
+	}
+	parsed := mappingPath{raw: path}
+	rest := path
+	for {
+		end := strings.IndexAny(rest, ".[")
+		key := rest
+		if end >= 0 {
+			key, rest = rest[:end], rest[end:]
+		} else {
+			rest = ""
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return mappingPath{}, fmt.Errorf("core: mapping path %q has an empty segment", path)
+		}
+		segment := mappingPathSegment{key: key}
+		if strings.HasPrefix(rest, "[") {
+			closing := mappingSelectorEnd(rest)
+			if closing < 0 {
+				return mappingPath{}, fmt.Errorf("core: mapping path %q has an unterminated selector", path)
+			}
+			if err := parseMappingPathSelector(&segment, rest[1:closing]); err != nil {
+				return mappingPath{}, fmt.Errorf("core: mapping path %q: %w", path, err)
+			}
+			rest = rest[closing+1:]
+			if strings.HasPrefix(rest, "[") {
+				return mappingPath{}, fmt.Errorf("core: mapping path %q nests selectors on segment %q", path, key)
+			}
+		}
+		parsed.segments = append(parsed.segments, segment)
+		if rest == "" {
+			return parsed, nil
+		}
+		if rest[0] != '.' || len(rest) == 1 {
+			return mappingPath{}, fmt.Errorf("core: mapping path %q is malformed near %q", path, rest)
+		}
+		rest = rest[1:]
+	}
+}
+
+// mappingSelectorEnd returns the index of the bracket closing the selector
+// at the start of value, skipping quoted filter literals.
+func mappingSelectorEnd(value string) int {
+	var quote byte
+	for index := 1; index < len(value); index++ {
+		switch char := value[index]; {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == ']':
+			return index
+		}
+	}
+	return -1
+}
+
+func parseMappingPathSelector(segment *mappingPathSegment, selector string) error {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		segment.selector = mappingPathSelectAll
+		return nil
+	}
+	if !strings.HasPrefix(selector, "?") {
+		index, err := strconv.Atoi(selector)
+		if err != nil || index < 0 {
+			return fmt.Errorf("invalid selector [%s]", selector)
+		}
+		segment.selector = mappingPathSelectIndex
+		segment.index = index
+		return nil
+	}
+	field, literal, ok := strings.Cut(selector[1:], "==")
+	field = strings.TrimSpace(field)
+	literal = strings.TrimSpace(literal)
+	if !ok || field == "" || literal == "" {
+		return fmt.Errorf("filter [%s] must have the form [?field==value]", selector)
+	}
+	value, err := parseMappingFilterLiteral(literal)
+	if err != nil {
+		return err
+	}
+	segment.selector = mappingPathSelectFilter
+	segment.filterField = field
+	segment.filterValue = value
+	return nil
+}
+
+func parseMappingFilterLiteral(literal string) (any, error) {
+	if len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0] {
+		return literal[1 : len(literal)-1], nil
+	}
+	switch literal {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %s", literal)
+	}
+	return number, nil
+}
+
+// depth is the number of collection selectors in the path.
+func (p mappingPath) depth() int {
+	depth := 0
+	for _, segment := range p.segments {
+		if segment.selector == mappingPathSelectAll || segment.selector == mappingPathSelectFilter {
+			depth++
+		}
+	}
+	return depth
+}
+
+// schemaPath drops selectors, giving the path schema fields are declared at.
+func (p mappingPath) schemaPath() string {
+	keys := make([]string, len(p.segments))
+	for index, segment := range p.segments {
+		keys[index] = segment.key
+	}
+	return strings.Join(keys, ".")
+}
+
+// lookup returns the values the path selects, in element order. A path
+// without collection selectors yields at most one match.
+func (p mappingPath) lookup(root map[string]any) []mappingPathMatch {
+	if root == nil || len(p.segments) == 0 {
+		return nil
+	}
+	var matches []mappingPathMatch
+	var walk func(current any, segmentIndex int, elements []int, path string)
+	walk = func(current any, segmentIndex int, elements []int, path string) {
+		if segmentIndex == len(p.segments) {
+			matches = append(matches, mappingPathMatch{
+				value:    current,
+				elements: append([]int(nil), elements...),
+				path:     path,
+			})
+			return
+		}
+		asMap, ok := current.(map[string]any)
+		if !ok {
+			return
+		}
+		segment := p.segments[segmentIndex]
+		next, exists := asMap[segment.key]
+		if !exists {
+			return
+		}
+		path = joinMappingPath(path, segment.key)
+		if segment.selector == mappingPathSelectNone {
+			walk(next, segmentIndex+1, elements, path)
+			return
+		}
+		items, ok := mappingListValues(next)
+		if !ok {
+			return
+		}
+		switch segment.selector {
+		case mappingPathSelectIndex:
+			if segment.index < len(items) {
+				walk(items[segment.index], segmentIndex+1, elements, indexedMappingPath(path, segment.index))
+			}
+		case mappingPathSelectAll:
+			for index, item := range items {
+				walk(item, segmentIndex+1, append(elements, index), indexedMappingPath(path, index))
+			}
+		case mappingPathSelectFilter:
+			position := 0
+			for index, item := range items {
+				if !segment.matches(item) {
+					continue
+				}
+				walk(item, segmentIndex+1, append(elements, position), indexedMappingPath(path, index))
+				position++
+			}
+		}
+	}
+	walk(root, 0, nil, "")
+	return matches
+}
+
+func (s mappingPathSegment) matches(item any) bool {
+	element, ok := item.(map[string]any)
+	if !ok {
+		return false
+	}
+	actual, found := lookupPathValue(element, s.filterField)
+	if !found {
+		return s.filterValue == nil
+	}
+	switch expected := s.filterValue.(type) {
+	case nil:
+		return actual == nil
+	case float64:
+		number, err := toFloatValue(actual)
+		return err == nil && number == expected
+	case bool:
+		flag, ok := actual.(bool)
+		return ok && flag == expected
+	default:
+		return fmt.Sprint(actual) == fmt.Sprint(expected)
+	}
+}
+
+// set writes value at the path, using elements as the position for each
+// `[]` segment. With appendValue the value is appended to the list at the
+// leaf; list values are spread so nested collections are flattened.
+func (p mappingPath) set(root map[string]any, elements []int, value any, appendValue bool) (string, error) {
+	if root == nil || len(p.segments) == 0 {
+		return "", fmt.Errorf("core: mapping path %q cannot be written", p.raw)
+	}
+	current := root
+	path := ""
+	for segmentIndex, segment := range p.segments {
+		last := segmentIndex == len(p.segments)-1
+		path = joinMappingPath(path, segment.key)
+		switch segment.selector {
+		case mappingPathSelectNone:
+			if last {
+				if appendValue {
+					existing, _ := mappingListValues(current[segment.key])
+					current[segment.key] = appendMappingValues(existing, value)
+				} else {
+					current[segment.key] = value
+				}
+				return path, nil
+			}
+			child, ok := current[segment.key].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				current[segment.key] = child
+			}
+			current = child
+		case mappingPathSelectAll:
+			if len(elements) == 0 {
+				return "", fmt.Errorf("core: mapping path %q needs an element position for %q", p.raw, segment.key)
+			}
+			position := elements[0]
+			elements = elements[1:]
+			items, _ := current[segment.key].([]any)
+			for len(items) <= position {
+				items = append(items, nil)
+			}
+			current[segment.key] = items
+			path = indexedMappingPath(path, position)
+			if last {
+				if appendValue {
+					existing, _ := mappingListValues(items[position])
+					items[position] = appendMappingValues(existing, value)
+				} else {
+					items[position] = value
+				}
+				return path, nil
+			}
+			child, ok := items[position].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				items[position] = child
+			}
+			current = child
+		default:
+			return "", fmt.Errorf("core: target path %q may only select elements with []", p.raw)
+		}
+	}
+	return path, nil
+}
+
+func appendMappingValues(existing []any, value any) []any {
+	if items, ok := mappingListValues(value); ok {
+		for _, item := range items {
+			existing = appendMappingValues(existing, item)
+		}
+		return existing
+	}
+	return append(existing, value)
+}
+
+// mappingListValues returns the elements of a list value.
+func mappingListValues(value any) ([]any, bool) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, false
+	case []any:
+		return typed, true
+	case []map[string]any:
+		out := make([]any, len(typed))
+		for index, item := range typed {
+			out[index] = item
+		}
+		return out, true
+	}
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Slice && reflected.Kind() != reflect.Array {
+		return nil, false
+	}
+	if reflected.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	out := make([]any, reflected.Len())
+	for index := range out {
+		out[index] = reflected.Index(index).Interface()
+	}
+	return out, true
+}
+
+func joinMappingPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexedMappingPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func collectionTestSchema() ExternalSchema {
+	return ExternalSchema{
+		ProviderID: "typeform",
+		Scope:      ScopeRef{Type: "org", ID: "org_123"},
+		Name:       "responses_schema",
+		Objects: []ExternalObjectSchema{
+			{
+				Name: "orders",
+				Fields: []ExternalField{
+					{Path: "id", Type: "string"},
+					{Path: "tags", Type: "string", Repeatable: true},
+					{Path: "items", Type: "object", Repeatable: true},
+					{Path: "items[].sku", Type: "string"},
+					{Path: "items[].quantity", Type: "integer"},
+					{Path: "items[].options", Type: "string", Repeatable: true},
+					{Path: "answers", Type: "object", Repeatable: true},
+					{Path: "answers.id", Type: "string"},
+					{Path: "answers.value", Type: "string"},
+				},
+			},
+		},
+	}
+}
+
+func collectionTestSpec(rules ...MappingRule) MappingSpec {
+	return MappingSpec{
+		SpecID:       "spec_orders",
+		ProviderID:   "typeform",
+		Scope:        ScopeRef{Type: "org", ID: "org_123"},
+		Name:         "orders",
+		SourceObject: "orders",
+		TargetModel:  "crm_orders",
+		Version:      1,
+		Status:       MappingSpecStatusDraft,
+		Rules:        rules,
+	}
+}
+
+func TestParseMappingPath(t *testing.T) {
+	path, err := parseMappingPath("answers[?id=='q.1'].value")
+	if err != nil {
+		t.Fatalf("parse path: %v", err)
+	}
+	if path.schemaPath() != "answers.value" || path.depth() != 1 {
+		t.Fatalf("unexpected parsed path: %q depth %d", path.schemaPath(), path.depth())
+	}
+	if path.segments[0].filterField != "id" || path.segments[0].filterValue != "q.1" {
+		t.Fatalf("unexpected filter: %+v", path.segments[0])
+	}
+
+	indexed, err := parseMappingPath("items[1].sku")
+	if err != nil {
+		t.Fatalf("parse indexed path: %v", err)
+	}
+	if indexed.depth() != 0 {
+		t.Fatalf("expected index selector to select a single element")
+	}
+
+	for _, invalid := range []string{"items[", "items[x]", "items[][]", "items..sku", "items.", "answers[?id]"} {
+		if _, err := parseMappingPath(invalid); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestMappingPreviewerMapsCollectionsPerElement(t *testing.T) {
+	previewer := NewMappingPreviewer(NewMappingCompiler())
+	result, err := previewer.PreviewMappingSpec(context.Background(), PreviewMappingSpecRequest{
+		Spec: collectionTestSpec(
+			MappingRule{ID: "rule_sku", SourcePath: "items[].sku", TargetPath: "line_items[].product.sku", Transform: "uppercase"},
+			MappingRule{ID: "rule_quantity", SourcePath: "items[].quantity", TargetPath: "line_items[].quantity"},
+			MappingRule{ID: "rule_options", SourcePath: "items[].options", TargetPath: "all_options"},
+			MappingRule{ID: "rule_tags", SourcePath: "tags", TargetPath: "labels[].name"},
+			MappingRule{ID: "rule_answer", SourcePath: "answers[?id=='q2'].value", TargetPath: "budget[].value"},
+			MappingRule{ID: "rule_first_sku", SourcePath: "items[0].sku", TargetPath: "primary_sku"},
+		),
+		Schema: collectionTestSchema(),
+		Samples: []map[string]any{{
+			"id":   "ord_1",
+			"tags": []any{"vip", "eu"},
+			"items": []any{
+				map[string]any{"sku": "a-1", "quantity": 2, "options": []any{"red", "xl"}},
+				map[string]any{"sku": "b-2", "quantity": 1, "options": []any{"blue"}},
+			},
+			"answers": []any{
+				map[string]any{"id": "q1", "value": "yes"},
+				map[string]any{"id": "q2", "value": "5000"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("preview mapping spec: %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Fatalf("expected no validation issues, got %+v", result.Issues)
+	}
+	record := result.Records[0]
+	if len(record.Issues) != 0 {
+		t.Fatalf("expected no record issues, got %+v", record.Issues)
+	}
+
+	expected := map[string]any{
+		"line_items": []any{
+			map[string]any{"product": map[string]any{"sku": "A-1"}, "quantity": 2},
+			map[string]any{"product": map[string]any{"sku": "B-2"}, "quantity": 1},
+		},
+		"all_options": []any{"red", "xl", "blue"},
+		"labels":      []any{map[string]any{"name": "vip"}, map[string]any{"name": "eu"}},
+		"budget":      []any{map[string]any{"value": "5000"}},
+		"primary_sku": "a-1",
+	}
+	if !reflect.DeepEqual(record.Output, expected) {
+		t.Fatalf("unexpected output:\n got %#v\nwant %#v", record.Output, expected)
+	}
+
+	var skuDiffs []PreviewFieldDiff
+	for _, diff := range record.Diff {
+		if diff.RuleID == "rule_sku" {
+			skuDiffs = append(skuDiffs, diff)
+		}
+	}
+	if len(skuDiffs) != 2 {
+		t.Fatalf("expected one diff per element, got %+v", skuDiffs)
+	}
+	if skuDiffs[1].SourceElementPath != "items[1].sku" || skuDiffs[1].TargetElementPath != "line_items[1].product.sku" {
+		t.Fatalf("unexpected element paths: %+v", skuDiffs[1])
+	}
+	if skuDiffs[1].InputValue != "b-2" || skuDiffs[1].OutputValue != "B-2" || !skuDiffs[1].Changed {
+		t.Fatalf("unexpected element diff: %+v", skuDiffs[1])
+	}
+	for _, diff := range record.Diff {
+		if diff.RuleID == "rule_answer" && diff.SourceElementPath != "answers[1].value" {
+			t.Fatalf("expected filter to report the source index, got %+v", diff)
+		}
+		if diff.RuleID == "rule_first_sku" && (diff.SourceElementPath != "" || diff.TargetElementPath != "") {
+			t.Fatalf("expected scalar rule without element paths, got %+v", diff)
+		}
+	}
+	if result.Report.AppliedRuleCount != len(record.Diff) {
+		t.Fatalf("expected applied rule count to match diffs")
+	}
+}
+
+func TestMappingCompilerValidatesCollectionCardinality(t *testing.T) {
+	compiler := NewMappingCompiler()
+	result, err := compiler.ValidateMappingSpec(context.Background(), ValidateMappingSpecRequest{
+		Spec: collectionTestSpec(
+			MappingRule{ID: "rule_no_selector", SourcePath: "items.sku", TargetPath: "sku"},
+			MappingRule{ID: "rule_scalar_selector", SourcePath: "id[]", TargetPath: "ids[]"},
+			MappingRule{ID: "rule_explode_scalar", SourcePath: "items[].sku", TargetPath: "lines[].skus[]"},
+			MappingRule{ID: "rule_target_filter", SourcePath: "items[].sku", TargetPath: "lines[?sku=='x'].sku"},
+			MappingRule{ID: "rule_bad_path", SourcePath: "items[", TargetPath: "broken"},
+			MappingRule{ID: "rule_explode_expression", SourcePath: "id", TargetPath: "parts[]", Transform: `cel: value.split("_")`},
+			MappingRule{ID: "rule_ok", SourcePath: "items[].sku", TargetPath: "lines[].sku"},
+		),
+		Schema: collectionTestSchema(),
+	})
+	if err != nil {
+		t.Fatalf("validate mapping spec: %v", err)
+	}
+	if result.Valid {
+		t.Fatalf("expected cardinality issues to invalidate the spec")
+	}
+	codes := map[string]string{}
+	for _, issue := range result.Issues {
+		codes[issue.RuleID] = issue.Code
+	}
+	expected := map[string]string{
+		"rule_no_selector":     "cardinality_mismatch",
+		"rule_scalar_selector": "cardinality_mismatch",
+		"rule_explode_scalar":  "cardinality_mismatch",
+		"rule_target_filter":   "target_path_invalid",
+		"rule_bad_path":        "source_path_invalid",
+	}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Fatalf("unexpected issues: %+v", result.Issues)
+	}
+}
+
+func TestMappingExpressionsSeeRepeatableObjectsAsLists(t *testing.T) {
+	compiler := NewMappingCompiler()
+	result, err := compiler.ValidateMappingSpec(context.Background(), ValidateMappingSpecRequest{
+		Spec: collectionTestSpec(MappingRule{
+			ID:          "rule_max_quantity",
+			SourcePath:  "id",
+			TargetPath:  "max_quantity",
+			Transform:   "cel: math.greatest(record.items.map(item, item.quantity))",
+			Constraints: map[string]any{"target_type": "number"},
+		}),
+		Schema: collectionTestSchema(),
+	})
+	if err != nil {
+		t.Fatalf("validate mapping spec: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected expression over repeatable objects to check, got %+v", result.Issues)
+	}
+}
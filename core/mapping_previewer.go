@@ -69,63 +69,9 @@ func (p *MappingPreviewer) PreviewMappingSpec(
 	appliedRuleCount := 0
 
 	for _, sample := range req.Samples {
-		output := make(map[string]any)
-		diffs := make([]PreviewFieldDiff, 0, len(compiledRules))
-		recordIssues := make([]MappingValidationIssue, 0)
-
-		for _, compiledRule := range compiledRules {
-			sourceValue, sourceFound := lookupPathValue(sample, compiledRule.Rule.SourcePath)
-			if !sourceFound {
-				recordIssues = append(recordIssues, mappingIssue(
-					"preview_source_missing",
-					fmt.Sprintf("core: source value for path %q was not found in sample", compiledRule.Rule.SourcePath),
-					compiledRule.Rule.ID,
-					compiledRule.Rule.SourcePath,
-					compiledRule.Rule.TargetPath,
-					MappingValidationIssueWarning,
-				))
-				continue
-			}
-
-			transformed, transformErr := applyMappingTransform(compiledRule.Transform, sourceValue)
-			if transformErr != nil {
-				recordIssues = append(recordIssues, mappingIssue(
-					"preview_transform_failed",
-					fmt.Sprintf("core: transform %q failed: %v", compiledRule.Transform, transformErr),
-					compiledRule.Rule.ID,
-					compiledRule.Rule.SourcePath,
-					compiledRule.Rule.TargetPath,
-					MappingValidationIssueError,
-				))
-				continue
-			}
-
-			setPathValue(output, compiledRule.Rule.TargetPath, transformed)
-			diffs = append(diffs, PreviewFieldDiff{
-				RuleID:      compiledRule.Rule.ID,
-				SourcePath:  compiledRule.Rule.SourcePath,
-				TargetPath:  compiledRule.Rule.TargetPath,
-				InputValue:  sourceValue,
-				OutputValue: transformed,
-				Changed: !reflect.DeepEqual(sourceValue, transformed) ||
-					normalizePath(compiledRule.Rule.SourcePath) != normalizePath(compiledRule.Rule.TargetPath),
-			})
-			appliedRuleCount++
-		}
-
-		sort.SliceStable(diffs, func(i, j int) bool {
-			left := diffs[i]
-			right := diffs[j]
-			if left.TargetPath != right.TargetPath {
-				return left.TargetPath < right.TargetPath
-			}
-			if left.SourcePath != right.SourcePath {
-				return left.SourcePath < right.SourcePath
-			}
-			return left.RuleID < right.RuleID
-		})
-		sortMappingValidationIssues(recordIssues)
+		output, diffs, recordIssues := applyCompiledMappingRules(compiledRules, sample, "preview")
 		totalRecordIssues += len(recordIssues)
+		appliedRuleCount += len(diffs)
 
 		records = append(records, PreviewRecord{
 			Input:  sample,
@@ -154,6 +100,176 @@ func (p *MappingPreviewer) PreviewMappingSpec(
 	}, nil
 }
 
+// applyCompiledMappingRules maps a source record through the compiled rules.
+// Missing source values are reported as warnings and failed transforms as
+// errors; issue codes are prefixed with codePrefix.
+//
+// Collection rules run once per selected element. When the target has one
+// more `[]` than the source the result list is exploded into target elements;
+// when it has fewer, values are flattened into the list at the target leaf.
+func applyCompiledMappingRules(
+	compiledRules []CompiledMappingRule,
+	input map[string]any,
+	codePrefix string,
+) (map[string]any, []PreviewFieldDiff, []MappingValidationIssue) {
+	output := make(map[string]any)
+	diffs := make([]PreviewFieldDiff, 0, len(compiledRules))
+	issues := make([]MappingValidationIssue, 0)
+
+	for _, compiledRule := range compiledRules {
+		rule := compiledRule.Rule
+		sourcePath, targetPath, pathErr := compiledRulePaths(compiledRule)
+		if pathErr != nil {
+			issues = append(issues, mappingIssue(
+				codePrefix+"_path_invalid",
+				pathErr.Error(),
+				rule.ID,
+				rule.SourcePath,
+				rule.TargetPath,
+				MappingValidationIssueError,
+			))
+			continue
+		}
+		matches := sourcePath.lookup(input)
+		_, isExpression := mappingExpressionSource(compiledRule.Transform)
+		if len(matches) == 0 {
+			// Empty collections map to nothing. Expressions may compute a
+			// value from other fields, so a missing scalar source is only a
+			// warning for plain transforms.
+			if sourcePath.depth() > 0 {
+				continue
+			}
+			if !isExpression {
+				issues = append(issues, mappingIssue(
+					codePrefix+"_source_missing",
+					fmt.Sprintf("core: source value for path %q was not found in sample", rule.SourcePath),
+					rule.ID,
+					rule.SourcePath,
+					rule.TargetPath,
+					MappingValidationIssueWarning,
+				))
+				continue
+			}
+			matches = []mappingPathMatch{{}}
+		}
+
+		collection := sourcePath.depth() > 0 || targetPath.depth() > 0
+		explode := targetPath.depth() == sourcePath.depth()+1
+		flatten := targetPath.depth() < sourcePath.depth()
+		changedPath := normalizePath(rule.SourcePath) != normalizePath(rule.TargetPath)
+		for _, match := range matches {
+			transformed, transformErr := applyCompiledMappingTransform(compiledRule, input, match.value)
+			if transformErr != nil {
+				issues = append(issues, mappingIssue(
+					codePrefix+"_transform_failed",
+					fmt.Sprintf("core: transform %q failed%s: %v", compiledRule.Transform, elementSuffix(match.path, collection), transformErr),
+					rule.ID,
+					rule.SourcePath,
+					rule.TargetPath,
+					MappingValidationIssueError,
+				))
+				continue
+			}
+
+			written := make([]PreviewFieldDiff, 0, 1)
+			var setErr error
+			switch {
+			case explode:
+				items, ok := mappingListValues(transformed)
+				if !ok {
+					setErr = fmt.Errorf("core: value%s is not a list and cannot be exploded into %q", elementSuffix(match.path, true), rule.TargetPath)
+					break
+				}
+				for index, item := range items {
+					elements := append(append([]int(nil), match.elements...), index)
+					targetElementPath, err := targetPath.set(output, elements, item, false)
+					if err != nil {
+						setErr = err
+						break
+					}
+					written = append(written, PreviewFieldDiff{TargetElementPath: targetElementPath, OutputValue: item})
+				}
+			case flatten:
+				targetElementPath, err := targetPath.set(output, match.elements[:targetPath.depth()], transformed, true)
+				setErr = err
+				written = append(written, PreviewFieldDiff{TargetElementPath: targetElementPath, OutputValue: transformed})
+			case targetPath.depth() == sourcePath.depth():
+				targetElementPath, err := targetPath.set(output, match.elements, transformed, false)
+				setErr = err
+				written = append(written, PreviewFieldDiff{TargetElementPath: targetElementPath, OutputValue: transformed})
+			default:
+				setErr = fmt.Errorf(
+					"core: target path %q selects %d collections but source path %q selects %d",
+					rule.TargetPath, targetPath.depth(), rule.SourcePath, sourcePath.depth(),
+				)
+			}
+			if setErr != nil {
+				issues = append(issues, mappingIssue(
+					codePrefix+"_cardinality_mismatch",
+					setErr.Error(),
+					rule.ID,
+					rule.SourcePath,
+					rule.TargetPath,
+					MappingValidationIssueError,
+				))
+				continue
+			}
+
+			for _, diff := range written {
+				diff.RuleID = rule.ID
+				diff.SourcePath = rule.SourcePath
+				diff.TargetPath = rule.TargetPath
+				diff.InputValue = match.value
+				diff.Changed = changedPath || !reflect.DeepEqual(match.value, diff.OutputValue)
+				if collection {
+					diff.SourceElementPath = match.path
+				} else {
+					diff.TargetElementPath = ""
+				}
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		left := diffs[i]
+		right := diffs[j]
+		if left.TargetPath != right.TargetPath {
+			return left.TargetPath < right.TargetPath
+		}
+		if left.SourcePath != right.SourcePath {
+			return left.SourcePath < right.SourcePath
+		}
+		return left.RuleID < right.RuleID
+	})
+	sortMappingValidationIssues(issues)
+	return output, diffs, issues
+}
+
+// compiledRulePaths returns the rule's parsed paths, parsing them when the
+// rule was built without the compiler.
+func compiledRulePaths(rule CompiledMappingRule) (mappingPath, mappingPath, error) {
+	if rule.paths != nil {
+		return rule.paths.source, rule.paths.target, nil
+	}
+	source, err := parseMappingPath(rule.Rule.SourcePath)
+	if err != nil {
+		return mappingPath{}, mappingPath{}, err
+	}
+	target, err := parseMappingPath(rule.Rule.TargetPath)
+	if err != nil {
+		return mappingPath{}, mappingPath{}, err
+	}
+	return source, target, nil
+}
+
+func elementSuffix(path string, collection bool) string {
+	if !collection || path == "" {
+		return ""
+	}
+	return fmt.Sprintf(" at %q", path)
+}
+
 func previewDeterministicHash(
 	issues []MappingValidationIssue,
 	records []PreviewRecord,
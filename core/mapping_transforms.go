@@ -8,6 +8,24 @@ import (
 	"time"
 )
 
+// applyCompiledMappingTransform evaluates the rule's expression against the
+// whole record, or applies its named transform to the source value.
+func applyCompiledMappingTransform(rule CompiledMappingRule, record map[string]any, value any) (any, error) {
+	source, isExpression := mappingExpressionSource(rule.Transform)
+	if !isExpression {
+		return applyMappingTransform(rule.Transform, value)
+	}
+	expression := rule.expression
+	if expression == nil {
+		compiled, err := compileMappingExpression(source, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		expression = compiled
+	}
+	return expression.evaluate(record, value)
+}
+
 func applyMappingTransform(transform string, value any) (any, error) {
 	switch normalizeTransform(transform) {
 	case "identity":
@@ -56,6 +74,16 @@ func applyMappingTransform(transform string, value any) (any, error) {
 			return nil, err
 		}
 		return time.Unix(unixValue, 0).UTC().Format(time.RFC3339), nil
+	case "rfc3339_to_unix_time":
+		text, err := toStringStrict(value)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, err
+		}
+		return parsed.Unix(), nil
 	default:
 		return nil, fmt.Errorf("core: unsupported transform %q", transform)
 	}
@@ -54,6 +54,8 @@ type serviceBuilder struct {
 	syncCursorStore     SyncCursorStore
 	installationStore   InstallationStore
 	syncJobStore        SyncJobStore
+	syncJobEnqueuer     JobEnqueuer
+	syncScheduleStore   SyncScheduleStore
 	grantStore          GrantStore
 	permissionEvaluator PermissionEvaluator
 	credentialCodec     CredentialCodec
@@ -206,6 +208,20 @@ func WithSyncJobStore(store SyncJobStore) Option {
 	}
 }
 
+// WithSyncJobEnqueuer queues an execution message for every sync job that is
+// created or resumed, to be run by a SyncJobExecutor.
+func WithSyncJobEnqueuer(enqueuer JobEnqueuer) Option {
+	return func(b *serviceBuilder) {
+		b.syncJobEnqueuer = enqueuer
+	}
+}
+
+func WithSyncScheduleStore(store SyncScheduleStore) Option {
+	return func(b *serviceBuilder) {
+		b.syncScheduleStore = store
+	}
+}
+
 func WithGrantStore(store GrantStore) Option {
 	return func(b *serviceBuilder) {
 		b.grantStore = store
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OutboxAdminOption func(*OutboxAdmin)
+
+// WithOutboxAdminActivitySink records an audit entry for every requeue and
+// discard.
+func WithOutboxAdminActivitySink(sink ServicesActivitySink) OutboxAdminOption {
+	return func(admin *OutboxAdmin) {
+		if admin == nil {
+			return
+		}
+		admin.activity = sink
+	}
+}
+
+func WithOutboxAdminMetrics(recorder MetricsRecorder) OutboxAdminOption {
+	return func(admin *OutboxAdmin) {
+		if admin == nil {
+			return
+		}
+		admin.metrics = recorder
+	}
+}
+
+func WithOutboxAdminClock(now func() time.Time) OutboxAdminOption {
+	return func(admin *OutboxAdmin) {
+		if admin == nil || now == nil {
+			return
+		}
+		admin.now = now
+	}
+}
+
+type OutboxAdminRequest struct {
+	EventID string
+	Actor   string
+	Reason  string
+}
+
+// OutboxAdmin is the operator surface over the lifecycle outbox: it lists and
+// inspects events, requeues or discards dead letters, and reports backlog
+// metrics.
+type OutboxAdmin struct {
+	store    OutboxAdminStore
+	activity ServicesActivitySink
+	metrics  MetricsRecorder
+	now      func() time.Time
+}
+
+func NewOutboxAdmin(store OutboxAdminStore, opts ...OutboxAdminOption) (*OutboxAdmin, error) {
+	if store == nil {
+		return nil, fmt.Errorf("core: outbox admin store is required")
+	}
+	admin := &OutboxAdmin{
+		store: store,
+		now:   func() time.Time { return time.Now().UTC() },
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(admin)
+		}
+	}
+	return admin, nil
+}
+
+func (a *OutboxAdmin) List(ctx context.Context, filter OutboxFilter) (OutboxPage, error) {
+	if a == nil || a.store == nil {
+		return OutboxPage{}, fmt.Errorf("core: outbox admin is not configured")
+	}
+	return a.store.List(ctx, filter)
+}
+
+func (a *OutboxAdmin) Get(ctx context.Context, eventID string) (OutboxEntry, error) {
+	if a == nil || a.store == nil {
+		return OutboxEntry{}, fmt.Errorf("core: outbox admin is not configured")
+	}
+	eventID = strings.TrimSpace(eventID)
+	if eventID == "" {
+		return OutboxEntry{}, fmt.Errorf("core: outbox event id is required")
+	}
+	return a.store.Get(ctx, eventID)
+}
+
+// Requeue gives a failed or discarded event a fresh attempt budget. Projectors
+// that already succeeded for the event are not run again.
+func (a *OutboxAdmin) Requeue(ctx context.Context, req OutboxAdminRequest) (OutboxEntry, error) {
+	if a == nil || a.store == nil {
+		return OutboxEntry{}, fmt.Errorf("core: outbox admin is not configured")
+	}
+	return a.apply(ctx, req, "services.outbox.requeued", ServiceActivityStatusOK, a.store.Requeue)
+}
+
+// Discard parks an undelivered event so it stops blocking its partition. The
+// event stays inspectable and can be requeued later.
+func (a *OutboxAdmin) Discard(ctx context.Context, req OutboxAdminRequest) (OutboxEntry, error) {
+	if a == nil || a.store == nil {
+		return OutboxEntry{}, fmt.Errorf("core: outbox admin is not configured")
+	}
+	return a.apply(ctx, req, "services.outbox.discarded", ServiceActivityStatusWarn, a.store.Discard)
+}
+
+// ReportMetrics publishes the backlog size, dead-letter count and lag of the
+// oldest pending event, and returns the stats they were derived from.
+func (a *OutboxAdmin) ReportMetrics(ctx context.Context) (OutboxStats, error) {
+	if a == nil || a.store == nil {
+		return OutboxStats{}, fmt.Errorf("core: outbox admin is not configured")
+	}
+	stats, err := a.store.Stats(ctx)
+	if err != nil {
+		return OutboxStats{}, err
+	}
+	if a.metrics == nil {
+		return stats, nil
+	}
+	lag := 0.0
+	if stats.OldestPendingAt != nil {
+		lag = a.now().Sub(*stats.OldestPendingAt).Seconds()
+		if lag < 0 {
+			lag = 0
+		}
+	}
+	a.metrics.ObserveHistogram(ctx, "services.outbox.pending", float64(stats.Pending+stats.Processing), nil)
+	a.metrics.ObserveHistogram(ctx, "services.outbox.dead_letter", float64(stats.Failed), nil)
+	a.metrics.ObserveHistogram(ctx, "services.outbox.lag_seconds", lag, nil)
+	return stats, nil
+}
+
+func (a *OutboxAdmin) apply(
+	ctx context.Context,
+	req OutboxAdminRequest,
+	action string,
+	status ServiceActivityStatus,
+	mutate func(ctx context.Context, eventID string) error,
+) (OutboxEntry, error) {
+	before, err := a.Get(ctx, req.EventID)
+	if err != nil {
+		return OutboxEntry{}, err
+	}
+	eventID := strings.TrimSpace(req.EventID)
+	if err := mutate(ctx, eventID); err != nil {
+		return OutboxEntry{}, err
+	}
+	after, err := a.store.Get(ctx, eventID)
+	if err != nil {
+		return OutboxEntry{}, err
+	}
+	if err := a.recordAudit(ctx, req, action, status, before); err != nil {
+		return after, err
+	}
+	return after, nil
+}
+
+func (a *OutboxAdmin) recordAudit(
+	ctx context.Context,
+	req OutboxAdminRequest,
+	action string,
+	status ServiceActivityStatus,
+	before OutboxEntry,
+) error {
+	if a.activity == nil {
+		return nil
+	}
+	actor := strings.TrimSpace(req.Actor)
+	if actor == "" {
+		actor = "system"
+	}
+	failedProjectors := make([]string, 0, len(before.Projectors))
+	for _, delivery := range before.Projectors {
+		if delivery.Status == OutboxProjectorStatusFailed {
+			failedProjectors = append(failedProjectors, delivery.Projector)
+		}
+	}
+	metadata := map[string]any{
+		"provider_id":       before.Event.ProviderID,
+		"scope_type":        before.Event.ScopeType,
+		"scope_id":          before.Event.ScopeID,
+		"event_id":          before.Event.ID,
+		"event_name":        before.Event.Name,
+		"previous_status":   string(before.Status),
+		"attempts":          before.Attempts,
+		"last_error":        before.LastError,
+		"failed_projectors": failedProjectors,
+	}
+	if before.Event.ConnectionID != "" {
+		metadata["connection_id"] = before.Event.ConnectionID
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		metadata["reason"] = reason
+	}
+	return a.activity.Record(ctx, ServiceActivityEntry{
+		Actor:     actor,
+		Action:    action,
+		Object:    "outbox_event:" + before.Event.ID,
+		Channel:   DefaultLifecycleChannel,
+		Status:    status,
+		Metadata:  metadata,
+		CreatedAt: a.now().UTC(),
+	})
+}
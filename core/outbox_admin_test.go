@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutboxAdmin_RequeueAndDiscardRecordAuditEntries(t *testing.T) {
+	now := time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC)
+	store := &memoryOutboxAdminStore{entries: map[string]OutboxEntry{
+		"evt_1": {
+			Event:     LifecycleEvent{ID: "evt_1", Name: "connection.connected", ProviderID: "github", ScopeType: "org", ScopeID: "org_1"},
+			Status:    OutboxEventStatusFailed,
+			Attempts:  5,
+			LastError: "smtp down",
+			Projectors: []OutboxProjectorDelivery{
+				{Projector: "activity", Status: OutboxProjectorStatusSucceeded},
+				{Projector: "notifications", Status: OutboxProjectorStatusFailed},
+			},
+		},
+	}}
+	sink := &recordingOutboxActivitySink{}
+	admin, err := NewOutboxAdmin(store, WithOutboxAdminActivitySink(sink), WithOutboxAdminClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("new outbox admin: %v", err)
+	}
+
+	entry, err := admin.Requeue(context.Background(), OutboxAdminRequest{EventID: "evt_1", Actor: "user:ops", Reason: "smtp restored"})
+	if err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+	if entry.Status != OutboxEventStatusPending || entry.Attempts != 0 {
+		t.Fatalf("expected requeued entry, got %+v", entry)
+	}
+	if len(sink.entries) != 1 {
+		t.Fatalf("expected one audit entry, got %d", len(sink.entries))
+	}
+	audit := sink.entries[0]
+	if audit.Actor != "user:ops" || audit.Action != "services.outbox.requeued" || audit.Object != "outbox_event:evt_1" ||
+		audit.Metadata["previous_status"] != "failed" || audit.Metadata["reason"] != "smtp restored" ||
+		audit.Metadata["provider_id"] != "github" || !audit.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected audit entry %+v", audit)
+	}
+	if failed, _ := audit.Metadata["failed_projectors"].([]string); len(failed) != 1 || failed[0] != "notifications" {
+		t.Fatalf("expected failed projectors in audit metadata, got %v", audit.Metadata["failed_projectors"])
+	}
+
+	if _, err := admin.Discard(context.Background(), OutboxAdminRequest{EventID: "evt_1"}); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	if len(sink.entries) != 2 || sink.entries[1].Action != "services.outbox.discarded" ||
+		sink.entries[1].Status != ServiceActivityStatusWarn || sink.entries[1].Actor != "system" {
+		t.Fatalf("unexpected discard audit %+v", sink.entries)
+	}
+
+	store.mutateErr = errors.New("in progress")
+	if _, err := admin.Requeue(context.Background(), OutboxAdminRequest{EventID: "evt_1"}); err == nil {
+		t.Fatalf("expected store rejection to surface")
+	}
+	if len(sink.entries) != 2 {
+		t.Fatalf("expected rejected requeue not to be audited")
+	}
+}
+
+func TestOutboxAdmin_ReportMetricsPublishesLagAndDeadLetters(t *testing.T) {
+	now := time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC)
+	oldest := now.Add(-90 * time.Second)
+	store := &memoryOutboxAdminStore{stats: OutboxStats{Pending: 3, Processing: 1, Failed: 2, OldestPendingAt: &oldest}}
+	metrics := &captureMetricsRecorder{}
+	admin, err := NewOutboxAdmin(store, WithOutboxAdminMetrics(metrics), WithOutboxAdminClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("new outbox admin: %v", err)
+	}
+	if _, err := admin.ReportMetrics(context.Background()); err != nil {
+		t.Fatalf("report metrics: %v", err)
+	}
+	observed := map[string]float64{}
+	for _, histogram := range metrics.histograms {
+		observed[histogram.name] = histogram.value
+	}
+	if observed["services.outbox.pending"] != 4 || observed["services.outbox.dead_letter"] != 2 ||
+		observed["services.outbox.lag_seconds"] != 90 {
+		t.Fatalf("unexpected outbox metrics %+v", observed)
+	}
+}
+
+type memoryOutboxAdminStore struct {
+	entries   map[string]OutboxEntry
+	stats     OutboxStats
+	mutateErr error
+}
+
+func (s *memoryOutboxAdminStore) List(context.Context, OutboxFilter) (OutboxPage, error) {
+	page := OutboxPage{Page: 1, PerPage: len(s.entries), Total: len(s.entries)}
+	for _, entry := range s.entries {
+		page.Items = append(page.Items, entry)
+	}
+	return page, nil
+}
+
+func (s *memoryOutboxAdminStore) Get(_ context.Context, eventID string) (OutboxEntry, error) {
+	entry, ok := s.entries[eventID]
+	if !ok {
+		return OutboxEntry{}, errors.New("not found")
+	}
+	return entry, nil
+}
+
+func (s *memoryOutboxAdminStore) Requeue(_ context.Context, eventID string) error {
+	return s.setStatus(eventID, OutboxEventStatusPending)
+}
+
+func (s *memoryOutboxAdminStore) Discard(_ context.Context, eventID string) error {
+	return s.setStatus(eventID, OutboxEventStatusDiscarded)
+}
+
+func (s *memoryOutboxAdminStore) Stats(context.Context) (OutboxStats, error) {
+	return s.stats, nil
+}
+
+func (s *memoryOutboxAdminStore) setStatus(eventID string, status OutboxEventStatus) error {
+	if s.mutateErr != nil {
+		return s.mutateErr
+	}
+	entry := s.entries[eventID]
+	entry.Status = status
+	if status == OutboxEventStatusPending {
+		entry.Attempts = 0
+		entry.LastError = ""
+	}
+	s.entries[eventID] = entry
+	return nil
+}
+
+type recordingOutboxActivitySink struct {
+	entries []ServiceActivityEntry
+}
+
+func (s *recordingOutboxActivitySink) Record(_ context.Context, entry ServiceActivityEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *recordingOutboxActivitySink) List(context.Context, ServicesActivityFilter) (ServicesActivityPage, error) {
+	return ServicesActivityPage{}, nil
+}
@@ -9,13 +9,20 @@ import (
 	"time"
 )
 
-const MetadataKeyOutboxAttempts = "_outbox_attempts"
+const (
+	MetadataKeyOutboxAttempts = "_outbox_attempts"
+
+	outboxDispatcherLeaderLease = "services.outbox_dispatcher"
+)
 
 type OutboxDispatcherConfig struct {
 	BatchSize      int
 	MaxAttempts    int
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
+	// Metrics receives delivery lag, projector failures and dead-lettered
+	// events. Optional.
+	Metrics MetricsRecorder
 }
 
 func DefaultOutboxDispatcherConfig() OutboxDispatcherConfig {
@@ -27,10 +34,24 @@ func DefaultOutboxDispatcherConfig() OutboxDispatcherConfig {
 	}
 }
 
+type OutboxDispatcherOption func(*OutboxDispatcher)
+
+// WithOutboxDispatcherLeaderElector makes Run dispatch only while this replica
+// holds the outbox dispatcher lease.
+func WithOutboxDispatcherLeaderElector(elector *LeaderElector) OutboxDispatcherOption {
+	return func(d *OutboxDispatcher) {
+		if d == nil || elector == nil {
+			return
+		}
+		d.elector = elector
+	}
+}
+
 type OutboxDispatcher struct {
 	store    OutboxStore
 	registry ProjectorRegistry
 	config   OutboxDispatcherConfig
+	elector  *LeaderElector
 	now      func() time.Time
 }
 
@@ -38,6 +59,7 @@ func NewOutboxDispatcher(
 	store OutboxStore,
 	registry ProjectorRegistry,
 	config OutboxDispatcherConfig,
+	opts ...OutboxDispatcherOption,
 ) (*OutboxDispatcher, error) {
 	if store == nil {
 		return nil, fmt.Errorf("core: outbox store is required")
@@ -54,14 +76,49 @@ func NewOutboxDispatcher(
 	if config.MaxBackoff <= 0 {
 		config.MaxBackoff = DefaultOutboxDispatcherConfig().MaxBackoff
 	}
-	return &OutboxDispatcher{
+	dispatcher := &OutboxDispatcher{
 		store:    store,
 		registry: registry,
 		config:   config,
 		now: func() time.Time {
 			return time.Now().UTC()
 		},
-	}, nil
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(dispatcher)
+		}
+	}
+	return dispatcher, nil
+}
+
+// Run dispatches pending events every interval until ctx is canceled, claiming
+// further batches right away while full ones keep coming back. Failed passes
+// are counted and retried on the next interval.
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration) error {
+	if d == nil || d.store == nil {
+		return fmt.Errorf("core: outbox dispatcher is not configured")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("core: outbox dispatch interval must be positive")
+	}
+	return runLeaderTicker(ctx, d.elector, outboxDispatcherLeaderLease, interval, d.tick)
+}
+
+func (d *OutboxDispatcher) tick(ctx context.Context) error {
+	for ctx.Err() == nil {
+		stats, err := d.DispatchPending(ctx, d.config.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.recordCounter(ctx, "services.outbox.dispatch_failed.total", nil)
+			}
+			return err
+		}
+		if stats.Claimed < d.config.BatchSize {
+			return nil
+		}
+	}
+	return nil
 }
 
 func (d *OutboxDispatcher) DispatchPending(ctx context.Context, batchSize int) (DispatchStats, error) {
@@ -86,6 +143,7 @@ func (d *OutboxDispatcher) DispatchPending(ctx context.Context, batchSize int) (
 			}
 			if nextAttemptIndex(event)+1 >= d.config.MaxAttempts {
 				stats.Failed++
+				d.recordCounter(ctx, "services.outbox.dead_lettered.total", outboxEventTags(event))
 			} else {
 				stats.Retried++
 			}
@@ -97,6 +155,9 @@ func (d *OutboxDispatcher) DispatchPending(ctx context.Context, batchSize int) (
 			continue
 		}
 		stats.Delivered++
+		if !event.OccurredAt.IsZero() {
+			d.recordHistogram(ctx, "services.outbox.delivery_lag_ms", float64(d.now().Sub(event.OccurredAt).Milliseconds()), outboxEventTags(event))
+		}
 	}
 
 	return stats, dispatchErr
@@ -106,6 +167,11 @@ func (d *OutboxDispatcher) dispatchOne(ctx context.Context, event LifecycleEvent
 	if d == nil || d.registry == nil {
 		return fmt.Errorf("core: outbox projector registry is not configured")
 	}
+	if named, ok := d.registry.(NamedProjectorRegistry); ok {
+		if tracker, ok := d.store.(OutboxProjectorStore); ok {
+			return d.dispatchTracked(ctx, tracker, named.NamedHandlers(), event)
+		}
+	}
 	handlers := d.registry.Handlers()
 	if len(handlers) == 0 {
 		return fmt.Errorf("core: outbox projector registry has no handlers")
@@ -121,6 +187,49 @@ func (d *OutboxDispatcher) dispatchOne(ctx context.Context, event LifecycleEvent
 	return nil
 }
 
+// dispatchTracked runs every projector that has not yet succeeded for the
+// event and records each outcome, so a retry only re-runs the failed ones.
+func (d *OutboxDispatcher) dispatchTracked(
+	ctx context.Context,
+	tracker OutboxProjectorStore,
+	handlers []NamedLifecycleEventHandler,
+	event LifecycleEvent,
+) error {
+	if len(handlers) == 0 {
+		return fmt.Errorf("core: outbox projector registry has no handlers")
+	}
+	eventID := strings.TrimSpace(event.ID)
+	deliveries, err := tracker.ListProjectorDeliveries(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	succeeded := make(map[string]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		if delivery.Status == OutboxProjectorStatusSucceeded {
+			succeeded[delivery.Projector] = true
+		}
+	}
+
+	var dispatchErr error
+	for _, named := range handlers {
+		if named.Handler == nil || succeeded[named.Name] {
+			continue
+		}
+		handleErr := named.Handler.Handle(ctx, event)
+		if err := tracker.RecordProjectorDelivery(ctx, eventID, named.Name, handleErr); err != nil {
+			dispatchErr = joinErrors(dispatchErr, err)
+			continue
+		}
+		if handleErr != nil {
+			tags := outboxEventTags(event)
+			tags["projector"] = named.Name
+			d.recordCounter(ctx, "services.outbox.projector_failed.total", tags)
+			dispatchErr = joinErrors(dispatchErr, fmt.Errorf("core: lifecycle projector %q failed for event %q: %w", named.Name, event.ID, handleErr))
+		}
+	}
+	return dispatchErr
+}
+
 func (d *OutboxDispatcher) retryEvent(ctx context.Context, event LifecycleEvent, cause error) error {
 	attempt := nextAttemptIndex(event)
 	if attempt+1 >= d.config.MaxAttempts {
@@ -146,6 +255,27 @@ func (d *OutboxDispatcher) nextBackoffDelay(attempt int) time.Duration {
 	return next
 }
 
+func (d *OutboxDispatcher) recordCounter(ctx context.Context, name string, tags map[string]string) {
+	if d == nil || d.config.Metrics == nil {
+		return
+	}
+	d.config.Metrics.IncCounter(ctx, name, 1, tags)
+}
+
+func (d *OutboxDispatcher) recordHistogram(ctx context.Context, name string, value float64, tags map[string]string) {
+	if d == nil || d.config.Metrics == nil {
+		return
+	}
+	d.config.Metrics.ObserveHistogram(ctx, name, value, tags)
+}
+
+func outboxEventTags(event LifecycleEvent) map[string]string {
+	return map[string]string{
+		"event_name":  strings.TrimSpace(event.Name),
+		"provider_id": strings.TrimSpace(event.ProviderID),
+	}
+}
+
 func nextAttemptIndex(event LifecycleEvent) int {
 	if len(event.Metadata) == 0 {
 		return 0
@@ -140,6 +140,84 @@ func TestOutboxDispatcher_MissingProjectorsDoesNotAckEvents(t *testing.T) {
 	}
 }
 
+func TestOutboxDispatcher_RunDispatchesAsLeaderAndCountsFailures(t *testing.T) {
+	store := &leasedOutboxStore{
+		stubOutboxStore: stubOutboxStore{claimed: []LifecycleEvent{{ID: "evt_1", Name: "connection.connected"}}},
+		claimErrs:       []error{nil, errors.New("database unavailable")},
+		claims:          make(chan LeaderLease, 8),
+	}
+	registry := &stubProjectorRegistry{}
+	registry.Register("ok", lifecycleEventHandlerFunc(func(context.Context, LifecycleEvent) error {
+		return nil
+	}))
+	elector, err := NewLeaderElector(newMemoryLeaderLeaseStore(), WithLeaderHolderID("replica_a"))
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+	metrics := &captureMetricsRecorder{}
+	dispatcher, err := NewOutboxDispatcher(store, registry, OutboxDispatcherConfig{Metrics: metrics},
+		WithOutboxDispatcherLeaderElector(elector))
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- dispatcher.Run(ctx, 5*time.Millisecond) }()
+	for range 3 {
+		select {
+		case lease := <-store.claims:
+			if lease.Name != outboxDispatcherLeaderLease || lease.HolderID != "replica_a" {
+				t.Fatalf("expected claims under the dispatcher lease, got %+v", lease)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected the dispatcher to keep claiming")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected canceled dispatcher to return nil, got %v", err)
+	}
+	if len(store.acked) != 1 || store.acked[0] != "evt_1" {
+		t.Fatalf("expected evt_1 to be delivered, got %v", store.acked)
+	}
+	failed := 0
+	for _, counter := range metrics.counters {
+		if counter.name == "services.outbox.dispatch_failed.total" {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Fatalf("expected the failed claim to be counted once, got %+v", metrics.counters)
+	}
+	if err := dispatcher.Run(context.Background(), 0); err == nil {
+		t.Fatalf("expected non-positive interval to be rejected")
+	}
+}
+
+// leasedOutboxStore records the lease each claim runs under and fails claims
+// with the scripted errors.
+type leasedOutboxStore struct {
+	stubOutboxStore
+	claimErrs []error
+	calls     int
+	claims    chan LeaderLease
+}
+
+func (s *leasedOutboxStore) ClaimBatch(ctx context.Context, limit int) ([]LifecycleEvent, error) {
+	lease, _ := LeaderLeaseFromContext(ctx)
+	select {
+	case s.claims <- lease:
+	default:
+	}
+	index := s.calls
+	s.calls++
+	if index < len(s.claimErrs) && s.claimErrs[index] != nil {
+		return nil, s.claimErrs[index]
+	}
+	return s.stubOutboxStore.ClaimBatch(ctx, limit)
+}
+
 type stubOutboxStore struct {
 	claimed []LifecycleEvent
 	acked   []string
@@ -196,3 +274,72 @@ func (s *stubProjectorRegistry) Register(_ string, handler LifecycleEventHandler
 func (s *stubProjectorRegistry) Handlers() []LifecycleEventHandler {
 	return append([]LifecycleEventHandler(nil), s.handlers...)
 }
+
+func TestOutboxDispatcher_TrackedProjectorsOnlyRetryFailures(t *testing.T) {
+	event := LifecycleEvent{ID: "evt_1", Name: "connection.connected", ProviderID: "github"}
+	store := &trackingOutboxStore{deliveries: map[string]OutboxProjectorStatus{}}
+	store.claimed = []LifecycleEvent{event}
+
+	calls := map[string]int{}
+	registry := NewLifecycleProjectorRegistry()
+	registry.Register("activity", lifecycleEventHandlerFunc(func(context.Context, LifecycleEvent) error {
+		calls["activity"]++
+		return nil
+	}))
+	registry.Register("notifications", lifecycleEventHandlerFunc(func(context.Context, LifecycleEvent) error {
+		calls["notifications"]++
+		if calls["notifications"] == 1 {
+			return errors.New("smtp down")
+		}
+		return nil
+	}))
+	metrics := &captureMetricsRecorder{}
+	config := DefaultOutboxDispatcherConfig()
+	config.Metrics = metrics
+	dispatcher, err := NewOutboxDispatcher(store, registry, config)
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+
+	stats, err := dispatcher.DispatchPending(context.Background(), 10)
+	if err == nil || stats.Retried != 1 {
+		t.Fatalf("expected projector failure to retry the event, got %+v %v", stats, err)
+	}
+	if store.deliveries["activity"] != OutboxProjectorStatusSucceeded || store.deliveries["notifications"] != OutboxProjectorStatusFailed {
+		t.Fatalf("unexpected projector deliveries %+v", store.deliveries)
+	}
+	if len(metrics.counters) != 1 || metrics.counters[0].name != "services.outbox.projector_failed.total" ||
+		metrics.counters[0].tags["projector"] != "notifications" {
+		t.Fatalf("expected projector failure counter, got %+v", metrics.counters)
+	}
+
+	store.claimed = []LifecycleEvent{event}
+	stats, err = dispatcher.DispatchPending(context.Background(), 10)
+	if err != nil || stats.Delivered != 1 {
+		t.Fatalf("expected redelivery to succeed, got %+v %v", stats, err)
+	}
+	if calls["activity"] != 1 || calls["notifications"] != 2 {
+		t.Fatalf("expected only the failed projector to re-run, got %+v", calls)
+	}
+}
+
+type trackingOutboxStore struct {
+	stubOutboxStore
+	deliveries map[string]OutboxProjectorStatus
+}
+
+func (s *trackingOutboxStore) ListProjectorDeliveries(_ context.Context, eventID string) ([]OutboxProjectorDelivery, error) {
+	out := make([]OutboxProjectorDelivery, 0, len(s.deliveries))
+	for projector, status := range s.deliveries {
+		out = append(out, OutboxProjectorDelivery{EventID: eventID, Projector: projector, Status: status})
+	}
+	return out, nil
+}
+
+func (s *trackingOutboxStore) RecordProjectorDelivery(_ context.Context, _ string, projector string, cause error) error {
+	s.deliveries[projector] = OutboxProjectorStatusSucceeded
+	if cause != nil {
+		s.deliveries[projector] = OutboxProjectorStatusFailed
+	}
+	return nil
+}
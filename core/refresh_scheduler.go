@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultRefreshSchedulerPollInterval = time.Minute
+	defaultRefreshSchedulerWindow       = 10 * time.Minute
+	defaultRefreshSchedulerBatchSize    = 100
+
+	refreshSchedulerLeaderLease = "services.refresh_scheduler"
+)
+
+// ExpiringCredentialLister is implemented by credential stores that can find
+// the active, refreshable credentials expiring before a point in time,
+// soonest first.
+type ExpiringCredentialLister interface {
+	ListExpiringCredentials(ctx context.Context, before time.Time, limit int) ([]Credential, error)
+}
+
+type RefreshSchedulerOption func(*RefreshScheduler)
+
+func WithRefreshSchedulerClock(now func() time.Time) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil || now == nil {
+			return
+		}
+		s.now = now
+	}
+}
+
+func WithRefreshSchedulerPollInterval(interval time.Duration) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil || interval <= 0 {
+			return
+		}
+		s.pollInterval = interval
+	}
+}
+
+// WithRefreshSchedulerWindow sets how long before expiry a credential is
+// refreshed.
+func WithRefreshSchedulerWindow(window time.Duration) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil || window <= 0 {
+			return
+		}
+		s.window = window
+	}
+}
+
+// WithRefreshSchedulerBatchSize bounds how many credentials one tick refreshes.
+func WithRefreshSchedulerBatchSize(size int) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil || size <= 0 {
+			return
+		}
+		s.batchSize = size
+	}
+}
+
+func WithRefreshSchedulerRunOptions(opts RefreshRunOptions) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil {
+			return
+		}
+		s.runOptions = opts
+	}
+}
+
+// WithRefreshSchedulerLeaderElector makes Run refresh only while this replica
+// holds the refresh scheduler lease. The connection locker still serializes
+// refreshes of one connection; the lease keeps replicas from polling for the
+// same credentials.
+func WithRefreshSchedulerLeaderElector(elector *LeaderElector) RefreshSchedulerOption {
+	return func(s *RefreshScheduler) {
+		if s == nil || elector == nil {
+			return
+		}
+		s.elector = elector
+	}
+}
+
+// RefreshSchedulerTickResult counts what one pass over expiring credentials
+// did. PendingReauth counts connections moved to pending reauth.
+type RefreshSchedulerTickResult struct {
+	Refreshed     int
+	PendingReauth int
+	Failed        int
+}
+
+// RefreshScheduler refreshes credentials before they expire through
+// Service.RunRefreshWithRetry.
+type RefreshScheduler struct {
+	service      *Service
+	lister       ExpiringCredentialLister
+	now          func() time.Time
+	pollInterval time.Duration
+	window       time.Duration
+	batchSize    int
+	runOptions   RefreshRunOptions
+	elector      *LeaderElector
+}
+
+func NewRefreshScheduler(service *Service, opts ...RefreshSchedulerOption) (*RefreshScheduler, error) {
+	if service == nil {
+		return nil, fmt.Errorf("core: service is required")
+	}
+	if service.connectionStore == nil {
+		return nil, fmt.Errorf("core: connection store is required")
+	}
+	lister, ok := service.credentialStore.(ExpiringCredentialLister)
+	if !ok {
+		return nil, fmt.Errorf("core: credential store cannot list expiring credentials")
+	}
+	scheduler := &RefreshScheduler{
+		service:      service,
+		lister:       lister,
+		pollInterval: defaultRefreshSchedulerPollInterval,
+		window:       defaultRefreshSchedulerWindow,
+		batchSize:    defaultRefreshSchedulerBatchSize,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(scheduler)
+	}
+	return scheduler, nil
+}
+
+// Run ticks every poll interval until the context is canceled. Tick errors
+// are reported through the service observability hooks and do not stop the
+// loop. With a leader elector, only the lease holder ticks.
+func (s *RefreshScheduler) Run(ctx context.Context) error {
+	if s == nil || s.service == nil {
+		return fmt.Errorf("core: refresh scheduler is not configured")
+	}
+	return runLeaderTicker(ctx, s.elector, refreshSchedulerLeaderLease, s.pollInterval, s.tick)
+}
+
+func (s *RefreshScheduler) tick(ctx context.Context) error {
+	startedAt := time.Now().UTC()
+	result, err := s.Tick(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+	s.service.observeOperation(ctx, startedAt, "refresh_schedule_tick", err, map[string]any{
+		"refreshed":      result.Refreshed,
+		"pending_reauth": result.PendingReauth,
+		"failed":         result.Failed,
+	})
+	return err
+}
+
+// Tick refreshes every credential that expires within the window on an
+// active connection. A failing credential does not block the rest; its error
+// is joined into the returned error.
+func (s *RefreshScheduler) Tick(ctx context.Context) (RefreshSchedulerTickResult, error) {
+	if s == nil || s.lister == nil {
+		return RefreshSchedulerTickResult{}, fmt.Errorf("core: refresh scheduler is not configured")
+	}
+	credentials, err := s.lister.ListExpiringCredentials(ctx, s.now().Add(s.window), s.batchSize)
+	if err != nil {
+		return RefreshSchedulerTickResult{}, err
+	}
+	var (
+		result RefreshSchedulerTickResult
+		errs   []error
+	)
+	for _, credential := range credentials {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		connection, err := s.service.connectionStore.Get(ctx, credential.ConnectionID)
+		if err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("core: load connection %q: %w", credential.ConnectionID, err))
+			continue
+		}
+		if connection.Status != ConnectionStatusActive {
+			continue
+		}
+		run, err := s.service.RunRefreshWithRetry(ctx, RefreshRequest{
+			ProviderID:   connection.ProviderID,
+			ConnectionID: connection.ID,
+		}, s.runOptions)
+		if run.PendingReauth {
+			result.PendingReauth++
+		}
+		if err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("core: refresh connection %q: %w", connection.ID, err))
+			continue
+		}
+		result.Refreshed++
+	}
+	return result, errors.Join(errs...)
+}
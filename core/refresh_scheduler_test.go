@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRefreshScheduler_RefreshesCredentialsInsideTheWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	provider := &scriptedRefreshProvider{testProvider: testProvider{id: "github"}}
+	registry := NewProviderRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	connectionStore := newMemoryConnectionStore()
+	credentialStore := &expiringMemoryCredentialStore{memoryCredentialStore: newMemoryCredentialStore()}
+	seed := func(accountID string, status ConnectionStatus, expiresAt time.Time) Connection {
+		t.Helper()
+		connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+			ProviderID:        "github",
+			Scope:             ScopeRef{Type: "user", ID: "u_" + accountID},
+			ExternalAccountID: accountID,
+			Status:            status,
+		})
+		if err != nil {
+			t.Fatalf("create connection: %v", err)
+		}
+		if _, err := credentialStore.SaveNewVersion(ctx, SaveCredentialInput{
+			ConnectionID: connection.ID,
+			TokenType:    "bearer",
+			Refreshable:  true,
+			ExpiresAt:    &expiresAt,
+			Status:       CredentialStatusActive,
+		}); err != nil {
+			t.Fatalf("seed credential: %v", err)
+		}
+		return connection
+	}
+	seed("acct_due", ConnectionStatusActive, now.Add(5*time.Minute))
+	seed("acct_later", ConnectionStatusActive, now.Add(2*time.Hour))
+	seed("acct_disconnected", ConnectionStatusDisconnected, now.Add(time.Minute))
+
+	svc, err := NewService(
+		Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithCredentialStore(credentialStore),
+		WithSecretProvider(testSecretProvider{}),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	scheduler, err := NewRefreshScheduler(svc,
+		WithRefreshSchedulerClock(func() time.Time { return now }),
+		WithRefreshSchedulerWindow(10*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("new refresh scheduler: %v", err)
+	}
+	result, err := scheduler.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Refreshed != 1 || provider.calls != 1 {
+		t.Fatalf("expected only the due credential on an active connection to refresh, got %+v after %d calls", result, provider.calls)
+	}
+
+	plain, err := NewService(Config{}, WithConnectionStore(connectionStore), WithCredentialStore(newMemoryCredentialStore()))
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if _, err := NewRefreshScheduler(plain); err == nil {
+		t.Fatalf("expected a credential store that cannot list expiring credentials to be rejected")
+	}
+}
+
+type expiringMemoryCredentialStore struct {
+	*memoryCredentialStore
+}
+
+func (s *expiringMemoryCredentialStore) ListExpiringCredentials(_ context.Context, before time.Time, limit int) ([]Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []Credential{}
+	for _, credential := range s.current {
+		if credential.Status != CredentialStatusActive || !credential.Refreshable || credential.ExpiresAt.IsZero() {
+			continue
+		}
+		if !credential.ExpiresAt.After(before) {
+			out = append(out, credential)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(out[j].ExpiresAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+var _ ExpiringCredentialLister = (*expiringMemoryCredentialStore)(nil)
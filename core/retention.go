@@ -0,0 +1,272 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetentionTarget names a high-volume table that retention can prune.
+type RetentionTarget string
+
+const (
+	RetentionTargetActivityEntries           RetentionTarget = "activity_entries"
+	RetentionTargetWebhookDeliveries         RetentionTarget = "webhook_deliveries"
+	RetentionTargetOutboundWebhookDeliveries RetentionTarget = "outbound_webhook_deliveries"
+	RetentionTargetLifecycleOutbox           RetentionTarget = "lifecycle_outbox"
+	RetentionTargetNotificationDispatches    RetentionTarget = "notification_dispatches"
+	RetentionTargetGrantEvents               RetentionTarget = "grant_events"
+	RetentionTargetSyncChangeLog             RetentionTarget = "sync_change_log"
+	RetentionTargetCredentialVersions        RetentionTarget = "credential_versions"
+	RetentionTargetSyncJobIdempotency        RetentionTarget = "sync_job_idempotency"
+)
+
+// RetentionMatch decides how MaxAge and KeepPerKey combine when a policy sets
+// both.
+type RetentionMatch string
+
+const (
+	// RetentionMatchAny deletes rows older than MaxAge and rows outside the
+	// newest KeepPerKey of their key.
+	RetentionMatchAny RetentionMatch = "any"
+	// RetentionMatchAll deletes only rows that are both older than MaxAge and
+	// outside the newest KeepPerKey of their key, so every key keeps at least
+	// KeepPerKey rows however old they are.
+	RetentionMatchAll RetentionMatch = "all"
+)
+
+const (
+	defaultRetentionBatchSize = 500
+
+	retentionLeaderLease = "services.retention"
+)
+
+// RetentionPolicy selects the rows of one target that may be deleted. A row is
+// eligible when its status is one of Statuses and it matches the age and
+// per-key rules as combined by Match (RetentionMatchAny when empty). Each
+// target defines its key (for example the connection for credential versions)
+// and the statuses that are safe to prune; an empty Statuses uses those
+// defaults. Some targets always keep the newest row of each key: the sync
+// change log keeps the latest change per record so conflict checks still see
+// it.
+type RetentionPolicy struct {
+	Target     RetentionTarget
+	MaxAge     time.Duration
+	KeepPerKey int
+	Match      RetentionMatch
+	Statuses   []string
+	// BatchSize bounds each delete statement so pruning never holds long
+	// table locks. Defaults to 500.
+	BatchSize int
+}
+
+type RetentionResult struct {
+	Target RetentionTarget
+	DryRun bool
+	// Eligible is the number of matching rows: counted up front by a dry
+	// run, and found batch by batch by a prune.
+	Eligible int
+	Deleted  int
+	Batches  int
+}
+
+type RetentionReport struct {
+	DryRun     bool
+	Results    []RetentionResult
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// RetentionStore applies retention policies. Preview counts eligible rows
+// without deleting them.
+type RetentionStore interface {
+	Prune(ctx context.Context, policy RetentionPolicy) (RetentionResult, error)
+	Preview(ctx context.Context, policy RetentionPolicy) (RetentionResult, error)
+}
+
+type RetentionRunnerOption func(*RetentionRunner)
+
+func WithRetentionMetrics(recorder MetricsRecorder) RetentionRunnerOption {
+	return func(runner *RetentionRunner) {
+		if runner == nil {
+			return
+		}
+		runner.metrics = recorder
+	}
+}
+
+// WithRetentionReporter receives the report of every run, including the
+// background runs started by Run.
+func WithRetentionReporter(reporter func(ctx context.Context, report RetentionReport, err error)) RetentionRunnerOption {
+	return func(runner *RetentionRunner) {
+		if runner == nil {
+			return
+		}
+		runner.reporter = reporter
+	}
+}
+
+// WithRetentionRunnerLeaderElector makes Run prune only while this replica
+// holds the retention lease.
+func WithRetentionRunnerLeaderElector(elector *LeaderElector) RetentionRunnerOption {
+	return func(runner *RetentionRunner) {
+		if runner == nil || elector == nil {
+			return
+		}
+		runner.elector = elector
+	}
+}
+
+func WithRetentionClock(now func() time.Time) RetentionRunnerOption {
+	return func(runner *RetentionRunner) {
+		if runner == nil || now == nil {
+			return
+		}
+		runner.now = now
+	}
+}
+
+// RetentionRunner enforces a set of per-target retention policies.
+type RetentionRunner struct {
+	store    RetentionStore
+	policies []RetentionPolicy
+	metrics  MetricsRecorder
+	reporter func(ctx context.Context, report RetentionReport, err error)
+	now      func() time.Time
+	elector  *LeaderElector
+}
+
+func NewRetentionRunner(
+	store RetentionStore,
+	policies []RetentionPolicy,
+	opts ...RetentionRunnerOption,
+) (*RetentionRunner, error) {
+	if store == nil {
+		return nil, fmt.Errorf("core: retention store is required")
+	}
+	seen := map[RetentionTarget]struct{}{}
+	normalized := make([]RetentionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		policy.Target = RetentionTarget(strings.TrimSpace(string(policy.Target)))
+		if policy.Target == "" {
+			return nil, fmt.Errorf("core: retention policy target is required")
+		}
+		if _, exists := seen[policy.Target]; exists {
+			return nil, fmt.Errorf("core: duplicate retention policy for %q", policy.Target)
+		}
+		seen[policy.Target] = struct{}{}
+		if policy.MaxAge <= 0 && policy.KeepPerKey <= 0 {
+			return nil, fmt.Errorf("core: retention policy for %q needs a max age or a per-key cap", policy.Target)
+		}
+		switch policy.Match = RetentionMatch(strings.TrimSpace(string(policy.Match))); policy.Match {
+		case "":
+			policy.Match = RetentionMatchAny
+		case RetentionMatchAny, RetentionMatchAll:
+		default:
+			return nil, fmt.Errorf("core: unknown retention match %q for %q", policy.Match, policy.Target)
+		}
+		if policy.BatchSize <= 0 {
+			policy.BatchSize = defaultRetentionBatchSize
+		}
+		policy.Statuses = append([]string(nil), policy.Statuses...)
+		normalized = append(normalized, policy)
+	}
+	runner := &RetentionRunner{
+		store:    store,
+		policies: normalized,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(runner)
+		}
+	}
+	return runner, nil
+}
+
+// Enforce deletes every eligible row. A failing target does not stop the
+// others; their errors are joined.
+func (r *RetentionRunner) Enforce(ctx context.Context) (RetentionReport, error) {
+	return r.run(ctx, false)
+}
+
+// DryRun reports how many rows each policy would delete without deleting any.
+func (r *RetentionRunner) DryRun(ctx context.Context) (RetentionReport, error) {
+	return r.run(ctx, true)
+}
+
+// Run enforces the policies every interval until ctx is canceled. Failed runs
+// are reported through the reporter and the services.retention.failed.total
+// counter, and retried on the next interval. With a leader elector, only the
+// lease holder prunes.
+func (r *RetentionRunner) Run(ctx context.Context, interval time.Duration) error {
+	if r == nil {
+		return fmt.Errorf("core: retention runner is not configured")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("core: retention interval must be positive")
+	}
+	return runLeaderTicker(ctx, r.elector, retentionLeaderLease, interval, func(ctx context.Context) error {
+		_, err := r.Enforce(ctx)
+		return err
+	})
+}
+
+func (r *RetentionRunner) run(ctx context.Context, dryRun bool) (RetentionReport, error) {
+	if r == nil || r.store == nil {
+		return RetentionReport{}, fmt.Errorf("core: retention runner is not configured")
+	}
+	report := RetentionReport{
+		DryRun:    dryRun,
+		Results:   make([]RetentionResult, 0, len(r.policies)),
+		StartedAt: r.now().UTC(),
+	}
+	var runErr error
+	for _, policy := range r.policies {
+		if ctx.Err() != nil {
+			runErr = joinErrors(runErr, ctx.Err())
+			break
+		}
+		var (
+			result RetentionResult
+			err    error
+		)
+		if dryRun {
+			result, err = r.store.Preview(ctx, policy)
+		} else {
+			result, err = r.store.Prune(ctx, policy)
+		}
+		result.Target = policy.Target
+		result.DryRun = dryRun
+		report.Results = append(report.Results, result)
+		r.recordMetrics(ctx, result)
+		if err != nil {
+			runErr = joinErrors(runErr, fmt.Errorf("core: retention for %q failed: %w", policy.Target, err))
+		}
+	}
+	report.FinishedAt = r.now().UTC()
+	if runErr != nil && r.metrics != nil && ctx.Err() == nil {
+		r.metrics.IncCounter(ctx, "services.retention.failed.total", 1, map[string]string{
+			"dry_run": fmt.Sprint(dryRun),
+		})
+	}
+	if r.reporter != nil {
+		r.reporter(ctx, report, runErr)
+	}
+	return report, runErr
+}
+
+func (r *RetentionRunner) recordMetrics(ctx context.Context, result RetentionResult) {
+	if r.metrics == nil {
+		return
+	}
+	tags := map[string]string{
+		"target":  string(result.Target),
+		"dry_run": fmt.Sprint(result.DryRun),
+	}
+	r.metrics.ObserveHistogram(ctx, "services.retention.eligible", float64(result.Eligible), tags)
+	if !result.DryRun && result.Deleted > 0 {
+		r.metrics.IncCounter(ctx, "services.retention.deleted.total", int64(result.Deleted), tags)
+	}
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetentionRunner_DryRunAndEnforceReportPerTarget(t *testing.T) {
+	store := &fakeRetentionStore{
+		eligible: map[RetentionTarget]int{
+			RetentionTargetWebhookDeliveries: 12,
+			RetentionTargetGrantEvents:       3,
+		},
+		fail: map[RetentionTarget]error{RetentionTargetGrantEvents: errors.New("locked")},
+	}
+	metrics := &captureMetricsRecorder{}
+	var reported []RetentionReport
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	runner, err := NewRetentionRunner(store, []RetentionPolicy{
+		{Target: RetentionTargetWebhookDeliveries, MaxAge: 7 * 24 * time.Hour},
+		{Target: RetentionTargetGrantEvents, KeepPerKey: 100},
+	},
+		WithRetentionMetrics(metrics),
+		WithRetentionClock(func() time.Time { return now }),
+		WithRetentionReporter(func(_ context.Context, report RetentionReport, _ error) {
+			reported = append(reported, report)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("new retention runner: %v", err)
+	}
+
+	preview, err := runner.DryRun(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "grant_events") {
+		t.Fatalf("expected failing target to be reported, got %v", err)
+	}
+	if !preview.DryRun || len(preview.Results) != 2 || preview.Results[0].Eligible != 12 || store.deleted != 0 {
+		t.Fatalf("unexpected dry run %+v (deleted %d)", preview, store.deleted)
+	}
+
+	delete(store.fail, RetentionTargetGrantEvents)
+	report, err := runner.Enforce(context.Background())
+	if err != nil {
+		t.Fatalf("enforce: %v", err)
+	}
+	if report.DryRun || report.Results[0].Deleted != 12 || report.Results[1].Deleted != 3 ||
+		report.Results[0].Batches != 1 || !report.StartedAt.Equal(now) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if store.policies[0].BatchSize != defaultRetentionBatchSize || store.policies[0].Match != RetentionMatchAny {
+		t.Fatalf("expected default batch size and match, got %+v", store.policies[0])
+	}
+	if len(reported) != 2 {
+		t.Fatalf("expected both runs to be reported, got %d", len(reported))
+	}
+	deleted := map[string]int64{}
+	for _, counter := range metrics.counters {
+		if counter.name == "services.retention.deleted.total" {
+			deleted[counter.tags["target"]] += counter.value
+		}
+	}
+	if deleted["webhook_deliveries"] != 12 || deleted["grant_events"] != 3 {
+		t.Fatalf("unexpected deletion metrics %+v", deleted)
+	}
+}
+
+func TestRetentionRunner_RunReportsFailuresUnderTheRetentionLease(t *testing.T) {
+	store := &fakeRetentionStore{fail: map[RetentionTarget]error{RetentionTargetGrantEvents: errors.New("locked")}}
+	elector, err := NewLeaderElector(newMemoryLeaderLeaseStore(), WithLeaderHolderID("replica_a"))
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+	type run struct {
+		lease LeaderLease
+		err   error
+	}
+	runs := make(chan run, 8)
+	metrics := &captureMetricsRecorder{}
+	runner, err := NewRetentionRunner(store, []RetentionPolicy{{Target: RetentionTargetGrantEvents, MaxAge: time.Hour}},
+		WithRetentionRunnerLeaderElector(elector),
+		WithRetentionMetrics(metrics),
+		WithRetentionReporter(func(ctx context.Context, _ RetentionReport, err error) {
+			lease, _ := LeaderLeaseFromContext(ctx)
+			select {
+			case runs <- run{lease: lease, err: err}:
+			default:
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("new retention runner: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.Run(ctx, 10*time.Millisecond)
+	}()
+	for range 2 {
+		select {
+		case got := <-runs:
+			if got.err == nil || got.lease.Name != retentionLeaderLease || got.lease.HolderID != "replica_a" {
+				t.Fatalf("expected a failed run under the retention lease, got %+v", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected failed runs to be retried")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected canceled retention loop to return nil, got %v", err)
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	failed := int64(0)
+	for _, counter := range metrics.counters {
+		if counter.name == "services.retention.failed.total" {
+			failed += counter.value
+		}
+	}
+	if failed < 2 {
+		t.Fatalf("expected failed runs to be counted, got %d", failed)
+	}
+	if err := runner.Run(context.Background(), 0); err == nil {
+		t.Fatalf("expected non-positive interval to be rejected")
+	}
+}
+
+func TestNewRetentionRunner_ValidatesPolicies(t *testing.T) {
+	store := &fakeRetentionStore{}
+	cases := map[string][]RetentionPolicy{
+		"missing target": {{MaxAge: time.Hour}},
+		"no rule":        {{Target: RetentionTargetGrantEvents}},
+		"duplicate": {
+			{Target: RetentionTargetGrantEvents, MaxAge: time.Hour},
+			{Target: RetentionTargetGrantEvents, KeepPerKey: 5},
+		},
+		"unknown match": {{Target: RetentionTargetGrantEvents, MaxAge: time.Hour, Match: "newest"}},
+	}
+	for name, policies := range cases {
+		if _, err := NewRetentionRunner(store, policies); err == nil {
+			t.Fatalf("%s: expected policy validation error", name)
+		}
+	}
+	if _, err := NewRetentionRunner(nil, nil); err == nil {
+		t.Fatalf("expected store to be required")
+	}
+}
+
+type fakeRetentionStore struct {
+	eligible map[RetentionTarget]int
+	fail     map[RetentionTarget]error
+	policies []RetentionPolicy
+	deleted  int
+}
+
+func (s *fakeRetentionStore) Preview(_ context.Context, policy RetentionPolicy) (RetentionResult, error) {
+	return RetentionResult{Eligible: s.eligible[policy.Target]}, s.fail[policy.Target]
+}
+
+func (s *fakeRetentionStore) Prune(_ context.Context, policy RetentionPolicy) (RetentionResult, error) {
+	s.policies = append(s.policies, policy)
+	if err := s.fail[policy.Target]; err != nil {
+		return RetentionResult{}, err
+	}
+	eligible := s.eligible[policy.Target]
+	s.deleted += eligible
+	return RetentionResult{Eligible: eligible, Deleted: eligible, Batches: 1}, nil
+}
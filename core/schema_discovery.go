@@ -0,0 +1,109 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field types in the mapping compiler vocabulary. Discovery providers map
+// native types onto these; anything else is kept as given.
+const (
+	ExternalFieldTypeString   = "string"
+	ExternalFieldTypeInteger  = "integer"
+	ExternalFieldTypeNumber   = "number"
+	ExternalFieldTypeBoolean  = "boolean"
+	ExternalFieldTypeDatetime = "datetime"
+	ExternalFieldTypeObject   = "object"
+)
+
+// NormalizeDiscoveredSchema canonicalizes field types, orders objects and
+// fields, and versions the schema by its content. The version changes only
+// when objects, fields, types or constraints change, so specs pinned to an
+// earlier version report schema_drift_detected.
+func NormalizeDiscoveredSchema(schema ExternalSchema) (ExternalSchema, error) {
+	schema.ProviderID = strings.TrimSpace(schema.ProviderID)
+	schema.Name = strings.TrimSpace(schema.Name)
+	objects := make([]ExternalObjectSchema, 0, len(schema.Objects))
+	for _, object := range schema.Objects {
+		object.Name = strings.TrimSpace(object.Name)
+		fields := make([]ExternalField, 0, len(object.Fields))
+		for _, field := range object.Fields {
+			field.Path = normalizePath(field.Path)
+			if field.Path == "" {
+				continue
+			}
+			field.Type = canonicalFieldType(field.Type)
+			field.Format = strings.TrimSpace(field.Format)
+			fields = append(fields, field)
+		}
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Path < fields[j].Path
+		})
+		object.Fields = fields
+		objects = append(objects, object)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].Name < objects[j].Name
+	})
+	schema.Objects = objects
+
+	version, err := discoveredSchemaVersion(schema)
+	if err != nil {
+		return ExternalSchema{}, err
+	}
+	schema.Version = version
+	return schema, nil
+}
+
+func discoveredSchemaVersion(schema ExternalSchema) (string, error) {
+	type fingerprintField struct {
+		Path        string         `json:"path"`
+		Type        string         `json:"type"`
+		Required    bool           `json:"required"`
+		Repeatable  bool           `json:"repeatable"`
+		Format      string         `json:"format"`
+		Constraints map[string]any `json:"constraints"`
+	}
+	type fingerprintObject struct {
+		Name       string             `json:"name"`
+		PrimaryKey []string           `json:"primary_key"`
+		Fields     []fingerprintField `json:"fields"`
+	}
+	objects := make([]fingerprintObject, 0, len(schema.Objects))
+	for _, object := range schema.Objects {
+		fields := make([]fingerprintField, 0, len(object.Fields))
+		for _, field := range object.Fields {
+			fields = append(fields, fingerprintField{
+				Path:        field.Path,
+				Type:        field.Type,
+				Required:    field.Required,
+				Repeatable:  field.Repeatable,
+				Format:      field.Format,
+				Constraints: field.Constraints,
+			})
+		}
+		objects = append(objects, fingerprintObject{
+			Name:       object.Name,
+			PrimaryKey: object.PrimaryKey,
+			Fields:     fields,
+		})
+	}
+	payload, err := json.Marshal(struct {
+		ProviderID string              `json:"provider_id"`
+		Name       string              `json:"name"`
+		Objects    []fingerprintObject `json:"objects"`
+	}{
+		ProviderID: schema.ProviderID,
+		Name:       schema.Name,
+		Objects:    objects,
+	})
+	if err != nil {
+		return "", fmt.Errorf("core: marshal discovered schema: %w", err)
+	}
+	digest := sha256.Sum256(payload)
+	return "sha256-" + hex.EncodeToString(digest[:])[:16], nil
+}
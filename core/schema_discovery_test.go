@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDiscoveredSchemaVersionsByContent(t *testing.T) {
+	discovered := func(fields ...ExternalField) ExternalSchema {
+		schema, err := NormalizeDiscoveredSchema(ExternalSchema{
+			ProviderID: "salesforce",
+			Scope:      ScopeRef{Type: "org", ID: "org_123"},
+			Name:       "salesforce.sobjects",
+			Objects:    []ExternalObjectSchema{{Name: "Account", Fields: fields}},
+			Metadata:   map[string]any{"fetched_at": "ignored"},
+		})
+		if err != nil {
+			t.Fatalf("normalize schema: %v", err)
+		}
+		return schema
+	}
+
+	first := discovered(
+		ExternalField{Path: "Name", Type: "String"},
+		ExternalField{Path: "AnnualRevenue", Type: "double"},
+	)
+	if !strings.HasPrefix(first.Version, "sha256-") {
+		t.Fatalf("expected content version, got %q", first.Version)
+	}
+	if first.Objects[0].Fields[0].Path != "AnnualRevenue" || first.Objects[0].Fields[0].Type != "number" {
+		t.Fatalf("expected sorted canonical fields, got %+v", first.Objects[0].Fields)
+	}
+	reordered := discovered(
+		ExternalField{Path: "AnnualRevenue", Type: "number"},
+		ExternalField{Path: "Name", Type: "string", Metadata: map[string]any{"label": "Account Name"}},
+	)
+	if reordered.Version != first.Version {
+		t.Fatalf("expected stable version, got %q and %q", first.Version, reordered.Version)
+	}
+	changed := discovered(
+		ExternalField{Path: "AnnualRevenue", Type: "number"},
+		ExternalField{Path: "Name", Type: "string"},
+		ExternalField{Path: "Industry", Type: "string"},
+	)
+	if changed.Version == first.Version {
+		t.Fatalf("expected added field to change the version")
+	}
+
+	result, err := NewMappingCompiler().ValidateMappingSpec(context.Background(), ValidateMappingSpecRequest{
+		Spec: MappingSpec{
+			SpecID:       "spec_accounts",
+			ProviderID:   "salesforce",
+			Scope:        ScopeRef{Type: "org", ID: "org_123"},
+			Name:         "accounts",
+			SourceObject: "Account",
+			TargetModel:  "crm_accounts",
+			SchemaRef:    deriveExternalSchemaReference(first),
+			Version:      1,
+			Status:       MappingSpecStatusDraft,
+			Rules:        []MappingRule{{ID: "name", SourcePath: "Name", TargetPath: "account.name"}},
+		},
+		Schema: changed,
+	})
+	if err != nil {
+		t.Fatalf("validate mapping spec: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Code != "schema_drift_detected" {
+		t.Fatalf("expected schema drift against the rediscovered schema, got %+v", result.Issues)
+	}
+}
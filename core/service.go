@@ -45,6 +45,8 @@ type Service struct {
 	syncCursorStore         SyncCursorStore
 	installationStore       InstallationStore
 	syncJobStore            SyncJobStore
+	syncJobEnqueuer         JobEnqueuer
+	syncScheduleStore       SyncScheduleStore
 	grantStore              GrantStore
 	permissionEvaluator     PermissionEvaluator
 	credentialCodec         CredentialCodec
@@ -77,6 +79,8 @@ type ServiceDependencies struct {
 	SyncCursorStore     SyncCursorStore
 	InstallationStore   InstallationStore
 	SyncJobStore        SyncJobStore
+	SyncJobEnqueuer     JobEnqueuer
+	SyncScheduleStore   SyncScheduleStore
 	GrantStore          GrantStore
 	PermissionEvaluator PermissionEvaluator
 	CredentialCodec     CredentialCodec
@@ -193,6 +197,11 @@ func NewService(cfg Config, opts ...Option) (*Service, error) {
 			builder.syncJobStore = provider.SyncJobStore()
 		}
 	}
+	if builder.syncScheduleStore == nil && builder.repositoryFactory != nil {
+		if provider, ok := builder.repositoryFactory.(interface{ SyncScheduleStoreCore() SyncScheduleStore }); ok {
+			builder.syncScheduleStore = provider.SyncScheduleStoreCore()
+		}
+	}
 	if builder.permissionEvaluator == nil {
 		builder.permissionEvaluator = NewGrantPermissionEvaluator(
 			builder.connectionStore,
@@ -237,6 +246,8 @@ func NewService(cfg Config, opts ...Option) (*Service, error) {
 		syncCursorStore:         builder.syncCursorStore,
 		installationStore:       builder.installationStore,
 		syncJobStore:            builder.syncJobStore,
+		syncJobEnqueuer:         builder.syncJobEnqueuer,
+		syncScheduleStore:       builder.syncScheduleStore,
 		grantStore:              builder.grantStore,
 		permissionEvaluator:     builder.permissionEvaluator,
 		credentialCodec:         builder.credentialCodec,
@@ -299,6 +310,8 @@ func (s *Service) Dependencies() ServiceDependencies {
 		SyncCursorStore:     s.syncCursorStore,
 		InstallationStore:   s.installationStore,
 		SyncJobStore:        s.syncJobStore,
+		SyncJobEnqueuer:     s.syncJobEnqueuer,
+		SyncScheduleStore:   s.syncScheduleStore,
 		GrantStore:          s.grantStore,
 		PermissionEvaluator: s.permissionEvaluator,
 		CredentialCodec:     s.credentialCodec,
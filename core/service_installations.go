@@ -40,6 +40,11 @@ func (s *Service) UpsertInstallation(ctx context.Context, in UpsertInstallationI
 	found := false
 	for _, item := range existingByScope {
 		if strings.EqualFold(strings.TrimSpace(item.InstallType), in.InstallType) {
+			// Uninstalled installations are final; activating the scope
+			// again records a reinstall as a new installation.
+			if item.Status == InstallationStatusUninstalled && status == InstallationStatusActive {
+				continue
+			}
 			found = true
 			candidate := item
 			if transitionErr := candidate.TransitionTo(status, time.Now().UTC()); transitionErr != nil {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"strings"
@@ -42,10 +43,22 @@ func (s *Service) Subscribe(ctx context.Context, req SubscribeRequest) (Subscrip
 		return Subscription{}, s.mapError(fmt.Errorf("core: provider %q is not subscribable", connection.ProviderID))
 	}
 
+	if req.Credential == nil {
+		credential, credErr := s.resolveSubscriptionCredential(ctx, connection.ID)
+		if credErr != nil {
+			return Subscription{}, s.mapError(credErr)
+		}
+		req.Credential = credential
+	}
+
 	result, err := subscribable.Subscribe(ctx, req)
 	if err != nil {
 		return Subscription{}, s.mapError(err)
 	}
+	tokenRef, err := EncodeVerificationTokenRef(ctx, s.secretProvider, result.VerificationToken)
+	if err != nil {
+		return Subscription{}, s.mapError(err)
+	}
 
 	record, err := s.subscriptionStore.Upsert(ctx, UpsertSubscriptionInput{
 		ConnectionID:         connection.ID,
@@ -55,6 +68,7 @@ func (s *Service) Subscribe(ctx context.Context, req SubscribeRequest) (Subscrip
 		ChannelID:            strings.TrimSpace(result.ChannelID),
 		RemoteSubscriptionID: strings.TrimSpace(result.RemoteSubscriptionID),
 		CallbackURL:          strings.TrimSpace(req.CallbackURL),
+		VerificationTokenRef: tokenRef,
 		Status:               SubscriptionStatusActive,
 		ExpiresAt:            result.ExpiresAt,
 		Metadata:             copyAnyMap(result.Metadata),
@@ -90,11 +104,28 @@ func (s *Service) RenewSubscription(ctx context.Context, req RenewSubscriptionRe
 		return Subscription{}, s.mapError(fmt.Errorf("core: provider %q is not subscribable", existing.ProviderID))
 	}
 
+	req.SubscriptionID = subscriptionID
+	req.Subscription = &existing
+	if req.Credential == nil {
+		credential, credErr := s.resolveSubscriptionCredential(ctx, existing.ConnectionID)
+		if credErr != nil {
+			return Subscription{}, s.mapError(credErr)
+		}
+		req.Credential = credential
+	}
+
 	result, err := subscribable.RenewSubscription(ctx, req)
 	if err != nil {
 		_ = s.subscriptionStore.UpdateState(ctx, existing.ID, SubscriptionStatusErrored, err.Error())
 		return Subscription{}, s.mapError(err)
 	}
+	tokenRef := existing.VerificationTokenRef
+	if strings.TrimSpace(result.VerificationToken) != "" {
+		tokenRef, err = EncodeVerificationTokenRef(ctx, s.secretProvider, result.VerificationToken)
+		if err != nil {
+			return Subscription{}, s.mapError(err)
+		}
+	}
 
 	channelID := strings.TrimSpace(result.ChannelID)
 	if channelID == "" {
@@ -111,7 +142,16 @@ func (s *Service) RenewSubscription(ctx context.Context, req RenewSubscriptionRe
 	}
 
 	metadata := mergeAnyMap(existing.Metadata, result.Metadata)
+	if existing.VerificationTokenRef != "" && tokenRef != existing.VerificationTokenRef {
+		grace := req.SecretGracePeriod
+		if grace <= 0 {
+			grace = DefaultVerificationTokenGracePeriod
+		}
+		metadata[MetadataKeyPreviousVerificationTokenRef] = existing.VerificationTokenRef
+		metadata[MetadataKeyPreviousVerificationTokenExpiresAt] = time.Now().UTC().Add(grace).Format(time.RFC3339Nano)
+	}
 	record, err := s.subscriptionStore.Upsert(ctx, UpsertSubscriptionInput{
+		ID:                   existing.ID,
 		ConnectionID:         existing.ConnectionID,
 		ProviderID:           existing.ProviderID,
 		ResourceType:         existing.ResourceType,
@@ -119,7 +159,7 @@ func (s *Service) RenewSubscription(ctx context.Context, req RenewSubscriptionRe
 		ChannelID:            channelID,
 		RemoteSubscriptionID: remoteID,
 		CallbackURL:          existing.CallbackURL,
-		VerificationTokenRef: existing.VerificationTokenRef,
+		VerificationTokenRef: tokenRef,
 		Status:               SubscriptionStatusActive,
 		ExpiresAt:            expiresAt,
 		Metadata:             metadata,
@@ -158,12 +198,26 @@ func (s *Service) CancelSubscription(ctx context.Context, req CancelSubscription
 	if !ok {
 		return s.mapError(fmt.Errorf("core: provider %q is not subscribable", existing.ProviderID))
 	}
-	if err := subscribable.CancelSubscription(ctx, CancelSubscriptionRequest{
-		SubscriptionID: subscriptionID,
-		Reason:         reason,
-	}); err != nil {
-		_ = s.subscriptionStore.UpdateState(ctx, existing.ID, SubscriptionStatusErrored, err.Error())
+	// A revoked or disconnected connection has no credential to call the
+	// provider with. The subscription is still cancelled locally so it is no
+	// longer renewed; the remote side lapses or was already dropped. Any other
+	// lookup failure is returned so the remote subscription is not orphaned.
+	credential, err := s.resolveSubscriptionCredential(ctx, existing.ConnectionID)
+	switch {
+	case errors.Is(err, ErrCredentialNotFound):
+		reason = fmt.Sprintf("%s (remote cancel skipped: %v)", reason, err)
+	case err != nil:
 		return s.mapError(err)
+	default:
+		if err := subscribable.CancelSubscription(ctx, CancelSubscriptionRequest{
+			SubscriptionID: subscriptionID,
+			Reason:         reason,
+			Subscription:   &existing,
+			Credential:     credential,
+		}); err != nil {
+			_ = s.subscriptionStore.UpdateState(ctx, existing.ID, SubscriptionStatusErrored, err.Error())
+			return s.mapError(err)
+		}
 	}
 
 	if err := s.subscriptionStore.UpdateState(
@@ -177,6 +231,23 @@ func (s *Service) CancelSubscription(ctx context.Context, req CancelSubscription
 	return nil
 }
 
+// resolveSubscriptionCredential loads the active credential providers need to
+// call their subscription APIs. Missing credential stores yield nil.
+func (s *Service) resolveSubscriptionCredential(ctx context.Context, connectionID string) (*ActiveCredential, error) {
+	if s == nil || s.credentialStore == nil || strings.TrimSpace(connectionID) == "" {
+		return nil, nil
+	}
+	stored, err := s.credentialStore.GetActiveByConnection(ctx, strings.TrimSpace(connectionID))
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.credentialToActive(ctx, stored)
+	if err != nil {
+		return nil, err
+	}
+	return &active, nil
+}
+
 func mergeAnyMap(left map[string]any, right map[string]any) map[string]any {
 	if len(left) == 0 && len(right) == 0 {
 		return map[string]any{}
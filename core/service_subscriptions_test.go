@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -105,6 +106,153 @@ func TestService_SubscriptionLifecycle_PersistsState(t *testing.T) {
 	}
 }
 
+func TestService_CancelSubscriptionWithoutCredentialCancelsLocally(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	subscriptionStore := newMemorySubscriptionStore()
+	provider := &subscribableTestProvider{id: "github"}
+	registry := NewProviderRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	svc, err := NewService(Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithSubscriptionStore(subscriptionStore),
+		WithCredentialStore(newMemoryCredentialStore()),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "user", ID: "usr_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusDisconnected,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	subscription, err := subscriptionStore.Upsert(ctx, UpsertSubscriptionInput{
+		ConnectionID:         connection.ID,
+		ProviderID:           "github",
+		ResourceType:         "repo",
+		ResourceID:           "repo_1",
+		ChannelID:            "chan_1",
+		RemoteSubscriptionID: "remote_1",
+		CallbackURL:          "https://app.example/webhooks/github",
+		Status:               SubscriptionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("seed subscription: %v", err)
+	}
+
+	// A failing credential lookup is not a missing credential: cancelling
+	// locally would orphan the remote subscription.
+	unavailable, err := NewService(Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithSubscriptionStore(subscriptionStore),
+		WithCredentialStore(&failingCredentialStore{
+			memoryCredentialStore: newMemoryCredentialStore(),
+			err:                   errors.New("database is unavailable"),
+		}),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	if err := unavailable.CancelSubscription(ctx, CancelSubscriptionRequest{
+		SubscriptionID: subscription.ID,
+		Reason:         "user disconnect",
+	}); err == nil {
+		t.Fatalf("expected the credential store error to be returned")
+	}
+	if stored, _ := subscriptionStore.Get(ctx, subscription.ID); stored.Status != SubscriptionStatusActive {
+		t.Fatalf("expected the subscription to stay active, got %q", stored.Status)
+	}
+
+	if err := svc.CancelSubscription(ctx, CancelSubscriptionRequest{
+		SubscriptionID: subscription.ID,
+		Reason:         "user disconnect",
+	}); err != nil {
+		t.Fatalf("cancel subscription: %v", err)
+	}
+	stored, err := subscriptionStore.Get(ctx, subscription.ID)
+	if err != nil {
+		t.Fatalf("load stored subscription: %v", err)
+	}
+	if stored.Status != SubscriptionStatusCancelled {
+		t.Fatalf("expected cancelled status, got %q", stored.Status)
+	}
+	if reason, _ := stored.Metadata["status_reason"].(string); !strings.HasPrefix(reason, "user disconnect (remote cancel skipped") {
+		t.Fatalf("expected the reason to note the skipped remote cancel, got %q", reason)
+	}
+	if provider.cancelCount != 0 {
+		t.Fatalf("expected no provider cancel without a credential")
+	}
+}
+
+func TestService_RenewSubscriptionWithNewChannelKeepsOneActiveSubscription(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	subscriptionStore := newMemorySubscriptionStore()
+	provider := &subscribableTestProvider{
+		id:              "github",
+		subscribeResult: SubscriptionResult{ChannelID: "chan_1", RemoteSubscriptionID: "remote_1"},
+		renewResult:     SubscriptionResult{ChannelID: "chan_2", RemoteSubscriptionID: "remote_2"},
+	}
+	registry := NewProviderRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	svc, err := NewService(Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithSubscriptionStore(subscriptionStore),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "user", ID: "usr_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	subscription, err := svc.Subscribe(ctx, SubscribeRequest{
+		ConnectionID: connection.ID,
+		ResourceType: "drive.file",
+		ResourceID:   "file_1",
+		CallbackURL:  "https://app.example/webhooks/github",
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	renewed, err := svc.RenewSubscription(ctx, RenewSubscriptionRequest{SubscriptionID: subscription.ID})
+	if err != nil {
+		t.Fatalf("renew subscription: %v", err)
+	}
+	if renewed.ID != subscription.ID || renewed.ChannelID != "chan_2" {
+		t.Fatalf("expected the renewal to move the subscription to chan_2, got %+v", renewed)
+	}
+	active := 0
+	for _, record := range subscriptionStore.byID {
+		if record.Status == SubscriptionStatusActive {
+			active++
+		}
+	}
+	if active != 1 || len(subscriptionStore.byID) != 1 {
+		t.Fatalf("expected exactly one active subscription, got %+v", subscriptionStore.byID)
+	}
+	if _, err := subscriptionStore.GetByChannelID(ctx, "github", "chan_1"); err == nil {
+		t.Fatalf("expected the stopped channel to be gone")
+	}
+}
+
 func TestService_RenewSubscriptionMarksErroredOnProviderFailure(t *testing.T) {
 	ctx := context.Background()
 	connectionStore := newMemoryConnectionStore()
@@ -165,6 +313,107 @@ func TestService_RenewSubscriptionMarksErroredOnProviderFailure(t *testing.T) {
 	}
 }
 
+func TestService_SubscribePassesCredentialAndEncryptsVerificationToken(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	subscriptionStore := newMemorySubscriptionStore()
+	credentialStore := newMemoryCredentialStore()
+	provider := &subscribableTestProvider{
+		id: "github",
+		subscribeResult: SubscriptionResult{
+			ChannelID:         "chan_token",
+			VerificationToken: "channel-secret",
+		},
+		renewResult: SubscriptionResult{
+			ChannelID:         "chan_token",
+			VerificationToken: "rotated-secret",
+		},
+	}
+	registry := NewProviderRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	svc, err := NewService(Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithSubscriptionStore(subscriptionStore),
+		WithCredentialStore(credentialStore),
+		WithSecretProvider(testSecretProvider{}),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "user", ID: "usr_3"},
+		ExternalAccountID: "acct_3",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	encryptedToken, err := testSecretProvider{}.Encrypt(ctx, []byte("access-123"))
+	if err != nil {
+		t.Fatalf("encrypt seed credential: %v", err)
+	}
+	if _, err := credentialStore.SaveNewVersion(ctx, SaveCredentialInput{
+		ConnectionID:     connection.ID,
+		EncryptedPayload: encryptedToken,
+		TokenType:        "bearer",
+		Status:           CredentialStatusActive,
+	}); err != nil {
+		t.Fatalf("seed credential: %v", err)
+	}
+
+	subscription, err := svc.Subscribe(ctx, SubscribeRequest{
+		ConnectionID: connection.ID,
+		ResourceType: "repo",
+		ResourceID:   "acme/widgets",
+		CallbackURL:  "https://app.example/webhooks/github",
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if provider.lastSubscribe.Credential == nil || provider.lastSubscribe.Credential.AccessToken != "access-123" {
+		t.Fatalf("expected provider to receive the active credential")
+	}
+	if subscription.VerificationTokenRef == "" || subscription.VerificationTokenRef == "channel-secret" {
+		t.Fatalf("expected encrypted verification token ref, got %q", subscription.VerificationTokenRef)
+	}
+	token, err := DecodeVerificationTokenRef(ctx, testSecretProvider{}, subscription.VerificationTokenRef)
+	if err != nil || token != "channel-secret" {
+		t.Fatalf("expected token ref to decrypt, got %q err=%v", token, err)
+	}
+
+	renewed, err := svc.RenewSubscription(ctx, RenewSubscriptionRequest{SubscriptionID: subscription.ID})
+	if err != nil {
+		t.Fatalf("renew subscription: %v", err)
+	}
+	if provider.lastRenew.Subscription == nil || provider.lastRenew.Subscription.ResourceID != "acme/widgets" {
+		t.Fatalf("expected provider to receive the existing subscription on renew")
+	}
+	token, err = DecodeVerificationTokenRef(ctx, testSecretProvider{}, renewed.VerificationTokenRef)
+	if err != nil || token != "rotated-secret" {
+		t.Fatalf("expected rotated token ref, got %q err=%v", token, err)
+	}
+	previous, ok := PreviousVerificationTokenRef(renewed, time.Now().UTC())
+	if !ok || previous != subscription.VerificationTokenRef {
+		t.Fatalf("expected replaced token ref to stay valid for the grace window, got %q", previous)
+	}
+	if _, ok := PreviousVerificationTokenRef(renewed, time.Now().UTC().Add(DefaultVerificationTokenGracePeriod+time.Second)); ok {
+		t.Fatalf("expected replaced token ref to expire after the grace window")
+	}
+
+	provider.renewResult.VerificationToken = ""
+	kept, err := svc.RenewSubscription(ctx, RenewSubscriptionRequest{SubscriptionID: subscription.ID})
+	if err != nil {
+		t.Fatalf("renew without rotation: %v", err)
+	}
+	if kept.VerificationTokenRef != renewed.VerificationTokenRef {
+		t.Fatalf("expected renewal without a new token to keep the current token ref")
+	}
+}
+
 type subscribableTestProvider struct {
 	id string
 
@@ -173,6 +422,8 @@ type subscribableTestProvider struct {
 	renewErr        error
 	cancelErr       error
 	cancelCount     int
+	lastSubscribe   SubscribeRequest
+	lastRenew       RenewSubscriptionRequest
 }
 
 func (p *subscribableTestProvider) ID() string {
@@ -203,11 +454,13 @@ func (p *subscribableTestProvider) Refresh(context.Context, ActiveCredential) (R
 	return RefreshResult{}, nil
 }
 
-func (p *subscribableTestProvider) Subscribe(context.Context, SubscribeRequest) (SubscriptionResult, error) {
+func (p *subscribableTestProvider) Subscribe(_ context.Context, req SubscribeRequest) (SubscriptionResult, error) {
+	p.lastSubscribe = req
 	return p.subscribeResult, nil
 }
 
-func (p *subscribableTestProvider) RenewSubscription(context.Context, RenewSubscriptionRequest) (SubscriptionResult, error) {
+func (p *subscribableTestProvider) RenewSubscription(_ context.Context, req RenewSubscriptionRequest) (SubscriptionResult, error) {
+	p.lastRenew = req
 	if p.renewErr != nil {
 		return SubscriptionResult{}, p.renewErr
 	}
@@ -218,3 +471,12 @@ func (p *subscribableTestProvider) CancelSubscription(context.Context, CancelSub
 	p.cancelCount++
 	return p.cancelErr
 }
+
+type failingCredentialStore struct {
+	*memoryCredentialStore
+	err error
+}
+
+func (s *failingCredentialStore) GetActiveByConnection(context.Context, string) (Credential, error) {
+	return Credential{}, s.err
+}
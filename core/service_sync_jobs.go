@@ -7,6 +7,12 @@ import (
 	"time"
 )
 
+const (
+	defaultSyncJobListLimit = 50
+	maxSyncJobListLimit     = 500
+	syncJobControlAttempts  = 3
+)
+
 func (s *Service) CreateSyncJob(ctx context.Context, req CreateSyncJobRequest) (result CreateSyncJobResult, err error) {
 	startedAt := time.Now().UTC()
 	fields := map[string]any{
@@ -52,6 +58,10 @@ func (s *Service) CreateSyncJob(ctx context.Context, req CreateSyncJobRequest) (
 		err = s.mapError(createErr)
 		return CreateSyncJobResult{}, err
 	}
+	if enqueueErr := s.enqueueSyncJob(ctx, storeResult.Job); enqueueErr != nil {
+		err = s.mapError(enqueueErr)
+		return CreateSyncJobResult{}, err
+	}
 	return storeResult, nil
 }
 
@@ -93,6 +103,176 @@ func (s *Service) GetSyncJob(ctx context.Context, req GetSyncJobRequest) (SyncJo
 	return job, nil
 }
 
+// CancelSyncJob stops the job for good. A running job stops the next time its
+// runner reports progress.
+func (s *Service) CancelSyncJob(ctx context.Context, req ControlSyncJobRequest) (SyncJob, error) {
+	return s.controlSyncJob(ctx, "cancel_sync_job", req, SyncJobStatusCanceled)
+}
+
+// PauseSyncJob holds the job until it is resumed. A running job stops the
+// next time its runner reports progress and keeps its checkpoint.
+func (s *Service) PauseSyncJob(ctx context.Context, req ControlSyncJobRequest) (SyncJob, error) {
+	return s.controlSyncJob(ctx, "pause_sync_job", req, SyncJobStatusPaused)
+}
+
+// ResumeSyncJob queues a paused or failed job again.
+func (s *Service) ResumeSyncJob(ctx context.Context, req ControlSyncJobRequest) (SyncJob, error) {
+	return s.controlSyncJob(ctx, "resume_sync_job", req, SyncJobStatusQueued)
+}
+
+func (s *Service) ListSyncJobs(ctx context.Context, req ListSyncJobsRequest) (jobs []SyncJob, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{
+		"provider_id":   req.ProviderID,
+		"connection_id": req.ConnectionID,
+	}
+	defer func() {
+		fields["count"] = len(jobs)
+		s.observeOperation(ctx, startedAt, "list_sync_jobs", err, fields)
+	}()
+
+	store, storeErr := s.syncJobExecutionStore()
+	if storeErr != nil {
+		err = s.mapError(storeErr)
+		return nil, err
+	}
+	filter := SyncJobFilter{
+		ProviderID:   strings.TrimSpace(req.ProviderID),
+		ConnectionID: strings.TrimSpace(req.ConnectionID),
+		Limit:        req.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSyncJobListLimit
+	}
+	filter.Limit = min(filter.Limit, maxSyncJobListLimit)
+	for _, status := range req.Statuses {
+		normalized := SyncJobStatus(strings.TrimSpace(strings.ToLower(string(status))))
+		if normalized == "" {
+			continue
+		}
+		filter.Statuses = append(filter.Statuses, normalized)
+	}
+	jobs, err = store.ListSyncJobs(ctx, filter)
+	if err != nil {
+		err = s.mapError(err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *Service) controlSyncJob(
+	ctx context.Context,
+	operation string,
+	req ControlSyncJobRequest,
+	status SyncJobStatus,
+) (job SyncJob, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{
+		"sync_job_id": req.SyncJobID,
+		"status":      status,
+	}
+	defer func() {
+		s.observeOperation(ctx, startedAt, operation, err, fields)
+	}()
+
+	store, storeErr := s.syncJobExecutionStore()
+	if storeErr != nil {
+		err = s.mapError(storeErr)
+		return SyncJob{}, err
+	}
+	job, err = s.GetSyncJob(ctx, GetSyncJobRequest{
+		SyncJobID:    req.SyncJobID,
+		ProviderID:   req.ProviderID,
+		ScopeType:    req.ScopeType,
+		ScopeID:      req.ScopeID,
+		ConnectionID: req.ConnectionID,
+	})
+	if err != nil {
+		return SyncJob{}, err
+	}
+	if status == SyncJobStatusQueued && job.Status != SyncJobStatusPaused && job.Status != SyncJobStatusFailed {
+		err = s.mapError(fmt.Errorf("%w: only paused or failed jobs can be resumed, job is %s", ErrInvalidSyncJobStatusTransition, job.Status))
+		return SyncJob{}, err
+	}
+
+	// A running job keeps renewing its lease, so retry when the status moved
+	// between the read and the conditional update.
+	for range syncJobControlAttempts {
+		if job.Status == status {
+			return job, nil
+		}
+		next := job
+		if transitionErr := next.TransitionTo(status, time.Now().UTC()); transitionErr != nil {
+			err = s.mapError(transitionErr)
+			return SyncJob{}, err
+		}
+		next.LeaseExpiresAt = nil
+		next.Metadata = mergeAnyMap(next.Metadata, syncJobControlMetadata(status, req))
+		saved, ok, updateErr := store.UpdateSyncJobIf(ctx, next, job.Status)
+		if updateErr != nil {
+			err = s.mapError(updateErr)
+			return SyncJob{}, err
+		}
+		job = saved
+		if !ok {
+			continue
+		}
+		if status == SyncJobStatusQueued {
+			if enqueueErr := s.enqueueSyncJob(ctx, job); enqueueErr != nil {
+				err = s.mapError(enqueueErr)
+				return SyncJob{}, err
+			}
+		}
+		return job, nil
+	}
+	err = s.mapError(fmt.Errorf("%w: sync job %q kept changing status", ErrInvalidSyncJobStatusTransition, job.ID))
+	return SyncJob{}, err
+}
+
+func (s *Service) syncJobExecutionStore() (SyncJobExecutionStore, error) {
+	if s == nil || s.syncJobStore == nil {
+		return nil, fmt.Errorf("core: sync job store is required")
+	}
+	store, ok := s.syncJobStore.(SyncJobExecutionStore)
+	if !ok {
+		return nil, fmt.Errorf("core: sync job store does not support job execution")
+	}
+	return store, nil
+}
+
+// enqueueSyncJob queues a queued job for execution. Replayed creates enqueue
+// again, which the queue deduplicates, so a create whose enqueue failed can be
+// retried with the same idempotency key.
+func (s *Service) enqueueSyncJob(ctx context.Context, job SyncJob) error {
+	if s == nil || s.syncJobEnqueuer == nil || job.Status != SyncJobStatusQueued {
+		return nil
+	}
+	if _, err := s.syncJobEnqueuer.Enqueue(ctx, NewSyncJobExecutionMessage(job)); err != nil {
+		return fmt.Errorf("core: enqueue sync job %q: %w", job.ID, err)
+	}
+	return nil
+}
+
+func syncJobControlMetadata(status SyncJobStatus, req ControlSyncJobRequest) map[string]any {
+	action := "resumed"
+	switch status {
+	case SyncJobStatusCanceled:
+		action = "canceled"
+	case SyncJobStatusPaused:
+		action = "paused"
+	}
+	metadata := map[string]any{
+		action + "_at": time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		metadata[action+"_reason"] = reason
+	}
+	if requestedBy := strings.TrimSpace(req.RequestedBy); requestedBy != "" {
+		metadata[action+"_by"] = requestedBy
+	}
+	return metadata
+}
+
 func normalizeCreateSyncJobRequest(req CreateSyncJobRequest) (CreateSyncJobRequest, ScopeRef, error) {
 	normalized := CreateSyncJobRequest{
 		ProviderID:     strings.TrimSpace(req.ProviderID),
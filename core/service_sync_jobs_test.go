@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -226,6 +227,99 @@ func TestService_GetSyncJob_GuardsAndNotFound(t *testing.T) {
 	}
 }
 
+func TestService_SyncJobControls_CancelPauseResumeAndList(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "org", ID: "org_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+
+	syncJobStore := newMemoryServiceSyncJobStore()
+	enqueuer := &recordingSyncJobEnqueuer{}
+	svc, err := NewService(
+		Config{},
+		WithConnectionStore(connectionStore),
+		WithSyncJobStore(syncJobStore),
+		WithSyncJobEnqueuer(enqueuer),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	create := func() SyncJob {
+		t.Helper()
+		result, err := svc.CreateSyncJob(ctx, CreateSyncJobRequest{
+			ProviderID:   "github",
+			ScopeType:    "org",
+			ScopeID:      "org_1",
+			ConnectionID: connection.ID,
+			Mode:         SyncJobModeFull,
+		})
+		if err != nil {
+			t.Fatalf("create sync job: %v", err)
+		}
+		return result.Job
+	}
+	paused := create()
+	canceled := create()
+	if ids := enqueuer.jobIDs(); !slices.Equal(ids, []string{paused.ID, canceled.ID}) {
+		t.Fatalf("expected created jobs to be enqueued, got %v", ids)
+	}
+
+	if _, err := svc.ResumeSyncJob(ctx, ControlSyncJobRequest{SyncJobID: paused.ID}); err == nil {
+		t.Fatalf("expected resuming a queued job to fail")
+	}
+	got, err := svc.PauseSyncJob(ctx, ControlSyncJobRequest{SyncJobID: paused.ID, Reason: "maintenance", RequestedBy: "ops"})
+	if err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if got.Status != SyncJobStatusPaused || got.Metadata["paused_reason"] != "maintenance" || got.Metadata["paused_by"] != "ops" {
+		t.Fatalf("unexpected paused job %+v", got)
+	}
+	got, err = svc.ResumeSyncJob(ctx, ControlSyncJobRequest{SyncJobID: paused.ID, ProviderID: "github"})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if got.Status != SyncJobStatusQueued || got.Metadata["resumed_at"] == nil {
+		t.Fatalf("unexpected resumed job %+v", got)
+	}
+	if ids := enqueuer.jobIDs(); len(ids) != 3 || ids[2] != paused.ID {
+		t.Fatalf("expected resumed job to be enqueued again, got %v", ids)
+	}
+
+	got, err = svc.CancelSyncJob(ctx, ControlSyncJobRequest{SyncJobID: canceled.ID})
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if got.Status != SyncJobStatusCanceled || got.Metadata["canceled_at"] == nil {
+		t.Fatalf("unexpected canceled job %+v", got)
+	}
+	if _, err := svc.ResumeSyncJob(ctx, ControlSyncJobRequest{SyncJobID: canceled.ID}); err == nil {
+		t.Fatalf("expected resuming a canceled job to fail")
+	}
+	if _, err := svc.CancelSyncJob(ctx, ControlSyncJobRequest{SyncJobID: canceled.ID, ProviderID: "slack"}); err == nil ||
+		!strings.Contains(strings.ToLower(fmt.Sprint(err)), "sync job not found") {
+		t.Fatalf("expected provider guard miss to return sync job not found, got %v", err)
+	}
+
+	listed, err := svc.ListSyncJobs(ctx, ListSyncJobsRequest{
+		ProviderID: "github",
+		Statuses:   []SyncJobStatus{" Canceled "},
+	})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != canceled.ID {
+		t.Fatalf("expected only the canceled job, got %+v", listed)
+	}
+}
+
 type memoryServiceSyncJobStore struct {
 	mu      sync.Mutex
 	next    int
@@ -292,4 +386,77 @@ func (s *memoryServiceSyncJobStore) GetSyncJob(_ context.Context, id string) (Sy
 	return job, nil
 }
 
-var _ SyncJobStore = (*memoryServiceSyncJobStore)(nil)
+func (s *memoryServiceSyncJobStore) UpdateSyncJobIf(
+	_ context.Context,
+	job SyncJob,
+	expected SyncJobStatus,
+) (SyncJob, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.jobs[job.ID]
+	if !ok {
+		return SyncJob{}, false, fmt.Errorf("%w: id %q", ErrSyncJobNotFound, job.ID)
+	}
+	if current.Status != expected {
+		return current, false, nil
+	}
+	s.jobs[job.ID] = job
+	return job, true, nil
+}
+
+func (s *memoryServiceSyncJobStore) ListSyncJobs(_ context.Context, filter SyncJobFilter) ([]SyncJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []SyncJob{}
+	for i := 1; i <= s.next; i++ {
+		job, ok := s.jobs[fmt.Sprintf("job_%d", i)]
+		if !ok {
+			continue
+		}
+		if filter.ProviderID != "" && job.ProviderID != filter.ProviderID {
+			continue
+		}
+		if filter.ConnectionID != "" && job.ConnectionID != filter.ConnectionID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, job.Status) {
+			continue
+		}
+		if filter.LeaseExpiredBefore != nil &&
+			(job.LeaseExpiresAt == nil || job.LeaseExpiresAt.After(*filter.LeaseExpiredBefore)) {
+			continue
+		}
+		out = append(out, job)
+		if filter.Limit > 0 && len(out) == filter.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+type recordingSyncJobEnqueuer struct {
+	mu       sync.Mutex
+	messages []*JobExecutionMessage
+}
+
+func (e *recordingSyncJobEnqueuer) Enqueue(_ context.Context, msg *JobExecutionMessage) (JobEnqueueReceipt, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.messages = append(e.messages, msg)
+	return JobEnqueueReceipt{DispatchID: fmt.Sprintf("dispatch_%d", len(e.messages))}, nil
+}
+
+func (e *recordingSyncJobEnqueuer) jobIDs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ids := make([]string, 0, len(e.messages))
+	for _, msg := range e.messages {
+		ids = append(ids, fmt.Sprint(msg.Parameters[SyncJobParamID]))
+	}
+	return ids
+}
+
+var (
+	_ SyncJobStore          = (*memoryServiceSyncJobStore)(nil)
+	_ SyncJobExecutionStore = (*memoryServiceSyncJobStore)(nil)
+)
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSyncScheduleListLimit = 50
+	maxSyncScheduleListLimit     = 500
+	defaultSyncScheduleRunCount  = 5
+	maxSyncScheduleRunCount      = 100
+)
+
+// UpsertSyncSchedule creates or replaces a recurring sync. Saving a schedule
+// recomputes its next window from now, so resuming a paused schedule skips
+// the windows it missed.
+func (s *Service) UpsertSyncSchedule(ctx context.Context, req UpsertSyncScheduleRequest) (schedule SyncSchedule, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{
+		"sync_schedule_id": req.SyncScheduleID,
+		"provider_id":      req.ProviderID,
+		"connection_id":    req.ConnectionID,
+		"sync_binding_id":  req.SyncBindingID,
+	}
+	defer func() {
+		if schedule.ID != "" {
+			fields["sync_schedule_id"] = schedule.ID
+		}
+		s.observeOperation(ctx, startedAt, "upsert_sync_schedule", err, fields)
+	}()
+
+	if s == nil || s.syncScheduleStore == nil {
+		err = s.mapError(fmt.Errorf("core: sync schedule store is required"))
+		return SyncSchedule{}, err
+	}
+	schedule = SyncSchedule{
+		ID:             strings.TrimSpace(req.SyncScheduleID),
+		ProviderID:     strings.TrimSpace(req.ProviderID),
+		Scope:          ScopeRef{Type: strings.TrimSpace(strings.ToLower(req.ScopeType)), ID: strings.TrimSpace(req.ScopeID)},
+		SyncBindingID:  strings.TrimSpace(req.SyncBindingID),
+		Mode:           SyncJobMode(strings.TrimSpace(strings.ToLower(string(req.Mode)))),
+		CronExpression: strings.TrimSpace(req.CronExpression),
+		Interval:       req.Interval.Truncate(time.Second),
+		Jitter:         req.Jitter.Truncate(time.Second),
+		Timezone:       strings.TrimSpace(req.Timezone),
+		Status:         SyncScheduleStatusActive,
+		Metadata:       copyAnyMap(req.Metadata),
+	}
+	if schedule.Mode == "" {
+		schedule.Mode = SyncJobModeDelta
+	}
+	if req.Paused {
+		schedule.Status = SyncScheduleStatusPaused
+	}
+	if schedule.ProviderID == "" {
+		err = s.mapError(fmt.Errorf("core: provider id is required"))
+		return SyncSchedule{}, err
+	}
+	if scopeErr := schedule.Scope.Validate(); scopeErr != nil {
+		err = s.mapError(fmt.Errorf("%w: %v", ErrInvalidSyncJobScope, scopeErr))
+		return SyncSchedule{}, err
+	}
+	connection, resolveErr := s.resolveSyncJobConnection(ctx, schedule.ProviderID, schedule.Scope, req.ConnectionID)
+	if resolveErr != nil {
+		err = s.mapError(resolveErr)
+		return SyncSchedule{}, err
+	}
+	schedule.ConnectionID = connection.ID
+	if validateErr := schedule.Validate(); validateErr != nil {
+		err = s.mapError(validateErr)
+		return SyncSchedule{}, err
+	}
+	window, nextErr := schedule.NextWindow(time.Now().UTC())
+	if nextErr != nil {
+		err = s.mapError(nextErr)
+		return SyncSchedule{}, err
+	}
+	runAt := schedule.RunAt(window)
+	schedule.NextWindowAt = &window
+	schedule.NextRunAt = &runAt
+	if schedule.ID != "" {
+		existing, getErr := s.syncScheduleStore.GetSyncSchedule(ctx, schedule.ID)
+		switch {
+		case getErr == nil:
+			schedule.LastWindowAt = existing.LastWindowAt
+			schedule.LastSyncJobID = existing.LastSyncJobID
+		case !errors.Is(getErr, ErrSyncScheduleNotFound):
+			err = s.mapError(getErr)
+			return SyncSchedule{}, err
+		}
+	}
+
+	schedule, err = s.syncScheduleStore.UpsertSyncSchedule(ctx, schedule)
+	if err != nil {
+		err = s.mapError(err)
+		return SyncSchedule{}, err
+	}
+	return schedule, nil
+}
+
+func (s *Service) GetSyncSchedule(ctx context.Context, id string) (schedule SyncSchedule, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{"sync_schedule_id": id}
+	defer func() {
+		s.observeOperation(ctx, startedAt, "get_sync_schedule", err, fields)
+	}()
+
+	if s == nil || s.syncScheduleStore == nil {
+		err = s.mapError(fmt.Errorf("core: sync schedule store is required"))
+		return SyncSchedule{}, err
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		err = s.mapError(fmt.Errorf("core: sync schedule id is required"))
+		return SyncSchedule{}, err
+	}
+	schedule, err = s.syncScheduleStore.GetSyncSchedule(ctx, id)
+	if err != nil {
+		err = s.mapError(err)
+		return SyncSchedule{}, err
+	}
+	return schedule, nil
+}
+
+func (s *Service) ListSyncSchedules(ctx context.Context, req ListSyncSchedulesRequest) (schedules []SyncSchedule, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{
+		"provider_id":     req.ProviderID,
+		"connection_id":   req.ConnectionID,
+		"sync_binding_id": req.SyncBindingID,
+	}
+	defer func() {
+		fields["count"] = len(schedules)
+		s.observeOperation(ctx, startedAt, "list_sync_schedules", err, fields)
+	}()
+
+	if s == nil || s.syncScheduleStore == nil {
+		err = s.mapError(fmt.Errorf("core: sync schedule store is required"))
+		return nil, err
+	}
+	filter := SyncScheduleFilter{
+		ProviderID:    strings.TrimSpace(req.ProviderID),
+		ConnectionID:  strings.TrimSpace(req.ConnectionID),
+		SyncBindingID: strings.TrimSpace(req.SyncBindingID),
+		Limit:         req.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSyncScheduleListLimit
+	}
+	filter.Limit = min(filter.Limit, maxSyncScheduleListLimit)
+	for _, status := range req.Statuses {
+		normalized := SyncScheduleStatus(strings.TrimSpace(strings.ToLower(string(status))))
+		if normalized == "" {
+			continue
+		}
+		filter.Statuses = append(filter.Statuses, normalized)
+	}
+	schedules, err = s.syncScheduleStore.ListSyncSchedules(ctx, filter)
+	if err != nil {
+		err = s.mapError(err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ListSyncScheduleRuns previews upcoming runs of a schedule, including the
+// jitter each run will use. Paused and suspended schedules are previewed as if
+// they were active.
+func (s *Service) ListSyncScheduleRuns(ctx context.Context, req ListSyncScheduleRunsRequest) (runs []SyncScheduleRun, err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{"sync_schedule_id": req.SyncScheduleID}
+	defer func() {
+		s.observeOperation(ctx, startedAt, "list_sync_schedule_runs", err, fields)
+	}()
+
+	schedule, err := s.GetSyncSchedule(ctx, req.SyncScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	count := req.Count
+	if count <= 0 {
+		count = defaultSyncScheduleRunCount
+	}
+	count = min(count, maxSyncScheduleRunCount)
+	after := time.Now().UTC()
+	if req.After != nil {
+		after = req.After.UTC()
+	}
+	runs, err = schedule.UpcomingRuns(after, count)
+	if err != nil {
+		err = s.mapError(err)
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (s *Service) DeleteSyncSchedule(ctx context.Context, id string) (err error) {
+	startedAt := time.Now().UTC()
+	fields := map[string]any{"sync_schedule_id": id}
+	defer func() {
+		s.observeOperation(ctx, startedAt, "delete_sync_schedule", err, fields)
+	}()
+
+	if s == nil || s.syncScheduleStore == nil {
+		err = s.mapError(fmt.Errorf("core: sync schedule store is required"))
+		return err
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		err = s.mapError(fmt.Errorf("core: sync schedule id is required"))
+		return err
+	}
+	if err = s.syncScheduleStore.DeleteSyncSchedule(ctx, id); err != nil {
+		err = s.mapError(err)
+		return err
+	}
+	return nil
+}
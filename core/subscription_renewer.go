@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultSubscriptionRenewerPollInterval = time.Minute
+	defaultSubscriptionRenewerWindow       = time.Hour
+
+	subscriptionRenewerLeaderLease = "services.subscription_renewer"
+)
+
+type SubscriptionRenewerOption func(*SubscriptionRenewer)
+
+func WithSubscriptionRenewerClock(now func() time.Time) SubscriptionRenewerOption {
+	return func(r *SubscriptionRenewer) {
+		if r == nil || now == nil {
+			return
+		}
+		r.now = now
+	}
+}
+
+func WithSubscriptionRenewerPollInterval(interval time.Duration) SubscriptionRenewerOption {
+	return func(r *SubscriptionRenewer) {
+		if r == nil || interval <= 0 {
+			return
+		}
+		r.pollInterval = interval
+	}
+}
+
+// WithSubscriptionRenewerWindow sets how long before expiry a subscription is
+// renewed. It should exceed the poll interval so a tick never misses one.
+func WithSubscriptionRenewerWindow(window time.Duration) SubscriptionRenewerOption {
+	return func(r *SubscriptionRenewer) {
+		if r == nil || window <= 0 {
+			return
+		}
+		r.window = window
+	}
+}
+
+// WithSubscriptionRenewerLeaderElector makes Run renew only while this replica
+// holds the subscription renewer lease, so providers see one renewal per
+// subscription instead of one per replica.
+func WithSubscriptionRenewerLeaderElector(elector *LeaderElector) SubscriptionRenewerOption {
+	return func(r *SubscriptionRenewer) {
+		if r == nil || elector == nil {
+			return
+		}
+		r.elector = elector
+	}
+}
+
+// SubscriptionRenewalResult counts what one pass over expiring subscriptions
+// did.
+type SubscriptionRenewalResult struct {
+	Renewed int
+	Failed  int
+}
+
+// SubscriptionRenewer renews active subscriptions through
+// Service.RenewSubscription before they expire.
+type SubscriptionRenewer struct {
+	service      *Service
+	store        SubscriptionStore
+	now          func() time.Time
+	pollInterval time.Duration
+	window       time.Duration
+	elector      *LeaderElector
+}
+
+func NewSubscriptionRenewer(service *Service, opts ...SubscriptionRenewerOption) (*SubscriptionRenewer, error) {
+	if service == nil {
+		return nil, fmt.Errorf("core: service is required")
+	}
+	if service.subscriptionStore == nil {
+		return nil, fmt.Errorf("core: subscription store is required")
+	}
+	renewer := &SubscriptionRenewer{
+		service:      service,
+		store:        service.subscriptionStore,
+		pollInterval: defaultSubscriptionRenewerPollInterval,
+		window:       defaultSubscriptionRenewerWindow,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(renewer)
+	}
+	return renewer, nil
+}
+
+// Run ticks every poll interval until the context is canceled. Tick errors
+// are reported through the service observability hooks and do not stop the
+// loop. With a leader elector, only the lease holder ticks.
+func (r *SubscriptionRenewer) Run(ctx context.Context) error {
+	if r == nil || r.service == nil {
+		return fmt.Errorf("core: subscription renewer is not configured")
+	}
+	return runLeaderTicker(ctx, r.elector, subscriptionRenewerLeaderLease, r.pollInterval, r.tick)
+}
+
+func (r *SubscriptionRenewer) tick(ctx context.Context) error {
+	startedAt := time.Now().UTC()
+	result, err := r.Tick(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+	r.service.observeOperation(ctx, startedAt, "subscription_renewal_tick", err, map[string]any{
+		"renewed": result.Renewed,
+		"failed":  result.Failed,
+	})
+	return err
+}
+
+// Tick renews every active subscription that expires within the window. A
+// failing subscription does not block the rest; its error is joined into the
+// returned error and it is retried next tick while it is still active.
+func (r *SubscriptionRenewer) Tick(ctx context.Context) (SubscriptionRenewalResult, error) {
+	if r == nil || r.store == nil {
+		return SubscriptionRenewalResult{}, fmt.Errorf("core: subscription renewer is not configured")
+	}
+	expiring, err := r.store.ListExpiring(ctx, r.now().Add(r.window))
+	if err != nil {
+		return SubscriptionRenewalResult{}, err
+	}
+	var (
+		result SubscriptionRenewalResult
+		errs   []error
+	)
+	for _, subscription := range expiring {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		if subscription.Status != SubscriptionStatusActive {
+			continue
+		}
+		if _, err := r.service.RenewSubscription(ctx, RenewSubscriptionRequest{SubscriptionID: subscription.ID}); err != nil {
+			result.Failed++
+			errs = append(errs, fmt.Errorf("core: renew subscription %q: %w", subscription.ID, err))
+			if errors.Is(err, ErrLeadershipLost) {
+				break
+			}
+			continue
+		}
+		result.Renewed++
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionRenewer_RenewsSubscriptionsInsideTheWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	connectionStore := newMemoryConnectionStore()
+	subscriptionStore := newMemorySubscriptionStore()
+	provider := &subscribableTestProvider{id: "github"}
+	registry := NewProviderRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	svc, err := NewService(Config{},
+		WithRegistry(registry),
+		WithConnectionStore(connectionStore),
+		WithSubscriptionStore(subscriptionStore),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "user", ID: "usr_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	subscribe := func(channelID string, expiresAt time.Time) Subscription {
+		t.Helper()
+		provider.subscribeResult = SubscriptionResult{ChannelID: channelID, ExpiresAt: &expiresAt}
+		subscription, err := svc.Subscribe(ctx, SubscribeRequest{
+			ConnectionID: connection.ID,
+			ResourceType: "repo",
+			ResourceID:   channelID,
+			CallbackURL:  "https://app.example/webhooks/github",
+		})
+		if err != nil {
+			t.Fatalf("subscribe %s: %v", channelID, err)
+		}
+		return subscription
+	}
+	soon := subscribe("chan_soon", now.Add(30*time.Minute))
+	subscribe("chan_later", now.Add(3*time.Hour))
+	renewedUntil := now.Add(24 * time.Hour)
+	provider.renewResult = SubscriptionResult{ChannelID: "chan_soon", ExpiresAt: &renewedUntil}
+
+	renewer, err := NewSubscriptionRenewer(svc,
+		WithSubscriptionRenewerClock(func() time.Time { return now }),
+		WithSubscriptionRenewerWindow(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("new renewer: %v", err)
+	}
+	result, err := renewer.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Renewed != 1 || provider.lastRenew.SubscriptionID != soon.ID {
+		t.Fatalf("expected only the expiring subscription to be renewed, got %+v", result)
+	}
+	if result, err := renewer.Tick(ctx); err != nil || result.Renewed != 0 {
+		t.Fatalf("expected the renewed subscription to leave the window, got %+v %v", result, err)
+	}
+
+	provider.renewErr = errTestRenewFailed
+	now = now.Add(23 * time.Hour)
+	result, err = renewer.Tick(ctx)
+	if err == nil || result.Failed != 2 {
+		t.Fatalf("expected both failed renewals to be reported, got %+v %v", result, err)
+	}
+}
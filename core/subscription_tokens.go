@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	verificationTokenRefPrefix = "enc:v1:"
+
+	// MetadataKeyPreviousVerificationTokenRef and
+	// MetadataKeyPreviousVerificationTokenExpiresAt keep the token replaced
+	// by a rotation, so notifications signed before it still verify.
+	MetadataKeyPreviousVerificationTokenRef       = "previous_verification_token_ref"
+	MetadataKeyPreviousVerificationTokenExpiresAt = "previous_verification_token_expires_at"
+
+	DefaultVerificationTokenGracePeriod = 10 * time.Minute
+)
+
+// EncodeVerificationTokenRef encrypts a subscription verification token into
+// the opaque reference persisted on Subscription.VerificationTokenRef.
+func EncodeVerificationTokenRef(ctx context.Context, secrets SecretProvider, token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", nil
+	}
+	if secrets == nil {
+		return "", fmt.Errorf("core: secret provider is required to store subscription verification tokens")
+	}
+	ciphertext, err := secrets.Encrypt(ctx, []byte(token))
+	if err != nil {
+		return "", fmt.Errorf("core: encrypt subscription verification token: %w", err)
+	}
+	return verificationTokenRefPrefix + base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecodeVerificationTokenRef resolves the plaintext token for a reference
+// produced by EncodeVerificationTokenRef.
+func DecodeVerificationTokenRef(ctx context.Context, secrets SecretProvider, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("core: subscription verification token ref is empty")
+	}
+	if !strings.HasPrefix(ref, verificationTokenRefPrefix) {
+		return "", fmt.Errorf("core: unsupported subscription verification token ref format")
+	}
+	if secrets == nil {
+		return "", fmt.Errorf("core: secret provider is required to read subscription verification tokens")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(ref, verificationTokenRefPrefix))
+	if err != nil {
+		return "", fmt.Errorf("core: decode subscription verification token ref: %w", err)
+	}
+	plaintext, err := secrets.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("core: decrypt subscription verification token: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// PreviousVerificationTokenRef returns the token reference replaced by the
+// last rotation while its grace window is still open at now.
+func PreviousVerificationTokenRef(subscription Subscription, now time.Time) (string, bool) {
+	ref, _ := subscription.Metadata[MetadataKeyPreviousVerificationTokenRef].(string)
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", false
+	}
+	raw, _ := subscription.Metadata[MetadataKeyPreviousVerificationTokenExpiresAt].(string)
+	expiresAt, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(raw))
+	if err != nil || !now.Before(expiresAt) {
+		return "", false
+	}
+	return ref, true
+}
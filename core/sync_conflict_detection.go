@@ -0,0 +1,492 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SyncConflictReasonStaleVersion           = "stale_source_version"
+	SyncConflictReasonConcurrentModification = "concurrent_modification"
+
+	syncChangeMetadataModifiedAt = "modified_at"
+)
+
+// WithSyncConflictDetection compares every applied change with the last
+// change-log entries for its record and settles conflicts with the binding's
+// conflict_strategy metadata, falling back to strategy. The change log store
+// must implement SyncChangeLogReader.
+func WithSyncConflictDetection(strategy SyncConflictStrategy) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.conflictStrategy = strategy
+	}
+}
+
+// WithSyncConflictMerger registers the merger for a strategy, replacing the
+// built-in one when the names match.
+func WithSyncConflictMerger(strategy SyncConflictStrategy, merger SyncConflictMerger) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil || merger == nil {
+			return
+		}
+		if s.conflictMergers == nil {
+			s.conflictMergers = defaultSyncConflictMergers()
+		}
+		s.conflictMergers[strategy] = merger
+	}
+}
+
+func defaultSyncConflictMergers() map[SyncConflictStrategy]SyncConflictMerger {
+	return map[SyncConflictStrategy]SyncConflictMerger{
+		SyncConflictStrategyLastWriterWins: SyncConflictMergerFunc(mergeSyncConflictLastWriterWins),
+		SyncConflictStrategySourceWins:     SyncConflictMergerFunc(mergeSyncConflictSourceWins),
+		SyncConflictStrategyTargetWins:     SyncConflictMergerFunc(mergeSyncConflictTargetWins),
+		SyncConflictStrategyThreeWayMerge:  SyncConflictMergerFunc(mergeSyncConflictThreeWay),
+		SyncConflictStrategyManual: SyncConflictMergerFunc(func(context.Context, DetectedSyncConflict) (SyncConflictDecision, error) {
+			return SyncConflictDecision{Outcome: SyncConflictOutcomeManual, Reason: "manual resolution required"}, nil
+		}),
+	}
+}
+
+func (s *SyncExecutionService) configureSyncConflictDetection() error {
+	if s.conflictStrategy == "" {
+		return nil
+	}
+	reader, ok := s.changeLogStore.(SyncChangeLogReader)
+	if !ok {
+		return fmt.Errorf("core: sync conflict detection requires a change log store implementing SyncChangeLogReader")
+	}
+	if s.conflictMergers == nil {
+		s.conflictMergers = defaultSyncConflictMergers()
+	}
+	if _, ok := s.conflictMergers[s.conflictStrategy]; !ok {
+		return fmt.Errorf("core: unknown sync conflict strategy %q", s.conflictStrategy)
+	}
+	s.changeLogReader = reader
+	return nil
+}
+
+// settleSyncConflict detects whether the change conflicts with the record's
+// synced state and applies the strategy's decision. It returns the change to
+// deliver, which carries the merged payload when the strategy merged.
+func (s *SyncExecutionService) settleSyncConflict(
+	ctx context.Context,
+	target *syncRunTarget,
+	result *SyncRunResult,
+	checkpoint SyncCheckpoint,
+	direction SyncDirection,
+	change SyncChange,
+	idempotencyKey string,
+	runMetadata map[string]any,
+) (syncRecordDisposition, SyncChange) {
+	detected, err := s.detectSyncConflict(ctx, target, checkpoint.SyncBindingID, direction, change)
+	if err != nil {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change, err)
+		return syncRecordFailed, change
+	}
+	if detected == nil {
+		return syncRecordDelivered, change
+	}
+	if detected.Change.ModifiedAt.IsZero() {
+		detected.Change.ModifiedAt = s.now()
+	}
+	merger, ok := s.conflictMergers[detected.Strategy]
+	if !ok {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change,
+			fmt.Errorf("core: unknown sync conflict strategy %q", detected.Strategy))
+		return syncRecordFailed, change
+	}
+	decision, err := merger.MergeSyncConflict(ctx, *detected)
+	if err != nil {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change, fmt.Errorf("core: merge sync conflict: %w", err))
+		return syncRecordFailed, change
+	}
+	switch decision.Outcome {
+	case SyncConflictOutcomeApply, SyncConflictOutcomeKeep, SyncConflictOutcomeManual:
+	default:
+		decision = SyncConflictDecision{
+			Outcome: SyncConflictOutcomeManual,
+			Reason:  fmt.Sprintf("unknown conflict outcome %q", decision.Outcome),
+		}
+	}
+
+	var recorded SyncConflict
+	if s.conflictRecorder != nil {
+		recordedResult, err := s.conflictRecorder.RecordSyncConflict(ctx, RecordSyncConflictRequest{
+			Conflict: SyncConflict{
+				ProviderID:     checkpoint.ProviderID,
+				Scope:          checkpoint.Scope,
+				ConnectionID:   checkpoint.ConnectionID,
+				SyncBindingID:  checkpoint.SyncBindingID,
+				CheckpointID:   checkpoint.ID,
+				SourceObject:   change.SourceObject,
+				ExternalID:     change.ExternalID,
+				SourceVersion:  change.SourceVersion,
+				IdempotencyKey: idempotencyKey,
+				Policy:         string(detected.Strategy),
+				Reason:         detected.Reason,
+				Status:         SyncConflictStatusPending,
+				SourcePayload:  RedactSensitiveMap(change.Payload),
+				TargetPayload:  RedactSensitiveMap(detected.Current),
+				Metadata: map[string]any{
+					"run_id":          result.RunID,
+					"direction":       string(direction),
+					"fields":          append([]string(nil), detected.Fields...),
+					"current_version": detected.CurrentVersion,
+					"base_payload":    copyMetadata(detected.Base),
+				},
+			},
+			Metadata: runMetadata,
+		})
+		if err != nil {
+			s.recordSyncRecordFailure(ctx, result, checkpoint, change, fmt.Errorf("core: record sync conflict: %w", err))
+			return syncRecordFailed, change
+		}
+		recorded = recordedResult.Conflict
+	}
+
+	if decision.Outcome == SyncConflictOutcomeManual {
+		result.ConflictCount++
+		return syncRecordConflicted, change
+	}
+	if resolver, ok := s.conflictRecorder.(SyncConflictResolver); ok && recorded.ID != "" {
+		if _, err := resolver.ResolveSyncConflict(ctx, ResolveSyncConflictRequest{
+			ProviderID: recorded.ProviderID,
+			Scope:      recorded.Scope,
+			ConflictID: recorded.ID,
+			Resolution: SyncConflictResolution{
+				Action:     SyncConflictResolutionResolve,
+				Patch:      copyMetadata(decision.Payload),
+				Reason:     strings.TrimSpace(string(decision.Outcome) + ": " + decision.Reason),
+				ResolvedBy: "sync:" + string(detected.Strategy),
+			},
+			Metadata: runMetadata,
+		}); err != nil {
+			s.recordSyncRecordFailure(ctx, result, checkpoint, change, fmt.Errorf("core: resolve sync conflict: %w", err))
+			return syncRecordFailed, change
+		}
+	}
+	result.ResolvedConflictCount++
+	if decision.Outcome == SyncConflictOutcomeKeep {
+		return syncRecordSkipped, change
+	}
+	if decision.Payload != nil {
+		change.Payload = decision.Payload
+	}
+	return syncRecordDelivered, change
+}
+
+// detectSyncConflict reports a conflict when the change is older than the
+// version last synced in its direction, or when the other direction logged a
+// newer change that the incoming payload disagrees with. Fields the other side
+// left at their base value are not conflicts: the incoming change simply moved
+// them forward. A record never synced in the run direction cannot conflict,
+// since the only version its side has seen came from the other direction.
+// Deletes and restores carry no state to compare, so only stale ones conflict.
+func (s *SyncExecutionService) detectSyncConflict(
+	ctx context.Context,
+	target *syncRunTarget,
+	bindingID string,
+	direction SyncDirection,
+	change SyncChange,
+) (*DetectedSyncConflict, error) {
+	if s.changeLogReader == nil {
+		return nil, nil
+	}
+	last, hasLast, err := s.changeLogReader.GetLatest(ctx, bindingID, direction, change.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("core: load last sync change: %w", err)
+	}
+	if !hasLast || last.SourceVersion == change.SourceVersion {
+		return nil, nil
+	}
+
+	detected := &DetectedSyncConflict{
+		Direction: direction,
+		Strategy:  s.conflictStrategy,
+		Change:    change,
+	}
+	if target != nil && target.binding != nil {
+		detected.Binding = *target.binding
+		if strategy, ok := target.binding.Metadata[SyncBindingMetadataConflictStrategy].(string); ok && strings.TrimSpace(strategy) != "" {
+			detected.Strategy = SyncConflictStrategy(strings.TrimSpace(strings.ToLower(strategy)))
+		}
+	}
+	if compareSyncVersions(change.SourceVersion, last.SourceVersion) < 0 {
+		detected.Reason = SyncConflictReasonStaleVersion
+		detected.Current = last.Payload
+		detected.CurrentVersion = last.SourceVersion
+		detected.CurrentModifiedAt = syncChangeLogModifiedAt(last)
+		detected.Fields = conflictingSyncFields(change.Payload, last.Payload, nil)
+		return detected, nil
+	}
+
+	if change.Operation.isTombstone() || last.Operation.isTombstone() {
+		return nil, nil
+	}
+	other, hasOther, err := s.changeLogReader.GetLatest(ctx, bindingID, direction.opposite(), change.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("core: load last sync change: %w", err)
+	}
+	if !hasOther || !other.OccurredAt.After(last.OccurredAt) || other.Operation.isTombstone() {
+		return nil, nil
+	}
+	current, ok := target.counterpartPayload(other.Payload)
+	if !ok {
+		return nil, nil
+	}
+	fields := conflictingSyncFields(change.Payload, current, last.Payload)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	detected.Reason = SyncConflictReasonConcurrentModification
+	detected.Base = last.Payload
+	detected.Current = current
+	detected.CurrentVersion = other.SourceVersion
+	detected.CurrentModifiedAt = syncChangeLogModifiedAt(other)
+	detected.Fields = fields
+	return detected, nil
+}
+
+// counterpartPayload maps a payload logged by the opposite direction into the
+// shape of this run's changes. Without a mapping both directions share one
+// shape; a mapped run without a counterpart cannot compare payloads.
+func (t *syncRunTarget) counterpartPayload(payload map[string]any) (map[string]any, bool) {
+	if t == nil || t.compiled == nil {
+		return payload, true
+	}
+	if t.counterpart == nil {
+		return nil, false
+	}
+	output, _, issues := applyCompiledMappingRules(t.counterpart.Rules, payload, "sync")
+	if containsMappingErrors(issues) {
+		return nil, false
+	}
+	return output, true
+}
+
+func mergeSyncConflictLastWriterWins(_ context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error) {
+	if conflict.Reason == SyncConflictReasonStaleVersion {
+		return keepNewerSyncVersion(conflict), nil
+	}
+	if conflict.Change.ModifiedAt.After(conflict.CurrentModifiedAt) {
+		return SyncConflictDecision{Outcome: SyncConflictOutcomeApply, Reason: "incoming change is newer"}, nil
+	}
+	return SyncConflictDecision{Outcome: SyncConflictOutcomeKeep, Reason: "current version is newer"}, nil
+}
+
+func mergeSyncConflictSourceWins(_ context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error) {
+	if conflict.Reason == SyncConflictReasonStaleVersion {
+		return keepNewerSyncVersion(conflict), nil
+	}
+	if conflict.Direction == SyncDirectionImport {
+		return SyncConflictDecision{Outcome: SyncConflictOutcomeApply, Reason: "source wins"}, nil
+	}
+	return SyncConflictDecision{Outcome: SyncConflictOutcomeKeep, Reason: "source wins"}, nil
+}
+
+func mergeSyncConflictTargetWins(_ context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error) {
+	if conflict.Reason == SyncConflictReasonStaleVersion {
+		return keepNewerSyncVersion(conflict), nil
+	}
+	if conflict.Direction == SyncDirectionExport {
+		return SyncConflictDecision{Outcome: SyncConflictOutcomeApply, Reason: "target wins"}, nil
+	}
+	return SyncConflictDecision{Outcome: SyncConflictOutcomeKeep, Reason: "target wins"}, nil
+}
+
+// mergeSyncConflictThreeWay takes the current value for every field the
+// incoming change left at its base value and keeps the incoming value
+// elsewhere. Fields both sides changed differently need manual resolution.
+func mergeSyncConflictThreeWay(_ context.Context, conflict DetectedSyncConflict) (SyncConflictDecision, error) {
+	if conflict.Reason == SyncConflictReasonStaleVersion {
+		return keepNewerSyncVersion(conflict), nil
+	}
+	if conflict.Base == nil {
+		return SyncConflictDecision{Outcome: SyncConflictOutcomeManual, Reason: "no base version to merge against"}, nil
+	}
+	merged := copySyncPayload(conflict.Change.Payload)
+	var unresolved []string
+	for _, path := range conflict.Fields {
+		segments := strings.Split(path, ".")
+		incoming, _ := syncPayloadLeaf(conflict.Change.Payload, segments)
+		current, _ := syncPayloadLeaf(conflict.Current, segments)
+		base, hasBase := syncPayloadLeaf(conflict.Base, segments)
+		if hasBase && syncValuesEqual(incoming, base) {
+			setSyncPayloadLeaf(merged, segments, current)
+			continue
+		}
+		unresolved = append(unresolved, path)
+	}
+	if len(unresolved) > 0 {
+		return SyncConflictDecision{
+			Outcome: SyncConflictOutcomeManual,
+			Reason:  "both sides changed " + strings.Join(unresolved, ", "),
+		}, nil
+	}
+	return SyncConflictDecision{
+		Outcome: SyncConflictOutcomeApply,
+		Payload: merged,
+		Reason:  "merged " + strings.Join(conflict.Fields, ", "),
+	}, nil
+}
+
+func keepNewerSyncVersion(conflict DetectedSyncConflict) SyncConflictDecision {
+	return SyncConflictDecision{
+		Outcome: SyncConflictOutcomeKeep,
+		Reason:  fmt.Sprintf("version %q was already synced", conflict.CurrentVersion),
+	}
+}
+
+// conflictingSyncFields lists the leaf paths present in both payloads whose
+// values differ, skipping those where current still holds the base value.
+func conflictingSyncFields(incoming, current, base map[string]any) []string {
+	var fields []string
+	for path, currentValue := range flattenSyncPayload(current, "") {
+		segments := strings.Split(path, ".")
+		incomingValue, ok := syncPayloadLeaf(incoming, segments)
+		if !ok || syncValuesEqual(incomingValue, currentValue) {
+			continue
+		}
+		if base != nil {
+			if baseValue, ok := syncPayloadLeaf(base, segments); ok && syncValuesEqual(baseValue, currentValue) {
+				continue
+			}
+		}
+		fields = append(fields, path)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+func flattenSyncPayload(payload map[string]any, prefix string) map[string]any {
+	leaves := make(map[string]any)
+	for key, value := range payload {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			maps.Copy(leaves, flattenSyncPayload(nested, path))
+			continue
+		}
+		leaves[path] = value
+	}
+	return leaves
+}
+
+func syncPayloadLeaf(payload map[string]any, segments []string) (any, bool) {
+	var current any = payload
+	for _, segment := range segments {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func setSyncPayloadLeaf(payload map[string]any, segments []string, value any) {
+	current := payload
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[segment] = next
+		}
+		current = next
+	}
+	current[segments[len(segments)-1]] = value
+}
+
+func copySyncPayload(payload map[string]any) map[string]any {
+	out := make(map[string]any, len(payload))
+	for key, value := range payload {
+		if nested, ok := value.(map[string]any); ok {
+			out[key] = copySyncPayload(nested)
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// syncValuesEqual compares payload values, treating numbers of different Go
+// types as equal when their values match.
+func syncValuesEqual(left, right any) bool {
+	leftNumber, leftOK := syncNumber(left)
+	rightNumber, rightOK := syncNumber(right)
+	if leftOK && rightOK {
+		return leftNumber == rightNumber
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func syncNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed), true
+	case int32:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint:
+		return float64(typed), true
+	case uint32:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	case float32:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	default:
+		return 0, false
+	}
+}
+
+// compareSyncVersions orders versions that are both integers or both RFC 3339
+// timestamps. Other versions are opaque and compare as equal.
+func compareSyncVersions(left, right string) int {
+	leftInt, leftErr := strconv.ParseInt(left, 10, 64)
+	rightInt, rightErr := strconv.ParseInt(right, 10, 64)
+	if leftErr == nil && rightErr == nil {
+		return cmp.Compare(leftInt, rightInt)
+	}
+	leftTime, leftErr := time.Parse(time.RFC3339Nano, left)
+	rightTime, rightErr := time.Parse(time.RFC3339Nano, right)
+	if leftErr == nil && rightErr == nil {
+		return leftTime.Compare(rightTime)
+	}
+	return 0
+}
+
+func syncChangeLogModifiedAt(entry SyncChangeLogEntry) time.Time {
+	if raw, ok := entry.Metadata[syncChangeMetadataModifiedAt].(string); ok {
+		if modifiedAt, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return modifiedAt
+		}
+	}
+	return entry.OccurredAt
+}
+
+func (d SyncDirection) opposite() SyncDirection {
+	if d == SyncDirectionImport {
+		return SyncDirectionExport
+	}
+	return SyncDirectionImport
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type steppingClock struct {
+	now time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.now = c.now.Add(time.Minute)
+	return c.now
+}
+
+func TestSyncExecutionServiceMergesConcurrentChangesThreeWay(t *testing.T) {
+	ctx := context.Background()
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	clock := &steppingClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	conflictStore := newInMemorySyncConflictStore()
+	ledger, err := NewSyncConflictLedgerService(conflictStore)
+	if err != nil {
+		t.Fatalf("new conflict ledger: %v", err)
+	}
+	sink := &recordingSyncTargetSink{}
+	writer := &recordingSyncSourceWriter{}
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		newInMemorySyncChangeLogStore(),
+		WithSyncExecutionClock(clock.Now),
+		WithSyncTargetSink(sink),
+		WithSyncSourceWriter(writer),
+		WithSyncExecutionConflictRecorder(ledger),
+		WithSyncConflictDetection(SyncConflictStrategyThreeWayMerge),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	checkpoint := func(direction SyncDirection) SyncCheckpoint {
+		return SyncCheckpoint{ProviderID: "hubspot", Scope: scope, ConnectionID: "conn_1", Direction: direction}
+	}
+	runImport := func(version string, payload map[string]any) SyncRunResult {
+		t.Helper()
+		result, err := service.RunSyncImport(ctx, RunSyncImportRequest{
+			Plan: SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply, Checkpoint: checkpoint(SyncDirectionImport)},
+			Changes: []SyncChange{{
+				SourceObject: "contacts", ExternalID: "ext_1", SourceVersion: version, Payload: payload,
+			}},
+		})
+		if err != nil {
+			t.Fatalf("run import %s: %v", version, err)
+		}
+		return result
+	}
+	runExport := func(version string, payload map[string]any) SyncRunResult {
+		t.Helper()
+		result, err := service.RunSyncExport(ctx, RunSyncExportRequest{
+			Plan: SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply, Checkpoint: checkpoint(SyncDirectionExport)},
+			Changes: []SyncChange{{
+				SourceObject: "contacts", ExternalID: "ext_1", SourceVersion: version, Payload: payload,
+			}},
+		})
+		if err != nil {
+			t.Fatalf("run export %s: %v", version, err)
+		}
+		return result
+	}
+
+	runImport("1", map[string]any{"name": "Ada", "phone": "1"})
+	if result := runExport("e1", map[string]any{"name": "Ada", "phone": "2"}); result.ProcessedCount != 1 {
+		t.Fatalf("expected first export without conflict, got %+v", result)
+	}
+
+	merged := runImport("2", map[string]any{"name": "Ada L", "phone": "1"})
+	if merged.ProcessedCount != 1 || merged.ResolvedConflictCount != 1 || merged.ConflictCount != 0 {
+		t.Fatalf("expected auto-merged import, got %+v", merged)
+	}
+	if want := map[string]any{"name": "Ada L", "phone": "2"}; !reflect.DeepEqual(sink.records[len(sink.records)-1].Output, want) {
+		t.Fatalf("expected merged payload %v, got %v", want, sink.records[len(sink.records)-1].Output)
+	}
+	resolved, _ := conflictStore.ListByBinding(ctx, "hubspot", scope, "binding_1", SyncConflictStatusResolved)
+	if len(resolved) != 1 || resolved[0].Reason != SyncConflictReasonConcurrentModification ||
+		resolved[0].Policy != string(SyncConflictStrategyThreeWayMerge) {
+		t.Fatalf("expected resolved concurrent conflict, got %+v", resolved)
+	}
+
+	stale := runImport("1", map[string]any{"name": "Ada", "phone": "1"})
+	if stale.SkippedCount != 1 || stale.ResolvedConflictCount != 1 || len(sink.records) != 2 {
+		t.Fatalf("expected stale version to be skipped, got %+v", stale)
+	}
+
+	manual := runExport("e2", map[string]any{"name": "Ada X", "phone": "2"})
+	if manual.ConflictCount != 1 || manual.ProcessedCount != 0 || len(writer.records) != 1 {
+		t.Fatalf("expected field changed on both sides to need manual resolution, got %+v", manual)
+	}
+	pending, _ := conflictStore.ListByBinding(ctx, "hubspot", scope, "binding_1", SyncConflictStatusPending)
+	if len(pending) != 1 || !reflect.DeepEqual(pending[0].Metadata["fields"], []string{"name"}) {
+		t.Fatalf("expected pending conflict on name, got %+v", pending)
+	}
+}
+
+func TestSyncExecutionServiceAppliesBindingConflictStrategy(t *testing.T) {
+	ctx := context.Background()
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	specs := newInMemoryMappingSpecStore()
+	if _, err := specs.CreateDraft(ctx, MappingSpec{
+		ID: "spec_row_1", SpecID: "spec_contacts", ProviderID: "hubspot", Scope: scope, Version: 1,
+		Status: MappingSpecStatusPublished, Name: "contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+		Rules: []MappingRule{
+			{ID: "email", SourcePath: "properties.email", TargetPath: "contact.email"},
+			{ID: "phone", SourcePath: "properties.phone", TargetPath: "contact.phone"},
+		},
+	}); err != nil {
+		t.Fatalf("seed spec: %v", err)
+	}
+	bindings := &staticSyncBindingStore{bindings: map[string]SyncBinding{
+		"binding_1": {
+			ID: "binding_1", ProviderID: "hubspot", Scope: scope, ConnectionID: "conn_1",
+			MappingSpecID: "spec_contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+			Direction: SyncDirectionBidirectional, Status: SyncBindingStatusActive,
+			Metadata: map[string]any{SyncBindingMetadataConflictStrategy: "last_writer_wins"},
+		},
+	}}
+	changeLog := newInMemorySyncChangeLogStore()
+	if _, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		struct{ SyncChangeLogStore }{changeLog},
+		WithSyncConflictDetection(SyncConflictStrategyManual),
+	); err == nil {
+		t.Fatalf("expected conflict detection to require a change log reader")
+	}
+	if _, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		changeLog,
+		WithSyncConflictDetection("coin_flip"),
+	); err == nil {
+		t.Fatalf("expected unknown strategy to be rejected")
+	}
+
+	clock := &steppingClock{now: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	sink := &recordingSyncTargetSink{}
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		changeLog,
+		WithSyncExecutionClock(clock.Now),
+		WithSyncExecutionMappings(bindings, specs),
+		WithSyncTargetSink(sink),
+		WithSyncSourceWriter(&recordingSyncSourceWriter{}),
+		WithSyncConflictDetection(SyncConflictStrategyManual),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	modifiedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	importChange := func(version string, phone string, modifiedAt time.Time) SyncRunResult {
+		t.Helper()
+		result, err := service.RunSyncImport(ctx, RunSyncImportRequest{
+			Plan: SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply},
+			Changes: []SyncChange{{
+				SourceObject: "contacts", ExternalID: "ext_1", SourceVersion: version, ModifiedAt: modifiedAt,
+				Payload: map[string]any{"properties": map[string]any{"email": "a@example.com", "phone": phone}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("run import: %v", err)
+		}
+		return result
+	}
+
+	importChange("1", "1", modifiedAt)
+	if _, err := service.RunSyncExport(ctx, RunSyncExportRequest{
+		Plan: SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{{
+			SourceObject: "crm_contacts", ExternalID: "ext_1", SourceVersion: "e1", ModifiedAt: modifiedAt.Add(10 * time.Minute),
+			Payload: map[string]any{"contact": map[string]any{"email": "a@example.com", "phone": "2"}},
+		}},
+	}); err != nil {
+		t.Fatalf("run export: %v", err)
+	}
+
+	older := importChange("2", "3", modifiedAt.Add(5*time.Minute))
+	if older.SkippedCount != 1 || older.ResolvedConflictCount != 1 || len(sink.records) != 1 {
+		t.Fatalf("expected newer exported phone to win, got %+v", older)
+	}
+	newer := importChange("3", "4", modifiedAt.Add(15*time.Minute))
+	if newer.ProcessedCount != 1 || newer.ResolvedConflictCount != 1 || len(sink.records) != 2 {
+		t.Fatalf("expected newer import to win, got %+v", newer)
+	}
+	contact, _ := sink.records[1].Output["contact"].(map[string]any)
+	if contact["phone"] != "4" {
+		t.Fatalf("expected newer phone to be applied, got %+v", sink.records[1].Output)
+	}
+}
@@ -0,0 +1,361 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// syncRunTarget carries what a run needs to map and deliver each change.
+type syncRunTarget struct {
+	binding  *SyncBinding
+	spec     *MappingSpec
+	compiled *CompiledMappingSpec
+	// counterpart maps payloads logged by the opposite direction of a
+	// bidirectional binding into the shape of this run's changes.
+	counterpart *CompiledMappingSpec
+	deliver     func(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+	// remove delivers deletes when the sink implements the direction's
+	// deleter interface.
+	remove    func(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error)
+	retention time.Duration
+}
+
+type syncRecordDisposition int
+
+const (
+	syncRecordDelivered syncRecordDisposition = iota
+	syncRecordFailed
+	syncRecordConflicted
+	syncRecordSkipped
+)
+
+func (s *SyncExecutionService) resolveSyncRunTarget(
+	ctx context.Context,
+	bindingID string,
+	direction SyncDirection,
+) (*syncRunTarget, error) {
+	target := &syncRunTarget{retention: s.tombstoneRetention}
+	switch direction {
+	case SyncDirectionImport:
+		if s.targetSink != nil {
+			target.deliver = s.targetSink.ApplySyncRecord
+		}
+		if deleter, ok := s.targetSink.(SyncTargetDeleter); ok {
+			target.remove = deleter.DeleteSyncRecord
+		}
+	case SyncDirectionExport:
+		if s.sourceWriter != nil {
+			target.deliver = s.sourceWriter.WriteSyncRecord
+		}
+		if deleter, ok := s.sourceWriter.(SyncSourceDeleter); ok {
+			target.remove = deleter.DeleteSourceRecord
+		}
+	}
+	if s.bindingStore == nil {
+		if target.deliver == nil {
+			return nil, nil
+		}
+		return target, nil
+	}
+
+	binding, err := s.bindingStore.Get(ctx, bindingID)
+	if err != nil {
+		return nil, fmt.Errorf("core: load sync binding %q: %w", bindingID, err)
+	}
+	if !binding.Direction.Allows(direction) {
+		return nil, fmt.Errorf(
+			"core: sync binding %q direction %q does not match run direction %q",
+			bindingID,
+			binding.Direction,
+			direction,
+		)
+	}
+	target.binding = &binding
+	target.retention, err = syncBindingTombstoneRetention(binding, s.tombstoneRetention)
+	if err != nil {
+		return nil, err
+	}
+	if s.mappingSpecStore == nil {
+		return target, nil
+	}
+
+	spec, err := resolvePublishedMappingSpec(ctx, s.mappingSpecStore, binding)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileMappingSpecForExecution(spec)
+	if err != nil {
+		return nil, err
+	}
+	if binding.Direction == SyncDirectionBidirectional {
+		inverse, issues, err := invertCompiledMappingSpec(compiled)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case direction == SyncDirectionImport:
+			// Imports only use the inverse to compare against exported
+			// payloads; without one, concurrent changes go undetected.
+			if !containsMappingErrors(issues) {
+				target.counterpart = &inverse
+			}
+		case containsMappingErrors(issues):
+			return nil, fmt.Errorf(
+				"core: mapping spec %s version %d cannot be inverted for export: %w",
+				spec.SpecID,
+				spec.Version,
+				mappingIssuesError(issues),
+			)
+		default:
+			forward := compiled
+			target.counterpart = &forward
+			compiled = inverse
+		}
+	}
+	target.spec = &spec
+	target.compiled = &compiled
+	return target, nil
+}
+
+// resolvePublishedMappingSpec finds the published version of the spec the
+// binding references. MappingSpecID may name either a stored spec version or
+// the logical spec id; in both cases the currently published version wins.
+func resolvePublishedMappingSpec(
+	ctx context.Context,
+	store MappingSpecStore,
+	binding SyncBinding,
+) (MappingSpec, error) {
+	reference := strings.TrimSpace(binding.MappingSpecID)
+	specs, err := store.ListByScope(ctx, binding.ProviderID, binding.Scope)
+	if err != nil {
+		return MappingSpec{}, err
+	}
+	specID := ""
+	for _, spec := range specs {
+		if spec.ID == reference {
+			specID = spec.SpecID
+			break
+		}
+	}
+	if specID == "" {
+		specID = reference
+	}
+	for _, spec := range specs {
+		if spec.SpecID == specID && spec.Status == MappingSpecStatusPublished {
+			return spec, nil
+		}
+	}
+	return MappingSpec{}, fmt.Errorf(
+		"core: no published mapping spec %q for sync binding %q",
+		reference,
+		binding.ID,
+	)
+}
+
+// compileMappingSpecForExecution compiles a published spec without an
+// external schema. Rules were type-checked against the schema before publish,
+// so only the transforms are re-checked here and expressions compile with
+// dynamic record types.
+func compileMappingSpecForExecution(spec MappingSpec) (CompiledMappingSpec, error) {
+	spec = normalizeMappingSpec(spec)
+	if spec.Status != MappingSpecStatusPublished {
+		return CompiledMappingSpec{}, fmt.Errorf(
+			"core: mapping spec %s version %d is not published",
+			spec.SpecID,
+			spec.Version,
+		)
+	}
+	rules := make([]CompiledMappingRule, 0, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		rule = normalizeMappingRule(rule)
+		source, err := parseMappingPath(rule.SourcePath)
+		if err != nil {
+			return CompiledMappingSpec{}, fmt.Errorf("core: rule %q: %w", rule.ID, err)
+		}
+		target, err := parseMappingPath(rule.TargetPath)
+		if err != nil {
+			return CompiledMappingSpec{}, fmt.Errorf("core: rule %q: %w", rule.ID, err)
+		}
+		compiledRule := CompiledMappingRule{
+			Rule:       rule,
+			SourceType: resolveSourceType(rule),
+			TargetType: resolveTargetType(rule),
+			Transform:  rule.Transform,
+			paths:      &mappingRulePaths{source: source, target: target},
+		}
+		if source, ok := mappingExpressionSource(rule.Transform); ok {
+			expression, err := compileMappingExpression(source, nil, nil)
+			if err != nil {
+				return CompiledMappingSpec{}, fmt.Errorf("core: rule %q: %w", rule.ID, err)
+			}
+			compiledRule.expression = expression
+		} else if !isSupportedMappingTransform(rule.Transform) {
+			return CompiledMappingSpec{}, fmt.Errorf("core: unsupported transform %q", rule.Transform)
+		}
+		rules = append(rules, compiledRule)
+	}
+	sortCompiledMappingRules(rules)
+	compiled := CompiledMappingSpec{
+		SpecID:       spec.SpecID,
+		Version:      spec.Version,
+		SourceObject: spec.SourceObject,
+		Rules:        rules,
+	}
+	hash, err := mappingCompiledHash(compiled)
+	if err != nil {
+		return CompiledMappingSpec{}, err
+	}
+	compiled.DeterministicHash = hash
+	return compiled, nil
+}
+
+// mapSyncChange applies the run target's compiled mapping to a change. Without
+// a mapping the payload is passed through unchanged, as it is for deletes and
+// for restores that carry no payload.
+func (t *syncRunTarget) mapSyncChange(
+	runID string,
+	direction SyncDirection,
+	change SyncChange,
+	idempotencyKey string,
+	runMetadata map[string]any,
+) SyncMappedRecord {
+	record := SyncMappedRecord{
+		RunID:          runID,
+		Direction:      direction,
+		Operation:      change.Operation,
+		SourceObject:   change.SourceObject,
+		ExternalID:     change.ExternalID,
+		SourceVersion:  change.SourceVersion,
+		IdempotencyKey: idempotencyKey,
+		Input:          copyMetadata(change.Payload),
+		Metadata:       mergeMetadata(change.Metadata, runMetadata),
+	}
+	if t.binding != nil {
+		record.Binding = *t.binding
+	}
+	unmapped := change.Operation == SyncChangeOperationDelete ||
+		(change.Operation == SyncChangeOperationRestore && len(change.Payload) == 0)
+	if t.compiled == nil || unmapped {
+		record.Output = copyMetadata(change.Payload)
+		return record
+	}
+	record.MappingSpecID = t.spec.ID
+	record.MappingVersion = t.compiled.Version
+	record.Output, _, record.Issues = applyCompiledMappingRules(t.compiled.Rules, change.Payload, "sync")
+	return record
+}
+
+// deliverSyncRecord maps and delivers one change. Failures and conflicts are
+// recorded on the result instead of aborting the run.
+func (s *SyncExecutionService) deliverSyncRecord(
+	ctx context.Context,
+	target *syncRunTarget,
+	result *SyncRunResult,
+	checkpoint SyncCheckpoint,
+	direction SyncDirection,
+	change SyncChange,
+	idempotencyKey string,
+	runMetadata map[string]any,
+) syncRecordDisposition {
+	record := target.mapSyncChange(result.RunID, direction, change, idempotencyKey, runMetadata)
+	if containsMappingErrors(record.Issues) {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change, mappingIssuesError(record.Issues))
+		return syncRecordFailed
+	}
+	deliver := target.deliver
+	if record.Operation.isTombstone() {
+		if err := s.prepareSyncTombstone(ctx, target, &record, checkpoint.SyncBindingID); err != nil {
+			s.recordSyncRecordFailure(ctx, result, checkpoint, change, err)
+			return syncRecordFailed
+		}
+		if record.Operation == SyncChangeOperationDelete && deliver != nil {
+			if target.remove == nil {
+				s.recordSyncRecordFailure(ctx, result, checkpoint, change, fmt.Errorf("core: sync sink does not handle deletes"))
+				return syncRecordFailed
+			}
+			deliver = target.remove
+		}
+	}
+	if deliver == nil {
+		return syncRecordDelivered
+	}
+
+	sinkResult, err := deliver(ctx, record)
+	if err != nil {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change, err)
+		return syncRecordFailed
+	}
+	if sinkResult.Conflict == nil {
+		return syncRecordDelivered
+	}
+
+	result.ConflictCount++
+	if s.conflictRecorder == nil {
+		return syncRecordConflicted
+	}
+	reason := strings.TrimSpace(sinkResult.Conflict.Reason)
+	if reason == "" {
+		reason = "sink_conflict"
+	}
+	if _, err := s.conflictRecorder.RecordSyncConflict(ctx, RecordSyncConflictRequest{
+		Conflict: SyncConflict{
+			ProviderID:     checkpoint.ProviderID,
+			Scope:          checkpoint.Scope,
+			ConnectionID:   checkpoint.ConnectionID,
+			SyncBindingID:  checkpoint.SyncBindingID,
+			CheckpointID:   checkpoint.ID,
+			SourceObject:   change.SourceObject,
+			ExternalID:     change.ExternalID,
+			SourceVersion:  change.SourceVersion,
+			IdempotencyKey: idempotencyKey,
+			Policy:         sinkResult.Conflict.Policy,
+			Reason:         reason,
+			Status:         SyncConflictStatusPending,
+			SourcePayload:  RedactSensitiveMap(record.Output),
+			TargetPayload:  RedactSensitiveMap(sinkResult.Conflict.TargetPayload),
+			Metadata:       mergeMetadata(sinkResult.Conflict.Metadata, map[string]any{"run_id": result.RunID}),
+		},
+		Metadata: runMetadata,
+	}); err != nil {
+		s.recordSyncRecordFailure(ctx, result, checkpoint, change, fmt.Errorf("core: record sync conflict: %w", err))
+		return syncRecordFailed
+	}
+	return syncRecordConflicted
+}
+
+func (s *SyncExecutionService) recordSyncRecordFailure(
+	ctx context.Context,
+	result *SyncRunResult,
+	checkpoint SyncCheckpoint,
+	change SyncChange,
+	cause error,
+) {
+	result.FailedCount++
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]any)
+	}
+	failures, _ := result.Metadata["failed_records"].([]map[string]any)
+	result.Metadata["failed_records"] = append(failures, map[string]any{
+		"external_id":    change.ExternalID,
+		"source_version": change.SourceVersion,
+		"error":          cause.Error(),
+	})
+	_ = s.publishSyncRunEvent(ctx, result.RunID, checkpoint, "services.sync.run.record_failed", map[string]any{
+		"status":         string(SyncRunStatusRunning),
+		"external_id":    change.ExternalID,
+		"source_version": change.SourceVersion,
+		"error":          cause.Error(),
+	})
+}
+
+func mappingIssuesError(issues []MappingValidationIssue) error {
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Severity == MappingValidationIssueError {
+			messages = append(messages, issue.Message)
+		}
+	}
+	return fmt.Errorf("core: mapping failed: %s", strings.Join(messages, "; "))
+}
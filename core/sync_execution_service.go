@@ -30,11 +30,63 @@ func WithSyncExecutionEventBus(bus LifecycleEventBus) SyncExecutionServiceOption
 	}
 }
 
+// WithSyncExecutionMappings loads each run's binding and applies the
+// published mapping spec it references before records reach a sink.
+func WithSyncExecutionMappings(bindings SyncBindingStore, specs MappingSpecStore) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.bindingStore = bindings
+		s.mappingSpecStore = specs
+	}
+}
+
+func WithSyncTargetSink(sink SyncTargetSink) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.targetSink = sink
+	}
+}
+
+func WithSyncSourceWriter(writer SyncSourceWriter) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.sourceWriter = writer
+	}
+}
+
+// WithSyncExecutionConflictRecorder routes conflicts reported by sinks into
+// the conflict ledger, typically a SyncConflictLedgerService.
+func WithSyncExecutionConflictRecorder(recorder SyncConflictRecorder) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.conflictRecorder = recorder
+	}
+}
+
 type SyncExecutionService struct {
-	checkpointStore SyncCheckpointStore
-	changeLogStore  SyncChangeLogStore
-	eventBus        LifecycleEventBus
-	now             func() time.Time
+	checkpointStore  SyncCheckpointStore
+	changeLogStore   SyncChangeLogStore
+	bindingStore     SyncBindingStore
+	mappingSpecStore MappingSpecStore
+	targetSink       SyncTargetSink
+	sourceWriter     SyncSourceWriter
+	conflictRecorder SyncConflictRecorder
+	conflictStrategy SyncConflictStrategy
+	conflictMergers  map[SyncConflictStrategy]SyncConflictMerger
+	changeLogReader  SyncChangeLogReader
+	// identityBindings and tombstoneRetention serve deletes and restores.
+	identityBindings   IdentityBindingStore
+	tombstoneRetention time.Duration
+	eventBus           LifecycleEventBus
+	now                func() time.Time
 }
 
 func NewSyncExecutionService(
@@ -59,6 +111,9 @@ func NewSyncExecutionService(
 			opt(svc)
 		}
 	}
+	if err := svc.configureSyncConflictDetection(); err != nil {
+		return nil, err
+	}
 	return svc, nil
 }
 
@@ -109,6 +164,22 @@ func (s *SyncExecutionService) run(
 		return SyncRunResult{}, fmt.Errorf("core: checkpoint direction %q does not match run direction %q", checkpoint.Direction, direction)
 	}
 
+	target, err := s.resolveSyncRunTarget(ctx, plan.BindingID, direction)
+	if err != nil {
+		return SyncRunResult{}, err
+	}
+	if target != nil && target.binding != nil {
+		if checkpoint.ProviderID == "" {
+			checkpoint.ProviderID = target.binding.ProviderID
+		}
+		if checkpoint.Scope.Type == "" && checkpoint.Scope.ID == "" {
+			checkpoint.Scope = target.binding.Scope
+		}
+		if checkpoint.ConnectionID == "" {
+			checkpoint.ConnectionID = target.binding.ConnectionID
+		}
+	}
+
 	sequence := checkpoint.Sequence
 	result := SyncRunResult{
 		RunID:    strings.TrimSpace(plan.ID),
@@ -126,17 +197,22 @@ func (s *SyncExecutionService) run(
 		return SyncRunResult{}, publishErr
 	}
 
+	// held is the last checkpoint before the first failed record. Once set,
+	// later records are still delivered but the checkpoint no longer moves,
+	// so the next run resumes at the failure and delivers it again.
+	var held *SyncCheckpoint
 	for _, change := range changes {
+		committed := checkpoint
 		change = normalizeSyncChange(change)
-		if change.ExternalID == "" {
+		if err := validateSyncChange(change); err != nil {
 			result.Status = SyncRunStatusFailed
 			result.FailedCount++
 			result.NextCheckpoint = &checkpoint
 			_ = s.publishSyncRunEvent(ctx, result.RunID, checkpoint, "services.sync.run.failed", map[string]any{
 				"status": string(result.Status),
-				"error":  "core: sync change external id is required",
+				"error":  err.Error(),
 			})
-			return result, fmt.Errorf("core: sync change external id is required")
+			return result, err
 		}
 
 		sequence++
@@ -155,45 +231,97 @@ func (s *SyncExecutionService) run(
 			plan.BindingID,
 			direction,
 			change.ExternalID,
-			change.SourceVersion,
+			syncChangeVersion(change),
 		)
-		entry := SyncChangeLogEntry{
-			ProviderID:     checkpoint.ProviderID,
-			Scope:          checkpoint.Scope,
-			ConnectionID:   checkpoint.ConnectionID,
-			SyncBindingID:  plan.BindingID,
-			Direction:      direction,
-			SourceObject:   change.SourceObject,
-			ExternalID:     change.ExternalID,
-			SourceVersion:  change.SourceVersion,
-			IdempotencyKey: idempotencyKey,
-			Payload:        RedactSensitiveMap(change.Payload),
-			Metadata:       RedactSensitiveMap(mergeMetadata(change.Metadata, runMetadata)),
-			OccurredAt:     s.now(),
+		disposition := syncRecordDelivered
+		if s.changeLogReader != nil {
+			disposition, change = s.settleSyncConflict(
+				ctx,
+				target,
+				&result,
+				checkpoint,
+				direction,
+				change,
+				idempotencyKey,
+				runMetadata,
+			)
+		}
+		if disposition == syncRecordDelivered && target != nil {
+			disposition = s.deliverSyncRecord(
+				ctx,
+				target,
+				&result,
+				checkpoint,
+				direction,
+				change,
+				idempotencyKey,
+				runMetadata,
+			)
+		}
+		if disposition == syncRecordDelivered {
+			if err := s.orphanSyncIdentity(ctx, plan.BindingID, change); err != nil {
+				s.recordSyncRecordFailure(ctx, &result, checkpoint, change, err)
+				disposition = syncRecordFailed
+			}
 		}
 
-		applied, appendErr := s.changeLogStore.Append(ctx, entry)
-		if appendErr != nil {
-			result.Status = SyncRunStatusFailed
-			result.FailedCount++
-			result.NextCheckpoint = &checkpoint
-			_ = s.publishSyncRunEvent(ctx, result.RunID, checkpoint, "services.sync.run.failed", map[string]any{
-				"status": string(result.Status),
-				"error":  appendErr.Error(),
-			})
-			return result, appendErr
+		// Failed and conflicted records stay out of the change log so a
+		// later run can deliver them again; conflicts wait in the ledger and
+		// failures hold the checkpoint. Skipped records lost a conflict to a
+		// newer version and are not logged either.
+		if disposition == syncRecordFailed && held == nil {
+			held = &committed
 		}
-		if applied {
-			result.ProcessedCount++
-		} else {
+		if disposition == syncRecordSkipped {
 			result.SkippedCount++
 		}
+		if disposition == syncRecordDelivered {
+			metadata := mergeMetadata(change.Metadata, runMetadata)
+			if !change.ModifiedAt.IsZero() {
+				metadata[syncChangeMetadataModifiedAt] = change.ModifiedAt.UTC().Format(time.RFC3339Nano)
+			}
+			entry := SyncChangeLogEntry{
+				ProviderID:     checkpoint.ProviderID,
+				Scope:          checkpoint.Scope,
+				ConnectionID:   checkpoint.ConnectionID,
+				SyncBindingID:  plan.BindingID,
+				Direction:      direction,
+				Operation:      change.Operation,
+				SourceObject:   change.SourceObject,
+				ExternalID:     change.ExternalID,
+				SourceVersion:  change.SourceVersion,
+				IdempotencyKey: idempotencyKey,
+				Payload:        RedactSensitiveMap(change.Payload),
+				Metadata:       RedactSensitiveMap(metadata),
+				OccurredAt:     s.now(),
+			}
 
+			applied, appendErr := s.changeLogStore.Append(ctx, entry)
+			if appendErr != nil {
+				result.Status = SyncRunStatusFailed
+				result.FailedCount++
+				result.NextCheckpoint = resumeSyncCheckpoint(held, committed)
+				_ = s.publishSyncRunEvent(ctx, result.RunID, checkpoint, "services.sync.run.failed", map[string]any{
+					"status": string(result.Status),
+					"error":  appendErr.Error(),
+				})
+				return result, appendErr
+			}
+			if applied {
+				result.ProcessedCount++
+			} else {
+				result.SkippedCount++
+			}
+		}
+
+		if held != nil {
+			continue
+		}
 		savedCheckpoint, saveErr := s.checkpointStore.Save(ctx, checkpoint)
 		if saveErr != nil {
 			result.Status = SyncRunStatusFailed
 			result.FailedCount++
-			result.NextCheckpoint = &checkpoint
+			result.NextCheckpoint = &committed
 			_ = s.publishSyncRunEvent(ctx, result.RunID, checkpoint, "services.sync.run.failed", map[string]any{
 				"status": string(result.Status),
 				"error":  saveErr.Error(),
@@ -216,16 +344,24 @@ func (s *SyncExecutionService) run(
 		}
 	}
 
+	checkpoint = *resumeSyncCheckpoint(held, checkpoint)
 	result.NextCheckpoint = &checkpoint
+	// A run with failed records finishes as partial so callers and the
+	// lifecycle outbox can tell it from a clean run.
+	if result.FailedCount > 0 {
+		result.Status = SyncRunStatusPartial
+	}
 	if publishErr := s.publishSyncRunEvent(
 		ctx,
 		result.RunID,
 		checkpoint,
-		"services.sync.run.succeeded",
+		"services.sync.run."+string(result.Status),
 		map[string]any{
-			"status":    string(SyncRunStatusSucceeded),
+			"status":    string(result.Status),
 			"processed": result.ProcessedCount,
 			"skipped":   result.SkippedCount,
+			"conflicts": result.ConflictCount,
+			"resolved":  result.ResolvedConflictCount,
 			"failed":    result.FailedCount,
 			"sequence":  checkpoint.Sequence,
 		},
@@ -255,16 +391,39 @@ func BuildSyncIdempotencyKey(
 	return hex.EncodeToString(digest[:])
 }
 
+// resumeSyncCheckpoint returns the checkpoint the next run should start from:
+// the held checkpoint when a record failed, otherwise current.
+func resumeSyncCheckpoint(held *SyncCheckpoint, current SyncCheckpoint) *SyncCheckpoint {
+	if held != nil {
+		current = *held
+	}
+	return &current
+}
+
 func normalizeSyncChange(change SyncChange) SyncChange {
 	change.SourceObject = strings.TrimSpace(change.SourceObject)
 	change.ExternalID = strings.TrimSpace(change.ExternalID)
 	change.SourceVersion = strings.TrimSpace(change.SourceVersion)
+	change.Operation = SyncChangeOperation(strings.TrimSpace(strings.ToLower(string(change.Operation))))
+	if change.Operation == "" {
+		change.Operation = SyncChangeOperationUpdate
+	}
 	if change.Payload == nil {
 		change.Payload = make(map[string]any)
 	}
 	return change
 }
 
+func validateSyncChange(change SyncChange) error {
+	if change.ExternalID == "" {
+		return fmt.Errorf("core: sync change external id is required")
+	}
+	if !change.Operation.IsValid() {
+		return fmt.Errorf("core: invalid sync change operation %q", change.Operation)
+	}
+	return nil
+}
+
 func mergeMetadata(left map[string]any, right map[string]any) map[string]any {
 	size := len(left) + len(right)
 	if size == 0 {
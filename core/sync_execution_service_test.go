@@ -119,6 +119,26 @@ func (s *inMemorySyncChangeLogStore) ListSince(
 	return out, "", nil
 }
 
+func (s *inMemorySyncChangeLogStore) GetLatest(
+	ctx context.Context,
+	syncBindingID string,
+	direction SyncDirection,
+	externalID string,
+) (SyncChangeLogEntry, bool, error) {
+	var latest SyncChangeLogEntry
+	found := false
+	for _, entry := range s.entries {
+		if entry.SyncBindingID != syncBindingID || entry.Direction != direction || entry.ExternalID != externalID {
+			continue
+		}
+		if !found || entry.OccurredAt.After(latest.OccurredAt) {
+			latest = entry
+			found = true
+		}
+	}
+	return latest, found, nil
+}
+
 func TestSyncExecutionServiceRunSyncImportSourceVersionAwareIdempotency(t *testing.T) {
 	checkpointStore := newInMemorySyncCheckpointStore()
 	changeLogStore := newInMemorySyncChangeLogStore()
@@ -323,3 +343,266 @@ func TestSyncExecutionServiceEmitsLifecycleEvents(t *testing.T) {
 		t.Fatalf("unexpected third event name %q", eventBus.events[2].Name)
 	}
 }
+
+type staticSyncBindingStore struct {
+	bindings map[string]SyncBinding
+}
+
+func (s *staticSyncBindingStore) Upsert(ctx context.Context, binding SyncBinding) (SyncBinding, error) {
+	s.bindings[binding.ID] = binding
+	return binding, nil
+}
+
+func (s *staticSyncBindingStore) Get(ctx context.Context, id string) (SyncBinding, error) {
+	binding, ok := s.bindings[id]
+	if !ok {
+		return SyncBinding{}, fmt.Errorf("binding not found")
+	}
+	return binding, nil
+}
+
+func (s *staticSyncBindingStore) ListByConnection(ctx context.Context, connectionID string) ([]SyncBinding, error) {
+	return nil, nil
+}
+
+func (s *staticSyncBindingStore) UpdateStatus(ctx context.Context, id string, status SyncBindingStatus, reason string) error {
+	return nil
+}
+
+type recordingSyncTargetSink struct {
+	records []SyncMappedRecord
+}
+
+func (s *recordingSyncTargetSink) ApplySyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error) {
+	s.records = append(s.records, record)
+	switch record.ExternalID {
+	case "ext_fail":
+		return SyncSinkResult{}, fmt.Errorf("target unavailable")
+	case "ext_conflict":
+		return SyncSinkResult{Conflict: &SyncSinkConflict{
+			Reason:        "target_newer",
+			TargetPayload: map[string]any{"email": "newer@example.com"},
+		}}, nil
+	default:
+		return SyncSinkResult{InternalID: "ct_" + record.ExternalID}, nil
+	}
+}
+
+func TestSyncExecutionServiceAppliesPublishedMappingAndRoutesSinkOutcomes(t *testing.T) {
+	ctx := context.Background()
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	specs := newInMemoryMappingSpecStore()
+	for _, spec := range []MappingSpec{
+		{
+			ID: "spec_row_1", SpecID: "spec_contacts", ProviderID: "hubspot", Scope: scope, Version: 1,
+			Status: MappingSpecStatusValidated, Name: "contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+			Rules: []MappingRule{{ID: "email", SourcePath: "email", TargetPath: "contact.email"}},
+		},
+		{
+			ID: "spec_row_2", SpecID: "spec_contacts", ProviderID: "hubspot", Scope: scope, Version: 2,
+			Status: MappingSpecStatusPublished, Name: "contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+			Rules: []MappingRule{
+				{ID: "email", SourcePath: "properties.email", TargetPath: "contact.email", Transform: "lowercase"},
+				{ID: "age", SourcePath: "properties.age", TargetPath: "contact.age", Transform: "to_int"},
+			},
+		},
+	} {
+		if _, err := specs.CreateDraft(ctx, spec); err != nil {
+			t.Fatalf("seed spec: %v", err)
+		}
+	}
+	bindings := &staticSyncBindingStore{bindings: map[string]SyncBinding{
+		"binding_1": {
+			ID: "binding_1", ProviderID: "hubspot", Scope: scope, ConnectionID: "conn_1",
+			MappingSpecID: "spec_row_1", SourceObject: "contacts", TargetModel: "crm_contacts",
+			Direction: SyncDirectionImport, Status: SyncBindingStatusActive,
+		},
+	}}
+	conflictStore := newInMemorySyncConflictStore()
+	conflicts, err := NewSyncConflictLedgerService(conflictStore)
+	if err != nil {
+		t.Fatalf("new conflict ledger: %v", err)
+	}
+	sink := &recordingSyncTargetSink{}
+	changeLogStore := newInMemorySyncChangeLogStore()
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		changeLogStore,
+		WithSyncExecutionMappings(bindings, specs),
+		WithSyncTargetSink(sink),
+		WithSyncExecutionConflictRecorder(conflicts),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+
+	result, err := service.RunSyncImport(ctx, RunSyncImportRequest{
+		Plan: SyncRunPlan{ID: "run_mapped", BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{
+			{SourceObject: "contacts", ExternalID: "ext_ok", SourceVersion: "1", Payload: map[string]any{
+				"properties": map[string]any{"email": "A@Example.com", "age": "42"},
+			}},
+			{SourceObject: "contacts", ExternalID: "ext_bad_age", SourceVersion: "1", Payload: map[string]any{
+				"properties": map[string]any{"email": "b@example.com", "age": "unknown"},
+			}},
+			{SourceObject: "contacts", ExternalID: "ext_fail", SourceVersion: "1", Payload: map[string]any{
+				"properties": map[string]any{"email": "c@example.com", "age": 3},
+			}},
+			{SourceObject: "contacts", ExternalID: "ext_conflict", SourceVersion: "1", Payload: map[string]any{
+				"properties": map[string]any{"email": "d@example.com", "age": 4},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("run sync import: %v", err)
+	}
+	if result.ProcessedCount != 1 || result.FailedCount != 2 || result.ConflictCount != 1 {
+		t.Fatalf("unexpected run counts %+v", result)
+	}
+	if result.NextCheckpoint == nil || result.NextCheckpoint.Sequence != 1 || result.NextCheckpoint.ConnectionID != "conn_1" {
+		t.Fatalf("expected checkpoint to hold before the first failed record, got %+v", result.NextCheckpoint)
+	}
+	failures, _ := result.Metadata["failed_records"].([]map[string]any)
+	if len(failures) != 2 || failures[0]["external_id"] != "ext_bad_age" || failures[1]["external_id"] != "ext_fail" {
+		t.Fatalf("unexpected failed records %+v", failures)
+	}
+
+	if len(sink.records) != 3 {
+		t.Fatalf("expected sink to receive three mapped records, got %d", len(sink.records))
+	}
+	first := sink.records[0]
+	if first.MappingSpecID != "spec_row_2" || first.MappingVersion != 2 || first.Binding.ID != "binding_1" {
+		t.Fatalf("expected published v2 mapping to be applied, got %+v", first)
+	}
+	contact, _ := first.Output["contact"].(map[string]any)
+	if contact["email"] != "a@example.com" || contact["age"] != int64(42) {
+		t.Fatalf("unexpected mapped output %+v", first.Output)
+	}
+
+	if len(changeLogStore.entries) != 1 {
+		t.Fatalf("expected only delivered records in change log, got %d", len(changeLogStore.entries))
+	}
+	pending, err := conflictStore.ListByBinding(ctx, "hubspot", scope, "binding_1", SyncConflictStatusPending)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected one pending conflict, got %d err=%v", len(pending), err)
+	}
+	if pending[0].ExternalID != "ext_conflict" || pending[0].Reason != "target_newer" ||
+		pending[0].TargetPayload["email"] != "newer@example.com" {
+		t.Fatalf("unexpected recorded conflict %+v", pending[0])
+	}
+}
+
+type flakySyncTargetSink struct {
+	failures map[string]int
+	records  []SyncMappedRecord
+}
+
+func (s *flakySyncTargetSink) ApplySyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error) {
+	s.records = append(s.records, record)
+	if s.failures[record.ExternalID] > 0 {
+		s.failures[record.ExternalID]--
+		return SyncSinkResult{}, fmt.Errorf("target unavailable")
+	}
+	return SyncSinkResult{InternalID: "ct_" + record.ExternalID}, nil
+}
+
+func TestSyncExecutionServiceRedeliversFailedRecordOnNextRun(t *testing.T) {
+	ctx := context.Background()
+	checkpoints := newInMemorySyncCheckpointStore()
+	changeLogStore := newInMemorySyncChangeLogStore()
+	sink := &flakySyncTargetSink{failures: map[string]int{"ext_2": 1}}
+	events := &recordingLifecycleEventBus{}
+	service, err := NewSyncExecutionService(checkpoints, changeLogStore, WithSyncTargetSink(sink), WithSyncExecutionEventBus(events))
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	changes := []SyncChange{
+		{SourceObject: "contacts", ExternalID: "ext_1", SourceVersion: "1"},
+		{SourceObject: "contacts", ExternalID: "ext_2", SourceVersion: "1"},
+		{SourceObject: "contacts", ExternalID: "ext_3", SourceVersion: "1"},
+	}
+	plan := SyncRunPlan{
+		BindingID: "binding_1",
+		Mode:      SyncRunModeApply,
+		Checkpoint: SyncCheckpoint{
+			ProviderID:   "hubspot",
+			Scope:        ScopeRef{Type: "org", ID: "org_1"},
+			ConnectionID: "conn_1",
+			Cursor:       "page_1",
+		},
+	}
+
+	first, err := service.RunSyncImport(ctx, RunSyncImportRequest{Plan: plan, Changes: changes})
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.ProcessedCount != 2 || first.FailedCount != 1 {
+		t.Fatalf("unexpected first run counts %+v", first)
+	}
+	if first.Status != SyncRunStatusPartial {
+		t.Fatalf("expected a run with failed records to be partial, got %q", first.Status)
+	}
+	if last := events.events[len(events.events)-1]; last.Name != "services.sync.run.partial" {
+		t.Fatalf("expected the partial run event, got %q", last.Name)
+	}
+	if first.NextCheckpoint == nil || first.NextCheckpoint.Sequence != 1 || first.NextCheckpoint.Cursor != "page_1" {
+		t.Fatalf("expected checkpoint to hold before ext_2, got %+v", first.NextCheckpoint)
+	}
+	stored, ok, err := checkpoints.GetLatest(ctx, "hubspot", plan.Checkpoint.Scope, "binding_1", SyncDirectionImport)
+	if err != nil || !ok || stored.Sequence != 1 {
+		t.Fatalf("expected stored checkpoint at sequence 1, got %+v ok=%v err=%v", stored, ok, err)
+	}
+
+	plan.Checkpoint = *first.NextCheckpoint
+	second, err := service.RunSyncImport(ctx, RunSyncImportRequest{Plan: plan, Changes: changes[1:]})
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.ProcessedCount != 1 || second.SkippedCount != 1 || second.FailedCount != 0 {
+		t.Fatalf("unexpected second run counts %+v", second)
+	}
+	if second.Status != SyncRunStatusSucceeded || events.events[len(events.events)-1].Name != "services.sync.run.succeeded" {
+		t.Fatalf("expected the redelivery run to succeed, got %q", second.Status)
+	}
+	if second.NextCheckpoint == nil || second.NextCheckpoint.Sequence != 3 {
+		t.Fatalf("expected checkpoint to advance after redelivery, got %+v", second.NextCheckpoint)
+	}
+	if last := sink.records[len(sink.records)-2]; last.ExternalID != "ext_2" {
+		t.Fatalf("expected ext_2 to be delivered again, got %q", last.ExternalID)
+	}
+	if len(changeLogStore.entries) != 3 {
+		t.Fatalf("expected every record in the change log, got %d", len(changeLogStore.entries))
+	}
+}
+
+func TestSyncExecutionServiceRequiresPublishedMapping(t *testing.T) {
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	specs := newInMemoryMappingSpecStore()
+	if _, err := specs.CreateDraft(context.Background(), MappingSpec{
+		ID: "spec_row_1", SpecID: "spec_contacts", ProviderID: "hubspot", Scope: scope, Version: 1,
+		Status: MappingSpecStatusDraft, Name: "contacts", SourceObject: "contacts", TargetModel: "crm_contacts",
+	}); err != nil {
+		t.Fatalf("seed spec: %v", err)
+	}
+	bindings := &staticSyncBindingStore{bindings: map[string]SyncBinding{
+		"binding_1": {
+			ID: "binding_1", ProviderID: "hubspot", Scope: scope, ConnectionID: "conn_1",
+			MappingSpecID: "spec_contacts", Direction: SyncDirectionImport, Status: SyncBindingStatusActive,
+		},
+	}}
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		newInMemorySyncChangeLogStore(),
+		WithSyncExecutionMappings(bindings, specs),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	_, err = service.RunSyncImport(context.Background(), RunSyncImportRequest{
+		Plan:    SyncRunPlan{ID: "run_unpublished", BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{{SourceObject: "contacts", ExternalID: "ext_1"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no published mapping spec") {
+		t.Fatalf("expected missing published mapping error, got %v", err)
+	}
+}
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SyncJobScriptPath is the job script path that executes a sync job.
+	SyncJobScriptPath = "services.sync.job"
+	// SyncJobParamID is the job parameter naming the sync job to execute.
+	SyncJobParamID = "sync_job_id"
+
+	defaultSyncJobLease = 5 * time.Minute
+	// syncJobSettleTimeout bounds recording an outcome once the caller's
+	// context is canceled.
+	syncJobSettleTimeout = 10 * time.Second
+)
+
+type SyncJobRunnerFunc func(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error
+
+func (f SyncJobRunnerFunc) RunSyncJob(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error {
+	return f(ctx, job, progress)
+}
+
+type SyncJobExecutorOption func(*SyncJobExecutor)
+
+func WithSyncJobExecutorClock(now func() time.Time) SyncJobExecutorOption {
+	return func(e *SyncJobExecutor) {
+		if e == nil || now == nil {
+			return
+		}
+		e.now = now
+	}
+}
+
+// WithSyncJobLease sets how long a running job may go without reporting
+// progress before it is considered abandoned.
+func WithSyncJobLease(lease time.Duration) SyncJobExecutorOption {
+	return func(e *SyncJobExecutor) {
+		if e == nil || lease <= 0 {
+			return
+		}
+		e.lease = lease
+	}
+}
+
+// WithSyncJobExecutorEnqueuer queues jobs recovered from an expired lease so
+// a worker picks them up again.
+func WithSyncJobExecutorEnqueuer(enqueuer JobEnqueuer) SyncJobExecutorOption {
+	return func(e *SyncJobExecutor) {
+		if e == nil {
+			return
+		}
+		e.enqueuer = enqueuer
+	}
+}
+
+func WithSyncJobRunner(providerID string, runner SyncJobRunner) SyncJobExecutorOption {
+	return func(e *SyncJobExecutor) {
+		if e == nil {
+			return
+		}
+		_ = e.RegisterRunner(providerID, runner)
+	}
+}
+
+// SyncJobExecutor runs queued sync jobs with the runner registered for their
+// provider. A running job holds a lease that every progress report renews;
+// cancel and pause take effect the next time the runner reports progress.
+type SyncJobExecutor struct {
+	store    SyncJobExecutionStore
+	enqueuer JobEnqueuer
+	lease    time.Duration
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	runners map[string]SyncJobRunner
+}
+
+func NewSyncJobExecutor(store SyncJobExecutionStore, opts ...SyncJobExecutorOption) (*SyncJobExecutor, error) {
+	if store == nil {
+		return nil, fmt.Errorf("core: sync job execution store is required")
+	}
+	executor := &SyncJobExecutor{
+		store:   store,
+		lease:   defaultSyncJobLease,
+		runners: map[string]SyncJobRunner{},
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(executor)
+	}
+	return executor, nil
+}
+
+func (e *SyncJobExecutor) RegisterRunner(providerID string, runner SyncJobRunner) error {
+	if e == nil {
+		return fmt.Errorf("core: sync job executor is nil")
+	}
+	key := strings.ToLower(strings.TrimSpace(providerID))
+	if key == "" {
+		return fmt.Errorf("core: sync job runner provider id is required")
+	}
+	if runner == nil {
+		return fmt.Errorf("core: sync job runner for provider %q is required", providerID)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.runners[key] = runner
+	return nil
+}
+
+// Execute claims the sync job and runs it. Jobs that are paused, canceled,
+// finished or leased by another executor are returned unchanged. A runner
+// error marks the job failed and is returned so the caller can retry; a
+// failed job is claimed again on the next call.
+func (e *SyncJobExecutor) Execute(ctx context.Context, jobID string) (SyncJob, error) {
+	if e == nil || e.store == nil {
+		return SyncJob{}, fmt.Errorf("core: sync job executor is not configured")
+	}
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return SyncJob{}, fmt.Errorf("core: sync job id is required")
+	}
+	job, err := e.store.GetSyncJob(ctx, jobID)
+	if err != nil {
+		return SyncJob{}, err
+	}
+	now := e.now()
+	if !syncJobClaimable(job, now) {
+		return job, nil
+	}
+	runner := e.runner(job.ProviderID)
+	if runner == nil {
+		cause := fmt.Errorf("%w: provider %q", ErrSyncJobRunnerNotFound, job.ProviderID)
+		failed, _, saveErr := e.settle(ctx, job, job.Status, SyncJobStatusFailed, cause)
+		if saveErr != nil {
+			return job, errors.Join(cause, saveErr)
+		}
+		return failed, cause
+	}
+
+	next := job
+	if err := next.TransitionTo(SyncJobStatusRunning, now); err != nil {
+		return job, err
+	}
+	next.Attempts++
+	next.LeaseExpiresAt = timePointer(now.Add(e.lease))
+	if next.Progress.StartedAt == nil {
+		next.Progress.StartedAt = timePointer(now)
+	}
+	claimed, ok, err := e.store.UpdateSyncJobIf(ctx, next, job.Status)
+	if err != nil {
+		return job, err
+	}
+	if !ok {
+		return claimed, nil
+	}
+
+	reporter := &syncJobProgressReporter{executor: e, job: claimed}
+	runErr := runner.RunSyncJob(ctx, claimed, reporter)
+	current := reporter.current()
+	switch {
+	case runErr == nil:
+		finished, _, err := e.settle(ctx, current, SyncJobStatusRunning, SyncJobStatusSucceeded, nil)
+		return finished, err
+	case errors.Is(runErr, ErrSyncJobInterrupted):
+		return current, nil
+	case ctx.Err() != nil:
+		// The executor is shutting down; hand the job back to the queue
+		// instead of charging the runner with a failure.
+		requeued, _, err := e.settle(ctx, current, SyncJobStatusRunning, SyncJobStatusQueued, nil)
+		if err != nil {
+			return requeued, errors.Join(runErr, err)
+		}
+		return requeued, runErr
+	default:
+		failed, ok, err := e.settle(ctx, current, SyncJobStatusRunning, SyncJobStatusFailed, runErr)
+		if err != nil {
+			return failed, errors.Join(runErr, err)
+		}
+		if !ok {
+			return failed, nil
+		}
+		return failed, runErr
+	}
+}
+
+// RecoverExpiredLeases requeues running jobs whose executor stopped renewing
+// their lease, typically because its process died. It returns the number of
+// recovered jobs.
+func (e *SyncJobExecutor) RecoverExpiredLeases(ctx context.Context, limit int) (int, error) {
+	if e == nil || e.store == nil {
+		return 0, fmt.Errorf("core: sync job executor is not configured")
+	}
+	now := e.now()
+	jobs, err := e.store.ListSyncJobs(ctx, SyncJobFilter{
+		Statuses:           []SyncJobStatus{SyncJobStatusRunning},
+		LeaseExpiredBefore: &now,
+		Limit:              limit,
+	})
+	if err != nil {
+		return 0, err
+	}
+	recovered := 0
+	for _, job := range jobs {
+		if job.LeaseExpiresAt != nil {
+			job.Metadata = mergeAnyMap(job.Metadata, map[string]any{
+				"lease_expired_at": job.LeaseExpiresAt.UTC().Format(time.RFC3339Nano),
+			})
+		}
+		requeued, ok, err := e.settle(ctx, job, SyncJobStatusRunning, SyncJobStatusQueued, nil)
+		if err != nil {
+			return recovered, err
+		}
+		if !ok {
+			continue
+		}
+		recovered++
+		if e.enqueuer != nil {
+			if _, err := e.enqueuer.Enqueue(ctx, NewSyncJobExecutionMessage(requeued)); err != nil {
+				return recovered, fmt.Errorf("core: enqueue recovered sync job %q: %w", requeued.ID, err)
+			}
+		}
+	}
+	return recovered, nil
+}
+
+// settle moves the job out of expected into status and releases its lease.
+// The outcome has already happened, so it is recorded even when ctx was
+// canceled, as it is while the executor shuts down.
+func (e *SyncJobExecutor) settle(
+	ctx context.Context,
+	job SyncJob,
+	expected SyncJobStatus,
+	status SyncJobStatus,
+	cause error,
+) (SyncJob, bool, error) {
+	now := e.now()
+	next := job
+	if err := next.TransitionTo(status, now); err != nil {
+		return job, false, err
+	}
+	next.LeaseExpiresAt = nil
+	if status == SyncJobStatusSucceeded {
+		next.Progress.EstimatedCompletionAt = nil
+		if next.Progress.ItemsTotal > 0 {
+			next.Progress.ItemsProcessed = max(next.Progress.ItemsProcessed, next.Progress.ItemsTotal)
+		}
+		next.Progress.Percent = 100
+	}
+	if cause != nil {
+		next.Metadata = mergeAnyMap(next.Metadata, map[string]any{
+			"last_error": strings.TrimSpace(cause.Error()),
+		})
+	}
+	settleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), syncJobSettleTimeout)
+	defer cancel()
+	return e.store.UpdateSyncJobIf(settleCtx, next, expected)
+}
+
+func (e *SyncJobExecutor) runner(providerID string) SyncJobRunner {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.runners[strings.ToLower(strings.TrimSpace(providerID))]
+}
+
+// NewSyncJobExecutionMessage builds the job message that executes the sync
+// job. Repeated messages for the same job are dropped while one is pending.
+func NewSyncJobExecutionMessage(job SyncJob) *JobExecutionMessage {
+	return &JobExecutionMessage{
+		JobID:      SyncJobScriptPath,
+		ScriptPath: SyncJobScriptPath,
+		Parameters: map[string]any{
+			SyncJobParamID: job.ID,
+			"provider_id":  job.ProviderID,
+		},
+		IdempotencyKey: "sync_job:" + job.ID,
+		DedupPolicy:    "drop",
+	}
+}
+
+func syncJobClaimable(job SyncJob, now time.Time) bool {
+	switch job.Status {
+	case SyncJobStatusQueued, SyncJobStatusFailed:
+		return true
+	case SyncJobStatusRunning:
+		return job.LeaseExpiresAt != nil && !job.LeaseExpiresAt.After(now)
+	default:
+		return false
+	}
+}
+
+type syncJobProgressReporter struct {
+	executor *SyncJobExecutor
+
+	mu  sync.Mutex
+	job SyncJob
+}
+
+// Report records progress and renews the lease. It returns
+// ErrSyncJobInterrupted once the job has been canceled or paused.
+func (r *syncJobProgressReporter) Report(ctx context.Context, update SyncJobProgressUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.job.Status != SyncJobStatusRunning {
+		return ErrSyncJobInterrupted
+	}
+	now := r.executor.now()
+	next := r.job
+	next.Progress = next.Progress.apply(update, now)
+	if checkpoint := strings.TrimSpace(update.Checkpoint); checkpoint != "" {
+		next.Checkpoint = checkpoint
+	}
+	next.LeaseExpiresAt = timePointer(now.Add(r.executor.lease))
+	next.UpdatedAt = now
+	saved, ok, err := r.executor.store.UpdateSyncJobIf(ctx, next, SyncJobStatusRunning)
+	if err != nil {
+		return err
+	}
+	r.job = saved
+	if !ok {
+		return ErrSyncJobInterrupted
+	}
+	return nil
+}
+
+func (r *syncJobProgressReporter) current() SyncJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.job
+}
+
+// apply folds an update into the progress and derives the percentage and an
+// estimated completion time from the throughput since the job started.
+func (p SyncJobProgress) apply(update SyncJobProgressUpdate, now time.Time) SyncJobProgress {
+	if update.ItemsProcessed > 0 {
+		p.ItemsProcessed = update.ItemsProcessed
+	}
+	if update.ItemsTotal > 0 {
+		p.ItemsTotal = update.ItemsTotal
+	}
+	if update.PagesProcessed > 0 {
+		p.PagesProcessed = update.PagesProcessed
+	}
+	p.Percent = 0
+	p.EstimatedCompletionAt = nil
+	if p.ItemsTotal <= 0 {
+		return p
+	}
+	p.Percent = math.Min(100, float64(p.ItemsProcessed)*100/float64(p.ItemsTotal))
+	if p.StartedAt != nil && p.ItemsProcessed > 0 && p.ItemsProcessed < p.ItemsTotal {
+		elapsed := now.Sub(*p.StartedAt)
+		remaining := float64(elapsed) * float64(p.ItemsTotal-p.ItemsProcessed) / float64(p.ItemsProcessed)
+		p.EstimatedCompletionAt = timePointer(now.Add(time.Duration(remaining)))
+	}
+	return p
+}
+
+func timePointer(value time.Time) *time.Time {
+	value = value.UTC()
+	return &value
+}
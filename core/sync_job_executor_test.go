@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newExecutorTestJob(t *testing.T, store *memoryServiceSyncJobStore, providerID string) SyncJob {
+	t.Helper()
+	result, err := store.CreateSyncJob(context.Background(), CreateSyncJobStoreInput{
+		ProviderID:   providerID,
+		ConnectionID: "conn_1",
+		Mode:         SyncJobModeFull,
+	})
+	if err != nil {
+		t.Fatalf("create sync job: %v", err)
+	}
+	return result.Job
+}
+
+func TestSyncJobExecutor_ReportsProgressAndSucceeds(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryServiceSyncJobStore()
+	job := newExecutorTestJob(t, store, "github")
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	var observed SyncJob
+	executor, err := NewSyncJobExecutor(store,
+		WithSyncJobExecutorClock(func() time.Time { return now }),
+		WithSyncJobLease(time.Minute),
+		WithSyncJobRunner("GitHub", SyncJobRunnerFunc(func(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error {
+			if job.Status != SyncJobStatusRunning || job.Attempts != 1 {
+				t.Fatalf("expected claimed job, got %+v", job)
+			}
+			now = now.Add(10 * time.Second)
+			if err := progress.Report(ctx, SyncJobProgressUpdate{ItemsProcessed: 25, ItemsTotal: 100, PagesProcessed: 1, Checkpoint: "page_1"}); err != nil {
+				return err
+			}
+			observed, _ = store.GetSyncJob(ctx, job.ID)
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("new executor: %v", err)
+	}
+
+	finished, err := executor.Execute(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if observed.Progress.Percent != 25 || observed.Checkpoint != "page_1" {
+		t.Fatalf("unexpected progress %+v", observed)
+	}
+	if eta := observed.Progress.EstimatedCompletionAt; eta == nil || !eta.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("expected eta 30s out, got %v", eta)
+	}
+	if lease := observed.LeaseExpiresAt; lease == nil || !lease.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected progress to renew the lease, got %v", lease)
+	}
+	if finished.Status != SyncJobStatusSucceeded || finished.Progress.Percent != 100 || finished.LeaseExpiresAt != nil {
+		t.Fatalf("unexpected finished job %+v", finished)
+	}
+
+	again, err := executor.Execute(ctx, job.ID)
+	if err != nil || again.Status != SyncJobStatusSucceeded || again.Attempts != 1 {
+		t.Fatalf("expected finished job to be left alone, got %+v %v", again, err)
+	}
+}
+
+func TestSyncJobExecutor_StopsWhenCanceledMidRun(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryServiceSyncJobStore()
+	job := newExecutorTestJob(t, store, "github")
+
+	var reportErr error
+	executor, err := NewSyncJobExecutor(store, WithSyncJobRunner("github", SyncJobRunnerFunc(
+		func(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error {
+			current, _ := store.GetSyncJob(ctx, job.ID)
+			current.Status = SyncJobStatusCanceled
+			if _, ok, _ := store.UpdateSyncJobIf(ctx, current, SyncJobStatusRunning); !ok {
+				t.Fatalf("expected cancel to apply")
+			}
+			reportErr = progress.Report(ctx, SyncJobProgressUpdate{ItemsProcessed: 1})
+			return reportErr
+		},
+	)))
+	if err != nil {
+		t.Fatalf("new executor: %v", err)
+	}
+	got, err := executor.Execute(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !errors.Is(reportErr, ErrSyncJobInterrupted) {
+		t.Fatalf("expected report to signal interruption, got %v", reportErr)
+	}
+	if got.Status != SyncJobStatusCanceled {
+		t.Fatalf("expected canceled job, got %+v", got)
+	}
+}
+
+func TestSyncJobExecutor_RequeuesOnShutdownWithCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := &ctxHonoringSyncJobStore{memoryServiceSyncJobStore: newMemoryServiceSyncJobStore()}
+	job := newExecutorTestJob(t, store.memoryServiceSyncJobStore, "github")
+
+	executor, err := NewSyncJobExecutor(store, WithSyncJobRunner("github", SyncJobRunnerFunc(
+		func(ctx context.Context, job SyncJob, progress SyncJobProgressReporter) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)))
+	if err != nil {
+		t.Fatalf("new executor: %v", err)
+	}
+	got, err := executor.Execute(ctx, job.ID)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation to be returned, got %v", err)
+	}
+	if got.Status != SyncJobStatusQueued || got.LeaseExpiresAt != nil {
+		t.Fatalf("expected the job handed back to the queue, got %+v", got)
+	}
+	stored, _ := store.GetSyncJob(context.Background(), job.ID)
+	if stored.Status != SyncJobStatusQueued {
+		t.Fatalf("expected the stored job to be queued, got %s", stored.Status)
+	}
+}
+
+// ctxHonoringSyncJobStore rejects writes on a canceled context, as the SQL
+// store does.
+type ctxHonoringSyncJobStore struct {
+	*memoryServiceSyncJobStore
+}
+
+func (s *ctxHonoringSyncJobStore) UpdateSyncJobIf(ctx context.Context, job SyncJob, expected SyncJobStatus) (SyncJob, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return job, false, err
+	}
+	return s.memoryServiceSyncJobStore.UpdateSyncJobIf(ctx, job, expected)
+}
+
+func TestSyncJobExecutor_FailuresAndLeaseRecovery(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryServiceSyncJobStore()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	enqueuer := &recordingSyncJobEnqueuer{}
+	boom := errors.New("provider unavailable")
+	executor, err := NewSyncJobExecutor(store,
+		WithSyncJobExecutorClock(func() time.Time { return now }),
+		WithSyncJobExecutorEnqueuer(enqueuer),
+		WithSyncJobRunner("github", SyncJobRunnerFunc(func(context.Context, SyncJob, SyncJobProgressReporter) error {
+			return boom
+		})),
+	)
+	if err != nil {
+		t.Fatalf("new executor: %v", err)
+	}
+
+	orphan := newExecutorTestJob(t, store, "slack")
+	got, err := executor.Execute(ctx, orphan.ID)
+	if !errors.Is(err, ErrSyncJobRunnerNotFound) || got.Status != SyncJobStatusFailed {
+		t.Fatalf("expected missing runner to fail the job, got %+v %v", got, err)
+	}
+
+	flaky := newExecutorTestJob(t, store, "github")
+	got, err = executor.Execute(ctx, flaky.ID)
+	if !errors.Is(err, boom) || got.Status != SyncJobStatusFailed || got.Metadata["last_error"] != boom.Error() {
+		t.Fatalf("expected runner error to fail the job, got %+v %v", got, err)
+	}
+
+	abandoned := newExecutorTestJob(t, store, "github")
+	abandoned.Status = SyncJobStatusRunning
+	abandoned.LeaseExpiresAt = timePointer(now.Add(-time.Second))
+	if _, ok, _ := store.UpdateSyncJobIf(ctx, abandoned, SyncJobStatusQueued); !ok {
+		t.Fatalf("expected abandoned job setup to apply")
+	}
+	leased := newExecutorTestJob(t, store, "github")
+	leased.Status = SyncJobStatusRunning
+	leased.LeaseExpiresAt = timePointer(now.Add(time.Minute))
+	if _, ok, _ := store.UpdateSyncJobIf(ctx, leased, SyncJobStatusQueued); !ok {
+		t.Fatalf("expected leased job setup to apply")
+	}
+
+	recovered, err := executor.RecoverExpiredLeases(ctx, 10)
+	if err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if recovered != 1 {
+		t.Fatalf("expected one recovered job, got %d", recovered)
+	}
+	if ids := enqueuer.jobIDs(); len(ids) != 1 || ids[0] != abandoned.ID {
+		t.Fatalf("expected recovered job to be enqueued, got %v", ids)
+	}
+	current, _ := store.GetSyncJob(ctx, abandoned.ID)
+	if current.Status != SyncJobStatusQueued || current.LeaseExpiresAt != nil || current.Metadata["lease_expired_at"] == nil {
+		t.Fatalf("unexpected recovered job %+v", current)
+	}
+	if current, _ := store.GetSyncJob(ctx, leased.ID); current.Status != SyncJobStatusRunning {
+		t.Fatalf("expected job with a live lease to keep running, got %+v", current)
+	}
+}
@@ -68,7 +68,10 @@ func (s *SyncPlannerService) PlanSyncRun(
 		limit = 1000
 	}
 
-	direction := binding.Direction
+	direction, err := resolvePlanDirection(binding, req.Direction)
+	if err != nil {
+		return SyncRunPlan{}, err
+	}
 	checkpoint, found, err := s.resolvePlanCheckpoint(ctx, req, binding, direction)
 	if err != nil {
 		return SyncRunPlan{}, err
@@ -112,6 +115,25 @@ func (s *SyncPlannerService) PlanSyncRun(
 	return plan, nil
 }
 
+func resolvePlanDirection(binding SyncBinding, requested SyncDirection) (SyncDirection, error) {
+	requested = SyncDirection(strings.TrimSpace(strings.ToLower(string(requested))))
+	if requested == "" {
+		if binding.Direction == SyncDirectionBidirectional {
+			return "", fmt.Errorf("core: sync direction is required for bidirectional binding %q", binding.ID)
+		}
+		return binding.Direction, nil
+	}
+	if !binding.Direction.Allows(requested) {
+		return "", fmt.Errorf(
+			"core: sync binding %q direction %q does not allow %q runs",
+			binding.ID,
+			binding.Direction,
+			requested,
+		)
+	}
+	return requested, nil
+}
+
 func (s *SyncPlannerService) resolvePlanCheckpoint(
 	ctx context.Context,
 	req PlanSyncRunRequest,
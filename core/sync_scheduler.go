@@ -0,0 +1,288 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultSyncSchedulerPollInterval = 30 * time.Second
+	defaultSyncSchedulerBatchSize    = 100
+
+	syncScheduleRequestedBy     = "sync_schedule"
+	syncScheduleSuspendedReason = "suspended_reason"
+	syncSchedulerLeaderLease    = "services.sync_scheduler"
+)
+
+type SyncSchedulerOption func(*SyncScheduler)
+
+func WithSyncSchedulerClock(now func() time.Time) SyncSchedulerOption {
+	return func(s *SyncScheduler) {
+		if s == nil || now == nil {
+			return
+		}
+		s.now = now
+	}
+}
+
+func WithSyncSchedulerPollInterval(interval time.Duration) SyncSchedulerOption {
+	return func(s *SyncScheduler) {
+		if s == nil || interval <= 0 {
+			return
+		}
+		s.pollInterval = interval
+	}
+}
+
+// WithSyncSchedulerBatchSize bounds how many schedules one tick materializes
+// and how many suspended schedules are listed at a time.
+func WithSyncSchedulerBatchSize(size int) SyncSchedulerOption {
+	return func(s *SyncScheduler) {
+		if s == nil || size <= 0 {
+			return
+		}
+		s.batchSize = size
+	}
+}
+
+// WithSyncSchedulerLeaderElector makes Run tick only while this replica holds
+// the sync scheduler lease. Ticking on several replicas is safe but wasteful.
+func WithSyncSchedulerLeaderElector(elector *LeaderElector) SyncSchedulerOption {
+	return func(s *SyncScheduler) {
+		if s == nil || elector == nil {
+			return
+		}
+		s.elector = elector
+	}
+}
+
+// SyncSchedulerTickResult counts what one pass over the schedules did.
+// Replayed counts windows another replica had already materialized.
+type SyncSchedulerTickResult struct {
+	Created     int
+	Replayed    int
+	Suspended   int
+	Reactivated int
+}
+
+// SyncScheduler turns due schedule windows into sync jobs through
+// Service.CreateSyncJob. Each window uses a deterministic idempotency key, so
+// several replicas can run the scheduler without creating duplicate jobs.
+type SyncScheduler struct {
+	service      *Service
+	store        SyncScheduleStore
+	now          func() time.Time
+	pollInterval time.Duration
+	batchSize    int
+	elector      *LeaderElector
+}
+
+func NewSyncScheduler(service *Service, opts ...SyncSchedulerOption) (*SyncScheduler, error) {
+	if service == nil {
+		return nil, fmt.Errorf("core: service is required")
+	}
+	if service.syncScheduleStore == nil {
+		return nil, fmt.Errorf("core: sync schedule store is required")
+	}
+	if service.connectionStore == nil {
+		return nil, fmt.Errorf("core: connection store is required")
+	}
+	scheduler := &SyncScheduler{
+		service:      service,
+		store:        service.syncScheduleStore,
+		pollInterval: defaultSyncSchedulerPollInterval,
+		batchSize:    defaultSyncSchedulerBatchSize,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(scheduler)
+	}
+	return scheduler, nil
+}
+
+// Run ticks every poll interval until the context is canceled. Tick errors
+// are reported through the service observability hooks and do not stop the
+// loop. With a leader elector, only the lease holder ticks.
+func (s *SyncScheduler) Run(ctx context.Context) error {
+	if s == nil || s.service == nil {
+		return fmt.Errorf("core: sync scheduler is not configured")
+	}
+	return runLeaderTicker(ctx, s.elector, syncSchedulerLeaderLease, s.pollInterval, s.tick)
+}
+
+func (s *SyncScheduler) tick(ctx context.Context) error {
+	startedAt := time.Now().UTC()
+	result, err := s.Tick(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+	s.service.observeOperation(ctx, startedAt, "sync_schedule_tick", err, map[string]any{
+		"created":     result.Created,
+		"replayed":    result.Replayed,
+		"suspended":   result.Suspended,
+		"reactivated": result.Reactivated,
+	})
+	return err
+}
+
+// Tick reactivates suspended schedules whose connection is active again and
+// materializes every due window. A failing schedule does not block the rest;
+// its error is joined into the returned error and it is retried next tick.
+func (s *SyncScheduler) Tick(ctx context.Context) (SyncSchedulerTickResult, error) {
+	if s == nil || s.store == nil {
+		return SyncSchedulerTickResult{}, fmt.Errorf("core: sync scheduler is not configured")
+	}
+	var (
+		result SyncSchedulerTickResult
+		errs   []error
+	)
+	now := s.now()
+
+	// Suspended schedules are paged through in full; only reactivation can
+	// bring them back, so any left unlisted would stay suspended.
+	afterID := ""
+	for ctx.Err() == nil {
+		suspended, err := s.store.ListSyncSchedules(ctx, SyncScheduleFilter{
+			Statuses: []SyncScheduleStatus{SyncScheduleStatusSuspended},
+			AfterID:  afterID,
+			Limit:    s.batchSize,
+		})
+		if err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		for _, schedule := range suspended {
+			reactivated, err := s.reactivate(ctx, schedule, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("core: reactivate sync schedule %q: %w", schedule.ID, err))
+				continue
+			}
+			if reactivated {
+				result.Reactivated++
+			}
+		}
+		if len(suspended) < s.batchSize {
+			break
+		}
+		afterID = suspended[len(suspended)-1].ID
+	}
+
+	due, err := s.store.ListSyncSchedules(ctx, SyncScheduleFilter{
+		Statuses:  []SyncScheduleStatus{SyncScheduleStatusActive},
+		DueBefore: &now,
+		Limit:     s.batchSize,
+	})
+	if err != nil {
+		return result, errors.Join(append(errs, err)...)
+	}
+	for _, schedule := range due {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		if err := s.materialize(ctx, schedule, now, &result); err != nil {
+			errs = append(errs, fmt.Errorf("core: materialize sync schedule %q: %w", schedule.ID, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func (s *SyncScheduler) materialize(
+	ctx context.Context,
+	schedule SyncSchedule,
+	now time.Time,
+	result *SyncSchedulerTickResult,
+) error {
+	connection, err := s.service.connectionStore.Get(ctx, schedule.ConnectionID)
+	if err != nil {
+		return err
+	}
+	if connection.Status != ConnectionStatusActive {
+		schedule.Status = SyncScheduleStatusSuspended
+		schedule.Metadata = mergeAnyMap(schedule.Metadata, map[string]any{
+			syncScheduleSuspendedReason: fmt.Sprintf("connection is %s", connection.Status),
+		})
+		if _, err := s.store.UpsertSyncSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		result.Suspended++
+		return nil
+	}
+	if schedule.NextWindowAt == nil {
+		return s.advance(ctx, schedule, now)
+	}
+
+	window := schedule.NextWindowAt.UTC()
+	metadata := map[string]any{
+		"sync_schedule_id": schedule.ID,
+		"scheduled_for":    window.Format(time.RFC3339),
+	}
+	if schedule.SyncBindingID != "" {
+		metadata["sync_binding_id"] = schedule.SyncBindingID
+	}
+	created, err := s.service.CreateSyncJob(ctx, CreateSyncJobRequest{
+		ProviderID:     schedule.ProviderID,
+		ScopeType:      schedule.Scope.Type,
+		ScopeID:        schedule.Scope.ID,
+		ConnectionID:   schedule.ConnectionID,
+		Mode:           schedule.Mode,
+		IdempotencyKey: SyncScheduleIdempotencyKey(schedule.ID, window),
+		RequestedBy:    syncScheduleRequestedBy,
+		Metadata:       metadata,
+	})
+	if err != nil {
+		return err
+	}
+	if created.Created {
+		result.Created++
+	} else {
+		result.Replayed++
+	}
+	schedule.LastWindowAt = &window
+	schedule.LastSyncJobID = created.Job.ID
+	// Windows missed while the scheduler was down are skipped rather than
+	// replayed one by one.
+	return s.advance(ctx, schedule, maxTime(window, now))
+}
+
+func (s *SyncScheduler) reactivate(ctx context.Context, schedule SyncSchedule, now time.Time) (bool, error) {
+	connection, err := s.service.connectionStore.Get(ctx, schedule.ConnectionID)
+	if err != nil {
+		return false, err
+	}
+	if connection.Status != ConnectionStatusActive {
+		return false, nil
+	}
+	schedule.Status = SyncScheduleStatusActive
+	schedule.Metadata = copyAnyMap(schedule.Metadata)
+	delete(schedule.Metadata, syncScheduleSuspendedReason)
+	if err := s.advance(ctx, schedule, now); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *SyncScheduler) advance(ctx context.Context, schedule SyncSchedule, after time.Time) error {
+	window, err := schedule.NextWindow(after)
+	if err != nil {
+		return err
+	}
+	runAt := schedule.RunAt(window)
+	schedule.NextWindowAt = &window
+	schedule.NextRunAt = &runAt
+	_, err = s.store.UpsertSyncSchedule(ctx, schedule)
+	return err
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
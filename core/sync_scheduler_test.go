@@ -0,0 +1,351 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncScheduler_MaterializesWindowsOnceAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "org", ID: "org_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	syncJobStore := newMemoryServiceSyncJobStore()
+	scheduleStore := newMemorySyncScheduleStore()
+	svc, err := NewService(
+		Config{},
+		WithConnectionStore(connectionStore),
+		WithSyncJobStore(syncJobStore),
+		WithSyncScheduleStore(scheduleStore),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	schedule, err := svc.UpsertSyncSchedule(ctx, UpsertSyncScheduleRequest{
+		ProviderID:    "github",
+		ScopeType:     "org",
+		ScopeID:       "org_1",
+		SyncBindingID: "binding_1",
+		Interval:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("upsert schedule: %v", err)
+	}
+	if schedule.ConnectionID != connection.ID || schedule.Mode != SyncJobModeDelta || schedule.NextRunAt == nil {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+	runs, err := svc.ListSyncScheduleRuns(ctx, ListSyncScheduleRunsRequest{SyncScheduleID: schedule.ID, Count: 2})
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	if len(runs) != 2 || !runs[0].WindowAt.Equal(*schedule.NextWindowAt) || runs[1].WindowAt.Sub(runs[0].WindowAt) != time.Hour {
+		t.Fatalf("unexpected upcoming runs %+v", runs)
+	}
+
+	// Two replicas observe the same due window.
+	now := schedule.NextWindowAt.Add(5 * time.Minute)
+	clock := func() time.Time { return now }
+	first, err := NewSyncScheduler(svc, WithSyncSchedulerClock(clock))
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	second, err := NewSyncScheduler(svc, WithSyncSchedulerClock(clock))
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	stale := scheduleStore.snapshot(schedule.ID)
+	result, err := first.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Created != 1 {
+		t.Fatalf("expected one created job, got %+v", result)
+	}
+	scheduleStore.restore(stale)
+	result, err = second.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick replica: %v", err)
+	}
+	if result.Created != 0 || result.Replayed != 1 {
+		t.Fatalf("expected the replica to replay the window, got %+v", result)
+	}
+	if len(syncJobStore.jobs) != 1 {
+		t.Fatalf("expected a single job for the window, got %d", len(syncJobStore.jobs))
+	}
+	job := syncJobStore.jobs["job_1"]
+	if job.Metadata["sync_schedule_id"] != schedule.ID || job.Metadata["sync_binding_id"] != "binding_1" {
+		t.Fatalf("unexpected job metadata %+v", job.Metadata)
+	}
+
+	advanced, _ := svc.GetSyncSchedule(ctx, schedule.ID)
+	if advanced.LastSyncJobID != job.ID || !advanced.NextWindowAt.Equal(stale.NextWindowAt.Add(time.Hour)) {
+		t.Fatalf("expected schedule to advance one window, got %+v", advanced)
+	}
+	if result, _ := first.Tick(ctx); result.Created != 0 {
+		t.Fatalf("expected nothing due before the next window, got %+v", result)
+	}
+}
+
+func TestSyncScheduler_SuspendsWhileConnectionInactive(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             ScopeRef{Type: "org", ID: "org_1"},
+		ExternalAccountID: "acct_1",
+		Status:            ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	syncJobStore := newMemoryServiceSyncJobStore()
+	scheduleStore := newMemorySyncScheduleStore()
+	svc, err := NewService(
+		Config{},
+		WithConnectionStore(connectionStore),
+		WithSyncJobStore(syncJobStore),
+		WithSyncScheduleStore(scheduleStore),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	schedule, err := svc.UpsertSyncSchedule(ctx, UpsertSyncScheduleRequest{
+		ProviderID:     "github",
+		ScopeType:      "org",
+		ScopeID:        "org_1",
+		ConnectionID:   connection.ID,
+		CronExpression: "*/5 * * * *",
+	})
+	if err != nil {
+		t.Fatalf("upsert schedule: %v", err)
+	}
+	if _, err := svc.UpsertSyncSchedule(ctx, UpsertSyncScheduleRequest{
+		ProviderID:     "github",
+		ScopeType:      "org",
+		ScopeID:        "org_1",
+		CronExpression: "*/5 * * * *",
+		Interval:       time.Hour,
+	}); err == nil {
+		t.Fatalf("expected cron and interval together to be rejected")
+	}
+
+	now := schedule.NextRunAt.Add(time.Minute)
+	scheduler, err := NewSyncScheduler(svc, WithSyncSchedulerClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	if err := connectionStore.UpdateStatus(ctx, connection.ID, ConnectionStatusPendingReauth, "token expired"); err != nil {
+		t.Fatalf("update connection: %v", err)
+	}
+	result, err := scheduler.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Suspended != 1 || len(syncJobStore.jobs) != 0 {
+		t.Fatalf("expected schedule to be suspended without a job, got %+v", result)
+	}
+	suspended, _ := svc.GetSyncSchedule(ctx, schedule.ID)
+	if suspended.Status != SyncScheduleStatusSuspended || suspended.Metadata[syncScheduleSuspendedReason] == nil {
+		t.Fatalf("unexpected suspended schedule %+v", suspended)
+	}
+
+	if err := connectionStore.UpdateStatus(ctx, connection.ID, ConnectionStatusActive, ""); err != nil {
+		t.Fatalf("update connection: %v", err)
+	}
+	now = now.Add(time.Hour)
+	result, err = scheduler.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Reactivated != 1 || result.Created != 0 {
+		t.Fatalf("expected reactivation without replaying missed windows, got %+v", result)
+	}
+	reactivated, _ := svc.GetSyncSchedule(ctx, schedule.ID)
+	if reactivated.Status != SyncScheduleStatusActive || !reactivated.NextWindowAt.After(now) {
+		t.Fatalf("unexpected reactivated schedule %+v", reactivated)
+	}
+	if _, ok := reactivated.Metadata[syncScheduleSuspendedReason]; ok {
+		t.Fatalf("expected suspended reason to be cleared")
+	}
+
+	listed, err := svc.ListSyncSchedules(ctx, ListSyncSchedulesRequest{ConnectionID: connection.ID, Statuses: []SyncScheduleStatus{"ACTIVE"}})
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one active schedule, got %+v %v", listed, err)
+	}
+	if err := svc.DeleteSyncSchedule(ctx, schedule.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := svc.GetSyncSchedule(ctx, schedule.ID); err == nil ||
+		!strings.Contains(strings.ToLower(fmt.Sprint(err)), "sync schedule not found") {
+		t.Fatalf("expected deleted schedule to be missing, got %v", err)
+	}
+}
+
+func TestSyncScheduler_ReactivatesSuspendedSchedulesBeyondOneBatch(t *testing.T) {
+	ctx := context.Background()
+	connectionStore := newMemoryConnectionStore()
+	scheduleStore := newMemorySyncScheduleStore()
+	svc, err := NewService(
+		Config{},
+		WithConnectionStore(connectionStore),
+		WithSyncJobStore(newMemoryServiceSyncJobStore()),
+		WithSyncScheduleStore(scheduleStore),
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	// Two schedules whose connection is still inactive fill the first batch
+	// ahead of the one that can be reactivated.
+	var schedules []SyncSchedule
+	for i, status := range []ConnectionStatus{ConnectionStatusPendingReauth, ConnectionStatusPendingReauth, ConnectionStatusActive} {
+		connection, err := connectionStore.Create(ctx, CreateConnectionInput{
+			ProviderID:        "github",
+			Scope:             ScopeRef{Type: "org", ID: "org_1"},
+			ExternalAccountID: fmt.Sprintf("acct_%d", i),
+			Status:            status,
+		})
+		if err != nil {
+			t.Fatalf("create connection: %v", err)
+		}
+		schedule, err := svc.UpsertSyncSchedule(ctx, UpsertSyncScheduleRequest{
+			ProviderID:   "github",
+			ScopeType:    "org",
+			ScopeID:      "org_1",
+			ConnectionID: connection.ID,
+			Interval:     time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("upsert schedule: %v", err)
+		}
+		schedule.Status = SyncScheduleStatusSuspended
+		scheduleStore.restore(schedule)
+		schedules = append(schedules, schedule)
+	}
+
+	scheduler, err := NewSyncScheduler(svc, WithSyncSchedulerBatchSize(2))
+	if err != nil {
+		t.Fatalf("new scheduler: %v", err)
+	}
+	result, err := scheduler.Tick(ctx)
+	if err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+	if result.Reactivated != 1 {
+		t.Fatalf("expected the schedule past the first batch to be reactivated, got %+v", result)
+	}
+	for i, schedule := range schedules {
+		want := SyncScheduleStatusSuspended
+		if i == 2 {
+			want = SyncScheduleStatusActive
+		}
+		if got := scheduleStore.snapshot(schedule.ID).Status; got != want {
+			t.Fatalf("expected schedule %d to be %s, got %s", i, want, got)
+		}
+	}
+}
+
+type memorySyncScheduleStore struct {
+	mu        sync.Mutex
+	next      int
+	schedules map[string]SyncSchedule
+}
+
+func newMemorySyncScheduleStore() *memorySyncScheduleStore {
+	return &memorySyncScheduleStore{schedules: map[string]SyncSchedule{}}
+}
+
+func (s *memorySyncScheduleStore) UpsertSyncSchedule(_ context.Context, schedule SyncSchedule) (SyncSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := schedule.Validate(); err != nil {
+		return SyncSchedule{}, err
+	}
+	now := time.Now().UTC()
+	if schedule.ID == "" {
+		s.next++
+		schedule.ID = fmt.Sprintf("schedule_%d", s.next)
+	}
+	if existing, ok := s.schedules[schedule.ID]; ok {
+		schedule.CreatedAt = existing.CreatedAt
+	} else {
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+	schedule.Metadata = copyAnyMap(schedule.Metadata)
+	s.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+func (s *memorySyncScheduleStore) GetSyncSchedule(_ context.Context, id string) (SyncSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return SyncSchedule{}, fmt.Errorf("%w: id %q", ErrSyncScheduleNotFound, id)
+	}
+	return schedule, nil
+}
+
+func (s *memorySyncScheduleStore) ListSyncSchedules(_ context.Context, filter SyncScheduleFilter) ([]SyncSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []SyncSchedule{}
+	for _, schedule := range s.schedules {
+		if filter.ConnectionID != "" && schedule.ConnectionID != filter.ConnectionID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, schedule.Status) {
+			continue
+		}
+		if filter.DueBefore != nil && (schedule.NextRunAt == nil || schedule.NextRunAt.After(*filter.DueBefore)) {
+			continue
+		}
+		if filter.AfterID != "" && schedule.ID <= filter.AfterID {
+			continue
+		}
+		out = append(out, schedule)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (s *memorySyncScheduleStore) DeleteSyncSchedule(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[id]; !ok {
+		return fmt.Errorf("%w: id %q", ErrSyncScheduleNotFound, id)
+	}
+	delete(s.schedules, id)
+	return nil
+}
+
+func (s *memorySyncScheduleStore) snapshot(id string) SyncSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedules[id]
+}
+
+func (s *memorySyncScheduleStore) restore(schedule SyncSchedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules[schedule.ID] = schedule
+}
+
+var _ SyncScheduleStore = (*memorySyncScheduleStore)(nil)
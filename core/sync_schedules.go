@@ -0,0 +1,348 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minSyncScheduleInterval = time.Minute
+	// cronSearchHorizon bounds the search for the next cron match so that
+	// expressions that can never fire, such as "0 0 30 2 *", terminate.
+	cronSearchHorizon = 5 * 366 * 24 * time.Hour
+)
+
+type SyncScheduleStatus string
+
+const (
+	SyncScheduleStatusActive SyncScheduleStatus = "active"
+	SyncScheduleStatusPaused SyncScheduleStatus = "paused"
+	// SyncScheduleStatusSuspended marks a schedule held back by the scheduler
+	// because its connection is not active. It becomes active again once the
+	// connection does.
+	SyncScheduleStatusSuspended SyncScheduleStatus = "suspended"
+)
+
+func (s SyncScheduleStatus) IsValid() bool {
+	switch s {
+	case SyncScheduleStatusActive, SyncScheduleStatusPaused, SyncScheduleStatusSuspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// SyncSchedule materializes sync jobs for a connection, or one of its sync
+// bindings, on a cron expression or a fixed interval. Each run belongs to a
+// window: the nominal time the expression or interval fires. The run itself
+// happens at the window plus a jitter derived from the schedule and window,
+// so every replica computes the same run time.
+type SyncSchedule struct {
+	ID             string
+	ProviderID     string
+	Scope          ScopeRef
+	ConnectionID   string
+	SyncBindingID  string
+	Mode           SyncJobMode
+	CronExpression string
+	Interval       time.Duration
+	Jitter         time.Duration
+	Timezone       string
+	Status         SyncScheduleStatus
+	NextWindowAt   *time.Time
+	NextRunAt      *time.Time
+	LastWindowAt   *time.Time
+	LastSyncJobID  string
+	Metadata       map[string]any
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SyncScheduleRun is one upcoming run of a schedule.
+type SyncScheduleRun struct {
+	WindowAt time.Time
+	RunAt    time.Time
+}
+
+func (s SyncSchedule) Validate() error {
+	if strings.TrimSpace(s.ProviderID) == "" {
+		return fmt.Errorf("core: provider id is required")
+	}
+	if err := s.Scope.Validate(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(s.ConnectionID) == "" {
+		return fmt.Errorf("core: connection id is required")
+	}
+	if !s.Status.IsValid() {
+		return fmt.Errorf("%w: status %q", ErrInvalidSyncSchedule, s.Status)
+	}
+	if _, err := parseSyncJobCreateMode(s.Mode); err != nil {
+		return err
+	}
+	hasCron := strings.TrimSpace(s.CronExpression) != ""
+	switch {
+	case hasCron && s.Interval != 0:
+		return fmt.Errorf("%w: cron expression and interval are mutually exclusive", ErrInvalidSyncSchedule)
+	case hasCron:
+		if _, err := ParseCronExpression(s.CronExpression); err != nil {
+			return err
+		}
+	case s.Interval < minSyncScheduleInterval:
+		return fmt.Errorf("%w: interval must be at least %s", ErrInvalidSyncSchedule, minSyncScheduleInterval)
+	}
+	if s.Jitter < 0 {
+		return fmt.Errorf("%w: jitter must not be negative", ErrInvalidSyncSchedule)
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NextWindow returns the first window strictly after the given time. Cron
+// windows are evaluated in the schedule time zone; interval windows are
+// multiples of the interval since the zero time, independent of time zone.
+func (s SyncSchedule) NextWindow(after time.Time) (time.Time, error) {
+	if strings.TrimSpace(s.CronExpression) == "" {
+		if s.Interval < minSyncScheduleInterval {
+			return time.Time{}, fmt.Errorf("%w: interval must be at least %s", ErrInvalidSyncSchedule, minSyncScheduleInterval)
+		}
+		return after.UTC().Truncate(s.Interval).Add(s.Interval), nil
+	}
+	expr, err := ParseCronExpression(s.CronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	next, ok := expr.Next(after.In(loc))
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: cron expression %q never fires", ErrInvalidSyncSchedule, s.CronExpression)
+	}
+	return next.UTC(), nil
+}
+
+// RunAt returns when the window runs once jitter is applied. The offset is a
+// hash of the schedule id and window, so it is stable across replicas.
+func (s SyncSchedule) RunAt(window time.Time) time.Time {
+	window = window.UTC()
+	if s.Jitter <= 0 {
+		return window
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(s.ID))
+	_, _ = hash.Write([]byte(window.Format(time.RFC3339)))
+	return window.Add(time.Duration(hash.Sum64() % uint64(s.Jitter)))
+}
+
+// UpcomingRuns lists the next count runs after the given time.
+func (s SyncSchedule) UpcomingRuns(after time.Time, count int) ([]SyncScheduleRun, error) {
+	runs := make([]SyncScheduleRun, 0, max(count, 0))
+	cursor := after
+	for len(runs) < count {
+		window, err := s.NextWindow(cursor)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, SyncScheduleRun{WindowAt: window, RunAt: s.RunAt(window)})
+		cursor = window
+	}
+	return runs, nil
+}
+
+// SyncScheduleIdempotencyKey is the sync job idempotency key for a schedule
+// window. Replicas that materialize the same window replay the same job.
+func SyncScheduleIdempotencyKey(scheduleID string, window time.Time) string {
+	return "sync_schedule:" + strings.TrimSpace(scheduleID) + ":" + window.UTC().Format(time.RFC3339)
+}
+
+func (s SyncSchedule) location() (*time.Location, error) {
+	name := strings.TrimSpace(s.Timezone)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: time zone %q: %v", ErrInvalidSyncSchedule, name, err)
+	}
+	return loc, nil
+}
+
+// CronExpression is a parsed five-field cron expression: minute, hour, day of
+// month, month and day of week. Fields accept *, lists, ranges, steps and
+// month or weekday names; the @hourly, @daily, @weekly, @monthly and @yearly
+// shorthands are also accepted. As in cron, a job whose day of month and day
+// of week are both restricted runs when either matches.
+type CronExpression struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var cronShorthands = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var (
+	cronMonthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	cronWeekdayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+func ParseCronExpression(expr string) (CronExpression, error) {
+	normalized := strings.ToLower(strings.TrimSpace(expr))
+	if shorthand, ok := cronShorthands[normalized]; ok {
+		normalized = shorthand
+	}
+	fields := strings.Fields(normalized)
+	if len(fields) != 5 {
+		return CronExpression{}, fmt.Errorf("%w: cron expression %q must have 5 fields", ErrInvalidSyncSchedule, expr)
+	}
+	var (
+		parsed CronExpression
+		err    error
+	)
+	if parsed.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return CronExpression{}, fmt.Errorf("%w: minute: %v", ErrInvalidSyncSchedule, err)
+	}
+	if parsed.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return CronExpression{}, fmt.Errorf("%w: hour: %v", ErrInvalidSyncSchedule, err)
+	}
+	if parsed.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return CronExpression{}, fmt.Errorf("%w: day of month: %v", ErrInvalidSyncSchedule, err)
+	}
+	if parsed.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return CronExpression{}, fmt.Errorf("%w: month: %v", ErrInvalidSyncSchedule, err)
+	}
+	// Day of week accepts 7 as an alias for Sunday.
+	if parsed.dow, err = parseCronField(fields[4], 0, 7, cronWeekdayNames); err != nil {
+		return CronExpression{}, fmt.Errorf("%w: day of week: %v", ErrInvalidSyncSchedule, err)
+	}
+	if parsed.dow&(1<<7) != 0 {
+		parsed.dow = parsed.dow&^(1<<7) | 1
+	}
+	parsed.domAny = strings.HasPrefix(fields[2], "*")
+	parsed.dowAny = strings.HasPrefix(fields[4], "*")
+	return parsed, nil
+}
+
+// Next returns the first time after the given time, in its location, that
+// matches the expression. It reports false when nothing matches within five
+// years.
+func (c CronExpression) Next(after time.Time) (time.Time, bool) {
+	loc := after.Location()
+	limit := after.Add(cronSearchHorizon)
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		if !cronBit(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cronBit(c.hour, t.Hour()) {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// Wall clock hours repeat when daylight saving time ends.
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if !cronBit(c.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (c CronExpression) dayMatches(t time.Time) bool {
+	dom := cronBit(c.dom, t.Day())
+	dow := cronBit(c.dow, int(t.Weekday()))
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func cronBit(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+func parseCronField(field string, minValue, maxValue int, names map[string]int) (uint64, error) {
+	var set uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = parsed
+		}
+		low, high := minValue, maxValue
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseCronValue(lowPart, names); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(highPart, names); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := parseCronValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+		if low < minValue || high > maxValue || low > high {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, minValue, maxValue)
+		}
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	if bits.OnesCount64(set) == 0 {
+		return 0, fmt.Errorf("%q matches nothing", field)
+	}
+	return set, nil
+}
+
+func parseCronValue(value string, names map[string]int) (int, error) {
+	if named, ok := names[value]; ok {
+		return named, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronExpression_NextMatches(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	cases := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "every fifteen minutes",
+			expr:  "*/15 * * * *",
+			after: time.Date(2026, 5, 1, 9, 7, 30, 0, time.UTC),
+			want:  time.Date(2026, 5, 1, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekday mornings with names",
+			expr:  "30 8 * * mon-fri",
+			after: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC), // Friday
+			want:  time.Date(2026, 5, 4, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "day of month or weekday",
+			expr:  "0 0 15 * 0",
+			after: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "sunday as seven",
+			expr:  "0 12 * * 7",
+			after: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2026, 5, 3, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "monthly shorthand",
+			expr:  "@monthly",
+			after: time.Date(2026, 12, 5, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "skips the hour lost to daylight saving time",
+			expr:  "30 2 * * *",
+			after: time.Date(2026, 3, 29, 0, 0, 0, 0, berlin),
+			want:  time.Date(2026, 3, 30, 2, 30, 0, 0, berlin),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := ParseCronExpression(tc.expr)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.expr, err)
+			}
+			got, ok := expr.Next(tc.after)
+			if !ok || !got.Equal(tc.want) {
+				t.Fatalf("expected %s, got %s (%v)", tc.want, got, ok)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"* * * *", "60 * * * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "1,,2 * * * *"} {
+		if _, err := ParseCronExpression(invalid); !errors.Is(err, ErrInvalidSyncSchedule) {
+			t.Fatalf("expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+	never, err := ParseCronExpression("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("parse february 30: %v", err)
+	}
+	if _, ok := never.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected february 30 never to match")
+	}
+}
+
+func TestSyncSchedule_WindowsJitterAndValidation(t *testing.T) {
+	base := SyncSchedule{
+		ID:           "schedule_1",
+		ProviderID:   "github",
+		Scope:        ScopeRef{Type: "org", ID: "org_1"},
+		ConnectionID: "conn_1",
+		Mode:         SyncJobModeDelta,
+		Status:       SyncScheduleStatusActive,
+	}
+
+	cron := base
+	cron.CronExpression = "0 9 * * *"
+	cron.Timezone = "America/New_York"
+	if err := cron.Validate(); err != nil {
+		if errors.Is(err, ErrInvalidSyncSchedule) {
+			t.Skipf("time zone data unavailable: %v", err)
+		}
+		t.Fatalf("validate cron schedule: %v", err)
+	}
+	window, err := cron.NextWindow(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("next cron window: %v", err)
+	}
+	if want := time.Date(2026, 7, 1, 13, 0, 0, 0, time.UTC); !window.Equal(want) {
+		t.Fatalf("expected 09:00 New York as %s, got %s", want, window)
+	}
+
+	interval := base
+	interval.Interval = time.Hour
+	interval.Jitter = 10 * time.Minute
+	runs, err := interval.UpcomingRuns(time.Date(2026, 7, 1, 12, 30, 0, 0, time.UTC), 3)
+	if err != nil {
+		t.Fatalf("upcoming runs: %v", err)
+	}
+	if len(runs) != 3 || !runs[0].WindowAt.Equal(time.Date(2026, 7, 1, 13, 0, 0, 0, time.UTC)) ||
+		!runs[2].WindowAt.Equal(time.Date(2026, 7, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected interval windows %+v", runs)
+	}
+	for _, run := range runs {
+		offset := run.RunAt.Sub(run.WindowAt)
+		if offset < 0 || offset >= interval.Jitter {
+			t.Fatalf("expected jitter within [0, %s), got %s", interval.Jitter, offset)
+		}
+		if again := interval.RunAt(run.WindowAt); !again.Equal(run.RunAt) {
+			t.Fatalf("expected jitter to be deterministic, got %s and %s", run.RunAt, again)
+		}
+	}
+	if key := SyncScheduleIdempotencyKey("schedule_1", runs[0].WindowAt); key != "sync_schedule:schedule_1:2026-07-01T13:00:00Z" {
+		t.Fatalf("unexpected idempotency key %q", key)
+	}
+
+	invalid := []SyncSchedule{
+		func() SyncSchedule { s := base; return s }(),
+		func() SyncSchedule { s := cron; s.Interval = time.Hour; return s }(),
+		func() SyncSchedule { s := base; s.Interval = time.Second; return s }(),
+		func() SyncSchedule { s := interval; s.Jitter = -time.Second; return s }(),
+		func() SyncSchedule { s := interval; s.Timezone = "Mars/Olympus"; return s }(),
+		func() SyncSchedule { s := interval; s.Status = "running"; return s }(),
+	}
+	for i, schedule := range invalid {
+		if err := schedule.Validate(); !errors.Is(err, ErrInvalidSyncSchedule) {
+			t.Fatalf("case %d: expected invalid sync schedule, got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithSyncExecutionIdentityBindings marks the identity binding of a deleted
+// record orphaned and clears the mark when the record is restored. Deletes
+// and restores delivered to sinks carry the bound internal id.
+func WithSyncExecutionIdentityBindings(store IdentityBindingStore) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil {
+			return
+		}
+		s.identityBindings = store
+	}
+}
+
+// WithSyncTombstoneRetention sets how long sinks should keep soft-deleted
+// records before purging them. Bindings override it with tombstone_retention
+// metadata; zero leaves RetainUntil unset.
+func WithSyncTombstoneRetention(retention time.Duration) SyncExecutionServiceOption {
+	return func(s *SyncExecutionService) {
+		if s == nil || retention < 0 {
+			return
+		}
+		s.tombstoneRetention = retention
+	}
+}
+
+// syncChangeVersion is the version component of a change's idempotency key.
+// Deletes and restores are keyed apart from updates so a delete reported with
+// the record's last version is not mistaken for that update. Without a source
+// version, they fall back to the modification time.
+func syncChangeVersion(change SyncChange) string {
+	if !change.Operation.isTombstone() {
+		return change.SourceVersion
+	}
+	version := change.SourceVersion
+	if version == "" && !change.ModifiedAt.IsZero() {
+		version = change.ModifiedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return string(change.Operation) + ":" + version
+}
+
+// syncBindingTombstoneRetention reads the binding's tombstone_retention
+// metadata, given as a duration string or a number of seconds.
+func syncBindingTombstoneRetention(binding SyncBinding, fallback time.Duration) (time.Duration, error) {
+	raw, ok := binding.Metadata[SyncBindingMetadataTombstoneRetention]
+	if !ok || raw == nil {
+		return fallback, nil
+	}
+	var (
+		retention time.Duration
+		err       error
+	)
+	switch typed := raw.(type) {
+	case string:
+		text := strings.TrimSpace(typed)
+		if text == "" {
+			return fallback, nil
+		}
+		if seconds, parseErr := strconv.ParseFloat(text, 64); parseErr == nil {
+			retention = time.Duration(seconds * float64(time.Second))
+		} else {
+			retention, err = time.ParseDuration(text)
+		}
+	case int:
+		retention = time.Duration(typed) * time.Second
+	case int64:
+		retention = time.Duration(typed) * time.Second
+	case float64:
+		retention = time.Duration(typed * float64(time.Second))
+	default:
+		err = fmt.Errorf("unsupported type %T", raw)
+	}
+	if err == nil && retention < 0 {
+		err = fmt.Errorf("must not be negative")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("core: sync binding %q has invalid %s %v: %w", binding.ID, SyncBindingMetadataTombstoneRetention, raw, err)
+	}
+	return retention, nil
+}
+
+// prepareSyncTombstone fills in what sinks need to apply a delete or restore:
+// the bound internal id and, for deletes, when the tombstone may be purged.
+func (s *SyncExecutionService) prepareSyncTombstone(
+	ctx context.Context,
+	target *syncRunTarget,
+	record *SyncMappedRecord,
+	bindingID string,
+) error {
+	if s.identityBindings != nil {
+		identity, found, err := s.identityBindings.GetByExternalID(ctx, bindingID, record.ExternalID)
+		if err != nil {
+			return fmt.Errorf("core: load identity binding: %w", err)
+		}
+		if found {
+			record.InternalID = identity.InternalID
+		}
+	}
+	if record.Operation == SyncChangeOperationDelete && target.retention > 0 {
+		retainUntil := s.now().Add(target.retention)
+		record.RetainUntil = &retainUntil
+	}
+	return nil
+}
+
+// orphanSyncIdentity marks the identity binding of a deleted record orphaned,
+// or clears the mark once the record is restored.
+func (s *SyncExecutionService) orphanSyncIdentity(ctx context.Context, bindingID string, change SyncChange) error {
+	if s.identityBindings == nil {
+		return nil
+	}
+	if !change.Operation.isTombstone() {
+		return nil
+	}
+	identity, found, err := s.identityBindings.GetByExternalID(ctx, bindingID, change.ExternalID)
+	if err != nil {
+		return fmt.Errorf("core: load identity binding: %w", err)
+	}
+	if !found {
+		return nil
+	}
+	if change.Operation == SyncChangeOperationRestore {
+		if identity.OrphanedAt == nil {
+			return nil
+		}
+		identity.OrphanedAt = nil
+	} else {
+		if identity.OrphanedAt != nil {
+			return nil
+		}
+		orphanedAt := s.now()
+		identity.OrphanedAt = &orphanedAt
+	}
+	if _, err := s.identityBindings.Upsert(ctx, identity); err != nil {
+		return fmt.Errorf("core: update identity binding: %w", err)
+	}
+	return nil
+}
+
+// isTombstone reports whether the operation deletes or restores a record
+// rather than carrying its state.
+func (o SyncChangeOperation) isTombstone() bool {
+	return o == SyncChangeOperationDelete || o == SyncChangeOperationRestore
+}
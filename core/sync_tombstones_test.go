@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type deletingSyncTargetSink struct {
+	recordingSyncTargetSink
+	deleted []SyncMappedRecord
+}
+
+func (s *deletingSyncTargetSink) DeleteSyncRecord(ctx context.Context, record SyncMappedRecord) (SyncSinkResult, error) {
+	s.deleted = append(s.deleted, record)
+	return SyncSinkResult{InternalID: record.InternalID}, nil
+}
+
+func TestSyncExecutionServicePropagatesDeletesAndRestores(t *testing.T) {
+	ctx := context.Background()
+	scope := ScopeRef{Type: "org", ID: "org_123"}
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	identities := newInMemoryIdentityBindingStore()
+	if _, err := identities.Upsert(ctx, IdentityBinding{
+		ProviderID: "google_drive", Scope: scope, ConnectionID: "conn_1", SyncBindingID: "binding_1",
+		SourceObject: "files", ExternalID: "file_1", InternalType: "document", InternalID: "doc_1",
+		MatchKind: IdentityBindingMatchExact, Confidence: 1,
+	}); err != nil {
+		t.Fatalf("seed identity: %v", err)
+	}
+	bindings := &staticSyncBindingStore{bindings: map[string]SyncBinding{
+		"binding_1": {
+			ID: "binding_1", ProviderID: "google_drive", Scope: scope, ConnectionID: "conn_1",
+			SourceObject: "files", TargetModel: "documents", Direction: SyncDirectionImport,
+			Status:   SyncBindingStatusActive,
+			Metadata: map[string]any{SyncBindingMetadataTombstoneRetention: "72h"},
+		},
+	}}
+	changeLog := newInMemorySyncChangeLogStore()
+	sink := &deletingSyncTargetSink{}
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		changeLog,
+		WithSyncExecutionClock(func() time.Time { return now }),
+		WithSyncExecutionMappings(bindings, nil),
+		WithSyncTargetSink(sink),
+		WithSyncExecutionIdentityBindings(identities),
+		WithSyncTombstoneRetention(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	run := func(changes ...SyncChange) SyncRunResult {
+		t.Helper()
+		result, err := service.RunSyncImport(ctx, RunSyncImportRequest{
+			Plan:    SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply},
+			Changes: changes,
+		})
+		if err != nil {
+			t.Fatalf("run import: %v", err)
+		}
+		return result
+	}
+
+	run(SyncChange{ExternalID: "file_1", SourceVersion: "7", Payload: map[string]any{"name": "Plan"}})
+	deleted := run(SyncChange{Operation: SyncChangeOperationDelete, ExternalID: "file_1", SourceVersion: "7"})
+	if deleted.ProcessedCount != 1 || len(sink.deleted) != 1 || len(sink.records) != 1 {
+		t.Fatalf("expected delete with the update's version to reach the sink, got %+v", deleted)
+	}
+	tombstone := sink.deleted[0]
+	if tombstone.Operation != SyncChangeOperationDelete || tombstone.InternalID != "doc_1" {
+		t.Fatalf("expected delete callback for doc_1, got %+v", tombstone)
+	}
+	if tombstone.RetainUntil == nil || !tombstone.RetainUntil.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("expected binding retention to set retain until, got %v", tombstone.RetainUntil)
+	}
+	identity, _, _ := identities.GetByExternalID(ctx, "binding_1", "file_1")
+	if identity.OrphanedAt == nil || !identity.OrphanedAt.Equal(now) {
+		t.Fatalf("expected identity to be orphaned, got %+v", identity)
+	}
+	operations := map[SyncChangeOperation]int{}
+	for _, entry := range changeLog.entries {
+		operations[entry.Operation]++
+	}
+	if operations[SyncChangeOperationUpdate] != 1 || operations[SyncChangeOperationDelete] != 1 {
+		t.Fatalf("expected update and delete to be logged, got %+v", changeLog.entries)
+	}
+
+	if replay := run(SyncChange{Operation: SyncChangeOperationDelete, ExternalID: "file_1", SourceVersion: "7"}); replay.SkippedCount != 1 {
+		t.Fatalf("expected replayed delete to be deduplicated, got %+v", replay)
+	}
+
+	restored := run(SyncChange{Operation: SyncChangeOperationRestore, ExternalID: "file_1", SourceVersion: "8"})
+	if restored.ProcessedCount != 1 || len(sink.records) != 2 || sink.records[1].Operation != SyncChangeOperationRestore {
+		t.Fatalf("expected restore to be applied, got %+v", restored)
+	}
+	identity, _, _ = identities.GetByExternalID(ctx, "binding_1", "file_1")
+	if identity.OrphanedAt != nil {
+		t.Fatalf("expected restore to clear orphaned mark, got %+v", identity)
+	}
+
+	if _, err := service.RunSyncImport(ctx, RunSyncImportRequest{
+		Plan:    SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{{Operation: "archive", ExternalID: "file_1"}},
+	}); err == nil {
+		t.Fatalf("expected unknown operation to fail the run")
+	}
+}
+
+func TestSyncExecutionServiceFailsDeletesWithoutDeleter(t *testing.T) {
+	service, err := NewSyncExecutionService(
+		newInMemorySyncCheckpointStore(),
+		newInMemorySyncChangeLogStore(),
+		WithSyncTargetSink(&recordingSyncTargetSink{}),
+	)
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	result, err := service.RunSyncImport(context.Background(), RunSyncImportRequest{
+		Plan:    SyncRunPlan{BindingID: "binding_1", Mode: SyncRunModeApply},
+		Changes: []SyncChange{{Operation: SyncChangeOperationDelete, ExternalID: "file_1"}},
+	})
+	if err != nil {
+		t.Fatalf("run import: %v", err)
+	}
+	if result.FailedCount != 1 || result.ProcessedCount != 0 {
+		t.Fatalf("expected delete to fail without a deleter, got %+v", result)
+	}
+
+	if _, err := syncBindingTombstoneRetention(SyncBinding{
+		ID: "binding_1", Metadata: map[string]any{SyncBindingMetadataTombstoneRetention: "soon"},
+	}, 0); err == nil {
+		t.Fatalf("expected invalid retention to be rejected")
+	}
+	retention, err := syncBindingTombstoneRetention(SyncBinding{
+		Metadata: map[string]any{SyncBindingMetadataTombstoneRetention: float64(3600)},
+	}, 0)
+	if err != nil || retention != time.Hour {
+		t.Fatalf("expected numeric retention in seconds, got %v %v", retention, err)
+	}
+}
@@ -243,7 +243,7 @@ func (s *memoryCredentialStore) GetActiveByConnection(_ context.Context, connect
 	defer s.mu.Unlock()
 	credential, ok := s.current[connectionID]
 	if !ok {
-		return Credential{}, fmt.Errorf("missing credential")
+		return Credential{}, fmt.Errorf("%w: connection %q", ErrCredentialNotFound, connectionID)
 	}
 	return credential, nil
 }
@@ -400,6 +400,15 @@ func (s *memorySubscriptionStore) Upsert(_ context.Context, in UpsertSubscriptio
 	defer s.mu.Unlock()
 	key := in.ProviderID + ":" + in.ChannelID
 	id := s.byPair[key]
+	if in.ID != "" {
+		previous, ok := s.byID[in.ID]
+		if !ok {
+			return Subscription{}, fmt.Errorf("missing subscription")
+		}
+		delete(s.byPair, previous.ProviderID+":"+previous.ChannelID)
+		id = in.ID
+		s.byPair[key] = id
+	}
 	if id == "" {
 		s.next++
 		id = fmt.Sprintf("sub_%d", s.next)
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS service_outbound_webhook_deliveries;
+DROP TABLE IF EXISTS service_outbound_webhook_endpoints;
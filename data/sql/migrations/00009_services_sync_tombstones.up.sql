@@ -0,0 +1,5 @@
+ALTER TABLE service_sync_change_log
+    ADD COLUMN IF NOT EXISTS operation TEXT NOT NULL DEFAULT 'update';
+
+ALTER TABLE service_identity_bindings
+    ADD COLUMN IF NOT EXISTS orphaned_at TIMESTAMPTZ;
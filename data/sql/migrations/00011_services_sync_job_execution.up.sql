@@ -0,0 +1,8 @@
+ALTER TABLE service_sync_jobs
+    ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+ALTER TABLE service_sync_jobs
+    ADD COLUMN IF NOT EXISTS progress JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_service_sync_jobs_status_lease
+    ON service_sync_jobs(status, lease_expires_at);
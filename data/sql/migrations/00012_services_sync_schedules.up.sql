@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS service_sync_schedules (
+    id TEXT PRIMARY KEY,
+    provider_id TEXT NOT NULL,
+    scope_type TEXT NOT NULL,
+    scope_id TEXT NOT NULL,
+    connection_id TEXT NOT NULL REFERENCES service_connections(id) ON DELETE CASCADE,
+    sync_binding_id TEXT NOT NULL DEFAULT '',
+    mode TEXT NOT NULL,
+    cron_expression TEXT NOT NULL DEFAULT '',
+    interval_seconds BIGINT NOT NULL DEFAULT 0,
+    jitter_seconds BIGINT NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    next_window_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    last_window_at TIMESTAMPTZ,
+    last_sync_job_id TEXT NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_sync_schedules_status_next_run
+    ON service_sync_schedules(status, next_run_at);
+CREATE INDEX IF NOT EXISTS idx_service_sync_schedules_connection_id
+    ON service_sync_schedules(connection_id);
+CREATE INDEX IF NOT EXISTS idx_service_sync_schedules_sync_binding_id
+    ON service_sync_schedules(sync_binding_id)
+    WHERE sync_binding_id <> '';
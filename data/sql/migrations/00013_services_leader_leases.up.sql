@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS service_leader_leases (
+    name TEXT PRIMARY KEY,
+    holder_id TEXT NOT NULL,
+    fencing_token BIGINT NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_service_lifecycle_outbox_partition;
+ALTER TABLE service_lifecycle_outbox DROP COLUMN IF EXISTS partition_key;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_service_lifecycle_outbox_projectors_status;
+DROP TABLE IF EXISTS service_lifecycle_outbox_projectors;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS service_webhook_event_outcomes (
+    id TEXT PRIMARY KEY,
+    provider_id TEXT NOT NULL,
+    delivery_id TEXT NOT NULL,
+    event_index INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(provider_id, delivery_id, event_index)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_service_sync_change_log_binding_direction_created;
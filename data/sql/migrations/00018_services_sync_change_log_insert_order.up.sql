@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_service_sync_change_log_binding_direction_created
+    ON service_sync_change_log(sync_binding_id, direction, created_at, id);
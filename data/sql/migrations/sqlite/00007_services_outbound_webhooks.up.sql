@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS service_outbound_webhook_endpoints (
+    id TEXT PRIMARY KEY,
+    scope_type TEXT NOT NULL,
+    scope_id TEXT NOT NULL,
+    url TEXT NOT NULL CHECK (trim(url) <> ''),
+    encrypted_secret BLOB NOT NULL,
+    event_filters TEXT NOT NULL DEFAULT '[]',
+    status TEXT NOT NULL,
+    disabled_reason TEXT NOT NULL DEFAULT '',
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    circuit_open_until DATETIME,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_outbound_webhook_endpoints_scope
+    ON service_outbound_webhook_endpoints(scope_type, scope_id, status);
+
+CREATE TABLE IF NOT EXISTS service_outbound_webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    endpoint_id TEXT NOT NULL REFERENCES service_outbound_webhook_endpoints(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_name TEXT NOT NULL,
+    payload BLOB NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(endpoint_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_outbound_webhook_deliveries_status_next_attempt
+    ON service_outbound_webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_service_outbound_webhook_deliveries_endpoint_created
+    ON service_outbound_webhook_deliveries(endpoint_id, created_at);
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_service_sync_change_log_binding_record
+    ON service_sync_change_log(sync_binding_id, direction, external_id, occurred_at DESC);
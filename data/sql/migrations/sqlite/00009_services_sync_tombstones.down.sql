@@ -0,0 +1,2 @@
+ALTER TABLE service_identity_bindings DROP COLUMN orphaned_at;
+ALTER TABLE service_sync_change_log DROP COLUMN operation;
@@ -0,0 +1,5 @@
+ALTER TABLE service_sync_change_log
+    ADD COLUMN operation TEXT NOT NULL DEFAULT 'update';
+
+ALTER TABLE service_identity_bindings
+    ADD COLUMN orphaned_at DATETIME;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS service_job_queue (
+    id TEXT PRIMARY KEY,
+    job_id TEXT NOT NULL,
+    script_path TEXT NOT NULL,
+    parameters TEXT NOT NULL DEFAULT '{}',
+    idempotency_key TEXT NOT NULL DEFAULT '',
+    dedup_policy TEXT NOT NULL DEFAULT '',
+    state TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at DATETIME NOT NULL,
+    lease_token TEXT NOT NULL DEFAULT '',
+    lease_expires_at DATETIME,
+    terminal_reason TEXT NOT NULL DEFAULT '',
+    enqueued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_job_queue_state_run_at
+    ON service_job_queue(state, run_at);
+CREATE INDEX IF NOT EXISTS idx_service_job_queue_idempotency_key
+    ON service_job_queue(idempotency_key, state)
+    WHERE idempotency_key <> '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_service_sync_jobs_status_lease;
+ALTER TABLE service_sync_jobs DROP COLUMN progress;
+ALTER TABLE service_sync_jobs DROP COLUMN lease_expires_at;
@@ -0,0 +1,8 @@
+ALTER TABLE service_sync_jobs
+    ADD COLUMN lease_expires_at DATETIME;
+
+ALTER TABLE service_sync_jobs
+    ADD COLUMN progress TEXT NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_service_sync_jobs_status_lease
+    ON service_sync_jobs(status, lease_expires_at);
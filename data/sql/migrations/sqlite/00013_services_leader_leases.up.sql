@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS service_leader_leases (
+    name TEXT PRIMARY KEY,
+    holder_id TEXT NOT NULL,
+    fencing_token INTEGER NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
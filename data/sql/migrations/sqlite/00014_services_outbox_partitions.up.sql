@@ -0,0 +1,9 @@
+ALTER TABLE service_lifecycle_outbox
+    ADD COLUMN partition_key TEXT NOT NULL DEFAULT '';
+
+UPDATE service_lifecycle_outbox
+SET partition_key = connection_id
+WHERE connection_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_service_lifecycle_outbox_partition
+    ON service_lifecycle_outbox(partition_key, status, occurred_at);
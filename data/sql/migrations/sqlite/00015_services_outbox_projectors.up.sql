@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS service_lifecycle_outbox_projectors (
+    id TEXT PRIMARY KEY,
+    event_id TEXT NOT NULL REFERENCES service_lifecycle_outbox(event_id) ON DELETE CASCADE,
+    projector TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(event_id, projector)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_lifecycle_outbox_projectors_status
+    ON service_lifecycle_outbox_projectors(projector, status);
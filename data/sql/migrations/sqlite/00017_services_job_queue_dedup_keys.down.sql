@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_service_job_queue_merge_key;
+DROP INDEX IF EXISTS uq_service_job_queue_drop_key;
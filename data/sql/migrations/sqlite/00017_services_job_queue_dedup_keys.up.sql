@@ -0,0 +1,27 @@
+UPDATE service_job_queue
+SET state = 'canceled',
+    terminal_reason = 'deduplicated before unique idempotency index'
+WHERE idempotency_key <> ''
+  AND dedup_policy IN ('drop', 'merge')
+  AND state IN ('accepted', 'retrying')
+  AND EXISTS (
+      SELECT 1
+      FROM service_job_queue AS earlier
+      WHERE earlier.idempotency_key = service_job_queue.idempotency_key
+        AND earlier.dedup_policy = service_job_queue.dedup_policy
+        AND earlier.state IN ('accepted', 'retrying', 'running')
+        AND (earlier.enqueued_at < service_job_queue.enqueued_at
+             OR (earlier.enqueued_at = service_job_queue.enqueued_at AND earlier.id < service_job_queue.id))
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_service_job_queue_drop_key
+    ON service_job_queue(idempotency_key)
+    WHERE idempotency_key <> ''
+      AND dedup_policy = 'drop'
+      AND state IN ('accepted', 'retrying', 'running');
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_service_job_queue_merge_key
+    ON service_job_queue(idempotency_key)
+    WHERE idempotency_key <> ''
+      AND dedup_policy = 'merge'
+      AND state IN ('accepted', 'retrying');
@@ -28,6 +28,11 @@ type Commands struct {
 	UpsertInstallation *servicescommand.UpsertInstallationCommand
 	UpdateInstallation *servicescommand.UpdateInstallationStatusCommand
 	CreateSyncJob      *servicescommand.CreateSyncJobCommand
+	CancelSyncJob      *servicescommand.CancelSyncJobCommand
+	PauseSyncJob       *servicescommand.PauseSyncJobCommand
+	ResumeSyncJob      *servicescommand.ResumeSyncJobCommand
+	UpsertSyncSchedule *servicescommand.UpsertSyncScheduleCommand
+	DeleteSyncSchedule *servicescommand.DeleteSyncScheduleCommand
 }
 
 type Queries struct {
@@ -36,6 +41,9 @@ type Queries struct {
 	GetInstallation      *servicesquery.GetInstallationQuery
 	ListInstallations    *servicesquery.ListInstallationsQuery
 	GetSyncJob           *servicesquery.GetSyncJobQuery
+	ListSyncJobs         *servicesquery.ListSyncJobsQuery
+	ListSyncSchedules    *servicesquery.ListSyncSchedulesQuery
+	ListSyncScheduleRuns *servicesquery.ListSyncScheduleRunsQuery
 }
 
 type Facade struct {
@@ -78,10 +86,40 @@ func NewFacade(service CommandQueryService, opts ...FacadeOption) (*Facade, erro
 	if syncJobService, ok := service.(servicescommand.SyncJobMutatingService); ok {
 		createSyncJobCommand = servicescommand.NewCreateSyncJobCommand(syncJobService)
 	}
+	var (
+		cancelSyncJobCommand *servicescommand.CancelSyncJobCommand
+		pauseSyncJobCommand  *servicescommand.PauseSyncJobCommand
+		resumeSyncJobCommand *servicescommand.ResumeSyncJobCommand
+	)
+	if controlService, ok := service.(servicescommand.SyncJobControlService); ok {
+		cancelSyncJobCommand = servicescommand.NewCancelSyncJobCommand(controlService)
+		pauseSyncJobCommand = servicescommand.NewPauseSyncJobCommand(controlService)
+		resumeSyncJobCommand = servicescommand.NewResumeSyncJobCommand(controlService)
+	}
 	var getSyncJobQuery *servicesquery.GetSyncJobQuery
 	if syncJobReader, ok := service.(servicesquery.SyncJobReader); ok {
 		getSyncJobQuery = servicesquery.NewGetSyncJobQuery(syncJobReader)
 	}
+	var listSyncJobsQuery *servicesquery.ListSyncJobsQuery
+	if syncJobLister, ok := service.(servicesquery.SyncJobLister); ok {
+		listSyncJobsQuery = servicesquery.NewListSyncJobsQuery(syncJobLister)
+	}
+	var (
+		upsertSyncScheduleCommand *servicescommand.UpsertSyncScheduleCommand
+		deleteSyncScheduleCommand *servicescommand.DeleteSyncScheduleCommand
+	)
+	if scheduleService, ok := service.(servicescommand.SyncScheduleMutatingService); ok {
+		upsertSyncScheduleCommand = servicescommand.NewUpsertSyncScheduleCommand(scheduleService)
+		deleteSyncScheduleCommand = servicescommand.NewDeleteSyncScheduleCommand(scheduleService)
+	}
+	var (
+		listSyncSchedulesQuery    *servicesquery.ListSyncSchedulesQuery
+		listSyncScheduleRunsQuery *servicesquery.ListSyncScheduleRunsQuery
+	)
+	if scheduleReader, ok := service.(servicesquery.SyncScheduleReader); ok {
+		listSyncSchedulesQuery = servicesquery.NewListSyncSchedulesQuery(scheduleReader)
+		listSyncScheduleRunsQuery = servicesquery.NewListSyncScheduleRunsQuery(scheduleReader)
+	}
 	facade.commands = Commands{
 		Connect:            servicescommand.NewConnectCommand(service),
 		StartReconsent:     servicescommand.NewStartReconsentCommand(service),
@@ -97,6 +135,11 @@ func NewFacade(service CommandQueryService, opts ...FacadeOption) (*Facade, erro
 		UpsertInstallation: servicescommand.NewUpsertInstallationCommand(service),
 		UpdateInstallation: servicescommand.NewUpdateInstallationStatusCommand(service),
 		CreateSyncJob:      createSyncJobCommand,
+		CancelSyncJob:      cancelSyncJobCommand,
+		PauseSyncJob:       pauseSyncJobCommand,
+		ResumeSyncJob:      resumeSyncJobCommand,
+		UpsertSyncSchedule: upsertSyncScheduleCommand,
+		DeleteSyncSchedule: deleteSyncScheduleCommand,
 	}
 	facade.queries = Queries{
 		LoadSyncCursor:       servicesquery.NewLoadSyncCursorQuery(service),
@@ -104,6 +147,9 @@ func NewFacade(service CommandQueryService, opts ...FacadeOption) (*Facade, erro
 		GetInstallation:      servicesquery.NewGetInstallationQuery(service),
 		ListInstallations:    servicesquery.NewListInstallationsQuery(service),
 		GetSyncJob:           getSyncJobQuery,
+		ListSyncJobs:         listSyncJobsQuery,
+		ListSyncSchedules:    listSyncSchedulesQuery,
+		ListSyncScheduleRuns: listSyncScheduleRunsQuery,
 	}
 
 	return facade, nil
@@ -0,0 +1,858 @@
+package installations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	// PurgeScriptPath is the job script path that purges the data of an
+	// uninstalled installation.
+	PurgeScriptPath = "services.installation.purge"
+	// ParamInstallationID is the purge job parameter naming the installation.
+	ParamInstallationID = "installation_id"
+
+	// DefaultInstallType is used when a signal does not name an install type.
+	DefaultInstallType = "app"
+	// DefaultPurgeDelay matches the 48 hour window Shopify allows between
+	// app/uninstalled and shop/redact.
+	DefaultPurgeDelay = 48 * time.Hour
+
+	eventSource = "services.installations"
+
+	metadataExternalInstallationID = "external_installation_id"
+	metadataGrantedScopes          = "granted_scopes"
+	metadataSuspendedConnections   = "suspended_connection_ids"
+	metadataPausedSyncBindings     = "paused_sync_binding_ids"
+	metadataStatusReason           = "status_reason"
+)
+
+var (
+	ErrInstallationNotFound    = errors.New("installations: installation not found")
+	ErrInstallationUninstalled = errors.New("installations: installation was uninstalled")
+	ErrInvalidSignal           = errors.New("installations: invalid signal")
+	ErrInstallationNotPurgable = errors.New("installations: only uninstalled installations can be purged")
+)
+
+// Signal is an installation lifecycle change reported by a provider.
+type Signal string
+
+const (
+	SignalInstalled     Signal = "installed"
+	SignalUninstalled   Signal = "uninstalled"
+	SignalSuspended     Signal = "suspended"
+	SignalUnsuspended   Signal = "unsuspended"
+	SignalScopesChanged Signal = "scopes_changed"
+)
+
+// Event is a provider signal about one installation. ExternalInstallationID
+// is the provider's identifier for the installation (a GitHub installation id,
+// a Shopify shop domain) and is recorded on the installation. Scopes is the
+// full set granted after the change.
+type Event struct {
+	ProviderID             string
+	Scope                  core.ScopeRef
+	InstallType            string
+	ExternalInstallationID string
+	Signal                 Signal
+	Scopes                 []string
+	Reason                 string
+	OccurredAt             time.Time
+	Metadata               map[string]any
+}
+
+// Result describes what applying a signal changed.
+type Result struct {
+	Installation         core.Installation
+	ConnectionIDs        []string
+	SubscriptionsUpdated int
+	SyncBindingsUpdated  int
+	ScopesAdded          []string
+	ScopesRemoved        []string
+	PurgeAt              *time.Time
+}
+
+// InstallationService is the installation and connection API the orchestrator
+// drives; *core.Service implements it.
+type InstallationService interface {
+	UpsertInstallation(ctx context.Context, in core.UpsertInstallationInput) (core.Installation, error)
+	GetInstallation(ctx context.Context, id string) (core.Installation, error)
+	ListInstallations(ctx context.Context, providerID string, scope core.ScopeRef) ([]core.Installation, error)
+	Revoke(ctx context.Context, connectionID string, reason string) error
+}
+
+type SubscriptionStore interface {
+	ListByConnection(ctx context.Context, connectionID string) ([]core.Subscription, error)
+	UpdateState(ctx context.Context, id string, status core.SubscriptionStatus, reason string) error
+}
+
+// PurgeRequest names the installation whose data should be deleted and the
+// connections it authorized.
+type PurgeRequest struct {
+	Installation  core.Installation
+	ConnectionIDs []string
+}
+
+// DataPurger deletes the application data synced through an installation.
+type DataPurger interface {
+	PurgeInstallation(ctx context.Context, req PurgeRequest) error
+}
+
+// HookRequest is the installation state a Hook sees once a signal has been
+// applied, with the connections the installation authorizes.
+type HookRequest struct {
+	Event        Event
+	Installation core.Installation
+	Connections  []core.Connection
+}
+
+// Hook runs provider setup after an installed or scopes_changed signal, such
+// as registering the webhooks the granted scopes allow. A hook error fails
+// the signal so it can be applied again.
+type Hook interface {
+	AfterInstallationChange(ctx context.Context, req HookRequest) error
+}
+
+type Option func(*Orchestrator)
+
+func WithSubscriptionStore(store SubscriptionStore) Option {
+	return func(o *Orchestrator) {
+		if o == nil || store == nil {
+			return
+		}
+		o.subscriptions = store
+	}
+}
+
+func WithSyncBindingStore(store core.SyncBindingStore) Option {
+	return func(o *Orchestrator) {
+		if o == nil || store == nil {
+			return
+		}
+		o.syncBindings = store
+	}
+}
+
+func WithEventBus(bus core.LifecycleEventBus) Option {
+	return func(o *Orchestrator) {
+		if o == nil || bus == nil {
+			return
+		}
+		o.events = bus
+	}
+}
+
+// WithPurgeScheduler enqueues a purge job delay after each uninstall. A
+// non-positive delay uses DefaultPurgeDelay.
+func WithPurgeScheduler(enqueuer core.JobScheduledEnqueuer, delay time.Duration) Option {
+	return func(o *Orchestrator) {
+		if o == nil || enqueuer == nil {
+			return
+		}
+		o.purgeJobs = enqueuer
+		if delay > 0 {
+			o.purgeDelay = delay
+		}
+	}
+}
+
+func WithDataPurger(purger DataPurger) Option {
+	return func(o *Orchestrator) {
+		if o == nil || purger == nil {
+			return
+		}
+		o.purger = purger
+	}
+}
+
+func WithHooks(hooks ...Hook) Option {
+	return func(o *Orchestrator) {
+		if o == nil {
+			return
+		}
+		for _, hook := range hooks {
+			if hook != nil {
+				o.hooks = append(o.hooks, hook)
+			}
+		}
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(o *Orchestrator) {
+		if o == nil || now == nil {
+			return
+		}
+		o.now = now
+	}
+}
+
+// Orchestrator applies installation signals and cascades them to the
+// connections the installation authorizes: every connection of the provider
+// in the installation scope. Cascades run before the installation status
+// changes, so a signal that fails part way can be applied again.
+type Orchestrator struct {
+	service       InstallationService
+	connections   core.ConnectionStore
+	subscriptions SubscriptionStore
+	syncBindings  core.SyncBindingStore
+	events        core.LifecycleEventBus
+	purgeJobs     core.JobScheduledEnqueuer
+	purgeDelay    time.Duration
+	purger        DataPurger
+	hooks         []Hook
+	now           func() time.Time
+}
+
+func NewOrchestrator(service InstallationService, connections core.ConnectionStore, opts ...Option) (*Orchestrator, error) {
+	if service == nil {
+		return nil, fmt.Errorf("installations: installation service is required")
+	}
+	if connections == nil {
+		return nil, fmt.Errorf("installations: connection store is required")
+	}
+	orchestrator := &Orchestrator{
+		service:     service,
+		connections: connections,
+		purgeDelay:  DefaultPurgeDelay,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(orchestrator)
+	}
+	return orchestrator, nil
+}
+
+// Apply processes one installation signal.
+func (o *Orchestrator) Apply(ctx context.Context, event Event) (Result, error) {
+	if o == nil || o.service == nil {
+		return Result{}, fmt.Errorf("installations: orchestrator is not configured")
+	}
+	event, err := normalizeEvent(event, o.now())
+	if err != nil {
+		return Result{}, err
+	}
+	switch event.Signal {
+	case SignalInstalled:
+		return o.install(ctx, event)
+	case SignalUninstalled:
+		return o.uninstall(ctx, event)
+	case SignalSuspended:
+		return o.suspend(ctx, event)
+	case SignalUnsuspended:
+		return o.unsuspend(ctx, event)
+	case SignalScopesChanged:
+		return o.changeScopes(ctx, event)
+	default:
+		return Result{}, fmt.Errorf("%w: %q", ErrInvalidSignal, event.Signal)
+	}
+}
+
+// LinkedConnections returns the connections the installation authorizes.
+func (o *Orchestrator) LinkedConnections(ctx context.Context, installation core.Installation) ([]core.Connection, error) {
+	if o == nil || o.connections == nil {
+		return nil, fmt.Errorf("installations: orchestrator is not configured")
+	}
+	connections, err := o.connections.FindByScope(ctx, installation.ProviderID, core.ScopeRef{
+		Type: installation.ScopeType,
+		ID:   installation.ScopeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(connections, func(i, j int) bool { return connections[i].ID < connections[j].ID })
+	return connections, nil
+}
+
+// Purge deletes the data of an uninstalled installation through the
+// configured DataPurger. It is what the PurgeScriptPath job runs.
+func (o *Orchestrator) Purge(ctx context.Context, installationID string) error {
+	if o == nil || o.service == nil {
+		return fmt.Errorf("installations: orchestrator is not configured")
+	}
+	if o.purger == nil {
+		return fmt.Errorf("installations: data purger is required")
+	}
+	installation, err := o.service.GetInstallation(ctx, strings.TrimSpace(installationID))
+	if err != nil {
+		return err
+	}
+	if installation.Status != core.InstallationStatusUninstalled {
+		return fmt.Errorf("%w: installation %q is %s", ErrInstallationNotPurgable, installation.ID, installation.Status)
+	}
+	// A reinstall shares the scope, and so the connections, of the
+	// installation it replaced; purging now would delete its data.
+	current, found, err := o.find(ctx, Event{
+		ProviderID:  installation.ProviderID,
+		Scope:       core.ScopeRef{Type: installation.ScopeType, ID: installation.ScopeID},
+		InstallType: installation.InstallType,
+	})
+	if err != nil {
+		return err
+	}
+	if found && current.ID != installation.ID && current.Status != core.InstallationStatusUninstalled {
+		return fmt.Errorf("%w: installation %q was reinstalled as %q", ErrInstallationNotPurgable, installation.ID, current.ID)
+	}
+	connections, err := o.LinkedConnections(ctx, installation)
+	if err != nil {
+		return err
+	}
+	connectionIDs := connectionIDs(connections)
+	if err := o.purger.PurgeInstallation(ctx, PurgeRequest{Installation: installation, ConnectionIDs: connectionIDs}); err != nil {
+		return err
+	}
+	return o.publish(ctx, installation, "purged", o.now(), connectionIDs, nil)
+}
+
+// install activates the installation. Uninstalled installations are final, so
+// a reinstall of the same scope is recorded as a new installation.
+func (o *Orchestrator) install(ctx context.Context, event Event) (Result, error) {
+	existing, found, err := o.find(ctx, event)
+	if err != nil {
+		return Result{}, err
+	}
+	if found && existing.Status == core.InstallationStatusUninstalled {
+		existing, found = core.Installation{}, false
+	}
+	metadata := mergeMetadata(existing.Metadata, event.Metadata)
+	metadata[metadataExternalInstallationID] = event.ExternalInstallationID
+	if event.Scopes != nil {
+		metadata[metadataGrantedScopes] = event.Scopes
+	}
+	grantedAt := event.OccurredAt
+	if found && existing.GrantedAt != nil {
+		grantedAt = *existing.GrantedAt
+	}
+	installation, err := o.service.UpsertInstallation(ctx, core.UpsertInstallationInput{
+		ProviderID:  event.ProviderID,
+		Scope:       event.Scope,
+		InstallType: event.InstallType,
+		Status:      core.InstallationStatusActive,
+		GrantedAt:   &grantedAt,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	connections, err := o.LinkedConnections(ctx, installation)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Installation: installation, ConnectionIDs: connectionIDs(connections), ScopesAdded: event.Scopes}
+	if err := o.runHooks(ctx, event, installation, connections); err != nil {
+		return result, err
+	}
+	return result, o.publish(ctx, installation, string(event.Signal), event.OccurredAt, result.ConnectionIDs, nil)
+}
+
+func (o *Orchestrator) uninstall(ctx context.Context, event Event) (Result, error) {
+	existing, err := o.require(ctx, event)
+	if err != nil {
+		return Result{}, err
+	}
+	if existing.Status == core.InstallationStatusUninstalled {
+		return Result{Installation: existing}, nil
+	}
+	connections, err := o.LinkedConnections(ctx, existing)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{ConnectionIDs: connectionIDs(connections)}
+	reason := reasonOr(event.Reason, "installation uninstalled")
+	for _, connection := range connections {
+		updated, err := o.cancelSubscriptions(ctx, connection.ID, reason)
+		result.SubscriptionsUpdated += updated
+		if err != nil {
+			return result, err
+		}
+		bindingIDs, err := o.setSyncBindings(ctx, connection.ID, nil, core.SyncBindingStatusDisabled, reason)
+		result.SyncBindingsUpdated += len(bindingIDs)
+		if err != nil {
+			return result, err
+		}
+		if connection.Status == core.ConnectionStatusDisconnected {
+			continue
+		}
+		if err := o.service.Revoke(ctx, connection.ID, reason); err != nil {
+			return result, err
+		}
+	}
+
+	metadata := mergeMetadata(existing.Metadata, event.Metadata)
+	metadata[metadataStatusReason] = reason
+	revokedAt := event.OccurredAt
+	installation, err := o.upsertStatus(ctx, existing, core.InstallationStatusUninstalled, metadata, &revokedAt)
+	if err != nil {
+		return result, err
+	}
+	result.Installation = installation
+
+	var extra map[string]any
+	if o.purgeJobs != nil {
+		purgeAt := o.now().Add(o.purgeDelay)
+		if _, err := o.purgeJobs.EnqueueAfter(ctx, NewPurgeMessage(installation), o.purgeDelay); err != nil {
+			return result, fmt.Errorf("installations: schedule purge of installation %q: %w", installation.ID, err)
+		}
+		result.PurgeAt = &purgeAt
+		extra = map[string]any{"purge_at": purgeAt.Format(time.RFC3339)}
+	}
+	return result, o.publish(ctx, installation, string(event.Signal), event.OccurredAt, result.ConnectionIDs, extra)
+}
+
+// suspend pauses the linked connections and sync bindings, remembering which
+// ones it paused so unsuspend restores only those.
+func (o *Orchestrator) suspend(ctx context.Context, event Event) (Result, error) {
+	existing, err := o.require(ctx, event)
+	if err != nil {
+		return Result{}, err
+	}
+	if existing.Status == core.InstallationStatusSuspended {
+		return Result{Installation: existing}, nil
+	}
+	connections, err := o.LinkedConnections(ctx, existing)
+	if err != nil {
+		return Result{}, err
+	}
+	reason := reasonOr(event.Reason, "installation suspended")
+	suspended := stringSliceMetadata(existing.Metadata, metadataSuspendedConnections)
+	paused := stringSliceMetadata(existing.Metadata, metadataPausedSyncBindings)
+	result := Result{}
+	for _, connection := range connections {
+		bindingIDs, err := o.setSyncBindings(ctx, connection.ID, []core.SyncBindingStatus{core.SyncBindingStatusActive}, core.SyncBindingStatusPaused, reason)
+		paused = appendUnique(paused, bindingIDs...)
+		result.SyncBindingsUpdated += len(bindingIDs)
+		if err != nil {
+			return result, err
+		}
+		if connection.Status != core.ConnectionStatusActive {
+			continue
+		}
+		if err := o.connections.UpdateStatus(ctx, connection.ID, core.ConnectionStatusErrored, reason); err != nil {
+			return result, err
+		}
+		suspended = appendUnique(suspended, connection.ID)
+	}
+	result.ConnectionIDs = suspended
+
+	metadata := mergeMetadata(existing.Metadata, event.Metadata)
+	metadata[metadataStatusReason] = reason
+	metadata[metadataSuspendedConnections] = suspended
+	metadata[metadataPausedSyncBindings] = paused
+	installation, err := o.upsertStatus(ctx, existing, core.InstallationStatusSuspended, metadata, nil)
+	if err != nil {
+		return result, err
+	}
+	result.Installation = installation
+	return result, o.publish(ctx, installation, string(event.Signal), event.OccurredAt, result.ConnectionIDs, nil)
+}
+
+func (o *Orchestrator) unsuspend(ctx context.Context, event Event) (Result, error) {
+	existing, err := o.require(ctx, event)
+	if err != nil {
+		return Result{}, err
+	}
+	if existing.Status != core.InstallationStatusSuspended {
+		return Result{Installation: existing}, nil
+	}
+	result := Result{ConnectionIDs: stringSliceMetadata(existing.Metadata, metadataSuspendedConnections)}
+	for _, connectionID := range result.ConnectionIDs {
+		connection, err := o.connections.Get(ctx, connectionID)
+		if err != nil {
+			return result, err
+		}
+		// Leave connections that moved on while suspended, such as a
+		// revoked one, where they are.
+		if connection.Status != core.ConnectionStatusErrored {
+			continue
+		}
+		if err := o.connections.UpdateStatus(ctx, connectionID, core.ConnectionStatusActive, ""); err != nil {
+			return result, err
+		}
+	}
+	if o.syncBindings != nil {
+		for _, bindingID := range stringSliceMetadata(existing.Metadata, metadataPausedSyncBindings) {
+			binding, err := o.syncBindings.Get(ctx, bindingID)
+			if err != nil {
+				return result, err
+			}
+			if binding.Status != core.SyncBindingStatusPaused {
+				continue
+			}
+			if err := o.syncBindings.UpdateStatus(ctx, bindingID, core.SyncBindingStatusActive, ""); err != nil {
+				return result, err
+			}
+			result.SyncBindingsUpdated++
+		}
+	}
+
+	metadata := mergeMetadata(existing.Metadata, event.Metadata)
+	delete(metadata, metadataStatusReason)
+	delete(metadata, metadataSuspendedConnections)
+	delete(metadata, metadataPausedSyncBindings)
+	installation, err := o.upsertStatus(ctx, existing, core.InstallationStatusActive, metadata, nil)
+	if err != nil {
+		return result, err
+	}
+	result.Installation = installation
+	return result, o.publish(ctx, installation, string(event.Signal), event.OccurredAt, result.ConnectionIDs, nil)
+}
+
+// changeScopes records the granted scopes. Removing a scope moves the linked
+// connections and the installation to needs_reconsent, since calls relying on
+// it will start failing; added scopes are only recorded.
+func (o *Orchestrator) changeScopes(ctx context.Context, event Event) (Result, error) {
+	existing, err := o.require(ctx, event)
+	if err != nil {
+		return Result{}, err
+	}
+	if existing.Status == core.InstallationStatusUninstalled {
+		return Result{}, fmt.Errorf("%w: %s", ErrInstallationUninstalled, existing.ID)
+	}
+	previous := stringSliceMetadata(existing.Metadata, metadataGrantedScopes)
+	result := Result{
+		ScopesAdded:   difference(event.Scopes, previous),
+		ScopesRemoved: difference(previous, event.Scopes),
+	}
+	status := existing.Status
+	if len(result.ScopesRemoved) > 0 {
+		connections, err := o.LinkedConnections(ctx, existing)
+		if err != nil {
+			return Result{}, err
+		}
+		reason := reasonOr(event.Reason, "installation scopes removed: "+strings.Join(result.ScopesRemoved, ", "))
+		for _, connection := range connections {
+			if connection.Status != core.ConnectionStatusActive {
+				continue
+			}
+			if err := o.connections.UpdateStatus(ctx, connection.ID, core.ConnectionStatusNeedsReconsent, reason); err != nil {
+				return result, err
+			}
+			result.ConnectionIDs = append(result.ConnectionIDs, connection.ID)
+		}
+		if status == core.InstallationStatusActive {
+			status = core.InstallationStatusNeedsReconsent
+		}
+	}
+
+	metadata := mergeMetadata(existing.Metadata, event.Metadata)
+	metadata[metadataGrantedScopes] = event.Scopes
+	installation, err := o.upsertStatus(ctx, existing, status, metadata, nil)
+	if err != nil {
+		return result, err
+	}
+	result.Installation = installation
+	if len(o.hooks) > 0 {
+		connections, err := o.LinkedConnections(ctx, installation)
+		if err != nil {
+			return result, err
+		}
+		if err := o.runHooks(ctx, event, installation, connections); err != nil {
+			return result, err
+		}
+	}
+	return result, o.publish(ctx, installation, string(event.Signal), event.OccurredAt, result.ConnectionIDs, map[string]any{
+		"scopes_added":   result.ScopesAdded,
+		"scopes_removed": result.ScopesRemoved,
+	})
+}
+
+// find returns the installation of the event's install type, preferring one
+// that is not uninstalled when the scope was reinstalled.
+func (o *Orchestrator) find(ctx context.Context, event Event) (core.Installation, bool, error) {
+	installations, err := o.service.ListInstallations(ctx, event.ProviderID, event.Scope)
+	if err != nil {
+		return core.Installation{}, false, err
+	}
+	var uninstalled core.Installation
+	found := false
+	for _, installation := range installations {
+		if !strings.EqualFold(strings.TrimSpace(installation.InstallType), event.InstallType) {
+			continue
+		}
+		if installation.Status != core.InstallationStatusUninstalled {
+			return installation, true, nil
+		}
+		if !found {
+			uninstalled, found = installation, true
+		}
+	}
+	return uninstalled, found, nil
+}
+
+func (o *Orchestrator) require(ctx context.Context, event Event) (core.Installation, error) {
+	installation, found, err := o.find(ctx, event)
+	if err != nil {
+		return core.Installation{}, err
+	}
+	if !found {
+		return core.Installation{}, fmt.Errorf(
+			"%w: provider %q scope %s/%s install type %q",
+			ErrInstallationNotFound,
+			event.ProviderID,
+			event.Scope.Type,
+			event.Scope.ID,
+			event.InstallType,
+		)
+	}
+	return installation, nil
+}
+
+func (o *Orchestrator) upsertStatus(
+	ctx context.Context,
+	existing core.Installation,
+	status core.InstallationStatus,
+	metadata map[string]any,
+	revokedAt *time.Time,
+) (core.Installation, error) {
+	return o.service.UpsertInstallation(ctx, core.UpsertInstallationInput{
+		ProviderID:  existing.ProviderID,
+		Scope:       core.ScopeRef{Type: existing.ScopeType, ID: existing.ScopeID},
+		InstallType: existing.InstallType,
+		Status:      status,
+		GrantedAt:   existing.GrantedAt,
+		RevokedAt:   revokedAt,
+		Metadata:    metadata,
+	})
+}
+
+func (o *Orchestrator) runHooks(
+	ctx context.Context,
+	event Event,
+	installation core.Installation,
+	connections []core.Connection,
+) error {
+	for _, hook := range o.hooks {
+		err := hook.AfterInstallationChange(ctx, HookRequest{
+			Event:        event,
+			Installation: installation,
+			Connections:  append([]core.Connection{}, connections...),
+		})
+		if err != nil {
+			return fmt.Errorf("installations: %s hook for installation %q: %w", event.Signal, installation.ID, err)
+		}
+	}
+	return nil
+}
+
+// cancelSubscriptions marks the connection's active subscriptions cancelled
+// without calling the provider, which has already dropped them.
+func (o *Orchestrator) cancelSubscriptions(ctx context.Context, connectionID string, reason string) (int, error) {
+	if o.subscriptions == nil {
+		return 0, nil
+	}
+	subscriptions, err := o.subscriptions.ListByConnection(ctx, connectionID)
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for _, subscription := range subscriptions {
+		if subscription.Status != core.SubscriptionStatusActive {
+			continue
+		}
+		if err := o.subscriptions.UpdateState(ctx, subscription.ID, core.SubscriptionStatusCancelled, reason); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// setSyncBindings moves the connection's bindings whose status is in from
+// (any status other than target when from is empty) to target and returns
+// the ids it changed.
+func (o *Orchestrator) setSyncBindings(
+	ctx context.Context,
+	connectionID string,
+	from []core.SyncBindingStatus,
+	target core.SyncBindingStatus,
+	reason string,
+) ([]string, error) {
+	if o.syncBindings == nil {
+		return nil, nil
+	}
+	bindings, err := o.syncBindings.ListByConnection(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	updated := []string{}
+	for _, binding := range bindings {
+		if binding.Status == target || (len(from) > 0 && !slices.Contains(from, binding.Status)) {
+			continue
+		}
+		if err := o.syncBindings.UpdateStatus(ctx, binding.ID, target, reason); err != nil {
+			return updated, err
+		}
+		updated = append(updated, binding.ID)
+	}
+	return updated, nil
+}
+
+func (o *Orchestrator) publish(
+	ctx context.Context,
+	installation core.Installation,
+	action string,
+	occurredAt time.Time,
+	connectionIDs []string,
+	extra map[string]any,
+) error {
+	if o.events == nil {
+		return nil
+	}
+	name := "services.installation." + action
+	payload := map[string]any{
+		"installation_id": installation.ID,
+		"install_type":    installation.InstallType,
+		"status":          string(installation.Status),
+		"connection_ids":  append([]string{}, connectionIDs...),
+	}
+	for key, value := range extra {
+		payload[key] = value
+	}
+	return o.events.Publish(ctx, core.LifecycleEvent{
+		ID:         fmt.Sprintf("installation:%s:%s:%d", installation.ID, action, occurredAt.UnixNano()),
+		Name:       name,
+		ProviderID: installation.ProviderID,
+		ScopeType:  installation.ScopeType,
+		ScopeID:    installation.ScopeID,
+		Source:     eventSource,
+		OccurredAt: occurredAt,
+		Payload:    payload,
+		Metadata:   mergeMetadata(nil, installation.Metadata),
+	})
+}
+
+// NewPurgeMessage builds the job message that purges an uninstalled
+// installation. Repeated messages are dropped while one is pending.
+func NewPurgeMessage(installation core.Installation) *core.JobExecutionMessage {
+	return &core.JobExecutionMessage{
+		JobID:      PurgeScriptPath,
+		ScriptPath: PurgeScriptPath,
+		Parameters: map[string]any{
+			ParamInstallationID: installation.ID,
+			"provider_id":       installation.ProviderID,
+		},
+		IdempotencyKey: "installation_purge:" + installation.ID,
+		DedupPolicy:    "drop",
+	}
+}
+
+func normalizeEvent(event Event, now time.Time) (Event, error) {
+	event.ProviderID = strings.TrimSpace(strings.ToLower(event.ProviderID))
+	event.Scope = core.ScopeRef{
+		Type: strings.TrimSpace(strings.ToLower(event.Scope.Type)),
+		ID:   strings.TrimSpace(event.Scope.ID),
+	}
+	event.InstallType = strings.TrimSpace(strings.ToLower(event.InstallType))
+	if event.InstallType == "" {
+		event.InstallType = DefaultInstallType
+	}
+	event.ExternalInstallationID = strings.TrimSpace(event.ExternalInstallationID)
+	event.Signal = Signal(strings.TrimSpace(strings.ToLower(string(event.Signal))))
+	event.Reason = strings.TrimSpace(event.Reason)
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = now
+	}
+	event.OccurredAt = event.OccurredAt.UTC()
+	if event.Scopes != nil {
+		event.Scopes = normalizeScopes(event.Scopes)
+	}
+	if event.ProviderID == "" {
+		return Event{}, fmt.Errorf("installations: provider id is required")
+	}
+	if err := event.Scope.Validate(); err != nil {
+		return Event{}, err
+	}
+	if event.Signal == "" {
+		return Event{}, fmt.Errorf("%w: signal is required", ErrInvalidSignal)
+	}
+	return event, nil
+}
+
+func normalizeScopes(scopes []string) []string {
+	out := []string{}
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || slices.Contains(out, scope) {
+			continue
+		}
+		out = append(out, scope)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func mergeMetadata(base map[string]any, overlay map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		out[key] = value
+	}
+	for key, value := range overlay {
+		out[key] = value
+	}
+	return out
+}
+
+// stringSliceMetadata reads a string list from metadata, which holds []any
+// once it has been round-tripped through JSON.
+func stringSliceMetadata(metadata map[string]any, key string) []string {
+	switch values := metadata[key].(type) {
+	case []string:
+		return append([]string{}, values...)
+	case []any:
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			if text, ok := value.(string); ok && strings.TrimSpace(text) != "" {
+				out = append(out, strings.TrimSpace(text))
+			}
+		}
+		return out
+	default:
+		return []string{}
+	}
+}
+
+func connectionIDs(connections []core.Connection) []string {
+	out := make([]string, 0, len(connections))
+	for _, connection := range connections {
+		out = append(out, connection.ID)
+	}
+	return out
+}
+
+func appendUnique(values []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(values, item) {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func difference(left []string, right []string) []string {
+	out := []string{}
+	for _, value := range left {
+		if !slices.Contains(right, value) {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func reasonOr(reason string, fallback string) string {
+	if strings.TrimSpace(reason) != "" {
+		return strings.TrimSpace(reason)
+	}
+	return fallback
+}
@@ -0,0 +1,642 @@
+package installations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestOrchestrator_UninstallCascadesAndSchedulesPurge(t *testing.T) {
+	ctx := context.Background()
+	fx := newOrchestratorFixture(t)
+	scope := core.ScopeRef{Type: "org", ID: "org_1"}
+	first := fx.connections.add("github", scope, core.ConnectionStatusActive)
+	second := fx.connections.add("github", scope, core.ConnectionStatusPendingReauth)
+	other := fx.connections.add("github", core.ScopeRef{Type: "org", ID: "org_2"}, core.ConnectionStatusActive)
+	fx.subscriptions.add(first.ID, core.SubscriptionStatusActive)
+	fx.subscriptions.add(second.ID, core.SubscriptionStatusExpired)
+	fx.bindings.add(first.ID, core.SyncBindingStatusActive)
+	fx.bindings.add(second.ID, core.SyncBindingStatusPaused)
+
+	installed, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID:             "GitHub",
+		Scope:                  scope,
+		ExternalInstallationID: "42",
+		Signal:                 SignalInstalled,
+		Scopes:                 []string{"issues:write", "contents:read", "issues:write"},
+	})
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if installed.Installation.Status != core.InstallationStatusActive ||
+		installed.Installation.InstallType != DefaultInstallType ||
+		installed.Installation.Metadata[metadataExternalInstallationID] != "42" ||
+		strings.Join(installed.ConnectionIDs, ",") != first.ID+","+second.ID {
+		t.Fatalf("unexpected install result %+v", installed)
+	}
+
+	uninstalled, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalUninstalled})
+	if err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if uninstalled.Installation.Status != core.InstallationStatusUninstalled || uninstalled.Installation.RevokedAt == nil {
+		t.Fatalf("unexpected uninstalled installation %+v", uninstalled.Installation)
+	}
+	if uninstalled.SubscriptionsUpdated != 1 || uninstalled.SyncBindingsUpdated != 2 {
+		t.Fatalf("expected the active subscription and both bindings to be updated, got %+v", uninstalled)
+	}
+	for _, id := range []string{first.ID, second.ID} {
+		if status := fx.connections.status(id); status != core.ConnectionStatusDisconnected {
+			t.Fatalf("expected connection %s to be revoked, got %s", id, status)
+		}
+	}
+	if status := fx.connections.status(other.ID); status != core.ConnectionStatusActive {
+		t.Fatalf("expected connection outside the scope to be untouched, got %s", status)
+	}
+	for _, binding := range fx.bindings.all() {
+		if binding.Status != core.SyncBindingStatusDisabled {
+			t.Fatalf("expected binding %s to be disabled, got %s", binding.ID, binding.Status)
+		}
+	}
+	if uninstalled.PurgeAt == nil || !uninstalled.PurgeAt.Equal(fx.now.Add(DefaultPurgeDelay)) {
+		t.Fatalf("expected purge after the default delay, got %v", uninstalled.PurgeAt)
+	}
+	if len(fx.jobs.messages) != 1 || fx.jobs.delays[0] != DefaultPurgeDelay ||
+		fx.jobs.messages[0].Parameters[ParamInstallationID] != installed.Installation.ID {
+		t.Fatalf("unexpected purge job %+v", fx.jobs.messages)
+	}
+
+	// A redelivered uninstall is a no-op.
+	if again, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalUninstalled}); err != nil ||
+		len(fx.jobs.messages) != 1 || again.Installation.ID != installed.Installation.ID {
+		t.Fatalf("expected repeated uninstall to be ignored, got %+v %v", again, err)
+	}
+
+	if err := fx.orchestrator.Purge(ctx, installed.Installation.ID); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if len(fx.purger.requests) != 1 || strings.Join(fx.purger.requests[0].ConnectionIDs, ",") != first.ID+","+second.ID {
+		t.Fatalf("unexpected purge requests %+v", fx.purger.requests)
+	}
+	if names := fx.events.names(); strings.Join(names, ",") !=
+		"services.installation.installed,services.installation.uninstalled,services.installation.purged" {
+		t.Fatalf("unexpected lifecycle events %v", names)
+	}
+
+	// Uninstalled installations are final, so a reinstall is recorded as a
+	// new installation and the old one can no longer be purged.
+	fx.now = fx.now.Add(time.Hour)
+	reinstalled, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID:             "github",
+		Scope:                  scope,
+		ExternalInstallationID: "43",
+		Signal:                 SignalInstalled,
+	})
+	if err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	if reinstalled.Installation.ID == installed.Installation.ID ||
+		reinstalled.Installation.Status != core.InstallationStatusActive ||
+		reinstalled.Installation.Metadata[metadataExternalInstallationID] != "43" ||
+		reinstalled.Installation.GrantedAt == nil || !reinstalled.Installation.GrantedAt.Equal(fx.now) {
+		t.Fatalf("expected a fresh active installation, got %+v", reinstalled.Installation)
+	}
+	if previous, _ := fx.service.GetInstallation(ctx, installed.Installation.ID); previous.Status != core.InstallationStatusUninstalled {
+		t.Fatalf("expected the previous installation to stay uninstalled, got %s", previous.Status)
+	}
+	if err := fx.orchestrator.Purge(ctx, installed.Installation.ID); !errors.Is(err, ErrInstallationNotPurgable) {
+		t.Fatalf("expected purge of a reinstalled scope to be refused, got %v", err)
+	}
+	suspended, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalSuspended})
+	if err != nil || suspended.Installation.ID != reinstalled.Installation.ID {
+		t.Fatalf("expected later signals to reach the reinstalled installation, got %+v %v", suspended.Installation, err)
+	}
+}
+
+func TestOrchestrator_SuspendRestoresOnlyWhatItPaused(t *testing.T) {
+	ctx := context.Background()
+	fx := newOrchestratorFixture(t)
+	scope := core.ScopeRef{Type: "org", ID: "org_1"}
+	connection := fx.connections.add("github", scope, core.ConnectionStatusActive)
+	active := fx.bindings.add(connection.ID, core.SyncBindingStatusActive)
+	paused := fx.bindings.add(connection.ID, core.SyncBindingStatusPaused)
+	if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalInstalled}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	suspended, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalSuspended})
+	if err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+	if suspended.Installation.Status != core.InstallationStatusSuspended || suspended.SyncBindingsUpdated != 1 {
+		t.Fatalf("unexpected suspend result %+v", suspended)
+	}
+	if fx.connections.status(connection.ID) != core.ConnectionStatusErrored || fx.bindings.status(active.ID) != core.SyncBindingStatusPaused {
+		t.Fatalf("expected connection errored and binding paused while suspended")
+	}
+
+	if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "github", Scope: scope, Signal: SignalUnsuspended}); err != nil {
+		t.Fatalf("unsuspend: %v", err)
+	}
+	if fx.connections.status(connection.ID) != core.ConnectionStatusActive || fx.bindings.status(active.ID) != core.SyncBindingStatusActive {
+		t.Fatalf("expected connection and binding to be restored")
+	}
+	if fx.bindings.status(paused.ID) != core.SyncBindingStatusPaused {
+		t.Fatalf("expected binding paused before the suspension to stay paused")
+	}
+	installation, _ := fx.service.GetInstallation(ctx, suspended.Installation.ID)
+	if installation.Status != core.InstallationStatusActive || installation.Metadata[metadataSuspendedConnections] != nil {
+		t.Fatalf("unexpected unsuspended installation %+v", installation)
+	}
+}
+
+func TestOrchestrator_ScopeRemovalRequiresReconsent(t *testing.T) {
+	ctx := context.Background()
+	fx := newOrchestratorFixture(t)
+	scope := core.ScopeRef{Type: "org", ID: "org_1"}
+	connection := fx.connections.add("shopify", scope, core.ConnectionStatusActive)
+	if _, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID: "shopify",
+		Scope:      scope,
+		Signal:     SignalInstalled,
+		Scopes:     []string{"read_products", "read_orders"},
+	}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+
+	added, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID: "shopify",
+		Scope:      scope,
+		Signal:     SignalScopesChanged,
+		Scopes:     []string{"read_products", "read_orders", "write_orders"},
+	})
+	if err != nil {
+		t.Fatalf("add scope: %v", err)
+	}
+	if strings.Join(added.ScopesAdded, ",") != "write_orders" || len(added.ScopesRemoved) != 0 ||
+		added.Installation.Status != core.InstallationStatusActive {
+		t.Fatalf("unexpected scope addition result %+v", added)
+	}
+
+	removed, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID: "shopify",
+		Scope:      scope,
+		Signal:     SignalScopesChanged,
+		Scopes:     []string{"write_orders"},
+	})
+	if err != nil {
+		t.Fatalf("remove scopes: %v", err)
+	}
+	if strings.Join(removed.ScopesRemoved, ",") != "read_orders,read_products" ||
+		removed.Installation.Status != core.InstallationStatusNeedsReconsent {
+		t.Fatalf("unexpected scope removal result %+v", removed)
+	}
+	if fx.connections.status(connection.ID) != core.ConnectionStatusNeedsReconsent {
+		t.Fatalf("expected connection to need reconsent")
+	}
+	event := fx.events.last()
+	if event.Name != "services.installation.scopes_changed" || fmt.Sprint(event.Payload["scopes_removed"]) != "[read_orders read_products]" {
+		t.Fatalf("unexpected scopes event %+v", event)
+	}
+
+	if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "shopify", Scope: core.ScopeRef{Type: "org", ID: "missing"}, Signal: SignalUninstalled}); !errors.Is(err, ErrInstallationNotFound) {
+		t.Fatalf("expected unknown installation error, got %v", err)
+	}
+	if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "shopify", Scope: scope, Signal: "renamed"}); !errors.Is(err, ErrInvalidSignal) {
+		t.Fatalf("expected invalid signal error, got %v", err)
+	}
+}
+
+func TestOrchestrator_HooksRunAfterInstallAndScopeChanges(t *testing.T) {
+	ctx := context.Background()
+	hook := &recordingHook{}
+	fx := newOrchestratorFixture(t, WithHooks(hook))
+	scope := core.ScopeRef{Type: "org", ID: "org_1"}
+	connection := fx.connections.add("shopify", scope, core.ConnectionStatusActive)
+
+	if _, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID:             "shopify",
+		Scope:                  scope,
+		ExternalInstallationID: "acme.myshopify.com",
+		Signal:                 SignalInstalled,
+		Scopes:                 []string{"read_orders", "read_products"},
+	}); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID: "shopify",
+		Scope:      scope,
+		Signal:     SignalScopesChanged,
+		Scopes:     []string{"read_orders"},
+	}); err != nil {
+		t.Fatalf("change scopes: %v", err)
+	}
+	if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: "shopify", Scope: scope, Signal: SignalUnsuspended}); err != nil {
+		t.Fatalf("unsuspend: %v", err)
+	}
+	if len(hook.requests) != 2 {
+		t.Fatalf("expected hooks after install and scope change only, got %+v", hook.requests)
+	}
+	for i, signal := range []Signal{SignalInstalled, SignalScopesChanged} {
+		req := hook.requests[i]
+		if req.Event.Signal != signal || req.Installation.ID == "" ||
+			len(req.Connections) != 1 || req.Connections[0].ID != connection.ID {
+			t.Fatalf("unexpected %s hook request %+v", signal, req)
+		}
+	}
+	if strings.Join(hook.requests[1].Event.Scopes, ",") != "read_orders" {
+		t.Fatalf("expected the hook to see the new scopes, got %v", hook.requests[1].Event.Scopes)
+	}
+
+	hook.err = errors.New("webhook registration failed")
+	if _, err := fx.orchestrator.Apply(ctx, Event{
+		ProviderID: "shopify",
+		Scope:      scope,
+		Signal:     SignalScopesChanged,
+		Scopes:     []string{"read_orders", "write_orders"},
+	}); err == nil || !errors.Is(err, hook.err) {
+		t.Fatalf("expected the hook error to fail the signal, got %v", err)
+	}
+}
+
+type orchestratorFixture struct {
+	now           time.Time
+	service       *memoryInstallationService
+	connections   *memoryConnectionStore
+	subscriptions *memorySubscriptionStore
+	bindings      *memorySyncBindingStore
+	events        *recordingEventBus
+	jobs          *recordingPurgeScheduler
+	purger        *recordingPurger
+	orchestrator  *Orchestrator
+}
+
+func newOrchestratorFixture(t *testing.T, opts ...Option) *orchestratorFixture {
+	t.Helper()
+	fx := &orchestratorFixture{
+		now:           time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+		connections:   &memoryConnectionStore{byID: map[string]core.Connection{}},
+		subscriptions: &memorySubscriptionStore{byID: map[string]core.Subscription{}},
+		bindings:      &memorySyncBindingStore{byID: map[string]core.SyncBinding{}},
+		events:        &recordingEventBus{},
+		jobs:          &recordingPurgeScheduler{},
+		purger:        &recordingPurger{},
+	}
+	fx.service = &memoryInstallationService{byID: map[string]core.Installation{}, connections: fx.connections}
+	orchestrator, err := NewOrchestrator(fx.service, fx.connections, append([]Option{
+		WithSubscriptionStore(fx.subscriptions),
+		WithSyncBindingStore(fx.bindings),
+		WithEventBus(fx.events),
+		WithPurgeScheduler(fx.jobs, 0),
+		WithDataPurger(fx.purger),
+		WithClock(func() time.Time { return fx.now }),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("new orchestrator: %v", err)
+	}
+	fx.orchestrator = orchestrator
+	return fx
+}
+
+type memoryInstallationService struct {
+	mu          sync.Mutex
+	next        int
+	byID        map[string]core.Installation
+	connections *memoryConnectionStore
+}
+
+func (s *memoryInstallationService) UpsertInstallation(_ context.Context, in core.UpsertInstallationInput) (core.Installation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, existing := range s.byID {
+		if existing.ProviderID != in.ProviderID || existing.ScopeType != in.Scope.Type ||
+			existing.ScopeID != in.Scope.ID || existing.InstallType != in.InstallType ||
+			existing.Status == core.InstallationStatusUninstalled && in.Status != core.InstallationStatusUninstalled {
+			continue
+		}
+		if err := existing.TransitionTo(in.Status, time.Now().UTC()); err != nil {
+			return core.Installation{}, err
+		}
+		existing.Metadata = in.Metadata
+		existing.GrantedAt = in.GrantedAt
+		if in.RevokedAt != nil {
+			existing.RevokedAt = in.RevokedAt
+		}
+		s.byID[id] = existing
+		return existing, nil
+	}
+	if in.Status != core.InstallationStatusActive {
+		return core.Installation{}, fmt.Errorf("installation must be created with status active")
+	}
+	s.next++
+	installation := core.Installation{
+		ID:          fmt.Sprintf("inst_%d", s.next),
+		ProviderID:  in.ProviderID,
+		ScopeType:   in.Scope.Type,
+		ScopeID:     in.Scope.ID,
+		InstallType: in.InstallType,
+		Status:      in.Status,
+		GrantedAt:   in.GrantedAt,
+		Metadata:    in.Metadata,
+	}
+	s.byID[installation.ID] = installation
+	return installation, nil
+}
+
+func (s *memoryInstallationService) GetInstallation(_ context.Context, id string) (core.Installation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	installation, ok := s.byID[id]
+	if !ok {
+		return core.Installation{}, fmt.Errorf("installation %q not found", id)
+	}
+	return installation, nil
+}
+
+func (s *memoryInstallationService) ListInstallations(_ context.Context, providerID string, scope core.ScopeRef) ([]core.Installation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.Installation{}
+	for _, installation := range s.byID {
+		if installation.ProviderID == providerID && installation.ScopeType == scope.Type && installation.ScopeID == scope.ID {
+			out = append(out, installation)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryInstallationService) Revoke(ctx context.Context, connectionID string, reason string) error {
+	return s.connections.UpdateStatus(ctx, connectionID, core.ConnectionStatusDisconnected, reason)
+}
+
+type memoryConnectionStore struct {
+	mu   sync.Mutex
+	next int
+	byID map[string]core.Connection
+}
+
+func (s *memoryConnectionStore) add(providerID string, scope core.ScopeRef, status core.ConnectionStatus) core.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	connection := core.Connection{
+		ID:         fmt.Sprintf("conn_%d", s.next),
+		ProviderID: providerID,
+		ScopeType:  scope.Type,
+		ScopeID:    scope.ID,
+		Status:     status,
+	}
+	s.byID[connection.ID] = connection
+	return connection
+}
+
+func (s *memoryConnectionStore) status(id string) core.ConnectionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byID[id].Status
+}
+
+func (s *memoryConnectionStore) Create(context.Context, core.CreateConnectionInput) (core.Connection, error) {
+	return core.Connection{}, fmt.Errorf("not implemented")
+}
+
+func (s *memoryConnectionStore) Get(_ context.Context, id string) (core.Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	connection, ok := s.byID[id]
+	if !ok {
+		return core.Connection{}, fmt.Errorf("connection %q not found", id)
+	}
+	return connection, nil
+}
+
+func (s *memoryConnectionStore) FindByScope(_ context.Context, providerID string, scope core.ScopeRef) ([]core.Connection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.Connection{}
+	for _, connection := range s.byID {
+		if connection.ProviderID == providerID && connection.ScopeType == scope.Type && connection.ScopeID == scope.ID {
+			out = append(out, connection)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryConnectionStore) FindByScopeAndExternalAccount(
+	context.Context,
+	string,
+	core.ScopeRef,
+	string,
+) (core.Connection, bool, error) {
+	return core.Connection{}, false, nil
+}
+
+func (s *memoryConnectionStore) UpdateStatus(_ context.Context, id string, status core.ConnectionStatus, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	connection, ok := s.byID[id]
+	if !ok {
+		return fmt.Errorf("connection %q not found", id)
+	}
+	if err := connection.TransitionTo(status, reason, time.Now().UTC()); err != nil {
+		return err
+	}
+	s.byID[id] = connection
+	return nil
+}
+
+type memorySubscriptionStore struct {
+	mu   sync.Mutex
+	next int
+	byID map[string]core.Subscription
+}
+
+func (s *memorySubscriptionStore) add(connectionID string, status core.SubscriptionStatus) core.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	subscription := core.Subscription{ID: fmt.Sprintf("sub_%d", s.next), ConnectionID: connectionID, Status: status}
+	s.byID[subscription.ID] = subscription
+	return subscription
+}
+
+func (s *memorySubscriptionStore) ListByConnection(_ context.Context, connectionID string) ([]core.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.Subscription{}
+	for _, subscription := range s.byID {
+		if subscription.ConnectionID == connectionID {
+			out = append(out, subscription)
+		}
+	}
+	return out, nil
+}
+
+func (s *memorySubscriptionStore) UpdateState(_ context.Context, id string, status core.SubscriptionStatus, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscription := s.byID[id]
+	subscription.Status = status
+	s.byID[id] = subscription
+	return nil
+}
+
+type memorySyncBindingStore struct {
+	mu   sync.Mutex
+	next int
+	byID map[string]core.SyncBinding
+}
+
+func (s *memorySyncBindingStore) add(connectionID string, status core.SyncBindingStatus) core.SyncBinding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	binding := core.SyncBinding{ID: fmt.Sprintf("binding_%d", s.next), ConnectionID: connectionID, Status: status}
+	s.byID[binding.ID] = binding
+	return binding
+}
+
+func (s *memorySyncBindingStore) status(id string) core.SyncBindingStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byID[id].Status
+}
+
+func (s *memorySyncBindingStore) all() []core.SyncBinding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.SyncBinding{}
+	for _, binding := range s.byID {
+		out = append(out, binding)
+	}
+	return out
+}
+
+func (s *memorySyncBindingStore) Upsert(_ context.Context, binding core.SyncBinding) (core.SyncBinding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byID[binding.ID] = binding
+	return binding, nil
+}
+
+func (s *memorySyncBindingStore) Get(_ context.Context, id string) (core.SyncBinding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	binding, ok := s.byID[id]
+	if !ok {
+		return core.SyncBinding{}, fmt.Errorf("sync binding %q not found", id)
+	}
+	return binding, nil
+}
+
+func (s *memorySyncBindingStore) ListByConnection(_ context.Context, connectionID string) ([]core.SyncBinding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.SyncBinding{}
+	for _, binding := range s.byID {
+		if binding.ConnectionID == connectionID {
+			out = append(out, binding)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *memorySyncBindingStore) UpdateStatus(_ context.Context, id string, status core.SyncBindingStatus, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	binding := s.byID[id]
+	binding.Status = status
+	s.byID[id] = binding
+	return nil
+}
+
+type recordingEventBus struct {
+	mu     sync.Mutex
+	events []core.LifecycleEvent
+}
+
+func (b *recordingEventBus) Publish(_ context.Context, event core.LifecycleEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *recordingEventBus) Subscribe(core.LifecycleEventHandler) {}
+
+func (b *recordingEventBus) names() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := []string{}
+	for _, event := range b.events {
+		out = append(out, event.Name)
+	}
+	return out
+}
+
+func (b *recordingEventBus) last() core.LifecycleEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) == 0 {
+		return core.LifecycleEvent{}
+	}
+	return b.events[len(b.events)-1]
+}
+
+type recordingPurgeScheduler struct {
+	messages []*core.JobExecutionMessage
+	delays   []time.Duration
+}
+
+func (s *recordingPurgeScheduler) Enqueue(ctx context.Context, msg *core.JobExecutionMessage) (core.JobEnqueueReceipt, error) {
+	return s.EnqueueAfter(ctx, msg, 0)
+}
+
+func (s *recordingPurgeScheduler) EnqueueAt(ctx context.Context, msg *core.JobExecutionMessage, at time.Time) (core.JobEnqueueReceipt, error) {
+	return s.EnqueueAfter(ctx, msg, time.Until(at))
+}
+
+func (s *recordingPurgeScheduler) EnqueueAfter(_ context.Context, msg *core.JobExecutionMessage, delay time.Duration) (core.JobEnqueueReceipt, error) {
+	s.messages = append(s.messages, msg)
+	s.delays = append(s.delays, delay)
+	return core.JobEnqueueReceipt{DispatchID: fmt.Sprintf("dispatch_%d", len(s.messages))}, nil
+}
+
+type recordingPurger struct {
+	requests []PurgeRequest
+}
+
+func (p *recordingPurger) PurgeInstallation(_ context.Context, req PurgeRequest) error {
+	p.requests = append(p.requests, req)
+	return nil
+}
+
+type recordingHook struct {
+	requests []HookRequest
+	err      error
+}
+
+func (h *recordingHook) AfterInstallationChange(_ context.Context, req HookRequest) error {
+	h.requests = append(h.requests, req)
+	return h.err
+}
+
+var (
+	_ InstallationService       = (*memoryInstallationService)(nil)
+	_ core.ConnectionStore      = (*memoryConnectionStore)(nil)
+	_ SubscriptionStore         = (*memorySubscriptionStore)(nil)
+	_ core.SyncBindingStore     = (*memorySyncBindingStore)(nil)
+	_ core.LifecycleEventBus    = (*recordingEventBus)(nil)
+	_ core.JobScheduledEnqueuer = (*recordingPurgeScheduler)(nil)
+	_ DataPurger                = (*recordingPurger)(nil)
+	_ Hook                      = (*recordingHook)(nil)
+	_ InstallationService       = (*core.Service)(nil)
+)
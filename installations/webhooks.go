@@ -0,0 +1,176 @@
+package installations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+const (
+	githubProviderID = "github"
+	// githubAppProviderID matches providers/github.AppProviderID. GitHub
+	// installations belong to the app, so they are recorded under the app
+	// provider whose connections they authorize.
+	githubAppProviderID = "github_app"
+	shopifyProviderID   = "shopify"
+
+	shopifyHeaderShopDomain = "X-Shopify-Shop-Domain"
+)
+
+// ScopeResolver maps a provider installation identifier, as carried by a
+// webhook, to the scope that owns the installation.
+type ScopeResolver func(ctx context.Context, providerID string, externalInstallationID string) (core.ScopeRef, error)
+
+type githubInstallationPayload struct {
+	Action       string `json:"action"`
+	Installation struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			ID    int64  `json:"id"`
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"account"`
+		Permissions map[string]string `json:"permissions"`
+	} `json:"installation"`
+}
+
+type shopifyScopesPayload struct {
+	Previous []string `json:"previous"`
+	Current  []string `json:"current"`
+}
+
+// GitHubInstallationEvent converts a GitHub "installation" webhook into an
+// Event. Granted permissions are reported as "name:level" scopes. Actions
+// without a lifecycle meaning are reported as not handled.
+func GitHubInstallationEvent(event webhooks.Event) (Event, bool, error) {
+	var payload githubInstallationPayload
+	if err := event.Decode(&payload); err != nil {
+		return Event{}, false, err
+	}
+	signal, ok := map[string]Signal{
+		"created":                  SignalInstalled,
+		"deleted":                  SignalUninstalled,
+		"suspend":                  SignalSuspended,
+		"unsuspend":                SignalUnsuspended,
+		"new_permissions_accepted": SignalScopesChanged,
+	}[strings.ToLower(strings.TrimSpace(payload.Action))]
+	if !ok {
+		return Event{}, false, nil
+	}
+	if payload.Installation.ID == 0 {
+		return Event{}, false, fmt.Errorf("installations: github installation event has no installation id")
+	}
+	scopes := make([]string, 0, len(payload.Installation.Permissions))
+	for name, level := range payload.Installation.Permissions {
+		scopes = append(scopes, name+":"+level)
+	}
+	sort.Strings(scopes)
+	return Event{
+		ProviderID:             githubAppProviderID,
+		ExternalInstallationID: strconv.FormatInt(payload.Installation.ID, 10),
+		Signal:                 signal,
+		Scopes:                 scopes,
+		Metadata: map[string]any{
+			"account_id":    payload.Installation.Account.ID,
+			"account_login": payload.Installation.Account.Login,
+			"account_type":  payload.Installation.Account.Type,
+		},
+	}, true, nil
+}
+
+// ShopifyAppEvent converts Shopify "app/uninstalled" and "app/scopes_update"
+// webhooks into Events keyed by the shop domain.
+func ShopifyAppEvent(event webhooks.Event) (Event, bool, error) {
+	shopDomain := strings.ToLower(strings.TrimSpace(webhooks.HeaderValue(event.Request.Headers, shopifyHeaderShopDomain)))
+	switch strings.ToLower(strings.TrimSpace(event.Topic)) {
+	case "app/uninstalled":
+		if shopDomain == "" {
+			var payload struct {
+				MyshopifyDomain string `json:"myshopify_domain"`
+			}
+			if err := event.Decode(&payload); err != nil {
+				return Event{}, false, err
+			}
+			shopDomain = strings.ToLower(strings.TrimSpace(payload.MyshopifyDomain))
+		}
+		if shopDomain == "" {
+			return Event{}, false, fmt.Errorf("installations: shopify uninstall event has no shop domain")
+		}
+		return Event{
+			ProviderID:             shopifyProviderID,
+			ExternalInstallationID: shopDomain,
+			Signal:                 SignalUninstalled,
+		}, true, nil
+	case "app/scopes_update":
+		if shopDomain == "" {
+			return Event{}, false, fmt.Errorf("installations: shopify scopes event requires the %s header", shopifyHeaderShopDomain)
+		}
+		var payload shopifyScopesPayload
+		if err := event.Decode(&payload); err != nil {
+			return Event{}, false, err
+		}
+		return Event{
+			ProviderID:             shopifyProviderID,
+			ExternalInstallationID: shopDomain,
+			Signal:                 SignalScopesChanged,
+			Scopes:                 append([]string{}, payload.Current...),
+		}, true, nil
+	default:
+		return Event{}, false, nil
+	}
+}
+
+// RegisterWebhookRoutes routes GitHub installation events and Shopify app
+// events on router to Apply. Signals for installations the app never recorded
+// are acknowledged and ignored.
+func (o *Orchestrator) RegisterWebhookRoutes(router *webhooks.Router, resolve ScopeResolver) error {
+	if o == nil {
+		return fmt.Errorf("installations: orchestrator is not configured")
+	}
+	if router == nil {
+		return fmt.Errorf("installations: webhook router is required")
+	}
+	if resolve == nil {
+		return fmt.Errorf("installations: scope resolver is required")
+	}
+	routes := []struct {
+		providerID string
+		topic      string
+		parse      func(webhooks.Event) (Event, bool, error)
+	}{
+		{providerID: githubProviderID, topic: "installation.*", parse: GitHubInstallationEvent},
+		{providerID: shopifyProviderID, topic: "app/uninstalled", parse: ShopifyAppEvent},
+		{providerID: shopifyProviderID, topic: "app/scopes_update", parse: ShopifyAppEvent},
+	}
+	for _, route := range routes {
+		if err := router.On(route.providerID, route.topic, o.webhookHandler(route.parse, resolve)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Orchestrator) webhookHandler(parse func(webhooks.Event) (Event, bool, error), resolve ScopeResolver) webhooks.EventHandler {
+	return webhooks.EventHandlerFunc(func(ctx context.Context, webhookEvent webhooks.Event) error {
+		event, ok, err := parse(webhookEvent)
+		if err != nil || !ok {
+			return err
+		}
+		scope, err := resolve(ctx, event.ProviderID, event.ExternalInstallationID)
+		if err != nil {
+			return err
+		}
+		event.Scope = scope
+		_, err = o.Apply(ctx, event)
+		if errors.Is(err, ErrInstallationNotFound) {
+			return nil
+		}
+		return err
+	})
+}
@@ -0,0 +1,102 @@
+package installations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+func TestRegisterWebhookRoutes_AppliesProviderInstallationSignals(t *testing.T) {
+	ctx := context.Background()
+	fx := newOrchestratorFixture(t)
+	githubScope := core.ScopeRef{Type: "org", ID: "org_1"}
+	shopScope := core.ScopeRef{Type: "org", ID: "org_2"}
+	githubConnection := fx.connections.add("github_app", githubScope, core.ConnectionStatusActive)
+	shopConnection := fx.connections.add("shopify", shopScope, core.ConnectionStatusActive)
+	for _, scope := range []struct {
+		providerID string
+		scope      core.ScopeRef
+	}{{"github_app", githubScope}, {"shopify", shopScope}} {
+		if _, err := fx.orchestrator.Apply(ctx, Event{ProviderID: scope.providerID, Scope: scope.scope, Signal: SignalInstalled}); err != nil {
+			t.Fatalf("install %s: %v", scope.providerID, err)
+		}
+	}
+
+	router := webhooks.NewRouter()
+	for _, template := range []webhooks.ProviderWebhookTemplate{
+		webhooks.NewGitHubWebhookTemplate("secret"),
+		webhooks.NewShopifyWebhookTemplate("secret"),
+	} {
+		if err := router.UseTemplate(template); err != nil {
+			t.Fatalf("use template: %v", err)
+		}
+	}
+	owners := map[string]core.ScopeRef{
+		"github_app:42":              githubScope,
+		"shopify:acme.myshopify.com": shopScope,
+	}
+	resolve := func(_ context.Context, providerID string, externalID string) (core.ScopeRef, error) {
+		if scope, ok := owners[providerID+":"+externalID]; ok {
+			return scope, nil
+		}
+		return core.ScopeRef{Type: "org", ID: "unclaimed"}, nil
+	}
+	if err := fx.orchestrator.RegisterWebhookRoutes(router, resolve); err != nil {
+		t.Fatalf("register routes: %v", err)
+	}
+
+	requests := []core.InboundRequest{
+		{
+			ProviderID: "github",
+			Headers:    map[string]string{"X-GitHub-Event": "installation"},
+			Body:       []byte(`{"action":"deleted","installation":{"id":42,"account":{"id":7,"login":"acme","type":"Organization"}}}`),
+		},
+		{
+			ProviderID: "shopify",
+			Headers: map[string]string{
+				"X-Shopify-Topic":       "app/uninstalled",
+				"X-Shopify-Shop-Domain": "ACME.myshopify.com",
+			},
+			Body: []byte(`{"id":1,"domain":"acme.example"}`),
+		},
+		// Unknown installations are acknowledged without side effects.
+		{
+			ProviderID: "github",
+			Headers:    map[string]string{"X-GitHub-Event": "installation"},
+			Body:       []byte(`{"action":"suspend","installation":{"id":99}}`),
+		},
+	}
+	for _, req := range requests {
+		result, err := router.Handle(ctx, req)
+		if err != nil || !result.Accepted {
+			t.Fatalf("handle %s webhook: %+v %v", req.ProviderID, result, err)
+		}
+	}
+
+	for _, id := range []string{githubConnection.ID, shopConnection.ID} {
+		if status := fx.connections.status(id); status != core.ConnectionStatusDisconnected {
+			t.Fatalf("expected connection %s to be revoked, got %s", id, status)
+		}
+	}
+	if len(fx.jobs.messages) != 2 {
+		t.Fatalf("expected a purge per uninstall, got %d", len(fx.jobs.messages))
+	}
+}
+
+func TestGitHubInstallationEvent_MapsPermissionsToScopes(t *testing.T) {
+	event, ok, err := GitHubInstallationEvent(webhooks.Event{
+		Payload: []byte(`{"action":"new_permissions_accepted","installation":{"id":42,"permissions":{"issues":"write","contents":"read"}}}`),
+	})
+	if err != nil || !ok {
+		t.Fatalf("expected event to be handled, got %v %v", ok, err)
+	}
+	if event.ProviderID != githubAppProviderID || event.Signal != SignalScopesChanged || event.ExternalInstallationID != "42" ||
+		len(event.Scopes) != 2 || event.Scopes[0] != "contents:read" || event.Scopes[1] != "issues:write" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if _, ok, err := GitHubInstallationEvent(webhooks.Event{Payload: []byte(`{"action":"renamed"}`)}); ok || err != nil {
+		t.Fatalf("expected unrelated action to be skipped, got %v %v", ok, err)
+	}
+}
@@ -109,59 +109,40 @@ func TestRegister_RejectsUnknownValidationTargets(t *testing.T) {
 	}
 }
 
-func TestRateLimitStateUniquenessMigrationPair_ExistsForBothDialects(t *testing.T) {
+func TestMigrationPairs_ExistForBothDialects(t *testing.T) {
 	root := services.GetCoreMigrationsFS()
-	paths := []string{
-		"data/sql/migrations/00004_services_rate_limit_state_uniqueness.up.sql",
-		"data/sql/migrations/00004_services_rate_limit_state_uniqueness.down.sql",
-		"data/sql/migrations/sqlite/00004_services_rate_limit_state_uniqueness.up.sql",
-		"data/sql/migrations/sqlite/00004_services_rate_limit_state_uniqueness.down.sql",
-	}
-	for _, migrationPath := range paths {
-		content, err := fs.ReadFile(root, migrationPath)
-		if err != nil {
-			t.Fatalf("read migration %s: %v", migrationPath, err)
-		}
-		if strings.TrimSpace(string(content)) == "" {
-			t.Fatalf("expected migration %s to have SQL content", migrationPath)
-		}
-	}
-}
-
-func TestFormDataFoundationMigrationPair_ExistsForBothDialects(t *testing.T) {
-	root := services.GetCoreMigrationsFS()
-	paths := []string{
-		"data/sql/migrations/00005_services_form_data_foundation.up.sql",
-		"data/sql/migrations/00005_services_form_data_foundation.down.sql",
-		"data/sql/migrations/sqlite/00005_services_form_data_foundation.up.sql",
-		"data/sql/migrations/sqlite/00005_services_form_data_foundation.down.sql",
-	}
-	for _, migrationPath := range paths {
-		content, err := fs.ReadFile(root, migrationPath)
-		if err != nil {
-			t.Fatalf("read migration %s: %v", migrationPath, err)
-		}
-		if strings.TrimSpace(string(content)) == "" {
-			t.Fatalf("expected migration %s to have SQL content", migrationPath)
-		}
-	}
-}
-
-func TestSyncJobIdempotencyMigrationPair_ExistsForBothDialects(t *testing.T) {
-	root := services.GetCoreMigrationsFS()
-	paths := []string{
-		"data/sql/migrations/00006_services_sync_job_idempotency.up.sql",
-		"data/sql/migrations/00006_services_sync_job_idempotency.down.sql",
-		"data/sql/migrations/sqlite/00006_services_sync_job_idempotency.up.sql",
-		"data/sql/migrations/sqlite/00006_services_sync_job_idempotency.down.sql",
-	}
-	for _, migrationPath := range paths {
-		content, err := fs.ReadFile(root, migrationPath)
-		if err != nil {
-			t.Fatalf("read migration %s: %v", migrationPath, err)
-		}
-		if strings.TrimSpace(string(content)) == "" {
-			t.Fatalf("expected migration %s to have SQL content", migrationPath)
+	migrations := []string{
+		"00001_services_core_schema",
+		"00002_services_credential_payload_codec",
+		"00003_services_grant_snapshots",
+		"00004_services_rate_limit_state_uniqueness",
+		"00005_services_form_data_foundation",
+		"00006_services_sync_job_idempotency",
+		"00007_services_outbound_webhooks",
+		"00008_services_sync_change_log_record_lookup",
+		"00009_services_sync_tombstones",
+		"00010_services_job_queue",
+		"00011_services_sync_job_execution",
+		"00012_services_sync_schedules",
+		"00013_services_leader_leases",
+		"00014_services_outbox_partitions",
+		"00015_services_outbox_projectors",
+		"00016_services_webhook_event_outcomes",
+		"00017_services_job_queue_dedup_keys",
+		"00018_services_sync_change_log_insert_order",
+	}
+	for _, migration := range migrations {
+		for _, dir := range []string{"data/sql/migrations", "data/sql/migrations/sqlite"} {
+			for _, direction := range []string{"up", "down"} {
+				migrationPath := dir + "/" + migration + "." + direction + ".sql"
+				content, err := fs.ReadFile(root, migrationPath)
+				if err != nil {
+					t.Fatalf("read migration %s: %v", migrationPath, err)
+				}
+				if strings.TrimSpace(string(content)) == "" {
+					t.Fatalf("expected migration %s to have SQL content", migrationPath)
+				}
+			}
 		}
 	}
 }
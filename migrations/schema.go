@@ -18,9 +18,14 @@ var requiredSQLTables = []string{
 	"service_grant_snapshots",
 	"service_identity_bindings",
 	"service_installations",
+	"service_job_queue",
+	"service_leader_leases",
 	"service_lifecycle_outbox",
+	"service_lifecycle_outbox_projectors",
 	"service_mapping_specs",
 	"service_notification_dispatches",
+	"service_outbound_webhook_deliveries",
+	"service_outbound_webhook_endpoints",
 	"service_rate_limit_state",
 	"service_subscriptions",
 	"service_sync_bindings",
@@ -30,7 +35,9 @@ var requiredSQLTables = []string{
 	"service_sync_cursors",
 	"service_sync_job_idempotency",
 	"service_sync_jobs",
+	"service_sync_schedules",
 	"service_webhook_deliveries",
+	"service_webhook_event_outcomes",
 }
 
 var requiredOAuthStorageTables = []string{
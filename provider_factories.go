@@ -22,6 +22,10 @@ func GitHubProvider(cfg github.Config) (core.Provider, error) {
 	return github.New(cfg)
 }
 
+func GitHubAppProvider(cfg github.AppConfig) (core.Provider, error) {
+	return github.NewApp(cfg)
+}
+
 func GmailProvider(cfg gmail.Config) (core.Provider, error) {
 	return gmail.New(cfg)
 }
@@ -0,0 +1,243 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/auth"
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+)
+
+// AppConfig configures GitHub App authentication. Connections authenticate
+// with installation access tokens instead of user OAuth tokens, and grants are
+// the app permissions rendered as "name:level".
+type AppConfig struct {
+	AppID      string
+	AppSlug    string
+	PrivateKey string
+	APIBaseURL string
+	// Permissions and Repositories narrow installation tokens by default.
+	Permissions         map[string]string
+	Repositories        []string
+	SupportedScopeTypes []string
+	RenewBefore         time.Duration
+	HTTPClient          providers.HTTPDoer
+	Now                 func() time.Time
+	// ClientID and ClientSecret let completion verify installations through
+	// the user authorization code sent to the setup URL.
+	ClientID     string
+	ClientSecret string
+	// Installations verifies installations without a user authorization code
+	// against those recorded for the scope under AppProviderID.
+	Installations core.InstallationStore
+}
+
+type AppProvider struct {
+	strategy     *auth.GitHubAppStrategy
+	scopeTypes   []string
+	capabilities []core.CapabilityDescriptor
+}
+
+var (
+	_ core.AuthStrategyProvider = (*AppProvider)(nil)
+	_ core.GrantAwareProvider   = (*AppProvider)(nil)
+)
+
+func NewApp(cfg AppConfig) (core.Provider, error) {
+	if strings.TrimSpace(cfg.AppID) == "" {
+		return nil, fmt.Errorf("providers/github: app id is required")
+	}
+	if strings.TrimSpace(cfg.PrivateKey) == "" {
+		return nil, fmt.Errorf("providers/github: app private key is required")
+	}
+	apiBaseURL := strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/")
+	if apiBaseURL == "" {
+		apiBaseURL = DefaultAPIBaseURL
+	}
+	strategyConfig := auth.GitHubAppStrategyConfig{
+		AppID:        cfg.AppID,
+		AppSlug:      cfg.AppSlug,
+		PrivateKey:   cfg.PrivateKey,
+		APIBaseURL:   apiBaseURL,
+		Permissions:  cfg.Permissions,
+		Repositories: cfg.Repositories,
+		RenewBefore:  cfg.RenewBefore,
+		Now:          cfg.Now,
+		HTTPClient:   cfg.HTTPClient,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+	}
+	if cfg.Installations != nil {
+		strategyConfig.InstallationVerifier = auth.GitHubInstallationStoreVerifier{
+			Store:      cfg.Installations,
+			ProviderID: AppProviderID,
+		}
+	}
+	return &AppProvider{
+		strategy:     auth.NewGitHubAppStrategy(strategyConfig),
+		scopeTypes:   normalizeAppScopeTypes(cfg.SupportedScopeTypes),
+		capabilities: AppCapabilities(),
+	}, nil
+}
+
+// AppCapabilities maps capabilities onto GitHub App permissions.
+func AppCapabilities() []core.CapabilityDescriptor {
+	return []core.CapabilityDescriptor{
+		{Name: "repo.read", RequiredGrants: []string{"contents:read"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+		{Name: "repo.write", RequiredGrants: []string{"contents:write"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+		{Name: "issues.read", RequiredGrants: []string{"issues:read"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+		{Name: "issues.write", RequiredGrants: []string{"issues:write"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+		{Name: "webhooks.manage", RequiredGrants: []string{"repository_hooks:write"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+	}
+}
+
+func (*AppProvider) ID() string {
+	return AppProviderID
+}
+
+func (*AppProvider) AuthKind() core.AuthKind {
+	return core.AuthKindGitHubApp
+}
+
+func (p *AppProvider) SupportedScopeTypes() []string {
+	if p == nil {
+		return []string{}
+	}
+	return append([]string(nil), p.scopeTypes...)
+}
+
+func (p *AppProvider) Capabilities() []core.CapabilityDescriptor {
+	if p == nil {
+		return []core.CapabilityDescriptor{}
+	}
+	out := make([]core.CapabilityDescriptor, len(p.capabilities))
+	copy(out, p.capabilities)
+	return out
+}
+
+func (p *AppProvider) AuthStrategy() core.AuthStrategy {
+	if p == nil || p.strategy == nil {
+		return nil
+	}
+	return p.strategy
+}
+
+// AppStrategy exposes the app JWT and installation token operations.
+func (p *AppProvider) AppStrategy() *auth.GitHubAppStrategy {
+	if p == nil {
+		return nil
+	}
+	return p.strategy
+}
+
+func (p *AppProvider) BeginAuth(ctx context.Context, req core.BeginAuthRequest) (core.BeginAuthResponse, error) {
+	if p == nil || p.strategy == nil {
+		return core.BeginAuthResponse{}, fmt.Errorf("providers/github: app strategy is not configured")
+	}
+	begin, err := p.strategy.Begin(ctx, core.AuthBeginRequest{
+		Scope:        req.Scope,
+		RedirectURI:  req.RedirectURI,
+		State:        req.State,
+		RequestedRaw: append([]string(nil), req.RequestedGrants...),
+		Metadata:     req.Metadata,
+	})
+	if err != nil {
+		return core.BeginAuthResponse{}, err
+	}
+	return core.BeginAuthResponse{
+		URL:             begin.URL,
+		State:           begin.State,
+		RequestedGrants: append([]string(nil), begin.RequestedGrants...),
+		Metadata:        begin.Metadata,
+	}, nil
+}
+
+func (p *AppProvider) CompleteAuth(ctx context.Context, req core.CompleteAuthRequest) (core.CompleteAuthResponse, error) {
+	if p == nil || p.strategy == nil {
+		return core.CompleteAuthResponse{}, fmt.Errorf("providers/github: app strategy is not configured")
+	}
+	complete, err := p.strategy.Complete(ctx, core.AuthCompleteRequest{
+		Scope:       req.Scope,
+		Code:        req.Code,
+		State:       req.State,
+		RedirectURI: req.RedirectURI,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		return core.CompleteAuthResponse{}, err
+	}
+	complete.Credential.GrantedScopes = normalizeAppGrants(complete.Credential.GrantedScopes)
+	return core.CompleteAuthResponse{
+		ExternalAccountID: complete.ExternalAccountID,
+		Credential:        complete.Credential,
+		RequestedGrants:   append([]string(nil), complete.RequestedGrants...),
+		GrantedGrants:     normalizeAppGrants(complete.GrantedGrants),
+		Metadata:          complete.Metadata,
+	}, nil
+}
+
+func (p *AppProvider) Refresh(ctx context.Context, cred core.ActiveCredential) (core.RefreshResult, error) {
+	if p == nil || p.strategy == nil {
+		return core.RefreshResult{}, fmt.Errorf("providers/github: app strategy is not configured")
+	}
+	refreshed, err := p.strategy.Refresh(ctx, cred)
+	if err != nil {
+		return core.RefreshResult{}, err
+	}
+	refreshed.Credential.GrantedScopes = normalizeAppGrants(refreshed.Credential.GrantedScopes)
+	refreshed.GrantedGrants = normalizeAppGrants(refreshed.GrantedGrants)
+	return refreshed, nil
+}
+
+func (*AppProvider) NormalizeGrantedPermissions(_ context.Context, raw []string) ([]string, error) {
+	return normalizeAppGrants(raw), nil
+}
+
+// normalizeAppGrants lowercases "name:level" permission grants and adds the
+// read grant implied by each write or admin grant.
+func normalizeAppGrants(raw []string) []string {
+	set := map[string]struct{}{}
+	for _, grant := range raw {
+		name, level, ok := strings.Cut(strings.ToLower(strings.TrimSpace(grant)), ":")
+		name, level = strings.TrimSpace(name), strings.TrimSpace(level)
+		if !ok || name == "" || level == "" {
+			continue
+		}
+		set[name+":"+level] = struct{}{}
+		switch level {
+		case "admin":
+			set[name+":write"] = struct{}{}
+			set[name+":read"] = struct{}{}
+		case "write":
+			set[name+":read"] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(set))
+	for grant := range set {
+		out = append(out, grant)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func normalizeAppScopeTypes(raw []string) []string {
+	set := map[string]struct{}{}
+	for _, value := range raw {
+		if normalized := strings.ToLower(strings.TrimSpace(value)); normalized != "" {
+			set[normalized] = struct{}{}
+		}
+	}
+	if len(set) == 0 {
+		return []string{"org", "user"}
+	}
+	out := make([]string, 0, len(set))
+	for scopeType := range set {
+		out = append(out, scopeType)
+	}
+	sort.Strings(out)
+	return out
+}
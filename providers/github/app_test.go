@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestNewApp_ExposesAppStrategyAndImpliedReadGrants(t *testing.T) {
+	if _, err := NewApp(AppConfig{AppID: "1234"}); err == nil {
+		t.Fatalf("expected private key to be required")
+	}
+	provider, err := NewApp(AppConfig{AppID: "1234", AppSlug: "acme-sync", PrivateKey: "unused"})
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+	if provider.AuthKind() != core.AuthKindGitHubApp || provider.ID() != AppProviderID {
+		t.Fatalf("unexpected provider identity %s/%s", provider.ID(), provider.AuthKind())
+	}
+	strategyProvider, ok := provider.(core.AuthStrategyProvider)
+	if !ok || strategyProvider.AuthStrategy().Type() != core.AuthKindGitHubApp {
+		t.Fatalf("expected provider to expose the github app strategy")
+	}
+	begin, err := provider.BeginAuth(context.Background(), core.BeginAuthRequest{State: "s1"})
+	if err != nil || !strings.HasPrefix(begin.URL, "https://github.com/apps/acme-sync/installations/new") {
+		t.Fatalf("unexpected begin %+v %v", begin, err)
+	}
+
+	grants, _ := provider.(core.GrantAwareProvider).NormalizeGrantedPermissions(context.Background(), []string{"Contents:write", "administration:admin", "bogus"})
+	if got := strings.Join(grants, ","); got != "administration:admin,administration:read,administration:write,contents:read,contents:write" {
+		t.Fatalf("unexpected normalized grants %s", got)
+	}
+}
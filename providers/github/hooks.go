@@ -0,0 +1,350 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+const (
+	DefaultAPIBaseURL = "https://api.github.com"
+	APIVersion        = "2022-11-28"
+
+	ResourceTypeRepository   = "repository"
+	ResourceTypeOrganization = "organization"
+
+	MetadataEvents = "events"
+	MetadataHookID = "hook_id"
+
+	maxHookResponseBodyBytes = 1 << 20 // 1 MiB
+)
+
+var _ core.SubscribableProvider = (*Provider)(nil)
+
+type Provider struct {
+	*providers.OAuth2Provider
+	apiBaseURL    string
+	httpClient    providers.HTTPDoer
+	webhookEvents []string
+}
+
+type hookConfig struct {
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	InsecureSSL string `json:"insecure_ssl,omitempty"`
+}
+
+type hookRequest struct {
+	Name   string      `json:"name,omitempty"`
+	Active bool        `json:"active"`
+	Events []string    `json:"events,omitempty"`
+	Config *hookConfig `json:"config,omitempty"`
+}
+
+type hookResponse struct {
+	ID     int64    `json:"id"`
+	Active bool     `json:"active"`
+	Events []string `json:"events"`
+	URL    string   `json:"url"`
+}
+
+type apiError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("providers/github: %s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Subscribe creates a repository ("owner/repo") or organization hook that
+// signs deliveries with a generated per-subscription secret.
+func (p *Provider) Subscribe(ctx context.Context, req core.SubscribeRequest) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: provider is nil")
+	}
+	hooksPath, err := hooksPath(req.ResourceType, req.ResourceID)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	callbackURL := strings.TrimSpace(req.CallbackURL)
+	if callbackURL == "" {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: callback url is required")
+	}
+	return p.createHook(ctx, req.Credential, hooksPath, callbackURL, p.resolveEvents(req.Metadata))
+}
+
+// RenewSubscription re-activates the hook and refreshes its events. The hook
+// secret is kept unless req.RotateSecret is set, so deliveries already in
+// flight still verify. Hooks deleted on GitHub are recreated.
+func (p *Provider) RenewSubscription(
+	ctx context.Context,
+	req core.RenewSubscriptionRequest,
+) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: provider is nil")
+	}
+	if req.Subscription == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: subscription is required to renew")
+	}
+	existing := req.Subscription
+	hooksPath, err := hooksPath(existing.ResourceType, existing.ResourceID)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	metadata := map[string]any{}
+	for key, value := range existing.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	events := p.resolveEvents(metadata)
+
+	hookID := strings.TrimSpace(existing.RemoteSubscriptionID)
+	if hookID == "" {
+		return p.createHook(ctx, req.Credential, hooksPath, existing.CallbackURL, events)
+	}
+	patch := hookRequest{Active: true, Events: events}
+	secret := ""
+	if req.RotateSecret {
+		if secret, err = generateHookSecret(); err != nil {
+			return core.SubscriptionResult{}, err
+		}
+		patch.Config = &hookConfig{
+			URL:         strings.TrimSpace(existing.CallbackURL),
+			ContentType: "json",
+			Secret:      secret,
+			InsecureSSL: "0",
+		}
+	}
+	var response hookResponse
+	err = p.do(ctx, req.Credential, http.MethodPatch, hooksPath+"/"+url.PathEscape(hookID), patch, &response)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return p.createHook(ctx, req.Credential, hooksPath, existing.CallbackURL, events)
+	}
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	return hookResult(response, secret, events), nil
+}
+
+// CancelSubscription deletes the hook; hooks already removed on GitHub are
+// treated as cancelled.
+func (p *Provider) CancelSubscription(ctx context.Context, req core.CancelSubscriptionRequest) error {
+	if p == nil {
+		return fmt.Errorf("providers/github: provider is nil")
+	}
+	if req.Subscription == nil {
+		return fmt.Errorf("providers/github: subscription is required to cancel")
+	}
+	hooksPath, err := hooksPath(req.Subscription.ResourceType, req.Subscription.ResourceID)
+	if err != nil {
+		return err
+	}
+	hookID := strings.TrimSpace(req.Subscription.RemoteSubscriptionID)
+	if hookID == "" {
+		return fmt.Errorf("providers/github: hook id is required to cancel")
+	}
+	err = p.do(ctx, req.Credential, http.MethodDelete, hooksPath+"/"+url.PathEscape(hookID), nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (p *Provider) createHook(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	hooksPath string,
+	callbackURL string,
+	events []string,
+) (core.SubscriptionResult, error) {
+	callbackURL = strings.TrimSpace(callbackURL)
+	if callbackURL == "" {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: callback url is required")
+	}
+	secret, err := generateHookSecret()
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	var response hookResponse
+	if err := p.do(ctx, credential, http.MethodPost, hooksPath, hookRequest{
+		Name:   "web",
+		Active: true,
+		Events: events,
+		Config: &hookConfig{
+			URL:         callbackURL,
+			ContentType: "json",
+			Secret:      secret,
+			InsecureSSL: "0",
+		},
+	}, &response); err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if response.ID == 0 {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/github: hook id missing from response")
+	}
+	return hookResult(response, secret, events), nil
+}
+
+func (p *Provider) resolveEvents(metadata map[string]any) []string {
+	events := readStringList(metadata, MetadataEvents)
+	if len(events) == 0 {
+		events = append([]string(nil), p.webhookEvents...)
+	}
+	return events
+}
+
+func (p *Provider) do(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	method string,
+	path string,
+	body any,
+	out any,
+) error {
+	if credential == nil || strings.TrimSpace(credential.AccessToken) == "" {
+		return fmt.Errorf("providers/github: access token is required")
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("providers/github: encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.apiBaseURL+"/"+strings.TrimLeft(path, "/"), reader)
+	if err != nil {
+		return fmt.Errorf("providers/github: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(credential.AccessToken))
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", APIVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("providers/github: %s %s: %w", method, path, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxHookResponseBodyBytes))
+	if err != nil {
+		return fmt.Errorf("providers/github: read response: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &apiError{
+			StatusCode: res.StatusCode,
+			Method:     method,
+			Path:       path,
+			Body:       strings.TrimSpace(string(raw)),
+		}
+	}
+	if out == nil || len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("providers/github: decode response: %w", err)
+	}
+	return nil
+}
+
+// NewWebhookTemplate validates deliveries against the secret stored for the
+// hook that sent them.
+func NewWebhookTemplate(
+	subscriptions webhooks.SubscriptionLookup,
+	secrets core.SecretProvider,
+) webhooks.ProviderWebhookTemplate {
+	return webhooks.NewGitHubSubscriptionWebhookTemplate(ProviderID, subscriptions, secrets)
+}
+
+func hooksPath(resourceType string, resourceID string) (string, error) {
+	resourceID = strings.Trim(strings.TrimSpace(resourceID), "/")
+	switch strings.TrimSpace(strings.ToLower(resourceType)) {
+	case ResourceTypeRepository, "repo":
+		owner, repo, ok := strings.Cut(resourceID, "/")
+		if !ok || strings.TrimSpace(owner) == "" || strings.TrimSpace(repo) == "" || strings.Contains(repo, "/") {
+			return "", fmt.Errorf("providers/github: repository resource id must be owner/repo")
+		}
+		return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/hooks", nil
+	case ResourceTypeOrganization, "org":
+		if resourceID == "" || strings.Contains(resourceID, "/") {
+			return "", fmt.Errorf("providers/github: organization resource id is required")
+		}
+		return "orgs/" + url.PathEscape(resourceID) + "/hooks", nil
+	default:
+		return "", fmt.Errorf("providers/github: unsupported resource type %q", resourceType)
+	}
+}
+
+func hookResult(response hookResponse, secret string, events []string) core.SubscriptionResult {
+	hookID := strconv.FormatInt(response.ID, 10)
+	if len(response.Events) > 0 {
+		events = response.Events
+	}
+	return core.SubscriptionResult{
+		ChannelID:            hookID,
+		RemoteSubscriptionID: hookID,
+		Metadata: map[string]any{
+			MetadataHookID: hookID,
+			MetadataEvents: append([]string(nil), events...),
+			"hook_api_url": strings.TrimSpace(response.URL),
+		},
+		VerificationToken: secret,
+	}
+}
+
+func generateHookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("providers/github: generate hook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func readStringList(metadata map[string]any, key string) []string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	var values []string
+	switch typed := metadata[key].(type) {
+	case []string:
+		values = typed
+	case []any:
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+	case string:
+		values = strings.Split(typed, ",")
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+func TestProvider_ManagesRepositoryHookLifecycle(t *testing.T) {
+	var created, patched hookRequest
+	deleted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" || r.Header.Get("X-GitHub-Api-Version") != APIVersion {
+			t.Fatalf("unexpected request headers %v", r.Header)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/hooks":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 101, "active": true, "events": created.Events})
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/widgets/hooks/101":
+			patched = hookRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 101, "active": true, "events": patched.Events})
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/acme/widgets/hooks/101":
+			deleted++
+			if deleted > 1 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", APIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	subscribable, ok := provider.(core.SubscribableProvider)
+	if !ok {
+		t.Fatalf("expected github provider to be subscribable")
+	}
+	credential := &core.ActiveCredential{AccessToken: "gh-token"}
+
+	result, err := subscribable.Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: ResourceTypeRepository,
+		ResourceID:   "acme/widgets",
+		CallbackURL:  "https://app.example/webhooks/github",
+		Metadata:     map[string]any{MetadataEvents: []any{"issues", "pull_request"}},
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if created.Name != "web" || created.Config.URL != "https://app.example/webhooks/github" || len(created.Events) != 2 {
+		t.Fatalf("unexpected create hook request %+v", created)
+	}
+	if result.ChannelID != "101" || result.RemoteSubscriptionID != "101" {
+		t.Fatalf("expected hook id as channel and remote id, got %+v", result)
+	}
+	if result.VerificationToken == "" || result.VerificationToken != created.Config.Secret {
+		t.Fatalf("expected generated hook secret as verification token")
+	}
+
+	existing := &core.Subscription{
+		ResourceType:         ResourceTypeRepository,
+		ResourceID:           "acme/widgets",
+		ChannelID:            result.ChannelID,
+		RemoteSubscriptionID: result.RemoteSubscriptionID,
+		CallbackURL:          "https://app.example/webhooks/github",
+		Metadata:             result.Metadata,
+	}
+	renewed, err := subscribable.RenewSubscription(context.Background(), core.RenewSubscriptionRequest{
+		Subscription: existing,
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if renewed.VerificationToken != "" || patched.Config != nil {
+		t.Fatalf("expected renewal to keep the hook secret, got token %q config %+v", renewed.VerificationToken, patched.Config)
+	}
+	if len(patched.Events) != 2 || !patched.Active {
+		t.Fatalf("expected renewal to keep subscribed events, got %+v", patched)
+	}
+
+	rotated, err := subscribable.RenewSubscription(context.Background(), core.RenewSubscriptionRequest{
+		Subscription: existing,
+		Credential:   credential,
+		RotateSecret: true,
+	})
+	if err != nil {
+		t.Fatalf("renew with rotation: %v", err)
+	}
+	if patched.Config == nil || rotated.VerificationToken == "" ||
+		rotated.VerificationToken == result.VerificationToken || rotated.VerificationToken != patched.Config.Secret {
+		t.Fatalf("expected requested rotation to issue a new hook secret")
+	}
+
+	for range 2 {
+		if err := subscribable.CancelSubscription(context.Background(), core.CancelSubscriptionRequest{
+			Subscription: existing,
+			Credential:   credential,
+		}); err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+	}
+}
+
+func TestProvider_RejectsUnsupportedHookResources(t *testing.T) {
+	provider, err := New(Config{ClientID: "client"})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	_, err = provider.(core.SubscribableProvider).Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: ResourceTypeRepository,
+		ResourceID:   "widgets",
+		CallbackURL:  "https://app.example/webhooks/github",
+		Credential:   &core.ActiveCredential{AccessToken: "gh-token"},
+	})
+	if err == nil {
+		t.Fatalf("expected repository without owner to be rejected")
+	}
+}
+
+func TestNewWebhookTemplate_VerifiesWithStoredHookSecret(t *testing.T) {
+	ctx := context.Background()
+	secrets := plainSecretProvider{}
+	ref, err := core.EncodeVerificationTokenRef(ctx, secrets, "hook-secret")
+	if err != nil {
+		t.Fatalf("encode token ref: %v", err)
+	}
+	template := NewWebhookTemplate(subscriptionLookup{ChannelID: "101", VerificationTokenRef: ref}, secrets)
+
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	_, _ = mac.Write(body)
+	req := core.InboundRequest{
+		ProviderID: ProviderID,
+		Body:       body,
+		Headers: map[string]string{
+			"X-GitHub-Hook-ID":    "101",
+			"X-GitHub-Delivery":   "delivery_1",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		},
+	}
+	if err := template.Verifier.Verify(ctx, req); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if deliveryID, err := template.Extractor(req); err != nil || deliveryID != "delivery_1" {
+		t.Fatalf("expected X-GitHub-Delivery id, got %q err=%v", deliveryID, err)
+	}
+	req.Headers["X-Hub-Signature-256"] = "sha256=" + hex.EncodeToString([]byte("forged"))
+	if err := template.Verifier.Verify(ctx, req); err == nil {
+		t.Fatalf("expected forged signature to fail")
+	}
+}
+
+func TestNewWebhookTemplate_AcceptsPreviousSecretDuringGraceWindow(t *testing.T) {
+	ctx := context.Background()
+	secrets := plainSecretProvider{}
+	current, _ := core.EncodeVerificationTokenRef(ctx, secrets, "new-secret")
+	previous, _ := core.EncodeVerificationTokenRef(ctx, secrets, "old-secret")
+	now := time.Now().UTC()
+	subscription := subscriptionLookup{
+		ChannelID:            "101",
+		VerificationTokenRef: current,
+		Metadata: map[string]any{
+			core.MetadataKeyPreviousVerificationTokenRef:       previous,
+			core.MetadataKeyPreviousVerificationTokenExpiresAt: now.Add(time.Minute).Format(time.RFC3339Nano),
+		},
+	}
+
+	body := []byte(`{"action":"opened"}`)
+	signed := func(secret string) core.InboundRequest {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(body)
+		return core.InboundRequest{
+			ProviderID: ProviderID,
+			Body:       body,
+			Headers: map[string]string{
+				"X-GitHub-Hook-ID":    "101",
+				"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+			},
+		}
+	}
+	verifier := NewWebhookTemplate(subscription, secrets).Verifier
+	for _, secret := range []string{"new-secret", "old-secret"} {
+		if err := verifier.Verify(ctx, signed(secret)); err != nil {
+			t.Fatalf("expected %s to verify during the grace window: %v", secret, err)
+		}
+	}
+
+	subscription.Metadata[core.MetadataKeyPreviousVerificationTokenExpiresAt] = now.Add(-time.Second).Format(time.RFC3339Nano)
+	verifier = NewWebhookTemplate(subscription, secrets).Verifier
+	if err := verifier.Verify(ctx, signed("old-secret")); err == nil {
+		t.Fatalf("expected previous secret to fail after the grace window")
+	}
+}
+
+type subscriptionLookup core.Subscription
+
+func (s subscriptionLookup) GetByChannelID(context.Context, string, string) (core.Subscription, error) {
+	return core.Subscription(s), nil
+}
+
+var _ webhooks.SubscriptionLookup = subscriptionLookup{}
+
+type plainSecretProvider struct{}
+
+func (plainSecretProvider) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return append([]byte("enc:"), plaintext...), nil
+}
+
+func (plainSecretProvider) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return ciphertext[len("enc:"):], nil
+}
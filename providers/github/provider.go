@@ -1,6 +1,8 @@
 package github
 
 import (
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/goliatone/go-services/core"
@@ -10,8 +12,12 @@ import (
 
 const (
 	ProviderID = "github"
-	AuthURL    = "https://github.com/login/oauth/authorize"
-	TokenURL   = "https://github.com/login/oauth/access_token"
+	// AppProviderID identifies the GitHub App provider. Its connections and
+	// the installations recorded from installation webhooks are kept apart
+	// from OAuth app connections.
+	AppProviderID = "github_app"
+	AuthURL       = "https://github.com/login/oauth/authorize"
+	TokenURL      = "https://github.com/login/oauth/access_token"
 )
 
 type Config struct {
@@ -22,6 +28,11 @@ type Config struct {
 	DefaultScopes       []string
 	SupportedScopeTypes []string
 	TokenTTL            time.Duration
+	APIBaseURL          string
+	HTTPClient          providers.HTTPDoer
+	// WebhookEvents are subscribed when a request does not list events in
+	// metadata.
+	WebhookEvents []string
 }
 
 func DefaultConfig() Config {
@@ -29,6 +40,8 @@ func DefaultConfig() Config {
 		AuthURL:       AuthURL,
 		TokenURL:      TokenURL,
 		DefaultScopes: []string{"repo", "read:user"},
+		APIBaseURL:    DefaultAPIBaseURL,
+		WebhookEvents: []string{"push"},
 	}
 }
 
@@ -43,7 +56,18 @@ func New(cfg Config) (core.Provider, error) {
 	if len(cfg.DefaultScopes) == 0 {
 		cfg.DefaultScopes = defaults.DefaultScopes
 	}
-	return providers.NewOAuth2Provider(providers.OAuth2Config{
+	cfg.APIBaseURL = strings.TrimRight(strings.TrimSpace(cfg.APIBaseURL), "/")
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = defaults.APIBaseURL
+	}
+	if len(cfg.WebhookEvents) == 0 {
+		cfg.WebhookEvents = defaults.WebhookEvents
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	oauth, err := providers.NewOAuth2Provider(providers.OAuth2Config{
 		ID:                  ProviderID,
 		AuthURL:             cfg.AuthURL,
 		TokenURL:            cfg.TokenURL,
@@ -53,11 +77,22 @@ func New(cfg Config) (core.Provider, error) {
 		ProfileResolver:     identity.DefaultResolver(),
 		SupportedScopeTypes: cfg.SupportedScopeTypes,
 		TokenTTL:            cfg.TokenTTL,
+		HTTPClient:          cfg.HTTPClient,
 		Capabilities: []core.CapabilityDescriptor{
 			{Name: "repo.read", RequiredGrants: []string{"repo"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
 			{Name: "repo.write", RequiredGrants: []string{"repo"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
 			{Name: "issues.read", RequiredGrants: []string{"repo"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
 			{Name: "issues.write", RequiredGrants: []string{"repo"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
+			{Name: "webhooks.manage", RequiredGrants: []string{"admin:repo_hook"}, DeniedBehavior: core.CapabilityDeniedBehaviorBlock},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		OAuth2Provider: oauth,
+		apiBaseURL:     cfg.APIBaseURL,
+		httpClient:     httpClient,
+		webhookEvents:  append([]string(nil), cfg.WebhookEvents...),
+	}, nil
 }
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+func TestProvider_ListChangesRestartsFullSyncWhenSyncTokenGone(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendar/v3/calendars/team@example.com/events" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		query := r.URL.Query()
+		queries = append(queries, r.URL.RawQuery)
+		switch {
+		case query.Get("syncToken") == "expired":
+			http.Error(w, `{"error":{"code":410}}`, http.StatusGone)
+		case query.Get("syncToken") == "" && query.Get("pageToken") == "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items": []map[string]any{
+					{"id": "evt_1", "etag": `"e1"`, "status": "confirmed"},
+					{"id": "evt_2", "etag": `"e2"`, "status": "cancelled"},
+				},
+				"nextPageToken": "p2",
+			})
+		case query.Get("pageToken") == "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{}, "nextSyncToken": "s2"})
+		default:
+			t.Fatalf("unexpected events query %q", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", APIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	incremental := provider.(core.IncrementalSyncProvider)
+	credential := &core.ActiveCredential{AccessToken: "access-token"}
+
+	first, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		ResourceType: ResourceTypeEvents,
+		ResourceID:   "team@example.com",
+		Cursor:       common.ChangeCursor{Sync: "expired"}.String(),
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if !first.HasMore || len(first.Items) != 2 || common.ParseChangeCursor(first.NextCursor).Sync != "" {
+		t.Fatalf("expected the relisted first page without the expired token, got %+v", first)
+	}
+	if first.Items[1]["deleted"] != true || first.Items[0]["version"] != `"e1"` {
+		t.Fatalf("unexpected event items %+v", first.Items)
+	}
+
+	second, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		ResourceID: "team@example.com",
+		Cursor:     first.NextCursor,
+		Credential: credential,
+	})
+	if err != nil {
+		t.Fatalf("list second page: %v", err)
+	}
+	if second.HasMore || second.NextCursor != (common.ChangeCursor{Sync: "s2"}).String() {
+		t.Fatalf("expected next sync token cursor, got %+v", second)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected expired, restarted, and continued requests, got %v", queries)
+	}
+}
@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"strings"
 	"time"
 
 	"github.com/goliatone/go-services/core"
@@ -24,6 +25,11 @@ type Config struct {
 	DisableIdentityScopes bool
 	SupportedScopeTypes   []string
 	TokenTTL              time.Duration
+	APIBaseURL            string
+	HTTPClient            providers.HTTPDoer
+	// ChannelToken is shared by every watch channel when set; otherwise a
+	// random token is generated per channel.
+	ChannelToken string
 }
 
 func DefaultConfig() Config {
@@ -49,7 +55,7 @@ func New(cfg Config) (core.Provider, error) {
 		cfg.DefaultScopes = defaults.DefaultScopes
 	}
 	cfg.DefaultScopes = common.WithIdentityScopes(cfg.DefaultScopes, !cfg.DisableIdentityScopes)
-	return providers.NewOAuth2Provider(providers.OAuth2Config{
+	oauth, err := providers.NewOAuth2Provider(providers.OAuth2Config{
 		ID:                  ProviderID,
 		AuthURL:             cfg.AuthURL,
 		TokenURL:            cfg.TokenURL,
@@ -59,6 +65,7 @@ func New(cfg Config) (core.Provider, error) {
 		ProfileResolver:     identity.DefaultResolver(),
 		SupportedScopeTypes: cfg.SupportedScopeTypes,
 		TokenTTL:            cfg.TokenTTL,
+		HTTPClient:          cfg.HTTPClient,
 		Capabilities: []core.CapabilityDescriptor{
 			{
 				Name:           "calendar.events.read",
@@ -77,4 +84,12 @@ func New(cfg Config) (core.Provider, error) {
 			},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		OAuth2Provider: oauth,
+		watch:          common.NewWatchClient(cfg.APIBaseURL, cfg.HTTPClient),
+		channelToken:   strings.TrimSpace(cfg.ChannelToken),
+	}, nil
 }
@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+	"github.com/goliatone/go-services/providers/google/common"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+const (
+	ResourceTypeEvents = "events"
+	DefaultCalendarID  = "primary"
+
+	stopChannelPath = "calendar/v3/channels/stop"
+)
+
+var _ core.SubscribableProvider = (*Provider)(nil)
+
+type Provider struct {
+	*providers.OAuth2Provider
+	watch        common.WatchClient
+	channelToken string
+}
+
+// Subscribe registers an events.watch channel for the calendar named by
+// ResourceID, defaulting to the primary calendar.
+func (p *Provider) Subscribe(ctx context.Context, req core.SubscribeRequest) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/calendar: provider is nil")
+	}
+	return p.watchEvents(ctx, req.Credential, req.ResourceType, req.ResourceID, req.CallbackURL)
+}
+
+// RenewSubscription opens a replacement channel and stops the previous one;
+// Google channels cannot be extended in place.
+func (p *Provider) RenewSubscription(
+	ctx context.Context,
+	req core.RenewSubscriptionRequest,
+) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/calendar: provider is nil")
+	}
+	if req.Subscription == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/calendar: subscription is required to renew")
+	}
+	existing := req.Subscription
+	result, err := p.watchEvents(ctx, req.Credential, existing.ResourceType, existing.ResourceID, existing.CallbackURL)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if stopErr := p.watch.StopChannel(
+		ctx,
+		req.Credential,
+		stopChannelPath,
+		existing.ChannelID,
+		existing.RemoteSubscriptionID,
+	); stopErr != nil {
+		result.Metadata["previous_channel_stop_error"] = stopErr.Error()
+	}
+	return result, nil
+}
+
+func (p *Provider) CancelSubscription(ctx context.Context, req core.CancelSubscriptionRequest) error {
+	if p == nil {
+		return fmt.Errorf("providers/google/calendar: provider is nil")
+	}
+	if req.Subscription == nil {
+		return fmt.Errorf("providers/google/calendar: subscription is required to cancel")
+	}
+	return p.watch.StopChannel(
+		ctx,
+		req.Credential,
+		stopChannelPath,
+		req.Subscription.ChannelID,
+		req.Subscription.RemoteSubscriptionID,
+	)
+}
+
+func (p *Provider) watchEvents(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	resourceType string,
+	calendarID string,
+	callbackURL string,
+) (core.SubscriptionResult, error) {
+	resourceType = strings.TrimSpace(strings.ToLower(resourceType))
+	if resourceType != "" && resourceType != ResourceTypeEvents {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/calendar: unsupported resource type %q", resourceType)
+	}
+	calendarID = strings.TrimSpace(calendarID)
+	if calendarID == "" {
+		calendarID = DefaultCalendarID
+	}
+	channel, err := common.NewChannel(callbackURL)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if p.channelToken != "" {
+		channel.Token = p.channelToken
+	}
+	token := channel.Token
+
+	var response common.Channel
+	path := "calendar/v3/calendars/" + url.PathEscape(calendarID) + "/events/watch"
+	if err := p.watch.Do(ctx, credential, http.MethodPost, path, channel, &response); err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if strings.TrimSpace(response.ID) == "" {
+		response.ID = channel.ID
+	}
+	return common.ChannelResult(response, token, map[string]any{"calendar_id": calendarID}), nil
+}
+
+type WebhookConfig struct {
+	ChannelToken string
+}
+
+// NewWebhookTemplate validates notifications sent with a shared channel token.
+func NewWebhookTemplate(cfg WebhookConfig) webhooks.ProviderWebhookTemplate {
+	template := webhooks.NewGoogleWebhookTemplate(cfg.ChannelToken)
+	template.ProviderID = ProviderID
+	return template
+}
+
+// NewSubscriptionWebhookTemplate validates notifications against the token
+// stored for each subscription channel.
+func NewSubscriptionWebhookTemplate(
+	subscriptions webhooks.SubscriptionLookup,
+	secrets core.SecretProvider,
+) webhooks.ProviderWebhookTemplate {
+	return webhooks.NewGoogleSubscriptionWebhookTemplate(ProviderID, subscriptions, secrets)
+}
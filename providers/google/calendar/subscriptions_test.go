@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+func TestProvider_SubscribeRenewCancelEventsWatch(t *testing.T) {
+	var stopped []common.Channel
+	var watched []common.Channel
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer access-token" {
+			t.Fatalf("unexpected authorization header %q", got)
+		}
+		var channel common.Channel
+		_ = json.NewDecoder(r.Body).Decode(&channel)
+		switch r.URL.Path {
+		case "/calendar/v3/calendars/team@example.com/events/watch":
+			watched = append(watched, channel)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":          channel.ID,
+				"resourceId":  "res_" + channel.ID[:4],
+				"resourceUri": "https://www.googleapis.com/calendar/v3/calendars/team/events",
+				"expiration":  "1893456000000",
+			})
+		case "/calendar/v3/channels/stop":
+			stopped = append(stopped, channel)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", APIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	subscribable, ok := provider.(core.SubscribableProvider)
+	if !ok {
+		t.Fatalf("expected calendar provider to be subscribable")
+	}
+	credential := &core.ActiveCredential{AccessToken: "access-token"}
+
+	result, err := subscribable.Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: ResourceTypeEvents,
+		ResourceID:   "team@example.com",
+		CallbackURL:  "https://app.example/webhooks/google_calendar",
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if len(watched) != 1 || watched[0].Type != "web_hook" || watched[0].Address != "https://app.example/webhooks/google_calendar" {
+		t.Fatalf("unexpected watch request %+v", watched)
+	}
+	if result.ChannelID != watched[0].ID || result.RemoteSubscriptionID == "" {
+		t.Fatalf("expected channel and resource ids, got %+v", result)
+	}
+	if result.VerificationToken == "" || result.VerificationToken != watched[0].Token {
+		t.Fatalf("expected generated channel token in result")
+	}
+	if result.ExpiresAt == nil || result.ExpiresAt.Year() != 2030 {
+		t.Fatalf("expected expiration parsed from milliseconds, got %v", result.ExpiresAt)
+	}
+
+	existing := &core.Subscription{
+		ResourceType:         ResourceTypeEvents,
+		ResourceID:           "team@example.com",
+		ChannelID:            result.ChannelID,
+		RemoteSubscriptionID: result.RemoteSubscriptionID,
+		CallbackURL:          "https://app.example/webhooks/google_calendar",
+	}
+	renewed, err := subscribable.RenewSubscription(context.Background(), core.RenewSubscriptionRequest{
+		Subscription: existing,
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if renewed.ChannelID == result.ChannelID {
+		t.Fatalf("expected renewal to open a new channel")
+	}
+	if len(stopped) != 1 || stopped[0].ID != result.ChannelID || stopped[0].ResourceID != result.RemoteSubscriptionID {
+		t.Fatalf("expected previous channel to be stopped, got %+v", stopped)
+	}
+
+	if err := subscribable.CancelSubscription(context.Background(), core.CancelSubscriptionRequest{
+		Subscription: &core.Subscription{ChannelID: renewed.ChannelID, RemoteSubscriptionID: renewed.RemoteSubscriptionID},
+		Credential:   credential,
+	}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(stopped) != 2 || stopped[1].ID != renewed.ChannelID {
+		t.Fatalf("expected cancel to stop renewed channel, got %+v", stopped)
+	}
+}
+
+func TestProvider_SubscribeRequiresCredential(t *testing.T) {
+	provider, err := New(Config{ClientID: "client"})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	_, err = provider.(core.SubscribableProvider).Subscribe(context.Background(), core.SubscribeRequest{
+		CallbackURL: "https://app.example/webhooks/google_calendar",
+	})
+	if err == nil {
+		t.Fatalf("expected missing credential to fail")
+	}
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	cursorSyncKey = "sync"
+	cursorPageKey = "page"
+)
+
+// ChangeCursor is the position of an incremental change feed. Sync holds the
+// provider token that survives between runs (a start page token, history id,
+// or sync token) and Page the continuation token of an unfinished listing.
+type ChangeCursor struct {
+	Sync string
+	Page string
+}
+
+// ParseChangeCursor decodes a cursor produced by ChangeCursor.String. A bare
+// provider token, such as the start page token or history id stored on a
+// subscription, is read as the sync token.
+func ParseChangeCursor(raw string) ChangeCursor {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ChangeCursor{}
+	}
+	values, err := url.ParseQuery(raw)
+	if err == nil && (values.Has(cursorSyncKey) || values.Has(cursorPageKey)) {
+		return ChangeCursor{
+			Sync: strings.TrimSpace(values.Get(cursorSyncKey)),
+			Page: strings.TrimSpace(values.Get(cursorPageKey)),
+		}
+	}
+	return ChangeCursor{Sync: raw}
+}
+
+// String encodes the cursor for SyncCursor and SyncCheckpoint storage.
+func (c ChangeCursor) String() string {
+	values := url.Values{}
+	if sync := strings.TrimSpace(c.Sync); sync != "" {
+		values.Set(cursorSyncKey, sync)
+	}
+	if page := strings.TrimSpace(c.Page); page != "" {
+		values.Set(cursorPageKey, page)
+	}
+	return values.Encode()
+}
+
+// ClampLimit bounds a requested page size to the API maximum, using
+// fallback when no size was requested.
+func ClampLimit(limit int, fallback int, max int) int {
+	if limit <= 0 {
+		limit = fallback
+	}
+	if limit > max {
+		limit = max
+	}
+	return limit
+}
@@ -0,0 +1,227 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+)
+
+const (
+	DefaultAPIBaseURL = "https://www.googleapis.com"
+
+	maxWatchResponseBodyBytes = 1 << 20 // 1 MiB
+)
+
+// Channel is the push notification channel shape shared by the Google
+// Calendar and Drive watch APIs.
+type Channel struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type,omitempty"`
+	Address     string            `json:"address,omitempty"`
+	Token       string            `json:"token,omitempty"`
+	Expiration  string            `json:"expiration,omitempty"`
+	ResourceID  string            `json:"resourceId,omitempty"`
+	ResourceURI string            `json:"resourceUri,omitempty"`
+	Params      map[string]string `json:"params,omitempty"`
+}
+
+// WatchClient performs authenticated JSON calls against Google APIs on behalf
+// of the subscribable providers.
+type WatchClient struct {
+	BaseURL    string
+	HTTPClient providers.HTTPDoer
+}
+
+func NewWatchClient(baseURL string, httpClient providers.HTTPDoer) WatchClient {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultAPIBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return WatchClient{BaseURL: baseURL, HTTPClient: httpClient}
+}
+
+// Do sends a JSON request using the credential bearer token and decodes the
+// response into out when provided.
+func (c WatchClient) Do(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	method string,
+	path string,
+	body any,
+	out any,
+) error {
+	if credential == nil || strings.TrimSpace(credential.AccessToken) == "" {
+		return fmt.Errorf("providers/google: access token is required")
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("providers/google: encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+"/"+strings.TrimLeft(path, "/"), reader)
+	if err != nil {
+		return fmt.Errorf("providers/google: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(credential.AccessToken))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("providers/google: %s %s: %w", method, path, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxWatchResponseBodyBytes))
+	if err != nil {
+		return fmt.Errorf("providers/google: read response: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(raw)),
+		}
+	}
+	if out == nil || len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("providers/google: decode response: %w", err)
+	}
+	return nil
+}
+
+// APIError reports a non-2xx response from a Google API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("providers/google: %s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// IsStatus reports whether err wraps an APIError with the given status code.
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// StopChannel stops a Calendar or Drive notification channel.
+func (c WatchClient) StopChannel(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	path string,
+	channelID string,
+	resourceID string,
+) error {
+	channelID = strings.TrimSpace(channelID)
+	resourceID = strings.TrimSpace(resourceID)
+	if channelID == "" || resourceID == "" {
+		return fmt.Errorf("providers/google: channel id and resource id are required to stop a channel")
+	}
+	return c.Do(ctx, credential, http.MethodPost, path, Channel{ID: channelID, ResourceID: resourceID}, nil)
+}
+
+// NewChannel builds a web_hook channel with a random id and verification
+// token for the given callback address.
+func NewChannel(address string) (Channel, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return Channel{}, fmt.Errorf("providers/google: callback url is required")
+	}
+	id, err := NewChannelID()
+	if err != nil {
+		return Channel{}, err
+	}
+	token, err := randomHex(24)
+	if err != nil {
+		return Channel{}, err
+	}
+	return Channel{ID: id, Type: "web_hook", Address: address, Token: token}, nil
+}
+
+// NewChannelID returns a random identifier usable as a channel id.
+func NewChannelID() (string, error) {
+	return randomHex(16)
+}
+
+// ParseExpiration converts Google's millisecond epoch strings to a time.
+func ParseExpiration(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return nil
+	}
+	expiresAt := time.UnixMilli(ms).UTC()
+	return &expiresAt
+}
+
+// ChannelResult maps a watch response into a subscription result.
+func ChannelResult(channel Channel, token string, metadata map[string]any) core.SubscriptionResult {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	if uri := strings.TrimSpace(channel.ResourceURI); uri != "" {
+		metadata["resource_uri"] = uri
+	}
+	if strings.TrimSpace(channel.Token) != "" {
+		token = channel.Token
+	}
+	return core.SubscriptionResult{
+		ChannelID:            strings.TrimSpace(channel.ID),
+		RemoteSubscriptionID: strings.TrimSpace(channel.ResourceID),
+		ExpiresAt:            ParseExpiration(channel.Expiration),
+		Metadata:             metadata,
+		VerificationToken:    token,
+	}
+}
+
+// ReadMetadataString returns a trimmed string value from request metadata.
+func ReadMetadataString(metadata map[string]any, key string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	switch value := metadata[key].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case fmt.Stringer:
+		return strings.TrimSpace(value.String())
+	default:
+		return ""
+	}
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("providers/google: generate channel value: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
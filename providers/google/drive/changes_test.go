@@ -0,0 +1,91 @@
+package drive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+func TestProvider_ListChangesPagesFromStartPageToken(t *testing.T) {
+	var pageTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/changes/startPageToken":
+			_ = json.NewEncoder(w).Encode(map[string]any{"startPageToken": "10"})
+		case "/drive/v3/changes":
+			token := r.URL.Query().Get("pageToken")
+			pageTokens = append(pageTokens, token)
+			if r.URL.Query().Get("pageSize") != "2" || r.URL.Query().Get("includeRemoved") != "true" {
+				t.Fatalf("unexpected changes query %q", r.URL.RawQuery)
+			}
+			switch token {
+			case "10":
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"changes": []map[string]any{
+						{"changeType": "file", "fileId": "file_1", "time": "t1", "file": map[string]any{"version": "7"}},
+						{"changeType": "file", "fileId": "file_2", "time": "t2", "removed": true},
+					},
+					"nextPageToken": "11",
+				})
+			case "11":
+				_ = json.NewEncoder(w).Encode(map[string]any{"changes": []any{}, "newStartPageToken": "12"})
+			default:
+				t.Fatalf("unexpected page token %q", token)
+			}
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", APIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	incremental := provider.(core.IncrementalSyncProvider)
+	credential := &core.ActiveCredential{AccessToken: "access-token"}
+
+	baseline, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{Credential: credential})
+	if err != nil {
+		t.Fatalf("list baseline: %v", err)
+	}
+	if len(baseline.Items) != 0 || baseline.HasMore || common.ParseChangeCursor(baseline.NextCursor).Sync != "10" {
+		t.Fatalf("expected start page token baseline, got %+v", baseline)
+	}
+
+	first, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		ResourceType: ResourceTypeChanges,
+		Cursor:       baseline.NextCursor,
+		Limit:        2,
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("list first page: %v", err)
+	}
+	if !first.HasMore || len(first.Items) != 2 {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	if first.Items[0]["id"] != "file_1" || first.Items[0]["version"] != "7" || first.Items[1]["deleted"] != true {
+		t.Fatalf("unexpected change items %+v", first.Items)
+	}
+
+	second, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		Cursor:     first.NextCursor,
+		Limit:      2,
+		Credential: credential,
+	})
+	if err != nil {
+		t.Fatalf("list second page: %v", err)
+	}
+	if second.HasMore || second.NextCursor != (common.ChangeCursor{Sync: "12"}).String() {
+		t.Fatalf("expected new start page token cursor, got %+v", second)
+	}
+	if len(pageTokens) != 2 || pageTokens[1] != "11" {
+		t.Fatalf("unexpected page tokens %v", pageTokens)
+	}
+}
@@ -1,6 +1,7 @@
 package drive
 
 import (
+	"strings"
 	"time"
 
 	"github.com/goliatone/go-services/core"
@@ -13,6 +14,9 @@ const (
 	ProviderID = "google_drive"
 	AuthURL    = "https://accounts.google.com/o/oauth2/v2/auth"
 	TokenURL   = "https://oauth2.googleapis.com/token"
+
+	SheetsAPIBaseURL = "https://sheets.googleapis.com"
+	FormsAPIBaseURL  = "https://forms.googleapis.com"
 )
 
 type Config struct {
@@ -24,12 +28,23 @@ type Config struct {
 	DisableIdentityScopes bool
 	SupportedScopeTypes   []string
 	TokenTTL              time.Duration
+	APIBaseURL            string
+	// SheetsAPIBaseURL and FormsAPIBaseURL serve schema discovery for
+	// spreadsheets and forms stored in Drive.
+	SheetsAPIBaseURL string
+	FormsAPIBaseURL  string
+	HTTPClient       providers.HTTPDoer
+	// ChannelToken is shared by every watch channel when set; otherwise a
+	// random token is generated per channel.
+	ChannelToken string
 }
 
 func DefaultConfig() Config {
 	return Config{
-		AuthURL:  AuthURL,
-		TokenURL: TokenURL,
+		AuthURL:          AuthURL,
+		TokenURL:         TokenURL,
+		SheetsAPIBaseURL: SheetsAPIBaseURL,
+		FormsAPIBaseURL:  FormsAPIBaseURL,
 		DefaultScopes: []string{
 			"https://www.googleapis.com/auth/drive.readonly",
 			"https://www.googleapis.com/auth/drive.file",
@@ -48,8 +63,14 @@ func New(cfg Config) (core.Provider, error) {
 	if len(cfg.DefaultScopes) == 0 {
 		cfg.DefaultScopes = defaults.DefaultScopes
 	}
+	if cfg.SheetsAPIBaseURL == "" {
+		cfg.SheetsAPIBaseURL = defaults.SheetsAPIBaseURL
+	}
+	if cfg.FormsAPIBaseURL == "" {
+		cfg.FormsAPIBaseURL = defaults.FormsAPIBaseURL
+	}
 	cfg.DefaultScopes = common.WithIdentityScopes(cfg.DefaultScopes, !cfg.DisableIdentityScopes)
-	return providers.NewOAuth2Provider(providers.OAuth2Config{
+	oauth, err := providers.NewOAuth2Provider(providers.OAuth2Config{
 		ID:                  ProviderID,
 		AuthURL:             cfg.AuthURL,
 		TokenURL:            cfg.TokenURL,
@@ -59,6 +80,7 @@ func New(cfg Config) (core.Provider, error) {
 		ProfileResolver:     identity.DefaultResolver(),
 		SupportedScopeTypes: cfg.SupportedScopeTypes,
 		TokenTTL:            cfg.TokenTTL,
+		HTTPClient:          cfg.HTTPClient,
 		Capabilities: []core.CapabilityDescriptor{
 			{
 				Name:           "drive.files.read",
@@ -77,4 +99,14 @@ func New(cfg Config) (core.Provider, error) {
 			},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		OAuth2Provider: oauth,
+		watch:          common.NewWatchClient(cfg.APIBaseURL, cfg.HTTPClient),
+		sheets:         common.NewWatchClient(cfg.SheetsAPIBaseURL, cfg.HTTPClient),
+		forms:          common.NewWatchClient(cfg.FormsAPIBaseURL, cfg.HTTPClient),
+		channelToken:   strings.TrimSpace(cfg.ChannelToken),
+	}, nil
 }
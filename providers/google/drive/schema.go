@@ -0,0 +1,270 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	ResourceTypeSpreadsheet = "spreadsheet"
+	ResourceTypeForm        = "form"
+
+	SpreadsheetSchemaName = "google.sheets"
+	FormSchemaName        = "google.forms"
+
+	formResponsesObject = "responses"
+)
+
+var _ core.SchemaDiscoveryProvider = (*Provider)(nil)
+
+type spreadsheetResponse struct {
+	Sheets []struct {
+		Properties struct {
+			Title string `json:"title"`
+		} `json:"properties"`
+	} `json:"sheets"`
+}
+
+type valueRangeResponse struct {
+	Values [][]any `json:"values"`
+}
+
+type formResponse struct {
+	FormID string `json:"formId"`
+	Info   struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	Items []struct {
+		Title        string `json:"title"`
+		QuestionItem *struct {
+			Question formQuestion `json:"question"`
+		} `json:"questionItem"`
+		QuestionGroupItem *struct {
+			Questions []formQuestion `json:"questions"`
+		} `json:"questionGroupItem"`
+	} `json:"items"`
+}
+
+type formQuestion struct {
+	QuestionID     string         `json:"questionId"`
+	Required       bool           `json:"required"`
+	ChoiceQuestion *choiceOptions `json:"choiceQuestion"`
+	TextQuestion   *struct{}      `json:"textQuestion"`
+	ScaleQuestion  *struct{}      `json:"scaleQuestion"`
+	DateQuestion   *struct{}      `json:"dateQuestion"`
+	TimeQuestion   *struct{}      `json:"timeQuestion"`
+	FileUpload     *struct{}      `json:"fileUploadQuestion"`
+	RowQuestion    *struct {
+		Title string `json:"title"`
+	} `json:"rowQuestion"`
+}
+
+type choiceOptions struct {
+	Type    string `json:"type"`
+	Options []struct {
+		Value string `json:"value"`
+	} `json:"options"`
+}
+
+// DiscoverSchema describes a spreadsheet, one object per tab with fields
+// named by the header row, or a form, whose responses object has one
+// answers.<questionId> field per question. ResourceID names the file.
+func (p *Provider) DiscoverSchema(ctx context.Context, req core.DiscoverSchemaRequest) (core.ExternalSchema, error) {
+	if p == nil {
+		return core.ExternalSchema{}, fmt.Errorf("providers/google/drive: provider is nil")
+	}
+	fileID := strings.TrimSpace(req.ResourceID)
+	if fileID == "" {
+		return core.ExternalSchema{}, fmt.Errorf("providers/google/drive: resource id is required for schema discovery")
+	}
+	switch strings.TrimSpace(strings.ToLower(req.ResourceType)) {
+	case ResourceTypeSpreadsheet:
+		return p.discoverSpreadsheetSchema(ctx, req, fileID)
+	case ResourceTypeForm:
+		return p.discoverFormSchema(ctx, req, fileID)
+	default:
+		return core.ExternalSchema{}, fmt.Errorf("providers/google/drive: unsupported schema resource type %q", req.ResourceType)
+	}
+}
+
+func (p *Provider) discoverSpreadsheetSchema(
+	ctx context.Context,
+	req core.DiscoverSchemaRequest,
+	spreadsheetID string,
+) (core.ExternalSchema, error) {
+	basePath := "v4/spreadsheets/" + url.PathEscape(spreadsheetID)
+	tabs := req.Objects
+	if len(tabs) == 0 {
+		var spreadsheet spreadsheetResponse
+		query := url.Values{"fields": {"sheets.properties.title"}}
+		if err := p.sheets.Do(ctx, req.Credential, http.MethodGet, basePath+"?"+query.Encode(), nil, &spreadsheet); err != nil {
+			return core.ExternalSchema{}, err
+		}
+		for _, sheet := range spreadsheet.Sheets {
+			tabs = append(tabs, sheet.Properties.Title)
+		}
+	}
+
+	objects := make([]core.ExternalObjectSchema, 0, len(tabs))
+	for _, tab := range tabs {
+		tab = strings.TrimSpace(tab)
+		if tab == "" {
+			continue
+		}
+		var values valueRangeResponse
+		query := url.Values{"valueRenderOption": {"UNFORMATTED_VALUE"}, "majorDimension": {"ROWS"}}
+		path := basePath + "/values/" + url.PathEscape(quoteSheetTitle(tab)+"!1:2") + "?" + query.Encode()
+		if err := p.sheets.Do(ctx, req.Credential, http.MethodGet, path, nil, &values); err != nil {
+			return core.ExternalSchema{}, err
+		}
+		objects = append(objects, sheetObjectSchema(tab, values.Values))
+	}
+	return core.NormalizeDiscoveredSchema(core.ExternalSchema{
+		ProviderID: ProviderID,
+		Scope:      req.Scope,
+		Name:       SpreadsheetSchemaName,
+		Objects:    objects,
+		Metadata:   map[string]any{"spreadsheet_id": spreadsheetID},
+	})
+}
+
+// sheetObjectSchema names fields after the header row and infers their types
+// from the first data row; empty cells default to string.
+func sheetObjectSchema(tab string, rows [][]any) core.ExternalObjectSchema {
+	var header, sample []any
+	if len(rows) > 0 {
+		header = rows[0]
+	}
+	if len(rows) > 1 {
+		sample = rows[1]
+	}
+	fields := make([]core.ExternalField, 0, len(header))
+	seen := make(map[string]bool, len(header))
+	for column, cell := range header {
+		name := strings.TrimSpace(fmt.Sprint(cell))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		var value any
+		if column < len(sample) {
+			value = sample[column]
+		}
+		fields = append(fields, core.ExternalField{
+			Path:     name,
+			Type:     sheetCellType(value),
+			Metadata: map[string]any{"column": columnLetter(column)},
+		})
+	}
+	return core.ExternalObjectSchema{Name: tab, Fields: fields}
+}
+
+func sheetCellType(value any) string {
+	switch typed := value.(type) {
+	case bool:
+		return core.ExternalFieldTypeBoolean
+	case float64:
+		if typed == math.Trunc(typed) {
+			return core.ExternalFieldTypeInteger
+		}
+		return core.ExternalFieldTypeNumber
+	case string:
+		if _, err := time.Parse(time.RFC3339, strings.TrimSpace(typed)); err == nil {
+			return core.ExternalFieldTypeDatetime
+		}
+	}
+	return core.ExternalFieldTypeString
+}
+
+func (p *Provider) discoverFormSchema(
+	ctx context.Context,
+	req core.DiscoverSchemaRequest,
+	formID string,
+) (core.ExternalSchema, error) {
+	var form formResponse
+	if err := p.forms.Do(ctx, req.Credential, http.MethodGet, "v1/forms/"+url.PathEscape(formID), nil, &form); err != nil {
+		return core.ExternalSchema{}, err
+	}
+	fields := []core.ExternalField{
+		{Path: "responseId", Type: core.ExternalFieldTypeString, Required: true},
+		{Path: "createTime", Type: core.ExternalFieldTypeDatetime},
+		{Path: "lastSubmittedTime", Type: core.ExternalFieldTypeDatetime},
+		{Path: "respondentEmail", Type: core.ExternalFieldTypeString},
+	}
+	for _, item := range form.Items {
+		switch {
+		case item.QuestionItem != nil:
+			fields = append(fields, formQuestionField(item.QuestionItem.Question, item.Title))
+		case item.QuestionGroupItem != nil:
+			for _, question := range item.QuestionGroupItem.Questions {
+				title := item.Title
+				if question.RowQuestion != nil {
+					title = strings.TrimSpace(title + " " + question.RowQuestion.Title)
+				}
+				fields = append(fields, formQuestionField(question, title))
+			}
+		}
+	}
+	return core.NormalizeDiscoveredSchema(core.ExternalSchema{
+		ProviderID: ProviderID,
+		Scope:      req.Scope,
+		Name:       FormSchemaName,
+		Objects: []core.ExternalObjectSchema{{
+			Name:       formResponsesObject,
+			PrimaryKey: []string{"responseId"},
+			Fields:     fields,
+		}},
+		Metadata: map[string]any{"form_id": formID, "title": form.Info.Title},
+	})
+}
+
+func formQuestionField(question formQuestion, title string) core.ExternalField {
+	field := core.ExternalField{
+		Path:     "answers." + question.QuestionID,
+		Type:     core.ExternalFieldTypeString,
+		Required: question.Required,
+		Metadata: map[string]any{"title": title},
+	}
+	switch {
+	case question.ChoiceQuestion != nil:
+		field.Repeatable = question.ChoiceQuestion.Type == "CHECKBOX"
+		values := make([]string, 0, len(question.ChoiceQuestion.Options))
+		for _, option := range question.ChoiceQuestion.Options {
+			values = append(values, option.Value)
+		}
+		if len(values) > 0 {
+			field.Constraints = map[string]any{"enum": values}
+		}
+	case question.ScaleQuestion != nil:
+		field.Type = core.ExternalFieldTypeInteger
+	case question.DateQuestion != nil:
+		field.Type = core.ExternalFieldTypeDatetime
+		field.Format = "date"
+	case question.TimeQuestion != nil:
+		field.Format = "time"
+	case question.FileUpload != nil:
+		field.Repeatable = true
+		field.Format = "drive_file_id"
+	}
+	return field
+}
+
+func quoteSheetTitle(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+func columnLetter(index int) string {
+	letters := ""
+	for index >= 0 {
+		letters = string(rune('A'+index%26)) + letters
+		index = index/26 - 1
+	}
+	return letters
+}
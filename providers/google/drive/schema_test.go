@@ -0,0 +1,117 @@
+package drive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_DiscoverSchemaReadsSpreadsheetHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4/spreadsheets/sheet_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"sheets": []map[string]any{
+				{"properties": map[string]any{"title": "Leads"}},
+			}})
+		case "/v4/spreadsheets/sheet_1/values/'Leads'!1:2":
+			if r.URL.Query().Get("valueRenderOption") != "UNFORMATTED_VALUE" {
+				t.Fatalf("unexpected values query %q", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": [][]any{
+				{"Email", "Seats", "Budget", "Qualified", "Signed Up", ""},
+				{"a@example.com", 12, 1500.5, true, "2026-01-02T03:04:05Z"},
+			}})
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", SheetsAPIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	schema, err := provider.(core.SchemaDiscoveryProvider).DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		ResourceType: ResourceTypeSpreadsheet,
+		ResourceID:   "sheet_1",
+		Credential:   &core.ActiveCredential{AccessToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("discover schema: %v", err)
+	}
+	if schema.Name != SpreadsheetSchemaName || len(schema.Objects) != 1 || schema.Objects[0].Name != "Leads" {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+	types := map[string]string{}
+	for _, field := range schema.Objects[0].Fields {
+		types[field.Path] = field.Type
+	}
+	expected := map[string]string{
+		"Email": "string", "Seats": "integer", "Budget": "number", "Qualified": "boolean", "Signed Up": "datetime",
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("unexpected fields %+v", types)
+	}
+	for path, fieldType := range expected {
+		if types[path] != fieldType {
+			t.Fatalf("expected %s to be %s, got %+v", path, fieldType, types)
+		}
+	}
+}
+
+func TestProvider_DiscoverSchemaMapsFormQuestions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/forms/form_1" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"formId": "form_1",
+			"info":   map[string]any{"title": "Signup"},
+			"items": []map[string]any{
+				{"title": "Name", "questionItem": map[string]any{"question": map[string]any{
+					"questionId": "q1", "required": true, "textQuestion": map[string]any{},
+				}}},
+				{"title": "Interests", "questionItem": map[string]any{"question": map[string]any{
+					"questionId": "q2", "choiceQuestion": map[string]any{
+						"type": "CHECKBOX", "options": []map[string]any{{"value": "a"}, {"value": "b"}},
+					},
+				}}},
+				{"title": "Rating", "questionItem": map[string]any{"question": map[string]any{
+					"questionId": "q3", "scaleQuestion": map[string]any{"low": 1, "high": 5},
+				}}},
+				{"title": "Section header"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", FormsAPIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	schema, err := provider.(core.SchemaDiscoveryProvider).DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		ResourceType: ResourceTypeForm,
+		ResourceID:   "form_1",
+		Credential:   &core.ActiveCredential{AccessToken: "token"},
+	})
+	if err != nil {
+		t.Fatalf("discover schema: %v", err)
+	}
+	fields := map[string]core.ExternalField{}
+	for _, field := range schema.Objects[0].Fields {
+		fields[field.Path] = field
+	}
+	if !fields["answers.q1"].Required || fields["answers.q1"].Metadata["title"] != "Name" {
+		t.Fatalf("unexpected text question %+v", fields["answers.q1"])
+	}
+	if !fields["answers.q2"].Repeatable || fields["answers.q3"].Type != core.ExternalFieldTypeInteger {
+		t.Fatalf("unexpected question fields %+v", fields)
+	}
+	if _, ok := fields["responseId"]; !ok {
+		t.Fatalf("expected response fields, got %+v", fields)
+	}
+}
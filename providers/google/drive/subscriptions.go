@@ -0,0 +1,160 @@
+package drive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+	"github.com/goliatone/go-services/providers/google/common"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+const (
+	ResourceTypeChanges = "changes"
+
+	MetadataStartPageToken = "start_page_token"
+
+	stopChannelPath = "drive/v3/channels/stop"
+)
+
+var _ core.SubscribableProvider = (*Provider)(nil)
+
+type Provider struct {
+	*providers.OAuth2Provider
+	watch        common.WatchClient
+	sheets       common.WatchClient
+	forms        common.WatchClient
+	channelToken string
+}
+
+// Subscribe fetches a start page token and registers a changes.watch channel.
+// ResourceID optionally names a shared drive.
+func (p *Provider) Subscribe(ctx context.Context, req core.SubscribeRequest) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/drive: provider is nil")
+	}
+	return p.watchChanges(ctx, req.Credential, req.ResourceType, req.ResourceID, req.CallbackURL)
+}
+
+// RenewSubscription opens a replacement channel and stops the previous one;
+// Google channels cannot be extended in place.
+func (p *Provider) RenewSubscription(
+	ctx context.Context,
+	req core.RenewSubscriptionRequest,
+) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/drive: provider is nil")
+	}
+	if req.Subscription == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/drive: subscription is required to renew")
+	}
+	existing := req.Subscription
+	result, err := p.watchChanges(ctx, req.Credential, existing.ResourceType, existing.ResourceID, existing.CallbackURL)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if stopErr := p.watch.StopChannel(
+		ctx,
+		req.Credential,
+		stopChannelPath,
+		existing.ChannelID,
+		existing.RemoteSubscriptionID,
+	); stopErr != nil {
+		result.Metadata["previous_channel_stop_error"] = stopErr.Error()
+	}
+	return result, nil
+}
+
+func (p *Provider) CancelSubscription(ctx context.Context, req core.CancelSubscriptionRequest) error {
+	if p == nil {
+		return fmt.Errorf("providers/google/drive: provider is nil")
+	}
+	if req.Subscription == nil {
+		return fmt.Errorf("providers/google/drive: subscription is required to cancel")
+	}
+	return p.watch.StopChannel(
+		ctx,
+		req.Credential,
+		stopChannelPath,
+		req.Subscription.ChannelID,
+		req.Subscription.RemoteSubscriptionID,
+	)
+}
+
+func (p *Provider) watchChanges(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	resourceType string,
+	driveID string,
+	callbackURL string,
+) (core.SubscriptionResult, error) {
+	resourceType = strings.TrimSpace(strings.ToLower(resourceType))
+	if resourceType != "" && resourceType != ResourceTypeChanges {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/drive: unsupported resource type %q", resourceType)
+	}
+	channel, err := common.NewChannel(callbackURL)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if p.channelToken != "" {
+		channel.Token = p.channelToken
+	}
+	token := channel.Token
+
+	driveID = strings.TrimSpace(driveID)
+	startPageToken, err := p.startPageToken(ctx, credential, driveID)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+
+	query := url.Values{}
+	query.Set("pageToken", startPageToken)
+	if driveID != "" {
+		query.Set("driveId", driveID)
+		query.Set("supportsAllDrives", "true")
+		query.Set("includeItemsFromAllDrives", "true")
+	}
+	var response common.Channel
+	if err := p.watch.Do(
+		ctx,
+		credential,
+		http.MethodPost,
+		"drive/v3/changes/watch?"+query.Encode(),
+		channel,
+		&response,
+	); err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	if strings.TrimSpace(response.ID) == "" {
+		response.ID = channel.ID
+	}
+	metadata := map[string]any{MetadataStartPageToken: startPageToken}
+	if driveID != "" {
+		metadata["drive_id"] = driveID
+	}
+	return common.ChannelResult(response, token, metadata), nil
+}
+
+type WebhookConfig struct {
+	ChannelToken string
+}
+
+// NewWebhookTemplate validates notifications sent with a shared channel token.
+func NewWebhookTemplate(cfg WebhookConfig) webhooks.ProviderWebhookTemplate {
+	template := webhooks.NewGoogleWebhookTemplate(cfg.ChannelToken)
+	template.ProviderID = ProviderID
+	return template
+}
+
+// NewSubscriptionWebhookTemplate validates notifications against the token
+// stored for each subscription channel.
+func NewSubscriptionWebhookTemplate(
+	subscriptions webhooks.SubscriptionLookup,
+	secrets core.SecretProvider,
+) webhooks.ProviderWebhookTemplate {
+	return webhooks.NewGoogleSubscriptionWebhookTemplate(ProviderID, subscriptions, secrets)
+}
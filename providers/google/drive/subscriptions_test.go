@@ -0,0 +1,70 @@
+package drive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+func TestProvider_SubscribeWatchesChangesFromStartPageToken(t *testing.T) {
+	var watchQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drive/v3/changes/startPageToken":
+			if r.URL.Query().Get("driveId") != "shared_1" {
+				t.Fatalf("expected shared drive id in start token request")
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"startPageToken": "42"})
+		case "/drive/v3/changes/watch":
+			watchQuery = r.URL.RawQuery
+			var channel common.Channel
+			_ = json.NewDecoder(r.Body).Decode(&channel)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":         channel.ID,
+				"resourceId": "drive_res",
+				"expiration": "1893456000000",
+			})
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{
+		ClientID:     "client",
+		APIBaseURL:   server.URL,
+		HTTPClient:   server.Client(),
+		ChannelToken: "shared-token",
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	result, err := provider.(core.SubscribableProvider).Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: ResourceTypeChanges,
+		ResourceID:   "shared_1",
+		CallbackURL:  "https://app.example/webhooks/google_drive",
+		Credential:   &core.ActiveCredential{AccessToken: "access-token"},
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if watchQuery == "" || result.Metadata[MetadataStartPageToken] != "42" {
+		t.Fatalf("expected start page token to be used and recorded, query=%q metadata=%v", watchQuery, result.Metadata)
+	}
+	if result.RemoteSubscriptionID != "drive_res" || result.VerificationToken != "shared-token" {
+		t.Fatalf("unexpected subscription result %+v", result)
+	}
+
+	template := NewWebhookTemplate(WebhookConfig{ChannelToken: "shared-token"})
+	if err := template.Verifier.Verify(context.Background(), core.InboundRequest{
+		ProviderID: ProviderID,
+		Headers:    map[string]string{"X-Goog-Channel-Token": "shared-token"},
+	}); err != nil {
+		t.Fatalf("expected configured channel token to verify: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+const (
+	defaultHistoryPageSize = 100
+	maxHistoryPageSize     = 500
+)
+
+var _ core.IncrementalSyncProvider = (*Provider)(nil)
+
+type historyResponse struct {
+	History       []historyRecord `json:"history"`
+	NextPageToken string          `json:"nextPageToken"`
+	HistoryID     string          `json:"historyId"`
+}
+
+type historyRecord struct {
+	ID              string           `json:"id"`
+	MessagesAdded   []historyMessage `json:"messagesAdded"`
+	MessagesDeleted []historyMessage `json:"messagesDeleted"`
+	LabelsAdded     []historyMessage `json:"labelsAdded"`
+	LabelsRemoved   []historyMessage `json:"labelsRemoved"`
+}
+
+type historyMessage struct {
+	Message  map[string]any `json:"message"`
+	LabelIDs []string       `json:"labelIds,omitempty"`
+}
+
+// ListChanges reads users.history.list from the cursor's history id and
+// returns one item per message change. Without a cursor, or when Gmail no
+// longer holds history that old, it records the mailbox's current history id
+// as a new baseline. Changes made between an expired cursor and the new
+// baseline are not returned.
+func (p *Provider) ListChanges(ctx context.Context, req core.ListChangesRequest) (core.ListChangesResult, error) {
+	if p == nil {
+		return core.ListChangesResult{}, fmt.Errorf("providers/google/gmail: provider is nil")
+	}
+	resourceType := strings.TrimSpace(strings.ToLower(req.ResourceType))
+	if resourceType != "" && resourceType != ResourceTypeMailbox {
+		return core.ListChangesResult{}, fmt.Errorf("providers/google/gmail: unsupported resource type %q", resourceType)
+	}
+	userID := strings.TrimSpace(req.ResourceID)
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	cursor := common.ParseChangeCursor(req.Cursor)
+	if cursor.Sync == "" {
+		return p.historyBaseline(ctx, req.Credential, userID)
+	}
+
+	query := url.Values{}
+	query.Set("startHistoryId", cursor.Sync)
+	query.Set("maxResults", strconv.Itoa(common.ClampLimit(req.Limit, defaultHistoryPageSize, maxHistoryPageSize)))
+	if cursor.Page != "" {
+		query.Set("pageToken", cursor.Page)
+	}
+	var response historyResponse
+	path := "gmail/v1/users/" + url.PathEscape(userID) + "/history?" + query.Encode()
+	if err := p.watch.Do(ctx, req.Credential, http.MethodGet, path, nil, &response); err != nil {
+		if common.IsStatus(err, http.StatusNotFound) {
+			return p.historyBaseline(ctx, req.Credential, userID)
+		}
+		return core.ListChangesResult{}, err
+	}
+
+	result := core.ListChangesResult{Metadata: map[string]any{}}
+	for _, record := range response.History {
+		result.Items = appendHistoryItems(result.Items, record, "message_added", record.MessagesAdded, false)
+		result.Items = appendHistoryItems(result.Items, record, "message_deleted", record.MessagesDeleted, true)
+		result.Items = appendHistoryItems(result.Items, record, "labels_added", record.LabelsAdded, false)
+		result.Items = appendHistoryItems(result.Items, record, "labels_removed", record.LabelsRemoved, false)
+	}
+	if strings.TrimSpace(response.NextPageToken) != "" {
+		result.NextCursor = common.ChangeCursor{Sync: cursor.Sync, Page: response.NextPageToken}.String()
+		result.HasMore = true
+		return result, nil
+	}
+	latest := strings.TrimSpace(response.HistoryID)
+	if latest == "" {
+		latest = cursor.Sync
+	}
+	result.NextCursor = common.ChangeCursor{Sync: latest}.String()
+	result.Metadata[MetadataHistoryID] = latest
+	return result, nil
+}
+
+func (p *Provider) historyBaseline(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	userID string,
+) (core.ListChangesResult, error) {
+	var profile struct {
+		HistoryID string `json:"historyId"`
+	}
+	path := "gmail/v1/users/" + url.PathEscape(userID) + "/profile"
+	if err := p.watch.Do(ctx, credential, http.MethodGet, path, nil, &profile); err != nil {
+		return core.ListChangesResult{}, err
+	}
+	historyID := strings.TrimSpace(profile.HistoryID)
+	if historyID == "" {
+		return core.ListChangesResult{}, fmt.Errorf("providers/google/gmail: history id missing from profile")
+	}
+	return core.ListChangesResult{
+		NextCursor: common.ChangeCursor{Sync: historyID}.String(),
+		Metadata: map[string]any{
+			"baseline":        true,
+			MetadataHistoryID: historyID,
+		},
+	}, nil
+}
+
+func appendHistoryItems(
+	items []map[string]any,
+	record historyRecord,
+	changeType string,
+	messages []historyMessage,
+	deleted bool,
+) []map[string]any {
+	for _, entry := range messages {
+		id, _ := entry.Message["id"].(string)
+		if strings.TrimSpace(id) == "" {
+			continue
+		}
+		payload := map[string]any{"message": entry.Message}
+		if len(entry.LabelIDs) > 0 {
+			payload["label_ids"] = entry.LabelIDs
+		}
+		items = append(items, map[string]any{
+			"id":          id,
+			"version":     record.ID + ":" + changeType,
+			"change_type": changeType,
+			"deleted":     deleted,
+			"payload":     payload,
+		})
+	}
+	return items
+}
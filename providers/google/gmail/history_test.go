@@ -0,0 +1,77 @@
+package gmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+func TestProvider_ListChangesReadsHistoryAndResetsExpiredHistoryID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/profile":
+			_ = json.NewEncoder(w).Encode(map[string]any{"historyId": "900"})
+		case "/gmail/v1/users/me/history":
+			switch r.URL.Query().Get("startHistoryId") {
+			case "100":
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"history": []map[string]any{{
+						"id":              "101",
+						"messagesAdded":   []map[string]any{{"message": map[string]any{"id": "msg_1", "threadId": "t_1"}}},
+						"messagesDeleted": []map[string]any{{"message": map[string]any{"id": "msg_2"}}},
+					}},
+					"historyId": "105",
+				})
+			case "1":
+				http.Error(w, `{"error":{"code":404}}`, http.StatusNotFound)
+			default:
+				t.Fatalf("unexpected history query %q", r.URL.RawQuery)
+			}
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{ClientID: "client", APIBaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	incremental := provider.(core.IncrementalSyncProvider)
+	credential := &core.ActiveCredential{AccessToken: "access-token"}
+
+	// A bare history id, as stored by Subscribe, is accepted as a cursor.
+	result, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		ResourceType: ResourceTypeMailbox,
+		Cursor:       "100",
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	if len(result.Items) != 2 || result.HasMore {
+		t.Fatalf("unexpected history page %+v", result)
+	}
+	if result.Items[0]["id"] != "msg_1" || result.Items[1]["deleted"] != true {
+		t.Fatalf("unexpected history items %+v", result.Items)
+	}
+	if common.ParseChangeCursor(result.NextCursor).Sync != "105" {
+		t.Fatalf("expected cursor at latest history id, got %q", result.NextCursor)
+	}
+
+	expired, err := incremental.ListChanges(context.Background(), core.ListChangesRequest{
+		Cursor:     common.ChangeCursor{Sync: "1"}.String(),
+		Credential: credential,
+	})
+	if err != nil {
+		t.Fatalf("list expired history: %v", err)
+	}
+	if expired.Metadata["baseline"] != true || common.ParseChangeCursor(expired.NextCursor).Sync != "900" {
+		t.Fatalf("expected a new baseline at the profile history id, got %+v", expired)
+	}
+}
@@ -1,6 +1,7 @@
 package gmail
 
 import (
+	"strings"
 	"time"
 
 	"github.com/goliatone/go-services/core"
@@ -24,6 +25,12 @@ type Config struct {
 	DisableIdentityScopes bool
 	SupportedScopeTypes   []string
 	TokenTTL              time.Duration
+	APIBaseURL            string
+	HTTPClient            providers.HTTPDoer
+	// TopicName is the default Pub/Sub topic for users.watch, in the form
+	// projects/{project}/topics/{topic}.
+	TopicName     string
+	WatchLabelIDs []string
 }
 
 func DefaultConfig() Config {
@@ -49,7 +56,7 @@ func New(cfg Config) (core.Provider, error) {
 		cfg.DefaultScopes = defaults.DefaultScopes
 	}
 	cfg.DefaultScopes = common.WithIdentityScopes(cfg.DefaultScopes, !cfg.DisableIdentityScopes)
-	return providers.NewOAuth2Provider(providers.OAuth2Config{
+	oauth, err := providers.NewOAuth2Provider(providers.OAuth2Config{
 		ID:                  ProviderID,
 		AuthURL:             cfg.AuthURL,
 		TokenURL:            cfg.TokenURL,
@@ -59,6 +66,7 @@ func New(cfg Config) (core.Provider, error) {
 		ProfileResolver:     identity.DefaultResolver(),
 		SupportedScopeTypes: cfg.SupportedScopeTypes,
 		TokenTTL:            cfg.TokenTTL,
+		HTTPClient:          cfg.HTTPClient,
 		Capabilities: []core.CapabilityDescriptor{
 			{
 				Name:           "mail.read",
@@ -72,4 +80,13 @@ func New(cfg Config) (core.Provider, error) {
 			},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Provider{
+		OAuth2Provider: oauth,
+		watch:          common.NewWatchClient(cfg.APIBaseURL, cfg.HTTPClient),
+		topicName:      strings.TrimSpace(cfg.TopicName),
+		labelIDs:       append([]string(nil), cfg.WatchLabelIDs...),
+	}, nil
 }
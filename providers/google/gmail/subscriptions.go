@@ -0,0 +1,161 @@
+package gmail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
+	"github.com/goliatone/go-services/providers/google/common"
+)
+
+const (
+	ResourceTypeMailbox = "mailbox"
+	DefaultUserID       = "me"
+
+	MetadataTopicName = "topic_name"
+	MetadataLabelIDs  = "label_ids"
+	MetadataHistoryID = "history_id"
+)
+
+var _ core.SubscribableProvider = (*Provider)(nil)
+
+type Provider struct {
+	*providers.OAuth2Provider
+	watch     common.WatchClient
+	topicName string
+	labelIDs  []string
+}
+
+type watchRequest struct {
+	TopicName           string   `json:"topicName"`
+	LabelIDs            []string `json:"labelIds,omitempty"`
+	LabelFilterBehavior string   `json:"labelFilterBehavior,omitempty"`
+}
+
+type watchResponse struct {
+	HistoryID  string `json:"historyId"`
+	Expiration string `json:"expiration"`
+}
+
+// Subscribe calls users.watch so mailbox changes are published to a Pub/Sub
+// topic. The topic comes from request metadata or the provider config.
+// Notifications are authenticated by Pub/Sub, so no verification token is
+// issued.
+func (p *Provider) Subscribe(ctx context.Context, req core.SubscribeRequest) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/gmail: provider is nil")
+	}
+	resourceType := strings.TrimSpace(strings.ToLower(req.ResourceType))
+	if resourceType != "" && resourceType != ResourceTypeMailbox {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/gmail: unsupported resource type %q", resourceType)
+	}
+	channelID, err := common.NewChannelID()
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	return p.watchMailbox(ctx, req.Credential, req.ResourceID, channelID, req.Metadata)
+}
+
+// RenewSubscription re-issues users.watch, which Gmail treats as an in-place
+// renewal for the mailbox.
+func (p *Provider) RenewSubscription(
+	ctx context.Context,
+	req core.RenewSubscriptionRequest,
+) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/gmail: provider is nil")
+	}
+	if req.Subscription == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/gmail: subscription is required to renew")
+	}
+	metadata := map[string]any{}
+	for key, value := range req.Subscription.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	return p.watchMailbox(ctx, req.Credential, req.Subscription.ResourceID, req.Subscription.ChannelID, metadata)
+}
+
+func (p *Provider) CancelSubscription(ctx context.Context, req core.CancelSubscriptionRequest) error {
+	if p == nil {
+		return fmt.Errorf("providers/google/gmail: provider is nil")
+	}
+	userID := DefaultUserID
+	if req.Subscription != nil && strings.TrimSpace(req.Subscription.ResourceID) != "" {
+		userID = strings.TrimSpace(req.Subscription.ResourceID)
+	}
+	return p.watch.Do(ctx, req.Credential, http.MethodPost, "gmail/v1/users/"+url.PathEscape(userID)+"/stop", nil, nil)
+}
+
+func (p *Provider) watchMailbox(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	userID string,
+	channelID string,
+	metadata map[string]any,
+) (core.SubscriptionResult, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	topicName := common.ReadMetadataString(metadata, MetadataTopicName)
+	if topicName == "" {
+		topicName = p.topicName
+	}
+	if topicName == "" {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/google/gmail: pub/sub topic name is required")
+	}
+	labelIDs := readLabelIDs(metadata)
+	if len(labelIDs) == 0 {
+		labelIDs = append([]string(nil), p.labelIDs...)
+	}
+	body := watchRequest{TopicName: topicName, LabelIDs: labelIDs}
+	if len(labelIDs) > 0 {
+		body.LabelFilterBehavior = "include"
+	}
+
+	var response watchResponse
+	path := "gmail/v1/users/" + url.PathEscape(userID) + "/watch"
+	if err := p.watch.Do(ctx, credential, http.MethodPost, path, body, &response); err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	result := core.SubscriptionResult{
+		ChannelID:            channelID,
+		RemoteSubscriptionID: topicName,
+		ExpiresAt:            common.ParseExpiration(response.Expiration),
+		Metadata: map[string]any{
+			MetadataTopicName: topicName,
+			MetadataHistoryID: strings.TrimSpace(response.HistoryID),
+		},
+	}
+	if len(labelIDs) > 0 {
+		result.Metadata[MetadataLabelIDs] = labelIDs
+	}
+	return result, nil
+}
+
+func readLabelIDs(metadata map[string]any) []string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	switch value := metadata[MetadataLabelIDs].(type) {
+	case []string:
+		return append([]string(nil), value...)
+	case []any:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			if text, ok := item.(string); ok && strings.TrimSpace(text) != "" {
+				out = append(out, strings.TrimSpace(text))
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package gmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_SubscribeWatchesMailboxTopic(t *testing.T) {
+	var body watchRequest
+	stopped := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/watch":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_ = json.NewEncoder(w).Encode(map[string]any{"historyId": "9001", "expiration": "1893456000000"})
+		case "/gmail/v1/users/me/stop":
+			stopped++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := New(Config{
+		ClientID:      "client",
+		APIBaseURL:    server.URL,
+		HTTPClient:    server.Client(),
+		TopicName:     "projects/acme/topics/gmail",
+		WatchLabelIDs: []string{"INBOX"},
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	subscribable := provider.(core.SubscribableProvider)
+	credential := &core.ActiveCredential{AccessToken: "access-token"}
+
+	result, err := subscribable.Subscribe(context.Background(), core.SubscribeRequest{Credential: credential})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if body.TopicName != "projects/acme/topics/gmail" || len(body.LabelIDs) != 1 || body.LabelFilterBehavior != "include" {
+		t.Fatalf("unexpected watch body %+v", body)
+	}
+	if result.ChannelID == "" || result.RemoteSubscriptionID != "projects/acme/topics/gmail" {
+		t.Fatalf("unexpected subscription ids %+v", result)
+	}
+	if result.Metadata[MetadataHistoryID] != "9001" || result.ExpiresAt == nil {
+		t.Fatalf("expected history id and expiration, got %+v", result)
+	}
+
+	renewed, err := subscribable.RenewSubscription(context.Background(), core.RenewSubscriptionRequest{
+		Subscription: &core.Subscription{ChannelID: result.ChannelID, Metadata: result.Metadata},
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if renewed.ChannelID != result.ChannelID {
+		t.Fatalf("expected gmail renewal to keep channel id")
+	}
+	if err := subscribable.CancelSubscription(context.Background(), core.CancelSubscriptionRequest{
+		Subscription: &core.Subscription{ChannelID: result.ChannelID},
+		Credential:   credential,
+	}); err != nil || stopped != 1 {
+		t.Fatalf("expected users.stop call, stopped=%d err=%v", stopped, err)
+	}
+}
+
+func TestProvider_SubscribeRequiresTopic(t *testing.T) {
+	provider, err := New(Config{ClientID: "client"})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	_, err = provider.(core.SubscribableProvider).Subscribe(context.Background(), core.SubscribeRequest{
+		Credential: &core.ActiveCredential{AccessToken: "access-token"},
+	})
+	if err == nil {
+		t.Fatalf("expected missing pub/sub topic to fail")
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/goliatone/go-services/auth"
 	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
 )
 
 const (
@@ -41,6 +42,8 @@ type Config struct {
 	SupportedScopes   []string
 	InstanceURL       string
 	ExternalAccountID string
+	// HTTPClient performs schema discovery calls.
+	HTTPClient providers.HTTPDoer
 }
 
 type Provider struct {
@@ -50,6 +53,7 @@ type Provider struct {
 	scopeTypes    []string
 	externalAcct  string
 	defaultGrants []string
+	httpClient    providers.HTTPDoer
 }
 
 func DefaultConfig() Config {
@@ -99,6 +103,7 @@ func New(cfg Config) (core.Provider, error) {
 		scopeTypes:    normalizeScopeTypes(cfg.SupportedScopes),
 		externalAcct:  strings.TrimSpace(cfg.ExternalAccountID),
 		defaultGrants: defaultGrants,
+		httpClient:    cfg.HTTPClient,
 	}, nil
 }
 
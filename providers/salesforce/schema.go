@@ -0,0 +1,208 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	SchemaName = "salesforce.sobjects"
+
+	maxDescribeResponseBytes = 4 << 20 // 4 MiB
+)
+
+var defaultSchemaObjects = []string{"Account"}
+
+var _ core.SchemaDiscoveryProvider = (*Provider)(nil)
+
+type sObjectDescribe struct {
+	Name   string         `json:"name"`
+	Label  string         `json:"label"`
+	Fields []sObjectField `json:"fields"`
+}
+
+type sObjectField struct {
+	Name              string   `json:"name"`
+	Label             string   `json:"label"`
+	Type              string   `json:"type"`
+	Nillable          bool     `json:"nillable"`
+	Createable        bool     `json:"createable"`
+	Updateable        bool     `json:"updateable"`
+	DefaultedOnCreate bool     `json:"defaultedOnCreate"`
+	Length            int      `json:"length"`
+	ExternalID        bool     `json:"externalId"`
+	ReferenceTo       []string `json:"referenceTo"`
+	PicklistValues    []struct {
+		Value  string `json:"value"`
+		Active bool   `json:"active"`
+	} `json:"picklistValues"`
+}
+
+// DiscoverSchema describes the requested sObjects, Account by default. The
+// instance URL comes from the credential's instance_url metadata when the
+// token response carried one.
+func (p *Provider) DiscoverSchema(ctx context.Context, req core.DiscoverSchemaRequest) (core.ExternalSchema, error) {
+	if p == nil {
+		return core.ExternalSchema{}, fmt.Errorf("providers/salesforce: provider is nil")
+	}
+	if req.Credential == nil || strings.TrimSpace(req.Credential.AccessToken) == "" {
+		return core.ExternalSchema{}, fmt.Errorf("providers/salesforce: access token is required")
+	}
+	baseURL := p.instanceURL
+	if instanceURL, ok := req.Credential.Metadata["instance_url"].(string); ok && strings.TrimSpace(instanceURL) != "" {
+		baseURL = strings.TrimRight(strings.TrimSpace(instanceURL), "/")
+	}
+	if baseURL == "" {
+		baseURL = DefaultAPIBase
+	}
+	objectNames := req.Objects
+	if len(objectNames) == 0 {
+		objectNames = defaultSchemaObjects
+	}
+
+	objects := make([]core.ExternalObjectSchema, 0, len(objectNames))
+	for _, name := range objectNames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		describe, err := p.describeSObject(ctx, req.Credential.AccessToken, baseURL, name)
+		if err != nil {
+			return core.ExternalSchema{}, err
+		}
+		objects = append(objects, sObjectSchema(describe))
+	}
+	return core.NormalizeDiscoveredSchema(core.ExternalSchema{
+		ProviderID: ProviderID,
+		Scope:      req.Scope,
+		Name:       SchemaName,
+		Objects:    objects,
+		Metadata:   map[string]any{"api_version": defaultAPIVersion},
+	})
+}
+
+func (p *Provider) describeSObject(
+	ctx context.Context,
+	accessToken string,
+	baseURL string,
+	name string,
+) (sObjectDescribe, error) {
+	endpoint := baseURL + "/services/data/" + defaultAPIVersion + "/sobjects/" + url.PathEscape(name) + "/describe"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return sObjectDescribe{}, fmt.Errorf("providers/salesforce: build describe request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(accessToken))
+	request.Header.Set("Accept", "application/json")
+
+	client := p.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return sObjectDescribe{}, fmt.Errorf("providers/salesforce: describe %s: %w", name, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxDescribeResponseBytes))
+	if err != nil {
+		return sObjectDescribe{}, fmt.Errorf("providers/salesforce: read describe response: %w", err)
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return sObjectDescribe{}, fmt.Errorf(
+			"providers/salesforce: describe %s returned status %d: %s",
+			name,
+			response.StatusCode,
+			strings.TrimSpace(string(body)),
+		)
+	}
+	var describe sObjectDescribe
+	if err := json.Unmarshal(body, &describe); err != nil {
+		return sObjectDescribe{}, fmt.Errorf("providers/salesforce: decode describe %s: %w", name, err)
+	}
+	if strings.TrimSpace(describe.Name) == "" {
+		describe.Name = name
+	}
+	return describe, nil
+}
+
+func sObjectSchema(describe sObjectDescribe) core.ExternalObjectSchema {
+	fields := make([]core.ExternalField, 0, len(describe.Fields))
+	for _, field := range describe.Fields {
+		fieldType, format := sObjectFieldType(field.Type)
+		constraints := map[string]any{}
+		if field.Length > 0 {
+			constraints["max_length"] = field.Length
+		}
+		if len(field.ReferenceTo) > 0 {
+			constraints["reference_to"] = append([]string(nil), field.ReferenceTo...)
+		}
+		values := make([]string, 0, len(field.PicklistValues))
+		for _, value := range field.PicklistValues {
+			if value.Active {
+				values = append(values, value.Value)
+			}
+		}
+		if len(values) > 0 {
+			constraints["enum"] = values
+		}
+		if len(constraints) == 0 {
+			constraints = nil
+		}
+		fields = append(fields, core.ExternalField{
+			Path:        field.Name,
+			Type:        fieldType,
+			Required:    !field.Nillable && field.Createable && !field.DefaultedOnCreate,
+			Format:      format,
+			Constraints: constraints,
+			Metadata: map[string]any{
+				"label":       field.Label,
+				"native_type": field.Type,
+				"createable":  field.Createable,
+				"updateable":  field.Updateable,
+				"external_id": field.ExternalID,
+			},
+		})
+	}
+	return core.ExternalObjectSchema{
+		Name:       describe.Name,
+		PrimaryKey: []string{"Id"},
+		Fields:     fields,
+		Metadata:   map[string]any{"label": describe.Label},
+	}
+}
+
+// sObjectFieldType maps a describe field type onto the compiler vocabulary,
+// keeping the native detail as the format.
+func sObjectFieldType(native string) (string, string) {
+	switch strings.ToLower(strings.TrimSpace(native)) {
+	case "boolean":
+		return core.ExternalFieldTypeBoolean, ""
+	case "int":
+		return core.ExternalFieldTypeInteger, ""
+	case "double":
+		return core.ExternalFieldTypeNumber, ""
+	case "currency", "percent":
+		return core.ExternalFieldTypeNumber, strings.ToLower(native)
+	case "date":
+		return core.ExternalFieldTypeDatetime, "date"
+	case "datetime":
+		return core.ExternalFieldTypeDatetime, "date-time"
+	case "time":
+		return core.ExternalFieldTypeString, "time"
+	case "address", "location":
+		return core.ExternalFieldTypeObject, strings.ToLower(native)
+	case "id", "reference", "email", "phone", "url", "base64", "multipicklist":
+		return core.ExternalFieldTypeString, strings.ToLower(native)
+	default:
+		return core.ExternalFieldTypeString, ""
+	}
+}
@@ -0,0 +1,83 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_DiscoverSchemaDescribesSObjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+defaultAPIVersion+"/sobjects/Account/describe" {
+			t.Fatalf("unexpected describe path %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer sf_token" {
+			t.Fatalf("expected bearer token")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":  "Account",
+			"label": "Account",
+			"fields": []map[string]any{
+				{"name": "Id", "type": "id", "nillable": false, "createable": false},
+				{"name": "Name", "type": "string", "length": 255, "nillable": false, "createable": true},
+				{"name": "AnnualRevenue", "type": "currency", "nillable": true, "createable": true},
+				{"name": "CreatedDate", "type": "datetime", "nillable": false, "createable": false},
+				{"name": "OwnerId", "type": "reference", "referenceTo": []string{"User"}, "nillable": false,
+					"createable": true, "defaultedOnCreate": true},
+				{"name": "Industry", "type": "picklist", "nillable": true, "createable": true, "picklistValues": []map[string]any{
+					{"value": "Banking", "active": true},
+					{"value": "Retired", "active": false},
+				}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	providerRaw, err := New(Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     "https://auth.example/token",
+		HTTPClient:   server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	provider := providerRaw.(*Provider)
+	schema, err := provider.DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		Scope: core.ScopeRef{Type: "org", ID: "org_123"},
+		Credential: &core.ActiveCredential{
+			AccessToken: "sf_token",
+			Metadata:    map[string]any{"instance_url": server.URL},
+		},
+	})
+	if err != nil {
+		t.Fatalf("discover schema: %v", err)
+	}
+	if schema.Name != SchemaName || schema.Version == "" || len(schema.Objects) != 1 {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+	fields := map[string]core.ExternalField{}
+	for _, field := range schema.Objects[0].Fields {
+		fields[field.Path] = field
+	}
+	if fields["AnnualRevenue"].Type != core.ExternalFieldTypeNumber || fields["AnnualRevenue"].Format != "currency" {
+		t.Fatalf("unexpected currency field %+v", fields["AnnualRevenue"])
+	}
+	if fields["CreatedDate"].Type != core.ExternalFieldTypeDatetime {
+		t.Fatalf("unexpected datetime field %+v", fields["CreatedDate"])
+	}
+	if !fields["Name"].Required || fields["OwnerId"].Required || fields["Id"].Required {
+		t.Fatalf("unexpected required flags %+v", fields)
+	}
+	if !reflect.DeepEqual(fields["Industry"].Constraints["enum"], []string{"Banking"}) {
+		t.Fatalf("expected active picklist values, got %+v", fields["Industry"].Constraints)
+	}
+	if err := schema.Validate(); err != nil {
+		t.Fatalf("expected valid schema: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/goliatone/go-services/core"
 	"github.com/goliatone/go-services/providers"
 	shopifyembedded "github.com/goliatone/go-services/providers/shopify/embedded"
+	"github.com/goliatone/go-services/transport"
 )
 
 const (
@@ -53,6 +54,12 @@ type Config struct {
 	TokenRequestTimeout time.Duration
 	HTTPClient          providers.HTTPDoer
 
+	// AdminAPIVersion selects the Admin GraphQL API version used for webhook
+	// subscription management. AdminAPIBaseURL overrides the shop host.
+	AdminAPIVersion  string
+	AdminAPIBaseURL  string
+	GraphQLTransport core.TransportAdapter
+
 	EmbeddedAuthService        core.EmbeddedAuthService
 	EmbeddedExpectedShopDomain string
 	EmbeddedClockSkew          time.Duration
@@ -83,6 +90,10 @@ type Provider struct {
 	embeddedAuth        core.EmbeddedAuthService
 	supportedScopeTypes []string
 	capabilities        []core.CapabilityDescriptor
+	shopDomain          string
+	adminAPIVersion     string
+	adminAPIBaseURL     string
+	graphQLTransport    core.TransportAdapter
 }
 
 func DefaultConfig() Config {
@@ -90,6 +101,7 @@ func DefaultConfig() Config {
 		Mode:                ModeHybrid,
 		DefaultScopes:       []string{ScopeReadProducts, ScopeReadInventory, ScopeReadOrders},
 		SupportedScopeTypes: []string{"org"},
+		AdminAPIVersion:     DefaultAdminAPIVersion,
 	}
 }
 
@@ -154,6 +166,13 @@ func New(cfg Config) (core.Provider, error) {
 	if err != nil {
 		return nil, err
 	}
+	if strings.TrimSpace(cfg.AdminAPIVersion) == "" {
+		cfg.AdminAPIVersion = defaults.AdminAPIVersion
+	}
+	graphQLTransport := cfg.GraphQLTransport
+	if graphQLTransport == nil {
+		graphQLTransport = transport.NewGraphQLAdapter("", cfg.HTTPClient)
+	}
 
 	return &Provider{
 		id:                  ProviderID,
@@ -162,6 +181,10 @@ func New(cfg Config) (core.Provider, error) {
 		embeddedAuth:        embeddedAuth,
 		supportedScopeTypes: append([]string(nil), cfg.SupportedScopeTypes...),
 		capabilities:        cloneCapabilities(BaselineCapabilities()),
+		shopDomain:          strings.TrimSpace(cfg.ShopDomain),
+		adminAPIVersion:     strings.TrimSpace(cfg.AdminAPIVersion),
+		adminAPIBaseURL:     strings.TrimRight(strings.TrimSpace(cfg.AdminAPIBaseURL), "/"),
+		graphQLTransport:    graphQLTransport,
 	}, nil
 }
 
@@ -0,0 +1,236 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	SchemaName = "shopify.admin"
+
+	maxMetafieldDefinitionPages = 20
+)
+
+var _ core.SchemaDiscoveryProvider = (*Provider)(nil)
+
+const metafieldDefinitionsQuery = `query metafieldDefinitions($ownerType: MetafieldOwnerType!, $first: Int!, $after: String) {
+  metafieldDefinitions(ownerType: $ownerType, first: $first, after: $after) {
+    nodes { namespace key name type { name } validations { name value } }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+type schemaObject struct {
+	ownerType string
+	fields    []core.ExternalField
+}
+
+// schemaObjects lists the core objects with the REST field names used by
+// webhook payloads. Metafield definitions are added per owner type.
+var schemaObjects = map[string]schemaObject{
+	"product": {
+		ownerType: "PRODUCT",
+		fields: []core.ExternalField{
+			{Path: "id", Type: core.ExternalFieldTypeInteger, Required: true},
+			{Path: "title", Type: core.ExternalFieldTypeString, Required: true},
+			{Path: "body_html", Type: core.ExternalFieldTypeString},
+			{Path: "vendor", Type: core.ExternalFieldTypeString},
+			{Path: "product_type", Type: core.ExternalFieldTypeString},
+			{Path: "handle", Type: core.ExternalFieldTypeString},
+			{Path: "status", Type: core.ExternalFieldTypeString},
+			{Path: "tags", Type: core.ExternalFieldTypeString},
+			{Path: "created_at", Type: core.ExternalFieldTypeDatetime},
+			{Path: "updated_at", Type: core.ExternalFieldTypeDatetime},
+			{Path: "variants", Type: core.ExternalFieldTypeObject, Repeatable: true},
+			{Path: "variants.id", Type: core.ExternalFieldTypeInteger},
+			{Path: "variants.sku", Type: core.ExternalFieldTypeString},
+			{Path: "variants.price", Type: core.ExternalFieldTypeString, Format: "decimal"},
+			{Path: "variants.inventory_quantity", Type: core.ExternalFieldTypeInteger},
+		},
+	},
+	"customer": {
+		ownerType: "CUSTOMER",
+		fields: []core.ExternalField{
+			{Path: "id", Type: core.ExternalFieldTypeInteger, Required: true},
+			{Path: "email", Type: core.ExternalFieldTypeString},
+			{Path: "first_name", Type: core.ExternalFieldTypeString},
+			{Path: "last_name", Type: core.ExternalFieldTypeString},
+			{Path: "phone", Type: core.ExternalFieldTypeString},
+			{Path: "state", Type: core.ExternalFieldTypeString},
+			{Path: "tags", Type: core.ExternalFieldTypeString},
+			{Path: "created_at", Type: core.ExternalFieldTypeDatetime},
+			{Path: "updated_at", Type: core.ExternalFieldTypeDatetime},
+		},
+	},
+	"order": {
+		ownerType: "ORDER",
+		fields: []core.ExternalField{
+			{Path: "id", Type: core.ExternalFieldTypeInteger, Required: true},
+			{Path: "name", Type: core.ExternalFieldTypeString},
+			{Path: "email", Type: core.ExternalFieldTypeString},
+			{Path: "currency", Type: core.ExternalFieldTypeString},
+			{Path: "total_price", Type: core.ExternalFieldTypeString, Format: "decimal"},
+			{Path: "financial_status", Type: core.ExternalFieldTypeString},
+			{Path: "fulfillment_status", Type: core.ExternalFieldTypeString},
+			{Path: "created_at", Type: core.ExternalFieldTypeDatetime},
+			{Path: "updated_at", Type: core.ExternalFieldTypeDatetime},
+			{Path: "line_items", Type: core.ExternalFieldTypeObject, Repeatable: true},
+			{Path: "line_items.sku", Type: core.ExternalFieldTypeString},
+			{Path: "line_items.quantity", Type: core.ExternalFieldTypeInteger},
+			{Path: "line_items.price", Type: core.ExternalFieldTypeString, Format: "decimal"},
+		},
+	},
+}
+
+var defaultSchemaObjects = []string{"product", "customer", "order"}
+
+type metafieldDefinition struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Type      struct {
+		Name string `json:"name"`
+	} `json:"type"`
+	Validations []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"validations"`
+}
+
+// DiscoverSchema returns the core product, customer and order fields along
+// with the shop's metafield definitions, under metafields.<namespace>.<key>.
+// ResourceID optionally names the shop domain.
+func (p *Provider) DiscoverSchema(ctx context.Context, req core.DiscoverSchemaRequest) (core.ExternalSchema, error) {
+	if p == nil {
+		return core.ExternalSchema{}, fmt.Errorf("providers/shopify: provider is nil")
+	}
+	shopDomain, err := p.resolveShopDomain(req.ResourceID, req.Credential, req.Metadata)
+	if err != nil {
+		return core.ExternalSchema{}, err
+	}
+	names := req.Objects
+	if len(names) == 0 {
+		names = defaultSchemaObjects
+	}
+	objects := make([]core.ExternalObjectSchema, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		object, ok := schemaObjects[name]
+		if !ok {
+			return core.ExternalSchema{}, fmt.Errorf("providers/shopify: unsupported schema object %q", name)
+		}
+		fields := make([]core.ExternalField, 0, len(object.fields))
+		fields = append(fields, object.fields...)
+		definitions, err := p.metafieldDefinitions(ctx, req.Credential, shopDomain, object.ownerType)
+		if err != nil {
+			return core.ExternalSchema{}, err
+		}
+		for _, definition := range definitions {
+			fields = append(fields, metafieldField(definition))
+		}
+		objects = append(objects, core.ExternalObjectSchema{
+			Name:       name,
+			PrimaryKey: []string{"id"},
+			Fields:     fields,
+			Metadata:   map[string]any{"owner_type": object.ownerType},
+		})
+	}
+	return core.NormalizeDiscoveredSchema(core.ExternalSchema{
+		ProviderID: ProviderID,
+		Scope:      req.Scope,
+		Name:       SchemaName,
+		Objects:    objects,
+		Metadata: map[string]any{
+			MetadataShopDomain:  shopDomain,
+			"admin_api_version": p.adminAPIVersion,
+		},
+	})
+}
+
+func (p *Provider) metafieldDefinitions(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+	ownerType string,
+) ([]metafieldDefinition, error) {
+	definitions := make([]metafieldDefinition, 0)
+	var after any
+	for page := 0; page < maxMetafieldDefinitionPages; page++ {
+		var data struct {
+			MetafieldDefinitions struct {
+				Nodes    []metafieldDefinition `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"metafieldDefinitions"`
+		}
+		if err := p.graphQL(ctx, credential, shopDomain, "metafieldDefinitions", metafieldDefinitionsQuery, map[string]any{
+			"ownerType": ownerType,
+			"first":     250,
+			"after":     after,
+		}, &data); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, data.MetafieldDefinitions.Nodes...)
+		pageInfo := data.MetafieldDefinitions.PageInfo
+		if !pageInfo.HasNextPage || strings.TrimSpace(pageInfo.EndCursor) == "" {
+			return definitions, nil
+		}
+		after = pageInfo.EndCursor
+	}
+	return nil, fmt.Errorf("providers/shopify: metafield definitions for %s exceed %d pages", ownerType, maxMetafieldDefinitionPages)
+}
+
+func metafieldField(definition metafieldDefinition) core.ExternalField {
+	nativeType := strings.TrimSpace(definition.Type.Name)
+	elementType, repeatable := strings.CutPrefix(nativeType, "list.")
+	fieldType, format := metafieldType(elementType)
+	var constraints map[string]any
+	for _, validation := range definition.Validations {
+		if constraints == nil {
+			constraints = map[string]any{}
+		}
+		constraints[validation.Name] = validation.Value
+	}
+	return core.ExternalField{
+		Path:        "metafields." + definition.Namespace + "." + definition.Key,
+		Type:        fieldType,
+		Repeatable:  repeatable,
+		Format:      format,
+		Constraints: constraints,
+		Metadata: map[string]any{
+			"label":       definition.Name,
+			"native_type": nativeType,
+		},
+	}
+}
+
+// metafieldType maps a metafield type onto the compiler vocabulary. Measured
+// values such as money and weight are objects; references are GID strings.
+func metafieldType(native string) (string, string) {
+	switch {
+	case native == "number_integer":
+		return core.ExternalFieldTypeInteger, ""
+	case native == "number_decimal":
+		return core.ExternalFieldTypeNumber, "decimal"
+	case native == "boolean":
+		return core.ExternalFieldTypeBoolean, ""
+	case native == "date":
+		return core.ExternalFieldTypeDatetime, "date"
+	case native == "date_time":
+		return core.ExternalFieldTypeDatetime, "date-time"
+	case native == "json", native == "money", native == "rating",
+		native == "weight", native == "volume", native == "dimension", native == "link":
+		return core.ExternalFieldTypeObject, native
+	case strings.HasSuffix(native, "_reference"):
+		return core.ExternalFieldTypeString, native
+	case native == "url", native == "color", native == "rich_text_field":
+		return core.ExternalFieldTypeString, native
+	default:
+		return core.ExternalFieldTypeString, ""
+	}
+}
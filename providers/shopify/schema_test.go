@@ -0,0 +1,89 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_DiscoverSchemaIncludesMetafieldDefinitions(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode graphql payload: %v", err)
+		}
+		if payload.OperationName != "metafieldDefinitions" || payload.Variables["ownerType"] != "PRODUCT" {
+			t.Fatalf("unexpected graphql request %+v", payload)
+		}
+		pages++
+		definitions := map[string]any{
+			"nodes": []map[string]any{{
+				"namespace": "custom", "key": "material", "name": "Material",
+				"type":        map[string]any{"name": "single_line_text_field"},
+				"validations": []map[string]any{{"name": "max", "value": "40"}},
+			}},
+			"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "cursor_1"},
+		}
+		if payload.Variables["after"] == "cursor_1" {
+			definitions = map[string]any{
+				"nodes": []map[string]any{{
+					"namespace": "custom", "key": "sizes", "name": "Sizes",
+					"type": map[string]any{"name": "list.number_integer"},
+				}},
+				"pageInfo": map[string]any{"hasNextPage": false},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"metafieldDefinitions": definitions}})
+	}))
+	defer server.Close()
+
+	providerRaw, err := New(Config{
+		ClientID:        "client",
+		ClientSecret:    "secret",
+		ShopDomain:      "acme.myshopify.com",
+		AdminAPIBaseURL: server.URL,
+		HTTPClient:      server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	schema, err := providerRaw.(*Provider).DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		Objects:    []string{"product"},
+		Credential: &core.ActiveCredential{AccessToken: "shpat_test"},
+	})
+	if err != nil {
+		t.Fatalf("discover schema: %v", err)
+	}
+	if pages != 2 || len(schema.Objects) != 1 || schema.Version == "" {
+		t.Fatalf("unexpected schema %+v after %d pages", schema, pages)
+	}
+	fields := map[string]core.ExternalField{}
+	for _, field := range schema.Objects[0].Fields {
+		fields[field.Path] = field
+	}
+	if fields["metafields.custom.material"].Constraints["max"] != "40" {
+		t.Fatalf("unexpected material metafield %+v", fields["metafields.custom.material"])
+	}
+	sizes := fields["metafields.custom.sizes"]
+	if sizes.Type != core.ExternalFieldTypeInteger || !sizes.Repeatable {
+		t.Fatalf("expected repeatable integer list metafield, got %+v", sizes)
+	}
+	if !fields["variants"].Repeatable || fields["variants.sku"].Type != core.ExternalFieldTypeString {
+		t.Fatalf("expected core product fields, got %+v", fields)
+	}
+
+	if _, err := providerRaw.(*Provider).DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		Objects:    []string{"inventory"},
+		Credential: &core.ActiveCredential{AccessToken: "shpat_test"},
+	}); err == nil {
+		t.Fatalf("expected unsupported object to fail")
+	}
+}
@@ -0,0 +1,668 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	DefaultAdminAPIVersion = "2025-07"
+
+	MetadataShopDomain      = "shop_domain"
+	MetadataWebhookTopic    = "webhook_topic"
+	MetadataWebhookFormat   = "webhook_format"
+	MetadataIncludeFields   = "include_fields"
+	MetadataWebhookFilter   = "webhook_filter"
+	MetadataDestinationKind = "destination_kind"
+
+	DestinationHTTP        = "http"
+	DestinationEventBridge = "eventbridge"
+	DestinationPubSub      = "pubsub"
+
+	maxListedWebhookSubscriptions = 250
+)
+
+const webhookSubscriptionFields = `id topic uri format includeFields filter`
+
+const webhookSubscriptionCreateMutation = `mutation webhookSubscriptionCreate($topic: WebhookSubscriptionTopic!, $webhookSubscription: WebhookSubscriptionInput!) {
+  webhookSubscriptionCreate(topic: $topic, webhookSubscription: $webhookSubscription) {
+    webhookSubscription { ` + webhookSubscriptionFields + ` }
+    userErrors { field message }
+  }
+}`
+
+const webhookSubscriptionUpdateMutation = `mutation webhookSubscriptionUpdate($id: ID!, $webhookSubscription: WebhookSubscriptionInput!) {
+  webhookSubscriptionUpdate(id: $id, webhookSubscription: $webhookSubscription) {
+    webhookSubscription { ` + webhookSubscriptionFields + ` }
+    userErrors { field message }
+  }
+}`
+
+const webhookSubscriptionDeleteMutation = `mutation webhookSubscriptionDelete($id: ID!) {
+  webhookSubscriptionDelete(id: $id) {
+    deletedWebhookSubscriptionId
+    userErrors { field message }
+  }
+}`
+
+const webhookSubscriptionsQuery = `query webhookSubscriptions($first: Int!, $after: String) {
+  webhookSubscriptions(first: $first, after: $after) {
+    nodes { ` + webhookSubscriptionFields + ` }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// topicGrants lists the access scope a topic family requires. Topics without
+// an entry are subscribed regardless of granted scopes.
+var topicGrants = map[string]string{
+	"ORDERS":             GrantReadOrders,
+	"ORDER_TRANSACTIONS": GrantReadOrders,
+	"FULFILLMENTS":       GrantReadOrders,
+	"REFUNDS":            GrantReadOrders,
+	"PRODUCTS":           GrantReadProducts,
+	"COLLECTIONS":        GrantReadProducts,
+	"INVENTORY_ITEMS":    GrantReadInventory,
+	"INVENTORY_LEVELS":   GrantReadInventory,
+}
+
+var _ core.SubscribableProvider = (*Provider)(nil)
+
+// WebhookSubscription is a webhook subscription registered on a shop.
+type WebhookSubscription struct {
+	ID            string   `json:"id"`
+	Topic         string   `json:"topic"`
+	URI           string   `json:"uri"`
+	Format        string   `json:"format"`
+	IncludeFields []string `json:"includeFields"`
+	Filter        string   `json:"filter"`
+}
+
+// ReconcileWebhooksRequest describes the desired topic set for one shop.
+type ReconcileWebhooksRequest struct {
+	Credential  *core.ActiveCredential
+	ShopDomain  string
+	CallbackURL string
+	Topics      []string
+	// Prune deletes subscriptions for topics outside the desired set.
+	Prune bool
+}
+
+type ReconcileWebhooksResult struct {
+	Created   []WebhookSubscription
+	Updated   []WebhookSubscription
+	Deleted   []WebhookSubscription
+	Unchanged []WebhookSubscription
+	// Skipped lists desired topics whose required scope is not granted.
+	Skipped []string
+}
+
+type graphQLUserError struct {
+	Field   []string `json:"field"`
+	Message string   `json:"message"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// Subscribe creates a webhook subscription for the topic in ResourceType.
+// CallbackURL may be an https endpoint, an EventBridge ARN or a
+// pubsub://project:topic destination.
+func (p *Provider) Subscribe(ctx context.Context, req core.SubscribeRequest) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/shopify: provider is nil")
+	}
+	topic := NormalizeWebhookTopic(req.ResourceType)
+	if topic == "" {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/shopify: webhook topic is required")
+	}
+	shopDomain, err := p.resolveShopDomain(req.ResourceID, req.Credential, req.Metadata)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	input, err := webhookSubscriptionInput(req.CallbackURL, req.Metadata)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	created, err := p.createWebhookSubscription(ctx, req.Credential, shopDomain, topic, input)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	return webhookSubscriptionResult(created, shopDomain), nil
+}
+
+// RenewSubscription re-points the subscription at its callback. Shopify
+// subscriptions do not expire, so renewal only repairs drift and recreates
+// subscriptions that were removed from the shop.
+func (p *Provider) RenewSubscription(
+	ctx context.Context,
+	req core.RenewSubscriptionRequest,
+) (core.SubscriptionResult, error) {
+	if p == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/shopify: provider is nil")
+	}
+	if req.Subscription == nil {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/shopify: subscription is required to renew")
+	}
+	existing := req.Subscription
+	metadata := map[string]any{}
+	for key, value := range existing.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	shopDomain, err := p.resolveShopDomain(existing.ResourceID, req.Credential, metadata)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	input, err := webhookSubscriptionInput(existing.CallbackURL, metadata)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+
+	remoteID := strings.TrimSpace(existing.RemoteSubscriptionID)
+	if remoteID != "" {
+		updated, found, err := p.updateWebhookSubscription(ctx, req.Credential, shopDomain, remoteID, input)
+		if err != nil {
+			return core.SubscriptionResult{}, err
+		}
+		if found {
+			return webhookSubscriptionResult(updated, shopDomain), nil
+		}
+	}
+	topic := NormalizeWebhookTopic(existing.ResourceType)
+	if topic == "" {
+		return core.SubscriptionResult{}, fmt.Errorf("providers/shopify: webhook topic is required")
+	}
+	created, err := p.createWebhookSubscription(ctx, req.Credential, shopDomain, topic, input)
+	if err != nil {
+		return core.SubscriptionResult{}, err
+	}
+	return webhookSubscriptionResult(created, shopDomain), nil
+}
+
+func (p *Provider) CancelSubscription(ctx context.Context, req core.CancelSubscriptionRequest) error {
+	if p == nil {
+		return fmt.Errorf("providers/shopify: provider is nil")
+	}
+	if req.Subscription == nil {
+		return fmt.Errorf("providers/shopify: subscription is required to cancel")
+	}
+	remoteID := strings.TrimSpace(req.Subscription.RemoteSubscriptionID)
+	if remoteID == "" {
+		return fmt.Errorf("providers/shopify: webhook subscription id is required to cancel")
+	}
+	shopDomain, err := p.resolveShopDomain(req.Subscription.ResourceID, req.Credential, req.Subscription.Metadata)
+	if err != nil {
+		return err
+	}
+	return p.deleteWebhookSubscription(ctx, req.Credential, shopDomain, remoteID)
+}
+
+// ListWebhookSubscriptions returns every webhook subscription on the shop.
+func (p *Provider) ListWebhookSubscriptions(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+) ([]WebhookSubscription, error) {
+	if p == nil {
+		return nil, fmt.Errorf("providers/shopify: provider is nil")
+	}
+	shopDomain, err := p.resolveShopDomain(shopDomain, credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := []WebhookSubscription{}
+	variables := map[string]any{"first": maxListedWebhookSubscriptions}
+	for {
+		var data struct {
+			WebhookSubscriptions struct {
+				Nodes    []WebhookSubscription `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"webhookSubscriptions"`
+		}
+		if err := p.graphQL(ctx, credential, shopDomain, "webhookSubscriptions", webhookSubscriptionsQuery, variables, &data); err != nil {
+			return nil, err
+		}
+		out = append(out, data.WebhookSubscriptions.Nodes...)
+		if !data.WebhookSubscriptions.PageInfo.HasNextPage || data.WebhookSubscriptions.PageInfo.EndCursor == "" {
+			return out, nil
+		}
+		variables = map[string]any{
+			"first": maxListedWebhookSubscriptions,
+			"after": data.WebhookSubscriptions.PageInfo.EndCursor,
+		}
+	}
+}
+
+// ReconcileWebhooks ensures the shop has exactly one subscription per desired
+// topic pointing at CallbackURL. WebhookReconciler runs it after install and
+// after scope changes; topics whose scope is not granted are skipped and,
+// with Prune, removed.
+func (p *Provider) ReconcileWebhooks(
+	ctx context.Context,
+	req ReconcileWebhooksRequest,
+) (ReconcileWebhooksResult, error) {
+	if p == nil {
+		return ReconcileWebhooksResult{}, fmt.Errorf("providers/shopify: provider is nil")
+	}
+	shopDomain, err := p.resolveShopDomain(req.ShopDomain, req.Credential, nil)
+	if err != nil {
+		return ReconcileWebhooksResult{}, err
+	}
+	input, err := webhookSubscriptionInput(req.CallbackURL, nil)
+	if err != nil {
+		return ReconcileWebhooksResult{}, err
+	}
+	uri, _ := input["uri"].(string)
+
+	result := ReconcileWebhooksResult{}
+	var granted []string
+	if req.Credential != nil {
+		granted = req.Credential.GrantedScopes
+	}
+	desired := map[string]struct{}{}
+	for _, topic := range req.Topics {
+		normalized := NormalizeWebhookTopic(topic)
+		if normalized == "" {
+			continue
+		}
+		if !topicGranted(normalized, granted) {
+			result.Skipped = append(result.Skipped, normalized)
+			continue
+		}
+		desired[normalized] = struct{}{}
+	}
+	sort.Strings(result.Skipped)
+
+	existing, err := p.ListWebhookSubscriptions(ctx, req.Credential, shopDomain)
+	if err != nil {
+		return result, err
+	}
+	matched := map[string]struct{}{}
+	for _, subscription := range existing {
+		topic := NormalizeWebhookTopic(subscription.Topic)
+		if _, ok := desired[topic]; !ok {
+			if req.Prune {
+				if err := p.deleteWebhookSubscription(ctx, req.Credential, shopDomain, subscription.ID); err != nil {
+					return result, err
+				}
+				result.Deleted = append(result.Deleted, subscription)
+			}
+			continue
+		}
+		if _, seen := matched[topic]; seen {
+			if err := p.deleteWebhookSubscription(ctx, req.Credential, shopDomain, subscription.ID); err != nil {
+				return result, err
+			}
+			result.Deleted = append(result.Deleted, subscription)
+			continue
+		}
+		matched[topic] = struct{}{}
+		if strings.TrimSpace(subscription.URI) == uri {
+			result.Unchanged = append(result.Unchanged, subscription)
+			continue
+		}
+		updated, found, err := p.updateWebhookSubscription(ctx, req.Credential, shopDomain, subscription.ID, input)
+		if err != nil {
+			return result, err
+		}
+		if !found {
+			delete(matched, topic)
+			continue
+		}
+		result.Updated = append(result.Updated, updated)
+	}
+
+	missing := make([]string, 0, len(desired))
+	for topic := range desired {
+		if _, ok := matched[topic]; !ok {
+			missing = append(missing, topic)
+		}
+	}
+	sort.Strings(missing)
+	for _, topic := range missing {
+		created, err := p.createWebhookSubscription(ctx, req.Credential, shopDomain, topic, input)
+		if err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, created)
+	}
+	return result, nil
+}
+
+// NormalizeWebhookTopic converts REST-style topics ("orders/create") to the
+// GraphQL enum form ("ORDERS_CREATE").
+func NormalizeWebhookTopic(topic string) string {
+	normalized := strings.TrimSpace(strings.ToUpper(topic))
+	normalized = strings.NewReplacer("/", "_", ".", "_", "-", "_", " ", "_").Replace(normalized)
+	return strings.Trim(normalized, "_")
+}
+
+// WebhookDestinationKind classifies a callback as an https endpoint, an
+// EventBridge partner event source ARN or a Google Pub/Sub topic.
+func WebhookDestinationKind(callback string) (string, error) {
+	callback = strings.TrimSpace(callback)
+	switch {
+	case callback == "":
+		return "", fmt.Errorf("providers/shopify: callback url is required")
+	case strings.HasPrefix(callback, "arn:aws:events:"):
+		return DestinationEventBridge, nil
+	case strings.HasPrefix(callback, "pubsub://"):
+		project, topic, ok := strings.Cut(strings.TrimPrefix(callback, "pubsub://"), ":")
+		if !ok || strings.TrimSpace(project) == "" || strings.TrimSpace(topic) == "" {
+			return "", fmt.Errorf("providers/shopify: pubsub destination must be pubsub://project:topic")
+		}
+		return DestinationPubSub, nil
+	}
+	parsed, err := url.Parse(callback)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("providers/shopify: callback url must be https, an eventbridge arn or a pubsub destination")
+	}
+	return DestinationHTTP, nil
+}
+
+func (p *Provider) createWebhookSubscription(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+	topic string,
+	input map[string]any,
+) (WebhookSubscription, error) {
+	var data struct {
+		WebhookSubscriptionCreate struct {
+			WebhookSubscription *WebhookSubscription `json:"webhookSubscription"`
+			UserErrors          []graphQLUserError   `json:"userErrors"`
+		} `json:"webhookSubscriptionCreate"`
+	}
+	if err := p.graphQL(ctx, credential, shopDomain, "webhookSubscriptionCreate", webhookSubscriptionCreateMutation, map[string]any{
+		"topic":               topic,
+		"webhookSubscription": input,
+	}, &data); err != nil {
+		return WebhookSubscription{}, err
+	}
+	payload := data.WebhookSubscriptionCreate
+	if err := userErrorsToError("webhookSubscriptionCreate", payload.UserErrors); err != nil {
+		return WebhookSubscription{}, err
+	}
+	if payload.WebhookSubscription == nil || strings.TrimSpace(payload.WebhookSubscription.ID) == "" {
+		return WebhookSubscription{}, fmt.Errorf("providers/shopify: webhookSubscriptionCreate returned no subscription")
+	}
+	return *payload.WebhookSubscription, nil
+}
+
+func (p *Provider) updateWebhookSubscription(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+	id string,
+	input map[string]any,
+) (WebhookSubscription, bool, error) {
+	var data struct {
+		WebhookSubscriptionUpdate struct {
+			WebhookSubscription *WebhookSubscription `json:"webhookSubscription"`
+			UserErrors          []graphQLUserError   `json:"userErrors"`
+		} `json:"webhookSubscriptionUpdate"`
+	}
+	if err := p.graphQL(ctx, credential, shopDomain, "webhookSubscriptionUpdate", webhookSubscriptionUpdateMutation, map[string]any{
+		"id":                  id,
+		"webhookSubscription": input,
+	}, &data); err != nil {
+		return WebhookSubscription{}, false, err
+	}
+	payload := data.WebhookSubscriptionUpdate
+	if userErrorsNotFound(payload.UserErrors) {
+		return WebhookSubscription{}, false, nil
+	}
+	if err := userErrorsToError("webhookSubscriptionUpdate", payload.UserErrors); err != nil {
+		return WebhookSubscription{}, false, err
+	}
+	if payload.WebhookSubscription == nil {
+		return WebhookSubscription{}, false, nil
+	}
+	return *payload.WebhookSubscription, true, nil
+}
+
+func (p *Provider) deleteWebhookSubscription(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+	id string,
+) error {
+	var data struct {
+		WebhookSubscriptionDelete struct {
+			DeletedWebhookSubscriptionID string             `json:"deletedWebhookSubscriptionId"`
+			UserErrors                   []graphQLUserError `json:"userErrors"`
+		} `json:"webhookSubscriptionDelete"`
+	}
+	if err := p.graphQL(ctx, credential, shopDomain, "webhookSubscriptionDelete", webhookSubscriptionDeleteMutation, map[string]any{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+	if userErrorsNotFound(data.WebhookSubscriptionDelete.UserErrors) {
+		return nil
+	}
+	return userErrorsToError("webhookSubscriptionDelete", data.WebhookSubscriptionDelete.UserErrors)
+}
+
+func (p *Provider) graphQL(
+	ctx context.Context,
+	credential *core.ActiveCredential,
+	shopDomain string,
+	operation string,
+	query string,
+	variables map[string]any,
+	out any,
+) error {
+	if credential == nil || strings.TrimSpace(credential.AccessToken) == "" {
+		return fmt.Errorf("providers/shopify: access token is required")
+	}
+	endpoint := (&url.URL{
+		Scheme: "https",
+		Host:   shopDomain,
+		Path:   "/admin/api/" + p.adminAPIVersion + "/graphql.json",
+	}).String()
+	if p.adminAPIBaseURL != "" {
+		endpoint = p.adminAPIBaseURL + "/admin/api/" + p.adminAPIVersion + "/graphql.json"
+	}
+	response, err := p.graphQLTransport.Do(ctx, core.TransportRequest{
+		URL: endpoint,
+		Headers: map[string]string{
+			"X-Shopify-Access-Token": strings.TrimSpace(credential.AccessToken),
+			"Accept":                 "application/json",
+		},
+		Metadata: map[string]any{
+			"query":          query,
+			"operation_name": operation,
+			"variables":      variables,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("providers/shopify: %s: %w", operation, err)
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"providers/shopify: %s returned status %d: %s",
+			operation,
+			response.StatusCode,
+			strings.TrimSpace(string(response.Body)),
+		)
+	}
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.Unmarshal(response.Body, &envelope); err != nil {
+		return fmt.Errorf("providers/shopify: decode %s response: %w", operation, err)
+	}
+	if len(envelope.Errors) > 0 {
+		messages := make([]string, 0, len(envelope.Errors))
+		for _, item := range envelope.Errors {
+			messages = append(messages, strings.TrimSpace(item.Message))
+		}
+		return fmt.Errorf("providers/shopify: %s failed: %s", operation, strings.Join(messages, "; "))
+	}
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("providers/shopify: decode %s data: %w", operation, err)
+	}
+	return nil
+}
+
+func (p *Provider) resolveShopDomain(
+	candidate string,
+	credential *core.ActiveCredential,
+	metadata map[string]any,
+) (string, error) {
+	values := []string{candidate, readMetadataString(metadata, MetadataShopDomain)}
+	if credential != nil {
+		values = append(values, readMetadataString(credential.Metadata, MetadataShopDomain))
+	}
+	values = append(values, p.shopDomain)
+	domain := firstNonEmpty(values...)
+	if domain == "" {
+		return "", fmt.Errorf("providers/shopify: shop_domain is required for admin api calls")
+	}
+	return normalizeShopDomain(domain)
+}
+
+func webhookSubscriptionInput(callback string, metadata map[string]any) (map[string]any, error) {
+	callback = strings.TrimSpace(callback)
+	if _, err := WebhookDestinationKind(callback); err != nil {
+		return nil, err
+	}
+	input := map[string]any{"uri": callback}
+	format := strings.ToUpper(readMetadataString(metadata, MetadataWebhookFormat))
+	if format == "" {
+		format = "JSON"
+	}
+	input["format"] = format
+	if fields := readMetadataStrings(metadata, MetadataIncludeFields); len(fields) > 0 {
+		input["includeFields"] = fields
+	}
+	if filter := readMetadataString(metadata, MetadataWebhookFilter); filter != "" {
+		input["filter"] = filter
+	}
+	return input, nil
+}
+
+func webhookSubscriptionResult(subscription WebhookSubscription, shopDomain string) core.SubscriptionResult {
+	kind, _ := WebhookDestinationKind(subscription.URI)
+	metadata := map[string]any{
+		MetadataShopDomain:   shopDomain,
+		MetadataWebhookTopic: NormalizeWebhookTopic(subscription.Topic),
+	}
+	if kind != "" {
+		metadata[MetadataDestinationKind] = kind
+	}
+	if format := strings.TrimSpace(subscription.Format); format != "" {
+		metadata[MetadataWebhookFormat] = format
+	}
+	if len(subscription.IncludeFields) > 0 {
+		metadata[MetadataIncludeFields] = append([]string(nil), subscription.IncludeFields...)
+	}
+	if filter := strings.TrimSpace(subscription.Filter); filter != "" {
+		metadata[MetadataWebhookFilter] = filter
+	}
+	id := strings.TrimSpace(subscription.ID)
+	return core.SubscriptionResult{
+		ChannelID:            id,
+		RemoteSubscriptionID: id,
+		Metadata:             metadata,
+	}
+}
+
+func topicGranted(topic string, granted []string) bool {
+	required := ""
+	matchedPrefix := ""
+	for prefix, grant := range topicGrants {
+		if strings.HasPrefix(topic, prefix+"_") && len(prefix) > len(matchedPrefix) {
+			matchedPrefix = prefix
+			required = grant
+		}
+	}
+	// Without a grant snapshot the desired set is taken as-is.
+	if required == "" || len(granted) == 0 {
+		return true
+	}
+	for _, grant := range normalizeCanonicalGrants(granted) {
+		if grant == required {
+			return true
+		}
+	}
+	return false
+}
+
+func userErrorsToError(operation string, userErrors []graphQLUserError) error {
+	if len(userErrors) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(userErrors))
+	for _, item := range userErrors {
+		message := strings.TrimSpace(item.Message)
+		if len(item.Field) > 0 {
+			message = strings.Join(item.Field, ".") + ": " + message
+		}
+		messages = append(messages, message)
+	}
+	return fmt.Errorf("providers/shopify: %s: %s", operation, strings.Join(messages, "; "))
+}
+
+func userErrorsNotFound(userErrors []graphQLUserError) bool {
+	for _, item := range userErrors {
+		message := strings.ToLower(item.Message)
+		if strings.Contains(message, "does not exist") || strings.Contains(message, "not found") {
+			return true
+		}
+	}
+	return false
+}
+
+func readMetadataString(metadata map[string]any, key string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	value, ok := metadata[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+func readMetadataStrings(metadata map[string]any, key string) []string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	var values []string
+	switch typed := metadata[key].(type) {
+	case []string:
+		values = typed
+	case []any:
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
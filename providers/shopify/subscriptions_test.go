@@ -0,0 +1,256 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_SubscribeRenewCancelWebhookSubscriptions(t *testing.T) {
+	shop := newFakeShopifyWebhookAdmin(t)
+	defer shop.Close()
+	provider := newSubscriptionTestProvider(t, shop)
+	credential := &core.ActiveCredential{
+		AccessToken: "shpat_test",
+		Metadata:    map[string]any{MetadataShopDomain: "acme.myshopify.com"},
+	}
+
+	result, err := provider.Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: "orders/create",
+		CallbackURL:  "https://app.example/webhooks/shopify",
+		Metadata:     map[string]any{MetadataIncludeFields: []any{"id", "email"}},
+		Credential:   credential,
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if result.RemoteSubscriptionID == "" || result.ChannelID != result.RemoteSubscriptionID {
+		t.Fatalf("expected webhook gid as ids, got %+v", result)
+	}
+	if result.Metadata[MetadataWebhookTopic] != "ORDERS_CREATE" || result.Metadata[MetadataDestinationKind] != DestinationHTTP {
+		t.Fatalf("unexpected subscription metadata %+v", result.Metadata)
+	}
+	if got := shop.subscriptions[result.RemoteSubscriptionID].IncludeFields; len(got) != 2 {
+		t.Fatalf("expected include fields forwarded, got %v", got)
+	}
+
+	shop.mu.Lock()
+	delete(shop.subscriptions, result.RemoteSubscriptionID)
+	shop.mu.Unlock()
+	renewed, err := provider.RenewSubscription(context.Background(), core.RenewSubscriptionRequest{
+		Subscription: &core.Subscription{
+			ResourceType:         "orders/create",
+			RemoteSubscriptionID: result.RemoteSubscriptionID,
+			CallbackURL:          "https://app.example/webhooks/shopify",
+			Metadata:             result.Metadata,
+		},
+		Credential: credential,
+	})
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if renewed.RemoteSubscriptionID == result.RemoteSubscriptionID {
+		t.Fatalf("expected missing subscription to be recreated on renew")
+	}
+
+	if err := provider.CancelSubscription(context.Background(), core.CancelSubscriptionRequest{
+		Subscription: &core.Subscription{RemoteSubscriptionID: renewed.RemoteSubscriptionID, Metadata: renewed.Metadata},
+		Credential:   credential,
+	}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if len(shop.subscriptions) != 0 {
+		t.Fatalf("expected subscription deleted, remaining %v", shop.subscriptions)
+	}
+}
+
+func TestProvider_SubscribeSupportsEventBridgeAndPubSubDestinations(t *testing.T) {
+	shop := newFakeShopifyWebhookAdmin(t)
+	defer shop.Close()
+	provider := newSubscriptionTestProvider(t, shop)
+	credential := &core.ActiveCredential{AccessToken: "shpat_test"}
+
+	for destination, kind := range map[string]string{
+		"arn:aws:events:us-east-1::event-source/aws.partner/shopify.com/123/acme": DestinationEventBridge,
+		"pubsub://acme-project:shopify-orders":                                    DestinationPubSub,
+	} {
+		result, err := provider.Subscribe(context.Background(), core.SubscribeRequest{
+			ResourceType: "PRODUCTS_UPDATE",
+			ResourceID:   "acme",
+			CallbackURL:  destination,
+			Credential:   credential,
+		})
+		if err != nil {
+			t.Fatalf("subscribe %s: %v", destination, err)
+		}
+		if result.Metadata[MetadataDestinationKind] != kind {
+			t.Fatalf("expected %s destination, got %v", kind, result.Metadata[MetadataDestinationKind])
+		}
+	}
+	if _, err := provider.Subscribe(context.Background(), core.SubscribeRequest{
+		ResourceType: "PRODUCTS_UPDATE",
+		ResourceID:   "acme",
+		CallbackURL:  "pubsub://missing-topic",
+		Credential:   credential,
+	}); err == nil {
+		t.Fatalf("expected malformed pubsub destination to be rejected")
+	}
+}
+
+func TestProvider_ReconcileWebhooksEnsuresDesiredTopics(t *testing.T) {
+	shop := newFakeShopifyWebhookAdmin(t)
+	defer shop.Close()
+	shop.seed("ORDERS_CREATE", "https://old.example/webhooks/shopify")
+	shop.seed("ORDERS_CREATE", "https://app.example/webhooks/shopify")
+	shop.seed("APP_UNINSTALLED", "https://app.example/webhooks/shopify")
+	shop.seed("CUSTOMERS_CREATE", "https://app.example/webhooks/shopify")
+	provider := newSubscriptionTestProvider(t, shop)
+
+	result, err := provider.ReconcileWebhooks(context.Background(), ReconcileWebhooksRequest{
+		Credential: &core.ActiveCredential{
+			AccessToken:   "shpat_test",
+			GrantedScopes: []string{ScopeReadOrders},
+		},
+		ShopDomain:  "acme.myshopify.com",
+		CallbackURL: "https://app.example/webhooks/shopify",
+		Topics:      []string{"orders/create", "app/uninstalled", "products/update", "orders/paid"},
+		Prune:       true,
+	})
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "PRODUCTS_UPDATE" {
+		t.Fatalf("expected products topic skipped without read_products, got %v", result.Skipped)
+	}
+	if len(result.Created) != 1 || result.Created[0].Topic != "ORDERS_PAID" {
+		t.Fatalf("expected orders/paid created, got %+v", result.Created)
+	}
+	if len(result.Deleted) != 2 {
+		t.Fatalf("expected duplicate and undesired subscriptions deleted, got %+v", result.Deleted)
+	}
+
+	topics := map[string]int{}
+	for _, subscription := range shop.subscriptions {
+		topics[subscription.Topic]++
+		if subscription.URI != "https://app.example/webhooks/shopify" {
+			t.Fatalf("expected reconciled uri, got %+v", subscription)
+		}
+	}
+	if len(topics) != 3 || topics["ORDERS_CREATE"] != 1 || topics["APP_UNINSTALLED"] != 1 || topics["ORDERS_PAID"] != 1 {
+		t.Fatalf("unexpected reconciled topics %v", topics)
+	}
+}
+
+func newSubscriptionTestProvider(t *testing.T, shop *fakeShopifyWebhookAdmin) *Provider {
+	t.Helper()
+	provider, err := New(Config{
+		ClientID:        "client",
+		ClientSecret:    "secret",
+		ShopDomain:      "acme.myshopify.com",
+		AdminAPIBaseURL: shop.URL,
+		HTTPClient:      shop.Client(),
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	return provider.(*Provider)
+}
+
+type fakeShopifyWebhookAdmin struct {
+	*httptest.Server
+	mu            sync.Mutex
+	next          int
+	subscriptions map[string]WebhookSubscription
+}
+
+func newFakeShopifyWebhookAdmin(t *testing.T) *fakeShopifyWebhookAdmin {
+	t.Helper()
+	admin := &fakeShopifyWebhookAdmin{subscriptions: map[string]WebhookSubscription{}}
+	admin.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/"+DefaultAdminAPIVersion+"/graphql.json" {
+			t.Fatalf("unexpected graphql path %q", r.URL.Path)
+		}
+		if r.Header.Get("X-Shopify-Access-Token") != "shpat_test" {
+			t.Fatalf("expected shopify access token header")
+		}
+		var payload struct {
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode graphql payload: %v", err)
+		}
+		data := admin.handle(payload.OperationName, payload.Variables)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	return admin
+}
+
+func (a *fakeShopifyWebhookAdmin) seed(topic string, uri string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.next++
+	id := fmt.Sprintf("gid://shopify/WebhookSubscription/%d", a.next)
+	a.subscriptions[id] = WebhookSubscription{ID: id, Topic: topic, URI: uri, Format: "JSON"}
+}
+
+func (a *fakeShopifyWebhookAdmin) handle(operation string, variables map[string]any) map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	notFound := []map[string]any{{"field": []string{"id"}, "message": "Webhook subscription does not exist"}}
+	switch operation {
+	case "webhookSubscriptionCreate":
+		input, _ := variables["webhookSubscription"].(map[string]any)
+		a.next++
+		subscription := WebhookSubscription{
+			ID:     fmt.Sprintf("gid://shopify/WebhookSubscription/%d", a.next),
+			Topic:  fmt.Sprint(variables["topic"]),
+			URI:    fmt.Sprint(input["uri"]),
+			Format: fmt.Sprint(input["format"]),
+		}
+		if fields, ok := input["includeFields"].([]any); ok {
+			for _, field := range fields {
+				subscription.IncludeFields = append(subscription.IncludeFields, fmt.Sprint(field))
+			}
+		}
+		a.subscriptions[subscription.ID] = subscription
+		return map[string]any{operation: map[string]any{"webhookSubscription": subscription, "userErrors": []any{}}}
+	case "webhookSubscriptionUpdate":
+		id := fmt.Sprint(variables["id"])
+		subscription, ok := a.subscriptions[id]
+		if !ok {
+			return map[string]any{operation: map[string]any{"webhookSubscription": nil, "userErrors": notFound}}
+		}
+		input, _ := variables["webhookSubscription"].(map[string]any)
+		subscription.URI = fmt.Sprint(input["uri"])
+		a.subscriptions[id] = subscription
+		return map[string]any{operation: map[string]any{"webhookSubscription": subscription, "userErrors": []any{}}}
+	case "webhookSubscriptionDelete":
+		id := fmt.Sprint(variables["id"])
+		if _, ok := a.subscriptions[id]; !ok {
+			return map[string]any{operation: map[string]any{"userErrors": notFound}}
+		}
+		delete(a.subscriptions, id)
+		return map[string]any{operation: map[string]any{"deletedWebhookSubscriptionId": id, "userErrors": []any{}}}
+	case "webhookSubscriptions":
+		nodes := make([]WebhookSubscription, 0, len(a.subscriptions))
+		for i := 1; i <= a.next; i++ {
+			if subscription, ok := a.subscriptions[fmt.Sprintf("gid://shopify/WebhookSubscription/%d", i)]; ok {
+				nodes = append(nodes, subscription)
+			}
+		}
+		return map[string]any{operation: map[string]any{
+			"nodes":    nodes,
+			"pageInfo": map[string]any{"hasNextPage": false, "endCursor": ""},
+		}}
+	default:
+		panic("unexpected operation " + strings.TrimSpace(operation))
+	}
+}
@@ -0,0 +1,183 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/installations"
+)
+
+// CredentialResolver resolves the credential of a connection before admin
+// API calls; *core.Service implements it.
+type CredentialResolver interface {
+	EnsureCredentialFresh(ctx context.Context, req core.EnsureCredentialFreshRequest) (core.EnsureCredentialFreshResult, error)
+}
+
+// WebhookSubscriptionStore records the webhook subscriptions a reconcile
+// leaves on the shop so subscription cascades can see them.
+type WebhookSubscriptionStore interface {
+	Upsert(ctx context.Context, in core.UpsertSubscriptionInput) (core.Subscription, error)
+	ListByConnection(ctx context.Context, connectionID string) ([]core.Subscription, error)
+	UpdateState(ctx context.Context, id string, status core.SubscriptionStatus, reason string) error
+}
+
+// WebhookReconcilerConfig describes the webhooks every installed shop should
+// have. Topics and Prune have the ReconcileWebhooksRequest meaning.
+type WebhookReconcilerConfig struct {
+	Provider      *Provider
+	Credentials   CredentialResolver
+	Subscriptions WebhookSubscriptionStore
+	CallbackURL   string
+	Topics        []string
+	Prune         bool
+}
+
+// WebhookReconciler is an installation hook that reconciles the shop's
+// webhook subscriptions after install and scope changes and records them as
+// core subscriptions of each linked connection.
+type WebhookReconciler struct {
+	provider      *Provider
+	credentials   CredentialResolver
+	subscriptions WebhookSubscriptionStore
+	callbackURL   string
+	topics        []string
+	prune         bool
+}
+
+var _ installations.Hook = (*WebhookReconciler)(nil)
+
+func NewWebhookReconciler(cfg WebhookReconcilerConfig) (*WebhookReconciler, error) {
+	if cfg.Provider == nil {
+		return nil, fmt.Errorf("providers/shopify: provider is required")
+	}
+	if cfg.Credentials == nil {
+		return nil, fmt.Errorf("providers/shopify: credential resolver is required")
+	}
+	if cfg.Subscriptions == nil {
+		return nil, fmt.Errorf("providers/shopify: subscription store is required")
+	}
+	if _, err := WebhookDestinationKind(cfg.CallbackURL); err != nil {
+		return nil, err
+	}
+	return &WebhookReconciler{
+		provider:      cfg.Provider,
+		credentials:   cfg.Credentials,
+		subscriptions: cfg.Subscriptions,
+		callbackURL:   strings.TrimSpace(cfg.CallbackURL),
+		topics:        append([]string(nil), cfg.Topics...),
+		prune:         cfg.Prune,
+	}, nil
+}
+
+// AfterInstallationChange reconciles the webhooks of every connected shop of
+// a Shopify installation. The installation's granted scopes, when the signal
+// carries them, decide which topics are allowed, since the stored credential
+// still lists the scopes of its original grant.
+func (r *WebhookReconciler) AfterInstallationChange(ctx context.Context, req installations.HookRequest) error {
+	if r == nil || req.Installation.ProviderID != ProviderID {
+		return nil
+	}
+	for _, connection := range req.Connections {
+		if connection.Status == core.ConnectionStatusDisconnected {
+			continue
+		}
+		fresh, err := r.credentials.EnsureCredentialFresh(ctx, core.EnsureCredentialFreshRequest{
+			ProviderID:   ProviderID,
+			ConnectionID: connection.ID,
+		})
+		if err != nil {
+			return err
+		}
+		credential := fresh.Credential
+		if req.Event.Scopes != nil {
+			credential.GrantedScopes = append([]string(nil), req.Event.Scopes...)
+		}
+		if err := r.reconcile(ctx, connection, &credential, req.Event.ExternalInstallationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *WebhookReconciler) reconcile(
+	ctx context.Context,
+	connection core.Connection,
+	credential *core.ActiveCredential,
+	shopDomain string,
+) error {
+	shopDomain, err := r.provider.resolveShopDomain(shopDomain, credential, nil)
+	if err != nil {
+		return err
+	}
+	result, err := r.provider.ReconcileWebhooks(ctx, ReconcileWebhooksRequest{
+		Credential:  credential,
+		ShopDomain:  shopDomain,
+		CallbackURL: r.callbackURL,
+		Topics:      r.topics,
+		Prune:       r.prune,
+	})
+	// Record what was done before the error so the local rows match the
+	// shop as closely as possible.
+	if recordErr := r.record(ctx, connection, shopDomain, result); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return err
+}
+
+func (r *WebhookReconciler) record(
+	ctx context.Context,
+	connection core.Connection,
+	shopDomain string,
+	result ReconcileWebhooksResult,
+) error {
+	active := make([]WebhookSubscription, 0, len(result.Created)+len(result.Updated)+len(result.Unchanged))
+	active = append(active, result.Created...)
+	active = append(active, result.Updated...)
+	active = append(active, result.Unchanged...)
+	for _, subscription := range active {
+		remote := webhookSubscriptionResult(subscription, shopDomain)
+		if remote.ChannelID == "" {
+			continue
+		}
+		_, err := r.subscriptions.Upsert(ctx, core.UpsertSubscriptionInput{
+			ConnectionID:         connection.ID,
+			ProviderID:           ProviderID,
+			ResourceType:         NormalizeWebhookTopic(subscription.Topic),
+			ResourceID:           shopDomain,
+			ChannelID:            remote.ChannelID,
+			RemoteSubscriptionID: remote.RemoteSubscriptionID,
+			CallbackURL:          r.callbackURL,
+			Status:               core.SubscriptionStatusActive,
+			Metadata:             remote.Metadata,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(result.Deleted) == 0 {
+		return nil
+	}
+
+	deleted := map[string]struct{}{}
+	for _, subscription := range result.Deleted {
+		deleted[strings.TrimSpace(subscription.ID)] = struct{}{}
+	}
+	existing, err := r.subscriptions.ListByConnection(ctx, connection.ID)
+	if err != nil {
+		return err
+	}
+	for _, subscription := range existing {
+		if subscription.Status == core.SubscriptionStatusCancelled {
+			continue
+		}
+		if _, ok := deleted[subscription.RemoteSubscriptionID]; !ok {
+			continue
+		}
+		if err := r.subscriptions.UpdateState(ctx, subscription.ID, core.SubscriptionStatusCancelled, "webhook subscription removed by reconcile"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/installations"
+)
+
+func TestWebhookReconciler_RecordsSubscriptionsAfterInstallAndScopeChanges(t *testing.T) {
+	ctx := context.Background()
+	shop := newFakeShopifyWebhookAdmin(t)
+	defer shop.Close()
+	shop.seed("ORDERS_CREATE", "https://old.example/webhooks/shopify")
+	store := &memoryWebhookSubscriptionStore{byID: map[string]core.Subscription{}}
+	reconciler, err := NewWebhookReconciler(WebhookReconcilerConfig{
+		Provider:      newSubscriptionTestProvider(t, shop),
+		Credentials:   staticCredentialResolver{},
+		Subscriptions: store,
+		CallbackURL:   "https://app.example/webhooks/shopify",
+		Topics:        []string{"orders/create", "products/update", "app/uninstalled"},
+		Prune:         true,
+	})
+	if err != nil {
+		t.Fatalf("new reconciler: %v", err)
+	}
+	connection := core.Connection{ID: "conn_1", ProviderID: ProviderID, Status: core.ConnectionStatusActive}
+	installation := core.Installation{ID: "inst_1", ProviderID: ProviderID}
+
+	if err := reconciler.AfterInstallationChange(ctx, installations.HookRequest{
+		Event: installations.Event{
+			ProviderID:             ProviderID,
+			ExternalInstallationID: "acme.myshopify.com",
+			Signal:                 installations.SignalInstalled,
+			Scopes:                 []string{ScopeReadOrders, ScopeReadProducts},
+		},
+		Installation: installation,
+		Connections:  []core.Connection{connection},
+	}); err != nil {
+		t.Fatalf("reconcile after install: %v", err)
+	}
+	if got := store.topics(connection.ID, core.SubscriptionStatusActive); got != "APP_UNINSTALLED,ORDERS_CREATE,PRODUCTS_UPDATE" {
+		t.Fatalf("expected every granted topic recorded as active, got %s", got)
+	}
+	for _, subscription := range store.byID {
+		if subscription.ResourceID != "acme.myshopify.com" || subscription.CallbackURL != "https://app.example/webhooks/shopify" ||
+			subscription.RemoteSubscriptionID == "" || subscription.ChannelID != subscription.RemoteSubscriptionID {
+			t.Fatalf("unexpected recorded subscription %+v", subscription)
+		}
+	}
+
+	if err := reconciler.AfterInstallationChange(ctx, installations.HookRequest{
+		Event: installations.Event{
+			ProviderID:             ProviderID,
+			ExternalInstallationID: "acme.myshopify.com",
+			Signal:                 installations.SignalScopesChanged,
+			Scopes:                 []string{ScopeReadOrders},
+		},
+		Installation: installation,
+		Connections:  []core.Connection{connection},
+	}); err != nil {
+		t.Fatalf("reconcile after scope change: %v", err)
+	}
+	if got := store.topics(connection.ID, core.SubscriptionStatusActive); got != "APP_UNINSTALLED,ORDERS_CREATE" {
+		t.Fatalf("expected the products topic to leave the active set, got %s", got)
+	}
+	if got := store.topics(connection.ID, core.SubscriptionStatusCancelled); got != "PRODUCTS_UPDATE" {
+		t.Fatalf("expected the pruned products subscription cancelled locally, got %s", got)
+	}
+	if len(shop.subscriptions) != 2 {
+		t.Fatalf("expected the shop to keep two subscriptions, got %+v", shop.subscriptions)
+	}
+
+	// Other providers' installations are left alone.
+	before := len(store.byID)
+	if err := reconciler.AfterInstallationChange(ctx, installations.HookRequest{
+		Installation: core.Installation{ID: "inst_2", ProviderID: "github"},
+		Connections:  []core.Connection{{ID: "conn_2", ProviderID: "github", Status: core.ConnectionStatusActive}},
+	}); err != nil || len(store.byID) != before {
+		t.Fatalf("expected non-shopify installations to be ignored, got %d subscriptions %v", len(store.byID), err)
+	}
+}
+
+type staticCredentialResolver struct{}
+
+func (staticCredentialResolver) EnsureCredentialFresh(
+	_ context.Context,
+	req core.EnsureCredentialFreshRequest,
+) (core.EnsureCredentialFreshResult, error) {
+	return core.EnsureCredentialFreshResult{Credential: core.ActiveCredential{
+		ConnectionID:  req.ConnectionID,
+		AccessToken:   "shpat_test",
+		GrantedScopes: []string{ScopeReadOrders},
+	}}, nil
+}
+
+type memoryWebhookSubscriptionStore struct {
+	mu   sync.Mutex
+	next int
+	byID map[string]core.Subscription
+}
+
+func (s *memoryWebhookSubscriptionStore) Upsert(_ context.Context, in core.UpsertSubscriptionInput) (core.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, existing := range s.byID {
+		if existing.ProviderID == in.ProviderID && existing.ChannelID == in.ChannelID {
+			existing.ConnectionID = in.ConnectionID
+			existing.ResourceType = in.ResourceType
+			existing.ResourceID = in.ResourceID
+			existing.CallbackURL = in.CallbackURL
+			existing.Status = in.Status
+			existing.Metadata = in.Metadata
+			s.byID[id] = existing
+			return existing, nil
+		}
+	}
+	s.next++
+	record := core.Subscription{
+		ID:                   fmt.Sprintf("sub_%d", s.next),
+		ConnectionID:         in.ConnectionID,
+		ProviderID:           in.ProviderID,
+		ResourceType:         in.ResourceType,
+		ResourceID:           in.ResourceID,
+		ChannelID:            in.ChannelID,
+		RemoteSubscriptionID: in.RemoteSubscriptionID,
+		CallbackURL:          in.CallbackURL,
+		Status:               in.Status,
+		Metadata:             in.Metadata,
+	}
+	s.byID[record.ID] = record
+	return record, nil
+}
+
+func (s *memoryWebhookSubscriptionStore) ListByConnection(_ context.Context, connectionID string) ([]core.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []core.Subscription{}
+	for _, subscription := range s.byID {
+		if subscription.ConnectionID == connectionID {
+			out = append(out, subscription)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryWebhookSubscriptionStore) UpdateState(_ context.Context, id string, status core.SubscriptionStatus, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscription, ok := s.byID[id]
+	if !ok {
+		return fmt.Errorf("subscription %q not found", id)
+	}
+	subscription.Status = status
+	s.byID[id] = subscription
+	return nil
+}
+
+func (s *memoryWebhookSubscriptionStore) topics(connectionID string, status core.SubscriptionStatus) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	topics := []string{}
+	for _, subscription := range s.byID {
+		if subscription.ConnectionID == connectionID && subscription.Status == status {
+			topics = append(topics, subscription.ResourceType)
+		}
+	}
+	sort.Strings(topics)
+	return strings.Join(topics, ",")
+}
+
+var (
+	_ CredentialResolver       = staticCredentialResolver{}
+	_ WebhookSubscriptionStore = (*memoryWebhookSubscriptionStore)(nil)
+	_ CredentialResolver       = (*core.Service)(nil)
+)
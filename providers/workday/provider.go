@@ -11,6 +11,7 @@ import (
 
 	"github.com/goliatone/go-services/auth"
 	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/providers"
 )
 
 const (
@@ -35,13 +36,19 @@ type Config struct {
 	TenantURL         string
 	SupportedScopes   []string
 	ExternalAccountID string
+	// Tenant names the tenant in WQL API paths used by schema discovery.
+	Tenant string
+	// HTTPClient performs schema discovery calls.
+	HTTPClient providers.HTTPDoer
 }
 
 type Provider struct {
 	strategy     core.AuthStrategy
 	tenantURL    string
+	tenant       string
 	scopeTypes   []string
 	capabilities []core.CapabilityDescriptor
+	httpClient   providers.HTTPDoer
 }
 
 func DefaultConfig() Config {
@@ -83,8 +90,10 @@ func New(cfg Config) (core.Provider, error) {
 	return &Provider{
 		strategy:     strategy,
 		tenantURL:    strings.TrimRight(strings.TrimSpace(cfg.TenantURL), "/"),
+		tenant:       strings.TrimSpace(cfg.Tenant),
 		scopeTypes:   normalizeScopeTypes(cfg.SupportedScopes),
 		capabilities: Capabilities(),
+		httpClient:   cfg.HTTPClient,
 	}, nil
 }
 
@@ -0,0 +1,211 @@
+package workday
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	SchemaName = "workday.wql"
+
+	wqlFieldsPageSize       = 100
+	maxWQLFieldPages        = 50
+	maxWQLResponseBytes     = 4 << 20 // 4 MiB
+	metadataTenant          = "tenant"
+	defaultWQLSchemaDataset = "allWorkers"
+)
+
+var _ core.SchemaDiscoveryProvider = (*Provider)(nil)
+
+type wqlDataSource struct {
+	ID         string `json:"id"`
+	Alias      string `json:"alias"`
+	Descriptor string `json:"descriptor"`
+}
+
+type wqlField struct {
+	ID         string `json:"id"`
+	Alias      string `json:"alias"`
+	Descriptor string `json:"descriptor"`
+	Type       string `json:"type"`
+}
+
+// DiscoverSchema lists the fields of WQL data sources by alias, allWorkers by
+// default. The tenant comes from the provider config or the credential's
+// tenant metadata.
+func (p *Provider) DiscoverSchema(ctx context.Context, req core.DiscoverSchemaRequest) (core.ExternalSchema, error) {
+	if p == nil {
+		return core.ExternalSchema{}, fmt.Errorf("providers/workday: provider is nil")
+	}
+	if req.Credential == nil || strings.TrimSpace(req.Credential.AccessToken) == "" {
+		return core.ExternalSchema{}, fmt.Errorf("providers/workday: access token is required")
+	}
+	tenant := p.tenant
+	if value, ok := req.Credential.Metadata[metadataTenant].(string); ok && strings.TrimSpace(value) != "" {
+		tenant = strings.TrimSpace(value)
+	}
+	if tenant == "" {
+		return core.ExternalSchema{}, fmt.Errorf("providers/workday: tenant is required for schema discovery")
+	}
+	aliases := req.Objects
+	if len(aliases) == 0 {
+		aliases = []string{defaultWQLSchemaDataset}
+	}
+
+	baseURL := p.tenantURL + "/ccx/api/wql/v1/" + url.PathEscape(tenant)
+	objects := make([]core.ExternalObjectSchema, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+		var sources struct {
+			Data []wqlDataSource `json:"data"`
+		}
+		query := url.Values{"alias": {alias}}
+		if err := p.getWQL(ctx, req.Credential.AccessToken, baseURL+"/dataSources?"+query.Encode(), &sources); err != nil {
+			return core.ExternalSchema{}, err
+		}
+		if len(sources.Data) == 0 {
+			return core.ExternalSchema{}, fmt.Errorf("providers/workday: data source %q not found", alias)
+		}
+		source := sources.Data[0]
+		fields, err := p.wqlFields(ctx, req.Credential.AccessToken, baseURL, source.ID)
+		if err != nil {
+			return core.ExternalSchema{}, err
+		}
+		objects = append(objects, wqlObjectSchema(alias, source, fields))
+	}
+	return core.NormalizeDiscoveredSchema(core.ExternalSchema{
+		ProviderID: ProviderID,
+		Scope:      req.Scope,
+		Name:       SchemaName,
+		Objects:    objects,
+		Metadata:   map[string]any{metadataTenant: tenant},
+	})
+}
+
+func (p *Provider) wqlFields(ctx context.Context, accessToken, baseURL, dataSourceID string) ([]wqlField, error) {
+	fields := make([]wqlField, 0)
+	for page := 0; page < maxWQLFieldPages; page++ {
+		var response struct {
+			Total int        `json:"total"`
+			Data  []wqlField `json:"data"`
+		}
+		query := url.Values{
+			"limit":  {strconv.Itoa(wqlFieldsPageSize)},
+			"offset": {strconv.Itoa(len(fields))},
+		}
+		endpoint := baseURL + "/dataSources/" + url.PathEscape(dataSourceID) + "/fields?" + query.Encode()
+		if err := p.getWQL(ctx, accessToken, endpoint, &response); err != nil {
+			return nil, err
+		}
+		fields = append(fields, response.Data...)
+		if len(response.Data) == 0 || len(fields) >= response.Total {
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("providers/workday: fields for data source %s exceed %d pages", dataSourceID, maxWQLFieldPages)
+}
+
+func (p *Provider) getWQL(ctx context.Context, accessToken, endpoint string, out any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("providers/workday: build wql request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(accessToken))
+	request.Header.Set("Accept", "application/json")
+
+	client := p.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("providers/workday: wql request: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxWQLResponseBytes))
+	if err != nil {
+		return fmt.Errorf("providers/workday: read wql response: %w", err)
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"providers/workday: wql request returned status %d: %s",
+			response.StatusCode,
+			strings.TrimSpace(string(body)),
+		)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("providers/workday: decode wql response: %w", err)
+	}
+	return nil
+}
+
+// wqlObjectSchema lists the data source fields by alias. Instance fields are
+// objects carrying id and descriptor.
+func wqlObjectSchema(alias string, source wqlDataSource, fields []wqlField) core.ExternalObjectSchema {
+	schemaFields := make([]core.ExternalField, 0, len(fields)+2)
+	for _, field := range fields {
+		name := strings.TrimSpace(field.Alias)
+		if name == "" {
+			continue
+		}
+		fieldType, format, repeatable := wqlFieldType(field.Type)
+		schemaFields = append(schemaFields, core.ExternalField{
+			Path:       name,
+			Type:       fieldType,
+			Repeatable: repeatable,
+			Format:     format,
+			Metadata: map[string]any{
+				"label":       field.Descriptor,
+				"native_type": field.Type,
+				"field_id":    field.ID,
+			},
+		})
+		if fieldType == core.ExternalFieldTypeObject {
+			schemaFields = append(schemaFields,
+				core.ExternalField{Path: name + ".id", Type: core.ExternalFieldTypeString},
+				core.ExternalField{Path: name + ".descriptor", Type: core.ExternalFieldTypeString},
+			)
+		}
+	}
+	return core.ExternalObjectSchema{
+		Name:     alias,
+		Fields:   schemaFields,
+		Metadata: map[string]any{"label": source.Descriptor, "data_source_id": source.ID},
+	}
+}
+
+// wqlFieldType maps a WQL field type onto the compiler vocabulary.
+func wqlFieldType(native string) (string, string, bool) {
+	switch strings.ToLower(strings.TrimSpace(native)) {
+	case "boolean":
+		return core.ExternalFieldTypeBoolean, "", false
+	case "numeric":
+		return core.ExternalFieldTypeNumber, "", false
+	case "currency":
+		return core.ExternalFieldTypeNumber, "currency", false
+	case "date":
+		return core.ExternalFieldTypeDatetime, "date", false
+	case "datetime":
+		return core.ExternalFieldTypeDatetime, "date-time", false
+	case "single instance":
+		return core.ExternalFieldTypeObject, "", false
+	case "multi-instance":
+		return core.ExternalFieldTypeObject, "", true
+	case "rich text":
+		return core.ExternalFieldTypeString, "html", false
+	default:
+		return core.ExternalFieldTypeString, "", false
+	}
+}
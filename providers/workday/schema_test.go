@@ -0,0 +1,78 @@
+package workday
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestProvider_DiscoverSchemaListsWQLDataSourceFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer wd_token" {
+			t.Fatalf("expected bearer token")
+		}
+		switch r.URL.Path {
+		case "/ccx/api/wql/v1/acme/dataSources":
+			if r.URL.Query().Get("alias") != "allWorkers" {
+				t.Fatalf("unexpected data source query %q", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"id": "ds_1", "alias": "allWorkers", "descriptor": "All Workers"}},
+			})
+		case "/ccx/api/wql/v1/acme/dataSources/ds_1/fields":
+			if r.URL.Query().Get("offset") == "0" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"total": 4, "data": []map[string]any{
+					{"id": "f1", "alias": "fullName", "descriptor": "Full Name", "type": "Text"},
+					{"id": "f2", "alias": "hireDate", "descriptor": "Hire Date", "type": "Date"},
+				}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"total": 4, "data": []map[string]any{
+				{"id": "f3", "alias": "annualSalary", "descriptor": "Annual Salary", "type": "Currency"},
+				{"id": "f4", "alias": "organizations", "descriptor": "Organizations", "type": "Multi-Instance"},
+			}})
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	providerRaw, err := New(Config{
+		Issuer:           "svc-account@example.iam.gserviceaccount.com",
+		Audience:         "https://api.workday.test/token",
+		SigningKey:       "secret-signing-key",
+		SigningAlgorithm: "HS256",
+		TenantURL:        server.URL,
+		Tenant:           "acme",
+		HTTPClient:       server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	schema, err := providerRaw.(*Provider).DiscoverSchema(context.Background(), core.DiscoverSchemaRequest{
+		Credential: &core.ActiveCredential{AccessToken: "wd_token"},
+	})
+	if err != nil {
+		t.Fatalf("discover schema: %v", err)
+	}
+	if schema.Name != SchemaName || schema.Version == "" || len(schema.Objects) != 1 {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+	fields := map[string]core.ExternalField{}
+	for _, field := range schema.Objects[0].Fields {
+		fields[field.Path] = field
+	}
+	if len(fields) != 6 {
+		t.Fatalf("expected paged fields with instance subfields, got %+v", fields)
+	}
+	if fields["hireDate"].Type != core.ExternalFieldTypeDatetime || fields["annualSalary"].Type != core.ExternalFieldTypeNumber {
+		t.Fatalf("unexpected field types %+v", fields)
+	}
+	if !fields["organizations"].Repeatable || fields["organizations.descriptor"].Type != core.ExternalFieldTypeString {
+		t.Fatalf("expected repeatable instance field, got %+v", fields)
+	}
+}
@@ -11,4 +11,7 @@ var (
 	_ gocmd.Querier[GetInstallationMessage, core.Installation]              = (*GetInstallationQuery)(nil)
 	_ gocmd.Querier[ListInstallationsMessage, []core.Installation]          = (*ListInstallationsQuery)(nil)
 	_ gocmd.Querier[GetSyncJobMessage, core.SyncJob]                        = (*GetSyncJobQuery)(nil)
+	_ gocmd.Querier[ListSyncJobsMessage, []core.SyncJob]                    = (*ListSyncJobsQuery)(nil)
+	_ gocmd.Querier[ListSyncSchedulesMessage, []core.SyncSchedule]          = (*ListSyncSchedulesQuery)(nil)
+	_ gocmd.Querier[ListSyncScheduleRunsMessage, []core.SyncScheduleRun]    = (*ListSyncScheduleRunsQuery)(nil)
 )
@@ -28,6 +28,15 @@ type SyncJobReader interface {
 	GetSyncJob(ctx context.Context, req core.GetSyncJobRequest) (core.SyncJob, error)
 }
 
+type SyncJobLister interface {
+	ListSyncJobs(ctx context.Context, req core.ListSyncJobsRequest) ([]core.SyncJob, error)
+}
+
+type SyncScheduleReader interface {
+	ListSyncSchedules(ctx context.Context, req core.ListSyncSchedulesRequest) ([]core.SyncSchedule, error)
+	ListSyncScheduleRuns(ctx context.Context, req core.ListSyncScheduleRunsRequest) ([]core.SyncScheduleRun, error)
+}
+
 type LoadSyncCursorQuery struct {
 	reader SyncCursorReader
 }
@@ -108,3 +117,48 @@ func (q *GetSyncJobQuery) Query(ctx context.Context, msg GetSyncJobMessage) (cor
 	}
 	return q.reader.GetSyncJob(ctx, msg.Request)
 }
+
+type ListSyncJobsQuery struct {
+	lister SyncJobLister
+}
+
+func NewListSyncJobsQuery(lister SyncJobLister) *ListSyncJobsQuery {
+	return &ListSyncJobsQuery{lister: lister}
+}
+
+func (q *ListSyncJobsQuery) Query(ctx context.Context, msg ListSyncJobsMessage) ([]core.SyncJob, error) {
+	if q == nil || q.lister == nil {
+		return nil, queryDependencyError("query: sync job lister is required")
+	}
+	return q.lister.ListSyncJobs(ctx, msg.Request)
+}
+
+type ListSyncSchedulesQuery struct {
+	reader SyncScheduleReader
+}
+
+func NewListSyncSchedulesQuery(reader SyncScheduleReader) *ListSyncSchedulesQuery {
+	return &ListSyncSchedulesQuery{reader: reader}
+}
+
+func (q *ListSyncSchedulesQuery) Query(ctx context.Context, msg ListSyncSchedulesMessage) ([]core.SyncSchedule, error) {
+	if q == nil || q.reader == nil {
+		return nil, queryDependencyError("query: sync schedule reader is required")
+	}
+	return q.reader.ListSyncSchedules(ctx, msg.Request)
+}
+
+type ListSyncScheduleRunsQuery struct {
+	reader SyncScheduleReader
+}
+
+func NewListSyncScheduleRunsQuery(reader SyncScheduleReader) *ListSyncScheduleRunsQuery {
+	return &ListSyncScheduleRunsQuery{reader: reader}
+}
+
+func (q *ListSyncScheduleRunsQuery) Query(ctx context.Context, msg ListSyncScheduleRunsMessage) ([]core.SyncScheduleRun, error) {
+	if q == nil || q.reader == nil {
+		return nil, queryDependencyError("query: sync schedule reader is required")
+	}
+	return q.reader.ListSyncScheduleRuns(ctx, msg.Request)
+}
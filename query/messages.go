@@ -12,6 +12,9 @@ const (
 	TypeGetInstallation      = "services.query.installation.get"
 	TypeListInstallations    = "services.query.installation.list_by_scope"
 	TypeGetSyncJob           = "services.query.sync_job.get"
+	TypeListSyncJobs         = "services.query.sync_job.list"
+	TypeListSyncSchedules    = "services.query.sync_schedule.list"
+	TypeListSyncScheduleRuns = "services.query.sync_schedule.list_runs"
 )
 
 type LoadSyncCursorMessage struct {
@@ -103,3 +106,53 @@ func (m GetSyncJobMessage) Validate() error {
 	}
 	return nil
 }
+
+type ListSyncJobsMessage struct {
+	Request core.ListSyncJobsRequest
+}
+
+func (ListSyncJobsMessage) Type() string { return TypeListSyncJobs }
+
+func (m ListSyncJobsMessage) Validate() error {
+	if strings.TrimSpace(m.Request.ProviderID) == "" && strings.TrimSpace(m.Request.ConnectionID) == "" {
+		return queryInvalidInputError("query: provider id or connection id is required")
+	}
+	if m.Request.Limit < 0 {
+		return queryValidationError("limit", "limit must not be negative")
+	}
+	return nil
+}
+
+type ListSyncSchedulesMessage struct {
+	Request core.ListSyncSchedulesRequest
+}
+
+func (ListSyncSchedulesMessage) Type() string { return TypeListSyncSchedules }
+
+func (m ListSyncSchedulesMessage) Validate() error {
+	if strings.TrimSpace(m.Request.ProviderID) == "" &&
+		strings.TrimSpace(m.Request.ConnectionID) == "" &&
+		strings.TrimSpace(m.Request.SyncBindingID) == "" {
+		return queryInvalidInputError("query: provider id, connection id or sync binding id is required")
+	}
+	if m.Request.Limit < 0 {
+		return queryValidationError("limit", "limit must not be negative")
+	}
+	return nil
+}
+
+type ListSyncScheduleRunsMessage struct {
+	Request core.ListSyncScheduleRunsRequest
+}
+
+func (ListSyncScheduleRunsMessage) Type() string { return TypeListSyncScheduleRuns }
+
+func (m ListSyncScheduleRunsMessage) Validate() error {
+	if strings.TrimSpace(m.Request.SyncScheduleID) == "" {
+		return queryValidationError("sync_schedule_id", "sync schedule id is required")
+	}
+	if m.Request.Count < 0 {
+		return queryValidationError("count", "count must not be negative")
+	}
+	return nil
+}
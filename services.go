@@ -29,6 +29,14 @@ type SubscriptionStore = core.SubscriptionStore
 type SyncCursorStore = core.SyncCursorStore
 type InstallationStore = core.InstallationStore
 type SyncJobStore = core.SyncJobStore
+type SyncJobExecutionStore = core.SyncJobExecutionStore
+type SyncJobRunner = core.SyncJobRunner
+type SyncJobRunnerFunc = core.SyncJobRunnerFunc
+type SyncJobExecutor = core.SyncJobExecutor
+type SyncScheduleStore = core.SyncScheduleStore
+type SyncScheduler = core.SyncScheduler
+type LeaderLeaseStore = core.LeaderLeaseStore
+type LeaderElector = core.LeaderElector
 type CredentialCodec = core.CredentialCodec
 type IdempotencyClaimStore = core.IdempotencyClaimStore
 type CallbackURLResolver = core.CallbackURLResolver
@@ -52,6 +60,14 @@ type InvokeCapabilityRequest = core.InvokeCapabilityRequest
 type CreateSyncJobRequest = core.CreateSyncJobRequest
 type CreateSyncJobResult = core.CreateSyncJobResult
 type GetSyncJobRequest = core.GetSyncJobRequest
+type ControlSyncJobRequest = core.ControlSyncJobRequest
+type ListSyncJobsRequest = core.ListSyncJobsRequest
+type SyncSchedule = core.SyncSchedule
+type SyncScheduleRun = core.SyncScheduleRun
+type LeaderLease = core.LeaderLease
+type UpsertSyncScheduleRequest = core.UpsertSyncScheduleRequest
+type ListSyncSchedulesRequest = core.ListSyncSchedulesRequest
+type ListSyncScheduleRunsRequest = core.ListSyncScheduleRunsRequest
 
 const (
 	CallbackURLResolveFlowConnect     = core.CallbackURLResolveFlowConnect
@@ -84,6 +100,8 @@ var (
 	WithSyncCursorStore         = core.WithSyncCursorStore
 	WithInstallationStore       = core.WithInstallationStore
 	WithSyncJobStore            = core.WithSyncJobStore
+	WithSyncJobEnqueuer         = core.WithSyncJobEnqueuer
+	WithSyncScheduleStore       = core.WithSyncScheduleStore
 	WithGrantStore              = core.WithGrantStore
 	WithPermissionEvaluator     = core.WithPermissionEvaluator
 	WithSigner                  = core.WithSigner
@@ -191,38 +191,46 @@ func (s *ActivityStore) Prune(ctx context.Context, policy core.ActivityRetention
 	deleted := 0
 	now := time.Now().UTC()
 
-	if policy.TTL > 0 {
-		cutoff := now.Add(-policy.TTL)
-		res, err := s.db.NewDelete().
-			Model((*activityEntryRecord)(nil)).
-			Where("created_at < ?", cutoff).
-			Exec(ctx)
-		if err != nil {
-			return deleted, err
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
 		}
-		affected, _ := res.RowsAffected()
-		deleted += int(affected)
-	}
-
-	if policy.RowCap > 0 {
-		total, err := s.db.NewSelect().Model((*activityEntryRecord)(nil)).Count(ctx)
-		if err != nil {
-			return deleted, err
-		}
-		excess := total - policy.RowCap
-		if excess > 0 {
-			res, err := s.db.NewRaw(
-				"DELETE FROM service_activity_entries WHERE id IN (SELECT id FROM service_activity_entries ORDER BY created_at ASC LIMIT ?)",
-				excess,
-			).Exec(ctx)
+		if policy.TTL > 0 {
+			cutoff := now.Add(-policy.TTL)
+			res, err := tx.NewDelete().
+				Model((*activityEntryRecord)(nil)).
+				Where("created_at < ?", cutoff).
+				Exec(ctx)
 			if err != nil {
-				return deleted, err
+				return err
 			}
 			affected, _ := res.RowsAffected()
 			deleted += int(affected)
 		}
-	}
 
+		if policy.RowCap > 0 {
+			total, err := tx.NewSelect().Model((*activityEntryRecord)(nil)).Count(ctx)
+			if err != nil {
+				return err
+			}
+			excess := total - policy.RowCap
+			if excess > 0 {
+				res, err := tx.NewRaw(
+					"DELETE FROM service_activity_entries WHERE id IN (SELECT id FROM service_activity_entries ORDER BY created_at ASC LIMIT ?)",
+					excess,
+				).Exec(ctx)
+				if err != nil {
+					return err
+				}
+				affected, _ := res.RowsAffected()
+				deleted += int(affected)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
 	return deleted, nil
 }
 
@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/installations"
 	"github.com/goliatone/go-services/ratelimit"
 	servicesync "github.com/goliatone/go-services/sync"
 )
@@ -9,18 +10,36 @@ import (
 var (
 	_ core.ConnectionStore            = (*ConnectionStore)(nil)
 	_ core.CredentialStore            = (*CredentialStore)(nil)
+	_ core.ExpiringCredentialLister   = (*CredentialStore)(nil)
 	_ core.SubscriptionStore          = (*SubscriptionStore)(nil)
 	_ core.SyncCursorStore            = (*SyncCursorStore)(nil)
 	_ core.InstallationStore          = (*InstallationStore)(nil)
 	_ core.SyncJobStore               = (*SyncJobStore)(nil)
+	_ core.SyncJobExecutionStore      = (*SyncJobStore)(nil)
+	_ core.SyncScheduleStore          = (*SyncScheduleStore)(nil)
+	_ core.LeaderLeaseStore           = (*LeaderLeaseStore)(nil)
+	_ core.RetentionStore             = (*RetentionStore)(nil)
 	_ ratelimit.StateStore            = (*RateLimitStateStore)(nil)
 	_ ratelimit.StateStore            = (*CachedRateLimitStateStore)(nil)
 	_ core.GrantStore                 = (*GrantStore)(nil)
 	_ core.OutboxStore                = (*OutboxStore)(nil)
+	_ core.OutboxAdminStore           = (*OutboxStore)(nil)
+	_ core.OutboxProjectorStore       = (*OutboxStore)(nil)
 	_ core.NotificationDispatchLedger = (*NotificationDispatchStore)(nil)
 	_ core.ServicesActivitySink       = (*ActivityStore)(nil)
 	_ core.ActivityRetentionPruner    = (*ActivityStore)(nil)
+	_ core.MappingSpecStore           = (*MappingSpecStore)(nil)
+	_ core.SyncBindingStore           = (*SyncBindingStore)(nil)
+	_ core.SyncCheckpointStore        = (*SyncCheckpointStore)(nil)
+	_ core.IdentityBindingStore       = (*IdentityBindingStore)(nil)
+	_ core.SyncConflictStore          = (*SyncConflictStore)(nil)
+	_ core.SyncChangeLogStore         = (*SyncChangeLogStore)(nil)
+	_ core.SyncChangeLogReader        = (*SyncChangeLogStore)(nil)
+	_ core.JobScheduledEnqueuer       = (*JobQueueStore)(nil)
+	_ core.JobDispatchStatusReader    = (*JobQueueStore)(nil)
+	_ core.JobDequeuer                = (*JobQueueStore)(nil)
 	_ servicesync.SyncJobStore        = (*SyncJobStore)(nil)
+	_ installations.SubscriptionStore = (*SubscriptionStore)(nil)
 	_ core.StoreProvider              = (*RepositoryFactory)(nil)
 	_ core.RepositoryStoreFactory     = (*RepositoryFactory)(nil)
 )
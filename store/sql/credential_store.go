@@ -58,6 +58,9 @@ func (s *CredentialStore) SaveNewVersion(ctx context.Context, in core.SaveCreden
 
 	var created core.Credential
 	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
 		nextVersion, versionErr := s.nextVersion(ctx, tx, trimmedConnectionID)
 		if versionErr != nil {
 			return versionErr
@@ -107,7 +110,7 @@ func (s *CredentialStore) GetActiveByConnection(ctx context.Context, connectionI
 		return core.Credential{}, err
 	}
 	if len(records) == 0 {
-		return core.Credential{}, fmt.Errorf("sqlstore: active credential not found for connection %q", connectionID)
+		return core.Credential{}, fmt.Errorf("%w: connection %q", core.ErrCredentialNotFound, connectionID)
 	}
 	return records[0].toDomain(), nil
 }
@@ -136,6 +139,36 @@ func (s *CredentialStore) RevokeActive(ctx context.Context, connectionID string,
 	return err
 }
 
+// ListExpiringCredentials returns the active, refreshable credentials that
+// expire before the given time, soonest first.
+func (s *CredentialStore) ListExpiringCredentials(ctx context.Context, before time.Time, limit int) ([]core.Credential, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: credential store is not configured")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	records, _, err := s.repo.List(ctx,
+		repository.SelectBy("status", "=", string(core.CredentialStatusActive)),
+		repository.SelectRawProcessor(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("?TableAlias.refreshable = ?", true).
+				Where("?TableAlias.expires_at IS NOT NULL").
+				Where("?TableAlias.expires_at <= ?", before.UTC())
+		}),
+		repository.OrderBy("expires_at ASC"),
+		repository.SelectPaginate(limit, 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.Credential, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
 func (s *CredentialStore) nextVersion(ctx context.Context, tx bun.Tx, connectionID string) (int, error) {
 	var maxVersion int
 	if err := tx.NewSelect().
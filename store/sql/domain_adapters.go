@@ -262,6 +262,15 @@ func (r *syncJobRecord) toDomain() core.SyncJob {
 		nextAttempt := *r.NextAttemptAt
 		job.NextAttemptAt = &nextAttempt
 	}
+	job.LeaseExpiresAt = cloneTimePointer(r.LeaseExpiresAt)
+	job.Progress = core.SyncJobProgress{
+		ItemsProcessed:        r.Progress.ItemsProcessed,
+		ItemsTotal:            r.Progress.ItemsTotal,
+		PagesProcessed:        r.Progress.PagesProcessed,
+		Percent:               r.Progress.Percent,
+		StartedAt:             cloneTimePointer(r.Progress.StartedAt),
+		EstimatedCompletionAt: cloneTimePointer(r.Progress.EstimatedCompletionAt),
+	}
 	return job
 }
 
@@ -282,6 +291,15 @@ func newSyncJobRecord(job core.SyncJob, now time.Time) *syncJobRecord {
 		value := *job.NextAttemptAt
 		record.NextAttemptAt = &value
 	}
+	record.LeaseExpiresAt = cloneTimePointer(job.LeaseExpiresAt)
+	record.Progress = syncJobProgressRecord{
+		ItemsProcessed:        job.Progress.ItemsProcessed,
+		ItemsTotal:            job.Progress.ItemsTotal,
+		PagesProcessed:        job.Progress.PagesProcessed,
+		Percent:               job.Progress.Percent,
+		StartedAt:             cloneTimePointer(job.Progress.StartedAt),
+		EstimatedCompletionAt: cloneTimePointer(job.Progress.EstimatedCompletionAt),
+	}
 	return record
 }
 
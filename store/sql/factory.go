@@ -8,6 +8,7 @@ import (
 	repositorycache "github.com/goliatone/go-repository-cache/cache"
 	"github.com/goliatone/go-services/core"
 	"github.com/goliatone/go-services/ratelimit"
+	"github.com/goliatone/go-services/webhooks"
 	"github.com/uptrace/bun"
 )
 
@@ -22,6 +23,51 @@ func WithRateLimitStateCache(cacheService repositorycache.CacheService) Reposito
 	}
 }
 
+// WithSecretProvider configures the secret provider used by stores that keep
+// encrypted material outside the credential store, such as outbound webhook
+// endpoint secrets.
+func WithSecretProvider(provider core.SecretProvider) RepositoryFactoryOption {
+	return func(factory *RepositoryFactory) {
+		if factory == nil || provider == nil {
+			return
+		}
+		factory.secretProvider = provider
+	}
+}
+
+// WithOutboundURLPolicy configures which destinations outbound webhook
+// endpoints may be registered with.
+func WithOutboundURLPolicy(policy webhooks.OutboundURLPolicy) RepositoryFactoryOption {
+	return func(factory *RepositoryFactory) {
+		if factory == nil {
+			return
+		}
+		factory.outboundURLPolicy = policy
+	}
+}
+
+// WithJobQueueConfig configures the lease and attempt limits of the SQL job
+// queue.
+func WithJobQueueConfig(config JobQueueConfig) RepositoryFactoryOption {
+	return func(factory *RepositoryFactory) {
+		if factory == nil {
+			return
+		}
+		factory.jobQueueConfig = config
+	}
+}
+
+// WithOutboxConfig configures how the SQL outbox partitions events for
+// ordered delivery.
+func WithOutboxConfig(config OutboxConfig) RepositoryFactoryOption {
+	return func(factory *RepositoryFactory) {
+		if factory == nil {
+			return
+		}
+		factory.outboxConfig = config
+	}
+}
+
 type RepositoryFactory struct {
 	db *bun.DB
 
@@ -35,9 +81,25 @@ type RepositoryFactory struct {
 	rateLimitStateCacheService repositorycache.CacheService
 	rateLimitPolicy            core.RateLimitPolicy
 	syncJobStore               *SyncJobStore
+	syncScheduleStore          *SyncScheduleStore
+	leaderLeaseStore           *LeaderLeaseStore
+	retentionStore             *RetentionStore
+	outboxConfig               OutboxConfig
 	outboxStore                *OutboxStore
 	notificationDispatchStore  *NotificationDispatchStore
 	activityStore              *ActivityStore
+	secretProvider             core.SecretProvider
+	outboundURLPolicy          webhooks.OutboundURLPolicy
+	outboundEndpointStore      *OutboundEndpointStore
+	outboundDeliveryStore      *OutboundDeliveryStore
+	mappingSpecStore           *MappingSpecStore
+	syncBindingStore           *SyncBindingStore
+	syncCheckpointStore        *SyncCheckpointStore
+	identityBindingStore       *IdentityBindingStore
+	syncConflictStore          *SyncConflictStore
+	syncChangeLogStore         *SyncChangeLogStore
+	jobQueueConfig             JobQueueConfig
+	jobQueueStore              *JobQueueStore
 }
 
 func NewRepositoryFactory(opts ...RepositoryFactoryOption) *RepositoryFactory {
@@ -164,6 +226,48 @@ func (f *RepositoryFactory) SyncJobStoreCore() core.SyncJobStore {
 	return f.syncJobStore
 }
 
+func (f *RepositoryFactory) SyncScheduleStore() *SyncScheduleStore {
+	if f == nil {
+		return nil
+	}
+	return f.syncScheduleStore
+}
+
+func (f *RepositoryFactory) SyncScheduleStoreCore() core.SyncScheduleStore {
+	if f == nil || f.syncScheduleStore == nil {
+		return nil
+	}
+	return f.syncScheduleStore
+}
+
+func (f *RepositoryFactory) LeaderLeaseStore() *LeaderLeaseStore {
+	if f == nil {
+		return nil
+	}
+	return f.leaderLeaseStore
+}
+
+func (f *RepositoryFactory) LeaderLeaseStoreCore() core.LeaderLeaseStore {
+	if f == nil || f.leaderLeaseStore == nil {
+		return nil
+	}
+	return f.leaderLeaseStore
+}
+
+func (f *RepositoryFactory) RetentionStore() *RetentionStore {
+	if f == nil {
+		return nil
+	}
+	return f.retentionStore
+}
+
+func (f *RepositoryFactory) RetentionStoreCore() core.RetentionStore {
+	if f == nil || f.retentionStore == nil {
+		return nil
+	}
+	return f.retentionStore
+}
+
 func (f *RepositoryFactory) OutboxStore() *OutboxStore {
 	if f == nil {
 		return nil
@@ -185,6 +289,70 @@ func (f *RepositoryFactory) ActivityStore() *ActivityStore {
 	return f.activityStore
 }
 
+// OutboundEndpointStore is nil unless WithSecretProvider was configured.
+func (f *RepositoryFactory) OutboundEndpointStore() *OutboundEndpointStore {
+	if f == nil {
+		return nil
+	}
+	return f.outboundEndpointStore
+}
+
+func (f *RepositoryFactory) OutboundDeliveryStore() *OutboundDeliveryStore {
+	if f == nil {
+		return nil
+	}
+	return f.outboundDeliveryStore
+}
+
+func (f *RepositoryFactory) MappingSpecStore() *MappingSpecStore {
+	if f == nil {
+		return nil
+	}
+	return f.mappingSpecStore
+}
+
+func (f *RepositoryFactory) SyncBindingStore() *SyncBindingStore {
+	if f == nil {
+		return nil
+	}
+	return f.syncBindingStore
+}
+
+func (f *RepositoryFactory) SyncCheckpointStore() *SyncCheckpointStore {
+	if f == nil {
+		return nil
+	}
+	return f.syncCheckpointStore
+}
+
+func (f *RepositoryFactory) IdentityBindingStore() *IdentityBindingStore {
+	if f == nil {
+		return nil
+	}
+	return f.identityBindingStore
+}
+
+func (f *RepositoryFactory) SyncConflictStore() *SyncConflictStore {
+	if f == nil {
+		return nil
+	}
+	return f.syncConflictStore
+}
+
+func (f *RepositoryFactory) SyncChangeLogStore() *SyncChangeLogStore {
+	if f == nil {
+		return nil
+	}
+	return f.syncChangeLogStore
+}
+
+func (f *RepositoryFactory) JobQueueStore() *JobQueueStore {
+	if f == nil {
+		return nil
+	}
+	return f.jobQueueStore
+}
+
 func (f *RepositoryFactory) initStores() error {
 	connectionRepo := repository.NewRepository[*connectionRecord](f.db, connectionHandlers())
 	if validator, ok := connectionRepo.(repository.Validator); ok {
@@ -244,7 +412,22 @@ func (f *RepositoryFactory) initStores() error {
 		return err
 	}
 	f.syncJobStore = syncJobStore
-	outboxStore, err := NewOutboxStore(f.db)
+	syncScheduleStore, err := NewSyncScheduleStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.syncScheduleStore = syncScheduleStore
+	leaderLeaseStore, err := NewLeaderLeaseStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.leaderLeaseStore = leaderLeaseStore
+	retentionStore, err := NewRetentionStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.retentionStore = retentionStore
+	outboxStore, err := NewOutboxStoreWithConfig(f.db, f.outboxConfig)
 	if err != nil {
 		return err
 	}
@@ -259,6 +442,54 @@ func (f *RepositoryFactory) initStores() error {
 		return err
 	}
 	f.activityStore = activityStore
+	outboundDeliveryStore, err := NewOutboundDeliveryStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.outboundDeliveryStore = outboundDeliveryStore
+	mappingSpecStore, err := NewMappingSpecStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.mappingSpecStore = mappingSpecStore
+	syncBindingStore, err := NewSyncBindingStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.syncBindingStore = syncBindingStore
+	syncCheckpointStore, err := NewSyncCheckpointStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.syncCheckpointStore = syncCheckpointStore
+	identityBindingStore, err := NewIdentityBindingStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.identityBindingStore = identityBindingStore
+	syncConflictStore, err := NewSyncConflictStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.syncConflictStore = syncConflictStore
+	syncChangeLogStore, err := NewSyncChangeLogStore(f.db)
+	if err != nil {
+		return err
+	}
+	f.syncChangeLogStore = syncChangeLogStore
+	jobQueueStore, err := NewJobQueueStore(f.db, f.jobQueueConfig)
+	if err != nil {
+		return err
+	}
+	f.jobQueueStore = jobQueueStore
+	if f.secretProvider != nil {
+		outboundEndpointStore, endpointErr := NewOutboundEndpointStore(f.db, f.secretProvider)
+		if endpointErr != nil {
+			return endpointErr
+		}
+		outboundEndpointStore.URLPolicy = f.outboundURLPolicy
+		f.outboundEndpointStore = outboundEndpointStore
+	}
 
 	return nil
 }
@@ -0,0 +1,471 @@
+package sqlstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestMappingSpecStore_LifecyclePublishesSingleVersion(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	store := factory.MappingSpecStore()
+	if store == nil {
+		t.Fatalf("expected mapping spec store from factory")
+	}
+	lifecycle, err := core.NewMappingSpecLifecycle(store)
+	if err != nil {
+		t.Fatalf("new mapping spec lifecycle: %v", err)
+	}
+
+	scope := core.ScopeRef{Type: "org", ID: "org_specs"}
+	draft := core.MappingSpec{
+		SpecID:       "spec_contacts",
+		ProviderID:   "hubspot",
+		Scope:        scope,
+		Name:         "contacts",
+		SourceObject: "contacts",
+		TargetModel:  "crm_contacts",
+		Rules: []core.MappingRule{{
+			ID:         "rule_email",
+			SourcePath: "properties.email",
+			TargetPath: "email",
+			Required:   true,
+			Default:    "unknown@example.com",
+		}},
+	}
+	v1, err := lifecycle.CreateDraft(ctx, draft)
+	if err != nil {
+		t.Fatalf("create draft v1: %v", err)
+	}
+	if v1.Version != 1 || v1.Status != core.MappingSpecStatusDraft {
+		t.Fatalf("unexpected v1 draft %+v", v1)
+	}
+	if _, err := store.CreateDraft(ctx, v1); err == nil {
+		t.Fatalf("expected duplicate version to be rejected")
+	}
+	if _, err := lifecycle.MarkValidated(ctx, "hubspot", scope, "spec_contacts", 1); err != nil {
+		t.Fatalf("validate v1: %v", err)
+	}
+	if _, err := lifecycle.Publish(ctx, "hubspot", scope, "spec_contacts", 1); err != nil {
+		t.Fatalf("publish v1: %v", err)
+	}
+
+	v2, err := lifecycle.CreateDraft(ctx, draft)
+	if err != nil {
+		t.Fatalf("create draft v2: %v", err)
+	}
+	if v2.Version != 2 {
+		t.Fatalf("expected version 2, got %d", v2.Version)
+	}
+	v2.Description = "updated"
+	if _, err := lifecycle.UpdateDraft(ctx, v2); err != nil {
+		t.Fatalf("update draft v2: %v", err)
+	}
+	if _, err := lifecycle.MarkValidated(ctx, "hubspot", scope, "spec_contacts", 2); err != nil {
+		t.Fatalf("validate v2: %v", err)
+	}
+	published, err := lifecycle.Publish(ctx, "hubspot", scope, "spec_contacts", 2)
+	if err != nil {
+		t.Fatalf("publish v2: %v", err)
+	}
+	if published.Status != core.MappingSpecStatusPublished || published.PublishedAt == nil {
+		t.Fatalf("expected v2 published, got %+v", published)
+	}
+
+	previous, found, err := store.GetVersion(ctx, "hubspot", scope, "spec_contacts", 1)
+	if err != nil || !found {
+		t.Fatalf("get v1: found=%v err=%v", found, err)
+	}
+	if previous.Status != core.MappingSpecStatusValidated || previous.PublishedAt != nil {
+		t.Fatalf("expected v1 demoted to validated, got %+v", previous)
+	}
+
+	latest, found, err := store.GetLatest(ctx, "hubspot", scope, "spec_contacts")
+	if err != nil || !found {
+		t.Fatalf("get latest: found=%v err=%v", found, err)
+	}
+	if latest.Version != 2 || latest.Description != "updated" {
+		t.Fatalf("unexpected latest spec %+v", latest)
+	}
+	if len(latest.Rules) != 1 || latest.Rules[0].SourcePath != "properties.email" || !latest.Rules[0].Required {
+		t.Fatalf("expected rules to round trip, got %+v", latest.Rules)
+	}
+
+	listed, err := store.ListByScope(ctx, "hubspot", scope)
+	if err != nil {
+		t.Fatalf("list by scope: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Version != 1 || listed[1].Version != 2 {
+		t.Fatalf("expected both versions ordered, got %+v", listed)
+	}
+	if _, found, _ := store.GetVersion(ctx, "hubspot", core.ScopeRef{Type: "org", ID: "other"}, "spec_contacts", 1); found {
+		t.Fatalf("expected scope isolation on get version")
+	}
+}
+
+func TestSyncStores_BindingIdentityConflictAndCheckpointPersistence(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	binding := seedSyncBinding(t, ctx, factory)
+	bindings := factory.SyncBindingStore()
+
+	updated, err := bindings.Upsert(ctx, core.SyncBinding{
+		ProviderID:    binding.ProviderID,
+		Scope:         binding.Scope,
+		ConnectionID:  binding.ConnectionID,
+		MappingSpecID: binding.MappingSpecID,
+		SourceObject:  binding.SourceObject,
+		TargetModel:   "crm_people",
+		Direction:     binding.Direction,
+	})
+	if err != nil {
+		t.Fatalf("upsert existing binding: %v", err)
+	}
+	if updated.ID != binding.ID || updated.TargetModel != "crm_people" {
+		t.Fatalf("expected upsert to update binding in place, got %+v", updated)
+	}
+	if err := bindings.UpdateStatus(ctx, binding.ID, core.SyncBindingStatusPaused, "quota exceeded"); err != nil {
+		t.Fatalf("update binding status: %v", err)
+	}
+	paused, err := bindings.Get(ctx, binding.ID)
+	if err != nil {
+		t.Fatalf("get binding: %v", err)
+	}
+	if paused.Status != core.SyncBindingStatusPaused || paused.Metadata["status_reason"] != "quota exceeded" {
+		t.Fatalf("unexpected paused binding %+v", paused)
+	}
+	listed, err := bindings.ListByConnection(ctx, binding.ConnectionID)
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected one binding for connection, got %d err=%v", len(listed), err)
+	}
+
+	reconciler, err := core.NewIdentityBindingReconciler(factory.IdentityBindingStore())
+	if err != nil {
+		t.Fatalf("new identity reconciler: %v", err)
+	}
+	reconcileReq := core.ReconcileIdentityRequest{
+		ProviderID:    binding.ProviderID,
+		Scope:         binding.Scope,
+		ConnectionID:  binding.ConnectionID,
+		SyncBindingID: binding.ID,
+		SourceObject:  "contacts",
+		ExternalID:    "ext_1",
+		Candidates:    []core.IdentityCandidate{{InternalType: "contact", InternalID: "ct_1", Confidence: 1}},
+	}
+	first, err := reconciler.ReconcileIdentity(ctx, reconcileReq)
+	if err != nil || !first.Created {
+		t.Fatalf("expected identity binding to be created, result=%+v err=%v", first, err)
+	}
+	second, err := reconciler.ReconcileIdentity(ctx, reconcileReq)
+	if err != nil || second.Created || second.Binding.ID != first.Binding.ID {
+		t.Fatalf("expected reconcile replay to reuse binding, result=%+v err=%v", second, err)
+	}
+	byInternal, err := factory.IdentityBindingStore().ListByInternalID(ctx, binding.ID, "contact", "ct_1")
+	if err != nil || len(byInternal) != 1 {
+		t.Fatalf("expected one identity binding by internal id, got %d err=%v", len(byInternal), err)
+	}
+	orphaned := first.Binding
+	orphanedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orphaned.OrphanedAt = &orphanedAt
+	if _, err := factory.IdentityBindingStore().Upsert(ctx, orphaned); err != nil {
+		t.Fatalf("orphan identity binding: %v", err)
+	}
+	reloaded, _, err := factory.IdentityBindingStore().GetByExternalID(ctx, binding.ID, "ext_1")
+	if err != nil || reloaded.OrphanedAt == nil || !reloaded.OrphanedAt.Equal(orphanedAt) {
+		t.Fatalf("expected orphaned identity binding, got %+v err=%v", reloaded, err)
+	}
+
+	conflicts := factory.SyncConflictStore()
+	conflict := core.SyncConflict{
+		ProviderID:     binding.ProviderID,
+		Scope:          binding.Scope,
+		ConnectionID:   binding.ConnectionID,
+		SyncBindingID:  binding.ID,
+		SourceObject:   "contacts",
+		ExternalID:     "ext_1",
+		IdempotencyKey: "conflict_ext_1_v2",
+		Reason:         "target modified",
+		SourcePayload:  map[string]any{"email": "a@example.com"},
+	}
+	recorded, err := conflicts.Append(ctx, conflict)
+	if err != nil {
+		t.Fatalf("append conflict: %v", err)
+	}
+	replayed, err := conflicts.Append(ctx, conflict)
+	if err != nil || replayed.ID != recorded.ID {
+		t.Fatalf("expected idempotent conflict append, got %+v err=%v", replayed, err)
+	}
+	pending, err := conflicts.ListByBinding(ctx, binding.ProviderID, binding.Scope, binding.ID, core.SyncConflictStatusPending)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected one pending conflict, got %d err=%v", len(pending), err)
+	}
+	resolved, err := conflicts.Resolve(ctx, binding.ProviderID, binding.Scope, recorded.ID, core.SyncConflictResolution{
+		Action:     core.SyncConflictResolutionResolve,
+		Patch:      map[string]any{"email": "b@example.com"},
+		Reason:     "source wins",
+		ResolvedBy: "usr_admin",
+	}, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("resolve conflict: %v", err)
+	}
+	if resolved.Status != core.SyncConflictStatusResolved || resolved.ResolvedAt == nil ||
+		resolved.Resolution["reason"] != "source wins" || resolved.ResolvedBy != "usr_admin" {
+		t.Fatalf("unexpected resolved conflict %+v", resolved)
+	}
+	if _, err := conflicts.Get(ctx, "other", binding.Scope, recorded.ID); err == nil {
+		t.Fatalf("expected provider isolation on conflict get")
+	}
+
+	checkpoints := factory.SyncCheckpointStore()
+	saved, err := checkpoints.Save(ctx, core.SyncCheckpoint{
+		ProviderID:    binding.ProviderID,
+		Scope:         binding.Scope,
+		ConnectionID:  binding.ConnectionID,
+		SyncBindingID: binding.ID,
+		Direction:     core.SyncDirectionImport,
+		Cursor:        "cursor_1",
+		Sequence:      1,
+	})
+	if err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	next, err := checkpoints.Save(ctx, core.SyncCheckpoint{
+		ID:            saved.ID,
+		ProviderID:    binding.ProviderID,
+		Scope:         binding.Scope,
+		ConnectionID:  binding.ConnectionID,
+		SyncBindingID: binding.ID,
+		Direction:     core.SyncDirectionImport,
+		Cursor:        "cursor_2",
+		Sequence:      2,
+	})
+	if err != nil {
+		t.Fatalf("save next checkpoint: %v", err)
+	}
+	if next.ID == saved.ID {
+		t.Fatalf("expected new sequence to be stored as a new checkpoint")
+	}
+	latest, found, err := checkpoints.GetLatest(ctx, binding.ProviderID, binding.Scope, binding.ID, core.SyncDirectionImport)
+	if err != nil || !found || latest.Cursor != "cursor_2" || latest.Sequence != 2 {
+		t.Fatalf("unexpected latest checkpoint %+v found=%v err=%v", latest, found, err)
+	}
+	byID, found, err := checkpoints.GetByID(ctx, binding.ProviderID, binding.Scope, saved.ID)
+	if err != nil || !found || byID.Cursor != "cursor_1" {
+		t.Fatalf("unexpected checkpoint by id %+v found=%v err=%v", byID, found, err)
+	}
+}
+
+func TestSyncChangeLogStore_IdempotentAppendAndCursorPagination(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	binding := seedSyncBinding(t, ctx, factory)
+	changeLog := factory.SyncChangeLogStore()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for idx, externalID := range []string{"ext_1", "ext_2", "ext_3", "ext_4", "ext_5"} {
+		entry := core.SyncChangeLogEntry{
+			ProviderID:     binding.ProviderID,
+			Scope:          binding.Scope,
+			ConnectionID:   binding.ConnectionID,
+			SyncBindingID:  binding.ID,
+			Direction:      core.SyncDirectionImport,
+			SourceObject:   "contacts",
+			ExternalID:     externalID,
+			SourceVersion:  "v1",
+			IdempotencyKey: "key_" + externalID,
+			Payload:        map[string]any{"id": externalID},
+			OccurredAt:     base.Add(time.Duration(idx/2) * time.Second),
+		}
+		created, err := changeLog.Append(ctx, entry)
+		if err != nil || !created {
+			t.Fatalf("append %s: created=%v err=%v", externalID, created, err)
+		}
+		created, err = changeLog.Append(ctx, entry)
+		if err != nil || created {
+			t.Fatalf("expected duplicate append of %s to be skipped: created=%v err=%v", externalID, created, err)
+		}
+	}
+
+	var seen []string
+	cursor := ""
+	for range 3 {
+		page, next, err := changeLog.ListSince(ctx, binding.ID, core.SyncDirectionImport, cursor, 2)
+		if err != nil {
+			t.Fatalf("list since: %v", err)
+		}
+		for _, entry := range page {
+			seen = append(seen, entry.ExternalID)
+		}
+		cursor = next
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected five distinct entries across pages, got %v", seen)
+	}
+	for idx := 1; idx < len(seen); idx++ {
+		if seen[idx] == seen[idx-1] {
+			t.Fatalf("expected no duplicates across pages, got %v", seen)
+		}
+	}
+
+	empty, tail, err := changeLog.ListSince(ctx, binding.ID, core.SyncDirectionImport, cursor, 2)
+	if err != nil || len(empty) != 0 || tail != cursor {
+		t.Fatalf("expected drained log to echo cursor, entries=%d cursor=%q err=%v", len(empty), tail, err)
+	}
+
+	// An entry logged late with an older event time still reaches a consumer
+	// that already read past that time.
+	if created, err := changeLog.Append(ctx, core.SyncChangeLogEntry{
+		ProviderID: binding.ProviderID, Scope: binding.Scope, ConnectionID: binding.ConnectionID,
+		SyncBindingID: binding.ID, Direction: core.SyncDirectionImport, SourceObject: "contacts",
+		ExternalID: "ext_late", SourceVersion: "v1", IdempotencyKey: "key_ext_late",
+		OccurredAt: base.Add(-time.Hour),
+	}); err != nil || !created {
+		t.Fatalf("append late entry: created=%v err=%v", created, err)
+	}
+	late, _, err := changeLog.ListSince(ctx, binding.ID, core.SyncDirectionImport, cursor, 2)
+	if err != nil || len(late) != 1 || late[0].ExternalID != "ext_late" {
+		t.Fatalf("expected the late entry after the cursor, got %+v err=%v", late, err)
+	}
+	exported, _, err := changeLog.ListSince(ctx, binding.ID, core.SyncDirectionExport, "", 10)
+	if err != nil || len(exported) != 0 {
+		t.Fatalf("expected direction isolation, got %d err=%v", len(exported), err)
+	}
+	if _, _, err := changeLog.ListSince(ctx, binding.ID, core.SyncDirectionImport, "not-a-cursor", 2); err == nil {
+		t.Fatalf("expected invalid cursor to be rejected")
+	}
+
+	if _, err := changeLog.Append(ctx, core.SyncChangeLogEntry{
+		ProviderID: binding.ProviderID, Scope: binding.Scope, ConnectionID: binding.ConnectionID,
+		SyncBindingID: binding.ID, Direction: core.SyncDirectionImport, SourceObject: "contacts",
+		ExternalID: "ext_3", SourceVersion: "v2", IdempotencyKey: "key_ext_3_v2",
+		Operation: core.SyncChangeOperationDelete, OccurredAt: base.Add(time.Minute),
+	}); err != nil {
+		t.Fatalf("append newer version: %v", err)
+	}
+	latest, found, err := changeLog.GetLatest(ctx, binding.ID, core.SyncDirectionImport, "ext_3")
+	if err != nil || !found || latest.SourceVersion != "v2" || latest.Operation != core.SyncChangeOperationDelete {
+		t.Fatalf("expected latest ext_3 entry at v2, got %+v found=%v err=%v", latest, found, err)
+	}
+	if _, found, err := changeLog.GetLatest(ctx, binding.ID, core.SyncDirectionExport, "ext_3"); err != nil || found {
+		t.Fatalf("expected no export entry, found=%v err=%v", found, err)
+	}
+}
+
+func TestSyncExecutionService_ReplaysIdempotentlyWithSQLStores(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	binding := seedSyncBinding(t, ctx, factory)
+	service, err := core.NewSyncExecutionService(factory.SyncCheckpointStore(), factory.SyncChangeLogStore())
+	if err != nil {
+		t.Fatalf("new sync execution service: %v", err)
+	}
+	request := core.RunSyncImportRequest{
+		Plan: core.SyncRunPlan{
+			ID:        "run_sql_1",
+			BindingID: binding.ID,
+			Mode:      core.SyncRunModeApply,
+			Checkpoint: core.SyncCheckpoint{
+				ProviderID:    binding.ProviderID,
+				Scope:         binding.Scope,
+				ConnectionID:  binding.ConnectionID,
+				SyncBindingID: binding.ID,
+				Direction:     core.SyncDirectionImport,
+			},
+		},
+		Changes: []core.SyncChange{
+			{SourceObject: "contacts", ExternalID: "ext_1", SourceVersion: "1", Payload: map[string]any{"email": "a@example.com"}},
+			{SourceObject: "contacts", ExternalID: "ext_2", SourceVersion: "1", Payload: map[string]any{"email": "b@example.com"}},
+		},
+	}
+	first, err := service.RunSyncImport(ctx, request)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.ProcessedCount != 2 || first.NextCheckpoint == nil {
+		t.Fatalf("unexpected first run result %+v", first)
+	}
+	replay, err := service.RunSyncImport(ctx, request)
+	if err != nil {
+		t.Fatalf("replay run: %v", err)
+	}
+	if replay.SkippedCount != 2 || replay.ProcessedCount != 0 {
+		t.Fatalf("expected replay to skip logged changes, got %+v", replay)
+	}
+	latest, found, err := factory.SyncCheckpointStore().GetLatest(
+		ctx,
+		binding.ProviderID,
+		binding.Scope,
+		binding.ID,
+		core.SyncDirectionImport,
+	)
+	if err != nil || !found || latest.Sequence != 2 {
+		t.Fatalf("unexpected latest checkpoint %+v found=%v err=%v", latest, found, err)
+	}
+}
+
+func seedSyncBinding(t *testing.T, ctx context.Context, factory *sqlstore.RepositoryFactory) core.SyncBinding {
+	t.Helper()
+	scope := core.ScopeRef{Type: "org", ID: "org_sync"}
+	connection, err := factory.ConnectionStore().Create(ctx, core.CreateConnectionInput{
+		ProviderID:        "hubspot",
+		Scope:             scope,
+		ExternalAccountID: "portal_1",
+		Status:            core.ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	spec, err := factory.MappingSpecStore().CreateDraft(ctx, core.MappingSpec{
+		SpecID:       "spec_sync",
+		ProviderID:   "hubspot",
+		Scope:        scope,
+		Name:         "contacts",
+		SourceObject: "contacts",
+		TargetModel:  "crm_contacts",
+		Version:      1,
+	})
+	if err != nil {
+		t.Fatalf("create mapping spec: %v", err)
+	}
+	binding, err := factory.SyncBindingStore().Upsert(ctx, core.SyncBinding{
+		ProviderID:    "hubspot",
+		Scope:         scope,
+		ConnectionID:  connection.ID,
+		MappingSpecID: spec.ID,
+		SourceObject:  "contacts",
+		TargetModel:   "crm_contacts",
+		Direction:     core.SyncDirectionImport,
+	})
+	if err != nil {
+		t.Fatalf("upsert sync binding: %v", err)
+	}
+	return binding
+}
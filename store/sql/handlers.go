@@ -413,6 +413,238 @@ func notificationDispatchHandlers() repository.ModelHandlers[*notificationDispat
 	}
 }
 
+func outboundWebhookEndpointHandlers() repository.ModelHandlers[*outboundWebhookEndpointRecord] {
+	return repository.ModelHandlers[*outboundWebhookEndpointRecord]{
+		NewRecord: func() *outboundWebhookEndpointRecord {
+			return &outboundWebhookEndpointRecord{}
+		},
+		GetID: func(record *outboundWebhookEndpointRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *outboundWebhookEndpointRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *outboundWebhookEndpointRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func outboundWebhookDeliveryHandlers() repository.ModelHandlers[*outboundWebhookDeliveryRecord] {
+	return repository.ModelHandlers[*outboundWebhookDeliveryRecord]{
+		NewRecord: func() *outboundWebhookDeliveryRecord {
+			return &outboundWebhookDeliveryRecord{}
+		},
+		GetID: func(record *outboundWebhookDeliveryRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *outboundWebhookDeliveryRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *outboundWebhookDeliveryRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func mappingSpecHandlers() repository.ModelHandlers[*mappingSpecRecord] {
+	return repository.ModelHandlers[*mappingSpecRecord]{
+		NewRecord: func() *mappingSpecRecord {
+			return &mappingSpecRecord{}
+		},
+		GetID: func(record *mappingSpecRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *mappingSpecRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *mappingSpecRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func syncBindingHandlers() repository.ModelHandlers[*syncBindingRecord] {
+	return repository.ModelHandlers[*syncBindingRecord]{
+		NewRecord: func() *syncBindingRecord {
+			return &syncBindingRecord{}
+		},
+		GetID: func(record *syncBindingRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *syncBindingRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *syncBindingRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func syncCheckpointHandlers() repository.ModelHandlers[*syncCheckpointRecord] {
+	return repository.ModelHandlers[*syncCheckpointRecord]{
+		NewRecord: func() *syncCheckpointRecord {
+			return &syncCheckpointRecord{}
+		},
+		GetID: func(record *syncCheckpointRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *syncCheckpointRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *syncCheckpointRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func identityBindingHandlers() repository.ModelHandlers[*identityBindingRecord] {
+	return repository.ModelHandlers[*identityBindingRecord]{
+		NewRecord: func() *identityBindingRecord {
+			return &identityBindingRecord{}
+		},
+		GetID: func(record *identityBindingRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *identityBindingRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *identityBindingRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func syncConflictHandlers() repository.ModelHandlers[*syncConflictRecord] {
+	return repository.ModelHandlers[*syncConflictRecord]{
+		NewRecord: func() *syncConflictRecord {
+			return &syncConflictRecord{}
+		},
+		GetID: func(record *syncConflictRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *syncConflictRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *syncConflictRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
+func syncChangeLogHandlers() repository.ModelHandlers[*syncChangeLogRecord] {
+	return repository.ModelHandlers[*syncChangeLogRecord]{
+		NewRecord: func() *syncChangeLogRecord {
+			return &syncChangeLogRecord{}
+		},
+		GetID: func(record *syncChangeLogRecord) uuid.UUID {
+			if record == nil {
+				return uuid.Nil
+			}
+			return parseUUID(record.ID)
+		},
+		SetID: func(record *syncChangeLogRecord, id uuid.UUID) {
+			if record == nil {
+				return
+			}
+			record.ID = id.String()
+		},
+		GetIdentifier: func() string {
+			return "id"
+		},
+		GetIdentifierValue: func(record *syncChangeLogRecord) string {
+			if record == nil {
+				return ""
+			}
+			return strings.TrimSpace(record.ID)
+		},
+	}
+}
+
 func parseUUID(value string) uuid.UUID {
 	parsed, err := uuid.Parse(strings.TrimSpace(value))
 	if err != nil {
@@ -0,0 +1,209 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type IdentityBindingStore struct {
+	db   *bun.DB
+	repo repository.Repository[*identityBindingRecord]
+}
+
+func NewIdentityBindingStore(db *bun.DB) (*IdentityBindingStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*identityBindingRecord](db, identityBindingHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid identity binding repository wiring: %w", err)
+		}
+	}
+	return &IdentityBindingStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+// Upsert creates the binding or replaces the internal match recorded for the
+// same sync binding and external id.
+func (s *IdentityBindingStore) Upsert(
+	ctx context.Context,
+	binding core.IdentityBinding,
+) (core.IdentityBinding, error) {
+	if s == nil || s.db == nil {
+		return core.IdentityBinding{}, fmt.Errorf("sqlstore: identity binding store is not configured")
+	}
+	binding.ID = strings.TrimSpace(binding.ID)
+	binding.ProviderID = strings.TrimSpace(binding.ProviderID)
+	binding.Scope = core.ScopeRef{Type: normalizeScopeType(binding.Scope.Type), ID: strings.TrimSpace(binding.Scope.ID)}
+	binding.ConnectionID = strings.TrimSpace(binding.ConnectionID)
+	binding.SyncBindingID = strings.TrimSpace(binding.SyncBindingID)
+	binding.SourceObject = strings.TrimSpace(binding.SourceObject)
+	binding.ExternalID = strings.TrimSpace(binding.ExternalID)
+	binding.InternalType = strings.TrimSpace(binding.InternalType)
+	binding.InternalID = strings.TrimSpace(binding.InternalID)
+	if binding.SyncBindingID == "" {
+		return core.IdentityBinding{}, fmt.Errorf("sqlstore: sync binding id is required")
+	}
+	if err := binding.Validate(); err != nil {
+		return core.IdentityBinding{}, err
+	}
+	now := time.Now().UTC()
+
+	var out core.IdentityBinding
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record, err := findIdentityBindingTx(ctx, tx, binding.SyncBindingID, binding.ExternalID)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			record = &identityBindingRecord{
+				ID:            binding.ID,
+				ProviderID:    binding.ProviderID,
+				ScopeType:     binding.Scope.Type,
+				ScopeID:       binding.Scope.ID,
+				ConnectionID:  binding.ConnectionID,
+				SyncBindingID: binding.SyncBindingID,
+				SourceObject:  binding.SourceObject,
+				ExternalID:    binding.ExternalID,
+				InternalType:  binding.InternalType,
+				InternalID:    binding.InternalID,
+				MatchKind:     string(binding.MatchKind),
+				Confidence:    binding.Confidence,
+				Metadata:      copyAnyMap(binding.Metadata),
+				OrphanedAt:    cloneTimePointer(binding.OrphanedAt),
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			if record.ID == "" {
+				record.ID = uuid.NewString()
+			}
+			if _, insertErr := tx.NewInsert().Model(record).Exec(ctx); insertErr != nil {
+				return insertErr
+			}
+			out = record.toDomain()
+			return nil
+		}
+
+		record.SourceObject = binding.SourceObject
+		record.InternalType = binding.InternalType
+		record.InternalID = binding.InternalID
+		record.MatchKind = string(binding.MatchKind)
+		record.Confidence = binding.Confidence
+		record.Metadata = copyAnyMap(binding.Metadata)
+		record.OrphanedAt = cloneTimePointer(binding.OrphanedAt)
+		record.UpdatedAt = now
+		if _, updateErr := tx.NewUpdate().Model(record).WherePK().Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.IdentityBinding{}, err
+	}
+	return out, nil
+}
+
+func (s *IdentityBindingStore) GetByExternalID(
+	ctx context.Context,
+	syncBindingID string,
+	externalID string,
+) (core.IdentityBinding, bool, error) {
+	if s == nil || s.db == nil {
+		return core.IdentityBinding{}, false, fmt.Errorf("sqlstore: identity binding store is not configured")
+	}
+	record, err := findIdentityBindingTx(ctx, s.db, strings.TrimSpace(syncBindingID), strings.TrimSpace(externalID))
+	if err != nil {
+		return core.IdentityBinding{}, false, err
+	}
+	if record == nil {
+		return core.IdentityBinding{}, false, nil
+	}
+	return record.toDomain(), true, nil
+}
+
+func (s *IdentityBindingStore) ListByInternalID(
+	ctx context.Context,
+	syncBindingID string,
+	internalType string,
+	internalID string,
+) ([]core.IdentityBinding, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: identity binding store is not configured")
+	}
+	syncBindingID = strings.TrimSpace(syncBindingID)
+	internalID = strings.TrimSpace(internalID)
+	if syncBindingID == "" || internalID == "" {
+		return nil, fmt.Errorf("sqlstore: sync binding id and internal id are required")
+	}
+	records, _, err := s.repo.List(ctx,
+		repository.SelectBy("sync_binding_id", "=", syncBindingID),
+		repository.SelectBy("internal_type", "=", strings.TrimSpace(internalType)),
+		repository.SelectBy("internal_id", "=", internalID),
+		repository.OrderBy("created_at ASC"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.IdentityBinding, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
+func findIdentityBindingTx(
+	ctx context.Context,
+	db bun.IDB,
+	syncBindingID string,
+	externalID string,
+) (*identityBindingRecord, error) {
+	record := &identityBindingRecord{}
+	err := db.NewSelect().
+		Model(record).
+		Where("?TableAlias.sync_binding_id = ?", syncBindingID).
+		Where("?TableAlias.external_id = ?", externalID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *identityBindingRecord) toDomain() core.IdentityBinding {
+	if r == nil {
+		return core.IdentityBinding{}
+	}
+	return core.IdentityBinding{
+		ID:            r.ID,
+		ProviderID:    r.ProviderID,
+		Scope:         core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:  r.ConnectionID,
+		SyncBindingID: r.SyncBindingID,
+		SourceObject:  r.SourceObject,
+		ExternalID:    r.ExternalID,
+		InternalType:  r.InternalType,
+		InternalID:    r.InternalID,
+		MatchKind:     core.IdentityBindingMatchKind(r.MatchKind),
+		Confidence:    r.Confidence,
+		Metadata:      copyAnyMap(r.Metadata),
+		OrphanedAt:    cloneTimePointer(r.OrphanedAt),
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
@@ -66,6 +66,12 @@ func (s *InstallationStore) Upsert(ctx context.Context, in core.UpsertInstallati
 		if err != nil {
 			return err
 		}
+		// A reinstall after an uninstall gets a fresh row; the partial unique
+		// index only covers active installations.
+		if record != nil && record.Status == string(core.InstallationStatusUninstalled) &&
+			status == core.InstallationStatusActive {
+			record = nil
+		}
 		if record == nil {
 			if status != core.InstallationStatusActive {
 				return fmt.Errorf("sqlstore: installation must be created with status active")
@@ -0,0 +1,538 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// Deduplication policies understood by JobQueueStore, matching go-job's.
+const (
+	JobDedupPolicyIgnore  = "ignore"
+	JobDedupPolicyDrop    = "drop"
+	JobDedupPolicyMerge   = "merge"
+	JobDedupPolicyReplace = "replace"
+
+	defaultJobQueueVisibilityTimeout = 5 * time.Minute
+	defaultJobQueueMaxAttempts       = 5
+)
+
+type JobQueueConfig struct {
+	// VisibilityTimeout is how long a dequeued job stays leased before another
+	// worker may claim it again.
+	VisibilityTimeout time.Duration
+	// MaxAttempts dead-letters a job whose lease expired on its last attempt,
+	// so a job that keeps crashing its worker is not redelivered forever.
+	// Negative disables the limit.
+	MaxAttempts int
+}
+
+func DefaultJobQueueConfig() JobQueueConfig {
+	return JobQueueConfig{
+		VisibilityTimeout: defaultJobQueueVisibilityTimeout,
+		MaxAttempts:       defaultJobQueueMaxAttempts,
+	}
+}
+
+// JobQueueStore is a database-backed job queue for apps that do not run
+// go-job. Dequeued jobs are leased for the visibility timeout; jobs that are
+// neither acked nor nacked before the lease expires are delivered again.
+// Postgres claims rows with FOR UPDATE SKIP LOCKED so workers do not contend
+// for the same job; SQLite relies on its single writer and the lease columns.
+type JobQueueStore struct {
+	db     *bun.DB
+	config JobQueueConfig
+	Now    func() time.Time
+}
+
+func NewJobQueueStore(db *bun.DB, config JobQueueConfig) (*JobQueueStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	defaults := DefaultJobQueueConfig()
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = defaults.VisibilityTimeout
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	return &JobQueueStore{
+		db:     db,
+		config: config,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
+}
+
+func (s *JobQueueStore) Enqueue(ctx context.Context, msg *core.JobExecutionMessage) (core.JobEnqueueReceipt, error) {
+	return s.enqueue(ctx, msg, time.Time{})
+}
+
+func (s *JobQueueStore) EnqueueAt(
+	ctx context.Context,
+	msg *core.JobExecutionMessage,
+	at time.Time,
+) (core.JobEnqueueReceipt, error) {
+	return s.enqueue(ctx, msg, at)
+}
+
+func (s *JobQueueStore) EnqueueAfter(
+	ctx context.Context,
+	msg *core.JobExecutionMessage,
+	delay time.Duration,
+) (core.JobEnqueueReceipt, error) {
+	if delay < 0 {
+		delay = 0
+	}
+	return s.enqueue(ctx, msg, s.now().Add(delay))
+}
+
+// enqueue inserts a job due at runAt, or now when runAt is zero. Messages with
+// an idempotency key are deduplicated against jobs that have not finished:
+// drop returns the existing job, merge folds the parameters into a job that
+// has not started yet, and replace cancels the pending job in favor of the new
+// one.
+func (s *JobQueueStore) enqueue(
+	ctx context.Context,
+	msg *core.JobExecutionMessage,
+	runAt time.Time,
+) (core.JobEnqueueReceipt, error) {
+	if s == nil || s.db == nil {
+		return core.JobEnqueueReceipt{}, fmt.Errorf("sqlstore: job queue store is not configured")
+	}
+	if msg == nil {
+		return core.JobEnqueueReceipt{}, fmt.Errorf("sqlstore: execution message is required")
+	}
+	scriptPath := strings.TrimSpace(msg.ScriptPath)
+	if scriptPath == "" {
+		return core.JobEnqueueReceipt{}, fmt.Errorf("sqlstore: job script path is required")
+	}
+	policy := strings.ToLower(strings.TrimSpace(msg.DedupPolicy))
+	switch policy {
+	case "", JobDedupPolicyIgnore, JobDedupPolicyDrop, JobDedupPolicyMerge, JobDedupPolicyReplace:
+	default:
+		return core.JobEnqueueReceipt{}, fmt.Errorf("sqlstore: unsupported job dedup policy %q", msg.DedupPolicy)
+	}
+	jobID := strings.TrimSpace(msg.JobID)
+	if jobID == "" {
+		jobID = scriptPath
+	}
+	key := strings.TrimSpace(msg.IdempotencyKey)
+	now := s.now()
+	if runAt.IsZero() || runAt.Before(now) {
+		runAt = now
+	}
+	record := &jobQueueRecord{
+		ID:             uuid.NewString(),
+		JobID:          jobID,
+		ScriptPath:     scriptPath,
+		Parameters:     copyAnyMap(msg.Parameters),
+		IdempotencyKey: key,
+		DedupPolicy:    policy,
+		State:          string(core.JobDispatchStateAccepted),
+		RunAt:          runAt.UTC(),
+		EnqueuedAt:     now,
+		UpdatedAt:      now,
+	}
+
+	deduped := key != "" && (policy == JobDedupPolicyDrop || policy == JobDedupPolicyMerge)
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if key != "" && policy == JobDedupPolicyReplace {
+			if _, err := tx.NewUpdate().
+				Model((*jobQueueRecord)(nil)).
+				Set("state = ?", core.JobDispatchStateCanceled).
+				Set("terminal_reason = ?", "replaced by dispatch "+record.ID).
+				Set("updated_at = ?", now).
+				Where("idempotency_key = ?", key).
+				Where("state IN (?)", bun.In([]string{
+					string(core.JobDispatchStateAccepted),
+					string(core.JobDispatchStateRetrying),
+				})).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if !deduped {
+			_, err := tx.NewInsert().Model(record).Exec(ctx)
+			return err
+		}
+		// A concurrent enqueue may claim the key between the lookup and the
+		// insert; the unique index turns that into a skipped insert, after
+		// which the winner is visible to the next lookup.
+		for attempt := 0; attempt < 3; attempt++ {
+			found, err := s.dedupe(ctx, tx, record, policy, now)
+			if err != nil || found {
+				return err
+			}
+			result, err := tx.NewInsert().
+				Model(record).
+				On("CONFLICT (idempotency_key) WHERE " + jobQueueDedupPredicate(policy) + " DO NOTHING").
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			if rows, _ := result.RowsAffected(); rows > 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("sqlstore: job idempotency key %q is contended", key)
+	})
+	if err != nil {
+		return core.JobEnqueueReceipt{}, err
+	}
+	return core.JobEnqueueReceipt{DispatchID: record.ID, EnqueuedAt: record.EnqueuedAt.UTC()}, nil
+}
+
+// dedupe applies the drop or merge policy to the job that already holds the
+// record's idempotency key. It reports whether such a job was found, in which
+// case record is replaced by it.
+func (s *JobQueueStore) dedupe(
+	ctx context.Context,
+	tx bun.Tx,
+	record *jobQueueRecord,
+	policy string,
+	now time.Time,
+) (bool, error) {
+	states := jobQueueActiveStates()
+	if policy == JobDedupPolicyMerge {
+		states = jobQueuePendingStates()
+	}
+	var existing []jobQueueRecord
+	if err := tx.NewSelect().
+		Model(&existing).
+		Where("?TableAlias.idempotency_key = ?", record.IdempotencyKey).
+		Where("?TableAlias.state IN (?)", bun.In(states)).
+		OrderExpr("?TableAlias.enqueued_at ASC").
+		Limit(1).
+		Scan(ctx); err != nil {
+		return false, err
+	}
+	if len(existing) == 0 {
+		return false, nil
+	}
+	if policy == JobDedupPolicyMerge {
+		merged := copyAnyMap(existing[0].Parameters)
+		for field, value := range record.Parameters {
+			merged[field] = value
+		}
+		if _, err := tx.NewUpdate().
+			Model((*jobQueueRecord)(nil)).
+			Set("parameters = ?", merged).
+			Set("updated_at = ?", now).
+			Where("id = ?", existing[0].ID).
+			Exec(ctx); err != nil {
+			return false, err
+		}
+	}
+	*record = existing[0]
+	return true, nil
+}
+
+func (s *JobQueueStore) GetDispatchStatus(ctx context.Context, dispatchID string) (core.JobDispatchStatus, error) {
+	if s == nil || s.db == nil {
+		return core.JobDispatchStatus{}, fmt.Errorf("sqlstore: job queue store is not configured")
+	}
+	dispatchID = strings.TrimSpace(dispatchID)
+	if dispatchID == "" {
+		return core.JobDispatchStatus{}, fmt.Errorf("sqlstore: dispatch id is required")
+	}
+	record := &jobQueueRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", dispatchID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.JobDispatchStatus{}, fmt.Errorf("sqlstore: job dispatch %q not found", dispatchID)
+		}
+		return core.JobDispatchStatus{}, err
+	}
+	enqueuedAt := record.EnqueuedAt.UTC()
+	updatedAt := record.UpdatedAt.UTC()
+	status := core.JobDispatchStatus{
+		DispatchID:     record.ID,
+		State:          core.JobDispatchState(record.State),
+		Attempt:        record.Attempts,
+		EnqueuedAt:     &enqueuedAt,
+		UpdatedAt:      &updatedAt,
+		TerminalReason: record.TerminalReason,
+	}
+	if jobQueuePending(record.State) {
+		nextRunAt := record.RunAt.UTC()
+		status.NextRunAt = &nextRunAt
+	}
+	return status, nil
+}
+
+// Dequeue leases the next due job, or a running job whose lease expired. It
+// returns nil without error when no job is due.
+func (s *JobQueueStore) Dequeue(ctx context.Context) (core.JobDelivery, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: job queue store is not configured")
+	}
+	now := s.now()
+	token := uuid.NewString()
+	lockClause := ""
+	if s.db.Dialect().Name() == dialect.PG {
+		lockClause = "FOR UPDATE SKIP LOCKED"
+	}
+	var records []jobQueueRecord
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if s.config.MaxAttempts > 0 {
+			if _, err := tx.NewUpdate().
+				Model((*jobQueueRecord)(nil)).
+				Set("state = ?", core.JobDispatchStateDeadLetter).
+				Set("lease_token = ''").
+				Set("lease_expires_at = NULL").
+				Set("terminal_reason = ?", fmt.Sprintf("lease expired after %d attempts", s.config.MaxAttempts)).
+				Set("updated_at = ?", now).
+				Where("state = ?", core.JobDispatchStateRunning).
+				Where("lease_expires_at <= ?", now).
+				Where("attempts >= ?", s.config.MaxAttempts).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		query := `
+WITH claimed AS (
+	SELECT id
+	FROM service_job_queue
+	WHERE (state IN (?, ?) AND run_at <= ?)
+	   OR (state = ? AND lease_expires_at <= ?)
+	ORDER BY run_at ASC
+	LIMIT 1
+	` + lockClause + `
+)
+UPDATE service_job_queue
+SET state = ?, attempts = attempts + 1, lease_token = ?, lease_expires_at = ?, updated_at = ?
+WHERE id IN (SELECT id FROM claimed)
+  AND ((state IN (?, ?) AND run_at <= ?) OR (state = ? AND lease_expires_at <= ?))
+RETURNING
+	id,
+	job_id,
+	script_path,
+	parameters,
+	idempotency_key,
+	dedup_policy,
+	state,
+	attempts,
+	run_at,
+	lease_token,
+	lease_expires_at,
+	terminal_reason,
+	enqueued_at,
+	updated_at
+`
+		return tx.NewRaw(
+			query,
+			core.JobDispatchStateAccepted,
+			core.JobDispatchStateRetrying,
+			now,
+			core.JobDispatchStateRunning,
+			now,
+			core.JobDispatchStateRunning,
+			token,
+			now.Add(s.config.VisibilityTimeout),
+			now,
+			core.JobDispatchStateAccepted,
+			core.JobDispatchStateRetrying,
+			now,
+			core.JobDispatchStateRunning,
+			now,
+		).Scan(ctx, &records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &jobQueueDelivery{store: s, record: records[0]}, nil
+}
+
+// settle finishes the leased run of a job. It fails when the lease expired and
+// the job was claimed again, so a slow worker cannot overwrite the newer run.
+func (s *JobQueueStore) settle(
+	ctx context.Context,
+	record jobQueueRecord,
+	state core.JobDispatchState,
+	runAt time.Time,
+	reason string,
+) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: job queue store is not configured")
+	}
+	return s.settleIn(ctx, s.db, record, state, runAt, reason)
+}
+
+// retry schedules another run of a leased job. A merge job may have gained a
+// pending duplicate while it ran, and the unique merge index allows only one
+// of them to wait; the retry is then folded into that duplicate, whose
+// parameters win, and the running job is canceled.
+func (s *JobQueueStore) retry(ctx context.Context, record jobQueueRecord, runAt time.Time) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: job queue store is not configured")
+	}
+	if record.IdempotencyKey == "" || record.DedupPolicy != JobDedupPolicyMerge {
+		return s.settle(ctx, record, core.JobDispatchStateRetrying, runAt, "")
+	}
+	var err error
+	// An enqueue that claims the key after the lookup makes the retry violate
+	// the unique index; the next attempt finds it and merges instead.
+	for attempt := 0; attempt < 3; attempt++ {
+		err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var pending []jobQueueRecord
+			if err := tx.NewSelect().
+				Model(&pending).
+				Where("?TableAlias.idempotency_key = ?", record.IdempotencyKey).
+				Where("?TableAlias.dedup_policy = ?", JobDedupPolicyMerge).
+				Where("?TableAlias.state IN (?)", bun.In(jobQueuePendingStates())).
+				Where("?TableAlias.id <> ?", record.ID).
+				Limit(1).
+				Scan(ctx); err != nil {
+				return err
+			}
+			if len(pending) == 0 {
+				return s.settleIn(ctx, tx, record, core.JobDispatchStateRetrying, runAt, "")
+			}
+			merged := copyAnyMap(record.Parameters)
+			for field, value := range pending[0].Parameters {
+				merged[field] = value
+			}
+			if _, err := tx.NewUpdate().
+				Model((*jobQueueRecord)(nil)).
+				Set("parameters = ?", merged).
+				Set("updated_at = ?", s.now()).
+				Where("id = ?", pending[0].ID).
+				Exec(ctx); err != nil {
+				return err
+			}
+			return s.settleIn(ctx, tx, record, core.JobDispatchStateCanceled, time.Time{}, "merged into dispatch "+pending[0].ID)
+		})
+		if err == nil || !isUniqueViolation(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *JobQueueStore) settleIn(
+	ctx context.Context,
+	db bun.IDB,
+	record jobQueueRecord,
+	state core.JobDispatchState,
+	runAt time.Time,
+	reason string,
+) error {
+	query := db.NewUpdate().
+		Model((*jobQueueRecord)(nil)).
+		Set("state = ?", state).
+		Set("lease_token = ''").
+		Set("lease_expires_at = NULL").
+		Set("terminal_reason = ?", reason).
+		Set("updated_at = ?", s.now()).
+		Where("id = ?", record.ID).
+		Where("lease_token = ?", record.LeaseToken).
+		Where("state = ?", core.JobDispatchStateRunning)
+	if !runAt.IsZero() {
+		query = query.Set("run_at = ?", runAt.UTC())
+	}
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("sqlstore: job dispatch %q lease was lost", record.ID)
+	}
+	return nil
+}
+
+func (s *JobQueueStore) now() time.Time {
+	if s != nil && s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+type jobQueueDelivery struct {
+	store  *JobQueueStore
+	record jobQueueRecord
+}
+
+func (d *jobQueueDelivery) Message() *core.JobExecutionMessage {
+	return &core.JobExecutionMessage{
+		JobID:          d.record.JobID,
+		ScriptPath:     d.record.ScriptPath,
+		Parameters:     copyAnyMap(d.record.Parameters),
+		IdempotencyKey: d.record.IdempotencyKey,
+		DedupPolicy:    d.record.DedupPolicy,
+	}
+}
+
+// Attempts counts the deliveries of the job, including this one.
+func (d *jobQueueDelivery) Attempts() int {
+	return d.record.Attempts
+}
+
+func (d *jobQueueDelivery) Ack(ctx context.Context) error {
+	return d.store.settle(ctx, d.record, core.JobDispatchStateSucceeded, time.Time{}, "")
+}
+
+func (d *jobQueueDelivery) Nack(ctx context.Context, opts core.JobNackOptions) error {
+	reason := strings.TrimSpace(opts.Reason)
+	switch opts.Disposition {
+	case "", core.JobNackDispositionRetry:
+		delay := opts.Delay
+		if delay < 0 {
+			delay = 0
+		}
+		return d.store.retry(ctx, d.record, d.store.now().Add(delay))
+	case core.JobNackDispositionDeadLetter:
+		return d.store.settle(ctx, d.record, core.JobDispatchStateDeadLetter, time.Time{}, reason)
+	case core.JobNackDispositionFailed:
+		return d.store.settle(ctx, d.record, core.JobDispatchStateFailed, time.Time{}, reason)
+	case core.JobNackDispositionCanceled:
+		return d.store.settle(ctx, d.record, core.JobDispatchStateCanceled, time.Time{}, reason)
+	default:
+		return fmt.Errorf("sqlstore: unsupported nack disposition %q", opts.Disposition)
+	}
+}
+
+func jobQueueActiveStates() []string {
+	return []string{
+		string(core.JobDispatchStateAccepted),
+		string(core.JobDispatchStateRetrying),
+		string(core.JobDispatchStateRunning),
+	}
+}
+
+func jobQueuePendingStates() []string {
+	return []string{
+		string(core.JobDispatchStateAccepted),
+		string(core.JobDispatchStateRetrying),
+	}
+}
+
+// jobQueueDedupPredicate matches the partial unique index that holds the
+// idempotency keys of the policy: drop keeps one unfinished job per key,
+// merge keeps one job per key that has not started.
+func jobQueueDedupPredicate(policy string) string {
+	if policy == JobDedupPolicyMerge {
+		return "idempotency_key <> '' AND dedup_policy = 'merge' AND state IN ('accepted', 'retrying')"
+	}
+	return "idempotency_key <> '' AND dedup_policy = 'drop' AND state IN ('accepted', 'retrying', 'running')"
+}
+
+func jobQueuePending(state string) bool {
+	return state == string(core.JobDispatchStateAccepted) || state == string(core.JobDispatchStateRetrying)
+}
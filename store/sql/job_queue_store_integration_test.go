@@ -0,0 +1,271 @@
+package sqlstore_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestJobQueueStore_LeasesDelaysAndDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	factory := sqlstore.NewRepositoryFactory(sqlstore.WithJobQueueConfig(sqlstore.JobQueueConfig{
+		VisibilityTimeout: time.Minute,
+		MaxAttempts:       2,
+	}))
+	if _, err := factory.BuildStores(client); err != nil {
+		t.Fatalf("build stores: %v", err)
+	}
+	queue := factory.JobQueueStore()
+	if queue == nil {
+		t.Fatalf("expected job queue store to be wired")
+	}
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	queue.Now = func() time.Time { return now }
+
+	delayed, err := queue.EnqueueAfter(ctx, &core.JobExecutionMessage{
+		JobID:      "refresh",
+		ScriptPath: "services.refresh",
+		Parameters: map[string]any{"connection_id": "conn_1"},
+	}, time.Hour)
+	if err != nil {
+		t.Fatalf("enqueue after: %v", err)
+	}
+	if delivery, err := queue.Dequeue(ctx); err != nil || delivery != nil {
+		t.Fatalf("expected delayed job not to be due, got %v %v", delivery, err)
+	}
+	status, err := queue.GetDispatchStatus(ctx, delayed.DispatchID)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.State != core.JobDispatchStateAccepted || status.NextRunAt == nil || !status.NextRunAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected delayed status %+v", status)
+	}
+
+	now = now.Add(time.Hour)
+	delivery, err := queue.Dequeue(ctx)
+	if err != nil || delivery == nil {
+		t.Fatalf("expected due job, got %v %v", delivery, err)
+	}
+	if msg := delivery.Message(); msg.ScriptPath != "services.refresh" || msg.Parameters["connection_id"] != "conn_1" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if again, _ := queue.Dequeue(ctx); again != nil {
+		t.Fatalf("expected leased job to stay invisible")
+	}
+	if err := delivery.Nack(ctx, core.JobNackOptions{Disposition: core.JobNackDispositionRetry, Delay: time.Minute}); err != nil {
+		t.Fatalf("nack: %v", err)
+	}
+	status, _ = queue.GetDispatchStatus(ctx, delayed.DispatchID)
+	if status.State != core.JobDispatchStateRetrying || status.Attempt != 1 {
+		t.Fatalf("expected retrying status, got %+v", status)
+	}
+
+	now = now.Add(time.Minute)
+	retried, err := queue.Dequeue(ctx)
+	if err != nil || retried == nil {
+		t.Fatalf("expected retried job, got %v %v", retried, err)
+	}
+	if counter, ok := retried.(interface{ Attempts() int }); !ok || counter.Attempts() != 2 {
+		t.Fatalf("expected second attempt")
+	}
+	now = now.Add(2 * time.Minute)
+	if expired, _ := queue.Dequeue(ctx); expired != nil {
+		t.Fatalf("expected job with expired lease on its last attempt to be dead-lettered")
+	}
+	if err := retried.Ack(ctx); err == nil {
+		t.Fatalf("expected ack after lost lease to fail")
+	}
+	status, _ = queue.GetDispatchStatus(ctx, delayed.DispatchID)
+	if status.State != core.JobDispatchStateDeadLetter || status.TerminalReason == "" {
+		t.Fatalf("expected dead-lettered status, got %+v", status)
+	}
+
+	if _, err := queue.GetDispatchStatus(ctx, "missing"); err == nil {
+		t.Fatalf("expected missing dispatch to fail")
+	}
+}
+
+func TestJobQueueStore_HonorsDedupPolicies(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	queue, err := sqlstore.NewJobQueueStore(client.DB(), sqlstore.JobQueueConfig{})
+	if err != nil {
+		t.Fatalf("new job queue store: %v", err)
+	}
+	enqueue := func(policy string, params map[string]any) core.JobEnqueueReceipt {
+		t.Helper()
+		receipt, err := queue.Enqueue(ctx, &core.JobExecutionMessage{
+			ScriptPath:     "services.sync.incremental",
+			Parameters:     params,
+			IdempotencyKey: "binding_1",
+			DedupPolicy:    policy,
+		})
+		if err != nil {
+			t.Fatalf("enqueue %s: %v", policy, err)
+		}
+		return receipt
+	}
+
+	first := enqueue(sqlstore.JobDedupPolicyDrop, map[string]any{"page_size": 10})
+	if dropped := enqueue(sqlstore.JobDedupPolicyDrop, nil); dropped.DispatchID != first.DispatchID {
+		t.Fatalf("expected drop to return the pending dispatch")
+	}
+	if merged := enqueue(sqlstore.JobDedupPolicyMerge, map[string]any{"max_pages": 2}); merged.DispatchID != first.DispatchID {
+		t.Fatalf("expected merge to reuse the pending dispatch")
+	}
+	replaced := enqueue(sqlstore.JobDedupPolicyReplace, map[string]any{"page_size": 50})
+	if replaced.DispatchID == first.DispatchID {
+		t.Fatalf("expected replace to create a new dispatch")
+	}
+	status, _ := queue.GetDispatchStatus(ctx, first.DispatchID)
+	if status.State != core.JobDispatchStateCanceled {
+		t.Fatalf("expected replaced dispatch to be canceled, got %+v", status)
+	}
+	if ignored := enqueue(sqlstore.JobDedupPolicyIgnore, nil); ignored.DispatchID == replaced.DispatchID {
+		t.Fatalf("expected ignore to always enqueue")
+	}
+
+	delivery, err := queue.Dequeue(ctx)
+	if err != nil || delivery == nil {
+		t.Fatalf("dequeue: %v %v", delivery, err)
+	}
+	if delivery.Message().Parameters["page_size"] != float64(50) {
+		t.Fatalf("expected replacement to be delivered first, got %+v", delivery.Message())
+	}
+	if err := delivery.Ack(ctx); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	status, _ = queue.GetDispatchStatus(ctx, replaced.DispatchID)
+	if status.State != core.JobDispatchStateSucceeded || status.NextRunAt != nil {
+		t.Fatalf("expected succeeded status, got %+v", status)
+	}
+
+	if _, err := queue.Enqueue(ctx, &core.JobExecutionMessage{ScriptPath: "x", DedupPolicy: "coalesce"}); err == nil {
+		t.Fatalf("expected unknown dedup policy to fail")
+	}
+}
+
+func TestJobQueueStore_ConcurrentDedupEnqueuesShareOneJob(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	queue, err := sqlstore.NewJobQueueStore(client.DB(), sqlstore.JobQueueConfig{})
+	if err != nil {
+		t.Fatalf("new job queue store: %v", err)
+	}
+	for _, policy := range []string{sqlstore.JobDedupPolicyDrop, sqlstore.JobDedupPolicyMerge} {
+		key := "binding_" + policy
+		const workers = 16
+		receipts := make([]core.JobEnqueueReceipt, workers)
+		errs := make([]error, workers)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				receipts[i], errs[i] = queue.Enqueue(ctx, &core.JobExecutionMessage{
+					ScriptPath:     "services.sync.incremental",
+					Parameters:     map[string]any{fmt.Sprintf("page_%d", i): i},
+					IdempotencyKey: key,
+					DedupPolicy:    policy,
+				})
+			}(i)
+		}
+		wg.Wait()
+		for i := range receipts {
+			if errs[i] != nil {
+				t.Fatalf("%s enqueue %d: %v", policy, i, errs[i])
+			}
+			if receipts[i].DispatchID != receipts[0].DispatchID {
+				t.Fatalf("expected concurrent %s enqueues to share one dispatch", policy)
+			}
+		}
+		var active int
+		if err := client.DB().NewRaw(
+			"SELECT COUNT(*) FROM service_job_queue WHERE idempotency_key = ? AND state IN ('accepted', 'retrying', 'running')",
+			key,
+		).Scan(ctx, &active); err != nil {
+			t.Fatalf("count active jobs: %v", err)
+		}
+		if active != 1 {
+			t.Fatalf("expected one active %s job, got %d", policy, active)
+		}
+
+		if _, err := client.DB().ExecContext(ctx, `
+INSERT INTO service_job_queue (id, job_id, script_path, idempotency_key, dedup_policy, state, run_at)
+VALUES (?, 'sync', 'services.sync.incremental', ?, ?, 'accepted', CURRENT_TIMESTAMP)`,
+			"dup_"+policy, key, policy,
+		); err == nil {
+			t.Fatalf("expected the unique index to reject a second pending %s job", policy)
+		}
+	}
+}
+
+func TestJobQueueStore_NackFoldsRetryIntoPendingMergeDuplicate(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	queue, err := sqlstore.NewJobQueueStore(client.DB(), sqlstore.JobQueueConfig{})
+	if err != nil {
+		t.Fatalf("new job queue store: %v", err)
+	}
+	enqueue := func(params map[string]any) core.JobEnqueueReceipt {
+		t.Helper()
+		receipt, err := queue.Enqueue(ctx, &core.JobExecutionMessage{
+			ScriptPath:     "services.sync.incremental",
+			Parameters:     params,
+			IdempotencyKey: "binding_1",
+			DedupPolicy:    sqlstore.JobDedupPolicyMerge,
+		})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		return receipt
+	}
+
+	running := enqueue(map[string]any{"page_size": 10, "cursor": "c1"})
+	delivery, err := queue.Dequeue(ctx)
+	if err != nil || delivery == nil {
+		t.Fatalf("dequeue: %v %v", delivery, err)
+	}
+	pending := enqueue(map[string]any{"page_size": 50})
+	if pending.DispatchID == running.DispatchID {
+		t.Fatalf("expected a running merge job not to absorb a new dispatch")
+	}
+
+	if err := delivery.Nack(ctx, core.JobNackOptions{Disposition: core.JobNackDispositionRetry}); err != nil {
+		t.Fatalf("nack with pending merge duplicate: %v", err)
+	}
+	status, _ := queue.GetDispatchStatus(ctx, running.DispatchID)
+	if status.State != core.JobDispatchStateCanceled || status.TerminalReason != "merged into dispatch "+pending.DispatchID {
+		t.Fatalf("expected retried job to fold into the pending duplicate, got %+v", status)
+	}
+
+	next, err := queue.Dequeue(ctx)
+	if err != nil || next == nil {
+		t.Fatalf("dequeue merged job: %v %v", next, err)
+	}
+	params := next.Message().Parameters
+	if params["page_size"] != float64(50) || params["cursor"] != "c1" {
+		t.Fatalf("expected merged parameters with the duplicate's values winning, got %+v", params)
+	}
+	if err := next.Nack(ctx, core.JobNackOptions{Disposition: core.JobNackDispositionRetry}); err != nil {
+		t.Fatalf("nack without duplicate: %v", err)
+	}
+	status, _ = queue.GetDispatchStatus(ctx, pending.DispatchID)
+	if status.State != core.JobDispatchStateRetrying {
+		t.Fatalf("expected lone merge job to retry, got %+v", status)
+	}
+}
@@ -0,0 +1,225 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// LeaderLeaseStore keeps one row per lease name. Every change is a
+// conditional update on the holder and fencing token the caller last saw, so
+// concurrent candidates race on a single row and at most one of them wins.
+// Expiry is judged by the caller's clock, which replicas must keep in sync to
+// within a small fraction of the lease TTL.
+type LeaderLeaseStore struct {
+	db  *bun.DB
+	Now func() time.Time
+}
+
+func NewLeaderLeaseStore(db *bun.DB) (*LeaderLeaseStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	return &LeaderLeaseStore{
+		db: db,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
+}
+
+func (s *LeaderLeaseStore) AcquireLeaderLease(
+	ctx context.Context,
+	name string,
+	holderID string,
+	ttl time.Duration,
+) (core.LeaderLease, bool, error) {
+	if s == nil || s.db == nil {
+		return core.LeaderLease{}, false, fmt.Errorf("sqlstore: leader lease store is not configured")
+	}
+	name = strings.TrimSpace(name)
+	holderID = strings.TrimSpace(holderID)
+	if name == "" || holderID == "" {
+		return core.LeaderLease{}, false, fmt.Errorf("sqlstore: leader lease name and holder id are required")
+	}
+	if ttl <= 0 {
+		return core.LeaderLease{}, false, fmt.Errorf("sqlstore: leader lease ttl must be positive")
+	}
+	now := s.now()
+	expiresAt := now.Add(ttl)
+
+	current := &leaderLeaseRecord{}
+	err := s.db.NewSelect().Model(current).Where("?TableAlias.name = ?", name).Limit(1).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		record := &leaderLeaseRecord{
+			Name:         name,
+			HolderID:     holderID,
+			FencingToken: 1,
+			AcquiredAt:   now,
+			ExpiresAt:    expiresAt,
+			UpdatedAt:    now,
+		}
+		result, insertErr := s.db.NewInsert().Model(record).On("CONFLICT (name) DO NOTHING").Exec(ctx)
+		if insertErr != nil {
+			return core.LeaderLease{}, false, insertErr
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			// Another candidate created the lease first.
+			return core.LeaderLease{}, false, nil
+		}
+		return record.toDomain(), true, nil
+	}
+	if err != nil {
+		return core.LeaderLease{}, false, err
+	}
+
+	live := current.ExpiresAt.After(now)
+	if live && current.HolderID != holderID {
+		return current.toDomain(), false, nil
+	}
+	next := *current
+	next.ExpiresAt = expiresAt
+	next.UpdatedAt = now
+	if !live {
+		// Taking over an expired lease, even one this holder used to own,
+		// fences off anything still running under the old token.
+		next.HolderID = holderID
+		next.FencingToken = current.FencingToken + 1
+		next.AcquiredAt = now
+	}
+	updated, err := s.compareAndSwap(ctx, current, &next)
+	if err != nil || !updated {
+		return core.LeaderLease{}, false, err
+	}
+	return next.toDomain(), true, nil
+}
+
+func (s *LeaderLeaseStore) RenewLeaderLease(ctx context.Context, lease core.LeaderLease, ttl time.Duration) (core.LeaderLease, error) {
+	if s == nil || s.db == nil {
+		return core.LeaderLease{}, fmt.Errorf("sqlstore: leader lease store is not configured")
+	}
+	if ttl <= 0 {
+		return core.LeaderLease{}, fmt.Errorf("sqlstore: leader lease ttl must be positive")
+	}
+	now := s.now()
+	if !lease.ExpiresAt.After(now) {
+		return core.LeaderLease{}, fmt.Errorf("%w: lease %q expired", core.ErrLeadershipLost, lease.Name)
+	}
+	current := newLeaderLeaseRecord(lease)
+	next := *current
+	next.ExpiresAt = now.Add(ttl)
+	next.UpdatedAt = now
+	updated, err := s.compareAndSwap(ctx, current, &next)
+	if err != nil {
+		return core.LeaderLease{}, err
+	}
+	if !updated {
+		return core.LeaderLease{}, fmt.Errorf("%w: lease %q changed hands", core.ErrLeadershipLost, lease.Name)
+	}
+	return next.toDomain(), nil
+}
+
+// ReleaseLeaderLease expires the lease instead of deleting it, so the next
+// holder continues from the same fencing token.
+func (s *LeaderLeaseStore) ReleaseLeaderLease(ctx context.Context, lease core.LeaderLease) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: leader lease store is not configured")
+	}
+	now := s.now()
+	current := newLeaderLeaseRecord(lease)
+	next := *current
+	next.ExpiresAt = now
+	next.UpdatedAt = now
+	updated, err := s.compareAndSwap(ctx, current, &next)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: lease %q changed hands", core.ErrLeadershipLost, lease.Name)
+	}
+	return nil
+}
+
+func (s *LeaderLeaseStore) compareAndSwap(ctx context.Context, current *leaderLeaseRecord, next *leaderLeaseRecord) (bool, error) {
+	result, err := s.db.NewUpdate().
+		Model((*leaderLeaseRecord)(nil)).
+		Set("holder_id = ?", next.HolderID).
+		Set("fencing_token = ?", next.FencingToken).
+		Set("acquired_at = ?", next.AcquiredAt).
+		Set("expires_at = ?", next.ExpiresAt).
+		Set("updated_at = ?", next.UpdatedAt).
+		Where("name = ?", current.Name).
+		Where("holder_id = ?", current.HolderID).
+		Where("fencing_token = ?", current.FencingToken).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// fenceLeaderLease fails with core.ErrLeadershipLost when ctx carries a
+// leader lease that has since changed hands. Stores call it first in the
+// transaction of a write that may run under RunAsLeader. On Postgres the lease
+// row stays share-locked until the write commits, so a takeover waits for the
+// write instead of overlapping it.
+func fenceLeaderLease(ctx context.Context, db bun.IDB) error {
+	lease, ok := core.LeaderLeaseFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	current := &leaderLeaseRecord{}
+	query := db.NewSelect().Model(current).Where("?TableAlias.name = ?", lease.Name).Limit(1)
+	if db.Dialect().Name() == dialect.PG {
+		query = query.For("SHARE")
+	}
+	err := query.Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: lease %q not found", core.ErrLeadershipLost, lease.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if current.HolderID != lease.HolderID || current.FencingToken != lease.FencingToken {
+		return fmt.Errorf("%w: lease %q fencing token %d is stale", core.ErrLeadershipLost, lease.Name, lease.FencingToken)
+	}
+	return nil
+}
+
+func (s *LeaderLeaseStore) now() time.Time {
+	if s != nil && s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func newLeaderLeaseRecord(lease core.LeaderLease) *leaderLeaseRecord {
+	return &leaderLeaseRecord{
+		Name:         strings.TrimSpace(lease.Name),
+		HolderID:     strings.TrimSpace(lease.HolderID),
+		FencingToken: lease.FencingToken,
+		AcquiredAt:   lease.AcquiredAt.UTC(),
+		ExpiresAt:    lease.ExpiresAt.UTC(),
+	}
+}
+
+func (r *leaderLeaseRecord) toDomain() core.LeaderLease {
+	if r == nil {
+		return core.LeaderLease{}
+	}
+	return core.LeaderLease{
+		Name:         r.Name,
+		HolderID:     r.HolderID,
+		FencingToken: r.FencingToken,
+		AcquiredAt:   r.AcquiredAt.UTC(),
+		ExpiresAt:    r.ExpiresAt.UTC(),
+	}
+}
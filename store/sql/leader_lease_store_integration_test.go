@@ -0,0 +1,104 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestLeaderLeaseStore_FencesHandoverAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	repoFactory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	store := repoFactory.LeaderLeaseStore()
+	if store == nil {
+		t.Fatalf("expected leader lease store to be wired")
+	}
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	store.Now = func() time.Time { return now }
+
+	first, acquired, err := store.AcquireLeaderLease(ctx, "sync_scheduler", "replica_a", 30*time.Second)
+	if err != nil || !acquired || first.FencingToken != 1 {
+		t.Fatalf("expected replica_a to acquire token 1, got %+v %v %v", first, acquired, err)
+	}
+	if _, acquired, err := store.AcquireLeaderLease(ctx, "sync_scheduler", "replica_b", 30*time.Second); err != nil || acquired {
+		t.Fatalf("expected live lease to be refused, got %v %v", acquired, err)
+	}
+	now = now.Add(20 * time.Second)
+	renewed, err := store.RenewLeaderLease(ctx, first, 30*time.Second)
+	if err != nil || renewed.FencingToken != 1 || !renewed.ExpiresAt.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("expected renewal to extend the lease, got %+v %v", renewed, err)
+	}
+
+	// replica_a crashes; replica_b takes over once the lease expires.
+	now = now.Add(31 * time.Second)
+	second, acquired, err := store.AcquireLeaderLease(ctx, "sync_scheduler", "replica_b", 30*time.Second)
+	if err != nil || !acquired || second.FencingToken != 2 || second.HolderID != "replica_b" {
+		t.Fatalf("expected replica_b to take over with token 2, got %+v %v %v", second, acquired, err)
+	}
+	if _, err := store.RenewLeaderLease(ctx, renewed, 30*time.Second); !errors.Is(err, core.ErrLeadershipLost) {
+		t.Fatalf("expected the fenced holder to lose its lease, got %v", err)
+	}
+	if err := store.ReleaseLeaderLease(ctx, renewed); !errors.Is(err, core.ErrLeadershipLost) {
+		t.Fatalf("expected the fenced holder not to release the new lease, got %v", err)
+	}
+
+	if err := store.ReleaseLeaderLease(ctx, second); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	third, acquired, err := store.AcquireLeaderLease(ctx, "sync_scheduler", "replica_a", 30*time.Second)
+	if err != nil || !acquired || third.FencingToken != 3 {
+		t.Fatalf("expected released lease to hand over with token 3, got %+v %v %v", third, acquired, err)
+	}
+}
+
+func TestLeaderLeaseStore_FencesWritesOfAReplacedLeader(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	repoFactory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	elector, err := core.NewLeaderElector(repoFactory.LeaderLeaseStore(), core.WithLeaderHolderID("replica_a"))
+	if err != nil {
+		t.Fatalf("new elector: %v", err)
+	}
+	activity := repoFactory.ActivityStore()
+	policy := core.ActivityRetentionPolicy{TTL: time.Hour}
+
+	var leading, fenced error
+	if err := elector.RunAsLeader(ctx, "activity_retention", func(ctx context.Context) error {
+		_, leading = activity.Prune(ctx, policy)
+		// replica_b takes the lease over while replica_a is still running.
+		if _, err := client.DB().NewRaw(
+			"UPDATE service_leader_leases SET holder_id = ?, fencing_token = fencing_token + 1 WHERE name = ?",
+			"replica_b", "activity_retention",
+		).Exec(ctx); err != nil {
+			return err
+		}
+		_, fenced = activity.Prune(ctx, policy)
+		return nil
+	}); err != nil {
+		t.Fatalf("run as leader: %v", err)
+	}
+	if leading != nil {
+		t.Fatalf("expected the current leader to write, got %v", leading)
+	}
+	if !errors.Is(fenced, core.ErrLeadershipLost) {
+		t.Fatalf("expected the replaced leader's write to be fenced, got %v", fenced)
+	}
+	if _, err := activity.Prune(ctx, policy); err != nil {
+		t.Fatalf("expected writes outside leadership to be unfenced, got %v", err)
+	}
+}
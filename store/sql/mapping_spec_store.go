@@ -0,0 +1,405 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type MappingSpecStore struct {
+	db   *bun.DB
+	repo repository.Repository[*mappingSpecRecord]
+}
+
+func NewMappingSpecStore(db *bun.DB) (*MappingSpecStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*mappingSpecRecord](db, mappingSpecHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid mapping spec repository wiring: %w", err)
+		}
+	}
+	return &MappingSpecStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+func (s *MappingSpecStore) CreateDraft(ctx context.Context, spec core.MappingSpec) (core.MappingSpec, error) {
+	if s == nil || s.db == nil {
+		return core.MappingSpec{}, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	spec = normalizeMappingSpec(spec)
+	if strings.TrimSpace(string(spec.Status)) == "" {
+		spec.Status = core.MappingSpecStatusDraft
+	}
+	if err := spec.Validate(); err != nil {
+		return core.MappingSpec{}, err
+	}
+	now := time.Now().UTC()
+	record := newMappingSpecRecord(spec, now)
+	if record.ID == "" {
+		record.ID = uuid.NewString()
+	}
+	if _, err := s.db.NewInsert().Model(record).Exec(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return core.MappingSpec{}, fmt.Errorf(
+				"sqlstore: mapping spec %q version %d already exists",
+				spec.SpecID,
+				spec.Version,
+			)
+		}
+		return core.MappingSpec{}, err
+	}
+	return record.toDomain(), nil
+}
+
+func (s *MappingSpecStore) UpdateDraft(ctx context.Context, spec core.MappingSpec) (core.MappingSpec, error) {
+	if s == nil || s.db == nil {
+		return core.MappingSpec{}, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	spec = normalizeMappingSpec(spec)
+	if err := spec.Validate(); err != nil {
+		return core.MappingSpec{}, err
+	}
+
+	var out core.MappingSpec
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record, err := findMappingSpecTx(ctx, tx, spec.ProviderID, spec.Scope, spec.SpecID, spec.Version)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("sqlstore: mapping spec %q version %d not found", spec.SpecID, spec.Version)
+		}
+		updated := newMappingSpecRecord(spec, time.Now().UTC())
+		updated.ID = record.ID
+		updated.CreatedAt = record.CreatedAt
+		if !spec.UpdatedAt.IsZero() {
+			updated.UpdatedAt = spec.UpdatedAt.UTC()
+		}
+		if _, updateErr := tx.NewUpdate().Model(updated).WherePK().Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = updated.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.MappingSpec{}, err
+	}
+	return out, nil
+}
+
+func (s *MappingSpecStore) SetStatus(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	specID string,
+	version int,
+	status core.MappingSpecStatus,
+	now time.Time,
+) (core.MappingSpec, error) {
+	if s == nil || s.db == nil {
+		return core.MappingSpec{}, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	if !status.IsValid() {
+		return core.MappingSpec{}, fmt.Errorf("sqlstore: invalid mapping spec status %q", status)
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var out core.MappingSpec
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record, err := findMappingSpecTx(ctx, tx, providerID, scope, specID, version)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("sqlstore: mapping spec %q version %d not found", specID, version)
+		}
+		if status == core.MappingSpecStatusPublished {
+			if demoteErr := demotePublishedMappingSpecsTx(ctx, tx, record, now.UTC()); demoteErr != nil {
+				return demoteErr
+			}
+			if record.PublishedAt == nil {
+				publishedAt := now.UTC()
+				record.PublishedAt = &publishedAt
+			}
+		} else {
+			record.PublishedAt = nil
+		}
+		record.Status = string(status)
+		record.UpdatedAt = now.UTC()
+		if _, updateErr := tx.NewUpdate().
+			Model(record).
+			Column("status", "published_at", "updated_at").
+			WherePK().
+			Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.MappingSpec{}, err
+	}
+	return out, nil
+}
+
+func (s *MappingSpecStore) GetVersion(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	specID string,
+	version int,
+) (core.MappingSpec, bool, error) {
+	if s == nil || s.db == nil {
+		return core.MappingSpec{}, false, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	record, err := findMappingSpecTx(ctx, s.db, providerID, scope, specID, version)
+	if err != nil {
+		return core.MappingSpec{}, false, err
+	}
+	if record == nil {
+		return core.MappingSpec{}, false, nil
+	}
+	return record.toDomain(), true, nil
+}
+
+// GetLatest returns the highest version of the spec regardless of status.
+func (s *MappingSpecStore) GetLatest(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	specID string,
+) (core.MappingSpec, bool, error) {
+	if s == nil || s.db == nil {
+		return core.MappingSpec{}, false, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	record := &mappingSpecRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.scope_type = ?", normalizeScopeType(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		Where("?TableAlias.spec_id = ?", strings.TrimSpace(specID)).
+		OrderExpr("?TableAlias.version DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.MappingSpec{}, false, nil
+		}
+		return core.MappingSpec{}, false, err
+	}
+	return record.toDomain(), true, nil
+}
+
+func (s *MappingSpecStore) ListByScope(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+) ([]core.MappingSpec, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: mapping spec store is not configured")
+	}
+	if err := scope.Validate(); err != nil {
+		return nil, err
+	}
+	records, _, err := s.repo.List(ctx,
+		repository.SelectBy("provider_id", "=", strings.TrimSpace(providerID)),
+		repository.SelectBy("scope_type", "=", normalizeScopeType(scope.Type)),
+		repository.SelectBy("scope_id", "=", strings.TrimSpace(scope.ID)),
+		repository.OrderBy("spec_id ASC"),
+		repository.OrderBy("version ASC"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.MappingSpec, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
+// PublishVersion marks the version as published and demotes any previously
+// published version of the same spec to validated in the same transaction.
+func (s *MappingSpecStore) PublishVersion(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	specID string,
+	version int,
+	publishedAt time.Time,
+) (core.MappingSpec, error) {
+	if publishedAt.IsZero() {
+		publishedAt = time.Now()
+	}
+	return s.SetStatus(ctx, providerID, scope, specID, version, core.MappingSpecStatusPublished, publishedAt)
+}
+
+func findMappingSpecTx(
+	ctx context.Context,
+	db bun.IDB,
+	providerID string,
+	scope core.ScopeRef,
+	specID string,
+	version int,
+) (*mappingSpecRecord, error) {
+	record := &mappingSpecRecord{}
+	err := db.NewSelect().
+		Model(record).
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.scope_type = ?", normalizeScopeType(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		Where("?TableAlias.spec_id = ?", strings.TrimSpace(specID)).
+		Where("?TableAlias.version = ?", version).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func demotePublishedMappingSpecsTx(
+	ctx context.Context,
+	tx bun.Tx,
+	record *mappingSpecRecord,
+	now time.Time,
+) error {
+	_, err := tx.NewUpdate().
+		Model((*mappingSpecRecord)(nil)).
+		Set("status = ?", string(core.MappingSpecStatusValidated)).
+		Set("published_at = NULL").
+		Set("updated_at = ?", now).
+		Where("provider_id = ?", record.ProviderID).
+		Where("scope_type = ?", record.ScopeType).
+		Where("scope_id = ?", record.ScopeID).
+		Where("spec_id = ?", record.SpecID).
+		Where("status = ?", string(core.MappingSpecStatusPublished)).
+		Where("id <> ?", record.ID).
+		Exec(ctx)
+	return err
+}
+
+func normalizeMappingSpec(spec core.MappingSpec) core.MappingSpec {
+	spec.ID = strings.TrimSpace(spec.ID)
+	spec.SpecID = strings.TrimSpace(spec.SpecID)
+	spec.ProviderID = strings.TrimSpace(spec.ProviderID)
+	spec.Scope = core.ScopeRef{
+		Type: normalizeScopeType(spec.Scope.Type),
+		ID:   strings.TrimSpace(spec.Scope.ID),
+	}
+	spec.Name = strings.TrimSpace(spec.Name)
+	spec.SourceObject = strings.TrimSpace(spec.SourceObject)
+	spec.TargetModel = strings.TrimSpace(spec.TargetModel)
+	spec.SchemaRef = strings.TrimSpace(spec.SchemaRef)
+	return spec
+}
+
+func normalizeScopeType(scopeType string) string {
+	return strings.TrimSpace(strings.ToLower(scopeType))
+}
+
+func newMappingSpecRecord(spec core.MappingSpec, now time.Time) *mappingSpecRecord {
+	createdAt := spec.CreatedAt.UTC()
+	if spec.CreatedAt.IsZero() {
+		createdAt = now
+	}
+	updatedAt := spec.UpdatedAt.UTC()
+	if spec.UpdatedAt.IsZero() {
+		updatedAt = now
+	}
+	rules := make([]mappingRuleRecord, 0, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		rules = append(rules, mappingRuleRecord{
+			ID:          rule.ID,
+			SourcePath:  rule.SourcePath,
+			TargetPath:  rule.TargetPath,
+			Transform:   rule.Transform,
+			Required:    rule.Required,
+			Default:     rule.Default,
+			Constraints: rule.Constraints,
+			Metadata:    rule.Metadata,
+		})
+	}
+	record := &mappingSpecRecord{
+		ID:           spec.ID,
+		SpecID:       spec.SpecID,
+		ProviderID:   spec.ProviderID,
+		ScopeType:    spec.Scope.Type,
+		ScopeID:      spec.Scope.ID,
+		Name:         spec.Name,
+		Description:  spec.Description,
+		SourceObject: spec.SourceObject,
+		TargetModel:  spec.TargetModel,
+		SchemaRef:    spec.SchemaRef,
+		Version:      spec.Version,
+		Status:       string(spec.Status),
+		Rules:        rules,
+		Metadata:     copyAnyMap(spec.Metadata),
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	}
+	if spec.PublishedAt != nil && spec.Status == core.MappingSpecStatusPublished {
+		value := spec.PublishedAt.UTC()
+		record.PublishedAt = &value
+	}
+	return record
+}
+
+func (r *mappingSpecRecord) toDomain() core.MappingSpec {
+	if r == nil {
+		return core.MappingSpec{}
+	}
+	rules := make([]core.MappingRule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, core.MappingRule{
+			ID:          rule.ID,
+			SourcePath:  rule.SourcePath,
+			TargetPath:  rule.TargetPath,
+			Transform:   rule.Transform,
+			Required:    rule.Required,
+			Default:     rule.Default,
+			Constraints: rule.Constraints,
+			Metadata:    rule.Metadata,
+		})
+	}
+	spec := core.MappingSpec{
+		ID:           r.ID,
+		SpecID:       r.SpecID,
+		ProviderID:   r.ProviderID,
+		Scope:        core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		Name:         r.Name,
+		Description:  r.Description,
+		SourceObject: r.SourceObject,
+		TargetModel:  r.TargetModel,
+		SchemaRef:    r.SchemaRef,
+		Version:      r.Version,
+		Status:       core.MappingSpecStatus(r.Status),
+		Rules:        rules,
+		Metadata:     copyAnyMap(r.Metadata),
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+	if r.PublishedAt != nil {
+		value := *r.PublishedAt
+		spec.PublishedAt = &value
+	}
+	return spec
+}
@@ -152,6 +152,16 @@ type webhookDeliveryRecord struct {
 	UpdatedAt     time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 }
 
+type webhookEventOutcomeRecord struct {
+	bun.BaseModel `bun:"table:service_webhook_event_outcomes,alias:sweo"`
+
+	ID         string    `bun:"id,pk"`
+	ProviderID string    `bun:"provider_id,notnull"`
+	DeliveryID string    `bun:"delivery_id,notnull"`
+	EventIndex int       `bun:"event_index,notnull"`
+	CreatedAt  time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
 type syncCursorRecord struct {
 	bun.BaseModel `bun:"table:service_sync_cursors,alias:ssc"`
 
@@ -204,17 +214,28 @@ type rateLimitStateRecord struct {
 type syncJobRecord struct {
 	bun.BaseModel `bun:"table:service_sync_jobs,alias:ssj"`
 
-	ID            string         `bun:"id,pk"`
-	ConnectionID  string         `bun:"connection_id,notnull"`
-	ProviderID    string         `bun:"provider_id,notnull"`
-	Mode          string         `bun:"mode,notnull"`
-	Checkpoint    string         `bun:"checkpoint"`
-	Status        string         `bun:"status,notnull"`
-	Attempts      int            `bun:"attempts,notnull"`
-	NextAttemptAt *time.Time     `bun:"next_attempt_at,nullzero"`
-	Metadata      map[string]any `bun:"metadata,type:jsonb,notnull"`
-	CreatedAt     time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	UpdatedAt     time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	ID             string                `bun:"id,pk"`
+	ConnectionID   string                `bun:"connection_id,notnull"`
+	ProviderID     string                `bun:"provider_id,notnull"`
+	Mode           string                `bun:"mode,notnull"`
+	Checkpoint     string                `bun:"checkpoint"`
+	Status         string                `bun:"status,notnull"`
+	Attempts       int                   `bun:"attempts,notnull"`
+	NextAttemptAt  *time.Time            `bun:"next_attempt_at,nullzero"`
+	LeaseExpiresAt *time.Time            `bun:"lease_expires_at,nullzero"`
+	Progress       syncJobProgressRecord `bun:"progress,type:jsonb,notnull"`
+	Metadata       map[string]any        `bun:"metadata,type:jsonb,notnull"`
+	CreatedAt      time.Time             `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time             `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type syncJobProgressRecord struct {
+	ItemsProcessed        int        `json:"items_processed,omitempty"`
+	ItemsTotal            int        `json:"items_total,omitempty"`
+	PagesProcessed        int        `json:"pages_processed,omitempty"`
+	Percent               float64    `json:"percent,omitempty"`
+	StartedAt             *time.Time `json:"started_at,omitempty"`
+	EstimatedCompletionAt *time.Time `json:"estimated_completion_at,omitempty"`
 }
 
 type syncJobIdempotencyRecord struct {
@@ -243,6 +264,7 @@ type lifecycleOutboxRecord struct {
 	ScopeType    string         `bun:"scope_type,notnull"`
 	ScopeID      string         `bun:"scope_id,notnull"`
 	ConnectionID *string        `bun:"connection_id"`
+	PartitionKey string         `bun:"partition_key,notnull"`
 	Payload      map[string]any `bun:"payload,type:jsonb,notnull"`
 	Metadata     map[string]any `bun:"metadata,type:jsonb,notnull"`
 	Status       string         `bun:"status,notnull"`
@@ -254,6 +276,20 @@ type lifecycleOutboxRecord struct {
 	UpdatedAt    time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 }
 
+type lifecycleOutboxProjectorRecord struct {
+	bun.BaseModel `bun:"table:service_lifecycle_outbox_projectors,alias:slop"`
+
+	ID          string     `bun:"id,pk"`
+	EventID     string     `bun:"event_id,notnull"`
+	Projector   string     `bun:"projector,notnull"`
+	Status      string     `bun:"status,notnull"`
+	Attempts    int        `bun:"attempts,notnull"`
+	LastError   string     `bun:"last_error,notnull"`
+	DeliveredAt *time.Time `bun:"delivered_at,nullzero"`
+	CreatedAt   time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
 type notificationDispatchRecord struct {
 	bun.BaseModel `bun:"table:service_notification_dispatches,alias:snd"`
 
@@ -268,3 +304,233 @@ type notificationDispatchRecord struct {
 	Metadata     map[string]any `bun:"metadata,type:jsonb,notnull"`
 	CreatedAt    time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 }
+
+type outboundWebhookEndpointRecord struct {
+	bun.BaseModel `bun:"table:service_outbound_webhook_endpoints,alias:sowe"`
+
+	ID                  string         `bun:"id,pk"`
+	ScopeType           string         `bun:"scope_type,notnull"`
+	ScopeID             string         `bun:"scope_id,notnull"`
+	URL                 string         `bun:"url,notnull"`
+	EncryptedSecret     []byte         `bun:"encrypted_secret,notnull"`
+	EventFilters        []string       `bun:"event_filters,type:jsonb,notnull"`
+	Status              string         `bun:"status,notnull"`
+	DisabledReason      string         `bun:"disabled_reason,notnull"`
+	ConsecutiveFailures int            `bun:"consecutive_failures,notnull"`
+	CircuitOpenUntil    *time.Time     `bun:"circuit_open_until,nullzero"`
+	Metadata            map[string]any `bun:"metadata,type:jsonb,notnull"`
+	CreatedAt           time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt           time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type outboundWebhookDeliveryRecord struct {
+	bun.BaseModel `bun:"table:service_outbound_webhook_deliveries,alias:sowd"`
+
+	ID             string     `bun:"id,pk"`
+	EndpointID     string     `bun:"endpoint_id,notnull"`
+	EventID        string     `bun:"event_id,notnull"`
+	EventName      string     `bun:"event_name,notnull"`
+	Payload        []byte     `bun:"payload,notnull"`
+	Status         string     `bun:"status,notnull"`
+	Attempts       int        `bun:"attempts,notnull"`
+	NextAttemptAt  *time.Time `bun:"next_attempt_at,nullzero"`
+	LastStatusCode int        `bun:"last_status_code,notnull"`
+	LastError      string     `bun:"last_error,notnull"`
+	DeliveredAt    *time.Time `bun:"delivered_at,nullzero"`
+	CreatedAt      time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type mappingSpecRecord struct {
+	bun.BaseModel `bun:"table:service_mapping_specs,alias:sms"`
+
+	ID           string              `bun:"id,pk"`
+	SpecID       string              `bun:"spec_id,notnull"`
+	ProviderID   string              `bun:"provider_id,notnull"`
+	ScopeType    string              `bun:"scope_type,notnull"`
+	ScopeID      string              `bun:"scope_id,notnull"`
+	Name         string              `bun:"name,notnull"`
+	Description  string              `bun:"description,notnull"`
+	SourceObject string              `bun:"source_object,notnull"`
+	TargetModel  string              `bun:"target_model,notnull"`
+	SchemaRef    string              `bun:"schema_ref,notnull"`
+	Version      int                 `bun:"version,notnull"`
+	Status       string              `bun:"status,notnull"`
+	Rules        []mappingRuleRecord `bun:"rules,type:jsonb,notnull"`
+	Metadata     map[string]any      `bun:"metadata,type:jsonb,notnull"`
+	PublishedAt  *time.Time          `bun:"published_at,nullzero"`
+	CreatedAt    time.Time           `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time           `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type mappingRuleRecord struct {
+	ID          string         `json:"id,omitempty"`
+	SourcePath  string         `json:"source_path"`
+	TargetPath  string         `json:"target_path"`
+	Transform   string         `json:"transform,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Default     any            `json:"default,omitempty"`
+	Constraints map[string]any `json:"constraints,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+type syncBindingRecord struct {
+	bun.BaseModel `bun:"table:service_sync_bindings,alias:ssb"`
+
+	ID            string         `bun:"id,pk"`
+	ProviderID    string         `bun:"provider_id,notnull"`
+	ScopeType     string         `bun:"scope_type,notnull"`
+	ScopeID       string         `bun:"scope_id,notnull"`
+	ConnectionID  string         `bun:"connection_id,notnull"`
+	MappingSpecID string         `bun:"mapping_spec_id,notnull"`
+	SourceObject  string         `bun:"source_object,notnull"`
+	TargetModel   string         `bun:"target_model,notnull"`
+	Direction     string         `bun:"direction,notnull"`
+	Status        string         `bun:"status,notnull"`
+	Metadata      map[string]any `bun:"metadata,type:jsonb,notnull"`
+	CreatedAt     time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type syncScheduleRecord struct {
+	bun.BaseModel `bun:"table:service_sync_schedules,alias:sssc"`
+
+	ID              string         `bun:"id,pk"`
+	ProviderID      string         `bun:"provider_id,notnull"`
+	ScopeType       string         `bun:"scope_type,notnull"`
+	ScopeID         string         `bun:"scope_id,notnull"`
+	ConnectionID    string         `bun:"connection_id,notnull"`
+	SyncBindingID   string         `bun:"sync_binding_id,notnull"`
+	Mode            string         `bun:"mode,notnull"`
+	CronExpression  string         `bun:"cron_expression,notnull"`
+	IntervalSeconds int64          `bun:"interval_seconds,notnull"`
+	JitterSeconds   int64          `bun:"jitter_seconds,notnull"`
+	Timezone        string         `bun:"timezone,notnull"`
+	Status          string         `bun:"status,notnull"`
+	NextWindowAt    *time.Time     `bun:"next_window_at,nullzero"`
+	NextRunAt       *time.Time     `bun:"next_run_at,nullzero"`
+	LastWindowAt    *time.Time     `bun:"last_window_at,nullzero"`
+	LastSyncJobID   string         `bun:"last_sync_job_id,notnull"`
+	Metadata        map[string]any `bun:"metadata,type:jsonb,notnull"`
+	CreatedAt       time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type leaderLeaseRecord struct {
+	bun.BaseModel `bun:"table:service_leader_leases,alias:sll"`
+
+	Name         string    `bun:"name,pk"`
+	HolderID     string    `bun:"holder_id,notnull"`
+	FencingToken int64     `bun:"fencing_token,notnull"`
+	AcquiredAt   time.Time `bun:"acquired_at,notnull"`
+	ExpiresAt    time.Time `bun:"expires_at,notnull"`
+	UpdatedAt    time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type syncCheckpointRecord struct {
+	bun.BaseModel `bun:"table:service_sync_checkpoints,alias:sscp"`
+
+	ID              string         `bun:"id,pk"`
+	ProviderID      string         `bun:"provider_id,notnull"`
+	ScopeType       string         `bun:"scope_type,notnull"`
+	ScopeID         string         `bun:"scope_id,notnull"`
+	ConnectionID    string         `bun:"connection_id,notnull"`
+	SyncBindingID   string         `bun:"sync_binding_id,notnull"`
+	Direction       string         `bun:"direction,notnull"`
+	Cursor          string         `bun:"cursor,notnull"`
+	Sequence        int64          `bun:"sequence_num,notnull"`
+	SourceVersion   string         `bun:"source_version,notnull"`
+	IdempotencySeed string         `bun:"idempotency_seed,notnull"`
+	Metadata        map[string]any `bun:"metadata,type:jsonb,notnull"`
+	LastEventAt     *time.Time     `bun:"last_event_at,nullzero"`
+	CreatedAt       time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type identityBindingRecord struct {
+	bun.BaseModel `bun:"table:service_identity_bindings,alias:sib"`
+
+	ID            string         `bun:"id,pk"`
+	ProviderID    string         `bun:"provider_id,notnull"`
+	ScopeType     string         `bun:"scope_type,notnull"`
+	ScopeID       string         `bun:"scope_id,notnull"`
+	ConnectionID  string         `bun:"connection_id,notnull"`
+	SyncBindingID string         `bun:"sync_binding_id,notnull"`
+	SourceObject  string         `bun:"source_object,notnull"`
+	ExternalID    string         `bun:"external_id,notnull"`
+	InternalType  string         `bun:"internal_type,notnull"`
+	InternalID    string         `bun:"internal_id,notnull"`
+	MatchKind     string         `bun:"match_kind,notnull"`
+	Confidence    float64        `bun:"confidence,notnull"`
+	Metadata      map[string]any `bun:"metadata,type:jsonb,notnull"`
+	OrphanedAt    *time.Time     `bun:"orphaned_at,nullzero"`
+	CreatedAt     time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type syncConflictRecord struct {
+	bun.BaseModel `bun:"table:service_sync_conflicts,alias:sscf"`
+
+	ID             string         `bun:"id,pk"`
+	ProviderID     string         `bun:"provider_id,notnull"`
+	ScopeType      string         `bun:"scope_type,notnull"`
+	ScopeID        string         `bun:"scope_id,notnull"`
+	ConnectionID   string         `bun:"connection_id,notnull"`
+	SyncBindingID  string         `bun:"sync_binding_id,notnull"`
+	CheckpointID   *string        `bun:"checkpoint_id"`
+	SourceObject   string         `bun:"source_object,notnull"`
+	ExternalID     string         `bun:"external_id,notnull"`
+	SourceVersion  string         `bun:"source_version,notnull"`
+	IdempotencyKey string         `bun:"idempotency_key,notnull"`
+	Policy         string         `bun:"policy,notnull"`
+	Reason         string         `bun:"reason,notnull"`
+	Status         string         `bun:"status,notnull"`
+	SourcePayload  map[string]any `bun:"source_payload,type:jsonb,notnull"`
+	TargetPayload  map[string]any `bun:"target_payload,type:jsonb,notnull"`
+	Resolution     map[string]any `bun:"resolution,type:jsonb,notnull"`
+	ResolvedBy     string         `bun:"resolved_by,notnull"`
+	ResolvedAt     *time.Time     `bun:"resolved_at,nullzero"`
+	Metadata       map[string]any `bun:"metadata,type:jsonb,notnull"`
+	CreatedAt      time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type syncChangeLogRecord struct {
+	bun.BaseModel `bun:"table:service_sync_change_log,alias:sscl"`
+
+	ID             string         `bun:"id,pk"`
+	ProviderID     string         `bun:"provider_id,notnull"`
+	ScopeType      string         `bun:"scope_type,notnull"`
+	ScopeID        string         `bun:"scope_id,notnull"`
+	ConnectionID   string         `bun:"connection_id,notnull"`
+	SyncBindingID  string         `bun:"sync_binding_id,notnull"`
+	Direction      string         `bun:"direction,notnull"`
+	Operation      string         `bun:"operation,notnull"`
+	SourceObject   string         `bun:"source_object,notnull"`
+	ExternalID     string         `bun:"external_id,notnull"`
+	SourceVersion  string         `bun:"source_version,notnull"`
+	IdempotencyKey string         `bun:"idempotency_key,notnull"`
+	Payload        map[string]any `bun:"payload,type:jsonb,notnull"`
+	Metadata       map[string]any `bun:"metadata,type:jsonb,notnull"`
+	OccurredAt     time.Time      `bun:"occurred_at,notnull"`
+	CreatedAt      time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type jobQueueRecord struct {
+	bun.BaseModel `bun:"table:service_job_queue,alias:sjq"`
+
+	ID             string         `bun:"id,pk"`
+	JobID          string         `bun:"job_id,notnull"`
+	ScriptPath     string         `bun:"script_path,notnull"`
+	Parameters     map[string]any `bun:"parameters,type:jsonb,notnull"`
+	IdempotencyKey string         `bun:"idempotency_key,notnull"`
+	DedupPolicy    string         `bun:"dedup_policy,notnull"`
+	State          string         `bun:"state,notnull"`
+	Attempts       int            `bun:"attempts,notnull"`
+	RunAt          time.Time      `bun:"run_at,notnull"`
+	LeaseToken     string         `bun:"lease_token,notnull"`
+	LeaseExpiresAt *time.Time     `bun:"lease_expires_at,nullzero"`
+	TerminalReason string         `bun:"terminal_reason,notnull"`
+	EnqueuedAt     time.Time      `bun:"enqueued_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time      `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,621 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/webhooks"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// OutboundEndpointStore persists customer webhook endpoints. Endpoint secrets
+// are encrypted at rest with the configured secret provider, and endpoint URLs
+// are checked against URLPolicy on register and update.
+type OutboundEndpointStore struct {
+	db        *bun.DB
+	repo      repository.Repository[*outboundWebhookEndpointRecord]
+	secrets   core.SecretProvider
+	URLPolicy webhooks.OutboundURLPolicy
+}
+
+func NewOutboundEndpointStore(db *bun.DB, secrets core.SecretProvider) (*OutboundEndpointStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	if secrets == nil {
+		return nil, fmt.Errorf("sqlstore: secret provider is required for outbound endpoint secrets")
+	}
+	repo := repository.NewRepository[*outboundWebhookEndpointRecord](db, outboundWebhookEndpointHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid outbound endpoint repository wiring: %w", err)
+		}
+	}
+	return &OutboundEndpointStore{db: db, repo: repo, secrets: secrets}, nil
+}
+
+func (s *OutboundEndpointStore) Register(
+	ctx context.Context,
+	in webhooks.RegisterOutboundEndpointInput,
+) (webhooks.OutboundEndpoint, error) {
+	if s == nil || s.repo == nil || s.secrets == nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	if strings.TrimSpace(in.ScopeType) == "" || strings.TrimSpace(in.ScopeID) == "" {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint scope type and scope id are required")
+	}
+	endpointURL, err := s.validateURL(ctx, in.URL)
+	if err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+	secret := strings.TrimSpace(in.Secret)
+	if secret == "" {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint secret is required")
+	}
+	encrypted, err := s.encryptSecret(ctx, secret)
+	if err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+
+	now := time.Now().UTC()
+	record := &outboundWebhookEndpointRecord{
+		ID:              uuid.NewString(),
+		ScopeType:       strings.TrimSpace(in.ScopeType),
+		ScopeID:         strings.TrimSpace(in.ScopeID),
+		URL:             endpointURL,
+		EncryptedSecret: encrypted,
+		EventFilters:    normalizeEventFilters(in.EventFilters),
+		Status:          webhooks.OutboundEndpointStatusActive,
+		Metadata:        copyAnyMap(in.Metadata),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	created, err := s.repo.Create(ctx, record)
+	if err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+	return s.toDomain(ctx, created)
+}
+
+func (s *OutboundEndpointStore) Get(ctx context.Context, id string) (webhooks.OutboundEndpoint, error) {
+	if s == nil || s.db == nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	record := &outboundWebhookEndpointRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", strings.TrimSpace(id)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint %q not found", id)
+		}
+		return webhooks.OutboundEndpoint{}, err
+	}
+	return s.toDomain(ctx, record)
+}
+
+func (s *OutboundEndpointStore) ListByScope(ctx context.Context, scope core.ScopeRef) ([]webhooks.OutboundEndpoint, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	var records []*outboundWebhookEndpointRecord
+	err := s.db.NewSelect().
+		Model(&records).
+		Where("?TableAlias.scope_type = ?", strings.TrimSpace(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		OrderExpr("?TableAlias.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]webhooks.OutboundEndpoint, 0, len(records))
+	for _, record := range records {
+		endpoint, err := s.toDomain(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, endpoint)
+	}
+	return out, nil
+}
+
+func (s *OutboundEndpointStore) UpdateHealth(ctx context.Context, id string, health webhooks.OutboundEndpointHealth) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("sqlstore: outbound endpoint id is required")
+	}
+	status := strings.TrimSpace(health.Status)
+	if status == "" {
+		status = webhooks.OutboundEndpointStatusActive
+	}
+	_, err := s.db.NewUpdate().
+		Model((*outboundWebhookEndpointRecord)(nil)).
+		Set("status = ?", status).
+		Set("disabled_reason = ?", strings.TrimSpace(health.DisabledReason)).
+		Set("consecutive_failures = ?", max(health.ConsecutiveFailures, 0)).
+		Set("circuit_open_until = ?", copyTimePointer(health.CircuitOpenUntil)).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (s *OutboundEndpointStore) Update(
+	ctx context.Context,
+	id string,
+	in webhooks.UpdateOutboundEndpointInput,
+) (webhooks.OutboundEndpoint, error) {
+	if s == nil || s.db == nil || s.secrets == nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint id is required")
+	}
+	endpointURL, err := s.validateURL(ctx, in.URL)
+	if err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+	record := &outboundWebhookEndpointRecord{
+		ID:           id,
+		URL:          endpointURL,
+		EventFilters: normalizeEventFilters(in.EventFilters),
+		Metadata:     copyAnyMap(in.Metadata),
+		UpdatedAt:    time.Now().UTC(),
+	}
+	columns := []string{"url", "event_filters", "metadata", "updated_at"}
+	if secret := strings.TrimSpace(in.Secret); secret != "" {
+		if record.EncryptedSecret, err = s.encryptSecret(ctx, secret); err != nil {
+			return webhooks.OutboundEndpoint{}, err
+		}
+		columns = append(columns, "encrypted_secret")
+	}
+	query := s.db.NewUpdate().Model(record).Column(columns...).WherePK()
+	if err := s.mutate(ctx, id, query); err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *OutboundEndpointStore) Enable(ctx context.Context, id string) (webhooks.OutboundEndpoint, error) {
+	if s == nil || s.db == nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint id is required")
+	}
+	query := s.db.NewUpdate().
+		Model((*outboundWebhookEndpointRecord)(nil)).
+		Set("status = ?", webhooks.OutboundEndpointStatusActive).
+		Set("disabled_reason = ?", "").
+		Set("consecutive_failures = 0").
+		Set("circuit_open_until = NULL").
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id)
+	if err := s.mutate(ctx, id, query); err != nil {
+		return webhooks.OutboundEndpoint{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Delete removes the endpoint and its delivery log in one transaction, so the
+// log does not rely on foreign key cascades being enabled.
+func (s *OutboundEndpointStore) Delete(ctx context.Context, id string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound endpoint store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("sqlstore: outbound endpoint id is required")
+	}
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*outboundWebhookDeliveryRecord)(nil)).
+			Where("endpoint_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+		result, err := tx.NewDelete().
+			Model((*outboundWebhookEndpointRecord)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return fmt.Errorf("sqlstore: outbound endpoint %q not found", id)
+		}
+		return nil
+	})
+}
+
+func (s *OutboundEndpointStore) mutate(ctx context.Context, id string, query *bun.UpdateQuery) error {
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("sqlstore: outbound endpoint %q not found", id)
+	}
+	return nil
+}
+
+func (s *OutboundEndpointStore) validateURL(ctx context.Context, raw string) (string, error) {
+	parsed, err := s.URLPolicy.ValidateURL(ctx, raw)
+	if err != nil {
+		return "", fmt.Errorf("sqlstore: %w", err)
+	}
+	return parsed.String(), nil
+}
+
+func (s *OutboundEndpointStore) encryptSecret(ctx context.Context, secret string) ([]byte, error) {
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("sqlstore: encrypt outbound endpoint secret: %w", err)
+	}
+	return encrypted, nil
+}
+
+func (s *OutboundEndpointStore) toDomain(
+	ctx context.Context,
+	record *outboundWebhookEndpointRecord,
+) (webhooks.OutboundEndpoint, error) {
+	if record == nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: outbound endpoint record is nil")
+	}
+	secret, err := s.secrets.Decrypt(ctx, record.EncryptedSecret)
+	if err != nil {
+		return webhooks.OutboundEndpoint{}, fmt.Errorf("sqlstore: decrypt outbound endpoint secret: %w", err)
+	}
+	return webhooks.OutboundEndpoint{
+		ID:                  record.ID,
+		ScopeType:           record.ScopeType,
+		ScopeID:             record.ScopeID,
+		URL:                 record.URL,
+		Secret:              string(secret),
+		EventFilters:        append([]string(nil), record.EventFilters...),
+		Status:              record.Status,
+		DisabledReason:      record.DisabledReason,
+		ConsecutiveFailures: record.ConsecutiveFailures,
+		CircuitOpenUntil:    copyTimePointer(record.CircuitOpenUntil),
+		Metadata:            copyAnyMap(record.Metadata),
+		CreatedAt:           record.CreatedAt,
+		UpdatedAt:           record.UpdatedAt,
+	}, nil
+}
+
+// OutboundDeliveryStore is the delivery log for outbound webhooks.
+type OutboundDeliveryStore struct {
+	db   *bun.DB
+	repo repository.Repository[*outboundWebhookDeliveryRecord]
+}
+
+func NewOutboundDeliveryStore(db *bun.DB) (*OutboundDeliveryStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*outboundWebhookDeliveryRecord](db, outboundWebhookDeliveryHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid outbound delivery repository wiring: %w", err)
+		}
+	}
+	return &OutboundDeliveryStore{db: db, repo: repo}, nil
+}
+
+func (s *OutboundDeliveryStore) Enqueue(
+	ctx context.Context,
+	delivery webhooks.OutboundDelivery,
+) (webhooks.OutboundDelivery, bool, error) {
+	if s == nil || s.db == nil {
+		return webhooks.OutboundDelivery{}, false, fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	endpointID := strings.TrimSpace(delivery.EndpointID)
+	eventID := strings.TrimSpace(delivery.EventID)
+	if endpointID == "" || eventID == "" {
+		return webhooks.OutboundDelivery{}, false, fmt.Errorf("sqlstore: endpoint id and event id are required")
+	}
+	now := time.Now().UTC()
+	record := &outboundWebhookDeliveryRecord{
+		ID:         uuid.NewString(),
+		EndpointID: endpointID,
+		EventID:    eventID,
+		EventName:  strings.TrimSpace(delivery.EventName),
+		Payload:    append([]byte(nil), delivery.Payload...),
+		Status:     webhooks.OutboundDeliveryStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	result, err := s.db.NewInsert().
+		Model(record).
+		On("CONFLICT (endpoint_id, event_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return webhooks.OutboundDelivery{}, false, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+
+	existing := &outboundWebhookDeliveryRecord{}
+	if err := s.db.NewSelect().
+		Model(existing).
+		Where("?TableAlias.endpoint_id = ?", endpointID).
+		Where("?TableAlias.event_id = ?", eventID).
+		Limit(1).
+		Scan(ctx); err != nil {
+		return webhooks.OutboundDelivery{}, false, err
+	}
+	return outboundDeliveryToDomain(existing), rowsAffected > 0, nil
+}
+
+// ClaimDue leases up to limit due deliveries, including processing ones whose
+// lease expired. Postgres claims rows with FOR UPDATE SKIP LOCKED, and the
+// update repeats the due check, so concurrent deliverers never send the same
+// delivery twice.
+func (s *OutboundDeliveryStore) ClaimDue(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]webhooks.OutboundDelivery, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	if limit <= 0 {
+		limit = 1
+	}
+	if lease <= 0 {
+		lease = time.Minute
+	}
+	now := time.Now().UTC()
+	lockClause := ""
+	if s.db.Dialect().Name() == dialect.PG {
+		lockClause = "FOR UPDATE SKIP LOCKED"
+	}
+	var records []outboundWebhookDeliveryRecord
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		query := `
+WITH claimed AS (
+	SELECT id
+	FROM service_outbound_webhook_deliveries
+	WHERE status IN (?, ?, ?)
+	  AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
+	ORDER BY created_at ASC
+	LIMIT ?
+	` + lockClause + `
+)
+UPDATE service_outbound_webhook_deliveries
+SET status = ?, attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+WHERE id IN (SELECT id FROM claimed)
+  AND status IN (?, ?, ?)
+  AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
+RETURNING
+	id,
+	endpoint_id,
+	event_id,
+	event_name,
+	payload,
+	status,
+	attempts,
+	next_attempt_at,
+	last_status_code,
+	last_error,
+	delivered_at,
+	created_at,
+	updated_at
+`
+		return tx.NewRaw(
+			query,
+			webhooks.OutboundDeliveryStatusPending,
+			webhooks.OutboundDeliveryStatusRetryReady,
+			webhooks.OutboundDeliveryStatusProcessing,
+			now,
+			limit,
+			webhooks.OutboundDeliveryStatusProcessing,
+			now.Add(lease),
+			now,
+			webhooks.OutboundDeliveryStatusPending,
+			webhooks.OutboundDeliveryStatusRetryReady,
+			webhooks.OutboundDeliveryStatusProcessing,
+			now,
+		).Scan(ctx, &records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]webhooks.OutboundDelivery, 0, len(records))
+	for i := range records {
+		out = append(out, outboundDeliveryToDomain(&records[i]))
+	}
+	return out, nil
+}
+
+func (s *OutboundDeliveryStore) Get(ctx context.Context, id string) (webhooks.OutboundDelivery, error) {
+	if s == nil || s.db == nil {
+		return webhooks.OutboundDelivery{}, fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	record := &outboundWebhookDeliveryRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", strings.TrimSpace(id)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhooks.OutboundDelivery{}, fmt.Errorf("sqlstore: outbound delivery %q not found", id)
+		}
+		return webhooks.OutboundDelivery{}, err
+	}
+	return outboundDeliveryToDomain(record), nil
+}
+
+func (s *OutboundDeliveryStore) ListByEndpoint(
+	ctx context.Context,
+	endpointID string,
+	limit int,
+) ([]webhooks.OutboundDelivery, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	var records []*outboundWebhookDeliveryRecord
+	err := s.db.NewSelect().
+		Model(&records).
+		Where("?TableAlias.endpoint_id = ?", strings.TrimSpace(endpointID)).
+		OrderExpr("?TableAlias.created_at DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]webhooks.OutboundDelivery, 0, len(records))
+	for _, record := range records {
+		out = append(out, outboundDeliveryToDomain(record))
+	}
+	return out, nil
+}
+
+func (s *OutboundDeliveryStore) Complete(
+	ctx context.Context,
+	id string,
+	result webhooks.OutboundDeliveryResult,
+) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	now := time.Now().UTC()
+	_, err := s.db.NewUpdate().
+		Model((*outboundWebhookDeliveryRecord)(nil)).
+		Set("status = ?", webhooks.OutboundDeliveryStatusDelivered).
+		Set("last_status_code = ?", result.StatusCode).
+		Set("last_error = ?", "").
+		Set("next_attempt_at = NULL").
+		Set("delivered_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", strings.TrimSpace(id)).
+		Exec(ctx)
+	return err
+}
+
+func (s *OutboundDeliveryStore) Fail(
+	ctx context.Context,
+	id string,
+	result webhooks.OutboundDeliveryResult,
+) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	status := webhooks.OutboundDeliveryStatusRetryReady
+	next := copyTimePointer(result.NextAttemptAt)
+	if result.Dead || next == nil {
+		status = webhooks.OutboundDeliveryStatusDead
+		next = nil
+	}
+	_, err := s.db.NewUpdate().
+		Model((*outboundWebhookDeliveryRecord)(nil)).
+		Set("status = ?", status).
+		Set("last_status_code = ?", result.StatusCode).
+		Set("last_error = ?", strings.TrimSpace(result.Error)).
+		Set("next_attempt_at = ?", next).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", strings.TrimSpace(id)).
+		Exec(ctx)
+	return err
+}
+
+// Defer returns a claimed delivery to pending without consuming an attempt.
+func (s *OutboundDeliveryStore) Defer(ctx context.Context, id string, nextAttemptAt time.Time) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	_, err := s.db.NewUpdate().
+		Model((*outboundWebhookDeliveryRecord)(nil)).
+		Set("status = ?", webhooks.OutboundDeliveryStatusPending).
+		Set("attempts = CASE WHEN attempts > 0 THEN attempts - 1 ELSE 0 END").
+		Set("next_attempt_at = ?", nextAttemptAt.UTC()).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", strings.TrimSpace(id)).
+		Exec(ctx)
+	return err
+}
+
+// Requeue resets a delivery for manual redelivery with a fresh attempt budget.
+func (s *OutboundDeliveryStore) Requeue(ctx context.Context, id string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbound delivery store is not configured")
+	}
+	result, err := s.db.NewUpdate().
+		Model((*outboundWebhookDeliveryRecord)(nil)).
+		Set("status = ?", webhooks.OutboundDeliveryStatusPending).
+		Set("attempts = 0").
+		Set("next_attempt_at = NULL").
+		Set("last_error = ?", "").
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", strings.TrimSpace(id)).
+		Where("status <> ?", webhooks.OutboundDeliveryStatusProcessing).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("sqlstore: outbound delivery %q not found or in progress", id)
+	}
+	return nil
+}
+
+func outboundDeliveryToDomain(record *outboundWebhookDeliveryRecord) webhooks.OutboundDelivery {
+	if record == nil {
+		return webhooks.OutboundDelivery{}
+	}
+	return webhooks.OutboundDelivery{
+		ID:             record.ID,
+		EndpointID:     record.EndpointID,
+		EventID:        record.EventID,
+		EventName:      record.EventName,
+		Payload:        append([]byte(nil), record.Payload...),
+		Status:         record.Status,
+		Attempts:       record.Attempts,
+		NextAttemptAt:  copyTimePointer(record.NextAttemptAt),
+		LastStatusCode: record.LastStatusCode,
+		LastError:      record.LastError,
+		DeliveredAt:    copyTimePointer(record.DeliveredAt),
+		CreatedAt:      record.CreatedAt,
+		UpdatedAt:      record.UpdatedAt,
+	}
+}
+
+func normalizeEventFilters(filters []string) []string {
+	out := make([]string, 0, len(filters))
+	seen := map[string]struct{}{}
+	for _, filter := range filters {
+		filter = strings.ToLower(strings.TrimSpace(filter))
+		if filter == "" {
+			continue
+		}
+		if _, ok := seen[filter]; ok {
+			continue
+		}
+		seen[filter] = struct{}{}
+		out = append(out, filter)
+	}
+	return out
+}
+
+var (
+	_ webhooks.OutboundEndpointStore = (*OutboundEndpointStore)(nil)
+	_ webhooks.OutboundDeliveryStore = (*OutboundDeliveryStore)(nil)
+)
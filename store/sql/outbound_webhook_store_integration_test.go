@@ -0,0 +1,303 @@
+package sqlstore_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+	serviceswebhooks "github.com/goliatone/go-services/webhooks"
+)
+
+func TestOutboundWebhookStores_DeliverLifecycleEventsEndToEnd(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	localPolicy := serviceswebhooks.OutboundURLPolicy{AllowHTTP: true, AllowPrivateNetworks: true}
+	factory := sqlstore.NewRepositoryFactory(
+		sqlstore.WithSecretProvider(newServiceSecretProvider(t)),
+		sqlstore.WithOutboundURLPolicy(localPolicy),
+	)
+	if _, err := factory.BuildStores(client); err != nil {
+		t.Fatalf("build stores: %v", err)
+	}
+	endpoints := factory.OutboundEndpointStore()
+	deliveries := factory.OutboundDeliveryStore()
+	if endpoints == nil || deliveries == nil {
+		t.Fatalf("expected outbound stores to be wired")
+	}
+
+	var received [][]byte
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, body)
+		headers = append(headers, r.Header.Clone())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	endpoint, err := endpoints.Register(ctx, serviceswebhooks.RegisterOutboundEndpointInput{
+		ScopeType:    "org",
+		ScopeID:      "org_outbound",
+		URL:          server.URL,
+		Secret:       "whsec_integration",
+		EventFilters: []string{"connection.*"},
+	})
+	if err != nil {
+		t.Fatalf("register endpoint: %v", err)
+	}
+	if endpoint.Secret != "whsec_integration" {
+		t.Fatalf("expected decrypted secret round trip")
+	}
+	var stored []byte
+	if err := client.DB().NewRaw(
+		"SELECT encrypted_secret FROM service_outbound_webhook_endpoints WHERE id = ?",
+		endpoint.ID,
+	).Scan(ctx, &stored); err != nil {
+		t.Fatalf("load encrypted secret: %v", err)
+	}
+	if bytes.Contains(stored, []byte("whsec_integration")) {
+		t.Fatalf("expected endpoint secret to be encrypted at rest")
+	}
+
+	projector := serviceswebhooks.NewOutboundProjector(endpoints, deliveries)
+	event := core.LifecycleEvent{
+		ID:         "evt_outbound_1",
+		Name:       "connection.connected",
+		ProviderID: "github",
+		ScopeType:  "org",
+		ScopeID:    "org_outbound",
+		OccurredAt: time.Now().UTC(),
+	}
+	for range 2 {
+		if err := projector.Handle(ctx, event); err != nil {
+			t.Fatalf("project event: %v", err)
+		}
+	}
+
+	deliverer, err := serviceswebhooks.NewOutboundDeliverer(
+		endpoints,
+		deliveries,
+		server.Client(),
+		serviceswebhooks.OutboundDelivererConfig{URLPolicy: localPolicy},
+	)
+	if err != nil {
+		t.Fatalf("new deliverer: %v", err)
+	}
+	stats, err := deliverer.DeliverPending(ctx, 10)
+	if err != nil {
+		t.Fatalf("deliver pending: %v", err)
+	}
+	if stats.Claimed != 1 || stats.Delivered != 1 {
+		t.Fatalf("expected one deduped delivery, got %+v", stats)
+	}
+	if err := serviceswebhooks.NewOutboundSignatureVerifier("whsec_integration").Verify(ctx, core.InboundRequest{
+		Headers: map[string]string{
+			serviceswebhooks.OutboundHeaderSignature: headers[0].Get(serviceswebhooks.OutboundHeaderSignature),
+			serviceswebhooks.OutboundHeaderTimestamp: headers[0].Get(serviceswebhooks.OutboundHeaderTimestamp),
+		},
+		Body: received[0],
+	}); err != nil {
+		t.Fatalf("verify outbound signature: %v", err)
+	}
+
+	log, err := deliveries.ListByEndpoint(ctx, endpoint.ID, 10)
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	if len(log) != 1 || log[0].Status != serviceswebhooks.OutboundDeliveryStatusDelivered || log[0].DeliveredAt == nil {
+		t.Fatalf("expected delivered log entry, got %+v", log)
+	}
+
+	if err := deliverer.Redeliver(ctx, log[0].ID); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	stats, err = deliverer.DeliverPending(ctx, 10)
+	if err != nil || stats.Delivered != 1 {
+		t.Fatalf("expected manual redelivery to send again, stats=%+v err=%v", stats, err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected two posts, got %d", len(received))
+	}
+}
+
+func TestOutboundDeliveryStore_ClaimLeaseAndFailureStates(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	endpoints, err := sqlstore.NewOutboundEndpointStore(client.DB(), newServiceSecretProvider(t))
+	if err != nil {
+		t.Fatalf("new endpoint store: %v", err)
+	}
+	deliveries, err := sqlstore.NewOutboundDeliveryStore(client.DB())
+	if err != nil {
+		t.Fatalf("new delivery store: %v", err)
+	}
+	endpoint, err := endpoints.Register(ctx, serviceswebhooks.RegisterOutboundEndpointInput{
+		ScopeType: "org",
+		ScopeID:   "org_fail",
+		URL:       "https://hooks.example.com/in",
+		Secret:    "whsec",
+	})
+	if err != nil {
+		t.Fatalf("register endpoint: %v", err)
+	}
+	if _, err := endpoints.Register(ctx, serviceswebhooks.RegisterOutboundEndpointInput{
+		ScopeType: "org",
+		ScopeID:   "org_fail",
+		URL:       "not a url",
+		Secret:    "whsec",
+	}); err == nil {
+		t.Fatalf("expected invalid url to be rejected")
+	}
+
+	for _, rejected := range []string{"http://hooks.example.com/in", "https://127.0.0.1/in", "https://169.254.169.254/"} {
+		if _, err := endpoints.Register(ctx, serviceswebhooks.RegisterOutboundEndpointInput{
+			ScopeType: "org",
+			ScopeID:   "org_fail",
+			URL:       rejected,
+			Secret:    "whsec",
+		}); err == nil {
+			t.Fatalf("expected %s to be rejected by the default url policy", rejected)
+		}
+	}
+
+	delivery, created, err := deliveries.Enqueue(ctx, serviceswebhooks.OutboundDelivery{
+		EndpointID: endpoint.ID,
+		EventID:    "evt_fail_1",
+		EventName:  "sync.failed",
+		Payload:    []byte(`{}`),
+	})
+	if err != nil || !created {
+		t.Fatalf("enqueue: created=%v err=%v", created, err)
+	}
+	claimed, err := deliveries.ClaimDue(ctx, 10, time.Minute)
+	if err != nil || len(claimed) != 1 || claimed[0].Attempts != 1 {
+		t.Fatalf("expected single claim with attempt 1, got %+v err=%v", claimed, err)
+	}
+	if again, _ := deliveries.ClaimDue(ctx, 10, time.Minute); len(again) != 0 {
+		t.Fatalf("expected leased delivery not to be reclaimed")
+	}
+
+	future := time.Now().UTC().Add(time.Hour)
+	if err := deliveries.Fail(ctx, delivery.ID, serviceswebhooks.OutboundDeliveryResult{
+		StatusCode:    http.StatusBadGateway,
+		Error:         "bad gateway",
+		NextAttemptAt: &future,
+	}); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	failed, err := deliveries.Get(ctx, delivery.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if failed.Status != serviceswebhooks.OutboundDeliveryStatusRetryReady || failed.LastStatusCode != http.StatusBadGateway {
+		t.Fatalf("unexpected failed delivery %+v", failed)
+	}
+
+	if err := deliveries.Fail(ctx, delivery.ID, serviceswebhooks.OutboundDeliveryResult{Error: "gone", Dead: true}); err != nil {
+		t.Fatalf("fail dead: %v", err)
+	}
+	dead, _ := deliveries.Get(ctx, delivery.ID)
+	if dead.Status != serviceswebhooks.OutboundDeliveryStatusDead {
+		t.Fatalf("expected dead delivery, got %q", dead.Status)
+	}
+}
+
+func TestOutboundEndpointStore_UpdateEnableAndDelete(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	endpoints, err := sqlstore.NewOutboundEndpointStore(client.DB(), newServiceSecretProvider(t))
+	if err != nil {
+		t.Fatalf("new endpoint store: %v", err)
+	}
+	deliveries, err := sqlstore.NewOutboundDeliveryStore(client.DB())
+	if err != nil {
+		t.Fatalf("new delivery store: %v", err)
+	}
+	endpoint, err := endpoints.Register(ctx, serviceswebhooks.RegisterOutboundEndpointInput{
+		ScopeType: "org",
+		ScopeID:   "org_manage",
+		URL:       "https://hooks.example.com/in",
+		Secret:    "whsec_old",
+	})
+	if err != nil {
+		t.Fatalf("register endpoint: %v", err)
+	}
+
+	updated, err := endpoints.Update(ctx, endpoint.ID, serviceswebhooks.UpdateOutboundEndpointInput{
+		URL:          "https://hooks.example.com/v2",
+		EventFilters: []string{"sync.*"},
+	})
+	if err != nil {
+		t.Fatalf("update endpoint: %v", err)
+	}
+	if updated.URL != "https://hooks.example.com/v2" || updated.Secret != "whsec_old" ||
+		len(updated.EventFilters) != 1 || updated.EventFilters[0] != "sync.*" {
+		t.Fatalf("expected url and filters replaced with secret kept, got %+v", updated)
+	}
+	if _, err := endpoints.Update(ctx, endpoint.ID, serviceswebhooks.UpdateOutboundEndpointInput{
+		URL: "https://10.1.2.3/in",
+	}); err == nil {
+		t.Fatalf("expected update to an internal address to be rejected")
+	}
+	rotated, err := endpoints.Update(ctx, endpoint.ID, serviceswebhooks.UpdateOutboundEndpointInput{
+		URL:    updated.URL,
+		Secret: "whsec_new",
+	})
+	if err != nil || rotated.Secret != "whsec_new" {
+		t.Fatalf("expected secret rotation, got %+v err=%v", rotated, err)
+	}
+
+	openUntil := time.Now().UTC().Add(time.Hour)
+	if err := endpoints.UpdateHealth(ctx, endpoint.ID, serviceswebhooks.OutboundEndpointHealth{
+		Status:              serviceswebhooks.OutboundEndpointStatusDisabled,
+		DisabledReason:      "auto-disabled",
+		ConsecutiveFailures: 50,
+		CircuitOpenUntil:    &openUntil,
+	}); err != nil {
+		t.Fatalf("disable endpoint: %v", err)
+	}
+	enabled, err := endpoints.Enable(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("enable endpoint: %v", err)
+	}
+	if enabled.Status != serviceswebhooks.OutboundEndpointStatusActive || enabled.DisabledReason != "" ||
+		enabled.ConsecutiveFailures != 0 || enabled.CircuitOpenUntil != nil {
+		t.Fatalf("expected endpoint health reset on enable, got %+v", enabled)
+	}
+
+	if _, _, err := deliveries.Enqueue(ctx, serviceswebhooks.OutboundDelivery{
+		EndpointID: endpoint.ID,
+		EventID:    "evt_manage_1",
+		EventName:  "sync.completed",
+		Payload:    []byte(`{}`),
+	}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := endpoints.Delete(ctx, endpoint.ID); err != nil {
+		t.Fatalf("delete endpoint: %v", err)
+	}
+	if _, err := endpoints.Get(ctx, endpoint.ID); err == nil {
+		t.Fatalf("expected deleted endpoint to be gone")
+	}
+	if log, err := deliveries.ListByEndpoint(ctx, endpoint.ID, 10); err != nil || len(log) != 0 {
+		t.Fatalf("expected delivery log removed with endpoint, got %+v err=%v", log, err)
+	}
+	if err := endpoints.Delete(ctx, endpoint.ID); err == nil {
+		t.Fatalf("expected deleting a missing endpoint to fail")
+	}
+	if _, err := endpoints.Enable(ctx, endpoint.ID); err == nil {
+		t.Fatalf("expected enabling a missing endpoint to fail")
+	}
+}
@@ -0,0 +1,300 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+func (s *OutboxStore) List(ctx context.Context, filter core.OutboxFilter) (core.OutboxPage, error) {
+	if s == nil || s.db == nil {
+		return core.OutboxPage{}, fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	perPage := filter.PerPage
+	if perPage <= 0 {
+		perPage = 25
+	}
+	offset := (page - 1) * perPage
+
+	var records []lifecycleOutboxRecord
+	query := s.db.NewSelect().Model(&records)
+	for _, criterion := range []struct {
+		column string
+		value  string
+	}{
+		{"status", string(filter.Status)},
+		{"event_name", filter.EventName},
+		{"provider_id", filter.ProviderID},
+		{"scope_type", filter.ScopeType},
+		{"scope_id", filter.ScopeID},
+		{"connection_id", filter.ConnectionID},
+	} {
+		if value := strings.TrimSpace(criterion.value); value != "" {
+			query = query.Where("?TableAlias.? = ?", bun.Ident(criterion.column), value)
+		}
+	}
+	total, err := query.
+		OrderExpr("?TableAlias.occurred_at DESC, ?TableAlias.id DESC").
+		Limit(perPage).
+		Offset(offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return core.OutboxPage{}, err
+	}
+
+	eventIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		eventIDs = append(eventIDs, record.EventID)
+	}
+	deliveries, err := s.projectorDeliveries(ctx, eventIDs...)
+	if err != nil {
+		return core.OutboxPage{}, err
+	}
+	items := make([]core.OutboxEntry, 0, len(records))
+	for _, record := range records {
+		items = append(items, outboxRecordToEntry(record, deliveries[record.EventID]))
+	}
+	return core.OutboxPage{
+		Items:   items,
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		HasNext: offset+len(items) < total,
+	}, nil
+}
+
+func (s *OutboxStore) Get(ctx context.Context, eventID string) (core.OutboxEntry, error) {
+	if s == nil || s.db == nil {
+		return core.OutboxEntry{}, fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	eventID = strings.TrimSpace(eventID)
+	record := lifecycleOutboxRecord{}
+	err := s.db.NewSelect().
+		Model(&record).
+		Where("?TableAlias.event_id = ?", eventID).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.OutboxEntry{}, fmt.Errorf("sqlstore: outbox event %q not found", eventID)
+		}
+		return core.OutboxEntry{}, err
+	}
+	deliveries, err := s.projectorDeliveries(ctx, eventID)
+	if err != nil {
+		return core.OutboxEntry{}, err
+	}
+	return outboxRecordToEntry(record, deliveries[eventID]), nil
+}
+
+// Requeue returns a pending, failed or discarded event to pending with a fresh
+// attempt budget. Projector deliveries are kept, so projectors that already
+// succeeded are skipped on the next dispatch.
+func (s *OutboxStore) Requeue(ctx context.Context, eventID string) error {
+	return s.transition(ctx, eventID, outboxStatusPending, "not found, in progress or delivered",
+		outboxStatusPending, outboxStatusFailed, outboxStatusDiscarded)
+}
+
+// Discard parks a pending or failed event. Discarded events release their
+// partition and are never claimed until requeued.
+func (s *OutboxStore) Discard(ctx context.Context, eventID string) error {
+	return s.transition(ctx, eventID, outboxStatusDiscarded, "not found, in progress or already settled",
+		outboxStatusPending, outboxStatusFailed)
+}
+
+func (s *OutboxStore) Stats(ctx context.Context) (core.OutboxStats, error) {
+	if s == nil || s.db == nil {
+		return core.OutboxStats{}, fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	var counts []struct {
+		Status string `bun:"status"`
+		Count  int    `bun:"count"`
+	}
+	err := s.db.NewSelect().
+		Model((*lifecycleOutboxRecord)(nil)).
+		ColumnExpr("?TableAlias.status AS status").
+		ColumnExpr("COUNT(*) AS count").
+		Where("?TableAlias.status <> ?", outboxStatusDelivered).
+		GroupExpr("?TableAlias.status").
+		Scan(ctx, &counts)
+	if err != nil {
+		return core.OutboxStats{}, err
+	}
+	stats := core.OutboxStats{}
+	for _, row := range counts {
+		switch row.Status {
+		case outboxStatusPending:
+			stats.Pending = row.Count
+		case outboxStatusProcessing:
+			stats.Processing = row.Count
+		case outboxStatusFailed:
+			stats.Failed = row.Count
+		case outboxStatusDiscarded:
+			stats.Discarded = row.Count
+		}
+	}
+
+	oldest := lifecycleOutboxRecord{}
+	err = s.db.NewSelect().
+		Model(&oldest).
+		Where("?TableAlias.status IN (?)", bun.In([]string{outboxStatusPending, outboxStatusProcessing})).
+		OrderExpr("?TableAlias.occurred_at ASC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return core.OutboxStats{}, err
+	}
+	if err == nil {
+		stats.OldestPendingAt = copyTimePointer(&oldest.OccurredAt)
+	}
+	return stats, nil
+}
+
+func (s *OutboxStore) ListProjectorDeliveries(ctx context.Context, eventID string) ([]core.OutboxProjectorDelivery, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	eventID = strings.TrimSpace(eventID)
+	deliveries, err := s.projectorDeliveries(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries[eventID], nil
+}
+
+// RecordProjectorDelivery stores the outcome of one projector run. A nil cause
+// marks the projector as succeeded for the event.
+func (s *OutboxStore) RecordProjectorDelivery(ctx context.Context, eventID string, projector string, cause error) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	eventID = strings.TrimSpace(eventID)
+	projector = strings.TrimSpace(projector)
+	if eventID == "" || projector == "" {
+		return fmt.Errorf("sqlstore: event id and projector are required")
+	}
+	now := s.now()
+	status := string(core.OutboxProjectorStatusSucceeded)
+	lastError := ""
+	var deliveredAt *time.Time
+	if cause != nil {
+		status = string(core.OutboxProjectorStatusFailed)
+		lastError = strings.TrimSpace(cause.Error())
+	} else {
+		deliveredAt = &now
+	}
+
+	// Claims hand an event to a single dispatcher, so update-then-insert does
+	// not race with another writer for the same row.
+	result, err := s.db.NewUpdate().
+		Model((*lifecycleOutboxProjectorRecord)(nil)).
+		Set("status = ?", status).
+		Set("attempts = attempts + 1").
+		Set("last_error = ?", lastError).
+		Set("delivered_at = ?", deliveredAt).
+		Set("updated_at = ?", now).
+		Where("event_id = ?", eventID).
+		Where("projector = ?", projector).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		return nil
+	}
+	_, err = s.db.NewInsert().Model(&lifecycleOutboxProjectorRecord{
+		ID:          uuid.NewString(),
+		EventID:     eventID,
+		Projector:   projector,
+		Status:      status,
+		Attempts:    1,
+		LastError:   lastError,
+		DeliveredAt: deliveredAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}).Exec(ctx)
+	return err
+}
+
+func (s *OutboxStore) transition(ctx context.Context, eventID string, status string, failure string, from ...string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: outbox store is not configured")
+	}
+	eventID = strings.TrimSpace(eventID)
+	if eventID == "" {
+		return fmt.Errorf("sqlstore: event id is required")
+	}
+	query := s.db.NewUpdate().
+		Model((*lifecycleOutboxRecord)(nil)).
+		Set("status = ?", status).
+		Set("next_attempt_at = NULL").
+		Set("updated_at = ?", s.now()).
+		Where("event_id = ?", eventID).
+		Where("status IN (?)", bun.In(from))
+	if status == outboxStatusPending {
+		query = query.Set("attempts = 0").Set("last_error = ?", "")
+	}
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("sqlstore: outbox event %q %s", eventID, failure)
+	}
+	return nil
+}
+
+func (s *OutboxStore) projectorDeliveries(ctx context.Context, eventIDs ...string) (map[string][]core.OutboxProjectorDelivery, error) {
+	out := map[string][]core.OutboxProjectorDelivery{}
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+	var records []lifecycleOutboxProjectorRecord
+	err := s.db.NewSelect().
+		Model(&records).
+		Where("?TableAlias.event_id IN (?)", bun.In(eventIDs)).
+		OrderExpr("?TableAlias.projector ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		out[record.EventID] = append(out[record.EventID], core.OutboxProjectorDelivery{
+			EventID:     record.EventID,
+			Projector:   record.Projector,
+			Status:      core.OutboxProjectorStatus(record.Status),
+			Attempts:    record.Attempts,
+			LastError:   record.LastError,
+			DeliveredAt: copyTimePointer(record.DeliveredAt),
+			UpdatedAt:   record.UpdatedAt,
+		})
+	}
+	return out, nil
+}
+
+func outboxRecordToEntry(record lifecycleOutboxRecord, deliveries []core.OutboxProjectorDelivery) core.OutboxEntry {
+	event := outboxRecordToEvent(record)
+	delete(event.Metadata, core.MetadataKeyOutboxAttempts)
+	return core.OutboxEntry{
+		Event:         event,
+		PartitionKey:  record.PartitionKey,
+		Status:        core.OutboxEventStatus(record.Status),
+		Attempts:      record.Attempts,
+		NextAttemptAt: copyTimePointer(record.NextAttempt),
+		LastError:     record.LastError,
+		Projectors:    append([]core.OutboxProjectorDelivery(nil), deliveries...),
+		CreatedAt:     record.CreatedAt,
+		UpdatedAt:     record.UpdatedAt,
+	}
+}
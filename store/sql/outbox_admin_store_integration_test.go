@@ -0,0 +1,124 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestOutboxStore_DeadLettersCanBeInspectedRequeuedAndDiscarded(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	outboxStore := factory.OutboxStore()
+	connectionID := createOutboxTestConnection(t, factory, "acct_dlq")
+	base := time.Now().UTC().Add(-time.Hour)
+	for i, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		if err := outboxStore.Enqueue(ctx, outboxTestEvent(id, connectionID, base.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("enqueue %s: %v", id, err)
+		}
+	}
+
+	calls := map[string]int{}
+	notificationsDown := true
+	registry := core.NewLifecycleProjectorRegistry()
+	registry.Register("activity", outboxHandlerFunc(func(_ context.Context, event core.LifecycleEvent) error {
+		calls["activity:"+event.ID]++
+		return nil
+	}))
+	registry.Register("notifications", outboxHandlerFunc(func(_ context.Context, event core.LifecycleEvent) error {
+		calls["notifications:"+event.ID]++
+		if notificationsDown {
+			return errors.New("smtp down")
+		}
+		return nil
+	}))
+	dispatcher, err := core.NewOutboxDispatcher(outboxStore, registry, core.OutboxDispatcherConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+
+	// Each dead letter releases the partition for the next event.
+	for range 3 {
+		if _, err := dispatcher.DispatchPending(ctx, 10); err == nil {
+			t.Fatalf("expected projector failure")
+		}
+	}
+	stats, err := outboxStore.Stats(ctx)
+	if err != nil || stats.Failed != 3 || stats.Pending != 0 || stats.OldestPendingAt != nil {
+		t.Fatalf("unexpected stats %+v %v", stats, err)
+	}
+
+	page, err := outboxStore.List(ctx, core.OutboxFilter{Status: core.OutboxEventStatusFailed, ConnectionID: connectionID, PerPage: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if page.Total != 3 || len(page.Items) != 2 || !page.HasNext || page.Items[0].Event.ID != "evt_3" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	entry, err := outboxStore.Get(ctx, "evt_1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if entry.Status != core.OutboxEventStatusFailed || entry.Attempts != 1 || !strings.Contains(entry.LastError, "notifications") ||
+		len(entry.Projectors) != 2 || entry.Projectors[0].Status != core.OutboxProjectorStatusSucceeded ||
+		entry.Projectors[1].Status != core.OutboxProjectorStatusFailed || entry.Projectors[1].LastError != "smtp down" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	activity := factory.ActivityStore()
+	admin, err := core.NewOutboxAdmin(outboxStore, core.WithOutboxAdminActivitySink(activity))
+	if err != nil {
+		t.Fatalf("new outbox admin: %v", err)
+	}
+	if _, err := admin.Discard(ctx, core.OutboxAdminRequest{EventID: "evt_2", Actor: "user:ops", Reason: "obsolete"}); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	requeued, err := admin.Requeue(ctx, core.OutboxAdminRequest{EventID: "evt_1", Actor: "user:ops"})
+	if err != nil || requeued.Status != core.OutboxEventStatusPending || requeued.Attempts != 0 {
+		t.Fatalf("unexpected requeue %+v %v", requeued, err)
+	}
+
+	notificationsDown = false
+	dispatched, err := dispatcher.DispatchPending(ctx, 10)
+	if err != nil || dispatched.Delivered != 1 {
+		t.Fatalf("expected requeued event to deliver, got %+v %v", dispatched, err)
+	}
+	if calls["activity:evt_1"] != 1 || calls["notifications:evt_1"] != 2 {
+		t.Fatalf("expected only the failed projector to re-run, got %+v", calls)
+	}
+	if _, err := outboxStore.Get(ctx, "evt_2"); err != nil {
+		t.Fatalf("expected discarded event to stay inspectable: %v", err)
+	}
+	if err := outboxStore.Requeue(ctx, "evt_1"); err == nil {
+		t.Fatalf("expected delivered event requeue to be rejected")
+	}
+	if err := outboxStore.Discard(ctx, "evt_2"); err == nil {
+		t.Fatalf("expected discarded event discard to be rejected")
+	}
+
+	stats, err = outboxStore.Stats(ctx)
+	if err != nil || stats.Failed != 1 || stats.Discarded != 1 {
+		t.Fatalf("unexpected stats after admin actions %+v %v", stats, err)
+	}
+	audit, err := activity.List(ctx, core.ServicesActivityFilter{Connections: []string{connectionID}})
+	if err != nil {
+		t.Fatalf("list activity: %v", err)
+	}
+	actions := map[string]bool{}
+	for _, item := range audit.Items {
+		actions[item.Action] = true
+	}
+	if !actions["services.outbox.discarded"] || !actions["services.outbox.requeued"] {
+		t.Fatalf("expected audit entries for admin actions, got %+v", audit.Items)
+	}
+}
@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/goliatone/go-services/core"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 )
 
 const (
@@ -17,14 +19,67 @@ const (
 	outboxStatusProcessing = "processing"
 	outboxStatusDelivered  = "delivered"
 	outboxStatusFailed     = "failed"
+	outboxStatusDiscarded  = "discarded"
 )
 
+const defaultOutboxProcessingTimeout = 5 * time.Minute
+
+// OutboxPartitionFunc maps an event to its ordering partition. Events that
+// share a non-empty key are delivered one at a time in occurrence order;
+// events with an empty key are delivered without ordering.
+type OutboxPartitionFunc func(event core.LifecycleEvent) string
+
+// OutboxPartitionByConnection orders events per connection. Events without a
+// connection are unordered.
+func OutboxPartitionByConnection(event core.LifecycleEvent) string {
+	return strings.TrimSpace(event.ConnectionID)
+}
+
+// OutboxPartitionByScope orders events per provider and scope.
+func OutboxPartitionByScope(event core.LifecycleEvent) string {
+	return strings.TrimSpace(event.ProviderID) + ":" + strings.TrimSpace(event.ScopeType) + ":" + strings.TrimSpace(event.ScopeID)
+}
+
+// OutboxUnpartitioned disables ordering so every due event can be claimed.
+func OutboxUnpartitioned(core.LifecycleEvent) string {
+	return ""
+}
+
+type OutboxConfig struct {
+	// PartitionBy assigns events to ordering partitions when they are
+	// enqueued. Defaults to OutboxPartitionByConnection.
+	PartitionBy OutboxPartitionFunc
+	// ProcessingTimeout releases events claimed by a dispatcher that never
+	// acked or retried them, so a crashed dispatcher does not stall its
+	// partitions.
+	ProcessingTimeout time.Duration
+}
+
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		PartitionBy:       OutboxPartitionByConnection,
+		ProcessingTimeout: defaultOutboxProcessingTimeout,
+	}
+}
+
+// OutboxStore claims at most one event per partition at a time: the oldest
+// undelivered event of a partition blocks the ones behind it until it is
+// acked, fails terminally or is discarded, including while it waits out a
+// retry backoff.
+// Postgres claims rows with FOR UPDATE SKIP LOCKED so concurrent dispatchers
+// do not contend; SQLite relies on its single writer.
 type OutboxStore struct {
-	db   *bun.DB
-	repo repository.Repository[*lifecycleOutboxRecord]
+	db     *bun.DB
+	repo   repository.Repository[*lifecycleOutboxRecord]
+	config OutboxConfig
+	Now    func() time.Time
 }
 
 func NewOutboxStore(db *bun.DB) (*OutboxStore, error) {
+	return NewOutboxStoreWithConfig(db, DefaultOutboxConfig())
+}
+
+func NewOutboxStoreWithConfig(db *bun.DB, config OutboxConfig) (*OutboxStore, error) {
 	if db == nil {
 		return nil, fmt.Errorf("sqlstore: bun db is required")
 	}
@@ -34,7 +89,21 @@ func NewOutboxStore(db *bun.DB) (*OutboxStore, error) {
 			return nil, fmt.Errorf("sqlstore: invalid outbox repository wiring: %w", err)
 		}
 	}
-	return &OutboxStore{db: db, repo: repo}, nil
+	defaults := DefaultOutboxConfig()
+	if config.PartitionBy == nil {
+		config.PartitionBy = defaults.PartitionBy
+	}
+	if config.ProcessingTimeout <= 0 {
+		config.ProcessingTimeout = defaults.ProcessingTimeout
+	}
+	return &OutboxStore{
+		db:     db,
+		repo:   repo,
+		config: config,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
 }
 
 func (s *OutboxStore) Enqueue(ctx context.Context, event core.LifecycleEvent) error {
@@ -54,26 +123,27 @@ func (s *OutboxStore) Enqueue(ctx context.Context, event core.LifecycleEvent) er
 		return fmt.Errorf("sqlstore: outbox scope type and scope id are required")
 	}
 
+	now := s.now()
 	occurredAt := event.OccurredAt.UTC()
 	if occurredAt.IsZero() {
-		occurredAt = time.Now().UTC()
+		occurredAt = now
 	}
-	now := time.Now().UTC()
 	record := &lifecycleOutboxRecord{
-		ID:         uuid.NewString(),
-		EventID:    strings.TrimSpace(event.ID),
-		EventName:  strings.TrimSpace(event.Name),
-		ProviderID: strings.TrimSpace(event.ProviderID),
-		ScopeType:  strings.TrimSpace(event.ScopeType),
-		ScopeID:    strings.TrimSpace(event.ScopeID),
-		Payload:    copyAnyMap(event.Payload),
-		Metadata:   copyAnyMap(event.Metadata),
-		Status:     outboxStatusPending,
-		Attempts:   0,
-		LastError:  "",
-		OccurredAt: occurredAt,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:           uuid.NewString(),
+		EventID:      strings.TrimSpace(event.ID),
+		EventName:    strings.TrimSpace(event.Name),
+		ProviderID:   strings.TrimSpace(event.ProviderID),
+		ScopeType:    strings.TrimSpace(event.ScopeType),
+		ScopeID:      strings.TrimSpace(event.ScopeID),
+		PartitionKey: strings.TrimSpace(s.config.PartitionBy(event)),
+		Payload:      copyAnyMap(event.Payload),
+		Metadata:     copyAnyMap(event.Metadata),
+		Status:       outboxStatusPending,
+		Attempts:     0,
+		LastError:    "",
+		OccurredAt:   occurredAt,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 	if trimmed := strings.TrimSpace(event.ConnectionID); trimmed != "" {
 		record.ConnectionID = &trimmed
@@ -83,6 +153,8 @@ func (s *OutboxStore) Enqueue(ctx context.Context, event core.LifecycleEvent) er
 	return err
 }
 
+// ClaimBatch claims up to limit due events, taking only the head of each
+// partition. Events are returned in occurrence order.
 func (s *OutboxStore) ClaimBatch(ctx context.Context, limit int) ([]core.LifecycleEvent, error) {
 	if s == nil || s.db == nil {
 		return nil, fmt.Errorf("sqlstore: outbox store is not configured")
@@ -90,22 +162,44 @@ func (s *OutboxStore) ClaimBatch(ctx context.Context, limit int) ([]core.Lifecyc
 	if limit <= 0 {
 		limit = 1
 	}
-	now := time.Now().UTC()
+	now := s.now()
+	staleBefore := now.Add(-s.config.ProcessingTimeout)
+	lockClause := ""
+	if s.db.Dialect().Name() == dialect.PG {
+		lockClause = "FOR UPDATE OF o SKIP LOCKED"
+	}
 	var records []lifecycleOutboxRecord
 	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		// A row is claimable when it is due, or was claimed by a dispatcher
+		// that went away, and no older unfinished row shares its partition.
 		query := `
 WITH claimed AS (
-	SELECT id
-	FROM service_lifecycle_outbox
-	WHERE status = ?
-	  AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
-	ORDER BY occurred_at ASC
+	SELECT o.id
+	FROM service_lifecycle_outbox AS o
+	WHERE ((o.status = ? AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= ?))
+	    OR (o.status = ? AND o.updated_at <= ?))
+	  AND (o.partition_key = '' OR NOT EXISTS (
+		SELECT 1
+		FROM service_lifecycle_outbox AS p
+		WHERE p.partition_key = o.partition_key
+		  AND p.id <> o.id
+		  AND ((p.status = ? AND p.updated_at > ?)
+		    OR (p.status IN (?, ?)
+		        AND (p.occurred_at < o.occurred_at
+		          OR (p.occurred_at = o.occurred_at AND p.created_at < o.created_at)
+		          OR (p.occurred_at = o.occurred_at AND p.created_at = o.created_at AND p.id < o.id))))
+	  ))
+	ORDER BY o.occurred_at ASC, o.created_at ASC, o.id ASC
 	LIMIT ?
+	` + lockClause + `
 )
 UPDATE service_lifecycle_outbox
 SET status = ?, updated_at = ?
 WHERE id IN (SELECT id FROM claimed)
-  AND status = ?
+  AND (status = ? OR (status = ? AND updated_at <= ?))
 RETURNING
 	id,
 	event_id,
@@ -114,6 +208,7 @@ RETURNING
 	scope_type,
 	scope_id,
 	connection_id,
+	partition_key,
 	payload,
 	metadata,
 	status,
@@ -128,16 +223,33 @@ RETURNING
 			query,
 			outboxStatusPending,
 			now,
+			outboxStatusProcessing,
+			staleBefore,
+			outboxStatusProcessing,
+			staleBefore,
+			outboxStatusPending,
+			outboxStatusProcessing,
 			limit,
 			outboxStatusProcessing,
 			now,
 			outboxStatusPending,
+			outboxStatusProcessing,
+			staleBefore,
 		).Scan(ctx, &records)
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].OccurredAt.Equal(records[j].OccurredAt) {
+			return records[i].OccurredAt.Before(records[j].OccurredAt)
+		}
+		if !records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].CreatedAt.Before(records[j].CreatedAt)
+		}
+		return records[i].ID < records[j].ID
+	})
 	events := make([]core.LifecycleEvent, 0, len(records))
 	for _, record := range records {
 		events = append(events, outboxRecordToEvent(record))
@@ -153,15 +265,20 @@ func (s *OutboxStore) Ack(ctx context.Context, eventID string) error {
 	if eventID == "" {
 		return fmt.Errorf("sqlstore: event id is required")
 	}
-	_, err := s.db.NewUpdate().
-		Model((*lifecycleOutboxRecord)(nil)).
-		Set("status = ?", outboxStatusDelivered).
-		Set("last_error = ?", "").
-		Set("next_attempt_at = NULL").
-		Set("updated_at = ?", time.Now().UTC()).
-		Where("event_id = ?", eventID).
-		Exec(ctx)
-	return err
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().
+			Model((*lifecycleOutboxRecord)(nil)).
+			Set("status = ?", outboxStatusDelivered).
+			Set("last_error = ?", "").
+			Set("next_attempt_at = NULL").
+			Set("updated_at = ?", s.now()).
+			Where("event_id = ?", eventID).
+			Exec(ctx)
+		return err
+	})
 }
 
 func (s *OutboxStore) Retry(ctx context.Context, eventID string, cause error, nextAttemptAt time.Time) error {
@@ -185,16 +302,28 @@ func (s *OutboxStore) Retry(ctx context.Context, eventID string, cause error, ne
 	if cause != nil {
 		lastError = strings.TrimSpace(cause.Error())
 	}
-	_, err := s.db.NewUpdate().
-		Model((*lifecycleOutboxRecord)(nil)).
-		Set("status = ?", status).
-		Set("attempts = attempts + 1").
-		Set("next_attempt_at = ?", next).
-		Set("last_error = ?", lastError).
-		Set("updated_at = ?", time.Now().UTC()).
-		Where("event_id = ?", eventID).
-		Exec(ctx)
-	return err
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().
+			Model((*lifecycleOutboxRecord)(nil)).
+			Set("status = ?", status).
+			Set("attempts = attempts + 1").
+			Set("next_attempt_at = ?", next).
+			Set("last_error = ?", lastError).
+			Set("updated_at = ?", s.now()).
+			Where("event_id = ?", eventID).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (s *OutboxStore) now() time.Time {
+	if s != nil && s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
 }
 
 func outboxRecordToEvent(record lifecycleOutboxRecord) core.LifecycleEvent {
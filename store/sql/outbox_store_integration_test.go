@@ -0,0 +1,240 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestOutboxStore_DeliversPartitionsInOrderAndBlocksOnlyFailingPartition(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	connA := createOutboxTestConnection(t, factory, "acct_a")
+	connB := createOutboxTestConnection(t, factory, "acct_b")
+
+	outboxStore, err := sqlstore.NewOutboxStoreWithConfig(client.DB(), sqlstore.OutboxConfig{ProcessingTimeout: time.Minute})
+	if err != nil {
+		t.Fatalf("new outbox store: %v", err)
+	}
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	outboxStore.Now = func() time.Time { return now }
+
+	base := now.Add(-time.Hour)
+	for i, spec := range []struct {
+		id           string
+		connectionID string
+	}{
+		{"a1", connA}, {"b1", connB}, {"a2", connA}, {"n1", ""}, {"b2", connB}, {"a3", connA}, {"n2", ""},
+	} {
+		if err := outboxStore.Enqueue(ctx, outboxTestEvent(spec.id, spec.connectionID, base.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("enqueue %s: %v", spec.id, err)
+		}
+	}
+
+	expectClaim(t, outboxStore, "a1,b1,n1,n2")
+	// Heads are in flight, so their partitions yield nothing further.
+	expectClaim(t, outboxStore, "")
+
+	for _, id := range []string{"b1", "n1", "n2"} {
+		mustAck(t, outboxStore, id)
+	}
+	if err := outboxStore.Retry(ctx, "a1", errors.New("projector down"), now.Add(time.Minute)); err != nil {
+		t.Fatalf("retry a1: %v", err)
+	}
+	// a1 waits out its backoff and holds back a2; b keeps flowing.
+	expectClaim(t, outboxStore, "b2")
+	mustAck(t, outboxStore, "b2")
+	expectClaim(t, outboxStore, "")
+
+	now = now.Add(2 * time.Minute)
+	expectClaim(t, outboxStore, "a1")
+	mustAck(t, outboxStore, "a1")
+	expectClaim(t, outboxStore, "a2")
+
+	// A terminal failure releases the partition.
+	if err := outboxStore.Retry(ctx, "a2", errors.New("bad payload"), time.Time{}); err != nil {
+		t.Fatalf("fail a2: %v", err)
+	}
+	expectClaim(t, outboxStore, "a3")
+
+	// A dispatcher that claims and disappears releases the event once the
+	// processing timeout lapses.
+	expectClaim(t, outboxStore, "")
+	now = now.Add(2 * time.Minute)
+	expectClaim(t, outboxStore, "a3")
+}
+
+func TestOutboxStore_ConcurrentDispatchersPreservePartitionOrder(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	outboxStore := factory.OutboxStore()
+
+	const (
+		partitions   = 4
+		perPartition = 6
+	)
+	connections := make([]string, partitions)
+	for i := range connections {
+		connections[i] = createOutboxTestConnection(t, factory, fmt.Sprintf("acct_%d", i))
+	}
+	base := time.Now().UTC().Add(-time.Hour)
+	for seq := 0; seq < perPartition; seq++ {
+		for p, connectionID := range connections {
+			id := fmt.Sprintf("evt_%d_%d", p, seq)
+			if err := outboxStore.Enqueue(ctx, outboxTestEvent(id, connectionID, base.Add(time.Duration(seq*partitions+p)*time.Millisecond))); err != nil {
+				t.Fatalf("enqueue %s: %v", id, err)
+			}
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		inFlight  = map[string]int{}
+		delivered = map[string][]string{}
+		failures  []string
+		failedOne = map[string]bool{}
+	)
+	registry := core.NewLifecycleProjectorRegistry()
+	registry.Register("recorder", outboxHandlerFunc(func(_ context.Context, event core.LifecycleEvent) error {
+		mu.Lock()
+		inFlight[event.ConnectionID]++
+		if inFlight[event.ConnectionID] > 1 {
+			failures = append(failures, "overlapping delivery in partition "+event.ConnectionID)
+		}
+		// The third event of each partition fails its first delivery, so
+		// retries happen in the middle of a partition.
+		failOnce := strings.HasSuffix(event.ID, "_2") && !failedOne[event.ID]
+		failedOne[event.ID] = true
+		if !failOnce {
+			delivered[event.ConnectionID] = append(delivered[event.ConnectionID], event.ID)
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight[event.ConnectionID]--
+		mu.Unlock()
+		if failOnce {
+			return errors.New("transient")
+		}
+		return nil
+	}))
+	dispatcher, err := core.NewOutboxDispatcher(outboxStore, registry, core.OutboxDispatcherConfig{
+		BatchSize:      3,
+		MaxAttempts:    5,
+		InitialBackoff: time.Nanosecond,
+		MaxBackoff:     time.Nanosecond,
+	})
+	if err != nil {
+		t.Fatalf("new dispatcher: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				_, _ = dispatcher.DispatchPending(ctx, 0)
+				mu.Lock()
+				total := 0
+				for _, ids := range delivered {
+					total += len(ids)
+				}
+				mu.Unlock()
+				if total == partitions*perPartition {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		t.Fatalf("partition exclusivity violated: %v", failures)
+	}
+	for p, connectionID := range connections {
+		ids := delivered[connectionID]
+		if len(ids) != perPartition {
+			t.Fatalf("expected %d deliveries for partition %d, got %v", perPartition, p, ids)
+		}
+		for seq, id := range ids {
+			if want := fmt.Sprintf("evt_%d_%d", p, seq); id != want {
+				t.Fatalf("partition %d delivered out of order: %v", p, ids)
+			}
+		}
+	}
+}
+
+type outboxHandlerFunc func(ctx context.Context, event core.LifecycleEvent) error
+
+func (fn outboxHandlerFunc) Handle(ctx context.Context, event core.LifecycleEvent) error {
+	return fn(ctx, event)
+}
+
+func createOutboxTestConnection(t *testing.T, factory *sqlstore.RepositoryFactory, externalAccountID string) string {
+	t.Helper()
+	connection, err := factory.ConnectionStore().Create(context.Background(), core.CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             core.ScopeRef{Type: "org", ID: "org_outbox"},
+		ExternalAccountID: externalAccountID,
+		Status:            core.ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	return connection.ID
+}
+
+func outboxTestEvent(id string, connectionID string, occurredAt time.Time) core.LifecycleEvent {
+	return core.LifecycleEvent{
+		ID:           id,
+		Name:         "connection.updated",
+		ProviderID:   "github",
+		ScopeType:    "org",
+		ScopeID:      "org_outbox",
+		ConnectionID: connectionID,
+		OccurredAt:   occurredAt,
+	}
+}
+
+func expectClaim(t *testing.T, store *sqlstore.OutboxStore, want string) {
+	t.Helper()
+	claimed, err := store.ClaimBatch(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("claim batch: %v", err)
+	}
+	ids := make([]string, 0, len(claimed))
+	for _, event := range claimed {
+		ids = append(ids, event.ID)
+	}
+	if got := strings.Join(ids, ","); got != want {
+		t.Fatalf("expected claim %q, got %q", want, got)
+	}
+}
+
+func mustAck(t *testing.T, store *sqlstore.OutboxStore, eventID string) {
+	t.Helper()
+	if err := store.Ack(context.Background(), eventID); err != nil {
+		t.Fatalf("ack %s: %v", eventID, err)
+	}
+}
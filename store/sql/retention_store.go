@@ -0,0 +1,309 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/webhooks"
+	"github.com/uptrace/bun"
+)
+
+// retentionTarget describes how one table is pruned. Rows are ranked per key
+// newest first by order. Only statuses listed in prunable can ever be
+// deleted; targets without a status column leave both status lists empty.
+// keepLatest protects the newest row of every key whatever the policy says.
+type retentionTarget struct {
+	table      string
+	timeColumn string
+	keyColumns []string
+	order      string
+	prunable   []string
+	defaults   []string
+	keepLatest bool
+	children   []retentionChild
+}
+
+// retentionChild is a dependent table whose rows are deleted with their
+// parent, so pruning does not rely on foreign key cascades being enabled.
+type retentionChild struct {
+	table        string
+	column       string
+	parentColumn string
+}
+
+var retentionTargets = map[core.RetentionTarget]retentionTarget{
+	core.RetentionTargetActivityEntries: {
+		table:      "service_activity_entries",
+		timeColumn: "created_at",
+		keyColumns: []string{"provider_id", "scope_type", "scope_id"},
+		order:      "created_at DESC, id DESC",
+		prunable: []string{
+			string(core.ServiceActivityStatusOK),
+			string(core.ServiceActivityStatusWarn),
+			string(core.ServiceActivityStatusError),
+		},
+	},
+	core.RetentionTargetWebhookDeliveries: {
+		table:      "service_webhook_deliveries",
+		timeColumn: "updated_at",
+		keyColumns: []string{"provider_id"},
+		order:      "updated_at DESC, id DESC",
+		prunable:   []string{webhooks.DeliveryStatusProcessed, webhooks.DeliveryStatusDead},
+	},
+	core.RetentionTargetOutboundWebhookDeliveries: {
+		table:      "service_outbound_webhook_deliveries",
+		timeColumn: "updated_at",
+		keyColumns: []string{"endpoint_id"},
+		order:      "updated_at DESC, id DESC",
+		prunable:   []string{webhooks.OutboundDeliveryStatusDelivered, webhooks.OutboundDeliveryStatusDead},
+	},
+	core.RetentionTargetLifecycleOutbox: {
+		table:      "service_lifecycle_outbox",
+		timeColumn: "updated_at",
+		keyColumns: []string{"partition_key"},
+		order:      "occurred_at DESC, created_at DESC, id DESC",
+		prunable:   []string{outboxStatusDelivered, outboxStatusDiscarded, outboxStatusFailed},
+		// Dead letters are kept until an operator requeues or discards them.
+		defaults: []string{outboxStatusDelivered, outboxStatusDiscarded},
+		children: []retentionChild{{
+			table:        "service_lifecycle_outbox_projectors",
+			column:       "event_id",
+			parentColumn: "event_id",
+		}},
+	},
+	core.RetentionTargetNotificationDispatches: {
+		table:      "service_notification_dispatches",
+		timeColumn: "created_at",
+		keyColumns: []string{"recipient_key"},
+		order:      "created_at DESC, id DESC",
+		prunable:   []string{"sent", "failed"},
+	},
+	core.RetentionTargetGrantEvents: {
+		table:      "service_grant_events",
+		timeColumn: "created_at",
+		keyColumns: []string{"connection_id"},
+		order:      "created_at DESC, id DESC",
+	},
+	core.RetentionTargetSyncChangeLog: {
+		table:      "service_sync_change_log",
+		timeColumn: "occurred_at",
+		keyColumns: []string{"sync_binding_id", "direction", "external_id"},
+		order:      "occurred_at DESC, created_at DESC, id DESC",
+		// Conflict detection compares against the latest change of a record.
+		keepLatest: true,
+	},
+	core.RetentionTargetCredentialVersions: {
+		table:      "service_credentials",
+		timeColumn: "updated_at",
+		keyColumns: []string{"connection_id"},
+		order:      "version DESC",
+		prunable:   []string{string(core.CredentialStatusRevoked), string(core.CredentialStatusExpired)},
+	},
+	core.RetentionTargetSyncJobIdempotency: {
+		table:      "service_sync_job_idempotency",
+		timeColumn: "created_at",
+		keyColumns: []string{"scope_type", "scope_id", "provider_id"},
+		order:      "created_at DESC, id DESC",
+	},
+}
+
+// RetentionStore prunes the high-volume tables in small batches: each batch
+// selects the next eligible primary keys after the previous batch and deletes
+// them in a short transaction, so writers are never blocked for the length of
+// a full prune.
+type RetentionStore struct {
+	db  *bun.DB
+	Now func() time.Time
+}
+
+func NewRetentionStore(db *bun.DB) (*RetentionStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	return &RetentionStore{
+		db: db,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
+}
+
+func (s *RetentionStore) Preview(ctx context.Context, policy core.RetentionPolicy) (core.RetentionResult, error) {
+	if s == nil || s.db == nil {
+		return core.RetentionResult{}, fmt.Errorf("sqlstore: retention store is not configured")
+	}
+	query, args, err := s.eligibleQuery(policy)
+	if err != nil {
+		return core.RetentionResult{}, err
+	}
+	var eligible int
+	if err := s.db.NewRaw("SELECT COUNT(*) FROM ("+query+") AS eligible", args...).Scan(ctx, &eligible); err != nil {
+		return core.RetentionResult{}, err
+	}
+	return core.RetentionResult{Target: policy.Target, DryRun: true, Eligible: eligible}, nil
+}
+
+func (s *RetentionStore) Prune(ctx context.Context, policy core.RetentionPolicy) (core.RetentionResult, error) {
+	if s == nil || s.db == nil {
+		return core.RetentionResult{}, fmt.Errorf("sqlstore: retention store is not configured")
+	}
+	target := retentionTargets[policy.Target]
+	query, args, err := s.eligibleQuery(policy)
+	if err != nil {
+		return core.RetentionResult{}, err
+	}
+	batchSize := policy.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	batchQuery := query + " AND id > ? ORDER BY id ASC LIMIT ?"
+
+	// Batches walk the eligible ids in order, each starting after the last id
+	// of the previous one, so a run visits every row once and always ends.
+	// The age cutoff is fixed when the run starts.
+	result := core.RetentionResult{Target: policy.Target}
+	last := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		var ids []string
+		if err := s.db.NewRaw(batchQuery, append(append([]any(nil), args...), last, batchSize)...).Scan(ctx, &ids); err != nil {
+			return result, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		result.Eligible += len(ids)
+		deleted, err := s.deleteBatch(ctx, target, ids)
+		if err != nil {
+			return result, err
+		}
+		result.Batches++
+		result.Deleted += deleted
+		if len(ids) < batchSize {
+			break
+		}
+		last = ids[len(ids)-1]
+	}
+	return result, nil
+}
+
+func (s *RetentionStore) deleteBatch(ctx context.Context, target retentionTarget, ids []string) (int, error) {
+	deleted := 0
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		for _, child := range target.children {
+			if _, err := tx.NewRaw(
+				fmt.Sprintf("DELETE FROM %s WHERE %s IN (SELECT %s FROM %s WHERE id IN (?))",
+					child.table, child.column, child.parentColumn, target.table),
+				bun.In(ids),
+			).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		res, err := tx.NewRaw(fmt.Sprintf("DELETE FROM %s WHERE id IN (?)", target.table), bun.In(ids)).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		affected, _ := res.RowsAffected()
+		deleted = int(affected)
+		return nil
+	})
+	return deleted, err
+}
+
+// eligibleQuery selects the id of every row the policy allows to be deleted.
+// The query ends in its WHERE clause so Prune can append the batch cursor.
+func (s *RetentionStore) eligibleQuery(policy core.RetentionPolicy) (string, []any, error) {
+	target, ok := retentionTargets[policy.Target]
+	if !ok {
+		return "", nil, fmt.Errorf("sqlstore: unknown retention target %q", policy.Target)
+	}
+	if policy.MaxAge <= 0 && policy.KeepPerKey <= 0 {
+		return "", nil, fmt.Errorf("sqlstore: retention policy for %q needs a max age or a per-key cap", policy.Target)
+	}
+	joiner := " OR "
+	switch policy.Match {
+	case "", core.RetentionMatchAny:
+	case core.RetentionMatchAll:
+		joiner = " AND "
+	default:
+		return "", nil, fmt.Errorf("sqlstore: unknown retention match %q for %q", policy.Match, policy.Target)
+	}
+	statuses, err := retentionStatuses(policy, target)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rank := "0"
+	if policy.KeepPerKey > 0 || target.keepLatest {
+		rank = fmt.Sprintf("ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s)", strings.Join(target.keyColumns, ", "), target.order)
+	}
+	status := "''"
+	if len(target.prunable) > 0 {
+		status = "status"
+	}
+	rules := make([]string, 0, 2)
+	args := make([]any, 0, 4)
+	if len(statuses) > 0 {
+		args = append(args, bun.In(statuses))
+	}
+	if policy.MaxAge > 0 {
+		rules = append(rules, "retention_time < ?")
+		args = append(args, s.now().Add(-policy.MaxAge))
+	}
+	if policy.KeepPerKey > 0 {
+		rules = append(rules, "retention_rank > ?")
+		args = append(args, policy.KeepPerKey)
+	}
+	where := "(" + strings.Join(rules, joiner) + ")"
+	if len(statuses) > 0 {
+		where = "retention_status IN (?) AND " + where
+	}
+	if target.keepLatest {
+		where += " AND retention_rank > 1"
+	}
+	query := fmt.Sprintf(
+		"SELECT id FROM (SELECT id, %s AS retention_time, %s AS retention_status, %s AS retention_rank FROM %s) AS ranked WHERE %s",
+		target.timeColumn, status, rank, target.table, where,
+	)
+	return query, args, nil
+}
+
+func retentionStatuses(policy core.RetentionPolicy, target retentionTarget) ([]string, error) {
+	if len(target.prunable) == 0 {
+		if len(policy.Statuses) > 0 {
+			return nil, fmt.Errorf("sqlstore: retention target %q has no status column", policy.Target)
+		}
+		return nil, nil
+	}
+	if len(policy.Statuses) == 0 {
+		if len(target.defaults) > 0 {
+			return append([]string(nil), target.defaults...), nil
+		}
+		return append([]string(nil), target.prunable...), nil
+	}
+	statuses := make([]string, 0, len(policy.Statuses))
+	for _, status := range policy.Statuses {
+		status = strings.TrimSpace(status)
+		if !slices.Contains(target.prunable, status) {
+			return nil, fmt.Errorf("sqlstore: status %q is not prunable for retention target %q", status, policy.Target)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *RetentionStore) now() time.Time {
+	if s != nil && s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
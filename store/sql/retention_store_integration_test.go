@@ -0,0 +1,213 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+	"github.com/uptrace/bun"
+)
+
+func TestRetentionStore_PrunesInBatchesAndHonorsStatusAndKeyRules(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	db := client.DB()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	retention := factory.RetentionStore()
+	retention.Now = func() time.Time { return now }
+
+	// Webhook deliveries: five old processed rows, one old pending row and one
+	// recent processed row.
+	for i := 0; i < 7; i++ {
+		status, updatedAt := "processed", now.Add(-48*time.Hour)
+		switch i {
+		case 5:
+			status = "pending"
+		case 6:
+			updatedAt = now.Add(-time.Hour)
+		}
+		if _, err := db.NewRaw(
+			"INSERT INTO service_webhook_deliveries (id, provider_id, delivery_id, status, attempts, created_at, updated_at) VALUES (?, ?, ?, ?, 0, ?, ?)",
+			fmt.Sprintf("wd_%d", i), "github", fmt.Sprintf("delivery_%d", i), status, updatedAt, updatedAt,
+		).Exec(ctx); err != nil {
+			t.Fatalf("insert webhook delivery: %v", err)
+		}
+	}
+	deliveries := core.RetentionPolicy{Target: core.RetentionTargetWebhookDeliveries, MaxAge: 24 * time.Hour, BatchSize: 2}
+	preview, err := retention.Preview(ctx, deliveries)
+	if err != nil || preview.Eligible != 5 || preview.Deleted != 0 || !preview.DryRun {
+		t.Fatalf("unexpected preview %+v %v", preview, err)
+	}
+	if remaining := countRetentionRows(t, db, "service_webhook_deliveries"); remaining != 7 {
+		t.Fatalf("expected dry run to keep all rows, got %d", remaining)
+	}
+	pruned, err := retention.Prune(ctx, deliveries)
+	if err != nil || pruned.Deleted != 5 || pruned.Batches != 3 {
+		t.Fatalf("unexpected prune %+v %v", pruned, err)
+	}
+	if remaining := countRetentionRows(t, db, "service_webhook_deliveries"); remaining != 2 {
+		t.Fatalf("expected pending and recent deliveries to survive, got %d", remaining)
+	}
+
+	// Credential versions: the active version is never pruned and counts
+	// toward the versions kept per connection.
+	connectionID := createOutboxTestConnection(t, factory, "acct_retention")
+	for version := 1; version <= 4; version++ {
+		status := "revoked"
+		if version == 4 {
+			status = "active"
+		}
+		if _, err := db.NewRaw(
+			"INSERT INTO service_credentials (id, connection_id, version, encrypted_payload, token_type, status, created_at, updated_at) VALUES (?, ?, ?, ?, 'bearer', ?, ?, ?)",
+			fmt.Sprintf("cred_%d", version), connectionID, version, []byte("secret"), status, now, now,
+		).Exec(ctx); err != nil {
+			t.Fatalf("insert credential: %v", err)
+		}
+	}
+	credentials, err := retention.Prune(ctx, core.RetentionPolicy{Target: core.RetentionTargetCredentialVersions, KeepPerKey: 2})
+	if err != nil || credentials.Deleted != 2 {
+		t.Fatalf("unexpected credential prune %+v %v", credentials, err)
+	}
+	var versions []int
+	if err := db.NewRaw("SELECT version FROM service_credentials ORDER BY version").Scan(ctx, &versions); err != nil {
+		t.Fatalf("list credential versions: %v", err)
+	}
+	if fmt.Sprint(versions) != "[3 4]" {
+		t.Fatalf("expected newest versions to survive, got %v", versions)
+	}
+	if _, err := retention.Prune(ctx, core.RetentionPolicy{
+		Target:     core.RetentionTargetCredentialVersions,
+		KeepPerKey: 1,
+		Statuses:   []string{"active"},
+	}); err == nil {
+		t.Fatalf("expected active credentials to be rejected as prunable")
+	}
+
+	// Outbox: delivered events go with their projector rows; dead letters
+	// stay by default.
+	outboxStore := factory.OutboxStore()
+	outboxStore.Now = func() time.Time { return now.Add(-72 * time.Hour) }
+	for _, id := range []string{"evt_delivered", "evt_failed"} {
+		if err := outboxStore.Enqueue(ctx, outboxTestEvent(id, connectionID, now.Add(-72*time.Hour))); err != nil {
+			t.Fatalf("enqueue %s: %v", id, err)
+		}
+	}
+	if err := outboxStore.RecordProjectorDelivery(ctx, "evt_delivered", "activity", nil); err != nil {
+		t.Fatalf("record projector delivery: %v", err)
+	}
+	mustAck(t, outboxStore, "evt_delivered")
+	if err := outboxStore.Retry(ctx, "evt_failed", errors.New("boom"), time.Time{}); err != nil {
+		t.Fatalf("fail event: %v", err)
+	}
+	outbox, err := retention.Prune(ctx, core.RetentionPolicy{Target: core.RetentionTargetLifecycleOutbox, MaxAge: 24 * time.Hour})
+	if err != nil || outbox.Deleted != 1 {
+		t.Fatalf("unexpected outbox prune %+v %v", outbox, err)
+	}
+	if _, err := outboxStore.Get(ctx, "evt_failed"); err != nil {
+		t.Fatalf("expected dead letter to be kept: %v", err)
+	}
+	if remaining := countRetentionRows(t, db, "service_lifecycle_outbox_projectors"); remaining != 0 {
+		t.Fatalf("expected projector rows to be pruned with their event, got %d", remaining)
+	}
+
+	if _, err := retention.Prune(ctx, core.RetentionPolicy{Target: "unknown", MaxAge: time.Hour}); err == nil {
+		t.Fatalf("expected unknown target to be rejected")
+	}
+}
+
+func TestRetentionStore_KeepsLatestChangePerRecordAndCombinesRulesPerMatch(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+	factory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	db := client.DB()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	retention := factory.RetentionStore()
+	retention.Now = func() time.Time { return now }
+
+	// Sync change log: every entry is past the age cutoff, but the latest
+	// change of each record survives.
+	binding := seedSyncBinding(t, ctx, factory)
+	for idx, externalID := range []string{"ext_1", "ext_1", "ext_1", "ext_2"} {
+		if _, err := factory.SyncChangeLogStore().Append(ctx, core.SyncChangeLogEntry{
+			ProviderID:     binding.ProviderID,
+			Scope:          binding.Scope,
+			ConnectionID:   binding.ConnectionID,
+			SyncBindingID:  binding.ID,
+			Direction:      core.SyncDirectionImport,
+			SourceObject:   "contacts",
+			ExternalID:     externalID,
+			IdempotencyKey: fmt.Sprintf("change_%d", idx),
+			OccurredAt:     now.Add(-time.Duration(72-idx) * time.Hour),
+		}); err != nil {
+			t.Fatalf("append change: %v", err)
+		}
+	}
+	changes, err := retention.Prune(ctx, core.RetentionPolicy{Target: core.RetentionTargetSyncChangeLog, MaxAge: 24 * time.Hour, BatchSize: 1})
+	if err != nil || changes.Deleted != 2 || changes.Batches != 2 {
+		t.Fatalf("unexpected change log prune %+v %v", changes, err)
+	}
+	var kept []string
+	if err := db.NewRaw("SELECT idempotency_key FROM service_sync_change_log ORDER BY idempotency_key").Scan(ctx, &kept); err != nil {
+		t.Fatalf("list changes: %v", err)
+	}
+	if fmt.Sprint(kept) != "[change_2 change_3]" {
+		t.Fatalf("expected the latest change per record to survive, got %v", kept)
+	}
+
+	// Outbound deliveries: three old delivered rows behind a newer pending
+	// one. Any prunes every old delivered row; All keeps the newest two rows
+	// of the endpoint.
+	if _, err := db.NewRaw(
+		"INSERT INTO service_outbound_webhook_endpoints (id, scope_type, scope_id, url, encrypted_secret, status) VALUES ('ep_1', 'org', 'org_1', 'https://hooks.example.com', ?, 'active')",
+		[]byte("secret"),
+	).Exec(ctx); err != nil {
+		t.Fatalf("insert endpoint: %v", err)
+	}
+	for i, status := range []string{"delivered", "delivered", "delivered", "pending"} {
+		updatedAt := now.Add(-time.Duration(72-i) * time.Hour)
+		if _, err := db.NewRaw(
+			"INSERT INTO service_outbound_webhook_deliveries (id, endpoint_id, event_id, event_name, payload, status, created_at, updated_at) VALUES (?, 'ep_1', ?, 'connection.created', ?, ?, ?, ?)",
+			fmt.Sprintf("od_%d", i), fmt.Sprintf("evt_%d", i), []byte("{}"), status, updatedAt, updatedAt,
+		).Exec(ctx); err != nil {
+			t.Fatalf("insert outbound delivery: %v", err)
+		}
+	}
+	policy := core.RetentionPolicy{Target: core.RetentionTargetOutboundWebhookDeliveries, MaxAge: 24 * time.Hour, KeepPerKey: 2}
+	if preview, err := retention.Preview(ctx, policy); err != nil || preview.Eligible != 3 {
+		t.Fatalf("unexpected match any preview %+v %v", preview, err)
+	}
+	policy.Match = core.RetentionMatchAll
+	pruned, err := retention.Prune(ctx, policy)
+	if err != nil || pruned.Eligible != 2 || pruned.Deleted != 2 {
+		t.Fatalf("unexpected match all prune %+v %v", pruned, err)
+	}
+	if remaining := countRetentionRows(t, db, "service_outbound_webhook_deliveries"); remaining != 2 {
+		t.Fatalf("expected the newest two deliveries to survive, got %d", remaining)
+	}
+	policy.Match = "newest"
+	if _, err := retention.Preview(ctx, policy); err == nil {
+		t.Fatalf("expected unknown match to be rejected")
+	}
+}
+
+func countRetentionRows(t *testing.T, db *bun.DB, table string) int {
+	t.Helper()
+	var count int
+	if err := db.NewRaw("SELECT COUNT(*) FROM "+table).Scan(context.Background(), &count); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return count
+}
@@ -653,6 +653,33 @@ func TestWebhookDeliveryStore_MarksDeadAfterMaxAttempts(t *testing.T) {
 	}
 }
 
+func TestWebhookDeliveryStore_RecordsHandledEventsOnce(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	deliveryStore, err := sqlstore.NewWebhookDeliveryStore(client.DB())
+	if err != nil {
+		t.Fatalf("new webhook delivery store: %v", err)
+	}
+	for _, index := range []int{2, 0, 2} {
+		if err := deliveryStore.MarkEventHandled(ctx, "meta", "delivery-batch", index); err != nil {
+			t.Fatalf("mark event %d handled: %v", index, err)
+		}
+	}
+	if err := deliveryStore.MarkEventHandled(ctx, "meta", "delivery-other", 1); err != nil {
+		t.Fatalf("mark other delivery handled: %v", err)
+	}
+
+	handled, err := deliveryStore.HandledEvents(ctx, "meta", "delivery-batch")
+	if err != nil {
+		t.Fatalf("handled events: %v", err)
+	}
+	if len(handled) != 2 || handled[0] != 0 || handled[1] != 2 {
+		t.Fatalf("expected handled events [0 2], got %v", handled)
+	}
+}
+
 func TestWebhookDeliveryStore_ReclaimsExpiredProcessingLease(t *testing.T) {
 	ctx := context.Background()
 	client, cleanup := newSQLiteClient(t)
@@ -1248,6 +1275,22 @@ func TestInstallationStore_UpsertListAndStatusTransitions(t *testing.T) {
 	} else if !strings.Contains(strings.ToLower(err.Error()), "invalid installation status transition") {
 		t.Fatalf("expected transition validation error, got %v", err)
 	}
+	reinstalled, err := installationStore.Upsert(ctx, core.UpsertInstallationInput{
+		ProviderID:  "github",
+		Scope:       core.ScopeRef{Type: "org", ID: "org_install_1"},
+		InstallType: "marketplace_app",
+		Status:      core.InstallationStatusActive,
+		Metadata:    map[string]any{"installer": "admin_4"},
+	})
+	if err != nil {
+		t.Fatalf("reinstall after uninstall: %v", err)
+	}
+	if reinstalled.ID == installation.ID || reinstalled.Status != core.InstallationStatusActive {
+		t.Fatalf("expected reinstall to create a fresh installation, got %+v", reinstalled)
+	}
+	if previous, _ := installationStore.Get(ctx, installation.ID); previous.Status != core.InstallationStatusUninstalled {
+		t.Fatalf("expected previous installation to stay uninstalled, got %q", previous.Status)
+	}
 
 	if _, err := installationStore.Upsert(ctx, core.UpsertInstallationInput{
 		ProviderID:  "github",
@@ -1565,7 +1608,7 @@ func TestSubscriptionLifecycle_RenewAndCancel_Integration(t *testing.T) {
 			Metadata:             map[string]any{"lease": "initial"},
 		},
 		renewSubscriptionResponse: core.SubscriptionResult{
-			ChannelID:            "channel_1",
+			ChannelID:            "channel_2",
 			RemoteSubscriptionID: "remote_2",
 			ExpiresAt:            ptrTime(time.Now().UTC().Add(60 * time.Minute)),
 			Metadata:             map[string]any{"lease": "renewed"},
@@ -1617,6 +1660,17 @@ func TestSubscriptionLifecycle_RenewAndCancel_Integration(t *testing.T) {
 	if renewed.RemoteSubscriptionID != "remote_2" {
 		t.Fatalf("expected remote_2 subscription id")
 	}
+	// A renewal that opened a new channel replaces the record of the old one.
+	if renewed.ID != subscription.ID || renewed.ChannelID != "channel_2" {
+		t.Fatalf("expected the renewal to move the subscription to channel_2, got %+v", renewed)
+	}
+	listed, err := subscriptionStore.ListByConnection(ctx, connection.ID)
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("expected exactly one subscription after renewal, got %+v %v", listed, err)
+	}
+	if _, err := subscriptionStore.GetByChannelID(ctx, "github", "channel_1"); err == nil {
+		t.Fatalf("expected the stopped channel to be gone")
+	}
 
 	if err := svc.CancelSubscription(ctx, core.CancelSubscriptionRequest{
 		SubscriptionID: renewed.ID,
@@ -38,6 +38,7 @@ func (s *SubscriptionStore) Upsert(ctx context.Context, in core.UpsertSubscripti
 	if s == nil || s.db == nil || s.repo == nil {
 		return core.Subscription{}, fmt.Errorf("sqlstore: subscription store is not configured")
 	}
+	in.ID = strings.TrimSpace(in.ID)
 	in.ConnectionID = strings.TrimSpace(in.ConnectionID)
 	in.ProviderID = strings.TrimSpace(in.ProviderID)
 	in.ResourceType = strings.TrimSpace(in.ResourceType)
@@ -65,10 +66,24 @@ func (s *SubscriptionStore) Upsert(ctx context.Context, in core.UpsertSubscripti
 
 	var out core.Subscription
 	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		existing, err := s.findByProviderChannelTx(ctx, tx, in.ProviderID, in.ChannelID)
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		var (
+			existing *subscriptionRecord
+			err      error
+		)
+		if in.ID != "" {
+			existing, err = s.findByIDTx(ctx, tx, in.ID)
+		} else {
+			existing, err = s.findByProviderChannelTx(ctx, tx, in.ProviderID, in.ChannelID)
+		}
 		if err != nil {
 			return err
 		}
+		if existing == nil && in.ID != "" {
+			return fmt.Errorf("sqlstore: subscription %q not found", in.ID)
+		}
 		if existing == nil {
 			record := newSubscriptionRecord(in, now)
 			record.ID = uuid.NewString()
@@ -175,6 +190,27 @@ func (s *SubscriptionStore) ListExpiring(ctx context.Context, before time.Time)
 	return out, nil
 }
 
+func (s *SubscriptionStore) ListByConnection(ctx context.Context, connectionID string) ([]core.Subscription, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: subscription store is not configured")
+	}
+	records, _, err := s.repo.List(ctx,
+		repository.SelectBy("connection_id", "=", strings.TrimSpace(connectionID)),
+		repository.SelectRawProcessor(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("?TableAlias.deleted_at IS NULL")
+		}),
+		repository.OrderBy("created_at ASC"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.Subscription, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
 func (s *SubscriptionStore) UpdateState(
 	ctx context.Context,
 	id string,
@@ -203,6 +239,23 @@ func (s *SubscriptionStore) UpdateState(
 	return err
 }
 
+func (s *SubscriptionStore) findByIDTx(ctx context.Context, tx bun.Tx, id string) (*subscriptionRecord, error) {
+	record := &subscriptionRecord{}
+	err := tx.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", id).
+		Where("?TableAlias.deleted_at IS NULL").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
 func (s *SubscriptionStore) findByProviderChannelTx(
 	ctx context.Context,
 	tx bun.Tx,
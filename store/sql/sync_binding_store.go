@@ -0,0 +1,227 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const syncBindingStatusReasonKey = "status_reason"
+
+type SyncBindingStore struct {
+	db   *bun.DB
+	repo repository.Repository[*syncBindingRecord]
+}
+
+func NewSyncBindingStore(db *bun.DB) (*SyncBindingStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*syncBindingRecord](db, syncBindingHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid sync binding repository wiring: %w", err)
+		}
+	}
+	return &SyncBindingStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+// Upsert creates the binding or updates the one already registered for the
+// same connection, mapping spec, and direction.
+func (s *SyncBindingStore) Upsert(ctx context.Context, binding core.SyncBinding) (core.SyncBinding, error) {
+	if s == nil || s.db == nil {
+		return core.SyncBinding{}, fmt.Errorf("sqlstore: sync binding store is not configured")
+	}
+	binding.ID = strings.TrimSpace(binding.ID)
+	binding.ProviderID = strings.TrimSpace(binding.ProviderID)
+	binding.Scope = core.ScopeRef{Type: normalizeScopeType(binding.Scope.Type), ID: strings.TrimSpace(binding.Scope.ID)}
+	binding.ConnectionID = strings.TrimSpace(binding.ConnectionID)
+	binding.MappingSpecID = strings.TrimSpace(binding.MappingSpecID)
+	binding.SourceObject = strings.TrimSpace(binding.SourceObject)
+	binding.TargetModel = strings.TrimSpace(binding.TargetModel)
+	if strings.TrimSpace(string(binding.Status)) == "" {
+		binding.Status = core.SyncBindingStatusActive
+	}
+	if err := binding.Validate(); err != nil {
+		return core.SyncBinding{}, err
+	}
+	now := time.Now().UTC()
+
+	var out core.SyncBinding
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record, err := findSyncBindingTx(ctx, tx, binding.ConnectionID, binding.MappingSpecID, binding.Direction)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			record = &syncBindingRecord{
+				ID:            binding.ID,
+				ProviderID:    binding.ProviderID,
+				ScopeType:     binding.Scope.Type,
+				ScopeID:       binding.Scope.ID,
+				ConnectionID:  binding.ConnectionID,
+				MappingSpecID: binding.MappingSpecID,
+				SourceObject:  binding.SourceObject,
+				TargetModel:   binding.TargetModel,
+				Direction:     string(binding.Direction),
+				Status:        string(binding.Status),
+				Metadata:      copyAnyMap(binding.Metadata),
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			if record.ID == "" {
+				record.ID = uuid.NewString()
+			}
+			if _, insertErr := tx.NewInsert().Model(record).Exec(ctx); insertErr != nil {
+				return insertErr
+			}
+			out = record.toDomain()
+			return nil
+		}
+
+		record.SourceObject = binding.SourceObject
+		record.TargetModel = binding.TargetModel
+		record.Status = string(binding.Status)
+		record.Metadata = copyAnyMap(binding.Metadata)
+		record.UpdatedAt = now
+		if _, updateErr := tx.NewUpdate().Model(record).WherePK().Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.SyncBinding{}, err
+	}
+	return out, nil
+}
+
+func (s *SyncBindingStore) Get(ctx context.Context, id string) (core.SyncBinding, error) {
+	if s == nil || s.repo == nil {
+		return core.SyncBinding{}, fmt.Errorf("sqlstore: sync binding store is not configured")
+	}
+	record, err := s.repo.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return core.SyncBinding{}, err
+	}
+	return record.toDomain(), nil
+}
+
+func (s *SyncBindingStore) ListByConnection(ctx context.Context, connectionID string) ([]core.SyncBinding, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: sync binding store is not configured")
+	}
+	connectionID = strings.TrimSpace(connectionID)
+	if connectionID == "" {
+		return nil, fmt.Errorf("sqlstore: connection id is required")
+	}
+	records, _, err := s.repo.List(ctx,
+		repository.SelectBy("connection_id", "=", connectionID),
+		repository.OrderBy("created_at ASC"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.SyncBinding, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
+// UpdateStatus changes the binding status and records the reason in the
+// binding metadata under "status_reason".
+func (s *SyncBindingStore) UpdateStatus(
+	ctx context.Context,
+	id string,
+	status core.SyncBindingStatus,
+	reason string,
+) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: sync binding store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("sqlstore: sync binding id is required")
+	}
+	if !status.IsValid() {
+		return fmt.Errorf("sqlstore: invalid sync binding status %q", status)
+	}
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record := &syncBindingRecord{}
+		if err := tx.NewSelect().Model(record).Where("?TableAlias.id = ?", id).Limit(1).Scan(ctx); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("sqlstore: sync binding %q not found", id)
+			}
+			return err
+		}
+		record.Metadata = copyAnyMap(record.Metadata)
+		if reason = strings.TrimSpace(reason); reason != "" {
+			record.Metadata[syncBindingStatusReasonKey] = reason
+		} else {
+			delete(record.Metadata, syncBindingStatusReasonKey)
+		}
+		record.Status = string(status)
+		record.UpdatedAt = time.Now().UTC()
+		_, err := tx.NewUpdate().
+			Model(record).
+			Column("status", "metadata", "updated_at").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+}
+
+func findSyncBindingTx(
+	ctx context.Context,
+	db bun.IDB,
+	connectionID string,
+	mappingSpecID string,
+	direction core.SyncDirection,
+) (*syncBindingRecord, error) {
+	record := &syncBindingRecord{}
+	err := db.NewSelect().
+		Model(record).
+		Where("?TableAlias.connection_id = ?", connectionID).
+		Where("?TableAlias.mapping_spec_id = ?", mappingSpecID).
+		Where("?TableAlias.direction = ?", string(direction)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *syncBindingRecord) toDomain() core.SyncBinding {
+	if r == nil {
+		return core.SyncBinding{}
+	}
+	return core.SyncBinding{
+		ID:            r.ID,
+		ProviderID:    r.ProviderID,
+		Scope:         core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:  r.ConnectionID,
+		MappingSpecID: r.MappingSpecID,
+		SourceObject:  r.SourceObject,
+		TargetModel:   r.TargetModel,
+		Direction:     core.SyncDirection(r.Direction),
+		Status:        core.SyncBindingStatus(r.Status),
+		Metadata:      copyAnyMap(r.Metadata),
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
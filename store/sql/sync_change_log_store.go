@@ -0,0 +1,253 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const (
+	defaultSyncChangeLogPageSize = 100
+	maxSyncChangeLogPageSize     = 1000
+)
+
+type SyncChangeLogStore struct {
+	db   *bun.DB
+	repo repository.Repository[*syncChangeLogRecord]
+}
+
+func NewSyncChangeLogStore(db *bun.DB) (*SyncChangeLogStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*syncChangeLogRecord](db, syncChangeLogHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid sync change log repository wiring: %w", err)
+		}
+	}
+	return &SyncChangeLogStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+// Append writes the entry and reports whether it was created. Entries whose
+// idempotency key was already logged for the binding are skipped.
+func (s *SyncChangeLogStore) Append(ctx context.Context, entry core.SyncChangeLogEntry) (bool, error) {
+	if s == nil || s.db == nil {
+		return false, fmt.Errorf("sqlstore: sync change log store is not configured")
+	}
+	entry.ProviderID = strings.TrimSpace(entry.ProviderID)
+	entry.Scope = core.ScopeRef{Type: normalizeScopeType(entry.Scope.Type), ID: strings.TrimSpace(entry.Scope.ID)}
+	entry.ConnectionID = strings.TrimSpace(entry.ConnectionID)
+	entry.SyncBindingID = strings.TrimSpace(entry.SyncBindingID)
+	entry.SourceObject = strings.TrimSpace(entry.SourceObject)
+	entry.ExternalID = strings.TrimSpace(entry.ExternalID)
+	if entry.ProviderID == "" {
+		return false, fmt.Errorf("sqlstore: provider id is required")
+	}
+	if err := entry.Scope.Validate(); err != nil {
+		return false, err
+	}
+	if entry.ConnectionID == "" || entry.SyncBindingID == "" {
+		return false, fmt.Errorf("sqlstore: connection id and sync binding id are required")
+	}
+	if !entry.Direction.IsValid() {
+		return false, fmt.Errorf("sqlstore: invalid sync direction %q", entry.Direction)
+	}
+	if entry.SourceObject == "" || entry.ExternalID == "" {
+		return false, fmt.Errorf("sqlstore: source object and external id are required")
+	}
+	if entry.Operation == "" {
+		entry.Operation = core.SyncChangeOperationUpdate
+	}
+	if !entry.Operation.IsValid() {
+		return false, fmt.Errorf("sqlstore: invalid sync change operation %q", entry.Operation)
+	}
+	now := time.Now().UTC()
+	occurredAt := entry.OccurredAt.UTC()
+	if entry.OccurredAt.IsZero() {
+		occurredAt = now
+	}
+	entryID := strings.TrimSpace(entry.ID)
+	if entryID == "" {
+		entryID = uuid.NewString()
+	}
+
+	record := &syncChangeLogRecord{
+		ID:             entryID,
+		ProviderID:     entry.ProviderID,
+		ScopeType:      entry.Scope.Type,
+		ScopeID:        entry.Scope.ID,
+		ConnectionID:   entry.ConnectionID,
+		SyncBindingID:  entry.SyncBindingID,
+		Direction:      string(entry.Direction),
+		Operation:      string(entry.Operation),
+		SourceObject:   entry.SourceObject,
+		ExternalID:     entry.ExternalID,
+		SourceVersion:  strings.TrimSpace(entry.SourceVersion),
+		IdempotencyKey: strings.TrimSpace(entry.IdempotencyKey),
+		Payload:        copyAnyMap(entry.Payload),
+		Metadata:       copyAnyMap(entry.Metadata),
+		OccurredAt:     occurredAt,
+		CreatedAt:      now,
+	}
+	query := s.db.NewInsert().Model(record)
+	if record.IdempotencyKey != "" {
+		query = query.On("CONFLICT (sync_binding_id, idempotency_key) WHERE idempotency_key <> '' DO NOTHING")
+	}
+	result, err := query.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// ListSince returns entries for the binding and direction logged after the
+// cursor, in the order they were logged. The returned cursor points at the
+// last entry of the page, or echoes the input cursor when nothing new was
+// logged, so callers can persist it and resume tailing the log later. Paging
+// follows created_at rather than occurred_at, which is the provider's event
+// time and may lie behind entries a consumer has already read.
+func (s *SyncChangeLogStore) ListSince(
+	ctx context.Context,
+	syncBindingID string,
+	direction core.SyncDirection,
+	cursor string,
+	limit int,
+) ([]core.SyncChangeLogEntry, string, error) {
+	if s == nil || s.repo == nil {
+		return nil, "", fmt.Errorf("sqlstore: sync change log store is not configured")
+	}
+	syncBindingID = strings.TrimSpace(syncBindingID)
+	if syncBindingID == "" {
+		return nil, "", fmt.Errorf("sqlstore: sync binding id is required")
+	}
+	if !direction.IsValid() {
+		return nil, "", fmt.Errorf("sqlstore: invalid sync direction %q", direction)
+	}
+	if limit <= 0 {
+		limit = defaultSyncChangeLogPageSize
+	}
+	if limit > maxSyncChangeLogPageSize {
+		limit = maxSyncChangeLogPageSize
+	}
+	cursor = strings.TrimSpace(cursor)
+
+	selectors := []repository.SelectCriteria{
+		repository.SelectBy("sync_binding_id", "=", syncBindingID),
+		repository.SelectBy("direction", "=", string(direction)),
+		repository.OrderBy("created_at ASC"),
+		repository.OrderBy("id ASC"),
+		repository.SelectPaginate(limit, 0),
+	}
+	if cursor != "" {
+		createdAt, entryID, err := decodeSyncChangeLogCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		selectors = append(selectors, repository.SelectRawProcessor(func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Where("?TableAlias.created_at > ?", createdAt).
+					WhereOr("?TableAlias.created_at = ? AND ?TableAlias.id > ?", createdAt, entryID)
+			})
+		}))
+	}
+	records, _, err := s.repo.List(ctx, selectors...)
+	if err != nil {
+		return nil, "", err
+	}
+	out := make([]core.SyncChangeLogEntry, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	if len(records) == 0 {
+		return out, cursor, nil
+	}
+	last := records[len(records)-1]
+	return out, encodeSyncChangeLogCursor(last.CreatedAt, last.ID), nil
+}
+
+// GetLatest returns the most recent entry logged for the record in the
+// binding and direction.
+func (s *SyncChangeLogStore) GetLatest(
+	ctx context.Context,
+	syncBindingID string,
+	direction core.SyncDirection,
+	externalID string,
+) (core.SyncChangeLogEntry, bool, error) {
+	if s == nil || s.db == nil {
+		return core.SyncChangeLogEntry{}, false, fmt.Errorf("sqlstore: sync change log store is not configured")
+	}
+	record := &syncChangeLogRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.sync_binding_id = ?", strings.TrimSpace(syncBindingID)).
+		Where("?TableAlias.direction = ?", string(direction)).
+		Where("?TableAlias.external_id = ?", strings.TrimSpace(externalID)).
+		OrderExpr("?TableAlias.occurred_at DESC").
+		OrderExpr("?TableAlias.created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.SyncChangeLogEntry{}, false, nil
+		}
+		return core.SyncChangeLogEntry{}, false, err
+	}
+	return record.toDomain(), true, nil
+}
+
+func encodeSyncChangeLogCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncChangeLogCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("sqlstore: invalid sync change log cursor: %w", err)
+	}
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || strings.TrimSpace(id) == "" {
+		return time.Time{}, "", fmt.Errorf("sqlstore: invalid sync change log cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("sqlstore: invalid sync change log cursor: %w", err)
+	}
+	return createdAt.UTC(), id, nil
+}
+
+func (r *syncChangeLogRecord) toDomain() core.SyncChangeLogEntry {
+	if r == nil {
+		return core.SyncChangeLogEntry{}
+	}
+	return core.SyncChangeLogEntry{
+		ID:             r.ID,
+		ProviderID:     r.ProviderID,
+		Scope:          core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:   r.ConnectionID,
+		SyncBindingID:  r.SyncBindingID,
+		Direction:      core.SyncDirection(r.Direction),
+		Operation:      core.SyncChangeOperation(r.Operation),
+		SourceObject:   r.SourceObject,
+		ExternalID:     r.ExternalID,
+		SourceVersion:  r.SourceVersion,
+		IdempotencyKey: r.IdempotencyKey,
+		Payload:        copyAnyMap(r.Payload),
+		Metadata:       copyAnyMap(r.Metadata),
+		OccurredAt:     r.OccurredAt,
+	}
+}
@@ -0,0 +1,222 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type SyncCheckpointStore struct {
+	db   *bun.DB
+	repo repository.Repository[*syncCheckpointRecord]
+}
+
+func NewSyncCheckpointStore(db *bun.DB) (*SyncCheckpointStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*syncCheckpointRecord](db, syncCheckpointHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid sync checkpoint repository wiring: %w", err)
+		}
+	}
+	return &SyncCheckpointStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+// Save stores the checkpoint keyed by binding, direction, and sequence. Saving
+// the same sequence again updates the stored cursor in place.
+func (s *SyncCheckpointStore) Save(ctx context.Context, checkpoint core.SyncCheckpoint) (core.SyncCheckpoint, error) {
+	if s == nil || s.db == nil {
+		return core.SyncCheckpoint{}, fmt.Errorf("sqlstore: sync checkpoint store is not configured")
+	}
+	checkpoint.ID = strings.TrimSpace(checkpoint.ID)
+	checkpoint.ProviderID = strings.TrimSpace(checkpoint.ProviderID)
+	checkpoint.Scope = core.ScopeRef{
+		Type: normalizeScopeType(checkpoint.Scope.Type),
+		ID:   strings.TrimSpace(checkpoint.Scope.ID),
+	}
+	checkpoint.ConnectionID = strings.TrimSpace(checkpoint.ConnectionID)
+	checkpoint.SyncBindingID = strings.TrimSpace(checkpoint.SyncBindingID)
+	if err := checkpoint.Validate(); err != nil {
+		return core.SyncCheckpoint{}, err
+	}
+	now := time.Now().UTC()
+
+	var out core.SyncCheckpoint
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record := &syncCheckpointRecord{}
+		err := tx.NewSelect().
+			Model(record).
+			Where("?TableAlias.sync_binding_id = ?", checkpoint.SyncBindingID).
+			Where("?TableAlias.direction = ?", string(checkpoint.Direction)).
+			Where("?TableAlias.sequence_num = ?", checkpoint.Sequence).
+			Limit(1).
+			Scan(ctx)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == sql.ErrNoRows {
+			record = newSyncCheckpointRecord(checkpoint, now)
+			if record.ID != "" {
+				// Runs advance from a stored checkpoint, so a carried-over id
+				// belongs to an earlier sequence and must not be reused.
+				taken, existsErr := tx.NewSelect().
+					Model((*syncCheckpointRecord)(nil)).
+					Where("?TableAlias.id = ?", record.ID).
+					Exists(ctx)
+				if existsErr != nil {
+					return existsErr
+				}
+				if taken {
+					record.ID = ""
+				}
+			}
+			if record.ID == "" {
+				record.ID = uuid.NewString()
+			}
+			if _, insertErr := tx.NewInsert().Model(record).Exec(ctx); insertErr != nil {
+				return insertErr
+			}
+			out = record.toDomain()
+			return nil
+		}
+
+		updated := newSyncCheckpointRecord(checkpoint, now)
+		updated.ID = record.ID
+		updated.CreatedAt = record.CreatedAt
+		if _, updateErr := tx.NewUpdate().Model(updated).WherePK().Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = updated.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.SyncCheckpoint{}, err
+	}
+	return out, nil
+}
+
+func (s *SyncCheckpointStore) GetByID(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	id string,
+) (core.SyncCheckpoint, bool, error) {
+	if s == nil || s.db == nil {
+		return core.SyncCheckpoint{}, false, fmt.Errorf("sqlstore: sync checkpoint store is not configured")
+	}
+	record := &syncCheckpointRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", strings.TrimSpace(id)).
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.scope_type = ?", normalizeScopeType(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.SyncCheckpoint{}, false, nil
+		}
+		return core.SyncCheckpoint{}, false, err
+	}
+	return record.toDomain(), true, nil
+}
+
+// GetLatest returns the checkpoint with the highest sequence for the binding
+// and direction.
+func (s *SyncCheckpointStore) GetLatest(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	syncBindingID string,
+	direction core.SyncDirection,
+) (core.SyncCheckpoint, bool, error) {
+	if s == nil || s.db == nil {
+		return core.SyncCheckpoint{}, false, fmt.Errorf("sqlstore: sync checkpoint store is not configured")
+	}
+	record := &syncCheckpointRecord{}
+	err := s.db.NewSelect().
+		Model(record).
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.scope_type = ?", normalizeScopeType(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		Where("?TableAlias.sync_binding_id = ?", strings.TrimSpace(syncBindingID)).
+		Where("?TableAlias.direction = ?", string(direction)).
+		OrderExpr("?TableAlias.sequence_num DESC").
+		OrderExpr("?TableAlias.updated_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.SyncCheckpoint{}, false, nil
+		}
+		return core.SyncCheckpoint{}, false, err
+	}
+	return record.toDomain(), true, nil
+}
+
+func newSyncCheckpointRecord(checkpoint core.SyncCheckpoint, now time.Time) *syncCheckpointRecord {
+	createdAt := checkpoint.CreatedAt.UTC()
+	if checkpoint.CreatedAt.IsZero() {
+		createdAt = now
+	}
+	record := &syncCheckpointRecord{
+		ID:              checkpoint.ID,
+		ProviderID:      checkpoint.ProviderID,
+		ScopeType:       checkpoint.Scope.Type,
+		ScopeID:         checkpoint.Scope.ID,
+		ConnectionID:    checkpoint.ConnectionID,
+		SyncBindingID:   checkpoint.SyncBindingID,
+		Direction:       string(checkpoint.Direction),
+		Cursor:          strings.TrimSpace(checkpoint.Cursor),
+		Sequence:        checkpoint.Sequence,
+		SourceVersion:   strings.TrimSpace(checkpoint.SourceVersion),
+		IdempotencySeed: strings.TrimSpace(checkpoint.IdempotencySeed),
+		Metadata:        copyAnyMap(checkpoint.Metadata),
+		CreatedAt:       createdAt,
+		UpdatedAt:       now,
+	}
+	if checkpoint.LastEventAt != nil {
+		value := checkpoint.LastEventAt.UTC()
+		record.LastEventAt = &value
+	}
+	return record
+}
+
+func (r *syncCheckpointRecord) toDomain() core.SyncCheckpoint {
+	if r == nil {
+		return core.SyncCheckpoint{}
+	}
+	checkpoint := core.SyncCheckpoint{
+		ID:              r.ID,
+		ProviderID:      r.ProviderID,
+		Scope:           core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:    r.ConnectionID,
+		SyncBindingID:   r.SyncBindingID,
+		Direction:       core.SyncDirection(r.Direction),
+		Cursor:          r.Cursor,
+		Sequence:        r.Sequence,
+		SourceVersion:   r.SourceVersion,
+		IdempotencySeed: r.IdempotencySeed,
+		Metadata:        copyAnyMap(r.Metadata),
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+	if r.LastEventAt != nil {
+		value := *r.LastEventAt
+		checkpoint.LastEventAt = &value
+	}
+	return checkpoint
+}
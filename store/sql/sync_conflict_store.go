@@ -0,0 +1,328 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	repository "github.com/goliatone/go-repository-bun"
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type SyncConflictStore struct {
+	db   *bun.DB
+	repo repository.Repository[*syncConflictRecord]
+}
+
+func NewSyncConflictStore(db *bun.DB) (*SyncConflictStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	repo := repository.NewRepository[*syncConflictRecord](db, syncConflictHandlers())
+	if validator, ok := repo.(repository.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid sync conflict repository wiring: %w", err)
+		}
+	}
+	return &SyncConflictStore{
+		db:   db,
+		repo: repo,
+	}, nil
+}
+
+// Append records the conflict. Conflicts carrying an idempotency key already
+// recorded for the binding return the stored conflict instead.
+func (s *SyncConflictStore) Append(ctx context.Context, conflict core.SyncConflict) (core.SyncConflict, error) {
+	if s == nil || s.db == nil {
+		return core.SyncConflict{}, fmt.Errorf("sqlstore: sync conflict store is not configured")
+	}
+	conflict.ID = strings.TrimSpace(conflict.ID)
+	conflict.ProviderID = strings.TrimSpace(conflict.ProviderID)
+	conflict.Scope = core.ScopeRef{Type: normalizeScopeType(conflict.Scope.Type), ID: strings.TrimSpace(conflict.Scope.ID)}
+	conflict.ConnectionID = strings.TrimSpace(conflict.ConnectionID)
+	conflict.SyncBindingID = strings.TrimSpace(conflict.SyncBindingID)
+	conflict.CheckpointID = strings.TrimSpace(conflict.CheckpointID)
+	conflict.IdempotencyKey = strings.TrimSpace(conflict.IdempotencyKey)
+	if strings.TrimSpace(string(conflict.Status)) == "" {
+		conflict.Status = core.SyncConflictStatusPending
+	}
+	if err := conflict.Validate(); err != nil {
+		return core.SyncConflict{}, err
+	}
+	now := time.Now().UTC()
+
+	var out core.SyncConflict
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if conflict.IdempotencyKey != "" {
+			existing, err := findSyncConflictByIdempotencyTx(ctx, tx, conflict.SyncBindingID, conflict.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				out = existing.toDomain()
+				return nil
+			}
+		}
+		record := newSyncConflictRecord(conflict, now)
+		if record.ID == "" {
+			record.ID = uuid.NewString()
+		}
+		if _, insertErr := tx.NewInsert().Model(record).Exec(ctx); insertErr != nil {
+			if conflict.IdempotencyKey == "" || !isUniqueViolation(insertErr) {
+				return insertErr
+			}
+			existing, err := findSyncConflictByIdempotencyTx(ctx, tx, conflict.SyncBindingID, conflict.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return insertErr
+			}
+			record = existing
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.SyncConflict{}, err
+	}
+	return out, nil
+}
+
+func (s *SyncConflictStore) Get(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	id string,
+) (core.SyncConflict, error) {
+	if s == nil || s.db == nil {
+		return core.SyncConflict{}, fmt.Errorf("sqlstore: sync conflict store is not configured")
+	}
+	record, err := findSyncConflictTx(ctx, s.db, providerID, scope, id)
+	if err != nil {
+		return core.SyncConflict{}, err
+	}
+	return record.toDomain(), nil
+}
+
+// ListByBinding returns the binding's conflicts, newest first. An empty
+// status lists conflicts in every status.
+func (s *SyncConflictStore) ListByBinding(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	syncBindingID string,
+	status core.SyncConflictStatus,
+) ([]core.SyncConflict, error) {
+	if s == nil || s.repo == nil {
+		return nil, fmt.Errorf("sqlstore: sync conflict store is not configured")
+	}
+	syncBindingID = strings.TrimSpace(syncBindingID)
+	if syncBindingID == "" {
+		return nil, fmt.Errorf("sqlstore: sync binding id is required")
+	}
+	selectors := []repository.SelectCriteria{
+		repository.SelectBy("provider_id", "=", strings.TrimSpace(providerID)),
+		repository.SelectBy("scope_type", "=", normalizeScopeType(scope.Type)),
+		repository.SelectBy("scope_id", "=", strings.TrimSpace(scope.ID)),
+		repository.SelectBy("sync_binding_id", "=", syncBindingID),
+		repository.OrderBy("created_at DESC"),
+	}
+	if status = core.SyncConflictStatus(strings.TrimSpace(string(status))); status != "" {
+		selectors = append(selectors, repository.SelectBy("status", "=", string(status)))
+	}
+	records, _, err := s.repo.List(ctx, selectors...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]core.SyncConflict, 0, len(records))
+	for _, record := range records {
+		out = append(out, record.toDomain())
+	}
+	return out, nil
+}
+
+// Resolve applies the resolution action to the conflict status and merges the
+// resolution patch and reason into the stored resolution document.
+func (s *SyncConflictStore) Resolve(
+	ctx context.Context,
+	providerID string,
+	scope core.ScopeRef,
+	id string,
+	resolution core.SyncConflictResolution,
+	resolvedAt time.Time,
+) (core.SyncConflict, error) {
+	if s == nil || s.db == nil {
+		return core.SyncConflict{}, fmt.Errorf("sqlstore: sync conflict store is not configured")
+	}
+	var status core.SyncConflictStatus
+	switch resolution.Action {
+	case core.SyncConflictResolutionResolve:
+		status = core.SyncConflictStatusResolved
+	case core.SyncConflictResolutionIgnore:
+		status = core.SyncConflictStatusIgnored
+	case core.SyncConflictResolutionRetry:
+		status = core.SyncConflictStatusPending
+	default:
+		return core.SyncConflict{}, fmt.Errorf("sqlstore: invalid sync conflict resolution action %q", resolution.Action)
+	}
+	if resolvedAt.IsZero() {
+		resolvedAt = time.Now()
+	}
+	resolvedAt = resolvedAt.UTC()
+
+	var out core.SyncConflict
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		record, err := findSyncConflictTx(ctx, tx, providerID, scope, id)
+		if err != nil {
+			return err
+		}
+		record.Status = string(status)
+		record.ResolvedBy = strings.TrimSpace(resolution.ResolvedBy)
+		record.ResolvedAt = &resolvedAt
+		record.Resolution = copyAnyMap(record.Resolution)
+		maps.Copy(record.Resolution, resolution.Patch)
+		if reason := strings.TrimSpace(resolution.Reason); reason != "" {
+			record.Resolution["reason"] = reason
+		}
+		record.UpdatedAt = resolvedAt
+		if _, updateErr := tx.NewUpdate().
+			Model(record).
+			Column("status", "resolution", "resolved_by", "resolved_at", "updated_at").
+			WherePK().
+			Exec(ctx); updateErr != nil {
+			return updateErr
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.SyncConflict{}, err
+	}
+	return out, nil
+}
+
+func findSyncConflictTx(
+	ctx context.Context,
+	db bun.IDB,
+	providerID string,
+	scope core.ScopeRef,
+	id string,
+) (*syncConflictRecord, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("sqlstore: sync conflict id is required")
+	}
+	record := &syncConflictRecord{}
+	err := db.NewSelect().
+		Model(record).
+		Where("?TableAlias.id = ?", id).
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.scope_type = ?", normalizeScopeType(scope.Type)).
+		Where("?TableAlias.scope_id = ?", strings.TrimSpace(scope.ID)).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sqlstore: sync conflict %q not found", id)
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func findSyncConflictByIdempotencyTx(
+	ctx context.Context,
+	db bun.IDB,
+	syncBindingID string,
+	idempotencyKey string,
+) (*syncConflictRecord, error) {
+	record := &syncConflictRecord{}
+	err := db.NewSelect().
+		Model(record).
+		Where("?TableAlias.sync_binding_id = ?", syncBindingID).
+		Where("?TableAlias.idempotency_key = ?", idempotencyKey).
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func newSyncConflictRecord(conflict core.SyncConflict, now time.Time) *syncConflictRecord {
+	record := &syncConflictRecord{
+		ID:             conflict.ID,
+		ProviderID:     conflict.ProviderID,
+		ScopeType:      conflict.Scope.Type,
+		ScopeID:        conflict.Scope.ID,
+		ConnectionID:   conflict.ConnectionID,
+		SyncBindingID:  conflict.SyncBindingID,
+		SourceObject:   strings.TrimSpace(conflict.SourceObject),
+		ExternalID:     strings.TrimSpace(conflict.ExternalID),
+		SourceVersion:  strings.TrimSpace(conflict.SourceVersion),
+		IdempotencyKey: conflict.IdempotencyKey,
+		Policy:         strings.TrimSpace(conflict.Policy),
+		Reason:         strings.TrimSpace(conflict.Reason),
+		Status:         string(conflict.Status),
+		SourcePayload:  copyAnyMap(conflict.SourcePayload),
+		TargetPayload:  copyAnyMap(conflict.TargetPayload),
+		Resolution:     copyAnyMap(conflict.Resolution),
+		ResolvedBy:     strings.TrimSpace(conflict.ResolvedBy),
+		Metadata:       copyAnyMap(conflict.Metadata),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if conflict.CheckpointID != "" {
+		checkpointID := conflict.CheckpointID
+		record.CheckpointID = &checkpointID
+	}
+	if conflict.ResolvedAt != nil {
+		value := conflict.ResolvedAt.UTC()
+		record.ResolvedAt = &value
+	}
+	return record
+}
+
+func (r *syncConflictRecord) toDomain() core.SyncConflict {
+	if r == nil {
+		return core.SyncConflict{}
+	}
+	conflict := core.SyncConflict{
+		ID:             r.ID,
+		ProviderID:     r.ProviderID,
+		Scope:          core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:   r.ConnectionID,
+		SyncBindingID:  r.SyncBindingID,
+		SourceObject:   r.SourceObject,
+		ExternalID:     r.ExternalID,
+		SourceVersion:  r.SourceVersion,
+		IdempotencyKey: r.IdempotencyKey,
+		Policy:         r.Policy,
+		Reason:         r.Reason,
+		Status:         core.SyncConflictStatus(r.Status),
+		SourcePayload:  copyAnyMap(r.SourcePayload),
+		TargetPayload:  copyAnyMap(r.TargetPayload),
+		Resolution:     copyAnyMap(r.Resolution),
+		ResolvedBy:     r.ResolvedBy,
+		Metadata:       copyAnyMap(r.Metadata),
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+	if r.CheckpointID != nil {
+		conflict.CheckpointID = *r.CheckpointID
+	}
+	if r.ResolvedAt != nil {
+		value := *r.ResolvedAt
+		conflict.ResolvedAt = &value
+	}
+	return conflict
+}
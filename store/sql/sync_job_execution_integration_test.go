@@ -0,0 +1,101 @@
+package sqlstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestSyncJobStore_ConditionalUpdatesProgressAndListing(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	repoFactory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	connection, err := repoFactory.ConnectionStore().Create(ctx, core.CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             core.ScopeRef{Type: "org", ID: "org_sync_job_exec"},
+		ExternalAccountID: "acct_sync_job_exec",
+		Status:            core.ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	store, err := sqlstore.NewSyncJobStore(client.DB())
+	if err != nil {
+		t.Fatalf("new sync job store: %v", err)
+	}
+	create := func() core.SyncJob {
+		t.Helper()
+		result, err := store.CreateSyncJob(ctx, core.CreateSyncJobStoreInput{
+			ProviderID:   "github",
+			Scope:        core.ScopeRef{Type: "org", ID: "org_sync_job_exec"},
+			ConnectionID: connection.ID,
+			Mode:         core.SyncJobModeFull,
+		})
+		if err != nil {
+			t.Fatalf("create sync job: %v", err)
+		}
+		return result.Job
+	}
+	running := create()
+	queued := create()
+
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	lease := now.Add(-time.Minute)
+	running.Status = core.SyncJobStatusRunning
+	running.LeaseExpiresAt = &lease
+	running.Progress = core.SyncJobProgress{ItemsProcessed: 10, ItemsTotal: 40, Percent: 25, StartedAt: &now}
+	saved, ok, err := store.UpdateSyncJobIf(ctx, running, core.SyncJobStatusQueued)
+	if err != nil || !ok {
+		t.Fatalf("expected claim to apply, got %v %v", ok, err)
+	}
+	if _, ok, err := store.UpdateSyncJobIf(ctx, running, core.SyncJobStatusQueued); err != nil || ok {
+		t.Fatalf("expected second claim to be rejected, got %v %v", ok, err)
+	}
+
+	loaded, err := store.GetSyncJob(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("get sync job: %v", err)
+	}
+	if loaded.Status != core.SyncJobStatusRunning || loaded.LeaseExpiresAt == nil || !loaded.LeaseExpiresAt.Equal(lease) {
+		t.Fatalf("unexpected claimed job %+v", loaded)
+	}
+	if loaded.Progress.ItemsTotal != 40 || loaded.Progress.Percent != 25 || loaded.Progress.StartedAt == nil {
+		t.Fatalf("expected progress to round-trip, got %+v", loaded.Progress)
+	}
+
+	expired, err := store.ListSyncJobs(ctx, core.SyncJobFilter{
+		Statuses:           []core.SyncJobStatus{core.SyncJobStatusRunning},
+		LeaseExpiredBefore: &now,
+	})
+	if err != nil {
+		t.Fatalf("list expired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != running.ID {
+		t.Fatalf("expected the expired running job, got %+v", expired)
+	}
+	all, err := store.ListSyncJobs(ctx, core.SyncJobFilter{ConnectionID: connection.ID, Limit: 1})
+	if err != nil {
+		t.Fatalf("list by connection: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("expected limit to apply, got %d jobs", len(all))
+	}
+	queuedOnly, err := store.ListSyncJobs(ctx, core.SyncJobFilter{
+		ProviderID: "github",
+		Statuses:   []core.SyncJobStatus{core.SyncJobStatusQueued},
+	})
+	if err != nil {
+		t.Fatalf("list queued: %v", err)
+	}
+	if len(queuedOnly) != 1 || queuedOnly[0].ID != queued.ID {
+		t.Fatalf("expected only the queued job, got %+v", queuedOnly)
+	}
+}
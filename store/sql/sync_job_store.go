@@ -185,6 +185,83 @@ func (s *SyncJobStore) Update(ctx context.Context, job core.SyncJob) (core.SyncJ
 	return record.toDomain(), nil
 }
 
+// UpdateSyncJobIf saves the job only while its stored status is expected. When
+// another writer changed the status first it returns the stored job and false.
+func (s *SyncJobStore) UpdateSyncJobIf(
+	ctx context.Context,
+	job core.SyncJob,
+	expected core.SyncJobStatus,
+) (core.SyncJob, bool, error) {
+	if s == nil || s.db == nil {
+		return core.SyncJob{}, false, fmt.Errorf("sqlstore: sync job store is not configured")
+	}
+	job.ID = strings.TrimSpace(job.ID)
+	if job.ID == "" {
+		return core.SyncJob{}, false, fmt.Errorf("sqlstore: job id is required")
+	}
+	job.UpdatedAt = time.Now().UTC()
+	record := newSyncJobRecord(job, job.UpdatedAt)
+	record.ID = job.ID
+	record.CreatedAt = job.CreatedAt
+
+	result, err := s.db.NewUpdate().
+		Model(record).
+		Where("id = ?", record.ID).
+		Where("status = ?", string(expected)).
+		Exec(ctx)
+	if err != nil {
+		return core.SyncJob{}, false, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		current, getErr := s.GetSyncJob(ctx, job.ID)
+		if getErr != nil {
+			return core.SyncJob{}, false, getErr
+		}
+		return current, false, nil
+	}
+	return record.toDomain(), true, nil
+}
+
+func (s *SyncJobStore) ListSyncJobs(ctx context.Context, filter core.SyncJobFilter) ([]core.SyncJob, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: sync job store is not configured")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	records := []syncJobRecord{}
+	query := s.db.NewSelect().Model(&records)
+	if providerID := strings.TrimSpace(filter.ProviderID); providerID != "" {
+		query = query.Where("?TableAlias.provider_id = ?", providerID)
+	}
+	if connectionID := strings.TrimSpace(filter.ConnectionID); connectionID != "" {
+		query = query.Where("?TableAlias.connection_id = ?", connectionID)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		query = query.Where("?TableAlias.status IN (?)", bun.In(statuses))
+	}
+	if filter.LeaseExpiredBefore != nil {
+		query = query.Where("?TableAlias.lease_expires_at <= ?", filter.LeaseExpiredBefore.UTC())
+	}
+	if err := query.
+		OrderExpr("?TableAlias.created_at DESC").
+		OrderExpr("?TableAlias.id DESC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	jobs := make([]core.SyncJob, 0, len(records))
+	for i := range records {
+		jobs = append(jobs, records[i].toDomain())
+	}
+	return jobs, nil
+}
+
 func (s *SyncJobStore) createSyncJobWithIdempotency(
 	ctx context.Context,
 	in core.CreateSyncJobStoreInput,
@@ -0,0 +1,217 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+const defaultSyncScheduleListLimit = 50
+
+type SyncScheduleStore struct {
+	db *bun.DB
+}
+
+func NewSyncScheduleStore(db *bun.DB) (*SyncScheduleStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sqlstore: bun db is required")
+	}
+	return &SyncScheduleStore{db: db}, nil
+}
+
+// UpsertSyncSchedule inserts the schedule, or replaces the stored schedule
+// with the same id while keeping its creation time. Interval and jitter are
+// stored in whole seconds.
+func (s *SyncScheduleStore) UpsertSyncSchedule(ctx context.Context, schedule core.SyncSchedule) (core.SyncSchedule, error) {
+	if s == nil || s.db == nil {
+		return core.SyncSchedule{}, fmt.Errorf("sqlstore: sync schedule store is not configured")
+	}
+	schedule.ID = strings.TrimSpace(schedule.ID)
+	schedule.ProviderID = strings.TrimSpace(schedule.ProviderID)
+	schedule.Scope = core.ScopeRef{Type: normalizeScopeType(schedule.Scope.Type), ID: strings.TrimSpace(schedule.Scope.ID)}
+	schedule.ConnectionID = strings.TrimSpace(schedule.ConnectionID)
+	schedule.SyncBindingID = strings.TrimSpace(schedule.SyncBindingID)
+	if strings.TrimSpace(string(schedule.Status)) == "" {
+		schedule.Status = core.SyncScheduleStatusActive
+	}
+	if err := schedule.Validate(); err != nil {
+		return core.SyncSchedule{}, err
+	}
+	now := time.Now().UTC()
+	record := newSyncScheduleRecord(schedule, now)
+
+	var out core.SyncSchedule
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := fenceLeaderLease(ctx, tx); err != nil {
+			return err
+		}
+		if record.ID != "" {
+			existing := &syncScheduleRecord{}
+			err := tx.NewSelect().Model(existing).Where("?TableAlias.id = ?", record.ID).Limit(1).Scan(ctx)
+			switch {
+			case err == nil:
+				record.CreatedAt = existing.CreatedAt
+				if _, updateErr := tx.NewUpdate().Model(record).WherePK().Exec(ctx); updateErr != nil {
+					return updateErr
+				}
+				out = record.toDomain()
+				return nil
+			case !errors.Is(err, sql.ErrNoRows):
+				return err
+			}
+		} else {
+			record.ID = uuid.NewString()
+		}
+		if _, insertErr := tx.NewInsert().Model(record).Exec(ctx); insertErr != nil {
+			return insertErr
+		}
+		out = record.toDomain()
+		return nil
+	})
+	if err != nil {
+		return core.SyncSchedule{}, err
+	}
+	return out, nil
+}
+
+func (s *SyncScheduleStore) GetSyncSchedule(ctx context.Context, id string) (core.SyncSchedule, error) {
+	if s == nil || s.db == nil {
+		return core.SyncSchedule{}, fmt.Errorf("sqlstore: sync schedule store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	record := &syncScheduleRecord{}
+	if err := s.db.NewSelect().Model(record).Where("?TableAlias.id = ?", id).Limit(1).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.SyncSchedule{}, fmt.Errorf("%w: id %q", core.ErrSyncScheduleNotFound, id)
+		}
+		return core.SyncSchedule{}, err
+	}
+	return record.toDomain(), nil
+}
+
+// ListSyncSchedules orders due queries by next run so the most overdue
+// schedules are materialized first, and other queries by creation time. Ties
+// are broken by id, which AfterID pages on.
+func (s *SyncScheduleStore) ListSyncSchedules(ctx context.Context, filter core.SyncScheduleFilter) ([]core.SyncSchedule, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: sync schedule store is not configured")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultSyncScheduleListLimit
+	}
+	records := []syncScheduleRecord{}
+	query := s.db.NewSelect().Model(&records)
+	if providerID := strings.TrimSpace(filter.ProviderID); providerID != "" {
+		query = query.Where("?TableAlias.provider_id = ?", providerID)
+	}
+	if connectionID := strings.TrimSpace(filter.ConnectionID); connectionID != "" {
+		query = query.Where("?TableAlias.connection_id = ?", connectionID)
+	}
+	if bindingID := strings.TrimSpace(filter.SyncBindingID); bindingID != "" {
+		query = query.Where("?TableAlias.sync_binding_id = ?", bindingID)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		query = query.Where("?TableAlias.status IN (?)", bun.In(statuses))
+	}
+	order := bun.Ident("created_at")
+	if filter.DueBefore != nil {
+		order = bun.Ident("next_run_at")
+		query = query.Where("?TableAlias.next_run_at <= ?", filter.DueBefore.UTC())
+	}
+	if afterID := strings.TrimSpace(filter.AfterID); afterID != "" {
+		after := s.db.NewSelect().
+			TableExpr("service_sync_schedules AS after_schedule").
+			ColumnExpr("after_schedule.?", order).
+			Where("after_schedule.id = ?", afterID)
+		query = query.Where(
+			"(?TableAlias.? > (?) OR (?TableAlias.? = (?) AND ?TableAlias.id > ?))",
+			order, after, order, after, afterID,
+		)
+	}
+	query = query.OrderExpr("?TableAlias.? ASC", order)
+	if err := query.OrderExpr("?TableAlias.id ASC").Limit(limit).Scan(ctx); err != nil {
+		return nil, err
+	}
+	schedules := make([]core.SyncSchedule, 0, len(records))
+	for i := range records {
+		schedules = append(schedules, records[i].toDomain())
+	}
+	return schedules, nil
+}
+
+func (s *SyncScheduleStore) DeleteSyncSchedule(ctx context.Context, id string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: sync schedule store is not configured")
+	}
+	id = strings.TrimSpace(id)
+	result, err := s.db.NewDelete().Model((*syncScheduleRecord)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("%w: id %q", core.ErrSyncScheduleNotFound, id)
+	}
+	return nil
+}
+
+func newSyncScheduleRecord(schedule core.SyncSchedule, now time.Time) *syncScheduleRecord {
+	return &syncScheduleRecord{
+		ID:              schedule.ID,
+		ProviderID:      schedule.ProviderID,
+		ScopeType:       schedule.Scope.Type,
+		ScopeID:         schedule.Scope.ID,
+		ConnectionID:    schedule.ConnectionID,
+		SyncBindingID:   schedule.SyncBindingID,
+		Mode:            string(schedule.Mode),
+		CronExpression:  strings.TrimSpace(schedule.CronExpression),
+		IntervalSeconds: int64(schedule.Interval / time.Second),
+		JitterSeconds:   int64(schedule.Jitter / time.Second),
+		Timezone:        strings.TrimSpace(schedule.Timezone),
+		Status:          string(schedule.Status),
+		NextWindowAt:    cloneTimePointer(schedule.NextWindowAt),
+		NextRunAt:       cloneTimePointer(schedule.NextRunAt),
+		LastWindowAt:    cloneTimePointer(schedule.LastWindowAt),
+		LastSyncJobID:   strings.TrimSpace(schedule.LastSyncJobID),
+		Metadata:        copyAnyMap(schedule.Metadata),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func (r *syncScheduleRecord) toDomain() core.SyncSchedule {
+	if r == nil {
+		return core.SyncSchedule{}
+	}
+	return core.SyncSchedule{
+		ID:             r.ID,
+		ProviderID:     r.ProviderID,
+		Scope:          core.ScopeRef{Type: r.ScopeType, ID: r.ScopeID},
+		ConnectionID:   r.ConnectionID,
+		SyncBindingID:  r.SyncBindingID,
+		Mode:           core.SyncJobMode(r.Mode),
+		CronExpression: r.CronExpression,
+		Interval:       time.Duration(r.IntervalSeconds) * time.Second,
+		Jitter:         time.Duration(r.JitterSeconds) * time.Second,
+		Timezone:       r.Timezone,
+		Status:         core.SyncScheduleStatus(r.Status),
+		NextWindowAt:   cloneTimePointer(r.NextWindowAt),
+		NextRunAt:      cloneTimePointer(r.NextRunAt),
+		LastWindowAt:   cloneTimePointer(r.LastWindowAt),
+		LastSyncJobID:  r.LastSyncJobID,
+		Metadata:       copyAnyMap(r.Metadata),
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+package sqlstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	sqlstore "github.com/goliatone/go-services/store/sql"
+)
+
+func TestSyncScheduleStore_UpsertListAndDelete(t *testing.T) {
+	ctx := context.Background()
+	client, cleanup := newSQLiteClient(t)
+	defer cleanup()
+
+	repoFactory, err := sqlstore.NewRepositoryFactoryFromPersistence(client)
+	if err != nil {
+		t.Fatalf("new repository factory: %v", err)
+	}
+	connection, err := repoFactory.ConnectionStore().Create(ctx, core.CreateConnectionInput{
+		ProviderID:        "github",
+		Scope:             core.ScopeRef{Type: "org", ID: "org_sync_schedule"},
+		ExternalAccountID: "acct_sync_schedule",
+		Status:            core.ConnectionStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	store := repoFactory.SyncScheduleStore()
+	if store == nil {
+		t.Fatalf("expected sync schedule store to be wired")
+	}
+
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+	newSchedule := func(runAt time.Time) core.SyncSchedule {
+		return core.SyncSchedule{
+			ProviderID:    "github",
+			Scope:         core.ScopeRef{Type: "org", ID: "org_sync_schedule"},
+			ConnectionID:  connection.ID,
+			SyncBindingID: "binding_1",
+			Mode:          core.SyncJobModeDelta,
+			Interval:      time.Hour,
+			Jitter:        90 * time.Second,
+			Status:        core.SyncScheduleStatusActive,
+			NextWindowAt:  &runAt,
+			NextRunAt:     &runAt,
+			Metadata:      map[string]any{"owner": "ops"},
+		}
+	}
+	dueSchedule, err := store.UpsertSyncSchedule(ctx, newSchedule(due))
+	if err != nil {
+		t.Fatalf("upsert due schedule: %v", err)
+	}
+	laterSchedule, err := store.UpsertSyncSchedule(ctx, newSchedule(later))
+	if err != nil {
+		t.Fatalf("upsert later schedule: %v", err)
+	}
+	if dueSchedule.ID == "" || dueSchedule.ID == laterSchedule.ID {
+		t.Fatalf("expected generated ids, got %q and %q", dueSchedule.ID, laterSchedule.ID)
+	}
+
+	loaded, err := store.GetSyncSchedule(ctx, dueSchedule.ID)
+	if err != nil {
+		t.Fatalf("get schedule: %v", err)
+	}
+	if loaded.Interval != time.Hour || loaded.Jitter != 90*time.Second || loaded.Metadata["owner"] != "ops" ||
+		loaded.NextRunAt == nil || !loaded.NextRunAt.Equal(due) {
+		t.Fatalf("expected schedule to round-trip, got %+v", loaded)
+	}
+
+	listed, err := store.ListSyncSchedules(ctx, core.SyncScheduleFilter{
+		Statuses:  []core.SyncScheduleStatus{core.SyncScheduleStatusActive},
+		DueBefore: &now,
+	})
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != dueSchedule.ID {
+		t.Fatalf("expected only the due schedule, got %+v", listed)
+	}
+
+	loaded.Status = core.SyncScheduleStatusSuspended
+	loaded.LastSyncJobID = "job_1"
+	updated, err := store.UpsertSyncSchedule(ctx, loaded)
+	if err != nil {
+		t.Fatalf("update schedule: %v", err)
+	}
+	if updated.Status != core.SyncScheduleStatusSuspended || updated.LastSyncJobID != "job_1" || !updated.CreatedAt.Equal(loaded.CreatedAt) {
+		t.Fatalf("unexpected updated schedule %+v", updated)
+	}
+	byBinding, err := store.ListSyncSchedules(ctx, core.SyncScheduleFilter{SyncBindingID: "binding_1"})
+	if err != nil || len(byBinding) != 2 {
+		t.Fatalf("expected both schedules by binding, got %+v %v", byBinding, err)
+	}
+	firstPage, err := store.ListSyncSchedules(ctx, core.SyncScheduleFilter{SyncBindingID: "binding_1", Limit: 1})
+	if err != nil || len(firstPage) != 1 {
+		t.Fatalf("expected a one schedule page, got %+v %v", firstPage, err)
+	}
+	secondPage, err := store.ListSyncSchedules(ctx, core.SyncScheduleFilter{
+		SyncBindingID: "binding_1",
+		AfterID:       firstPage[0].ID,
+		Limit:         1,
+	})
+	if err != nil || len(secondPage) != 1 || secondPage[0].ID != byBinding[1].ID {
+		t.Fatalf("expected the page after %q to hold the other schedule, got %+v %v", firstPage[0].ID, secondPage, err)
+	}
+	if lastPage, err := store.ListSyncSchedules(ctx, core.SyncScheduleFilter{
+		SyncBindingID: "binding_1",
+		AfterID:       secondPage[0].ID,
+	}); err != nil || len(lastPage) != 0 {
+		t.Fatalf("expected nothing after the last schedule, got %+v %v", lastPage, err)
+	}
+
+	if err := store.DeleteSyncSchedule(ctx, dueSchedule.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.GetSyncSchedule(ctx, dueSchedule.ID); !errors.Is(err, core.ErrSyncScheduleNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if err := store.DeleteSyncSchedule(ctx, dueSchedule.ID); !errors.Is(err, core.ErrSyncScheduleNotFound) {
+		t.Fatalf("expected second delete to report not found, got %v", err)
+	}
+}
@@ -216,6 +216,48 @@ func (s *WebhookDeliveryStore) Fail(
 	return err
 }
 
+// HandledEvents returns the indexes of the delivery's events that a router
+// already handled.
+func (s *WebhookDeliveryStore) HandledEvents(ctx context.Context, providerID string, deliveryID string) ([]int, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("sqlstore: webhook delivery store is not configured")
+	}
+	var indexes []int
+	err := s.db.NewSelect().
+		Model((*webhookEventOutcomeRecord)(nil)).
+		Column("event_index").
+		Where("?TableAlias.provider_id = ?", strings.TrimSpace(providerID)).
+		Where("?TableAlias.delivery_id = ?", strings.TrimSpace(deliveryID)).
+		OrderExpr("?TableAlias.event_index ASC").
+		Scan(ctx, &indexes)
+	if err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+func (s *WebhookDeliveryStore) MarkEventHandled(ctx context.Context, providerID string, deliveryID string, index int) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("sqlstore: webhook delivery store is not configured")
+	}
+	providerID = strings.TrimSpace(providerID)
+	deliveryID = strings.TrimSpace(deliveryID)
+	if providerID == "" || deliveryID == "" {
+		return fmt.Errorf("sqlstore: provider id and delivery id are required")
+	}
+	_, err := s.db.NewInsert().
+		Model(&webhookEventOutcomeRecord{
+			ID:         uuid.NewString(),
+			ProviderID: providerID,
+			DeliveryID: deliveryID,
+			EventIndex: index,
+			CreatedAt:  time.Now().UTC(),
+		}).
+		On("CONFLICT (provider_id, delivery_id, event_index) DO NOTHING").
+		Exec(ctx)
+	return err
+}
+
 func webhookDeliveryToDomain(record *webhookDeliveryRecord) webhooks.DeliveryRecord {
 	if record == nil {
 		return webhooks.DeliveryRecord{}
@@ -271,4 +313,7 @@ func isUniqueViolation(err error) bool {
 		strings.Contains(message, "duplicate key value violates unique constraint")
 }
 
-var _ webhooks.DeliveryLedger = (*WebhookDeliveryStore)(nil)
+var (
+	_ webhooks.DeliveryLedger    = (*WebhookDeliveryStore)(nil)
+	_ webhooks.EventOutcomeStore = (*WebhookDeliveryStore)(nil)
+)
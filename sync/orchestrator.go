@@ -100,9 +100,9 @@ func (o *Orchestrator) Resume(ctx context.Context, jobID string) error {
 	}
 
 	switch job.Status {
-	case core.SyncJobStatusFailed:
+	case core.SyncJobStatusFailed, core.SyncJobStatusPaused:
 		job.Status = core.SyncJobStatusQueued
-	case core.SyncJobStatusSucceeded:
+	case core.SyncJobStatusSucceeded, core.SyncJobStatusCanceled:
 		return nil
 	}
 	job.Attempts++
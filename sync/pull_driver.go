@@ -0,0 +1,435 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	defaultPullPageSize = 100
+	maxPullPageSize     = 1000
+)
+
+// ChangeDecoder converts one provider change item into a sync change.
+type ChangeDecoder func(binding core.SyncBinding, item map[string]any) (core.SyncChange, error)
+
+// CredentialFreshener resolves a usable credential for a connection; it is
+// satisfied by *core.Service.
+type CredentialFreshener interface {
+	EnsureCredentialFresh(
+		ctx context.Context,
+		req core.EnsureCredentialFreshRequest,
+	) (core.EnsureCredentialFreshResult, error)
+}
+
+type PullRequest struct {
+	BindingID string
+	// JobID optionally names a sync job that receives progress updates.
+	JobID    string
+	PageSize int
+	// MaxPages bounds a single call; zero pulls until the provider is caught up.
+	MaxPages int
+	Metadata map[string]any
+}
+
+type PullResult struct {
+	BindingID      string
+	JobID          string
+	Pages          int
+	ProcessedCount int
+	SkippedCount   int
+	ConflictCount  int
+	FailedCount    int
+	CaughtUp       bool
+	// Halted is the binding status that stopped the pull, if any.
+	Halted     core.SyncBindingStatus
+	Checkpoint *core.SyncCheckpoint
+}
+
+// PullDriver pulls changes for import bindings from incremental sync
+// providers and runs them through the planner and runner page by page. The
+// provider cursor is stored on the run checkpoint after every page, so a
+// crashed pull resumes from the last completed page and replayed changes are
+// skipped by the change log. A page with failed records keeps its cursor and
+// ends the pull, so the next pull fetches the page again.
+type PullDriver struct {
+	Bindings    core.SyncBindingStore
+	Providers   core.Registry
+	Planner     core.SyncPlanner
+	Runner      core.SyncRunner
+	Checkpoints core.SyncCheckpointStore
+	Jobs        SyncJobStore
+	// Credentials, when set, supplies the connection credential for every
+	// page request.
+	Credentials CredentialFreshener
+	Decode      ChangeDecoder
+	Now         func() time.Time
+}
+
+func NewPullDriver(
+	bindings core.SyncBindingStore,
+	providers core.Registry,
+	planner core.SyncPlanner,
+	runner core.SyncRunner,
+	checkpoints core.SyncCheckpointStore,
+	jobs SyncJobStore,
+) *PullDriver {
+	return &PullDriver{
+		Bindings:    bindings,
+		Providers:   providers,
+		Planner:     planner,
+		Runner:      runner,
+		Checkpoints: checkpoints,
+		Jobs:        jobs,
+		Decode:      DecodeChange,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+// Pull drives the binding until the provider reports no more changes, a page
+// has failed records, the page budget is spent, or the binding is paused or
+// disabled. None of these is an error: a pull with failed records leaves the
+// job failed, and a halted or budget-limited pull leaves it paused. Either
+// way the job resumes from the stored checkpoint.
+func (d *PullDriver) Pull(ctx context.Context, req PullRequest) (PullResult, error) {
+	if d == nil || d.Bindings == nil || d.Providers == nil || d.Planner == nil || d.Runner == nil || d.Checkpoints == nil {
+		return PullResult{}, fmt.Errorf("sync: pull driver dependencies are required")
+	}
+	bindingID := strings.TrimSpace(req.BindingID)
+	if bindingID == "" {
+		return PullResult{}, fmt.Errorf("sync: binding id is required")
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPullPageSize
+	}
+	if pageSize > maxPullPageSize {
+		pageSize = maxPullPageSize
+	}
+	result := PullResult{
+		BindingID: bindingID,
+		JobID:     strings.TrimSpace(req.JobID),
+	}
+
+	binding, err := d.Bindings.Get(ctx, bindingID)
+	if err != nil {
+		return result, err
+	}
+	if !binding.Direction.Allows(core.SyncDirectionImport) {
+		return result, fmt.Errorf("sync: binding %q does not import", bindingID)
+	}
+	provider, ok := d.Providers.Get(binding.ProviderID)
+	if !ok || provider == nil {
+		return result, fmt.Errorf("sync: provider %q is not registered", binding.ProviderID)
+	}
+	incremental, ok := provider.(core.IncrementalSyncProvider)
+	if !ok {
+		return result, fmt.Errorf("sync: provider %q does not support incremental sync", binding.ProviderID)
+	}
+
+	job, err := d.startJob(ctx, result.JobID, binding)
+	if err != nil {
+		return result, err
+	}
+
+	for req.MaxPages <= 0 || result.Pages < req.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return result, d.failJob(ctx, job, &result, err)
+		}
+		if binding.Status != core.SyncBindingStatusActive {
+			result.Halted = binding.Status
+			break
+		}
+
+		caughtUp, pageErr := d.pullPage(ctx, incremental, binding, pageSize, req.Metadata, &result)
+		if pageErr != nil {
+			return result, d.failJob(ctx, job, &result, pageErr)
+		}
+		if job, err = d.updateJob(ctx, job, &result, core.SyncJobStatusRunning); err != nil {
+			return result, err
+		}
+		if result.FailedCount > 0 {
+			break
+		}
+		if caughtUp {
+			result.CaughtUp = true
+			break
+		}
+
+		// Reload between pages so pausing a binding stops a long pull.
+		if binding, err = d.Bindings.Get(ctx, bindingID); err != nil {
+			return result, d.failJob(ctx, job, &result, err)
+		}
+	}
+	if result.FailedCount > 0 {
+		cause := fmt.Errorf("sync: %d records failed for binding %q", result.FailedCount, bindingID)
+		if err := d.recordJobFailure(ctx, job, &result, cause); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+	status := core.SyncJobStatusSucceeded
+	if !result.CaughtUp {
+		status = core.SyncJobStatusPaused
+	}
+	if _, err := d.updateJob(ctx, job, &result, status); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (d *PullDriver) pullPage(
+	ctx context.Context,
+	provider core.IncrementalSyncProvider,
+	binding core.SyncBinding,
+	pageSize int,
+	metadata map[string]any,
+	result *PullResult,
+) (bool, error) {
+	plan, err := d.Planner.PlanSyncRun(ctx, core.PlanSyncRunRequest{
+		Binding:   binding,
+		Direction: core.SyncDirectionImport,
+		Mode:      core.SyncRunModeApply,
+		Limit:     pageSize,
+		Metadata:  metadata,
+	})
+	if err != nil {
+		return false, err
+	}
+	listReq := core.ListChangesRequest{
+		ConnectionID: binding.ConnectionID,
+		ResourceType: binding.SourceObject,
+		ResourceID:   firstString(binding.Metadata, "resource_id"),
+		Cursor:       plan.Checkpoint.Cursor,
+		Limit:        pageSize,
+		Metadata:     binding.Metadata,
+	}
+	if d.Credentials != nil {
+		fresh, credErr := d.Credentials.EnsureCredentialFresh(ctx, core.EnsureCredentialFreshRequest{
+			ProviderID:   binding.ProviderID,
+			ConnectionID: binding.ConnectionID,
+		})
+		if credErr != nil {
+			return false, fmt.Errorf("sync: resolve credential for binding %q: %w", binding.ID, credErr)
+		}
+		listReq.Credential = &fresh.Credential
+	}
+	page, err := provider.ListChanges(ctx, listReq)
+	if err != nil {
+		return false, fmt.Errorf("sync: list changes for binding %q: %w", binding.ID, err)
+	}
+	nextCursor := strings.TrimSpace(page.NextCursor)
+	if page.HasMore && (nextCursor == "" || nextCursor == plan.Checkpoint.Cursor) {
+		return false, fmt.Errorf("sync: provider %q reported more changes without advancing the cursor", binding.ProviderID)
+	}
+
+	decode := d.Decode
+	if decode == nil {
+		decode = DecodeChange
+	}
+	changes := make([]core.SyncChange, 0, len(page.Items))
+	for _, item := range page.Items {
+		change, decodeErr := decode(binding, item)
+		if decodeErr != nil {
+			return false, decodeErr
+		}
+		changes = append(changes, change)
+	}
+
+	run, err := d.Runner.RunSyncImport(ctx, core.RunSyncImportRequest{
+		Plan:     plan,
+		Changes:  changes,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return false, err
+	}
+	result.Pages++
+	result.ProcessedCount += run.ProcessedCount
+	result.SkippedCount += run.SkippedCount
+	result.ConflictCount += run.ConflictCount
+	result.FailedCount += run.FailedCount
+
+	checkpoint := plan.Checkpoint
+	if run.NextCheckpoint != nil {
+		checkpoint = *run.NextCheckpoint
+	}
+	// Failed records are only fetched again if the cursor stays on their page.
+	if nextCursor != "" && run.FailedCount == 0 {
+		checkpoint.Cursor = nextCursor
+	}
+	checkpoint.UpdatedAt = d.now()
+	saved, err := d.Checkpoints.Save(ctx, checkpoint)
+	if err != nil {
+		return false, err
+	}
+	result.Checkpoint = &saved
+	return !page.HasMore && run.FailedCount == 0, nil
+}
+
+func (d *PullDriver) startJob(ctx context.Context, jobID string, binding core.SyncBinding) (*core.SyncJob, error) {
+	if jobID == "" {
+		return nil, nil
+	}
+	if d.Jobs == nil {
+		return nil, fmt.Errorf("sync: pull driver requires sync job store to track job %q", jobID)
+	}
+	job, err := d.Jobs.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.TransitionTo(core.SyncJobStatusRunning, d.now()); err != nil {
+		return nil, err
+	}
+	job.Metadata = mergeAnyMap(job.Metadata, map[string]any{
+		"sync_binding_id": binding.ID,
+	})
+	updated, err := d.Jobs.Update(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (d *PullDriver) updateJob(
+	ctx context.Context,
+	job *core.SyncJob,
+	result *PullResult,
+	status core.SyncJobStatus,
+) (*core.SyncJob, error) {
+	if job == nil {
+		return nil, nil
+	}
+	next := *job
+	if err := next.TransitionTo(status, d.now()); err != nil {
+		return job, err
+	}
+	if result.Checkpoint != nil {
+		next.Checkpoint = result.Checkpoint.Cursor
+	}
+	next.Metadata = mergeAnyMap(next.Metadata, pullProgressMetadata(result))
+	updated, err := d.Jobs.Update(ctx, next)
+	if err != nil {
+		return job, err
+	}
+	return &updated, nil
+}
+
+func (d *PullDriver) failJob(ctx context.Context, job *core.SyncJob, result *PullResult, cause error) error {
+	if err := d.recordJobFailure(ctx, job, result, cause); err != nil {
+		return fmt.Errorf("%w (update sync job: %v)", cause, err)
+	}
+	return cause
+}
+
+func (d *PullDriver) recordJobFailure(ctx context.Context, job *core.SyncJob, result *PullResult, cause error) error {
+	if job == nil {
+		return nil
+	}
+	next := *job
+	next.Status = core.SyncJobStatusFailed
+	next.Attempts++
+	next.UpdatedAt = d.now()
+	next.Metadata = mergeAnyMap(next.Metadata, pullProgressMetadata(result))
+	next.Metadata["last_error"] = strings.TrimSpace(cause.Error())
+	_, err := d.Jobs.Update(ctx, next)
+	return err
+}
+
+func pullProgressMetadata(result *PullResult) map[string]any {
+	metadata := map[string]any{
+		"pages":     result.Pages,
+		"processed": result.ProcessedCount,
+		"skipped":   result.SkippedCount,
+		"conflicts": result.ConflictCount,
+		"failed":    result.FailedCount,
+		"caught_up": result.CaughtUp,
+	}
+	if result.Halted != "" {
+		metadata["halted"] = string(result.Halted)
+	}
+	if result.Checkpoint != nil {
+		metadata["checkpoint_id"] = result.Checkpoint.ID
+		metadata["sequence"] = result.Checkpoint.Sequence
+	}
+	return metadata
+}
+
+func (d *PullDriver) now() time.Time {
+	if d != nil && d.Now != nil {
+		return d.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// DecodeChange is the default ChangeDecoder. It reads the external id from
+// "external_id" or "id", the version from "source_version", "version",
+// "etag", or "updated_at", and uses a nested "payload" map when present. The
+// operation comes from "operation", a true "deleted" flag, or the suffix of a
+// webhook "topic" such as products/delete.
+func DecodeChange(binding core.SyncBinding, item map[string]any) (core.SyncChange, error) {
+	change := core.SyncChange{
+		Operation:     decodeChangeOperation(item),
+		SourceObject:  firstString(item, "source_object"),
+		ExternalID:    firstString(item, "external_id", "id"),
+		SourceVersion: firstString(item, "source_version", "version", "etag", "updated_at"),
+		Payload:       item,
+	}
+	if change.SourceObject == "" {
+		change.SourceObject = binding.SourceObject
+	}
+	if payload, ok := item["payload"].(map[string]any); ok {
+		change.Payload = payload
+	}
+	if metadata, ok := item["metadata"].(map[string]any); ok {
+		change.Metadata = metadata
+	}
+	if change.ExternalID == "" {
+		return core.SyncChange{}, fmt.Errorf("sync: change item for binding %q has no external id", binding.ID)
+	}
+	if !change.Operation.IsValid() {
+		return core.SyncChange{}, fmt.Errorf(
+			"sync: change item %q for binding %q has unknown operation %q",
+			change.ExternalID,
+			binding.ID,
+			change.Operation,
+		)
+	}
+	return change, nil
+}
+
+func decodeChangeOperation(item map[string]any) core.SyncChangeOperation {
+	if operation := firstString(item, "operation"); operation != "" {
+		return core.SyncChangeOperation(strings.ToLower(operation))
+	}
+	if deleted, _ := item["deleted"].(bool); deleted {
+		return core.SyncChangeOperationDelete
+	}
+	if topic := firstString(item, "topic"); topic != "" {
+		_, action, _ := strings.Cut(topic, "/")
+		switch operation := core.SyncChangeOperation(strings.ToLower(action)); operation {
+		case core.SyncChangeOperationCreate, core.SyncChangeOperationDelete:
+			return operation
+		}
+	}
+	return core.SyncChangeOperationUpdate
+}
+
+func firstString(item map[string]any, keys ...string) string {
+	for _, key := range keys {
+		value, ok := item[key]
+		if !ok || value == nil {
+			continue
+		}
+		if text := strings.TrimSpace(fmt.Sprint(value)); text != "" {
+			return text
+		}
+	}
+	return ""
+}
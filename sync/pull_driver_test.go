@@ -0,0 +1,471 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestPullDriver_PagesUntilCaughtUpAndTracksJob(t *testing.T) {
+	fixture := newPullFixture(t)
+	provider := fixture.provider
+	provider.pages = map[string]core.ListChangesResult{
+		"": {
+			Items:      []map[string]any{{"id": "a", "version": "1"}, {"id": "b", "version": "1"}},
+			NextCursor: "c1",
+			HasMore:    true,
+		},
+		"c1": {
+			Items:      []map[string]any{{"id": "c", "version": "2", "payload": map[string]any{"name": "C"}}},
+			NextCursor: "c2",
+			HasMore:    true,
+		},
+		"c2": {NextCursor: "c3"},
+	}
+	job := fixture.createJob(t)
+	fixture.driver.Credentials = staticCredentialFreshener{token: "access-token"}
+
+	result, err := fixture.driver.Pull(context.Background(), PullRequest{
+		BindingID: "binding_1",
+		JobID:     job.ID,
+		PageSize:  2,
+	})
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if !result.CaughtUp || result.Pages != 3 || result.ProcessedCount != 3 {
+		t.Fatalf("unexpected pull result %+v", result)
+	}
+	if fmt.Sprint(provider.cursors) != "[ c1 c2]" || provider.limits[0] != 2 {
+		t.Fatalf("unexpected provider calls cursors=%q limits=%v", provider.cursors, provider.limits)
+	}
+	if provider.tokens[0] != "access-token" {
+		t.Fatalf("expected resolved credential on provider requests")
+	}
+	if provider.resourceIDs[0] != "folder_1" || provider.resourceTypes[0] != "drive.file" {
+		t.Fatalf("expected binding resource to be passed to provider")
+	}
+	latest := fixture.checkpoints.latest()
+	if latest.Cursor != "c3" || latest.Sequence != 3 {
+		t.Fatalf("expected final cursor to be persisted, got %+v", latest)
+	}
+
+	stored, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if stored.Status != core.SyncJobStatusSucceeded || stored.Checkpoint != "c3" {
+		t.Fatalf("expected succeeded job at final cursor, got %+v", stored)
+	}
+	if stored.Metadata["pages"] != 3 || stored.Metadata["processed"] != 3 || stored.Metadata["caught_up"] != true {
+		t.Fatalf("unexpected job progress %+v", stored.Metadata)
+	}
+}
+
+func TestPullDriver_ResumesFromLastPageAfterFailure(t *testing.T) {
+	fixture := newPullFixture(t)
+	provider := fixture.provider
+	provider.pages = map[string]core.ListChangesResult{
+		"": {
+			Items:      []map[string]any{{"id": "a", "version": "1"}},
+			NextCursor: "c1",
+			HasMore:    true,
+		},
+		"c1": {
+			Items:      []map[string]any{{"id": "b", "version": "1"}},
+			NextCursor: "c2",
+		},
+	}
+	provider.failOnce = map[string]error{"c1": errors.New("upstream timeout")}
+	job := fixture.createJob(t)
+
+	_, err := fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID})
+	if err == nil {
+		t.Fatalf("expected provider failure")
+	}
+	failed, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if failed.Status != core.SyncJobStatusFailed || failed.Checkpoint != "c1" || failed.Metadata["last_error"] == "" {
+		t.Fatalf("expected failed job at first page cursor, got %+v", failed)
+	}
+
+	result, err := fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID})
+	if err != nil {
+		t.Fatalf("resume pull: %v", err)
+	}
+	if !result.CaughtUp || result.Pages != 1 || result.ProcessedCount != 1 {
+		t.Fatalf("expected resumed pull to run only the remaining page, got %+v", result)
+	}
+	if fmt.Sprint(provider.cursors) != "[ c1 c1]" {
+		t.Fatalf("expected resume from stored cursor, got %q", provider.cursors)
+	}
+	if len(fixture.changeLog.entries) != 2 {
+		t.Fatalf("expected each change logged once, got %d", len(fixture.changeLog.entries))
+	}
+}
+
+func TestPullDriver_HoldsCursorWhenPageHasFailedRecords(t *testing.T) {
+	fixture := newPullFixture(t)
+	provider := fixture.provider
+	provider.pages = map[string]core.ListChangesResult{
+		"": {
+			Items:      []map[string]any{{"id": "a", "version": "1"}, {"id": "b", "version": "1"}},
+			NextCursor: "c1",
+			HasMore:    true,
+		},
+		"c1": {NextCursor: "c2"},
+	}
+	sink := &failingSyncTargetSink{failures: map[string]int{"b": 1}}
+	runner, err := core.NewSyncExecutionService(fixture.checkpoints, fixture.changeLog, core.WithSyncTargetSink(sink))
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	fixture.driver.Runner = runner
+	job := fixture.createJob(t)
+
+	result, err := fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID})
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if result.FailedCount != 1 || result.Pages != 1 || result.CaughtUp {
+		t.Fatalf("expected pull to stop after the failed page, got %+v", result)
+	}
+	if result.Checkpoint == nil || result.Checkpoint.Cursor != "" {
+		t.Fatalf("expected cursor to stay on the failed page, got %+v", result.Checkpoint)
+	}
+	failed, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if failed.Status != core.SyncJobStatusFailed || failed.Metadata["last_error"] == "" {
+		t.Fatalf("expected failed job, got %+v", failed)
+	}
+
+	result, err = fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID})
+	if err != nil {
+		t.Fatalf("resume pull: %v", err)
+	}
+	if !result.CaughtUp || result.FailedCount != 0 || result.ProcessedCount != 1 || result.SkippedCount != 1 {
+		t.Fatalf("expected failed record to be delivered on the next pull, got %+v", result)
+	}
+	if fmt.Sprint(provider.cursors) != "[  c1]" {
+		t.Fatalf("expected failed page to be fetched again, got %q", provider.cursors)
+	}
+	stored, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if stored.Status != core.SyncJobStatusSucceeded || stored.Checkpoint != "c2" {
+		t.Fatalf("expected succeeded job at final cursor, got %+v", stored)
+	}
+}
+
+func TestPullDriver_PausesJobWhenPageBudgetIsSpent(t *testing.T) {
+	fixture := newPullFixture(t)
+	fixture.provider.pages = map[string]core.ListChangesResult{
+		"": {
+			Items:      []map[string]any{{"id": "a", "version": "1"}},
+			NextCursor: "c1",
+			HasMore:    true,
+		},
+	}
+	job := fixture.createJob(t)
+
+	result, err := fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID, MaxPages: 1})
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if result.Pages != 1 || result.CaughtUp {
+		t.Fatalf("expected pull to stop at the page budget, got %+v", result)
+	}
+	stored, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if stored.Status != core.SyncJobStatusPaused || stored.Checkpoint != "c1" {
+		t.Fatalf("expected paused job at first page cursor, got %+v", stored)
+	}
+}
+
+func TestDecodeChange_ReadsOperation(t *testing.T) {
+	binding := core.SyncBinding{ID: "binding_1", SourceObject: "files"}
+	cases := []struct {
+		item map[string]any
+		want core.SyncChangeOperation
+	}{
+		{item: map[string]any{"id": "a"}, want: core.SyncChangeOperationUpdate},
+		{item: map[string]any{"id": "a", "deleted": true}, want: core.SyncChangeOperationDelete},
+		{item: map[string]any{"id": "a", "operation": "Restore"}, want: core.SyncChangeOperationRestore},
+		{item: map[string]any{"id": "a", "topic": "products/delete"}, want: core.SyncChangeOperationDelete},
+		{item: map[string]any{"id": "a", "topic": "customers/create"}, want: core.SyncChangeOperationCreate},
+	}
+	for _, tc := range cases {
+		change, err := DecodeChange(binding, tc.item)
+		if err != nil {
+			t.Fatalf("decode %v: %v", tc.item, err)
+		}
+		if change.Operation != tc.want {
+			t.Fatalf("expected %q for %v, got %q", tc.want, tc.item, change.Operation)
+		}
+	}
+	if _, err := DecodeChange(binding, map[string]any{"id": "a", "operation": "archive"}); err == nil {
+		t.Fatalf("expected unknown operation to be rejected")
+	}
+}
+
+func TestPullDriver_StopsWhenBindingPaused(t *testing.T) {
+	fixture := newPullFixture(t)
+	provider := fixture.provider
+	provider.pages = map[string]core.ListChangesResult{
+		"": {
+			Items:      []map[string]any{{"id": "a", "version": "1"}},
+			NextCursor: "c1",
+			HasMore:    true,
+		},
+	}
+	provider.onList = func(cursor string) {
+		binding := fixture.bindings.bindings["binding_1"]
+		binding.Status = core.SyncBindingStatusPaused
+		fixture.bindings.bindings["binding_1"] = binding
+	}
+	job := fixture.createJob(t)
+
+	result, err := fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1", JobID: job.ID})
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if result.Halted != core.SyncBindingStatusPaused || result.Pages != 1 || result.CaughtUp {
+		t.Fatalf("expected pull to halt after first page, got %+v", result)
+	}
+	stored, _ := fixture.jobs.Get(context.Background(), job.ID)
+	if stored.Status != core.SyncJobStatusPaused || stored.Metadata["halted"] != "paused" || stored.Checkpoint != "c1" {
+		t.Fatalf("expected paused job at first page cursor, got %+v", stored)
+	}
+
+	result, err = fixture.driver.Pull(context.Background(), PullRequest{BindingID: "binding_1"})
+	if err != nil {
+		t.Fatalf("pull paused binding: %v", err)
+	}
+	if result.Halted != core.SyncBindingStatusPaused || result.Pages != 0 || len(provider.cursors) != 1 {
+		t.Fatalf("expected paused binding to skip provider, got %+v", result)
+	}
+}
+
+type pullFixture struct {
+	driver      *PullDriver
+	provider    *pageProvider
+	bindings    *memorySyncBindingStore
+	checkpoints *memorySyncCheckpointStore
+	changeLog   *memorySyncChangeLogStore
+	jobs        *memorySyncJobStore
+}
+
+func newPullFixture(t *testing.T) *pullFixture {
+	t.Helper()
+	fixture := &pullFixture{
+		provider: &pageProvider{id: "google_drive"},
+		bindings: &memorySyncBindingStore{bindings: map[string]core.SyncBinding{
+			"binding_1": {
+				ID:            "binding_1",
+				ProviderID:    "google_drive",
+				Scope:         core.ScopeRef{Type: "org", ID: "org_1"},
+				ConnectionID:  "conn_1",
+				MappingSpecID: "spec_1",
+				SourceObject:  "drive.file",
+				TargetModel:   "documents",
+				Direction:     core.SyncDirectionImport,
+				Status:        core.SyncBindingStatusActive,
+				Metadata:      map[string]any{"resource_id": "folder_1"},
+			},
+		}},
+		checkpoints: &memorySyncCheckpointStore{},
+		changeLog:   &memorySyncChangeLogStore{keys: map[string]struct{}{}},
+		jobs:        newMemorySyncJobStore(),
+	}
+	registry := core.NewProviderRegistry()
+	if err := registry.Register(fixture.provider); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	planner, err := core.NewSyncPlannerService(fixture.checkpoints)
+	if err != nil {
+		t.Fatalf("new planner: %v", err)
+	}
+	runner, err := core.NewSyncExecutionService(fixture.checkpoints, fixture.changeLog)
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	fixture.driver = NewPullDriver(fixture.bindings, registry, planner, runner, fixture.checkpoints, fixture.jobs)
+	return fixture
+}
+
+func (f *pullFixture) createJob(t *testing.T) core.SyncJob {
+	t.Helper()
+	job, err := NewOrchestrator(f.jobs, nil).StartIncremental(
+		context.Background(), "conn_1", "google_drive", "drive.file", "folder_1", nil,
+	)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+	return job
+}
+
+type pageProvider struct {
+	id            string
+	pages         map[string]core.ListChangesResult
+	failOnce      map[string]error
+	onList        func(cursor string)
+	cursors       []string
+	limits        []int
+	resourceTypes []string
+	resourceIDs   []string
+	tokens        []string
+}
+
+func (p *pageProvider) ID() string                                { return p.id }
+func (p *pageProvider) AuthKind() core.AuthKind                   { return "oauth2" }
+func (p *pageProvider) SupportedScopeTypes() []string             { return []string{"org"} }
+func (p *pageProvider) Capabilities() []core.CapabilityDescriptor { return nil }
+func (p *pageProvider) Refresh(context.Context, core.ActiveCredential) (core.RefreshResult, error) {
+	return core.RefreshResult{}, nil
+}
+func (p *pageProvider) BeginAuth(context.Context, core.BeginAuthRequest) (core.BeginAuthResponse, error) {
+	return core.BeginAuthResponse{}, nil
+}
+func (p *pageProvider) CompleteAuth(context.Context, core.CompleteAuthRequest) (core.CompleteAuthResponse, error) {
+	return core.CompleteAuthResponse{}, nil
+}
+
+func (p *pageProvider) ListChanges(_ context.Context, req core.ListChangesRequest) (core.ListChangesResult, error) {
+	p.cursors = append(p.cursors, req.Cursor)
+	p.limits = append(p.limits, req.Limit)
+	p.resourceTypes = append(p.resourceTypes, req.ResourceType)
+	p.resourceIDs = append(p.resourceIDs, req.ResourceID)
+	if req.Credential != nil {
+		p.tokens = append(p.tokens, req.Credential.AccessToken)
+	}
+	if err, ok := p.failOnce[req.Cursor]; ok {
+		delete(p.failOnce, req.Cursor)
+		return core.ListChangesResult{}, err
+	}
+	if p.onList != nil {
+		p.onList(req.Cursor)
+	}
+	return p.pages[req.Cursor], nil
+}
+
+type failingSyncTargetSink struct {
+	failures map[string]int
+}
+
+func (s *failingSyncTargetSink) ApplySyncRecord(_ context.Context, record core.SyncMappedRecord) (core.SyncSinkResult, error) {
+	if s.failures[record.ExternalID] > 0 {
+		s.failures[record.ExternalID]--
+		return core.SyncSinkResult{}, errors.New("target unavailable")
+	}
+	return core.SyncSinkResult{InternalID: record.ExternalID}, nil
+}
+
+type staticCredentialFreshener struct {
+	token string
+}
+
+func (f staticCredentialFreshener) EnsureCredentialFresh(
+	_ context.Context,
+	req core.EnsureCredentialFreshRequest,
+) (core.EnsureCredentialFreshResult, error) {
+	return core.EnsureCredentialFreshResult{Credential: core.ActiveCredential{
+		ConnectionID: req.ConnectionID,
+		AccessToken:  f.token,
+	}}, nil
+}
+
+type memorySyncBindingStore struct {
+	bindings map[string]core.SyncBinding
+}
+
+func (s *memorySyncBindingStore) Upsert(_ context.Context, binding core.SyncBinding) (core.SyncBinding, error) {
+	s.bindings[binding.ID] = binding
+	return binding, nil
+}
+
+func (s *memorySyncBindingStore) Get(_ context.Context, id string) (core.SyncBinding, error) {
+	binding, ok := s.bindings[id]
+	if !ok {
+		return core.SyncBinding{}, errors.New("binding not found")
+	}
+	return binding, nil
+}
+
+func (s *memorySyncBindingStore) ListByConnection(context.Context, string) ([]core.SyncBinding, error) {
+	return nil, nil
+}
+
+func (s *memorySyncBindingStore) UpdateStatus(_ context.Context, id string, status core.SyncBindingStatus, _ string) error {
+	binding := s.bindings[id]
+	binding.Status = status
+	s.bindings[id] = binding
+	return nil
+}
+
+type memorySyncCheckpointStore struct {
+	checkpoints []core.SyncCheckpoint
+}
+
+func (s *memorySyncCheckpointStore) Save(_ context.Context, checkpoint core.SyncCheckpoint) (core.SyncCheckpoint, error) {
+	for i, existing := range s.checkpoints {
+		if existing.Sequence == checkpoint.Sequence && existing.Direction == checkpoint.Direction {
+			checkpoint.ID = existing.ID
+			s.checkpoints[i] = checkpoint
+			return checkpoint, nil
+		}
+	}
+	checkpoint.ID = fmt.Sprintf("cp_%d", len(s.checkpoints)+1)
+	s.checkpoints = append(s.checkpoints, checkpoint)
+	return checkpoint, nil
+}
+
+func (s *memorySyncCheckpointStore) GetByID(_ context.Context, _ string, _ core.ScopeRef, id string) (core.SyncCheckpoint, bool, error) {
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.ID == id {
+			return checkpoint, true, nil
+		}
+	}
+	return core.SyncCheckpoint{}, false, nil
+}
+
+func (s *memorySyncCheckpointStore) GetLatest(
+	context.Context,
+	string,
+	core.ScopeRef,
+	string,
+	core.SyncDirection,
+) (core.SyncCheckpoint, bool, error) {
+	if len(s.checkpoints) == 0 {
+		return core.SyncCheckpoint{}, false, nil
+	}
+	return s.latest(), true, nil
+}
+
+func (s *memorySyncCheckpointStore) latest() core.SyncCheckpoint {
+	var latest core.SyncCheckpoint
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.Sequence >= latest.Sequence {
+			latest = checkpoint
+		}
+	}
+	return latest
+}
+
+type memorySyncChangeLogStore struct {
+	entries []core.SyncChangeLogEntry
+	keys    map[string]struct{}
+}
+
+func (s *memorySyncChangeLogStore) Append(_ context.Context, entry core.SyncChangeLogEntry) (bool, error) {
+	if _, ok := s.keys[entry.IdempotencyKey]; ok {
+		return false, nil
+	}
+	s.keys[entry.IdempotencyKey] = struct{}{}
+	s.entries = append(s.entries, entry)
+	return true, nil
+}
+
+func (s *memorySyncChangeLogStore) ListSince(
+	context.Context,
+	string,
+	core.SyncDirection,
+	string,
+	int,
+) ([]core.SyncChangeLogEntry, string, error) {
+	return s.entries, "", nil
+}
@@ -156,7 +156,7 @@ func DefaultBurstKeyExtractor(req core.InboundRequest) (string, bool) {
 	}
 	if req.Headers != nil {
 		for _, key := range []string{"x-goog-channel-id", "x-channel-id", "x-resource-id"} {
-			value := strings.TrimSpace(headerValue(req.Headers, key))
+			value := strings.TrimSpace(HeaderValue(req.Headers, key))
 			if value != "" {
 				return providerID + ":" + strings.ToLower(value), true
 			}
@@ -4,4 +4,8 @@
 // pending/retry_ready -> processing -> processed|dead.
 // This makes retries and crash-recovery explicit and prevents transient
 // failures from being deduped as permanently processed.
+//
+// Router is a Handler that resolves provider event topics (for example
+// X-Shopify-Topic or Meta entry changes) and dispatches each event to the
+// handler registered for that topic.
 package webhooks
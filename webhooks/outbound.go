@@ -0,0 +1,558 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	OutboundEndpointStatusActive   = "active"
+	OutboundEndpointStatusDisabled = "disabled"
+
+	OutboundDeliveryStatusPending    = "pending"
+	OutboundDeliveryStatusProcessing = "processing"
+	OutboundDeliveryStatusDelivered  = "delivered"
+	OutboundDeliveryStatusRetryReady = "retry_ready"
+	OutboundDeliveryStatusDead       = "dead"
+
+	OutboundHeaderSignature = "X-Webhook-Signature"
+	OutboundHeaderID        = "X-Webhook-Id"
+	OutboundHeaderEvent     = "X-Webhook-Event"
+	OutboundHeaderTimestamp = "X-Webhook-Timestamp"
+	OutboundSignaturePrefix = "sha256="
+
+	// DefaultOutboundSignatureTolerance bounds how far the signed timestamp
+	// may drift from the receiver clock before a delivery is rejected.
+	DefaultOutboundSignatureTolerance = 5 * time.Minute
+)
+
+// OutboundEndpoint is a customer-registered destination for lifecycle events.
+// EventFilters accepts exact event names, prefix patterns ("connection.*") or
+// "*"; an empty filter list receives every event for the scope.
+type OutboundEndpoint struct {
+	ID                  string
+	ScopeType           string
+	ScopeID             string
+	URL                 string
+	Secret              string
+	EventFilters        []string
+	Status              string
+	DisabledReason      string
+	ConsecutiveFailures int
+	CircuitOpenUntil    *time.Time
+	Metadata            map[string]any
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type RegisterOutboundEndpointInput struct {
+	ScopeType    string
+	ScopeID      string
+	URL          string
+	Secret       string
+	EventFilters []string
+	Metadata     map[string]any
+}
+
+// UpdateOutboundEndpointInput replaces the URL, event filters and metadata of
+// an endpoint. An empty Secret keeps the current secret.
+type UpdateOutboundEndpointInput struct {
+	URL          string
+	Secret       string
+	EventFilters []string
+	Metadata     map[string]any
+}
+
+type OutboundEndpointHealth struct {
+	ConsecutiveFailures int
+	CircuitOpenUntil    *time.Time
+	Status              string
+	DisabledReason      string
+}
+
+type OutboundDelivery struct {
+	ID             string
+	EndpointID     string
+	EventID        string
+	EventName      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type OutboundDeliveryResult struct {
+	StatusCode    int
+	Error         string
+	NextAttemptAt *time.Time
+	Dead          bool
+}
+
+type OutboundEndpointStore interface {
+	Register(ctx context.Context, in RegisterOutboundEndpointInput) (OutboundEndpoint, error)
+	Get(ctx context.Context, id string) (OutboundEndpoint, error)
+	ListByScope(ctx context.Context, scope core.ScopeRef) ([]OutboundEndpoint, error)
+	UpdateHealth(ctx context.Context, id string, health OutboundEndpointHealth) error
+	Update(ctx context.Context, id string, in UpdateOutboundEndpointInput) (OutboundEndpoint, error)
+	// Enable reactivates an endpoint and resets its failure count and circuit.
+	Enable(ctx context.Context, id string) (OutboundEndpoint, error)
+	// Delete removes an endpoint together with its delivery log.
+	Delete(ctx context.Context, id string) error
+}
+
+type OutboundDeliveryStore interface {
+	// Enqueue is idempotent on (endpoint id, event id).
+	Enqueue(ctx context.Context, delivery OutboundDelivery) (OutboundDelivery, bool, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]OutboundDelivery, error)
+	Get(ctx context.Context, id string) (OutboundDelivery, error)
+	ListByEndpoint(ctx context.Context, endpointID string, limit int) ([]OutboundDelivery, error)
+	Complete(ctx context.Context, id string, result OutboundDeliveryResult) error
+	Fail(ctx context.Context, id string, result OutboundDeliveryResult) error
+	Defer(ctx context.Context, id string, nextAttemptAt time.Time) error
+	Requeue(ctx context.Context, id string) error
+}
+
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// SignOutboundPayload signs "<timestamp>.<body>" with HMAC-SHA256, so the
+// X-Webhook-Timestamp header cannot be swapped to replay an old delivery.
+// Unlike the body-only provider signatures, the signed content includes the
+// timestamp; HeaderHMACVerifier checks it when TimestampHeader is set.
+func SignOutboundPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(strings.TrimSpace(secret)))
+	_, _ = mac.Write([]byte(strings.TrimSpace(timestamp)))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return OutboundSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// OutboundSignatureVerifier validates outbound deliveries on the receiving
+// side: the signature must match the signed timestamp and body, and the
+// timestamp must be within Tolerance of Now.
+type OutboundSignatureVerifier struct {
+	Secret    string
+	Tolerance time.Duration
+	Now       func() time.Time
+}
+
+// NewOutboundSignatureVerifier returns the verifier receivers can use to
+// validate outbound deliveries.
+func NewOutboundSignatureVerifier(secret string) OutboundSignatureVerifier {
+	return OutboundSignatureVerifier{
+		Secret:    strings.TrimSpace(secret),
+		Tolerance: DefaultOutboundSignatureTolerance,
+	}
+}
+
+func (v OutboundSignatureVerifier) Verify(ctx context.Context, req core.InboundRequest) error {
+	secret := strings.TrimSpace(v.Secret)
+	if secret == "" {
+		return fmt.Errorf("webhooks: signature secret is required")
+	}
+	signature := HeaderValue(req.Headers, OutboundHeaderSignature)
+	if signature == "" {
+		return fmt.Errorf("webhooks: %s signature header is required", OutboundHeaderSignature)
+	}
+	timestamp := HeaderValue(req.Headers, OutboundHeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhooks: %s header must be a unix timestamp", OutboundHeaderTimestamp)
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultOutboundSignatureTolerance
+	}
+	now := time.Now().UTC()
+	if v.Now != nil {
+		now = v.Now().UTC()
+	}
+	if drift := now.Sub(time.Unix(seconds, 0)); drift > tolerance || drift < -tolerance {
+		return fmt.Errorf("webhooks: outbound signature timestamp is outside the %s tolerance", tolerance)
+	}
+	return HeaderHMACVerifier{
+		Header:          OutboundHeaderSignature,
+		Prefix:          OutboundSignaturePrefix,
+		Secret:          secret,
+		TimestampHeader: OutboundHeaderTimestamp,
+	}.Verify(ctx, req)
+}
+
+// OutboundEventEnvelope is the JSON body posted to customer endpoints.
+type OutboundEventEnvelope struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	ProviderID   string         `json:"provider_id"`
+	ScopeType    string         `json:"scope_type"`
+	ScopeID      string         `json:"scope_id"`
+	ConnectionID string         `json:"connection_id,omitempty"`
+	Source       string         `json:"source,omitempty"`
+	OccurredAt   time.Time      `json:"occurred_at"`
+	Payload      map[string]any `json:"payload,omitempty"`
+}
+
+// OutboundProjector is a lifecycle projector that fans events out into
+// durable outbound deliveries for every matching endpoint in the event scope.
+type OutboundProjector struct {
+	endpoints  OutboundEndpointStore
+	deliveries OutboundDeliveryStore
+}
+
+func NewOutboundProjector(endpoints OutboundEndpointStore, deliveries OutboundDeliveryStore) *OutboundProjector {
+	return &OutboundProjector{endpoints: endpoints, deliveries: deliveries}
+}
+
+func (p *OutboundProjector) Handle(ctx context.Context, event core.LifecycleEvent) error {
+	if p == nil || p.endpoints == nil || p.deliveries == nil {
+		return fmt.Errorf("webhooks: outbound projector requires endpoint and delivery stores")
+	}
+	eventID := strings.TrimSpace(event.ID)
+	if eventID == "" {
+		return fmt.Errorf("webhooks: lifecycle event id is required")
+	}
+	endpoints, err := p.endpoints.ListByScope(ctx, core.ScopeRef{
+		Type: strings.TrimSpace(event.ScopeType),
+		ID:   strings.TrimSpace(event.ScopeID),
+	})
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if endpoint.Status != OutboundEndpointStatusActive || !MatchesEventFilters(endpoint.EventFilters, event.Name) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(OutboundEventEnvelope{
+				ID:           eventID,
+				Name:         strings.TrimSpace(event.Name),
+				ProviderID:   strings.TrimSpace(event.ProviderID),
+				ScopeType:    strings.TrimSpace(event.ScopeType),
+				ScopeID:      strings.TrimSpace(event.ScopeID),
+				ConnectionID: strings.TrimSpace(event.ConnectionID),
+				Source:       strings.TrimSpace(event.Source),
+				OccurredAt:   event.OccurredAt.UTC(),
+				Payload:      core.RedactSensitiveMap(event.Payload),
+			})
+			if err != nil {
+				return fmt.Errorf("webhooks: encode outbound event: %w", err)
+			}
+		}
+		if _, _, err := p.deliveries.Enqueue(ctx, OutboundDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    eventID,
+			EventName:  strings.TrimSpace(event.Name),
+			Payload:    payload,
+			Status:     OutboundDeliveryStatusPending,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchesEventFilters reports whether name is selected by filters.
+func MatchesEventFilters(filters []string, name string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	name = normalizeRouteKey(name)
+	for _, filter := range filters {
+		filter = normalizeRouteKey(filter)
+		if filter == "" {
+			continue
+		}
+		if filter == RouteWildcard || filter == name {
+			return true
+		}
+		if strings.HasSuffix(filter, RouteWildcard) && strings.HasPrefix(name, strings.TrimSuffix(filter, RouteWildcard)) {
+			return true
+		}
+	}
+	return false
+}
+
+type OutboundDelivererConfig struct {
+	BatchSize   int
+	ClaimLease  time.Duration
+	MaxAttempts int
+	Timeout     time.Duration
+	RetryPolicy RetryPolicy
+	// CircuitThreshold is the number of consecutive endpoint failures that
+	// opens the circuit for CircuitCooldown.
+	CircuitThreshold int
+	CircuitCooldown  time.Duration
+	// DisableAfter auto-disables an endpoint after this many consecutive
+	// failures. Zero disables auto-disable.
+	DisableAfter int
+	// URLPolicy is checked before every send and guards the default client's
+	// dialer.
+	URLPolicy OutboundURLPolicy
+}
+
+func DefaultOutboundDelivererConfig() OutboundDelivererConfig {
+	return OutboundDelivererConfig{
+		BatchSize:        50,
+		ClaimLease:       time.Minute,
+		MaxAttempts:      8,
+		Timeout:          10 * time.Second,
+		RetryPolicy:      ExponentialRetryPolicy{Initial: 10 * time.Second, Max: time.Hour},
+		CircuitThreshold: 5,
+		CircuitCooldown:  5 * time.Minute,
+		DisableAfter:     50,
+	}
+}
+
+type OutboundDeliveryStats struct {
+	Claimed   int
+	Delivered int
+	Retried   int
+	Dead      int
+	Deferred  int
+}
+
+// OutboundDeliverer posts claimed deliveries to customer endpoints.
+type OutboundDeliverer struct {
+	endpoints  OutboundEndpointStore
+	deliveries OutboundDeliveryStore
+	client     HTTPDoer
+	config     OutboundDelivererConfig
+	Metrics    core.MetricsRecorder
+	Now        func() time.Time
+}
+
+func NewOutboundDeliverer(
+	endpoints OutboundEndpointStore,
+	deliveries OutboundDeliveryStore,
+	client HTTPDoer,
+	config OutboundDelivererConfig,
+) (*OutboundDeliverer, error) {
+	if endpoints == nil || deliveries == nil {
+		return nil, fmt.Errorf("webhooks: outbound deliverer requires endpoint and delivery stores")
+	}
+	defaults := DefaultOutboundDelivererConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.ClaimLease <= 0 {
+		config.ClaimLease = defaults.ClaimLease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.RetryPolicy == nil {
+		config.RetryPolicy = defaults.RetryPolicy
+	}
+	if config.CircuitThreshold <= 0 {
+		config.CircuitThreshold = defaults.CircuitThreshold
+	}
+	if config.CircuitCooldown <= 0 {
+		config.CircuitCooldown = defaults.CircuitCooldown
+	}
+	if config.DisableAfter < 0 {
+		config.DisableAfter = 0
+	}
+	if client == nil {
+		client = NewOutboundHTTPClient(config.Timeout, config.URLPolicy)
+	}
+	return &OutboundDeliverer{
+		endpoints:  endpoints,
+		deliveries: deliveries,
+		client:     client,
+		config:     config,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
+}
+
+func (d *OutboundDeliverer) DeliverPending(ctx context.Context, batchSize int) (OutboundDeliveryStats, error) {
+	if d == nil || d.deliveries == nil || d.endpoints == nil {
+		return OutboundDeliveryStats{}, fmt.Errorf("webhooks: outbound deliverer is not configured")
+	}
+	limit := batchSize
+	if limit <= 0 {
+		limit = d.config.BatchSize
+	}
+	claimed, err := d.deliveries.ClaimDue(ctx, limit, d.config.ClaimLease)
+	if err != nil {
+		return OutboundDeliveryStats{}, err
+	}
+	stats := OutboundDeliveryStats{Claimed: len(claimed)}
+	var deliverErr error
+	for _, delivery := range claimed {
+		outcome, err := d.deliverOne(ctx, delivery)
+		if err != nil {
+			deliverErr = errors.Join(deliverErr, err)
+		}
+		switch outcome {
+		case OutboundDeliveryStatusDelivered:
+			stats.Delivered++
+		case OutboundDeliveryStatusRetryReady:
+			stats.Retried++
+		case OutboundDeliveryStatusDead:
+			stats.Dead++
+		case OutboundDeliveryStatusPending:
+			stats.Deferred++
+		}
+	}
+	return stats, deliverErr
+}
+
+// Redeliver resets a delivery so the next DeliverPending run sends it again.
+func (d *OutboundDeliverer) Redeliver(ctx context.Context, deliveryID string) error {
+	if d == nil || d.deliveries == nil {
+		return fmt.Errorf("webhooks: outbound deliverer is not configured")
+	}
+	deliveryID = strings.TrimSpace(deliveryID)
+	if deliveryID == "" {
+		return fmt.Errorf("webhooks: delivery id is required")
+	}
+	return d.deliveries.Requeue(ctx, deliveryID)
+}
+
+func (d *OutboundDeliverer) deliverOne(ctx context.Context, delivery OutboundDelivery) (string, error) {
+	now := d.now()
+	endpoint, err := d.endpoints.Get(ctx, delivery.EndpointID)
+	if err != nil {
+		return "", err
+	}
+	if endpoint.Status != OutboundEndpointStatusActive {
+		return OutboundDeliveryStatusDead, d.deliveries.Fail(ctx, delivery.ID, OutboundDeliveryResult{
+			Error: "endpoint disabled",
+			Dead:  true,
+		})
+	}
+	if endpoint.CircuitOpenUntil != nil && endpoint.CircuitOpenUntil.After(now) {
+		return OutboundDeliveryStatusPending, d.deliveries.Defer(ctx, delivery.ID, endpoint.CircuitOpenUntil.UTC())
+	}
+
+	statusCode, sendErr := d.send(ctx, endpoint, delivery, now)
+	tags := map[string]string{"endpoint_id": endpoint.ID, "event_name": delivery.EventName}
+	if sendErr == nil {
+		d.recordCounter(ctx, "webhooks.outbound.delivered.total", tags)
+		if err := d.deliveries.Complete(ctx, delivery.ID, OutboundDeliveryResult{StatusCode: statusCode}); err != nil {
+			return "", err
+		}
+		if endpoint.ConsecutiveFailures > 0 || endpoint.CircuitOpenUntil != nil {
+			if err := d.endpoints.UpdateHealth(ctx, endpoint.ID, OutboundEndpointHealth{
+				Status: OutboundEndpointStatusActive,
+			}); err != nil {
+				return OutboundDeliveryStatusDelivered, err
+			}
+		}
+		return OutboundDeliveryStatusDelivered, nil
+	}
+
+	d.recordCounter(ctx, "webhooks.outbound.failed.total", tags)
+	result := OutboundDeliveryResult{StatusCode: statusCode, Error: sendErr.Error()}
+	outcome := OutboundDeliveryStatusRetryReady
+	if delivery.Attempts >= d.config.MaxAttempts {
+		result.Dead = true
+		outcome = OutboundDeliveryStatusDead
+	} else {
+		next := now.Add(d.config.RetryPolicy.NextDelay(delivery.Attempts))
+		result.NextAttemptAt = &next
+	}
+	if err := d.deliveries.Fail(ctx, delivery.ID, result); err != nil {
+		return "", err
+	}
+	if err := d.endpoints.UpdateHealth(ctx, endpoint.ID, d.failureHealth(endpoint, now, sendErr)); err != nil {
+		return outcome, err
+	}
+	return outcome, nil
+}
+
+func (d *OutboundDeliverer) send(
+	ctx context.Context,
+	endpoint OutboundEndpoint,
+	delivery OutboundDelivery,
+	now time.Time,
+) (int, error) {
+	sendCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+	target, err := d.config.URLPolicy.ValidateURL(sendCtx, endpoint.URL)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, target.String(), bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("webhooks: build outbound request: %w", err)
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(OutboundHeaderID, delivery.ID)
+	req.Header.Set(OutboundHeaderEvent, delivery.EventName)
+	req.Header.Set(OutboundHeaderTimestamp, timestamp)
+	req.Header.Set(OutboundHeaderSignature, SignOutboundPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhooks: outbound delivery request failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("webhooks: outbound endpoint returned status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+func (d *OutboundDeliverer) failureHealth(endpoint OutboundEndpoint, now time.Time, cause error) OutboundEndpointHealth {
+	failures := endpoint.ConsecutiveFailures + 1
+	health := OutboundEndpointHealth{
+		ConsecutiveFailures: failures,
+		Status:              OutboundEndpointStatusActive,
+	}
+	if failures >= d.config.CircuitThreshold {
+		openUntil := now.Add(d.config.CircuitCooldown)
+		health.CircuitOpenUntil = &openUntil
+	}
+	if d.config.DisableAfter > 0 && failures >= d.config.DisableAfter {
+		health.Status = OutboundEndpointStatusDisabled
+		health.DisabledReason = fmt.Sprintf("auto-disabled after %d consecutive failures: %v", failures, cause)
+	}
+	return health
+}
+
+func (d *OutboundDeliverer) recordCounter(ctx context.Context, name string, tags map[string]string) {
+	if d == nil || d.Metrics == nil {
+		return
+	}
+	d.Metrics.IncCounter(ctx, name, 1, tags)
+}
+
+func (d *OutboundDeliverer) now() time.Time {
+	if d != nil && d.Now != nil {
+		return d.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+var (
+	_ core.LifecycleEventHandler = (*OutboundProjector)(nil)
+	_ Verifier                   = OutboundSignatureVerifier{}
+)
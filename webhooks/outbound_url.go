@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// OutboundURLPolicy decides which destinations customer endpoints may use.
+// The zero value requires https and rejects loopback, private, link-local and
+// other non-public addresses, both when an endpoint is registered and when a
+// delivery is dialed.
+type OutboundURLPolicy struct {
+	AllowHTTP            bool
+	AllowPrivateNetworks bool
+	// Resolver looks up hostnames at registration. Defaults to
+	// net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidateURL parses raw and rejects it when the scheme or host is not
+// allowed. Hostnames that resolve to internal addresses are rejected;
+// hostnames that do not resolve yet are accepted and checked again at dial
+// time.
+func (p OutboundURLPolicy) ValidateURL(ctx context.Context, raw string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Host == "" || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("webhooks: outbound endpoint url must be an absolute url")
+	}
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !p.AllowHTTP {
+			return nil, fmt.Errorf("webhooks: outbound endpoint url must use https")
+		}
+	default:
+		return nil, fmt.Errorf("webhooks: outbound endpoint url scheme %q is not supported", parsed.Scheme)
+	}
+	if parsed.User != nil {
+		return nil, fmt.Errorf("webhooks: outbound endpoint url must not embed credentials")
+	}
+	if p.AllowPrivateNetworks {
+		return parsed, nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return nil, fmt.Errorf("webhooks: outbound endpoint host %q is not public", host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddr(addr) {
+			return nil, fmt.Errorf("webhooks: outbound endpoint address %s is not public", addr)
+		}
+		return parsed, nil
+	}
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return parsed, nil
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return nil, fmt.Errorf("webhooks: outbound endpoint host %q resolves to non-public address %s", host, addr)
+		}
+	}
+	return parsed, nil
+}
+
+// DialControl rejects connections to non-public addresses after DNS
+// resolution, so a hostname cannot be re-pointed at an internal service after
+// registration. It returns nil when private networks are allowed.
+func (p OutboundURLPolicy) DialControl() func(network string, address string, conn syscall.RawConn) error {
+	if p.AllowPrivateNetworks {
+		return nil
+	}
+	return func(_ string, address string, _ syscall.RawConn) error {
+		addrPort, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return fmt.Errorf("webhooks: outbound dial address %q: %w", address, err)
+		}
+		if !IsPublicAddr(addrPort.Addr()) {
+			return fmt.Errorf("webhooks: outbound dial to non-public address %s refused", addrPort.Addr())
+		}
+		return nil
+	}
+}
+
+// NewOutboundHTTPClient returns the client the deliverer uses by default. It
+// dials through the policy guard, ignores proxy settings and does not follow
+// redirects, so every connection goes to an address the policy allows.
+func NewOutboundHTTPClient(timeout time.Duration, policy OutboundURLPolicy) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: policy.DialControl()}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublicAddr reports whether addr is a globally routable unicast address.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() {
+		return false
+	}
+	return !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
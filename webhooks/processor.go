@@ -234,13 +234,13 @@ func DefaultDeliveryIDExtractor(req core.InboundRequest) (string, error) {
 		}
 	}
 	if req.Headers != nil {
-		if value := headerValue(req.Headers, "x-delivery-id"); value != "" {
+		if value := HeaderValue(req.Headers, "x-delivery-id"); value != "" {
 			return value, nil
 		}
-		if value := headerValue(req.Headers, "x-github-delivery"); value != "" {
+		if value := HeaderValue(req.Headers, "x-github-delivery"); value != "" {
 			return value, nil
 		}
-		if value := headerValue(req.Headers, "x-goog-message-number"); value != "" {
+		if value := HeaderValue(req.Headers, "x-goog-message-number"); value != "" {
 			return value, nil
 		}
 	}
@@ -282,7 +282,9 @@ func ensureMetadata(metadata map[string]any) map[string]any {
 	return metadata
 }
 
-func headerValue(headers map[string]string, key string) string {
+// HeaderValue returns the trimmed value of the header named key, matched
+// case-insensitively, or "" when it is absent.
+func HeaderValue(headers map[string]string, key string) string {
 	if len(headers) == 0 {
 		return ""
 	}
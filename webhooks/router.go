@@ -0,0 +1,546 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/goliatone/go-services/core"
+)
+
+const (
+	// RouteWildcard matches any provider or topic when used as a route key.
+	RouteWildcard = "*"
+
+	MetricRouterDispatched = "webhooks.router.dispatched.total"
+	MetricRouterUnrouted   = "webhooks.router.unrouted.total"
+	MetricRouterFailed     = "webhooks.router.failed.total"
+	MetricRouterSkipped    = "webhooks.router.skipped.total"
+)
+
+// Event is a single provider event extracted from an inbound webhook request.
+// Batched payloads (for example Meta entry changes) produce one Event per item.
+type Event struct {
+	ProviderID string
+	Topic      string
+	Index      int
+	Payload    json.RawMessage
+	Metadata   map[string]any
+	Request    core.InboundRequest
+}
+
+// Decode unmarshals the event payload into target.
+func (e Event) Decode(target any) error {
+	if target == nil {
+		return fmt.Errorf("webhooks: decode target is required")
+	}
+	if len(e.Payload) == 0 {
+		return fmt.Errorf("webhooks: event payload is empty")
+	}
+	if err := json.Unmarshal(e.Payload, target); err != nil {
+		return fmt.Errorf("webhooks: decode %s event payload: %w", e.Topic, err)
+	}
+	return nil
+}
+
+// TopicExtractor resolves the events carried by an inbound request.
+type TopicExtractor func(req core.InboundRequest) ([]Event, error)
+
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event Event) error
+}
+
+type EventHandlerFunc func(ctx context.Context, event Event) error
+
+func (fn EventHandlerFunc) HandleEvent(ctx context.Context, event Event) error {
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, event)
+}
+
+// TypedEventHandler decodes each event payload into T before invoking fn.
+// T may be a struct or a map type such as map[string]any.
+func TypedEventHandler[T any](fn func(ctx context.Context, event Event, payload T) error) EventHandler {
+	return EventHandlerFunc(func(ctx context.Context, event Event) error {
+		if fn == nil {
+			return nil
+		}
+		var payload T
+		if len(event.Payload) > 0 {
+			if err := event.Decode(&payload); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, event, payload)
+	})
+}
+
+// EventOutcomeStore remembers which events of a delivery were handled, keyed
+// by the event index within the delivery.
+type EventOutcomeStore interface {
+	HandledEvents(ctx context.Context, providerID string, deliveryID string) ([]int, error)
+	MarkEventHandled(ctx context.Context, providerID string, deliveryID string, index int) error
+}
+
+// Router is a Handler that extracts event topics per provider and dispatches
+// each event to the handler registered for that topic.
+//
+// A batched delivery can partly fail. With Outcomes set, the router records
+// every handled event and returns an error for the failures, so a retried
+// delivery only re-dispatches the events that failed. Without Outcomes any
+// failed event still fails the delivery, so the provider redelivers it and
+// every event of it is dispatched again; handlers must then be idempotent.
+type Router struct {
+	// Fallback receives events without a matching route, and the whole body
+	// of providers without a registered extractor. When nil, unrouted events
+	// are acknowledged and reported through Metrics.
+	Fallback EventHandler
+	Metrics  core.MetricsRecorder
+	Outcomes EventOutcomeStore
+	// ExtractID resolves the delivery id outcomes are recorded under.
+	// Defaults to DefaultDeliveryIDExtractor.
+	ExtractID DeliveryIDExtractor
+
+	mu         sync.RWMutex
+	extractors map[string]TopicExtractor
+	routes     map[string]map[string]EventHandler
+}
+
+func NewRouter() *Router {
+	return &Router{
+		extractors: map[string]TopicExtractor{},
+		routes:     map[string]map[string]EventHandler{},
+	}
+}
+
+// UseTemplate registers the topic extractor carried by a provider template.
+func (r *Router) UseTemplate(template ProviderWebhookTemplate) error {
+	if template.Topics == nil {
+		return fmt.Errorf("webhooks: template %q has no topic extractor", template.ProviderID)
+	}
+	return r.RegisterExtractor(template.ProviderID, template.Topics)
+}
+
+func (r *Router) RegisterExtractor(providerID string, extractor TopicExtractor) error {
+	if r == nil {
+		return fmt.Errorf("webhooks: router is nil")
+	}
+	providerID = normalizeRouteKey(providerID)
+	if providerID == "" {
+		return fmt.Errorf("webhooks: provider id is required")
+	}
+	if extractor == nil {
+		return fmt.Errorf("webhooks: topic extractor is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ensureMaps()
+	r.extractors[providerID] = extractor
+	return nil
+}
+
+// On registers handler for topic on providerID. Either key may be "*", and a
+// topic ending in "*" matches by prefix (for example "orders/*").
+func (r *Router) On(providerID string, topic string, handler EventHandler) error {
+	if r == nil {
+		return fmt.Errorf("webhooks: router is nil")
+	}
+	providerID = normalizeRouteKey(providerID)
+	topic = normalizeRouteKey(topic)
+	if providerID == "" {
+		return fmt.Errorf("webhooks: provider id is required")
+	}
+	if topic == "" {
+		return fmt.Errorf("webhooks: topic is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("webhooks: event handler is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ensureMaps()
+	topics := r.routes[providerID]
+	if topics == nil {
+		topics = map[string]EventHandler{}
+		r.routes[providerID] = topics
+	}
+	if _, exists := topics[topic]; exists {
+		return fmt.Errorf("webhooks: handler already registered for %s topic %q", providerID, topic)
+	}
+	topics[topic] = handler
+	return nil
+}
+
+func (r *Router) OnFunc(providerID string, topic string, fn func(ctx context.Context, event Event) error) error {
+	if fn == nil {
+		return fmt.Errorf("webhooks: event handler is required")
+	}
+	return r.On(providerID, topic, EventHandlerFunc(fn))
+}
+
+func (r *Router) Handle(ctx context.Context, req core.InboundRequest) (core.InboundResult, error) {
+	if r == nil {
+		return core.InboundResult{}, fmt.Errorf("webhooks: router is nil")
+	}
+	providerID := normalizeRouteKey(req.ProviderID)
+	if providerID == "" {
+		return core.InboundResult{}, fmt.Errorf("webhooks: provider id is required")
+	}
+
+	events, extracted, err := r.extract(providerID, req)
+	if err != nil {
+		return core.InboundResult{}, err
+	}
+	deliveryID, handled, err := r.handledEvents(ctx, providerID, req)
+	if err != nil {
+		return core.InboundResult{}, err
+	}
+
+	topics := make([]string, 0, len(events))
+	unrouted := make([]string, 0)
+	skipped := 0
+	var errs []error
+	for index, event := range events {
+		event.ProviderID = providerID
+		event.Topic = normalizeRouteKey(event.Topic)
+		event.Index = index
+		event.Request = req
+		topics = append(topics, event.Topic)
+
+		tags := map[string]string{"provider_id": providerID, "topic": event.Topic}
+		if handled[index] {
+			skipped++
+			r.recordCounter(ctx, MetricRouterSkipped, tags)
+			continue
+		}
+		var handler EventHandler
+		if extracted {
+			handler = r.match(providerID, event.Topic)
+		}
+		if handler == nil {
+			unrouted = append(unrouted, event.Topic)
+			r.recordCounter(ctx, MetricRouterUnrouted, tags)
+			if r.Fallback == nil {
+				continue
+			}
+			handler = r.Fallback
+		}
+		if handleErr := handler.HandleEvent(ctx, event); handleErr != nil {
+			r.recordCounter(ctx, MetricRouterFailed, tags)
+			errs = append(errs, fmt.Errorf("webhooks: handle %s event %q: %w", providerID, event.Topic, handleErr))
+			continue
+		}
+		r.recordCounter(ctx, MetricRouterDispatched, tags)
+		if deliveryID == "" {
+			continue
+		}
+		if markErr := r.Outcomes.MarkEventHandled(ctx, providerID, deliveryID, index); markErr != nil {
+			errs = append(errs, fmt.Errorf("webhooks: record %s event %d outcome: %w", providerID, index, markErr))
+		}
+	}
+	if len(errs) > 0 {
+		return core.InboundResult{}, errors.Join(errs...)
+	}
+
+	metadata := map[string]any{
+		"provider_id": providerID,
+		"events":      len(events),
+		"topics":      topics,
+	}
+	if len(unrouted) > 0 {
+		metadata["unrouted"] = unrouted
+	}
+	if skipped > 0 {
+		metadata["skipped"] = skipped
+	}
+	return core.InboundResult{
+		Accepted:   true,
+		StatusCode: http.StatusOK,
+		Metadata:   metadata,
+	}, nil
+}
+
+// extract resolves the events of req. Providers without an extractor are
+// handed to Fallback as a single event carrying the whole body.
+func (r *Router) extract(providerID string, req core.InboundRequest) ([]Event, bool, error) {
+	extractor := r.extractor(providerID)
+	if extractor == nil {
+		if r.Fallback == nil {
+			return nil, false, fmt.Errorf("webhooks: no topic extractor registered for provider %q", providerID)
+		}
+		return []Event{{Payload: rawPayload(req.Body)}}, false, nil
+	}
+	events, err := extractor(req)
+	if err != nil {
+		return nil, false, err
+	}
+	return events, true, nil
+}
+
+// handledEvents loads the indexes already handled for the delivery. The
+// returned delivery id is empty when outcomes are not tracked.
+func (r *Router) handledEvents(ctx context.Context, providerID string, req core.InboundRequest) (string, map[int]bool, error) {
+	if r.Outcomes == nil {
+		return "", nil, nil
+	}
+	extractID := r.ExtractID
+	if extractID == nil {
+		extractID = DefaultDeliveryIDExtractor
+	}
+	deliveryID, err := extractID(req)
+	if err != nil {
+		return "", nil, err
+	}
+	indexes, err := r.Outcomes.HandledEvents(ctx, providerID, deliveryID)
+	if err != nil {
+		return "", nil, fmt.Errorf("webhooks: load %s event outcomes: %w", providerID, err)
+	}
+	handled := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		handled[index] = true
+	}
+	return deliveryID, handled, nil
+}
+
+func (r *Router) extractor(providerID string) TopicExtractor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if extractor := r.extractors[providerID]; extractor != nil {
+		return extractor
+	}
+	return r.extractors[RouteWildcard]
+}
+
+func (r *Router) match(providerID string, topic string) EventHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range []string{providerID, RouteWildcard} {
+		if handler := matchTopic(r.routes[key], topic); handler != nil {
+			return handler
+		}
+	}
+	return nil
+}
+
+// matchTopic prefers an exact route, then the longest matching prefix pattern.
+func matchTopic(routes map[string]EventHandler, topic string) EventHandler {
+	if len(routes) == 0 {
+		return nil
+	}
+	if handler, ok := routes[topic]; ok {
+		return handler
+	}
+	var (
+		matched    EventHandler
+		matchedLen = -1
+	)
+	for pattern, handler := range routes {
+		if !strings.HasSuffix(pattern, RouteWildcard) {
+			continue
+		}
+		prefix := strings.TrimSuffix(pattern, RouteWildcard)
+		if strings.HasPrefix(topic, prefix) && len(prefix) > matchedLen {
+			matched = handler
+			matchedLen = len(prefix)
+		}
+	}
+	return matched
+}
+
+func (r *Router) recordCounter(ctx context.Context, name string, tags map[string]string) {
+	if r == nil || r.Metrics == nil {
+		return
+	}
+	r.Metrics.IncCounter(ctx, name, 1, tags)
+}
+
+func (r *Router) ensureMaps() {
+	if r.extractors == nil {
+		r.extractors = map[string]TopicExtractor{}
+	}
+	if r.routes == nil {
+		r.routes = map[string]map[string]EventHandler{}
+	}
+}
+
+func normalizeRouteKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// HeaderTopicExtractor reads the topic from the first non-empty header and
+// emits the whole body as a single event.
+func HeaderTopicExtractor(headers ...string) TopicExtractor {
+	keys := append([]string(nil), headers...)
+	return func(req core.InboundRequest) ([]Event, error) {
+		for _, key := range keys {
+			if topic := strings.TrimSpace(HeaderValue(req.Headers, key)); topic != "" {
+				return []Event{{Topic: topic, Payload: rawPayload(req.Body)}}, nil
+			}
+		}
+		return nil, fmt.Errorf("webhooks: event topic header is required")
+	}
+}
+
+// BodyTopicExtractor reads the topic from the first non-empty top-level JSON
+// field and emits the whole body as a single event.
+func BodyTopicExtractor(fields ...string) TopicExtractor {
+	keys := append([]string(nil), fields...)
+	return func(req core.InboundRequest) ([]Event, error) {
+		var body map[string]any
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return nil, fmt.Errorf("webhooks: decode event body: %w", err)
+		}
+		for _, key := range keys {
+			if topic := stringField(body, key); topic != "" {
+				return []Event{{Topic: topic, Payload: rawPayload(req.Body)}}, nil
+			}
+		}
+		return nil, fmt.Errorf("webhooks: event topic field is required")
+	}
+}
+
+// GitHubTopicExtractor uses X-GitHub-Event and appends the payload action when
+// present, producing topics such as "issues.opened".
+func GitHubTopicExtractor(req core.InboundRequest) ([]Event, error) {
+	event := strings.TrimSpace(HeaderValue(req.Headers, "X-GitHub-Event"))
+	if event == "" {
+		return nil, fmt.Errorf("webhooks: X-GitHub-Event header is required")
+	}
+	topic := event
+	var body map[string]any
+	if len(req.Body) > 0 && json.Unmarshal(req.Body, &body) == nil {
+		if action := stringField(body, "action"); action != "" {
+			topic = event + "." + action
+		}
+	}
+	return []Event{{
+		Topic:    topic,
+		Payload:  rawPayload(req.Body),
+		Metadata: map[string]any{"event": event},
+	}}, nil
+}
+
+// MetaTopicExtractor splits Meta batched payloads into one event per
+// entry change (topic "<object>.<field>") or messaging item
+// (topic "<object>.messaging").
+func MetaTopicExtractor(req core.InboundRequest) ([]Event, error) {
+	var body struct {
+		Object string `json:"object"`
+		Entry  []struct {
+			ID        string            `json:"id"`
+			Time      int64             `json:"time"`
+			Changes   []json.RawMessage `json:"changes"`
+			Messaging []json.RawMessage `json:"messaging"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return nil, fmt.Errorf("webhooks: decode meta event body: %w", err)
+	}
+	object := strings.TrimSpace(body.Object)
+	if object == "" {
+		return nil, fmt.Errorf("webhooks: meta object is required")
+	}
+
+	events := make([]Event, 0, len(body.Entry))
+	for _, entry := range body.Entry {
+		for _, change := range entry.Changes {
+			var header struct {
+				Field string `json:"field"`
+			}
+			if err := json.Unmarshal(change, &header); err != nil {
+				return nil, fmt.Errorf("webhooks: decode meta entry change: %w", err)
+			}
+			topic := object
+			if field := strings.TrimSpace(header.Field); field != "" {
+				topic = object + "." + field
+			}
+			events = append(events, Event{
+				Topic:    topic,
+				Payload:  change,
+				Metadata: map[string]any{"entry_id": entry.ID, "entry_time": entry.Time},
+			})
+		}
+		for _, message := range entry.Messaging {
+			events = append(events, Event{
+				Topic:    object + ".messaging",
+				Payload:  message,
+				Metadata: map[string]any{"entry_id": entry.ID, "entry_time": entry.Time},
+			})
+		}
+	}
+	return events, nil
+}
+
+func rawPayload(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	return json.RawMessage(append([]byte(nil), body...))
+}
+
+func stringField(body map[string]any, key string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	value, ok := body[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// MemoryEventOutcomeStore keeps event outcomes in process. It suits single
+// replica deployments and tests; retries handled by another replica need a
+// shared store.
+type MemoryEventOutcomeStore struct {
+	mu      sync.Mutex
+	handled map[string]map[int]struct{}
+}
+
+func NewMemoryEventOutcomeStore() *MemoryEventOutcomeStore {
+	return &MemoryEventOutcomeStore{handled: map[string]map[int]struct{}{}}
+}
+
+func (s *MemoryEventOutcomeStore) HandledEvents(_ context.Context, providerID string, deliveryID string) ([]int, error) {
+	if s == nil {
+		return nil, fmt.Errorf("webhooks: event outcome store is nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	indexes := make([]int, 0, len(s.handled[eventOutcomeKey(providerID, deliveryID)]))
+	for index := range s.handled[eventOutcomeKey(providerID, deliveryID)] {
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func (s *MemoryEventOutcomeStore) MarkEventHandled(_ context.Context, providerID string, deliveryID string, index int) error {
+	if s == nil {
+		return fmt.Errorf("webhooks: event outcome store is nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.handled == nil {
+		s.handled = map[string]map[int]struct{}{}
+	}
+	key := eventOutcomeKey(providerID, deliveryID)
+	if s.handled[key] == nil {
+		s.handled[key] = map[int]struct{}{}
+	}
+	s.handled[key][index] = struct{}{}
+	return nil
+}
+
+func eventOutcomeKey(providerID string, deliveryID string) string {
+	return normalizeRouteKey(providerID) + "\x00" + strings.TrimSpace(deliveryID)
+}
+
+var (
+	_ Handler           = (*Router)(nil)
+	_ EventOutcomeStore = (*MemoryEventOutcomeStore)(nil)
+)
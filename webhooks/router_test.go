@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestRouter_DispatchesShopifyTopicToTypedHandler(t *testing.T) {
+	router := NewRouter()
+	if err := router.UseTemplate(NewShopifyWebhookTemplate("secret")); err != nil {
+		t.Fatalf("use shopify template: %v", err)
+	}
+
+	type orderPayload struct {
+		ID    int64  `json:"id"`
+		Email string `json:"email"`
+	}
+	var received orderPayload
+	if err := router.On("shopify", "orders/create", TypedEventHandler(
+		func(_ context.Context, event Event, payload orderPayload) error {
+			if event.Topic != "orders/create" {
+				t.Fatalf("unexpected topic %q", event.Topic)
+			}
+			received = payload
+			return nil
+		},
+	)); err != nil {
+		t.Fatalf("register route: %v", err)
+	}
+
+	result, err := router.Handle(context.Background(), core.InboundRequest{
+		ProviderID: "shopify",
+		Headers:    map[string]string{"X-Shopify-Topic": "orders/create"},
+		Body:       []byte(`{"id":42,"email":"buyer@example.com"}`),
+	})
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if !result.Accepted {
+		t.Fatalf("expected accepted result")
+	}
+	if received.ID != 42 || received.Email != "buyer@example.com" {
+		t.Fatalf("unexpected decoded payload %#v", received)
+	}
+}
+
+func TestRouter_SplitsMetaEntriesAndPrefersSpecificRoutes(t *testing.T) {
+	router := NewRouter()
+	if err := router.UseTemplate(NewMetaWebhookTemplate("secret")); err != nil {
+		t.Fatalf("use meta template: %v", err)
+	}
+
+	var mu sync.Mutex
+	calls := map[string][]string{}
+	record := func(route string) EventHandler {
+		return TypedEventHandler(func(_ context.Context, event Event, payload map[string]any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[route] = append(calls[route], event.Topic)
+			return nil
+		})
+	}
+	if err := router.On("meta", "page.feed", record("exact")); err != nil {
+		t.Fatalf("register exact route: %v", err)
+	}
+	if err := router.On("meta", "page.*", record("prefix")); err != nil {
+		t.Fatalf("register prefix route: %v", err)
+	}
+	if err := router.On("*", "*", record("wildcard")); err != nil {
+		t.Fatalf("register wildcard route: %v", err)
+	}
+
+	body := []byte(`{"object":"page","entry":[` +
+		`{"id":"1","time":1,"changes":[{"field":"feed","value":{"item":"post"}},{"field":"mention","value":{}}]},` +
+		`{"id":"2","time":2,"messaging":[{"sender":{"id":"u1"}}]}]}`)
+	result, err := router.Handle(context.Background(), core.InboundRequest{ProviderID: "meta", Body: body})
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if result.Metadata["events"] != 3 {
+		t.Fatalf("expected three split events, got %#v", result.Metadata["events"])
+	}
+	if len(calls["exact"]) != 1 || calls["exact"][0] != "page.feed" {
+		t.Fatalf("expected exact route for page.feed, got %#v", calls)
+	}
+	if len(calls["prefix"]) != 2 {
+		t.Fatalf("expected prefix route for mention and messaging, got %#v", calls)
+	}
+	if len(calls["wildcard"]) != 0 {
+		t.Fatalf("expected wildcard route unused, got %#v", calls)
+	}
+}
+
+func TestRouter_ReportsUnroutedTopicsAndUsesFallback(t *testing.T) {
+	metrics := &recordingMetrics{}
+	router := NewRouter()
+	router.Metrics = metrics
+	if err := router.RegisterExtractor("github", GitHubTopicExtractor); err != nil {
+		t.Fatalf("register extractor: %v", err)
+	}
+
+	req := core.InboundRequest{
+		ProviderID: "github",
+		Headers:    map[string]string{"X-GitHub-Event": "issues"},
+		Body:       []byte(`{"action":"opened"}`),
+	}
+	result, err := router.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	unrouted, _ := result.Metadata["unrouted"].([]string)
+	if len(unrouted) != 1 || unrouted[0] != "issues.opened" {
+		t.Fatalf("expected unrouted issues.opened, got %#v", result.Metadata["unrouted"])
+	}
+	if metrics.counters[MetricRouterUnrouted] != 1 {
+		t.Fatalf("expected unrouted metric, got %#v", metrics.counters)
+	}
+
+	fallbackCalls := 0
+	router.Fallback = EventHandlerFunc(func(context.Context, Event) error {
+		fallbackCalls++
+		return nil
+	})
+	if _, err := router.Handle(context.Background(), req); err != nil {
+		t.Fatalf("handle with fallback: %v", err)
+	}
+	if fallbackCalls != 1 {
+		t.Fatalf("expected fallback to receive unrouted event")
+	}
+}
+
+func TestRouter_ReturnsHandlerErrorsForRetry(t *testing.T) {
+	router := NewRouter()
+	if err := router.UseTemplate(NewGoogleWebhookTemplate("token")); err != nil {
+		t.Fatalf("use google template: %v", err)
+	}
+	if err := router.OnFunc("google", "exists", func(context.Context, Event) error {
+		return errors.New("downstream unavailable")
+	}); err != nil {
+		t.Fatalf("register route: %v", err)
+	}
+	if err := router.OnFunc("google", "exists", func(context.Context, Event) error { return nil }); err == nil {
+		t.Fatalf("expected duplicate route registration to fail")
+	}
+
+	processor := NewProcessor(nil, newMemoryDeliveryLedger(), router)
+	_, err := processor.Process(context.Background(), core.InboundRequest{
+		ProviderID: "google",
+		Headers: map[string]string{
+			"X-Goog-Resource-State": "exists",
+			"X-Goog-Message-Number": "7",
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected handler error to surface as retryable failure")
+	}
+}
+
+func TestRouter_RetriesOnlyFailedEventsWhenOutcomesAreTracked(t *testing.T) {
+	router := NewRouter()
+	router.Outcomes = NewMemoryEventOutcomeStore()
+	if err := router.UseTemplate(NewMetaWebhookTemplate("secret")); err != nil {
+		t.Fatalf("use meta template: %v", err)
+	}
+	calls := map[string]int{}
+	failMention := true
+	if err := router.OnFunc("meta", "page.*", func(_ context.Context, event Event) error {
+		calls[event.Topic]++
+		if event.Topic == "page.mention" && failMention {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("register route: %v", err)
+	}
+
+	processor := NewProcessor(nil, newMemoryDeliveryLedger(), router)
+	processor.RetryPolicy = ExponentialRetryPolicy{Initial: time.Nanosecond, Max: time.Nanosecond}
+	req := core.InboundRequest{
+		ProviderID: "meta",
+		Metadata:   map[string]any{"delivery_id": "batch-1"},
+		Body: []byte(`{"object":"page","entry":[` +
+			`{"id":"1","time":1,"changes":[{"field":"feed","value":{}},{"field":"mention","value":{}}]}]}`),
+	}
+	if _, err := processor.Process(context.Background(), req); err == nil {
+		t.Fatalf("expected failed event to surface for retry")
+	}
+
+	failMention = false
+	time.Sleep(time.Millisecond)
+	result, err := processor.Process(context.Background(), req)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if calls["page.feed"] != 1 || calls["page.mention"] != 2 {
+		t.Fatalf("expected only the failed event to be re-dispatched, got %#v", calls)
+	}
+	if result.Metadata["skipped"] != 1 {
+		t.Fatalf("expected one skipped event, got %#v", result.Metadata)
+	}
+}
+
+func TestRouter_FailsPartialSuccessWithoutOutcomes(t *testing.T) {
+	router := NewRouter()
+	if err := router.UseTemplate(NewMetaWebhookTemplate("secret")); err != nil {
+		t.Fatalf("use meta template: %v", err)
+	}
+	if err := router.OnFunc("meta", "page.mention", func(context.Context, Event) error {
+		return errors.New("downstream unavailable")
+	}); err != nil {
+		t.Fatalf("register failing route: %v", err)
+	}
+	feedCalls := 0
+	if err := router.OnFunc("meta", "page.feed", func(context.Context, Event) error {
+		feedCalls++
+		return nil
+	}); err != nil {
+		t.Fatalf("register route: %v", err)
+	}
+
+	result, err := router.Handle(context.Background(), core.InboundRequest{
+		ProviderID: "meta",
+		Body: []byte(`{"object":"page","entry":[` +
+			`{"id":"1","time":1,"changes":[{"field":"feed","value":{}},{"field":"mention","value":{}}]}]}`),
+	})
+	if err == nil || !strings.Contains(err.Error(), "page.mention") {
+		t.Fatalf("expected the failed mention event to fail the delivery, got %v", err)
+	}
+	if result.Accepted {
+		t.Fatalf("expected delivery not to be accepted, got %#v", result)
+	}
+	if feedCalls != 1 {
+		t.Fatalf("expected the feed event to be dispatched once, got %d", feedCalls)
+	}
+}
+
+func TestRouter_RoutesProvidersWithoutExtractorToFallback(t *testing.T) {
+	router := NewRouter()
+	req := core.InboundRequest{ProviderID: "acme", Body: []byte(`{"kind":"ping"}`)}
+	if _, err := router.Handle(context.Background(), req); err == nil {
+		t.Fatalf("expected missing extractor to fail without a fallback")
+	}
+
+	var received Event
+	router.Fallback = EventHandlerFunc(func(_ context.Context, event Event) error {
+		received = event
+		return nil
+	})
+	result, err := router.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handle with fallback: %v", err)
+	}
+	if received.ProviderID != "acme" || string(received.Payload) != `{"kind":"ping"}` {
+		t.Fatalf("expected fallback to receive the raw body, got %#v", received)
+	}
+	if !result.Accepted {
+		t.Fatalf("expected accepted result")
+	}
+}
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+func (m *recordingMetrics) IncCounter(_ context.Context, name string, value int64, _ map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counters == nil {
+		m.counters = map[string]int64{}
+	}
+	m.counters[name] += value
+}
+
+func (m *recordingMetrics) ObserveHistogram(context.Context, string, float64, map[string]string) {}
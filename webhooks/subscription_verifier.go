@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+// SubscriptionLookup resolves the subscription a notification was sent for.
+type SubscriptionLookup interface {
+	GetByChannelID(ctx context.Context, providerID, channelID string) (core.Subscription, error)
+}
+
+// SubscriptionTokenVerifier verifies notifications with the per-subscription
+// secret stored encrypted in Subscription.VerificationTokenRef. The channel
+// header identifies the subscription and Template builds the provider
+// verifier for the decrypted token. After a secret rotation the previous
+// token is also accepted until its grace window closes.
+type SubscriptionTokenVerifier struct {
+	ProviderID    string
+	ChannelHeader string
+	Subscriptions SubscriptionLookup
+	Secrets       core.SecretProvider
+	Template      func(token string) ProviderWebhookTemplate
+	Now           func() time.Time
+}
+
+func (v SubscriptionTokenVerifier) Verify(ctx context.Context, req core.InboundRequest) error {
+	if v.Subscriptions == nil {
+		return fmt.Errorf("webhooks: subscription lookup is required")
+	}
+	if v.Template == nil {
+		return fmt.Errorf("webhooks: subscription verifier template is required")
+	}
+	channelID := strings.TrimSpace(HeaderValue(req.Headers, v.ChannelHeader))
+	if channelID == "" {
+		return fmt.Errorf("webhooks: %s channel header is required", strings.TrimSpace(v.ChannelHeader))
+	}
+	providerID := strings.TrimSpace(v.ProviderID)
+	if providerID == "" {
+		providerID = strings.TrimSpace(req.ProviderID)
+	}
+	subscription, err := v.Subscriptions.GetByChannelID(ctx, providerID, channelID)
+	if err != nil {
+		return fmt.Errorf("webhooks: resolve subscription for channel %q: %w", channelID, err)
+	}
+	verifyErr := v.verifyWith(ctx, req, subscription.VerificationTokenRef)
+	if verifyErr == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	if v.Now != nil {
+		now = v.Now().UTC()
+	}
+	previous, ok := core.PreviousVerificationTokenRef(subscription, now)
+	if !ok {
+		return verifyErr
+	}
+	if err := v.verifyWith(ctx, req, previous); err != nil {
+		return verifyErr
+	}
+	return nil
+}
+
+func (v SubscriptionTokenVerifier) verifyWith(ctx context.Context, req core.InboundRequest, ref string) error {
+	token, err := core.DecodeVerificationTokenRef(ctx, v.Secrets, ref)
+	if err != nil {
+		return fmt.Errorf("webhooks: resolve subscription verification token: %w", err)
+	}
+	verifier := v.Template(token).Verifier
+	if verifier == nil {
+		return fmt.Errorf("webhooks: subscription verifier template has no verifier")
+	}
+	return verifier.Verify(ctx, req)
+}
+
+// NewGoogleSubscriptionWebhookTemplate validates Google channel notifications
+// against the token stored for the channel named in X-Goog-Channel-Id.
+func NewGoogleSubscriptionWebhookTemplate(
+	providerID string,
+	subscriptions SubscriptionLookup,
+	secrets core.SecretProvider,
+) ProviderWebhookTemplate {
+	template := NewGoogleWebhookTemplate("")
+	template.ProviderID = strings.TrimSpace(providerID)
+	template.Verifier = SubscriptionTokenVerifier{
+		ProviderID:    template.ProviderID,
+		ChannelHeader: "X-Goog-Channel-Id",
+		Subscriptions: subscriptions,
+		Secrets:       secrets,
+		Template:      NewGoogleWebhookTemplate,
+	}
+	return template
+}
+
+// NewGitHubSubscriptionWebhookTemplate validates GitHub hook deliveries with
+// the secret stored for the hook named in X-GitHub-Hook-ID.
+func NewGitHubSubscriptionWebhookTemplate(
+	providerID string,
+	subscriptions SubscriptionLookup,
+	secrets core.SecretProvider,
+) ProviderWebhookTemplate {
+	template := NewGitHubWebhookTemplate("")
+	if providerID = strings.TrimSpace(providerID); providerID != "" {
+		template.ProviderID = providerID
+	}
+	template.Verifier = SubscriptionTokenVerifier{
+		ProviderID:    template.ProviderID,
+		ChannelHeader: "X-GitHub-Hook-ID",
+		Subscriptions: subscriptions,
+		Secrets:       secrets,
+		Template:      NewGitHubWebhookTemplate,
+	}
+	return template
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/goliatone/go-services/core"
+)
+
+func TestGoogleSubscriptionWebhookTemplate_VerifiesStoredChannelToken(t *testing.T) {
+	ctx := context.Background()
+	secrets := base64SecretProvider{}
+	ref, err := core.EncodeVerificationTokenRef(ctx, secrets, "channel-token")
+	if err != nil {
+		t.Fatalf("encode token ref: %v", err)
+	}
+	lookup := staticSubscriptionLookup{
+		"google_calendar/chan_1": {ID: "sub_1", ChannelID: "chan_1", VerificationTokenRef: ref},
+	}
+	template := NewGoogleSubscriptionWebhookTemplate("google_calendar", lookup, secrets)
+
+	req := core.InboundRequest{
+		ProviderID: "google_calendar",
+		Headers: map[string]string{
+			"X-Goog-Channel-Id":    "chan_1",
+			"X-Goog-Channel-Token": "channel-token",
+		},
+	}
+	if err := template.Verifier.Verify(ctx, req); err != nil {
+		t.Fatalf("expected stored token to verify: %v", err)
+	}
+
+	req.Headers["X-Goog-Channel-Token"] = "forged"
+	if err := template.Verifier.Verify(ctx, req); err == nil {
+		t.Fatalf("expected mismatched token to fail")
+	}
+	req.Headers["X-Goog-Channel-Id"] = "chan_unknown"
+	if err := template.Verifier.Verify(ctx, req); err == nil {
+		t.Fatalf("expected unknown channel to fail")
+	}
+}
+
+type staticSubscriptionLookup map[string]core.Subscription
+
+func (l staticSubscriptionLookup) GetByChannelID(_ context.Context, providerID, channelID string) (core.Subscription, error) {
+	subscription, ok := l[providerID+"/"+channelID]
+	if !ok {
+		return core.Subscription{}, fmt.Errorf("subscription not found")
+	}
+	return subscription, nil
+}
+
+type base64SecretProvider struct{}
+
+func (base64SecretProvider) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	return []byte(base64.StdEncoding.EncodeToString(plaintext)), nil
+}
+
+func (base64SecretProvider) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(string(ciphertext))
+}
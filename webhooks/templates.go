@@ -17,6 +17,7 @@ type ProviderWebhookTemplate struct {
 	ProviderID string
 	Verifier   Verifier
 	Extractor  DeliveryIDExtractor
+	Topics     TopicExtractor
 }
 
 type HeaderHMACVerifier struct {
@@ -24,10 +25,14 @@ type HeaderHMACVerifier struct {
 	Prefix   string
 	Secret   string
 	Encoding string // hex | base64
+	// TimestampHeader, when set, names a header whose value is signed ahead
+	// of the body as "<timestamp>.<body>", the scheme SignOutboundPayload
+	// uses. Freshness of the timestamp is left to the caller.
+	TimestampHeader string
 }
 
 func (v HeaderHMACVerifier) Verify(_ context.Context, req core.InboundRequest) error {
-	header := strings.TrimSpace(headerValue(req.Headers, v.Header))
+	header := strings.TrimSpace(HeaderValue(req.Headers, v.Header))
 	if header == "" {
 		return fmt.Errorf("webhooks: %s signature header is required", strings.TrimSpace(v.Header))
 	}
@@ -42,6 +47,13 @@ func (v HeaderHMACVerifier) Verify(_ context.Context, req core.InboundRequest) e
 	}
 
 	mac := hmac.New(sha256.New, []byte(secret))
+	if timestampHeader := strings.TrimSpace(v.TimestampHeader); timestampHeader != "" {
+		timestamp := strings.TrimSpace(HeaderValue(req.Headers, timestampHeader))
+		if timestamp == "" {
+			return fmt.Errorf("webhooks: %s timestamp header is required", timestampHeader)
+		}
+		_, _ = mac.Write([]byte(timestamp + "."))
+	}
 	_, _ = mac.Write(req.Body)
 	expected := mac.Sum(nil)
 
@@ -76,7 +88,7 @@ func (v HeaderTokenVerifier) Verify(_ context.Context, req core.InboundRequest)
 	if expected == "" {
 		return fmt.Errorf("webhooks: verification token is required")
 	}
-	actual := strings.TrimSpace(headerValue(req.Headers, v.Header))
+	actual := strings.TrimSpace(HeaderValue(req.Headers, v.Header))
 	if actual == "" {
 		return fmt.Errorf("webhooks: %s verification header is required", strings.TrimSpace(v.Header))
 	}
@@ -90,7 +102,7 @@ func HeaderDeliveryIDExtractor(headers ...string) DeliveryIDExtractor {
 	keys := append([]string(nil), headers...)
 	return func(req core.InboundRequest) (string, error) {
 		for _, key := range keys {
-			if value := strings.TrimSpace(headerValue(req.Headers, key)); value != "" {
+			if value := strings.TrimSpace(HeaderValue(req.Headers, key)); value != "" {
 				return value, nil
 			}
 		}
@@ -130,6 +142,7 @@ func NewShopifyWebhookTemplate(secret string) ProviderWebhookTemplate {
 			Encoding: "base64",
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Shopify-Webhook-Id", "X-Request-Id"),
+		Topics:    HeaderTopicExtractor("X-Shopify-Topic"),
 	}
 }
 
@@ -143,6 +156,7 @@ func NewMetaWebhookTemplate(secret string) ProviderWebhookTemplate {
 			Encoding: "hex",
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Meta-Delivery-Id", "X-Hub-Signature-256"),
+		Topics:    MetaTopicExtractor,
 	}
 }
 
@@ -155,6 +169,7 @@ func NewTikTokWebhookTemplate(secret string) ProviderWebhookTemplate {
 			Encoding: "hex",
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Tt-Request-Id", "X-Tt-Logid"),
+		Topics:    BodyTopicExtractor("event", "type"),
 	}
 }
 
@@ -167,6 +182,21 @@ func NewPinterestWebhookTemplate(secret string) ProviderWebhookTemplate {
 			Encoding: "hex",
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Pinterest-Delivery-Id", "X-Pinterest-Request-Id"),
+		Topics:    BodyTopicExtractor("event_type", "type"),
+	}
+}
+
+func NewGitHubWebhookTemplate(secret string) ProviderWebhookTemplate {
+	return ProviderWebhookTemplate{
+		ProviderID: "github",
+		Verifier: HeaderHMACVerifier{
+			Header:   "X-Hub-Signature-256",
+			Prefix:   "sha256=",
+			Secret:   strings.TrimSpace(secret),
+			Encoding: "hex",
+		},
+		Extractor: HeaderDeliveryIDExtractor("X-GitHub-Delivery"),
+		Topics:    GitHubTopicExtractor,
 	}
 }
 
@@ -178,6 +208,7 @@ func NewGoogleWebhookTemplate(channelToken string) ProviderWebhookTemplate {
 			Token:  strings.TrimSpace(channelToken),
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Goog-Message-Number", "X-Goog-Resource-Id"),
+		Topics:    HeaderTopicExtractor("X-Goog-Resource-State"),
 	}
 }
 
@@ -189,5 +220,6 @@ func NewAmazonWebhookTemplate(signatureToken string) ProviderWebhookTemplate {
 			Token:  strings.TrimSpace(signatureToken),
 		},
 		Extractor: HeaderDeliveryIDExtractor("X-Amz-Sns-Message-Id", "X-Amz-Request-Id"),
+		Topics:    BodyTopicExtractor("notificationType", "NotificationType", "Type"),
 	}
 }
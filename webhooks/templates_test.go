@@ -54,6 +54,16 @@ func TestProviderWebhookTemplates_VerifyAndExtract(t *testing.T) {
 		},
 	}, "pin_delivery_1")
 
+	github := NewGitHubWebhookTemplate("gh_secret")
+	verifyAndExtractTemplate(t, github, core.InboundRequest{
+		ProviderID: "github",
+		Body:       body,
+		Headers: map[string]string{
+			"X-Hub-Signature-256": "sha256=" + signHexHMAC("gh_secret", body),
+			"X-GitHub-Delivery":   "gh_delivery_1",
+		},
+	}, "gh_delivery_1")
+
 	google := NewGoogleWebhookTemplate("google_token")
 	verifyAndExtractTemplate(t, google, core.InboundRequest{
 		ProviderID: "google",
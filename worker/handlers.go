@@ -0,0 +1,282 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/installations"
+	servicesync "github.com/goliatone/go-services/sync"
+	"github.com/goliatone/go-services/webhooks"
+)
+
+// Message parameters read by the built-in handlers.
+const (
+	ParamProviderID     = "provider_id"
+	ParamConnectionID   = "connection_id"
+	ParamSubscriptionID = "subscription_id"
+	ParamBatchSize      = "batch_size"
+	ParamSyncBindingID  = "sync_binding_id"
+	ParamSyncJobID      = "sync_job_id"
+	ParamPageSize       = "page_size"
+	ParamMaxPages       = "max_pages"
+	ParamDeliveryID     = "delivery_id"
+	ParamInstallationID = installations.ParamInstallationID
+
+	defaultOutboxBatchSize = 100
+)
+
+type Refresher interface {
+	Refresh(ctx context.Context, req core.RefreshRequest) (core.RefreshResult, error)
+}
+
+type SubscriptionRenewer interface {
+	RenewSubscription(ctx context.Context, req core.RenewSubscriptionRequest) (core.Subscription, error)
+}
+
+type SyncPuller interface {
+	Pull(ctx context.Context, req servicesync.PullRequest) (servicesync.PullResult, error)
+}
+
+type WebhookRedeliverer interface {
+	Redeliver(ctx context.Context, deliveryID string) error
+}
+
+type SyncJobExecutor interface {
+	Execute(ctx context.Context, jobID string) (core.SyncJob, error)
+}
+
+type InstallationPurger interface {
+	Purge(ctx context.Context, installationID string) error
+}
+
+var (
+	_ Refresher           = (*core.Service)(nil)
+	_ SubscriptionRenewer = (*core.Service)(nil)
+	_ SyncPuller          = (*servicesync.PullDriver)(nil)
+	_ WebhookRedeliverer  = (*webhooks.OutboundDeliverer)(nil)
+	_ SyncJobExecutor     = (*core.SyncJobExecutor)(nil)
+	_ InstallationPurger  = (*installations.Orchestrator)(nil)
+)
+
+// Builtins names the services behind the built-in script paths. Nil fields
+// are not registered.
+type Builtins struct {
+	Refresher           Refresher
+	SubscriptionRenewer SubscriptionRenewer
+	Outbox              core.LifecycleDispatcher
+	OutboxBatchSize     int
+	Sync                SyncPuller
+	Webhooks            WebhookRedeliverer
+	SyncJobs            SyncJobExecutor
+	Installations       InstallationPurger
+}
+
+// RegisterBuiltins registers the handlers for the configured services.
+func (r *Runtime) RegisterBuiltins(builtins Builtins) error {
+	handlers := map[string]Handler{}
+	if builtins.Refresher != nil {
+		handlers[ScriptPathRefresh] = RefreshHandler(builtins.Refresher)
+	}
+	if builtins.SubscriptionRenewer != nil {
+		handlers[ScriptPathSubscriptionRenew] = SubscriptionRenewalHandler(builtins.SubscriptionRenewer)
+	}
+	if builtins.Outbox != nil {
+		handlers[ScriptPathOutboxDispatch] = OutboxDispatchHandler(builtins.Outbox, builtins.OutboxBatchSize)
+	}
+	if builtins.Sync != nil {
+		handlers[ScriptPathSyncIncremental] = SyncPullHandler(builtins.Sync)
+	}
+	if builtins.Webhooks != nil {
+		handlers[ScriptPathWebhookRedeliver] = WebhookRedeliveryHandler(builtins.Webhooks)
+	}
+	if builtins.SyncJobs != nil {
+		handlers[ScriptPathSyncJob] = SyncJobHandler(builtins.SyncJobs)
+	}
+	if builtins.Installations != nil {
+		handlers[ScriptPathInstallationPurge] = InstallationPurgeHandler(builtins.Installations)
+	}
+	for _, scriptPath := range []string{
+		ScriptPathRefresh,
+		ScriptPathSubscriptionRenew,
+		ScriptPathOutboxDispatch,
+		ScriptPathSyncIncremental,
+		ScriptPathWebhookRedeliver,
+		ScriptPathSyncJob,
+		ScriptPathInstallationPurge,
+	} {
+		handler, ok := handlers[scriptPath]
+		if !ok {
+			continue
+		}
+		if err := r.Register(scriptPath, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshHandler refreshes the credential of the connection_id parameter.
+func RefreshHandler(refresher Refresher) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		connectionID, err := requiredStringParam(msg, ParamConnectionID)
+		if err != nil {
+			return err
+		}
+		_, err = refresher.Refresh(ctx, core.RefreshRequest{
+			ProviderID:   stringParam(msg, ParamProviderID),
+			ConnectionID: connectionID,
+		})
+		return err
+	})
+}
+
+// SubscriptionRenewalHandler renews the subscription_id parameter.
+func SubscriptionRenewalHandler(renewer SubscriptionRenewer) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		subscriptionID, err := requiredStringParam(msg, ParamSubscriptionID)
+		if err != nil {
+			return err
+		}
+		_, err = renewer.RenewSubscription(ctx, core.RenewSubscriptionRequest{
+			SubscriptionID: subscriptionID,
+			Metadata:       map[string]any{"job_id": msg.JobID},
+		})
+		return err
+	})
+}
+
+// OutboxDispatchHandler dispatches one batch of pending lifecycle events,
+// sized by the batch_size parameter or batchSize.
+func OutboxDispatchHandler(dispatcher core.LifecycleDispatcher, batchSize int) Handler {
+	if batchSize <= 0 {
+		batchSize = defaultOutboxBatchSize
+	}
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		size, err := intParam(msg, ParamBatchSize, batchSize)
+		if err != nil {
+			return err
+		}
+		_, err = dispatcher.DispatchPending(ctx, size)
+		return err
+	})
+}
+
+// SyncPullHandler pulls changes for the sync_binding_id parameter, reporting
+// progress to sync_job_id when set.
+func SyncPullHandler(puller SyncPuller) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		bindingID, err := requiredStringParam(msg, ParamSyncBindingID)
+		if err != nil {
+			return err
+		}
+		pageSize, err := intParam(msg, ParamPageSize, 0)
+		if err != nil {
+			return err
+		}
+		maxPages, err := intParam(msg, ParamMaxPages, 0)
+		if err != nil {
+			return err
+		}
+		_, err = puller.Pull(ctx, servicesync.PullRequest{
+			BindingID: bindingID,
+			JobID:     stringParam(msg, ParamSyncJobID),
+			PageSize:  pageSize,
+			MaxPages:  maxPages,
+			Metadata:  map[string]any{"job_id": msg.JobID},
+		})
+		return err
+	})
+}
+
+// WebhookRedeliveryHandler redelivers the outbound webhook delivery_id
+// parameter.
+func WebhookRedeliveryHandler(redeliverer WebhookRedeliverer) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		deliveryID, err := requiredStringParam(msg, ParamDeliveryID)
+		if err != nil {
+			return err
+		}
+		return redeliverer.Redeliver(ctx, deliveryID)
+	})
+}
+
+// SyncJobHandler executes the sync_job_id parameter. Jobs that no longer
+// exist or whose provider has no runner are dead-lettered.
+func SyncJobHandler(executor SyncJobExecutor) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		jobID, err := requiredStringParam(msg, ParamSyncJobID)
+		if err != nil {
+			return err
+		}
+		_, err = executor.Execute(ctx, jobID)
+		if errors.Is(err, core.ErrSyncJobNotFound) || errors.Is(err, core.ErrSyncJobRunnerNotFound) {
+			return Permanent(err)
+		}
+		return err
+	})
+}
+
+// InstallationPurgeHandler purges the installation_id parameter. Purges of
+// installations that are not uninstalled are dead-lettered.
+func InstallationPurgeHandler(purger InstallationPurger) Handler {
+	return HandlerFunc(func(ctx context.Context, msg *core.JobExecutionMessage) error {
+		installationID, err := requiredStringParam(msg, ParamInstallationID)
+		if err != nil {
+			return err
+		}
+		err = purger.Purge(ctx, installationID)
+		if errors.Is(err, installations.ErrInstallationNotPurgable) {
+			return Permanent(err)
+		}
+		return err
+	})
+}
+
+func stringParam(msg *core.JobExecutionMessage, key string) string {
+	value, ok := msg.Parameters[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+func requiredStringParam(msg *core.JobExecutionMessage, key string) (string, error) {
+	value := stringParam(msg, key)
+	if value == "" {
+		return "", Permanent(fmt.Errorf("worker: %s job requires parameter %q", msg.ScriptPath, key))
+	}
+	return value, nil
+}
+
+// intParam reads an integer parameter, which may have been decoded from JSON
+// as a float or json.Number.
+func intParam(msg *core.JobExecutionMessage, key string, fallback int) (int, error) {
+	value, ok := msg.Parameters[key]
+	if !ok || value == nil {
+		return fallback, nil
+	}
+	switch typed := value.(type) {
+	case int:
+		return typed, nil
+	case int64:
+		return int(typed), nil
+	case float64:
+		if typed == float64(int(typed)) {
+			return int(typed), nil
+		}
+	case json.Number:
+		if parsed, err := typed.Int64(); err == nil {
+			return int(parsed), nil
+		}
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(typed)); err == nil {
+			return parsed, nil
+		}
+	}
+	return 0, Permanent(fmt.Errorf("worker: %s job parameter %q must be an integer, got %v", msg.ScriptPath, key, value))
+}
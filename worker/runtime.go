@@ -0,0 +1,416 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+	"github.com/goliatone/go-services/installations"
+)
+
+// Script paths routed to the built-in handlers.
+const (
+	ScriptPathRefresh           = "services.refresh"
+	ScriptPathSubscriptionRenew = "services.subscription.renew"
+	ScriptPathOutboxDispatch    = "services.outbox.dispatch"
+	ScriptPathSyncIncremental   = "services.sync.incremental"
+	ScriptPathWebhookRedeliver  = "services.webhook.redeliver"
+	ScriptPathSyncJob           = core.SyncJobScriptPath
+	ScriptPathInstallationPurge = installations.PurgeScriptPath
+)
+
+const MetricDequeueFailed = "services.worker.dequeue_failed.total"
+
+// settleTimeout bounds an ack or nack once the job context is canceled.
+const settleTimeout = 10 * time.Second
+
+// Handler executes one job message. Returned errors are retried unless they
+// are wrapped with Permanent, Cancel or Retry.
+type Handler interface {
+	Handle(ctx context.Context, msg *core.JobExecutionMessage) error
+}
+
+type HandlerFunc func(ctx context.Context, msg *core.JobExecutionMessage) error
+
+func (f HandlerFunc) Handle(ctx context.Context, msg *core.JobExecutionMessage) error {
+	return f(ctx, msg)
+}
+
+// RetryPolicy returns the delay before a failed job's next attempt.
+type RetryPolicy interface {
+	NextDelay(attempt int) time.Duration
+}
+
+// DequeueFailureHook is an optional extension of core.JobWorkerHook. It
+// receives dequeue errors together with the number of consecutive failures
+// and the backoff before the next poll.
+type DequeueFailureHook interface {
+	OnDequeueFailure(ctx context.Context, err error, failures int, backoff time.Duration)
+}
+
+// AttemptCounter is implemented by deliveries that know how many times the
+// job was delivered, including the current delivery. Without it every
+// delivery counts as the first attempt and MaxAttempts is never reached.
+type AttemptCounter interface {
+	Attempts() int
+}
+
+type Config struct {
+	// Concurrency bounds the number of jobs handled at once.
+	Concurrency int
+	// IdleInterval is how long the runtime waits after an empty dequeue
+	// before polling again, and the first backoff after a failed one.
+	IdleInterval time.Duration
+	// MaxDequeueBackoff caps the backoff that doubles with every consecutive
+	// dequeue failure.
+	MaxDequeueBackoff time.Duration
+	// HandlerTimeout bounds a single handler call. Zero disables it.
+	HandlerTimeout time.Duration
+	// MaxAttempts dead-letters a job that fails on its last attempt. Zero
+	// retries without limit.
+	MaxAttempts int
+	RetryPolicy RetryPolicy
+	// DrainTimeout bounds how long Run waits for in-flight jobs after its
+	// context is canceled; jobs still running are then canceled and
+	// requeued. Zero waits until they finish.
+	DrainTimeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Concurrency:       4,
+		IdleInterval:      time.Second,
+		MaxDequeueBackoff: 30 * time.Second,
+		MaxAttempts:       5,
+		RetryPolicy:       core.ExponentialBackoffScheduler{Initial: 5 * time.Second, Max: 10 * time.Minute},
+		DrainTimeout:      30 * time.Second,
+	}
+}
+
+// Runtime dequeues job messages and routes them by script path to the
+// registered handlers. Dequeue errors are reported through Metrics and a Hook
+// implementing DequeueFailureHook, and back off exponentially.
+type Runtime struct {
+	dequeuer core.JobDequeuer
+	config   Config
+	Hook     core.JobWorkerHook
+	Metrics  core.MetricsRecorder
+	Now      func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	running  atomic.Bool
+}
+
+func NewRuntime(dequeuer core.JobDequeuer, config Config) (*Runtime, error) {
+	if dequeuer == nil {
+		return nil, fmt.Errorf("worker: job dequeuer is required")
+	}
+	defaults := DefaultConfig()
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.IdleInterval <= 0 {
+		config.IdleInterval = defaults.IdleInterval
+	}
+	if config.MaxDequeueBackoff <= 0 {
+		config.MaxDequeueBackoff = max(defaults.MaxDequeueBackoff, config.IdleInterval)
+	}
+	if config.HandlerTimeout < 0 {
+		config.HandlerTimeout = 0
+	}
+	if config.MaxAttempts < 0 {
+		config.MaxAttempts = 0
+	}
+	if config.RetryPolicy == nil {
+		config.RetryPolicy = defaults.RetryPolicy
+	}
+	if config.DrainTimeout < 0 {
+		config.DrainTimeout = 0
+	}
+	return &Runtime{
+		dequeuer: dequeuer,
+		config:   config,
+		handlers: make(map[string]Handler),
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}, nil
+}
+
+// Register routes messages with the script path to handler.
+func (r *Runtime) Register(scriptPath string, handler Handler) error {
+	if r == nil {
+		return fmt.Errorf("worker: runtime is nil")
+	}
+	scriptPath = strings.TrimSpace(scriptPath)
+	if scriptPath == "" {
+		return fmt.Errorf("worker: script path is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("worker: handler for %q is required", scriptPath)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.handlers[scriptPath]; exists {
+		return fmt.Errorf("worker: handler for %q is already registered", scriptPath)
+	}
+	r.handlers[scriptPath] = handler
+	return nil
+}
+
+// Run handles jobs until ctx is canceled, then stops dequeuing and drains the
+// jobs in flight. Handlers run on a context that outlives ctx so a shutdown
+// does not abort them midway.
+func (r *Runtime) Run(ctx context.Context) error {
+	if r == nil || r.dequeuer == nil {
+		return fmt.Errorf("worker: runtime is not configured")
+	}
+	if !r.running.CompareAndSwap(false, true) {
+		return fmt.Errorf("worker: runtime is already running")
+	}
+	defer r.running.Store(false)
+
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+	slots := make(chan struct{}, r.config.Concurrency)
+	var inflight sync.WaitGroup
+	failures := 0
+
+	for ctx.Err() == nil {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		delivery, err := r.dequeuer.Dequeue(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				continue
+			}
+			failures++
+			r.wait(ctx, r.dequeueFailed(ctx, err, failures))
+			continue
+		}
+		failures = 0
+		if delivery == nil {
+			<-slots
+			r.wait(ctx, r.config.IdleInterval)
+			continue
+		}
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			defer func() { <-slots }()
+			r.process(jobCtx, delivery)
+		}()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(drained)
+	}()
+	if r.config.DrainTimeout <= 0 {
+		<-drained
+		return nil
+	}
+	timer := time.NewTimer(r.config.DrainTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+	case <-timer.C:
+		cancelJobs()
+		<-drained
+	}
+	return nil
+}
+
+// dequeueFailed reports a dequeue error and returns the backoff before the
+// next poll.
+func (r *Runtime) dequeueFailed(ctx context.Context, err error, failures int) time.Duration {
+	backoff := core.ExponentialBackoffScheduler{
+		Initial: r.config.IdleInterval,
+		Max:     r.config.MaxDequeueBackoff,
+	}.NextDelay(failures)
+	if r.Metrics != nil {
+		r.Metrics.IncCounter(ctx, MetricDequeueFailed, 1, nil)
+	}
+	if hook, ok := r.Hook.(DequeueFailureHook); ok {
+		hook.OnDequeueFailure(ctx, fmt.Errorf("worker: dequeue job: %w", err), failures, backoff)
+	}
+	return backoff
+}
+
+func (r *Runtime) wait(ctx context.Context, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+func (r *Runtime) process(ctx context.Context, delivery core.JobDelivery) {
+	msg := delivery.Message()
+	started := r.now()
+	event := core.JobWorkerEvent{
+		Message:   msg,
+		Attempt:   deliveryAttempts(delivery),
+		StartedAt: started,
+	}
+	if r.Hook != nil {
+		r.Hook.OnStart(ctx, event)
+	}
+
+	err := r.handle(ctx, msg)
+	event.Duration = r.now().Sub(started)
+	// Settle even when a drain timeout canceled the job, so it is requeued
+	// instead of staying leased until the queue's visibility timeout.
+	settleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+	if err == nil {
+		if ackErr := delivery.Ack(settleCtx); ackErr != nil {
+			event.Err = fmt.Errorf("worker: ack job: %w", ackErr)
+			if r.Hook != nil {
+				r.Hook.OnFailure(ctx, event)
+			}
+			return
+		}
+		if r.Hook != nil {
+			r.Hook.OnSuccess(ctx, event)
+		}
+		return
+	}
+
+	opts := r.nackOptions(err, event.Attempt)
+	event.Err = err
+	event.Delay = opts.Delay
+	if nackErr := delivery.Nack(settleCtx, opts); nackErr != nil {
+		event.Err = errors.Join(err, fmt.Errorf("worker: nack job: %w", nackErr))
+	}
+	if r.Hook == nil {
+		return
+	}
+	if opts.Disposition == core.JobNackDispositionRetry {
+		r.Hook.OnRetry(ctx, event)
+		return
+	}
+	r.Hook.OnFailure(ctx, event)
+}
+
+func (r *Runtime) handle(ctx context.Context, msg *core.JobExecutionMessage) (err error) {
+	if msg == nil {
+		return Permanent(fmt.Errorf("worker: delivery has no message"))
+	}
+	scriptPath := strings.TrimSpace(msg.ScriptPath)
+	r.mu.RLock()
+	handler, ok := r.handlers[scriptPath]
+	r.mu.RUnlock()
+	if !ok {
+		return Permanent(fmt.Errorf("worker: no handler registered for script path %q", scriptPath))
+	}
+	if r.config.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.HandlerTimeout)
+		defer cancel()
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("worker: handler for %q panicked: %v", scriptPath, recovered)
+		}
+	}()
+	return handler.Handle(ctx, msg)
+}
+
+// nackOptions maps a handler error onto a nack disposition. Errors without
+// an explicit disposition are retried with the policy delay until the last
+// attempt, which is dead-lettered. A job canceled by shutdown is requeued
+// without delay.
+func (r *Runtime) nackOptions(err error, attempt int) core.JobNackOptions {
+	opts := core.JobNackOptions{
+		Disposition: core.JobNackDispositionRetry,
+		Reason:      err.Error(),
+	}
+	var disposition *dispositionError
+	switch {
+	case errors.As(err, &disposition):
+		opts.Disposition = disposition.disposition
+		opts.Delay = disposition.delay
+	case errors.Is(err, context.Canceled):
+		return opts
+	}
+	if opts.Disposition != core.JobNackDispositionRetry {
+		return opts
+	}
+	if r.config.MaxAttempts > 0 && attempt >= r.config.MaxAttempts {
+		opts.Disposition = core.JobNackDispositionDeadLetter
+		opts.Delay = 0
+		opts.Reason = fmt.Sprintf("attempt %d of %d: %s", attempt, r.config.MaxAttempts, opts.Reason)
+		return opts
+	}
+	if opts.Delay <= 0 {
+		opts.Delay = r.config.RetryPolicy.NextDelay(attempt)
+	}
+	return opts
+}
+
+func (r *Runtime) now() time.Time {
+	if r != nil && r.Now != nil {
+		return r.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func deliveryAttempts(delivery core.JobDelivery) int {
+	if counter, ok := delivery.(AttemptCounter); ok {
+		if attempts := counter.Attempts(); attempts > 0 {
+			return attempts
+		}
+	}
+	return 1
+}
+
+type dispositionError struct {
+	err         error
+	disposition core.JobNackDisposition
+	delay       time.Duration
+}
+
+func (e *dispositionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *dispositionError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error that retrying cannot fix; the job is
+// dead-lettered.
+func Permanent(err error) error {
+	return withDisposition(err, core.JobNackDispositionDeadLetter, 0)
+}
+
+// Cancel marks a job that should stop without being retried.
+func Cancel(err error) error {
+	return withDisposition(err, core.JobNackDispositionCanceled, 0)
+}
+
+// Retry asks for another attempt after delay, or after the retry policy's
+// delay when delay is zero.
+func Retry(err error, delay time.Duration) error {
+	return withDisposition(err, core.JobNackDispositionRetry, delay)
+}
+
+func withDisposition(err error, disposition core.JobNackDisposition, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &dispositionError{err: err, disposition: disposition, delay: delay}
+}
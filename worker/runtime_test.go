@@ -0,0 +1,389 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goliatone/go-services/core"
+)
+
+type fakeDelivery struct {
+	msg      *core.JobExecutionMessage
+	attempts int
+
+	mu    sync.Mutex
+	acked bool
+	nack  *core.JobNackOptions
+	done  chan struct{}
+}
+
+func newFakeDelivery(scriptPath string, params map[string]any, attempts int) *fakeDelivery {
+	return &fakeDelivery{
+		msg:      &core.JobExecutionMessage{JobID: "job_1", ScriptPath: scriptPath, Parameters: params},
+		attempts: attempts,
+		done:     make(chan struct{}),
+	}
+}
+
+func (d *fakeDelivery) Message() *core.JobExecutionMessage { return d.msg }
+
+func (d *fakeDelivery) Attempts() int { return d.attempts }
+
+func (d *fakeDelivery) Ack(context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.acked = true
+	close(d.done)
+	return nil
+}
+
+func (d *fakeDelivery) Nack(_ context.Context, opts core.JobNackOptions) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nack = &opts
+	close(d.done)
+	return nil
+}
+
+func (d *fakeDelivery) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-d.done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("delivery %s was not settled", d.msg.ScriptPath)
+	}
+}
+
+type fakeDequeuer struct {
+	deliveries chan core.JobDelivery
+}
+
+func (q *fakeDequeuer) Dequeue(ctx context.Context) (core.JobDelivery, error) {
+	select {
+	case delivery := <-q.deliveries:
+		return delivery, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		return nil, nil
+	}
+}
+
+type recordingHook struct {
+	mu     sync.Mutex
+	events map[string]int
+}
+
+func (h *recordingHook) record(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.events == nil {
+		h.events = map[string]int{}
+	}
+	h.events[name]++
+}
+
+func (h *recordingHook) count(name string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.events[name]
+}
+
+func (h *recordingHook) OnStart(context.Context, core.JobWorkerEvent)   { h.record("start") }
+func (h *recordingHook) OnSuccess(context.Context, core.JobWorkerEvent) { h.record("success") }
+func (h *recordingHook) OnFailure(context.Context, core.JobWorkerEvent) { h.record("failure") }
+func (h *recordingHook) OnRetry(context.Context, core.JobWorkerEvent)   { h.record("retry") }
+
+type stubRefresher struct {
+	requests chan core.RefreshRequest
+	err      error
+}
+
+func (s *stubRefresher) Refresh(_ context.Context, req core.RefreshRequest) (core.RefreshResult, error) {
+	s.requests <- req
+	return core.RefreshResult{}, s.err
+}
+
+func TestRuntime_RoutesJobsAndMapsDispositions(t *testing.T) {
+	dequeuer := &fakeDequeuer{deliveries: make(chan core.JobDelivery, 8)}
+	runtime, err := NewRuntime(dequeuer, Config{
+		Concurrency:  2,
+		IdleInterval: time.Millisecond,
+		MaxAttempts:  3,
+		RetryPolicy:  core.ExponentialBackoffScheduler{Initial: time.Second, Max: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("new runtime: %v", err)
+	}
+	hook := &recordingHook{}
+	runtime.Hook = hook
+	refresher := &stubRefresher{requests: make(chan core.RefreshRequest, 8)}
+	if err := runtime.RegisterBuiltins(Builtins{Refresher: refresher}); err != nil {
+		t.Fatalf("register builtins: %v", err)
+	}
+	flaky := errors.New("provider unavailable")
+	if err := runtime.Register("custom.flaky", HandlerFunc(func(context.Context, *core.JobExecutionMessage) error {
+		return flaky
+	})); err != nil {
+		t.Fatalf("register flaky: %v", err)
+	}
+	if err := runtime.Register("custom.flaky", HandlerFunc(func(context.Context, *core.JobExecutionMessage) error {
+		return nil
+	})); err == nil {
+		t.Fatalf("expected duplicate registration to fail")
+	}
+
+	refresh := newFakeDelivery(ScriptPathRefresh, map[string]any{ParamProviderID: "github", ParamConnectionID: "conn_1"}, 1)
+	missingParam := newFakeDelivery(ScriptPathRefresh, nil, 1)
+	unknown := newFakeDelivery("custom.unknown", nil, 1)
+	retried := newFakeDelivery("custom.flaky", nil, 2)
+	exhausted := newFakeDelivery("custom.flaky", nil, 3)
+	for _, delivery := range []*fakeDelivery{refresh, missingParam, unknown, retried, exhausted} {
+		dequeuer.deliveries <- delivery
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- runtime.Run(ctx) }()
+	for _, delivery := range []*fakeDelivery{refresh, missingParam, unknown, retried, exhausted} {
+		delivery.wait(t)
+	}
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if !refresh.acked {
+		t.Fatalf("expected refresh job to be acked")
+	}
+	if req := <-refresher.requests; req.ConnectionID != "conn_1" || req.ProviderID != "github" {
+		t.Fatalf("unexpected refresh request %+v", req)
+	}
+	for _, delivery := range []*fakeDelivery{missingParam, unknown} {
+		if delivery.nack == nil || delivery.nack.Disposition != core.JobNackDispositionDeadLetter {
+			t.Fatalf("expected %s to be dead-lettered, got %+v", delivery.msg.ScriptPath, delivery.nack)
+		}
+	}
+	if retried.nack == nil || retried.nack.Disposition != core.JobNackDispositionRetry || retried.nack.Delay != 2*time.Second {
+		t.Fatalf("expected second attempt to retry with backoff, got %+v", retried.nack)
+	}
+	if exhausted.nack == nil || exhausted.nack.Disposition != core.JobNackDispositionDeadLetter {
+		t.Fatalf("expected last attempt to be dead-lettered, got %+v", exhausted.nack)
+	}
+	if hook.count("start") != 5 || hook.count("success") != 1 || hook.count("retry") != 1 || hook.count("failure") != 3 {
+		t.Fatalf("unexpected hook events %+v", hook.events)
+	}
+}
+
+func TestRuntime_DrainsInFlightJobsOnShutdown(t *testing.T) {
+	dequeuer := &fakeDequeuer{deliveries: make(chan core.JobDelivery, 4)}
+	runtime, err := NewRuntime(dequeuer, Config{Concurrency: 2, IdleInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new runtime: %v", err)
+	}
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	var active, peak atomic.Int32
+	if err := runtime.Register("custom.slow", HandlerFunc(func(ctx context.Context, _ *core.JobExecutionMessage) error {
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		started <- struct{}{}
+		<-release
+		return ctx.Err()
+	})); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	first := newFakeDelivery("custom.slow", nil, 1)
+	second := newFakeDelivery("custom.slow", nil, 1)
+	third := newFakeDelivery("custom.slow", nil, 1)
+	dequeuer.deliveries <- first
+	dequeuer.deliveries <- second
+	dequeuer.deliveries <- third
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- runtime.Run(ctx) }()
+	<-started
+	<-started
+	cancel()
+	select {
+	case <-stopped:
+		t.Fatalf("expected run to wait for in-flight jobs")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !first.acked || !second.acked {
+		t.Fatalf("expected in-flight jobs to finish and ack")
+	}
+	if peak.Load() != 2 {
+		t.Fatalf("expected concurrency to be bounded at 2, got %d", peak.Load())
+	}
+	if len(dequeuer.deliveries) != 1 {
+		t.Fatalf("expected the queued job to stay undelivered after shutdown")
+	}
+}
+
+func TestRuntime_RequeuesJobsCanceledByDrainTimeout(t *testing.T) {
+	dequeuer := &fakeDequeuer{deliveries: make(chan core.JobDelivery, 1)}
+	runtime, err := NewRuntime(dequeuer, Config{
+		Concurrency:  1,
+		IdleInterval: time.Millisecond,
+		DrainTimeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new runtime: %v", err)
+	}
+	started := make(chan struct{})
+	if err := runtime.Register("custom.stuck", HandlerFunc(func(ctx context.Context, _ *core.JobExecutionMessage) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	delivery := &ctxHonoringDelivery{fakeDelivery: newFakeDelivery("custom.stuck", nil, 1)}
+	dequeuer.deliveries <- delivery
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- runtime.Run(ctx) }()
+	<-started
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	delivery.wait(t)
+	if delivery.nack == nil || delivery.nack.Disposition != core.JobNackDispositionRetry || delivery.nack.Delay != 0 {
+		t.Fatalf("expected the canceled job to be requeued without delay, got %+v", delivery.nack)
+	}
+}
+
+// ctxHonoringDelivery rejects settling on a canceled context, as the SQL
+// job queue does.
+type ctxHonoringDelivery struct {
+	*fakeDelivery
+}
+
+func (d *ctxHonoringDelivery) Ack(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.fakeDelivery.Ack(ctx)
+}
+
+func (d *ctxHonoringDelivery) Nack(ctx context.Context, opts core.JobNackOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.fakeDelivery.Nack(ctx, opts)
+}
+
+type failingDequeuer struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (q *failingDequeuer) Dequeue(context.Context) (core.JobDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.calls++
+	if q.calls <= q.failures {
+		return nil, errors.New("connection refused")
+	}
+	return nil, nil
+}
+
+type dequeueFailureHook struct {
+	recordingHook
+	mu       sync.Mutex
+	errs     []error
+	backoffs []time.Duration
+	reported chan struct{}
+}
+
+func (h *dequeueFailureHook) OnDequeueFailure(_ context.Context, err error, failures int, backoff time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errs = append(h.errs, err)
+	h.backoffs = append(h.backoffs, backoff)
+	if failures == 3 {
+		close(h.reported)
+	}
+}
+
+type countingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+func (m *countingMetrics) IncCounter(_ context.Context, name string, value int64, _ map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counters == nil {
+		m.counters = map[string]int64{}
+	}
+	m.counters[name] += value
+}
+
+func (m *countingMetrics) ObserveHistogram(context.Context, string, float64, map[string]string) {}
+
+func TestRuntime_ReportsDequeueErrorsAndBacksOff(t *testing.T) {
+	dequeuer := &failingDequeuer{failures: 3}
+	runtime, err := NewRuntime(dequeuer, Config{
+		Concurrency:       1,
+		IdleInterval:      time.Millisecond,
+		MaxDequeueBackoff: 3 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new runtime: %v", err)
+	}
+	hook := &dequeueFailureHook{reported: make(chan struct{})}
+	metrics := &countingMetrics{}
+	runtime.Hook = hook
+	runtime.Metrics = metrics
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- runtime.Run(ctx) }()
+	select {
+	case <-hook.reported:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected dequeue failures to be reported")
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.errs) != 3 {
+		t.Fatalf("expected only the failed dequeues to be reported, got %d", len(hook.errs))
+	}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}
+	for i, backoff := range hook.backoffs {
+		if backoff != want[i] {
+			t.Fatalf("expected backoffs %v, got %v", want, hook.backoffs)
+		}
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if metrics.counters[MetricDequeueFailed] != 3 {
+		t.Fatalf("expected dequeue failure metric, got %#v", metrics.counters)
+	}
+}